@@ -0,0 +1,41 @@
+package backend
+
+import (
+	"time"
+
+	"todoat/internal/tz"
+)
+
+// DateOnlyLayout is how due and start dates without a time of day are stored:
+// as calendar dates, so they keep their day when the timezone changes
+const DateOnlyLayout = "2006-01-02"
+
+// IsDateOnly reports whether t is at midnight in the configured timezone, the
+// form dates without a time of day take
+func IsDateOnly(t time.Time) bool {
+	local := t.In(tz.Location())
+	return local.Hour() == 0 && local.Minute() == 0 && local.Second() == 0 && local.Nanosecond() == 0
+}
+
+// FormatStoredDate formats a due or start date for storage: a date without a
+// time of day as a calendar date, other times in UTC
+func FormatStoredDate(t time.Time) string {
+	if IsDateOnly(t) {
+		return t.In(tz.Location()).Format(DateOnlyLayout)
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// ParseStoredDate parses a date written by FormatStoredDate or an RFC 3339
+// time. Calendar dates are midnight in the configured timezone; times are
+// returned in it.
+func ParseStoredDate(s string) (time.Time, error) {
+	if len(s) == len(DateOnlyLayout) {
+		return time.ParseInLocation(DateOnlyLayout, s, tz.Location())
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.In(tz.Location()), nil
+}
@@ -0,0 +1,475 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"todoat/backend"
+)
+
+// DefaultCodeMarkers are the comment markers scanned by default
+var DefaultCodeMarkers = []string{"TODO", "FIXME", "HACK"}
+
+// maxScannedFileSize skips generated or vendored blobs larger than this
+const maxScannedFileSize = 1 << 20
+
+// CodeConfig holds the settings of the code comment backend
+type CodeConfig struct {
+	WorkDir      string   // Working directory (defaults to current directory)
+	Markers      []string // Comment markers to collect (defaults to TODO, FIXME, HACK)
+	Exclude      []string // Path globs or directory prefixes to skip
+	EditComments bool     // Completing a task removes its comment; renaming rewrites it
+}
+
+// CodeBackend exposes TODO/FIXME/HACK comments in a repository's tracked
+// files as tasks. Each marker is a list; tasks are read-only unless
+// EditComments is set.
+type CodeBackend struct {
+	config   CodeConfig
+	repoPath string
+	lists    []backend.List
+	tasks    map[string][]backend.Task // listID (marker) -> tasks
+	comments map[string]codeComment    // taskID -> source location
+	scanned  bool
+}
+
+// codeComment is the location and text of a scanned comment
+type codeComment struct {
+	File   string // Path relative to the repository root
+	Line   int    // 1-based line number
+	Source string // The full source line as scanned
+	Start  int    // Byte offset of the comment leader in Source
+	Marker string
+	Text   string
+}
+
+// NewCode creates a new code comment backend
+func NewCode(cfg CodeConfig) (*CodeBackend, error) {
+	if cfg.WorkDir == "" {
+		workDir, err := os.Getwd()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get working directory: %w", err)
+		}
+		cfg.WorkDir = workDir
+	}
+	markers := cfg.Markers
+	if len(markers) == 0 {
+		markers = DefaultCodeMarkers
+	}
+	cfg.Markers = make([]string, len(markers))
+	for i, marker := range markers {
+		cfg.Markers[i] = strings.ToUpper(strings.TrimSpace(marker))
+		if !markerPattern.MatchString(cfg.Markers[i]) {
+			return nil, fmt.Errorf("invalid comment marker %q (letters only)", marker)
+		}
+	}
+	return &CodeBackend{config: cfg}, nil
+}
+
+// CanDetect reports whether the working directory is inside a git repository
+func (b *CodeBackend) CanDetect() (bool, error) {
+	repoPath, err := (&Backend{config: Config{WorkDir: b.config.WorkDir}}).findGitRepo()
+	if err != nil || repoPath == "" {
+		return false, nil
+	}
+	b.repoPath = repoPath
+	return true, nil
+}
+
+// DetectionInfo returns human-readable info about the detected environment
+func (b *CodeBackend) DetectionInfo() string {
+	return fmt.Sprintf("%s comments in git repository %s (read-only)", strings.Join(b.config.Markers, "/"), filepath.Base(b.repoPath))
+}
+
+// Close closes the backend
+func (b *CodeBackend) Close() error {
+	return nil
+}
+
+// =============================================================================
+// List Operations
+// =============================================================================
+
+// GetLists returns one list per comment marker
+func (b *CodeBackend) GetLists(ctx context.Context) ([]backend.List, error) {
+	if err := b.ensureScanned(); err != nil {
+		return nil, err
+	}
+	return b.lists, nil
+}
+
+// GetList returns a specific list by ID
+func (b *CodeBackend) GetList(ctx context.Context, listID string) (*backend.List, error) {
+	if err := b.ensureScanned(); err != nil {
+		return nil, err
+	}
+	for _, l := range b.lists {
+		if l.ID == listID {
+			return &l, nil
+		}
+	}
+	return nil, nil
+}
+
+// GetListByName returns a specific list by name (case-insensitive)
+func (b *CodeBackend) GetListByName(ctx context.Context, name string) (*backend.List, error) {
+	if err := b.ensureScanned(); err != nil {
+		return nil, err
+	}
+	return backend.FindListByName(b.lists, name), nil
+}
+
+// CreateList is not supported: lists are the configured markers
+func (b *CodeBackend) CreateList(ctx context.Context, name string) (*backend.List, error) {
+	return nil, fmt.Errorf("%w: lists are the comment markers (%s)", backend.ErrListCreationNotSupported, strings.Join(b.config.Markers, ", "))
+}
+
+// UpdateList is not supported
+func (b *CodeBackend) UpdateList(ctx context.Context, list *backend.List) (*backend.List, error) {
+	return nil, fmt.Errorf("code comment lists cannot be renamed")
+}
+
+// DeleteList is not supported
+func (b *CodeBackend) DeleteList(ctx context.Context, listID string) error {
+	return fmt.Errorf("code comment lists cannot be deleted")
+}
+
+// GetDeletedLists returns deleted lists (not supported)
+func (b *CodeBackend) GetDeletedLists(ctx context.Context) ([]backend.List, error) {
+	return []backend.List{}, nil
+}
+
+// GetDeletedListByName returns a deleted list by name (not supported)
+func (b *CodeBackend) GetDeletedListByName(ctx context.Context, name string) (*backend.List, error) {
+	return nil, nil
+}
+
+// RestoreList restores a deleted list (not supported)
+func (b *CodeBackend) RestoreList(ctx context.Context, listID string) error {
+	return fmt.Errorf("restore not supported in code backend")
+}
+
+// PurgeList permanently deletes a list (not supported)
+func (b *CodeBackend) PurgeList(ctx context.Context, listID string) error {
+	return fmt.Errorf("purge not supported in code backend")
+}
+
+// SupportsTrash returns false because comments are never deleted.
+func (b *CodeBackend) SupportsTrash() bool { return false }
+
+// =============================================================================
+// Task Operations
+// =============================================================================
+
+// GetTasks returns the comments with a list's marker
+func (b *CodeBackend) GetTasks(ctx context.Context, listID string) ([]backend.Task, error) {
+	if err := b.ensureScanned(); err != nil {
+		return nil, err
+	}
+	tasks, ok := b.tasks[listID]
+	if !ok {
+		return []backend.Task{}, nil
+	}
+	return tasks, nil
+}
+
+// GetTask returns a specific task by ID ("path/to/file.go:42")
+func (b *CodeBackend) GetTask(ctx context.Context, listID, taskID string) (*backend.Task, error) {
+	if err := b.ensureScanned(); err != nil {
+		return nil, err
+	}
+	for i, task := range b.tasks[listID] {
+		if task.ID == taskID {
+			return &b.tasks[listID][i], nil
+		}
+	}
+	return nil, nil
+}
+
+// CreateTask is not supported: add a comment to the code instead
+func (b *CodeBackend) CreateTask(ctx context.Context, listID string, task *backend.Task) (*backend.Task, error) {
+	return nil, fmt.Errorf("tasks from code comments are read-only; add a %s comment to the code instead", listID)
+}
+
+// UpdateTask removes the comment when the task is completed or cancelled and
+// rewrites it when the summary changes. Both require EditComments.
+func (b *CodeBackend) UpdateTask(ctx context.Context, listID string, task *backend.Task) (*backend.Task, error) {
+	existing, err := b.GetTask(ctx, listID, task.ID)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, fmt.Errorf("task not found: %s", task.ID)
+	}
+	comment := b.comments[task.ID]
+
+	done := task.Status == backend.StatusCompleted || task.Status == backend.StatusCancelled
+	renamed := task.Summary != existing.Summary
+	if !done && !renamed {
+		return existing, nil
+	}
+	if !b.config.EditComments {
+		return nil, fmt.Errorf("tasks from code comments are read-only; edit %s:%d (or set edit_comments: true)", comment.File, comment.Line)
+	}
+
+	var replacement *string
+	if !done {
+		line := comment.Source[:comment.Start] + rewriteComment(comment.Source[comment.Start:], comment.Text, task.Summary)
+		replacement = &line
+	}
+	if err := b.replaceLine(comment, replacement); err != nil {
+		return nil, err
+	}
+
+	updated := *existing
+	updated.Summary = task.Summary
+	updated.Status = task.Status
+	updated.Modified = time.Now()
+	if done {
+		now := time.Now()
+		updated.Completed = &now
+	}
+	// Line numbers below an edit have shifted
+	b.scanned = false
+	return &updated, nil
+}
+
+// DeleteTask removes the comment from the file (requires EditComments)
+func (b *CodeBackend) DeleteTask(ctx context.Context, listID, taskID string) error {
+	task, err := b.GetTask(ctx, listID, taskID)
+	if err != nil {
+		return err
+	}
+	if task == nil {
+		return fmt.Errorf("task not found: %s", taskID)
+	}
+	done := *task
+	done.Status = backend.StatusCompleted
+	_, err = b.UpdateTask(ctx, listID, &done)
+	return err
+}
+
+// =============================================================================
+// Scanning
+// =============================================================================
+
+// commentLeader matches the start of a comment in common languages
+const commentLeader = `(//+|#+|--|;+|/\*+|\*|<!--)`
+
+// markerPattern matches a valid comment marker
+var markerPattern = regexp.MustCompile(`^[A-Z]+$`)
+
+// commentPattern builds the pattern matching a marker comment:
+// "// TODO(alice): text" captures leader, marker, owner and text
+func (b *CodeBackend) commentPattern() *regexp.Regexp {
+	return regexp.MustCompile(commentLeader + `\s*(` + strings.Join(b.config.Markers, "|") + `)\b(?:\(([^)]*)\))?:?\s*(.*?)\s*(?:\*/|-->)?\s*$`)
+}
+
+// ensureScanned scans the repository if it has not been scanned yet
+func (b *CodeBackend) ensureScanned() error {
+	if b.scanned {
+		return nil
+	}
+	if b.repoPath == "" {
+		if ok, _ := b.CanDetect(); !ok {
+			return fmt.Errorf("not a git repository")
+		}
+	}
+	return b.scan()
+}
+
+// scan collects marker comments from the repository's tracked files
+func (b *CodeBackend) scan() error {
+	files, err := b.trackedFiles()
+	if err != nil {
+		return err
+	}
+
+	b.lists = nil
+	b.tasks = make(map[string][]backend.Task)
+	b.comments = make(map[string]codeComment)
+	for _, marker := range b.config.Markers {
+		b.lists = append(b.lists, backend.List{ID: marker, Name: marker, Modified: time.Now()})
+		b.tasks[marker] = []backend.Task{}
+	}
+
+	pattern := b.commentPattern()
+	for _, file := range files {
+		if b.excluded(file) {
+			continue
+		}
+		path := filepath.Join(b.repoPath, file)
+		info, err := os.Stat(path)
+		if err != nil || !info.Mode().IsRegular() || info.Size() > maxScannedFileSize {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil || bytes.IndexByte(data, 0) >= 0 {
+			// Unreadable or binary
+			continue
+		}
+		for i, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimSuffix(line, "\r")
+			loc := pattern.FindStringSubmatchIndex(line)
+			if loc == nil {
+				continue
+			}
+			comment := codeComment{
+				File:   filepath.ToSlash(file),
+				Line:   i + 1,
+				Source: line,
+				Start:  loc[2],
+				Marker: line[loc[4]:loc[5]],
+				Text:   line[loc[8]:loc[9]],
+			}
+			owner := ""
+			if loc[6] >= 0 {
+				owner = strings.TrimSpace(line[loc[6]:loc[7]])
+			}
+			b.addComment(comment, owner, info.ModTime())
+		}
+	}
+	b.scanned = true
+	return nil
+}
+
+// addComment records a comment as a task in its marker's list
+func (b *CodeBackend) addComment(comment codeComment, owner string, modified time.Time) {
+	ref := comment.File + ":" + strconv.Itoa(comment.Line)
+	summary := comment.Text
+	if summary == "" {
+		summary = comment.Marker + " at " + ref
+	}
+	task := backend.Task{
+		ID:          ref,
+		Summary:     summary,
+		Description: ref,
+		Status:      backend.StatusNeedsAction,
+		Categories:  owner,
+		ListID:      comment.Marker,
+		Created:     modified,
+		Modified:    modified,
+	}
+	b.tasks[comment.Marker] = append(b.tasks[comment.Marker], task)
+	b.comments[ref] = comment
+}
+
+// trackedFiles lists the files tracked by git, relative to the repository
+// root. Without a git binary, it walks the working tree instead.
+func (b *CodeBackend) trackedFiles() ([]string, error) {
+	cmd := exec.Command("git", "ls-files", "-z")
+	cmd.Dir = b.repoPath
+	if out, err := cmd.Output(); err == nil {
+		var files []string
+		for _, file := range strings.Split(string(out), "\x00") {
+			if file != "" {
+				files = append(files, file)
+			}
+		}
+		return files, nil
+	}
+
+	var files []string
+	err := filepath.WalkDir(b.repoPath, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() && path != b.repoPath && strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+		if !d.IsDir() {
+			rel, _ := filepath.Rel(b.repoPath, path)
+			files = append(files, rel)
+		}
+		return nil
+	})
+	return files, err
+}
+
+// excluded reports whether a file matches one of the exclude patterns,
+// either as a glob or as a directory prefix
+func (b *CodeBackend) excluded(file string) bool {
+	file = filepath.ToSlash(file)
+	for _, pattern := range b.config.Exclude {
+		pattern = strings.TrimSuffix(filepath.ToSlash(pattern), "/")
+		if ok, _ := filepath.Match(pattern, file); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(pattern, filepath.Base(file)); ok {
+			return true
+		}
+		if strings.HasPrefix(file, pattern+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// =============================================================================
+// Comment Editing
+// =============================================================================
+
+// replaceLine replaces a comment's line, or removes the comment when
+// replacement is nil. The line must still read as it did when scanned.
+func (b *CodeBackend) replaceLine(comment codeComment, replacement *string) error {
+	path := filepath.Join(b.repoPath, filepath.FromSlash(comment.File))
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(string(data), "\n")
+	idx := comment.Line - 1
+	if idx >= len(lines) || strings.TrimSuffix(lines[idx], "\r") != comment.Source {
+		return fmt.Errorf("%s:%d has changed since it was scanned; re-run the command", comment.File, comment.Line)
+	}
+	eol := ""
+	if strings.HasSuffix(lines[idx], "\r") {
+		eol = "\r"
+	}
+
+	switch {
+	case replacement != nil:
+		lines[idx] = *replacement + eol
+	case strings.TrimSpace(comment.Source[:comment.Start]) == "":
+		// The comment is the whole line
+		lines = append(lines[:idx], lines[idx+1:]...)
+	default:
+		// Trailing comment after code
+		lines[idx] = strings.TrimRight(comment.Source[:comment.Start], " \t") + eol
+	}
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")), info.Mode().Perm())
+}
+
+// rewriteComment replaces the text of a comment, keeping its leader, marker and owner
+func rewriteComment(comment, oldText, newText string) string {
+	if oldText == "" {
+		return strings.TrimRight(comment, " \t") + " " + newText
+	}
+	i := strings.LastIndex(comment, oldText)
+	return comment[:i] + newText + comment[i+len(oldText):]
+}
+
+// Verify interface compliance at compile time
+var _ backend.TaskManager = (*CodeBackend)(nil)
+var _ backend.DetectableBackend = (*CodeBackend)(nil)
+
+// init registers the code comment backend as detectable
+func init() {
+	// Priority 110 lists it in --detect-backend inside any repository without
+	// taking precedence over sqlite's 100 during auto-detection
+	backend.RegisterDetectableWithPriority("code", func(workDir string) (backend.DetectableBackend, error) {
+		return NewCode(CodeConfig{WorkDir: workDir})
+	}, 110)
+}
@@ -1803,3 +1803,151 @@ func TestGitBackendCreateListEdgeCases(t *testing.T) {
 		}
 	})
 }
+
+// =============================================================================
+// Code Comment Backend (TestCodeBackend)
+// =============================================================================
+
+// writeTracked writes a file into the repository and stages it
+func writeTracked(t *testing.T, repoPath, name, content string) {
+	t.Helper()
+	path := filepath.Join(repoPath, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+	cmd := exec.Command("git", "add", name)
+	cmd.Dir = repoPath
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("failed to stage %s: %v", name, err)
+	}
+}
+
+func TestCodeBackendScansTrackedFiles(t *testing.T) {
+	repoPath, cleanup := testRepo(t, false)
+	defer cleanup()
+
+	writeTracked(t, repoPath, "main.go", `package main
+
+// TODO(alice): handle errors
+func main() {
+	run() // FIXME: retries are not bounded
+	/* HACK: remove after v2 */
+	todoList := "TODO items" // not a marker comment
+	_ = todoList
+}
+`)
+	writeTracked(t, repoPath, "scripts/build.sh", "#!/bin/sh\n# TODO cache the build\n")
+	writeTracked(t, repoPath, "vendor/lib.go", "// TODO: vendored\n")
+	// Untracked files are ignored
+	if err := os.WriteFile(filepath.Join(repoPath, "scratch.go"), []byte("// TODO: untracked\n"), 0644); err != nil {
+		t.Fatalf("failed to write scratch file: %v", err)
+	}
+
+	be, err := git.NewCode(git.CodeConfig{WorkDir: repoPath, Exclude: []string{"vendor"}})
+	if err != nil {
+		t.Fatalf("NewCode: %v", err)
+	}
+	ctx := context.Background()
+
+	if ok, _ := be.CanDetect(); !ok {
+		t.Fatal("expected code backend to be detected in a git repository")
+	}
+	lists, err := be.GetLists(ctx)
+	if err != nil || len(lists) != 3 || lists[0].Name != "TODO" {
+		t.Fatalf("expected TODO, FIXME and HACK lists, got %+v, %v", lists, err)
+	}
+
+	todos, _ := be.GetTasks(ctx, "TODO")
+	if len(todos) != 2 {
+		t.Fatalf("expected 2 TODO tasks, got %+v", todos)
+	}
+	if todos[0].ID != "main.go:3" || todos[0].Summary != "handle errors" || todos[0].Categories != "alice" {
+		t.Errorf("unexpected TODO task %+v", todos[0])
+	}
+	if todos[1].ID != "scripts/build.sh:2" || todos[1].Summary != "cache the build" {
+		t.Errorf("unexpected TODO task %+v", todos[1])
+	}
+
+	fixmes, _ := be.GetTasks(ctx, "FIXME")
+	if len(fixmes) != 1 || fixmes[0].Summary != "retries are not bounded" || fixmes[0].Description != "main.go:5" {
+		t.Errorf("unexpected FIXME tasks %+v", fixmes)
+	}
+	hacks, _ := be.GetTasks(ctx, "HACK")
+	if len(hacks) != 1 || hacks[0].Summary != "remove after v2" {
+		t.Errorf("unexpected HACK tasks %+v", hacks)
+	}
+
+	// Read-only by default
+	done := fixmes[0]
+	done.Status = backend.StatusCompleted
+	if _, err := be.UpdateTask(ctx, "FIXME", &done); err == nil || !strings.Contains(err.Error(), "read-only") {
+		t.Errorf("expected read-only error, got %v", err)
+	}
+	if _, err := be.CreateTask(ctx, "TODO", &backend.Task{Summary: "new"}); err == nil {
+		t.Error("expected error creating a task")
+	}
+}
+
+func TestCodeBackendEditComments(t *testing.T) {
+	repoPath, cleanup := testRepo(t, false)
+	defer cleanup()
+
+	writeTracked(t, repoPath, "app.py", "def run():\n    # TODO: add logging\n    work()  # FIXME: slow\n    # TODO: old name\n")
+
+	be, err := git.NewCode(git.CodeConfig{WorkDir: repoPath, EditComments: true})
+	if err != nil {
+		t.Fatalf("NewCode: %v", err)
+	}
+	ctx := context.Background()
+
+	// Completing a whole-line comment removes the line
+	task, _ := be.GetTask(ctx, "TODO", "app.py:2")
+	if task == nil {
+		t.Fatal("expected task app.py:2")
+	}
+	done := *task
+	done.Status = backend.StatusCompleted
+	if _, err := be.UpdateTask(ctx, "TODO", &done); err != nil {
+		t.Fatalf("UpdateTask: %v", err)
+	}
+
+	// Line numbers are rescanned after the edit; a trailing comment keeps its code
+	task, _ = be.GetTask(ctx, "FIXME", "app.py:2")
+	if task == nil {
+		t.Fatal("expected FIXME to move to app.py:2")
+	}
+	done = *task
+	done.Status = backend.StatusCompleted
+	if _, err := be.UpdateTask(ctx, "FIXME", &done); err != nil {
+		t.Fatalf("UpdateTask: %v", err)
+	}
+
+	// Renaming rewrites the comment text
+	task, _ = be.GetTask(ctx, "TODO", "app.py:3")
+	if task == nil {
+		t.Fatal("expected task app.py:3")
+	}
+	renamed := *task
+	renamed.Summary = "new name"
+	if _, err := be.UpdateTask(ctx, "TODO", &renamed); err != nil {
+		t.Fatalf("UpdateTask: %v", err)
+	}
+
+	data, _ := os.ReadFile(filepath.Join(repoPath, "app.py"))
+	want := "def run():\n    work()\n    # TODO: new name\n"
+	if string(data) != want {
+		t.Errorf("file after edits = %q, want %q", string(data), want)
+	}
+
+	// A line changed on disk since the scan is not touched
+	task, _ = be.GetTask(ctx, "TODO", "app.py:3")
+	if err := os.WriteFile(filepath.Join(repoPath, "app.py"), []byte("def run():\n    work()\n    # TODO: edited elsewhere\n"), 0644); err != nil {
+		t.Fatalf("failed to rewrite file: %v", err)
+	}
+	if err := be.DeleteTask(ctx, "TODO", task.ID); err == nil || !strings.Contains(err.Error(), "changed since") {
+		t.Errorf("expected stale line error, got %v", err)
+	}
+}
@@ -8,10 +8,14 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
+	"sort"
 	"time"
 
 	"todoat/backend"
+	"todoat/internal/credentials"
+	"todoat/internal/utils"
 )
 
 const (
@@ -29,6 +33,11 @@ type Config struct {
 	ClientSecret string
 	BaseURL      string // Override for testing
 	TokenURL     string // Override for testing
+
+	// Expiry of AccessToken, if known; the token is refreshed shortly before it
+	Expiry time.Time
+	// OnTokenRefresh is called after a refresh so the new token can be persisted
+	OnTokenRefresh func(token *credentials.OAuthToken)
 }
 
 // ConfigFromEnv creates a Config from environment variables
@@ -49,6 +58,7 @@ type Backend struct {
 	tokenURL     string
 	accessToken  string
 	refreshToken string
+	expiry       time.Time
 }
 
 // New creates a new Google Tasks backend
@@ -74,6 +84,7 @@ func New(cfg Config) (*Backend, error) {
 		tokenURL:     tokenURL,
 		accessToken:  cfg.AccessToken,
 		refreshToken: cfg.RefreshToken,
+		expiry:       cfg.Expiry,
 	}, nil
 }
 
@@ -94,62 +105,40 @@ func (b *Backend) Close() error {
 
 // refreshAccessToken refreshes the OAuth2 access token using the refresh token
 func (b *Backend) refreshAccessToken(ctx context.Context) error {
-	if b.refreshToken == "" {
-		return fmt.Errorf("no refresh token available")
-	}
-
-	data := map[string]string{
-		"grant_type":    "refresh_token",
-		"refresh_token": b.refreshToken,
-	}
-
-	if b.config.ClientID != "" {
-		data["client_id"] = b.config.ClientID
+	client := credentials.OAuthClient{
+		TokenURL:     b.tokenURL,
+		ClientID:     b.config.ClientID,
+		ClientSecret: b.config.ClientSecret,
+		HTTPClient:   b.client,
 	}
-	if b.config.ClientSecret != "" {
-		data["client_secret"] = b.config.ClientSecret
-	}
-
-	jsonBody, err := json.Marshal(data)
+	token, err := client.Refresh(ctx, b.refreshToken)
 	if err != nil {
 		return err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.tokenURL, bytes.NewReader(jsonBody))
-	if err != nil {
-		return err
+	b.accessToken = token.AccessToken
+	b.refreshToken = token.RefreshToken
+	b.expiry = token.Expiry
+	if b.config.OnTokenRefresh != nil {
+		b.config.OnTokenRefresh(token)
 	}
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := b.client.Do(req)
-	if err != nil {
-		return err
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("token refresh failed: status %d", resp.StatusCode)
-	}
-
-	var tokenResp struct {
-		AccessToken  string `json:"access_token"`
-		RefreshToken string `json:"refresh_token"`
-	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
-		return err
-	}
-
-	b.accessToken = tokenResp.AccessToken
-	if tokenResp.RefreshToken != "" {
-		b.refreshToken = tokenResp.RefreshToken
-	}
-
 	return nil
 }
 
+// RawRequest sends an authenticated request to path, relative to the API base URL
+func (b *Backend) RawRequest(ctx context.Context, method, path string) (*http.Response, error) {
+	return b.doRequest(ctx, method, path, nil)
+}
+
 // doRequest performs an authenticated Google Tasks API request
 func (b *Backend) doRequest(ctx context.Context, method, path string, body interface{}) (*http.Response, error) {
+	// Refresh an access token known to be expired before using it
+	if b.refreshToken != "" && !b.expiry.IsZero() && time.Now().Add(credentials.ExpiryLeeway).After(b.expiry) {
+		if err := b.refreshAccessToken(ctx); err != nil {
+			return nil, fmt.Errorf("token refresh failed: %w", err)
+		}
+	}
+
 	url := b.baseURL + path
 
 	var bodyReader io.Reader
@@ -426,6 +415,7 @@ func (b *Backend) GetTasks(ctx context.Context, listID string) ([]backend.Task,
 			Parent    string `json:"parent"`
 			Updated   string `json:"updated"`
 			Completed string `json:"completed"`
+			Position  string `json:"position"`
 		} `json:"items"`
 	}
 
@@ -434,7 +424,9 @@ func (b *Backend) GetTasks(ctx context.Context, listID string) ([]backend.Task,
 	}
 
 	tasks := make([]backend.Task, len(result.Items))
+	positions := make([]string, len(result.Items))
 	for i, item := range result.Items {
+		positions[i] = item.Position
 		modified, _ := time.Parse(time.RFC3339, item.Updated)
 
 		tasks[i] = backend.Task{
@@ -448,10 +440,7 @@ func (b *Backend) GetTasks(ctx context.Context, listID string) ([]backend.Task,
 		}
 
 		if item.Due != "" {
-			dueDate, err := time.Parse(time.RFC3339, item.Due)
-			if err == nil {
-				tasks[i].DueDate = &dueDate
-			}
+			tasks[i].DueDate = parseGoogleDue(item.Due)
 		}
 
 		if item.Completed != "" {
@@ -461,10 +450,27 @@ func (b *Backend) GetTasks(ctx context.Context, listID string) ([]backend.Task,
 			}
 		}
 	}
+	rankPositions(tasks, positions)
 
 	return tasks, nil
 }
 
+// rankPositions sets the Position of tasks to their rank among their siblings.
+// Google positions are zero-padded strings that sort in the order tasks are
+// shown, too long to use as numbers.
+func rankPositions(tasks []backend.Task, positions []string) {
+	byParent := make(map[string][]int)
+	for i := range tasks {
+		byParent[tasks[i].ParentID] = append(byParent[tasks[i].ParentID], i)
+	}
+	for _, siblings := range byParent {
+		sort.SliceStable(siblings, func(a, b int) bool { return positions[siblings[a]] < positions[siblings[b]] })
+		for rank, i := range siblings {
+			tasks[i].Position = rank + 1
+		}
+	}
+}
+
 // GetTask returns a specific task by ID
 func (b *Backend) GetTask(ctx context.Context, listID, taskID string) (*backend.Task, error) {
 	resp, err := b.doRequest(ctx, http.MethodGet, "/tasks/v1/lists/"+listID+"/tasks/"+taskID, nil)
@@ -509,10 +515,7 @@ func (b *Backend) GetTask(ctx context.Context, listID, taskID string) (*backend.
 	}
 
 	if item.Due != "" {
-		dueDate, err := time.Parse(time.RFC3339, item.Due)
-		if err == nil {
-			task.DueDate = &dueDate
-		}
+		task.DueDate = parseGoogleDue(item.Due)
 	}
 
 	if item.Completed != "" {
@@ -542,7 +545,7 @@ func (b *Backend) CreateTask(ctx context.Context, listID string, task *backend.T
 
 	if task.DueDate != nil {
 		// Google Tasks uses RFC3339 format for due dates
-		body["due"] = task.DueDate.Format(time.RFC3339)
+		body["due"] = formatGoogleDue(*task.DueDate)
 	}
 
 	resp, err := b.doRequest(ctx, http.MethodPost, "/tasks/v1/lists/"+listID+"/tasks", body)
@@ -584,10 +587,7 @@ func (b *Backend) CreateTask(ctx context.Context, listID string, task *backend.T
 	}
 
 	if item.Due != "" {
-		dueDate, err := time.Parse(time.RFC3339, item.Due)
-		if err == nil {
-			created.DueDate = &dueDate
-		}
+		created.DueDate = parseGoogleDue(item.Due)
 	}
 
 	return created, nil
@@ -605,7 +605,7 @@ func (b *Backend) UpdateTask(ctx context.Context, listID string, task *backend.T
 	}
 
 	if task.DueDate != nil {
-		body["due"] = task.DueDate.Format(time.RFC3339)
+		body["due"] = formatGoogleDue(*task.DueDate)
 	}
 
 	resp, err := b.doRequest(ctx, http.MethodPatch, "/tasks/v1/lists/"+listID+"/tasks/"+task.ID, body)
@@ -646,10 +646,7 @@ func (b *Backend) UpdateTask(ctx context.Context, listID string, task *backend.T
 	}
 
 	if item.Due != "" {
-		dueDate, err := time.Parse(time.RFC3339, item.Due)
-		if err == nil {
-			updated.DueDate = &dueDate
-		}
+		updated.DueDate = parseGoogleDue(item.Due)
 	}
 
 	if item.Completed != "" {
@@ -662,6 +659,32 @@ func (b *Backend) UpdateTask(ctx context.Context, listID string, task *backend.T
 	return updated, nil
 }
 
+// MoveTask places a task after previousID among the children of parentID with
+// the move endpoint (backend.TaskMover)
+func (b *Backend) MoveTask(ctx context.Context, listID, taskID, parentID, previousID string) error {
+	query := url.Values{}
+	if parentID != "" {
+		query.Set("parent", parentID)
+	}
+	if previousID != "" {
+		query.Set("previous", previousID)
+	}
+	path := "/tasks/v1/lists/" + listID + "/tasks/" + taskID + "/move"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	resp, err := b.doRequest(ctx, http.MethodPost, path, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to move task: status %d", resp.StatusCode)
+	}
+	return nil
+}
+
 // DeleteTask removes a task
 func (b *Backend) DeleteTask(ctx context.Context, listID, taskID string) error {
 	resp, err := b.doRequest(ctx, http.MethodDelete, "/tasks/v1/lists/"+listID+"/tasks/"+taskID, nil)
@@ -703,3 +726,19 @@ func backendToGoogleStatus(status backend.TaskStatus) string {
 
 // Verify interface compliance at compile time
 var _ backend.TaskManager = (*Backend)(nil)
+
+// parseGoogleDue parses a Google Tasks due date. Google only keeps the date and
+// sends it as UTC midnight, so it becomes midnight of that date in the local zone.
+func parseGoogleDue(due string) *time.Time {
+	parsed, err := time.Parse(time.RFC3339, due)
+	if err != nil {
+		return nil
+	}
+	local := utils.DateAsLocal(parsed.UTC())
+	return &local
+}
+
+// formatGoogleDue formats a due date for Google Tasks as UTC midnight of its local date
+func formatGoogleDue(due time.Time) string {
+	return utils.DateAsUTC(due).Format(time.RFC3339)
+}
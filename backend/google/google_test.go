@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"todoat/backend"
+	"todoat/internal/credentials"
 )
 
 // =============================================================================
@@ -725,6 +726,41 @@ func TestGoogleTasksTokenRefresh(t *testing.T) {
 	}
 }
 
+// TestGoogleTasksProactiveRefresh - Expired tokens are refreshed before the request and persisted
+func TestGoogleTasksProactiveRefresh(t *testing.T) {
+	server := newMockGoogleTasksServer("test-access-token", "test-refresh-token")
+	defer server.Close()
+
+	server.AddTaskList("list-1", "TestList")
+
+	var refreshed *credentials.OAuthToken
+	be, err := New(Config{
+		AccessToken:    "stale-access-token",
+		RefreshToken:   "test-refresh-token",
+		Expiry:         time.Now().Add(-time.Minute),
+		BaseURL:        server.URL(),
+		TokenURL:       server.URL() + "/token",
+		OnTokenRefresh: func(token *credentials.OAuthToken) { refreshed = token },
+	})
+	if err != nil {
+		t.Fatalf("Failed to create backend: %v", err)
+	}
+	defer func() { _ = be.Close() }()
+
+	if _, err := be.GetLists(context.Background()); err != nil {
+		t.Fatalf("GetLists failed: %v", err)
+	}
+
+	// The stale token would be rejected, so the list request must follow the refresh
+	log := server.GetRequestLog()
+	if len(log) < 2 || log[0] != "POST /token" {
+		t.Errorf("expected refresh before the first API call, got %v", log)
+	}
+	if refreshed == nil || refreshed.AccessToken != "test-access-token" || refreshed.Expired() {
+		t.Errorf("expected OnTokenRefresh with a fresh token, got %+v", refreshed)
+	}
+}
+
 // TestGoogleTasksSubtasks - Parent-child relationships sync correctly
 func TestGoogleTasksSubtasks(t *testing.T) {
 	server := newMockGoogleTasksServer("test-access-token", "test-refresh-token")
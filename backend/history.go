@@ -0,0 +1,136 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// History actions recorded for a task
+const (
+	HistoryCreated = "created"
+	HistoryUpdated = "updated"
+	HistoryDeleted = "deleted"
+)
+
+// ErrTaskHistoryNotSupported is returned when a backend does not record the changes made to tasks.
+var ErrTaskHistoryNotSupported = errors.New("task history is not recorded by this backend")
+
+// HistoryEntry is one recorded change of a task. An update records one entry
+// per changed field; creations and deletions record a single entry with an
+// empty Field.
+type HistoryEntry struct {
+	TaskID    string    `json:"task_id"`
+	ListID    string    `json:"list_id"`
+	ChangedAt time.Time `json:"changed_at"`
+	Actor     string    `json:"actor"`
+	Action    string    `json:"action"`
+	Field     string    `json:"field,omitempty"`
+	OldValue  string    `json:"old_value,omitempty"`
+	NewValue  string    `json:"new_value,omitempty"`
+}
+
+// TaskHistoryReader is an optional interface for backends that record the
+// changes made to tasks. Currently supported by the SQLite backend, which is
+// also the local cache of synced remote backends.
+type TaskHistoryReader interface {
+	// TaskHistory returns the recorded changes of a task, oldest first
+	TaskHistory(ctx context.Context, taskID string) ([]HistoryEntry, error)
+}
+
+// ReadTaskHistory returns the history of a task from tm, or the first backend
+// it wraps that records one
+func ReadTaskHistory(ctx context.Context, tm TaskManager, taskID string) ([]HistoryEntry, error) {
+	reader, ok := findOptional[TaskHistoryReader](tm)
+	if !ok {
+		return nil, ErrTaskHistoryNotSupported
+	}
+	return reader.TaskHistory(ctx, taskID)
+}
+
+// actorKey is the context key of the actor recorded in task history
+type actorKey struct{}
+
+// defaultActor is the actor of changes made without one in their context, set
+// at startup
+var defaultActor string
+
+// SetActor sets the actor recorded for changes whose context carries none,
+// such as "cli:alice@laptop"
+func SetActor(actor string) {
+	defaultActor = actor
+}
+
+// DefaultActor returns the actor set with SetActor
+func DefaultActor() string {
+	return defaultActor
+}
+
+// WithActor returns a context whose changes are recorded as made by actor,
+// such as "sync:todoist" for tasks pulled from Todoist
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom returns the actor of ctx, or the default actor
+func ActorFrom(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return defaultActor
+}
+
+// FieldValue returns the value of a task field, as named by ChangedFields, in
+// the form recorded in task history
+func FieldValue(task *Task, field string) string {
+	switch field {
+	case FieldSummary:
+		return task.Summary
+	case FieldDescription:
+		return task.Description
+	case FieldStatus:
+		return string(task.Status)
+	case FieldPriority:
+		return strconv.Itoa(task.Priority)
+	case FieldDueDate:
+		return historyTime(task.DueDate)
+	case FieldStartDate:
+		return historyTime(task.StartDate)
+	case FieldCategories:
+		return task.Categories
+	case FieldRecurrence:
+		if task.Recurrence != "" && !task.RecurFromDue {
+			return task.Recurrence + " (from completion)"
+		}
+		return task.Recurrence
+	case FieldParent:
+		return task.ParentID
+	case FieldMetadata:
+		pairs := make([]string, 0, len(task.Metadata))
+		for _, key := range slices.Sorted(maps.Keys(task.Metadata)) {
+			pairs = append(pairs, key+"="+task.Metadata[key])
+		}
+		return strings.Join(pairs, ", ")
+	case FieldPosition:
+		return strconv.Itoa(task.Position)
+	case FieldPinned:
+		return strconv.FormatBool(task.Pinned)
+	}
+	return ""
+}
+
+// historyTime formats an optional date for task history: a calendar date for
+// dates without a time of day, otherwise the time in UTC
+func historyTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	if IsDateOnly(*t) {
+		return FormatStoredDate(*t)
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,50 @@
+package backend_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"todoat/backend"
+)
+
+// TestActorFrom verifies the actor of a context overrides the default actor
+func TestActorFrom(t *testing.T) {
+	backend.SetActor("cli:alice@laptop")
+	t.Cleanup(func() { backend.SetActor("") })
+
+	ctx := context.Background()
+	if got := backend.ActorFrom(ctx); got != "cli:alice@laptop" {
+		t.Errorf("ActorFrom without actor = %q, want the default actor", got)
+	}
+	if got := backend.ActorFrom(backend.WithActor(ctx, "sync:todoist")); got != "sync:todoist" {
+		t.Errorf("ActorFrom = %q, want sync:todoist", got)
+	}
+}
+
+// TestFieldValue verifies the values recorded in task history for each kind of field
+func TestFieldValue(t *testing.T) {
+	due := time.Date(2026, 3, 1, 9, 30, 0, 0, time.FixedZone("CET", 3600))
+	task := &backend.Task{
+		Status:     backend.StatusInProgress,
+		Priority:   3,
+		DueDate:    &due,
+		Recurrence: "FREQ=WEEKLY",
+		Metadata:   map[string]string{"estimate": "3h", "client": "acme"},
+		Pinned:     true,
+	}
+	tests := map[string]string{
+		backend.FieldStatus:     "IN-PROGRESS",
+		backend.FieldPriority:   "3",
+		backend.FieldDueDate:    "2026-03-01T08:30:00Z",
+		backend.FieldStartDate:  "",
+		backend.FieldRecurrence: "FREQ=WEEKLY (from completion)",
+		backend.FieldMetadata:   "client=acme, estimate=3h",
+		backend.FieldPinned:     "true",
+	}
+	for field, want := range tests {
+		if got := backend.FieldValue(task, field); got != want {
+			t.Errorf("FieldValue(%s) = %q, want %q", field, got, want)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package icsurl_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"todoat/internal/testutil"
+)
+
+// newDeadlinesServer serves a feed with one deadline today and one next month
+func newDeadlinesServer(t *testing.T) *httptest.Server {
+	today := time.Now().Format("20060102")
+	nextMonth := time.Now().AddDate(0, 1, 0).Format("20060102")
+	feed := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nX-WR-CALNAME:Course Deadlines\r\n" +
+		"BEGIN:VEVENT\r\nUID:lab-report\r\nSUMMARY:Lab report due\r\nDTSTART;VALUE=DATE:" + today + "\r\nEND:VEVENT\r\n" +
+		"BEGIN:VTODO\r\nUID:final-essay\r\nSUMMARY:Final essay\r\nDUE;VALUE=DATE:" + nextMonth + "\r\nEND:VTODO\r\n" +
+		"END:VCALENDAR\r\n"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/calendar")
+		_, _ = w.Write([]byte(feed))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestICSURLBackendCLI verifies that a configured ics-url backend shows the
+// feed as a list and rejects changes
+func TestICSURLBackendCLI(t *testing.T) {
+	server := newDeadlinesServer(t)
+	cli := testutil.NewCLITestWithConfig(t)
+	cli.SetFullConfig(`
+backends:
+  sqlite:
+    enabled: true
+  course:
+    type: ics-url
+    url: "` + server.URL + `/deadlines.ics"
+    ttl: 6h
+    cache_path: "` + filepath.Join(cli.TmpDir(), "course.ics") + `"
+no_prompt: true
+`)
+
+	stdout := cli.MustExecute("-y", "-b", "course", "list")
+	testutil.AssertContains(t, stdout, "Course Deadlines")
+
+	stdout = cli.MustExecute("-y", "-b", "course", "Course Deadlines")
+	testutil.AssertContains(t, stdout, "Lab report due")
+	testutil.AssertContains(t, stdout, "Final essay")
+
+	_, stderr := cli.ExecuteAndFail("-y", "-b", "course", "Course Deadlines", "add", "Sneaky task")
+	testutil.AssertContains(t, stderr, "read-only")
+}
+
+// TestICSURLRoutedSubscriptionCLI verifies that a feed routed to its backend
+// is pulled by sync, so its deadlines appear next to local tasks in @today
+func TestICSURLRoutedSubscriptionCLI(t *testing.T) {
+	server := newDeadlinesServer(t)
+	cli := testutil.NewCLITestWithConfig(t)
+	cli.SetFullConfig(`
+sync:
+  enabled: true
+  local_backend: sqlite
+  auto_sync_after_operation: false
+backends:
+  sqlite:
+    type: sqlite
+    enabled: true
+  course:
+    type: ics-url
+    enabled: true
+    url: "` + server.URL + `/deadlines.ics"
+    cache_path: "` + filepath.Join(cli.TmpDir(), "course.ics") + `"
+routes:
+  Course Deadlines: course
+no_prompt: true
+`)
+
+	cli.MustExecute("-y", "Chores", "add", "Water plants", "--due-date", "today")
+	cli.MustExecute("-y", "sync")
+
+	stdout := cli.MustExecute("-y", "@today")
+	testutil.AssertContains(t, stdout, "Water plants")
+	testutil.AssertContains(t, stdout, "Lab report due")
+	testutil.AssertNotContains(t, stdout, "Final essay")
+
+	testutil.AssertContains(t, cli.MustExecute("-y", "Course Deadlines"), "Final essay")
+}
@@ -0,0 +1,479 @@
+// Package icsurl provides a read-only backend for iCalendar subscriptions. A
+// public .ics URL, such as a shared team VTODO feed or a course deadlines
+// calendar, is shown as a single task list: VTODOs are tasks, and VEVENTs are
+// tasks due when the event starts. The feed is kept in a cache file and
+// fetched again once it is older than the TTL.
+package icsurl
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"todoat/backend"
+	"todoat/internal/ical"
+	"todoat/internal/recurrence"
+	"todoat/internal/tz"
+)
+
+// DefaultTTL is how long a fetched feed is used before it is fetched again
+const DefaultTTL = time.Hour
+
+// DefaultListName names the list of a feed without X-WR-CALNAME
+const DefaultListName = "Subscription"
+
+// maxFeedSize bounds the size of a fetched feed
+const maxFeedSize = 10 << 20
+
+// maxOccurrences bounds the occurrences stepped through to move a recurring
+// event to its next date
+const maxOccurrences = 10000
+
+// Config holds iCalendar subscription settings
+type Config struct {
+	URL       string        // Address of the feed (http, https or webcal)
+	ListName  string        // Name of the list (defaults to the feed's X-WR-CALNAME)
+	TTL       time.Duration // How long a fetched feed is used (default: DefaultTTL)
+	CachePath string        // File keeping the last fetched feed; empty keeps it in memory only
+}
+
+// Backend implements backend.TaskManager on top of an iCalendar feed
+type Backend struct {
+	config    Config
+	client    *http.Client
+	now       func() time.Time
+	data      string    // Last fetched feed
+	checkedAt time.Time // When data was fetched, or a failed fetch fell back to it
+	list      backend.List
+	tasks     []backend.Task
+}
+
+// New creates a new iCalendar subscription backend
+func New(cfg Config) (*Backend, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("feed URL is required (url: https://example.com/calendar.ics)")
+	}
+	u, err := url.Parse(cfg.URL)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid feed URL %q", cfg.URL)
+	}
+	switch u.Scheme {
+	case "http", "https":
+	case "webcal":
+		// webcal:// is the subscription form of an https:// address
+		u.Scheme = "https"
+		cfg.URL = u.String()
+	default:
+		return nil, fmt.Errorf("invalid feed URL %q (must be http, https or webcal)", cfg.URL)
+	}
+	if cfg.TTL < 0 {
+		return nil, fmt.Errorf("invalid TTL %s (must not be negative)", cfg.TTL)
+	}
+	if cfg.TTL == 0 {
+		cfg.TTL = DefaultTTL
+	}
+	return &Backend{config: cfg, client: createHTTPClient(), now: tz.Now}, nil
+}
+
+// createHTTPClient creates an HTTP client with proper configuration
+func createHTTPClient() *http.Client {
+	return &http.Client{
+		Timeout: 30 * time.Second,
+	}
+}
+
+// Close closes the backend
+func (b *Backend) Close() error {
+	if b.client == nil {
+		return nil
+	}
+	if transport, ok := b.client.Transport.(*http.Transport); ok {
+		transport.CloseIdleConnections()
+	}
+	return nil
+}
+
+// readOnly returns the error for an operation that would modify the feed
+func (b *Backend) readOnly(operation string) error {
+	return fmt.Errorf("cannot %s: %w (subscribed iCalendar feed %s)", operation, backend.ErrReadOnly, b.config.URL)
+}
+
+// =============================================================================
+// List Operations
+// =============================================================================
+
+// GetLists returns the feed's list
+func (b *Backend) GetLists(ctx context.Context) ([]backend.List, error) {
+	if err := b.ensureLoaded(ctx); err != nil {
+		return nil, err
+	}
+	return []backend.List{b.list}, nil
+}
+
+// GetList returns the feed's list if listID is its ID
+func (b *Backend) GetList(ctx context.Context, listID string) (*backend.List, error) {
+	if err := b.ensureLoaded(ctx); err != nil {
+		return nil, err
+	}
+	if listID != b.list.ID {
+		return nil, nil
+	}
+	list := b.list
+	return &list, nil
+}
+
+// GetListByName returns the feed's list if it has the name (case-insensitive)
+func (b *Backend) GetListByName(ctx context.Context, name string) (*backend.List, error) {
+	if err := b.ensureLoaded(ctx); err != nil {
+		return nil, err
+	}
+	return backend.FindListByName([]backend.List{b.list}, name), nil
+}
+
+// CreateList is rejected: the feed is its only list
+func (b *Backend) CreateList(ctx context.Context, name string) (*backend.List, error) {
+	return nil, b.readOnly("create list")
+}
+
+// UpdateList is rejected
+func (b *Backend) UpdateList(ctx context.Context, list *backend.List) (*backend.List, error) {
+	return nil, b.readOnly("update list")
+}
+
+// DeleteList is rejected
+func (b *Backend) DeleteList(ctx context.Context, listID string) error {
+	return b.readOnly("delete list")
+}
+
+// GetDeletedLists returns no lists: feeds have no trash
+func (b *Backend) GetDeletedLists(ctx context.Context) ([]backend.List, error) {
+	return nil, nil
+}
+
+// GetDeletedListByName returns no list: feeds have no trash
+func (b *Backend) GetDeletedListByName(ctx context.Context, name string) (*backend.List, error) {
+	return nil, nil
+}
+
+// RestoreList is rejected
+func (b *Backend) RestoreList(ctx context.Context, listID string) error {
+	return b.readOnly("restore list")
+}
+
+// PurgeList is rejected
+func (b *Backend) PurgeList(ctx context.Context, listID string) error {
+	return b.readOnly("purge list")
+}
+
+// SupportsTrash returns false
+func (b *Backend) SupportsTrash() bool { return false }
+
+// =============================================================================
+// Task Operations
+// =============================================================================
+
+// GetTasks returns the tasks and events of the feed
+func (b *Backend) GetTasks(ctx context.Context, listID string) ([]backend.Task, error) {
+	if err := b.ensureLoaded(ctx); err != nil {
+		return nil, err
+	}
+	if listID != b.list.ID {
+		return nil, nil
+	}
+	tasks := make([]backend.Task, len(b.tasks))
+	copy(tasks, b.tasks)
+	return tasks, nil
+}
+
+// GetTask returns a task of the feed by UID
+func (b *Backend) GetTask(ctx context.Context, listID, taskID string) (*backend.Task, error) {
+	tasks, err := b.GetTasks(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range tasks {
+		if tasks[i].ID == taskID {
+			return &tasks[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// CreateTask is rejected
+func (b *Backend) CreateTask(ctx context.Context, listID string, task *backend.Task) (*backend.Task, error) {
+	return nil, b.readOnly("add task")
+}
+
+// UpdateTask is rejected
+func (b *Backend) UpdateTask(ctx context.Context, listID string, task *backend.Task) (*backend.Task, error) {
+	return nil, b.readOnly("update task")
+}
+
+// DeleteTask is rejected
+func (b *Backend) DeleteTask(ctx context.Context, listID, taskID string) error {
+	return b.readOnly("delete task")
+}
+
+// =============================================================================
+// Fetching
+// =============================================================================
+
+// ensureLoaded parses the cached feed, fetching it again when it is older than
+// the TTL. If the fetch fails, a previously fetched copy is used until the
+// next TTL expires; the fetch error is only returned when there is none.
+func (b *Backend) ensureLoaded(ctx context.Context) error {
+	if b.data == "" && b.config.CachePath != "" {
+		if info, err := os.Stat(b.config.CachePath); err == nil {
+			if data, err := os.ReadFile(b.config.CachePath); err == nil {
+				if err := b.load(string(data)); err == nil {
+					b.checkedAt = info.ModTime()
+				}
+			}
+		}
+	}
+	if b.data != "" && b.now().Sub(b.checkedAt) < b.config.TTL {
+		return nil
+	}
+
+	data, err := b.fetch(ctx)
+	if err == nil {
+		err = b.load(data)
+	}
+	if err != nil {
+		if b.data == "" {
+			return err
+		}
+		b.checkedAt = b.now()
+		return nil
+	}
+	b.checkedAt = b.now()
+	if b.config.CachePath != "" {
+		// A feed that cannot be cached is fetched again next time
+		if err := os.MkdirAll(filepath.Dir(b.config.CachePath), 0755); err == nil {
+			_ = os.WriteFile(b.config.CachePath, []byte(data), 0644)
+		}
+	}
+	return nil
+}
+
+// fetch downloads the feed
+func (b *Backend) fetch(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, b.config.URL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "text/calendar")
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch iCalendar feed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to fetch iCalendar feed %s: status %d", b.config.URL, resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxFeedSize+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read iCalendar feed: %w", err)
+	}
+	if len(body) > maxFeedSize {
+		return "", fmt.Errorf("iCalendar feed %s is larger than %d MB", b.config.URL, maxFeedSize>>20)
+	}
+	return string(body), nil
+}
+
+// load parses a feed into the list and its tasks
+func (b *Backend) load(data string) error {
+	components, err := ical.Parse(data)
+	if err != nil {
+		return fmt.Errorf("invalid iCalendar feed %s: %w", b.config.URL, err)
+	}
+	var calendar *ical.Component
+	for _, c := range components {
+		if c.Name == "VCALENDAR" {
+			calendar = c
+			break
+		}
+	}
+	if calendar == nil {
+		return fmt.Errorf("invalid iCalendar feed %s: %w", b.config.URL, errors.New("no VCALENDAR"))
+	}
+
+	list := backend.List{
+		ID:   fmt.Sprintf("%x", sha256.Sum256([]byte(b.config.URL)))[:16],
+		Name: b.config.ListName,
+	}
+	if list.Name == "" {
+		if name := calendar.Get("X-WR-CALNAME"); name != nil {
+			list.Name = strings.TrimSpace(name.Text())
+		}
+	}
+	if list.Name == "" {
+		list.Name = DefaultListName
+	}
+	if desc := calendar.Get("X-WR-CALDESC"); desc != nil {
+		list.Description = desc.Text()
+	}
+
+	var tasks []backend.Task
+	for _, c := range calendar.Components {
+		// Overrides of single occurrences repeat the UID of their series
+		if c.Get("RECURRENCE-ID") != nil {
+			continue
+		}
+		var task backend.Task
+		switch c.Name {
+		case "VTODO":
+			task = b.todoTask(c)
+		case "VEVENT":
+			task = b.eventTask(c)
+		default:
+			continue
+		}
+		task.ListID = list.ID
+		if task.ID == "" {
+			task.ID = fmt.Sprintf("%x", sha256.Sum256([]byte(task.Summary+"\x00"+timeKey(task.DueDate))))[:16]
+		}
+		if task.Modified.After(list.Modified) {
+			list.Modified = task.Modified
+		}
+		tasks = append(tasks, task)
+	}
+
+	b.data = data
+	b.list = list
+	b.tasks = tasks
+	return nil
+}
+
+// commonTask reads the properties shared by VTODO and VEVENT
+func commonTask(c *ical.Component) backend.Task {
+	task := backend.Task{Status: backend.StatusNeedsAction}
+	if p := c.Get("UID"); p != nil {
+		task.ID = strings.TrimSpace(p.Value)
+	}
+	if p := c.Get("SUMMARY"); p != nil {
+		task.Summary = p.Text()
+	}
+	if p := c.Get("DESCRIPTION"); p != nil {
+		task.Description = p.Text()
+	}
+	var categories []string
+	for _, p := range c.GetAll("CATEGORIES") {
+		categories = append(categories, ical.SplitList(p.Value)...)
+	}
+	task.Categories = strings.Join(categories, ",")
+	task.Created = propTime(c, "CREATED")
+	task.Modified = propTime(c, "LAST-MODIFIED")
+	if task.Modified.IsZero() {
+		task.Modified = propTime(c, "DTSTAMP")
+	}
+	if task.Created.IsZero() {
+		task.Created = task.Modified
+	}
+	return task
+}
+
+// todoTask maps a VTODO to a task
+func (b *Backend) todoTask(c *ical.Component) backend.Task {
+	task := commonTask(c)
+	if p := c.Get("STATUS"); p != nil {
+		switch strings.ToUpper(strings.TrimSpace(p.Value)) {
+		case "COMPLETED":
+			task.Status = backend.StatusCompleted
+		case "IN-PROCESS":
+			task.Status = backend.StatusInProgress
+		case "CANCELLED":
+			task.Status = backend.StatusCancelled
+		}
+	}
+	if p := c.Get("PRIORITY"); p != nil {
+		if priority, err := strconv.Atoi(strings.TrimSpace(p.Value)); err == nil && priority >= 0 && priority <= 9 {
+			task.Priority = priority
+		}
+	}
+	task.DueDate = optionalTime(c, "DUE")
+	task.StartDate = optionalTime(c, "DTSTART")
+	task.Completed = optionalTime(c, "COMPLETED")
+	if task.Completed != nil {
+		task.Status = backend.StatusCompleted
+	}
+	if p := c.Get("RELATED-TO"); p != nil {
+		if reltype := p.Param("RELTYPE"); reltype == "" || strings.EqualFold(reltype, "PARENT") {
+			task.ParentID = strings.TrimSpace(p.Value)
+		}
+	}
+	if p := c.Get("RRULE"); p != nil {
+		task.Recurrence = p.Value
+	}
+	return task
+}
+
+// eventTask maps a VEVENT to a task due when the event starts. A recurring
+// event is due at its next occurrence from today on.
+func (b *Backend) eventTask(c *ical.Component) backend.Task {
+	task := commonTask(c)
+	if p := c.Get("STATUS"); p != nil && strings.EqualFold(strings.TrimSpace(p.Value), "CANCELLED") {
+		task.Status = backend.StatusCancelled
+	}
+	task.DueDate = optionalTime(c, "DTSTART")
+	if task.DueDate == nil {
+		return task
+	}
+	if p := c.Get("RRULE"); p != nil {
+		if rule, err := recurrence.Parse(p.Value); err == nil {
+			now := b.now()
+			today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, tz.Location())
+			due := *task.DueDate
+			it := rule.Iter(due)
+			for i := 0; i < maxOccurrences && due.Before(today); i++ {
+				next, ok := it.Next()
+				if !ok {
+					break
+				}
+				due = next
+			}
+			task.DueDate = &due
+		}
+	}
+	return task
+}
+
+// propTime returns the time of a DATE or DATE-TIME property, or the zero time
+func propTime(c *ical.Component, name string) time.Time {
+	if t := optionalTime(c, name); t != nil {
+		return *t
+	}
+	return time.Time{}
+}
+
+// optionalTime returns the time of a DATE or DATE-TIME property, or nil if it
+// is missing or invalid
+func optionalTime(c *ical.Component, name string) *time.Time {
+	p := c.Get(name)
+	if p == nil {
+		return nil
+	}
+	t, _, err := ical.ParseTime(*p)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+// timeKey formats an optional time for generated UIDs
+func timeKey(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,225 @@
+package icsurl
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"todoat/backend"
+)
+
+const feed = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"X-WR-CALNAME:Course Deadlines\r\n" +
+	"BEGIN:VTODO\r\n" +
+	"UID:todo-1\r\n" +
+	"SUMMARY:Read chapter 3\\, part 2\r\n" +
+	"DUE;VALUE=DATE:20261020\r\n" +
+	"PRIORITY:1\r\n" +
+	"STATUS:IN-PROCESS\r\n" +
+	"CATEGORIES:reading,course\r\n" +
+	"END:VTODO\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:event-1\r\n" +
+	"SUMMARY:Essay deadline\r\n" +
+	"DTSTART:20261101T120000Z\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:weekly-quiz\r\n" +
+	"SUMMARY:Weekly quiz\r\n" +
+	"DTSTART;VALUE=DATE:20260901\r\n" +
+	"RRULE:FREQ=WEEKLY\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:weekly-quiz\r\n" +
+	"RECURRENCE-ID;VALUE=DATE:20260908\r\n" +
+	"SUMMARY:Weekly quiz (moved)\r\n" +
+	"DTSTART;VALUE=DATE:20260909\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+// newFeedServer serves body and counts the requests
+func newFeedServer(t *testing.T, body *atomic.Value) (*httptest.Server, *atomic.Int32) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		data, _ := body.Load().(string)
+		if data == "" {
+			http.Error(w, "gone", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/calendar")
+		_, _ = w.Write([]byte(data))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestNewValidatesConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{"missing URL", Config{}},
+		{"unsupported scheme", Config{URL: "ftp://example.com/feed.ics"}},
+		{"no host", Config{URL: "https:///feed.ics"}},
+		{"negative TTL", Config{URL: "https://example.com/feed.ics", TTL: -time.Minute}},
+	}
+	for _, tt := range tests {
+		if _, err := New(tt.cfg); err == nil {
+			t.Errorf("%s: New succeeded, want an error", tt.name)
+		}
+	}
+
+	b, err := New(Config{URL: "webcal://example.com/feed.ics"})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if b.config.URL != "https://example.com/feed.ics" || b.config.TTL != DefaultTTL {
+		t.Errorf("config = %+v, want the https URL and the default TTL", b.config)
+	}
+}
+
+func TestFeedTasks(t *testing.T) {
+	var body atomic.Value
+	body.Store(feed)
+	server, _ := newFeedServer(t, &body)
+	b, err := New(Config{URL: server.URL})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	b.now = func() time.Time { return time.Date(2026, 10, 16, 10, 0, 0, 0, time.Local) }
+	ctx := context.Background()
+
+	lists, err := b.GetLists(ctx)
+	if err != nil {
+		t.Fatalf("GetLists: %v", err)
+	}
+	if len(lists) != 1 || lists[0].Name != "Course Deadlines" {
+		t.Fatalf("lists = %+v, want the feed's calendar name", lists)
+	}
+	tasks, err := b.GetTasks(ctx, lists[0].ID)
+	if err != nil {
+		t.Fatalf("GetTasks: %v", err)
+	}
+	if len(tasks) != 3 {
+		t.Fatalf("got %d tasks, want 3 (the moved occurrence is skipped): %+v", len(tasks), tasks)
+	}
+
+	todo := tasks[0]
+	if todo.Summary != "Read chapter 3, part 2" || todo.Status != backend.StatusInProgress || todo.Priority != 1 || todo.Categories != "reading,course" {
+		t.Errorf("todo = %+v", todo)
+	}
+	if todo.DueDate == nil || !todo.DueDate.Equal(time.Date(2026, 10, 20, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("todo due = %v, want 2026-10-20", todo.DueDate)
+	}
+
+	event := tasks[1]
+	if event.Summary != "Essay deadline" || event.Status != backend.StatusNeedsAction {
+		t.Errorf("event = %+v", event)
+	}
+	if event.DueDate == nil || !event.DueDate.Equal(time.Date(2026, 11, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("event due = %v, want its start", event.DueDate)
+	}
+
+	// A recurring event is due at its next occurrence from today
+	if quiz := tasks[2]; quiz.DueDate == nil || !quiz.DueDate.Equal(time.Date(2026, 10, 20, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("quiz due = %v, want Tuesday 2026-10-20", quiz.DueDate)
+	}
+
+	if task, _ := b.GetTask(ctx, lists[0].ID, "event-1"); task == nil || task.Summary != "Essay deadline" {
+		t.Errorf("GetTask(event-1) = %+v", task)
+	}
+}
+
+func TestFeedIsReadOnly(t *testing.T) {
+	b, err := New(Config{URL: "https://example.com/feed.ics"})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	ctx := context.Background()
+	if _, err := b.CreateTask(ctx, "list", &backend.Task{Summary: "x"}); !errors.Is(err, backend.ErrReadOnly) {
+		t.Errorf("CreateTask error = %v, want ErrReadOnly", err)
+	}
+	if _, err := b.UpdateTask(ctx, "list", &backend.Task{ID: "x"}); !errors.Is(err, backend.ErrReadOnly) {
+		t.Errorf("UpdateTask error = %v, want ErrReadOnly", err)
+	}
+	if err := b.DeleteTask(ctx, "list", "x"); !errors.Is(err, backend.ErrReadOnly) {
+		t.Errorf("DeleteTask error = %v, want ErrReadOnly", err)
+	}
+	if _, err := b.CreateList(ctx, "x"); !errors.Is(err, backend.ErrReadOnly) {
+		t.Errorf("CreateList error = %v, want ErrReadOnly", err)
+	}
+}
+
+func TestFeedRefreshesAfterTTL(t *testing.T) {
+	var body atomic.Value
+	body.Store(feed)
+	server, requests := newFeedServer(t, &body)
+	cachePath := filepath.Join(t.TempDir(), "ics", "course.ics")
+	now := time.Now()
+	clock := func() time.Time { return now }
+	ctx := context.Background()
+
+	open := func() *Backend {
+		b, err := New(Config{URL: server.URL, ListName: "Deadlines", TTL: time.Hour, CachePath: cachePath})
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+		b.now = clock
+		return b
+	}
+	summaries := func(b *Backend) []string {
+		lists, err := b.GetLists(ctx)
+		if err != nil {
+			t.Fatalf("GetLists: %v", err)
+		}
+		if lists[0].Name != "Deadlines" {
+			t.Errorf("list name = %q, want the configured name", lists[0].Name)
+		}
+		tasks, _ := b.GetTasks(ctx, lists[0].ID)
+		var out []string
+		for _, task := range tasks {
+			out = append(out, task.Summary)
+		}
+		return out
+	}
+
+	if got := summaries(open()); len(got) != 3 || requests.Load() != 1 {
+		t.Fatalf("first read: %v after %d requests", got, requests.Load())
+	}
+
+	// Within the TTL, a new process reads the cache file
+	body.Store("BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nUID:new\r\nSUMMARY:New item\r\nEND:VTODO\r\nEND:VCALENDAR\r\n")
+	if got := summaries(open()); len(got) != 3 || requests.Load() != 1 {
+		t.Errorf("within TTL: %v after %d requests, want the cached feed", got, requests.Load())
+	}
+
+	// Once the TTL expired, the feed is fetched again
+	now = now.Add(2 * time.Hour)
+	b := open()
+	if got := summaries(b); len(got) != 1 || got[0] != "New item" || requests.Load() != 2 {
+		t.Errorf("after TTL: %v after %d requests, want the new feed", got, requests.Load())
+	}
+
+	// A failed fetch falls back to the cached copy
+	body.Store("")
+	now = now.Add(2 * time.Hour)
+	if got := summaries(open()); len(got) != 1 || got[0] != "New item" || requests.Load() != 3 {
+		t.Errorf("failed fetch: %v after %d requests, want the cached feed", got, requests.Load())
+	}
+
+	// Without a cached copy the fetch error is returned
+	fresh, err := New(Config{URL: server.URL})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if _, err := fresh.GetLists(ctx); err == nil {
+		t.Error("GetLists succeeded without a feed or a cached copy")
+	}
+}
@@ -3,6 +3,7 @@ package backend
 import (
 	"context"
 	"errors"
+	"net/http"
 	"strings"
 	"time"
 
@@ -14,6 +15,16 @@ import (
 // not supported via CalDAV protocol.
 var ErrListCreationNotSupported = errors.New("creating lists is not supported by this backend")
 
+// ErrListArchiveNotSupported is returned when a backend does not support archiving lists.
+var ErrListArchiveNotSupported = errors.New("list archiving is not supported by this backend")
+
+// ErrListDefaultsNotSupported is returned when a backend cannot store per-list defaults.
+var ErrListDefaultsNotSupported = errors.New("per-list defaults are not supported by this backend")
+
+// CreatedByKey is the metadata key holding the account that created a task on a
+// shared backend (set by backends that know their user, such as Nextcloud)
+const CreatedByKey = "created_by"
+
 // Task represents a todo item
 type Task struct {
 	ID           string
@@ -27,10 +38,13 @@ type Task struct {
 	Created      time.Time
 	Modified     time.Time
 	ListID       string
-	ParentID     string // For subtasks
-	Categories   string // Comma-separated list of tags/categories
-	Recurrence   string // RRULE string: "FREQ=WEEKLY;INTERVAL=1"
-	RecurFromDue bool   // true = from due date, false = from completion
+	ParentID     string            // For subtasks
+	Categories   string            // Comma-separated list of tags/categories
+	Recurrence   string            // RRULE string: "FREQ=WEEKLY;INTERVAL=1"
+	RecurFromDue bool              // true = from due date, false = from completion
+	Position     int               // Manual order among siblings, lowest first; 0 = never reordered
+	Pinned       bool              // Kept above unpinned siblings
+	Metadata     map[string]string // Custom key-value fields; nil on update leaves stored metadata unchanged
 }
 
 // TaskStatus represents the completion state of a task
@@ -51,6 +65,8 @@ type List struct {
 	Description string
 	Modified    time.Time
 	DeletedAt   *time.Time // nil if not deleted, timestamp if in trash
+	ArchivedAt  *time.Time // nil if not archived, timestamp if archived
+	SharedBy    string     // Owner's display name when another user shared the list; empty for own lists
 }
 
 // TaskManager defines the interface for task storage backends
@@ -118,6 +134,226 @@ type ListSubscriber interface {
 	UnsubscribeList(ctx context.Context, listID string) error
 }
 
+// ListArchiver is an optional interface that backends can implement to support
+// archiving task lists. Archived lists keep all their tasks but are hidden from
+// GetLists and excluded from sync. GetList and GetListByName still return them
+// with ArchivedAt set, so archived data stays reachable by name.
+// Currently only supported by the SQLite backend.
+type ListArchiver interface {
+	// ArchiveList marks a list as archived.
+	ArchiveList(ctx context.Context, listID string) error
+
+	// UnarchiveList returns an archived list to the active lists.
+	UnarchiveList(ctx context.Context, listID string) error
+
+	// GetArchivedLists returns all archived (non-deleted) lists.
+	GetArchivedLists(ctx context.Context) ([]List, error)
+}
+
+// ListDefaults holds per-list settings applied when adding or getting tasks in a
+// list, unless overridden by command-line flags. Zero values mean "no default".
+type ListDefaults struct {
+	View     string   // View used by get
+	Sort     string   // Sort rule for get, in "field:direction" form
+	Tags     []string // Tags added to new tasks
+	Priority int      // Priority of new tasks (1-9)
+}
+
+// IsEmpty reports whether no default is set
+func (d ListDefaults) IsEmpty() bool {
+	return d.View == "" && d.Sort == "" && len(d.Tags) == 0 && d.Priority == 0
+}
+
+// ListDefaulter is an optional interface that backends can implement to store
+// per-list defaults alongside list metadata.
+// Currently only supported by the SQLite backend (including the sync cache).
+type ListDefaulter interface {
+	// GetListDefaults returns the defaults for a list (zero value if none are set).
+	GetListDefaults(ctx context.Context, listID string) (ListDefaults, error)
+
+	// SetListDefaults replaces the defaults for a list.
+	SetListDefaults(ctx context.Context, listID string, defaults ListDefaults) error
+}
+
+// TaskPager is an optional interface that backends can implement to return the
+// tasks of a list in pages, so very large lists can be loaded incrementally.
+// Pages follow the order of GetTasks, which must be stable, so consecutive pages
+// neither overlap nor skip tasks while the list is unchanged and paged and unpaged
+// loading show tasks in the same order.
+// Currently only supported by the SQLite backend (including the sync cache).
+type TaskPager interface {
+	// GetTasksPage returns up to limit tasks of a list, skipping the first offset tasks.
+	GetTasksPage(ctx context.Context, listID string, offset, limit int) ([]Task, error)
+}
+
+// TaskCounter is an optional interface that backends can implement to count the
+// tasks of their lists without loading them, used to show counts in 'todoat list'.
+// Currently only supported by the SQLite backend (including the sync cache); the
+// remote APIs do not report per-list task counts.
+type TaskCounter interface {
+	// GetTaskCounts returns the number of tasks in each list, keyed by list ID.
+	// Lists without tasks may be missing from the map.
+	GetTaskCounts(ctx context.Context) (map[string]int, error)
+}
+
+// TaskFinder is an optional interface that backends can implement to look up a
+// task by ID without knowing its list, used by 'todoat task <uid>'.
+// Currently only supported by the SQLite backend (including the sync cache), whose
+// task IDs are unique across lists.
+type TaskFinder interface {
+	// FindTask returns the task with the given ID in any list not in the trash,
+	// with ListID set, or nil if there is none.
+	FindTask(ctx context.Context, taskID string) (*Task, error)
+}
+
+// TaskBatcher is an optional interface that backends can implement to update or
+// delete many tasks of a list at once. Either every task in the batch is changed
+// or none is, and a batch is much faster than one call per task on large trees.
+// Currently only supported by the SQLite backend (including the sync cache).
+type TaskBatcher interface {
+	// UpdateTasks modifies the given tasks of a list and returns them as stored.
+	UpdateTasks(ctx context.Context, listID string, tasks []Task) ([]Task, error)
+
+	// DeleteTasks removes the tasks with the given IDs from a list. Children must be
+	// listed before their parents.
+	DeleteTasks(ctx context.Context, listID string, taskIDs []string) error
+}
+
+// RawRequester is an optional interface for HTTP backends that can send an arbitrary
+// authenticated request to their API, used by 'backend raw' to debug discrepancies
+// between todoat's view and the remote data.
+// Supported by the Todoist, Nextcloud, Google Tasks and Microsoft To Do backends.
+type RawRequester interface {
+	// RawRequest sends a request without a body to path, relative to the backend's API
+	// base URL, with the backend's credentials. The caller closes the response body.
+	RawRequest(ctx context.Context, method, path string) (*http.Response, error)
+}
+
+// RawQuerier is an optional interface for database backends that can run a read-only
+// SQL query, used by 'backend sql' for debugging. Statements that write are rejected.
+// Currently only supported by the SQLite backend.
+type RawQuerier interface {
+	// QueryReadOnly runs query and returns its column names and rows as strings (NULL as "").
+	QueryReadOnly(ctx context.Context, query string) ([]string, [][]string, error)
+}
+
+// ConcurrentReader is an optional interface for backends whose GetTasks can be
+// called from several goroutines at once, for different lists. Sync pulls the
+// lists of these backends in parallel.
+// Supported by the Todoist and Nextcloud backends.
+type ConcurrentReader interface {
+	ReadsConcurrently() bool
+}
+
+// ReadsConcurrently reports whether tm, or the backend it wraps, can read the
+// tasks of several lists at once
+func ReadsConcurrently(tm TaskManager) bool {
+	reader, ok := findOptional[ConcurrentReader](tm)
+	return ok && reader.ReadsConcurrently()
+}
+
+// GetTasksPage returns a page of a list's tasks, using TaskPager when the backend
+// supports it and slicing the full GetTasks result otherwise.
+func GetTasksPage(ctx context.Context, tm TaskManager, listID string, offset, limit int) ([]Task, error) {
+	if pager, ok := tm.(TaskPager); ok {
+		return pager.GetTasksPage(ctx, listID, offset, limit)
+	}
+	tasks, err := tm.GetTasks(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+	return PageTasks(tasks, offset, limit), nil
+}
+
+// GetTaskCounts returns the number of tasks in each of lists, keyed by list ID, using
+// TaskCounter when the backend supports it and loading each list's tasks otherwise.
+// When loading, a list whose tasks cannot be fetched is left out of the map.
+func GetTaskCounts(ctx context.Context, tm TaskManager, lists []List) (map[string]int, error) {
+	if counter, ok := tm.(TaskCounter); ok {
+		return counter.GetTaskCounts(ctx)
+	}
+	counts := make(map[string]int, len(lists))
+	for _, l := range lists {
+		tasks, err := tm.GetTasks(ctx, l.ID)
+		if err != nil {
+			continue
+		}
+		counts[l.ID] = len(tasks)
+	}
+	return counts, nil
+}
+
+// FindTask returns the task with the given ID and ListID set, using TaskFinder
+// when the backend supports it and searching the lists one by one otherwise.
+// It returns nil if no list has the task.
+func FindTask(ctx context.Context, tm TaskManager, taskID string) (*Task, error) {
+	if finder, ok := tm.(TaskFinder); ok {
+		return finder.FindTask(ctx, taskID)
+	}
+	lists, err := tm.GetLists(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range lists {
+		task, err := tm.GetTask(ctx, l.ID, taskID)
+		if err != nil || task == nil {
+			continue
+		}
+		if task.ListID == "" {
+			task.ListID = l.ID
+		}
+		return task, nil
+	}
+	return nil, nil
+}
+
+// UpdateTasks updates several tasks of a list, in one batch when the backend
+// supports TaskBatcher and one UpdateTask call per task otherwise.
+func UpdateTasks(ctx context.Context, tm TaskManager, listID string, tasks []Task) ([]Task, error) {
+	if batcher, ok := tm.(TaskBatcher); ok {
+		return batcher.UpdateTasks(ctx, listID, tasks)
+	}
+	updated := make([]Task, 0, len(tasks))
+	for i := range tasks {
+		task, err := tm.UpdateTask(ctx, listID, &tasks[i])
+		if err != nil {
+			return nil, err
+		}
+		updated = append(updated, *task)
+	}
+	return updated, nil
+}
+
+// DeleteTasks deletes several tasks of a list, in one batch when the backend
+// supports TaskBatcher and one DeleteTask call per task otherwise.
+func DeleteTasks(ctx context.Context, tm TaskManager, listID string, taskIDs []string) error {
+	if batcher, ok := tm.(TaskBatcher); ok {
+		return batcher.DeleteTasks(ctx, listID, taskIDs)
+	}
+	for _, id := range taskIDs {
+		if err := tm.DeleteTask(ctx, listID, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PageTasks returns the tasks[offset:offset+limit] window, clamped to the slice.
+// A limit of 0 or less returns everything after offset.
+func PageTasks(tasks []Task, offset, limit int) []Task {
+	if offset >= len(tasks) {
+		return []Task{}
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	end := len(tasks)
+	if limit > 0 && offset+limit < end {
+		end = offset + limit
+	}
+	return tasks[offset:end]
+}
+
 // FindListByName searches for a list by name (case-insensitive) in a slice of lists.
 // Returns nil if no match is found. This helper reduces code duplication across backends.
 func FindListByName(lists []List, name string) *List {
@@ -134,3 +370,20 @@ func FindListByName(lists []List, name string) *List {
 func GenerateID() string {
 	return uuid.New().String()
 }
+
+// findOptional returns tm, or the first backend it wraps (through Unwrap), that
+// implements the optional interface T
+func findOptional[T any](tm TaskManager) (T, bool) {
+	for tm != nil {
+		if v, ok := tm.(T); ok {
+			return v, true
+		}
+		wrapper, ok := tm.(interface{ Unwrap() TaskManager })
+		if !ok {
+			break
+		}
+		tm = wrapper.Unwrap()
+	}
+	var zero T
+	return zero, false
+}
@@ -0,0 +1,71 @@
+package issues_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"todoat/internal/testutil"
+)
+
+// TestIssuesBackendCLI verifies that a configured issues backend lists the
+// repository and that adding a task opens an issue
+func TestIssuesBackendCLI(t *testing.T) {
+	var mu sync.Mutex
+	issues := []map[string]interface{}{
+		{"number": 1, "title": "Fix login", "state": "open", "labels": []map[string]string{{"name": "bug"}}},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Header.Get("Authorization") != "Bearer gh-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/octo/app/issues":
+			_ = json.NewEncoder(w).Encode(issues)
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/octo/app/milestones":
+			_, _ = w.Write([]byte("[]"))
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/octo/app/issues":
+			var body map[string]interface{}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			created := map[string]interface{}{"number": len(issues) + 1, "title": body["title"], "state": "open"}
+			issues = append(issues, created)
+			_ = json.NewEncoder(w).Encode(created)
+		default:
+			http.Error(w, "unexpected request", http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	cli := testutil.NewCLITestWithConfig(t)
+	cli.SetFullConfig(`
+backends:
+  sqlite:
+    enabled: true
+  work:
+    type: issues
+    provider: github
+    token: gh-token
+    base_url: "` + server.URL + `"
+    repos: [octo/app]
+no_prompt: true
+`)
+
+	stdout := cli.MustExecute("-y", "-b", "work", "list")
+	testutil.AssertContains(t, stdout, "octo/app")
+
+	stdout = cli.MustExecute("-y", "-b", "work", "octo/app")
+	testutil.AssertContains(t, stdout, "Fix login")
+
+	cli.MustExecute("-y", "-b", "work", "octo/app", "add", "Triage inbox")
+	mu.Lock()
+	defer mu.Unlock()
+	if len(issues) != 2 || !strings.Contains(issues[1]["title"].(string), "Triage inbox") {
+		t.Errorf("expected add to open an issue, got %v", issues)
+	}
+}
@@ -0,0 +1,893 @@
+// Package issues provides a backend that maps the issues of GitHub or GitLab
+// repositories to task lists. Each configured repository is a list; open and
+// closed issues are pending and done tasks, labels are tags, and milestones
+// become either parent tasks or due dates.
+package issues
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"todoat/backend"
+	"todoat/internal/tz"
+)
+
+const (
+	// DefaultGitHubURL is the GitHub REST API base URL
+	DefaultGitHubURL = "https://api.github.com"
+	// DefaultGitLabURL is the GitLab.com REST API base URL
+	DefaultGitLabURL = "https://gitlab.com/api/v4"
+)
+
+// Supported issue trackers
+const (
+	ProviderGitHub = "github"
+	ProviderGitLab = "gitlab"
+)
+
+// Milestone mapping modes
+const (
+	// MilestonesAsParents shows each milestone as a parent task of its issues
+	MilestonesAsParents = "parents"
+	// MilestonesAsDueDates uses a milestone's due date as the due date of its issues
+	MilestonesAsDueDates = "due_dates"
+)
+
+// pageSize is the number of items requested per page
+const pageSize = 100
+
+// Config holds issue tracker connection settings
+type Config struct {
+	Provider   string   // "github" (default) or "gitlab"
+	Token      string   // Personal access token
+	BaseURL    string   // API base URL, for GitHub Enterprise, self-hosted GitLab or tests
+	Repos      []string // Repositories as "owner/repo" (GitLab: full project path)
+	Assignee   string   // Only show issues assigned to this user; new issues are assigned to them
+	Milestones string   // "parents" (default) or "due_dates"
+}
+
+// ConfigFromEnv creates a Config from environment variables
+func ConfigFromEnv() Config {
+	cfg := Config{
+		Provider: os.Getenv("TODOAT_ISSUES_PROVIDER"),
+		Token:    os.Getenv("TODOAT_ISSUES_TOKEN"),
+		Assignee: os.Getenv("TODOAT_ISSUES_ASSIGNEE"),
+	}
+	for _, repo := range strings.Split(os.Getenv("TODOAT_ISSUES_REPOS"), ",") {
+		if repo = strings.TrimSpace(repo); repo != "" {
+			cfg.Repos = append(cfg.Repos, repo)
+		}
+	}
+	return cfg
+}
+
+// Backend implements backend.TaskManager on top of a forge's issues API
+type Backend struct {
+	config  Config
+	client  *http.Client
+	baseURL string
+	forge   forge
+}
+
+// New creates a new issues backend
+func New(cfg Config) (*Backend, error) {
+	if cfg.Provider == "" {
+		cfg.Provider = ProviderGitHub
+	}
+	if cfg.Milestones == "" {
+		cfg.Milestones = MilestonesAsParents
+	}
+	if cfg.Token == "" {
+		return nil, fmt.Errorf("%s access token is required", cfg.Provider)
+	}
+	if len(cfg.Repos) == 0 {
+		return nil, fmt.Errorf("at least one repository is required (repos: [owner/repo])")
+	}
+	for _, repo := range cfg.Repos {
+		if !strings.Contains(repo, "/") {
+			return nil, fmt.Errorf("invalid repository %q (expected owner/repo)", repo)
+		}
+	}
+	if cfg.Milestones != MilestonesAsParents && cfg.Milestones != MilestonesAsDueDates {
+		return nil, fmt.Errorf("invalid milestones mode %q (must be one of: %s, %s)", cfg.Milestones, MilestonesAsParents, MilestonesAsDueDates)
+	}
+
+	b := &Backend{config: cfg, client: createHTTPClient()}
+	switch cfg.Provider {
+	case ProviderGitHub:
+		b.baseURL = DefaultGitHubURL
+		b.forge = &github{b: b}
+	case ProviderGitLab:
+		b.baseURL = DefaultGitLabURL
+		b.forge = &gitlab{b: b}
+	default:
+		return nil, fmt.Errorf("invalid provider %q (must be one of: %s, %s)", cfg.Provider, ProviderGitHub, ProviderGitLab)
+	}
+	if cfg.BaseURL != "" {
+		b.baseURL = strings.TrimSuffix(cfg.BaseURL, "/")
+	}
+	return b, nil
+}
+
+// createHTTPClient creates an HTTP client with proper configuration
+func createHTTPClient() *http.Client {
+	return &http.Client{
+		Timeout: 30 * time.Second,
+	}
+}
+
+// Close closes the backend
+func (b *Backend) Close() error {
+	if b.client == nil {
+		return nil
+	}
+	if transport, ok := b.client.Transport.(*http.Transport); ok {
+		transport.CloseIdleConnections()
+	}
+	return nil
+}
+
+// RawRequest sends an authenticated request to path, relative to the API base URL
+func (b *Backend) RawRequest(ctx context.Context, method, path string) (*http.Response, error) {
+	return b.doRequest(ctx, method, path, nil)
+}
+
+// doRequest performs an authenticated API request with a JSON body
+func (b *Backend) doRequest(ctx context.Context, method, path string, body interface{}) (*http.Response, error) {
+	var bodyReader io.Reader
+	if body != nil {
+		jsonBody, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		bodyReader = bytes.NewReader(jsonBody)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, b.baseURL+path, bodyReader)
+	if err != nil {
+		return nil, err
+	}
+	b.forge.authorize(req)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return b.client.Do(req)
+}
+
+// doJSON performs a request and decodes a successful response into out
+func (b *Backend) doJSON(ctx context.Context, method, path string, body, out interface{}, action string) error {
+	resp, err := b.doRequest(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		return fmt.Errorf("authentication failed: invalid %s token", b.config.Provider)
+	case resp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("failed to %s: not found (check the repository name and token scopes)", action)
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return fmt.Errorf("failed to %s: status %d", action, resp.StatusCode)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// =============================================================================
+// Forge Abstraction
+// =============================================================================
+
+// issue is a provider-neutral issue
+type issue struct {
+	Number     int
+	Title      string
+	Body       string
+	Closed     bool
+	NotPlanned bool // Closed without being done
+	Labels     []string
+	Milestone  *milestone
+	Due        *time.Time // Only GitLab issues have their own due date
+	Created    time.Time
+	Updated    time.Time
+	ClosedAt   *time.Time
+}
+
+// milestone is a provider-neutral milestone. ID is the identifier the
+// provider expects when assigning it to an issue.
+type milestone struct {
+	ID          int
+	Title       string
+	Description string
+	Closed      bool
+	Due         *time.Time
+	Created     time.Time
+	Updated     time.Time
+}
+
+// issueChange describes the fields written when creating or updating an issue
+type issueChange struct {
+	Title       string
+	Body        string
+	Labels      []string
+	Closed      bool
+	NotPlanned  bool
+	MilestoneID int // 0 clears the milestone
+	Due         *time.Time
+}
+
+// forge is the provider-specific part of the backend
+type forge interface {
+	authorize(req *http.Request)
+	listIssues(ctx context.Context, repo string) ([]issue, error)
+	listMilestones(ctx context.Context, repo string) ([]milestone, error)
+	createIssue(ctx context.Context, repo string, change issueChange) (*issue, error)
+	updateIssue(ctx context.Context, repo string, number int, change issueChange) (*issue, error)
+}
+
+// =============================================================================
+// List Operations
+// =============================================================================
+
+// GetLists returns one list per configured repository
+func (b *Backend) GetLists(ctx context.Context) ([]backend.List, error) {
+	lists := make([]backend.List, len(b.config.Repos))
+	for i, repo := range b.config.Repos {
+		lists[i] = backend.List{
+			ID:       repo,
+			Name:     repo,
+			Modified: time.Now(),
+		}
+	}
+	return lists, nil
+}
+
+// GetList returns the list of a configured repository
+func (b *Backend) GetList(ctx context.Context, listID string) (*backend.List, error) {
+	lists, _ := b.GetLists(ctx)
+	for i := range lists {
+		if lists[i].ID == listID {
+			return &lists[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// GetListByName returns a repository list by name (case-insensitive)
+func (b *Backend) GetListByName(ctx context.Context, name string) (*backend.List, error) {
+	lists, _ := b.GetLists(ctx)
+	return backend.FindListByName(lists, name), nil
+}
+
+// CreateList is not supported: lists are the repositories named in the config
+func (b *Backend) CreateList(ctx context.Context, name string) (*backend.List, error) {
+	return nil, fmt.Errorf("%w: add the repository to the backend's repos setting instead", backend.ErrListCreationNotSupported)
+}
+
+// UpdateList is not supported: repositories cannot be renamed from todoat
+func (b *Backend) UpdateList(ctx context.Context, list *backend.List) (*backend.List, error) {
+	return nil, fmt.Errorf("repositories cannot be renamed from todoat")
+}
+
+// SupportsTrash returns false because repositories are never deleted.
+func (b *Backend) SupportsTrash() bool { return false }
+
+// DeleteList is not supported: remove the repository from the config instead
+func (b *Backend) DeleteList(ctx context.Context, listID string) error {
+	return fmt.Errorf("repositories cannot be deleted from todoat; remove %s from the backend's repos setting instead", listID)
+}
+
+// GetDeletedLists returns deleted lists (not supported)
+func (b *Backend) GetDeletedLists(ctx context.Context) ([]backend.List, error) {
+	return []backend.List{}, nil
+}
+
+// GetDeletedListByName returns a deleted list by name (not supported)
+func (b *Backend) GetDeletedListByName(ctx context.Context, name string) (*backend.List, error) {
+	return nil, nil
+}
+
+// RestoreList restores a deleted list (not supported)
+func (b *Backend) RestoreList(ctx context.Context, listID string) error {
+	return fmt.Errorf("restoring lists is not supported by the issues backend")
+}
+
+// PurgeList permanently deletes a list (not supported)
+func (b *Backend) PurgeList(ctx context.Context, listID string) error {
+	return fmt.Errorf("purging lists is not supported by the issues backend")
+}
+
+// =============================================================================
+// Task Operations
+// =============================================================================
+
+// GetTasks returns the issues of a repository. Pull requests are skipped.
+// In "parents" mode the repository's milestones are returned as well, as
+// the parents of their issues.
+func (b *Backend) GetTasks(ctx context.Context, listID string) ([]backend.Task, error) {
+	if err := b.checkRepo(listID); err != nil {
+		return nil, err
+	}
+	issues, err := b.forge.listIssues(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []backend.Task
+	if b.config.Milestones == MilestonesAsParents {
+		milestones, err := b.forge.listMilestones(ctx, listID)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range milestones {
+			tasks = append(tasks, milestoneToTask(listID, m))
+		}
+	}
+	for _, is := range issues {
+		tasks = append(tasks, b.issueToTask(listID, is))
+	}
+	return tasks, nil
+}
+
+// GetTask returns a single issue or milestone task
+func (b *Backend) GetTask(ctx context.Context, listID, taskID string) (*backend.Task, error) {
+	tasks, err := b.GetTasks(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range tasks {
+		if tasks[i].ID == taskID {
+			return &tasks[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// CreateTask opens a new issue. A parent task must be a milestone.
+func (b *Backend) CreateTask(ctx context.Context, listID string, task *backend.Task) (*backend.Task, error) {
+	if err := b.checkRepo(listID); err != nil {
+		return nil, err
+	}
+	change, err := b.changeFromTask(listID, task)
+	if err != nil {
+		return nil, err
+	}
+	created, err := b.forge.createIssue(ctx, listID, change)
+	if err != nil {
+		return nil, err
+	}
+	// Issues are always opened; close them afterwards if the task is done
+	if change.Closed {
+		if created, err = b.forge.updateIssue(ctx, listID, created.Number, change); err != nil {
+			return nil, err
+		}
+	}
+	result := b.issueToTask(listID, *created)
+	return &result, nil
+}
+
+// UpdateTask updates an issue's title, body, state, labels and milestone.
+// Milestone tasks are read-only.
+func (b *Backend) UpdateTask(ctx context.Context, listID string, task *backend.Task) (*backend.Task, error) {
+	if err := b.checkRepo(listID); err != nil {
+		return nil, err
+	}
+	if _, ok := parseMilestoneTaskID(listID, task.ID); ok {
+		return nil, fmt.Errorf("milestone %q is read-only; edit it on the %s website", task.Summary, b.config.Provider)
+	}
+	number, ok := parseIssueTaskID(listID, task.ID)
+	if !ok {
+		return nil, fmt.Errorf("invalid issue ID %q", task.ID)
+	}
+	change, err := b.changeFromTask(listID, task)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := b.forge.updateIssue(ctx, listID, number, change)
+	if err != nil {
+		return nil, err
+	}
+	result := b.issueToTask(listID, *updated)
+	return &result, nil
+}
+
+// DeleteTask closes an issue as not planned. Forges only let administrators
+// delete issues, so the issue stays in the repository's history.
+func (b *Backend) DeleteTask(ctx context.Context, listID, taskID string) error {
+	task, err := b.GetTask(ctx, listID, taskID)
+	if err != nil {
+		return err
+	}
+	if task == nil {
+		return fmt.Errorf("issue %s not found", taskID)
+	}
+	task.Status = backend.StatusCancelled
+	_, err = b.UpdateTask(ctx, listID, task)
+	return err
+}
+
+// checkRepo rejects list IDs that are not configured repositories
+func (b *Backend) checkRepo(repo string) error {
+	for _, r := range b.config.Repos {
+		if r == repo {
+			return nil
+		}
+	}
+	return fmt.Errorf("repository %q is not configured for this backend", repo)
+}
+
+// changeFromTask builds the issue fields written for a task
+func (b *Backend) changeFromTask(listID string, task *backend.Task) (issueChange, error) {
+	change := issueChange{
+		Title:      task.Summary,
+		Body:       task.Description,
+		Labels:     categoriesToLabels(task.Categories),
+		Closed:     task.Status == backend.StatusCompleted || task.Status == backend.StatusCancelled,
+		NotPlanned: task.Status == backend.StatusCancelled,
+		Due:        task.DueDate,
+	}
+	if task.ParentID != "" {
+		id, ok := parseMilestoneTaskID(listID, task.ParentID)
+		if !ok || b.config.Milestones != MilestonesAsParents {
+			return change, fmt.Errorf("issues cannot have subtasks; only a milestone can be a parent")
+		}
+		change.MilestoneID = id
+	}
+	return change, nil
+}
+
+// issueToTask converts an issue to a task
+func (b *Backend) issueToTask(repo string, is issue) backend.Task {
+	task := backend.Task{
+		ID:          issueTaskID(repo, is.Number),
+		Summary:     is.Title,
+		Description: is.Body,
+		Status:      backend.StatusNeedsAction,
+		Categories:  strings.Join(is.Labels, ","),
+		Created:     is.Created,
+		Modified:    is.Updated,
+		ListID:      repo,
+		DueDate:     is.Due,
+	}
+	if is.Closed {
+		task.Status = backend.StatusCompleted
+		if is.NotPlanned {
+			task.Status = backend.StatusCancelled
+		}
+		task.Completed = is.ClosedAt
+	}
+	if is.Milestone != nil {
+		if b.config.Milestones == MilestonesAsParents {
+			task.ParentID = milestoneTaskID(repo, is.Milestone.ID)
+		} else if task.DueDate == nil {
+			task.DueDate = is.Milestone.Due
+		}
+	}
+	return task
+}
+
+// milestoneToTask converts a milestone to a parent task
+func milestoneToTask(repo string, m milestone) backend.Task {
+	task := backend.Task{
+		ID:          milestoneTaskID(repo, m.ID),
+		Summary:     m.Title,
+		Description: m.Description,
+		Status:      backend.StatusNeedsAction,
+		DueDate:     m.Due,
+		Created:     m.Created,
+		Modified:    m.Updated,
+		ListID:      repo,
+	}
+	if m.Closed {
+		task.Status = backend.StatusCompleted
+	}
+	return task
+}
+
+// issueTaskID returns the task ID of an issue: "owner/repo#12"
+func issueTaskID(repo string, number int) string {
+	return fmt.Sprintf("%s#%d", repo, number)
+}
+
+// milestoneTaskID returns the task ID of a milestone: "owner/repo/milestones/3"
+func milestoneTaskID(repo string, id int) string {
+	return fmt.Sprintf("%s/milestones/%d", repo, id)
+}
+
+// parseIssueTaskID extracts the issue number from an issue task ID
+func parseIssueTaskID(repo, taskID string) (int, bool) {
+	return parseTaskNumber(taskID, repo+"#")
+}
+
+// parseMilestoneTaskID extracts the milestone ID from a milestone task ID
+func parseMilestoneTaskID(repo, taskID string) (int, bool) {
+	return parseTaskNumber(taskID, repo+"/milestones/")
+}
+
+func parseTaskNumber(taskID, prefix string) (int, bool) {
+	if !strings.HasPrefix(taskID, prefix) {
+		return 0, false
+	}
+	n, err := strconv.Atoi(strings.TrimPrefix(taskID, prefix))
+	return n, err == nil && n > 0
+}
+
+// categoriesToLabels converts comma-separated categories to sorted, de-duplicated labels
+func categoriesToLabels(categories string) []string {
+	labels := []string{}
+	seen := map[string]bool{}
+	for _, label := range strings.Split(categories, ",") {
+		if label = strings.TrimSpace(label); label != "" && !seen[label] {
+			seen[label] = true
+			labels = append(labels, label)
+		}
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+// =============================================================================
+// GitHub
+// =============================================================================
+
+// github talks to the GitHub REST API
+type github struct {
+	b *Backend
+}
+
+type githubIssue struct {
+	Number      int    `json:"number"`
+	Title       string `json:"title"`
+	Body        string `json:"body"`
+	State       string `json:"state"`
+	StateReason string `json:"state_reason"`
+	Labels      []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	Milestone   *githubMilestone `json:"milestone"`
+	PullRequest *struct{}        `json:"pull_request"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+	ClosedAt    *time.Time       `json:"closed_at"`
+}
+
+type githubMilestone struct {
+	Number      int        `json:"number"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	State       string     `json:"state"`
+	DueOn       *time.Time `json:"due_on"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+func (g *github) authorize(req *http.Request) {
+	req.Header.Set("Authorization", "Bearer "+g.b.config.Token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+}
+
+func (g *github) listIssues(ctx context.Context, repo string) ([]issue, error) {
+	var issues []issue
+	for page := 1; ; page++ {
+		path := fmt.Sprintf("/repos/%s/issues?state=all&per_page=%d&page=%d", repo, pageSize, page)
+		if g.b.config.Assignee != "" {
+			path += "&assignee=" + url.QueryEscape(g.b.config.Assignee)
+		}
+		var items []githubIssue
+		if err := g.b.doJSON(ctx, http.MethodGet, path, nil, &items, "list issues of "+repo); err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			if item.PullRequest != nil {
+				continue
+			}
+			issues = append(issues, item.toIssue())
+		}
+		if len(items) < pageSize {
+			return issues, nil
+		}
+	}
+}
+
+func (g *github) listMilestones(ctx context.Context, repo string) ([]milestone, error) {
+	var milestones []milestone
+	for page := 1; ; page++ {
+		path := fmt.Sprintf("/repos/%s/milestones?state=all&per_page=%d&page=%d", repo, pageSize, page)
+		var items []githubMilestone
+		if err := g.b.doJSON(ctx, http.MethodGet, path, nil, &items, "list milestones of "+repo); err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			milestones = append(milestones, item.toMilestone())
+		}
+		if len(items) < pageSize {
+			return milestones, nil
+		}
+	}
+}
+
+func (g *github) createIssue(ctx context.Context, repo string, change issueChange) (*issue, error) {
+	body := map[string]interface{}{
+		"title":  change.Title,
+		"body":   change.Body,
+		"labels": change.Labels,
+	}
+	if change.MilestoneID != 0 {
+		body["milestone"] = change.MilestoneID
+	}
+	if g.b.config.Assignee != "" {
+		body["assignees"] = []string{g.b.config.Assignee}
+	}
+	var item githubIssue
+	if err := g.b.doJSON(ctx, http.MethodPost, "/repos/"+repo+"/issues", body, &item, "create issue in "+repo); err != nil {
+		return nil, err
+	}
+	is := item.toIssue()
+	return &is, nil
+}
+
+func (g *github) updateIssue(ctx context.Context, repo string, number int, change issueChange) (*issue, error) {
+	body := map[string]interface{}{
+		"title":     change.Title,
+		"body":      change.Body,
+		"labels":    change.Labels,
+		"state":     "open",
+		"milestone": nil,
+	}
+	if change.Closed {
+		body["state"] = "closed"
+		body["state_reason"] = "completed"
+		if change.NotPlanned {
+			body["state_reason"] = "not_planned"
+		}
+	}
+	if change.MilestoneID != 0 {
+		body["milestone"] = change.MilestoneID
+	}
+	var item githubIssue
+	path := fmt.Sprintf("/repos/%s/issues/%d", repo, number)
+	if err := g.b.doJSON(ctx, http.MethodPatch, path, body, &item, fmt.Sprintf("update issue %s#%d", repo, number)); err != nil {
+		return nil, err
+	}
+	is := item.toIssue()
+	return &is, nil
+}
+
+func (i githubIssue) toIssue() issue {
+	is := issue{
+		Number:     i.Number,
+		Title:      i.Title,
+		Body:       i.Body,
+		Closed:     i.State == "closed",
+		NotPlanned: i.StateReason == "not_planned",
+		Created:    i.CreatedAt,
+		Updated:    i.UpdatedAt,
+		ClosedAt:   i.ClosedAt,
+	}
+	for _, label := range i.Labels {
+		is.Labels = append(is.Labels, label.Name)
+	}
+	if i.Milestone != nil {
+		m := i.Milestone.toMilestone()
+		is.Milestone = &m
+	}
+	return is
+}
+
+func (m githubMilestone) toMilestone() milestone {
+	return milestone{
+		ID:          m.Number,
+		Title:       m.Title,
+		Description: m.Description,
+		Closed:      m.State == "closed",
+		Due:         m.DueOn,
+		Created:     m.CreatedAt,
+		Updated:     m.UpdatedAt,
+	}
+}
+
+// =============================================================================
+// GitLab
+// =============================================================================
+
+// gitlab talks to the GitLab REST API (v4)
+type gitlab struct {
+	b          *Backend
+	assigneeID int // Resolved from Config.Assignee on first use
+}
+
+type gitlabIssue struct {
+	IID         int              `json:"iid"`
+	Title       string           `json:"title"`
+	Description string           `json:"description"`
+	State       string           `json:"state"` // "opened" or "closed"
+	Labels      []string         `json:"labels"`
+	Milestone   *gitlabMilestone `json:"milestone"`
+	DueDate     string           `json:"due_date"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+	ClosedAt    *time.Time       `json:"closed_at"`
+}
+
+type gitlabMilestone struct {
+	ID          int       `json:"id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	State       string    `json:"state"` // "active" or "closed"
+	DueDate     string    `json:"due_date"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (g *gitlab) authorize(req *http.Request) {
+	req.Header.Set("PRIVATE-TOKEN", g.b.config.Token)
+}
+
+// projectPath returns the API path of a project, addressed by its URL-encoded full path
+func (g *gitlab) projectPath(repo string) string {
+	return "/projects/" + url.PathEscape(repo)
+}
+
+func (g *gitlab) listIssues(ctx context.Context, repo string) ([]issue, error) {
+	var issues []issue
+	for page := 1; ; page++ {
+		path := fmt.Sprintf("%s/issues?state=all&per_page=%d&page=%d", g.projectPath(repo), pageSize, page)
+		if g.b.config.Assignee != "" {
+			path += "&assignee_username=" + url.QueryEscape(g.b.config.Assignee)
+		}
+		var items []gitlabIssue
+		if err := g.b.doJSON(ctx, http.MethodGet, path, nil, &items, "list issues of "+repo); err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			issues = append(issues, item.toIssue())
+		}
+		if len(items) < pageSize {
+			return issues, nil
+		}
+	}
+}
+
+func (g *gitlab) listMilestones(ctx context.Context, repo string) ([]milestone, error) {
+	var milestones []milestone
+	for page := 1; ; page++ {
+		path := fmt.Sprintf("%s/milestones?per_page=%d&page=%d", g.projectPath(repo), pageSize, page)
+		var items []gitlabMilestone
+		if err := g.b.doJSON(ctx, http.MethodGet, path, nil, &items, "list milestones of "+repo); err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			milestones = append(milestones, item.toMilestone())
+		}
+		if len(items) < pageSize {
+			return milestones, nil
+		}
+	}
+}
+
+func (g *gitlab) createIssue(ctx context.Context, repo string, change issueChange) (*issue, error) {
+	body := g.issueBody(change)
+	if g.b.config.Assignee != "" {
+		id, err := g.resolveAssignee(ctx)
+		if err != nil {
+			return nil, err
+		}
+		body["assignee_ids"] = []int{id}
+	}
+	var item gitlabIssue
+	if err := g.b.doJSON(ctx, http.MethodPost, g.projectPath(repo)+"/issues", body, &item, "create issue in "+repo); err != nil {
+		return nil, err
+	}
+	is := item.toIssue()
+	return &is, nil
+}
+
+func (g *gitlab) updateIssue(ctx context.Context, repo string, number int, change issueChange) (*issue, error) {
+	body := g.issueBody(change)
+	body["state_event"] = "reopen"
+	if change.Closed {
+		body["state_event"] = "close"
+	}
+	var item gitlabIssue
+	path := fmt.Sprintf("%s/issues/%d", g.projectPath(repo), number)
+	if err := g.b.doJSON(ctx, http.MethodPut, path, body, &item, fmt.Sprintf("update issue %s#%d", repo, number)); err != nil {
+		return nil, err
+	}
+	is := item.toIssue()
+	return &is, nil
+}
+
+// issueBody builds the fields shared by issue creation and updates.
+// A milestone_id of 0 unassigns the milestone.
+func (g *gitlab) issueBody(change issueChange) map[string]interface{} {
+	body := map[string]interface{}{
+		"title":        change.Title,
+		"description":  change.Body,
+		"labels":       strings.Join(change.Labels, ","),
+		"milestone_id": change.MilestoneID,
+		"due_date":     "",
+	}
+	if change.Due != nil {
+		body["due_date"] = change.Due.Format("2006-01-02")
+	}
+	return body
+}
+
+// resolveAssignee looks up the user ID of the configured assignee, which
+// GitLab requires when assigning an issue
+func (g *gitlab) resolveAssignee(ctx context.Context) (int, error) {
+	if g.assigneeID != 0 {
+		return g.assigneeID, nil
+	}
+	var users []struct {
+		ID int `json:"id"`
+	}
+	path := "/users?username=" + url.QueryEscape(g.b.config.Assignee)
+	if err := g.b.doJSON(ctx, http.MethodGet, path, nil, &users, "look up user "+g.b.config.Assignee); err != nil {
+		return 0, err
+	}
+	if len(users) == 0 {
+		return 0, fmt.Errorf("gitlab user %q not found", g.b.config.Assignee)
+	}
+	g.assigneeID = users[0].ID
+	return g.assigneeID, nil
+}
+
+func (i gitlabIssue) toIssue() issue {
+	is := issue{
+		Number:   i.IID,
+		Title:    i.Title,
+		Body:     i.Description,
+		Closed:   i.State == "closed",
+		Labels:   i.Labels,
+		Due:      parseGitLabDate(i.DueDate),
+		Created:  i.CreatedAt,
+		Updated:  i.UpdatedAt,
+		ClosedAt: i.ClosedAt,
+	}
+	if i.Milestone != nil {
+		m := i.Milestone.toMilestone()
+		is.Milestone = &m
+	}
+	return is
+}
+
+func (m gitlabMilestone) toMilestone() milestone {
+	return milestone{
+		ID:          m.ID,
+		Title:       m.Title,
+		Description: m.Description,
+		Closed:      m.State == "closed",
+		Due:         parseGitLabDate(m.DueDate),
+		Created:     m.CreatedAt,
+		Updated:     m.UpdatedAt,
+	}
+}
+
+// parseGitLabDate parses a "2006-01-02" date in local time, or returns nil
+func parseGitLabDate(value string) *time.Time {
+	if value == "" {
+		return nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, tz.Location())
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+// Verify interface compliance at compile time
+var _ backend.TaskManager = (*Backend)(nil)
+var _ backend.RawRequester = (*Backend)(nil)
@@ -0,0 +1,268 @@
+package issues
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"todoat/backend"
+)
+
+// mockForge records requests and answers with canned JSON per "METHOD path"
+type mockForge struct {
+	server    *httptest.Server
+	mu        sync.Mutex
+	responses map[string]string
+	bodies    map[string]map[string]interface{}
+	headers   http.Header
+}
+
+func newMockForge(t *testing.T, responses map[string]string) *mockForge {
+	m := &mockForge{responses: responses, bodies: map[string]map[string]interface{}{}}
+	m.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.headers = r.Header.Clone()
+
+		key := r.Method + " " + r.URL.EscapedPath()
+		if r.URL.RawQuery != "" {
+			key += "?" + r.URL.RawQuery
+		}
+		if data, _ := io.ReadAll(r.Body); len(data) > 0 {
+			var body map[string]interface{}
+			_ = json.Unmarshal(data, &body)
+			m.bodies[key] = body
+		}
+		resp, ok := m.responses[key]
+		if !ok {
+			http.Error(w, "unexpected request "+key, http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(resp))
+	}))
+	t.Cleanup(m.server.Close)
+	return m
+}
+
+func (m *mockForge) body(key string) map[string]interface{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.bodies[key]
+}
+
+func TestNewValidatesConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{"missing token", Config{Repos: []string{"o/r"}}},
+		{"missing repos", Config{Token: "t"}},
+		{"bad repo", Config{Token: "t", Repos: []string{"repo"}}},
+		{"bad provider", Config{Token: "t", Repos: []string{"o/r"}, Provider: "bitbucket"}},
+		{"bad milestones", Config{Token: "t", Repos: []string{"o/r"}, Milestones: "labels"}},
+	}
+	for _, tt := range tests {
+		if _, err := New(tt.cfg); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
+
+const githubIssuesJSON = `[
+  {"number": 1, "title": "Fix login", "body": "Steps", "state": "open",
+   "labels": [{"name": "bug"}, {"name": "auth"}],
+   "milestone": {"number": 3, "title": "v1.0", "state": "open", "due_on": "2026-06-01T07:00:00Z"},
+   "created_at": "2026-01-01T10:00:00Z", "updated_at": "2026-01-02T10:00:00Z"},
+  {"number": 2, "title": "Old idea", "state": "closed", "state_reason": "not_planned",
+   "created_at": "2026-01-01T10:00:00Z", "updated_at": "2026-01-03T10:00:00Z", "closed_at": "2026-01-03T10:00:00Z"},
+  {"number": 4, "title": "Shipped", "state": "closed", "state_reason": "completed",
+   "created_at": "2026-01-01T10:00:00Z", "updated_at": "2026-01-03T10:00:00Z", "closed_at": "2026-01-03T10:00:00Z"},
+  {"number": 5, "title": "A pull request", "state": "open", "pull_request": {}}
+]`
+
+const githubMilestonesJSON = `[{"number": 3, "title": "v1.0", "description": "First release", "state": "open", "due_on": "2026-06-01T07:00:00Z"}]`
+
+func TestGitHubGetTasks(t *testing.T) {
+	m := newMockForge(t, map[string]string{
+		"GET /repos/octo/app/issues?state=all&per_page=100&page=1&assignee=alice": githubIssuesJSON,
+		"GET /repos/octo/app/milestones?state=all&per_page=100&page=1":            githubMilestonesJSON,
+	})
+	be, err := New(Config{Token: "secret", BaseURL: m.server.URL, Repos: []string{"octo/app"}, Assignee: "alice"})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	lists, _ := be.GetLists(context.Background())
+	if len(lists) != 1 || lists[0].Name != "octo/app" {
+		t.Fatalf("expected one list per repository, got %+v", lists)
+	}
+
+	tasks, err := be.GetTasks(context.Background(), "octo/app")
+	if err != nil {
+		t.Fatalf("GetTasks: %v", err)
+	}
+	if m.headers.Get("Authorization") != "Bearer secret" {
+		t.Errorf("expected bearer token, got %q", m.headers.Get("Authorization"))
+	}
+	if len(tasks) != 4 {
+		t.Fatalf("expected milestone and 3 issues (pull request skipped), got %d: %+v", len(tasks), tasks)
+	}
+
+	byID := map[string]backend.Task{}
+	for _, task := range tasks {
+		byID[task.ID] = task
+	}
+	milestone := byID["octo/app/milestones/3"]
+	if milestone.Summary != "v1.0" || milestone.DueDate == nil || milestone.Status != backend.StatusNeedsAction {
+		t.Errorf("unexpected milestone task %+v", milestone)
+	}
+	first := byID["octo/app#1"]
+	if first.ParentID != "octo/app/milestones/3" || first.Categories != "bug,auth" || first.Description != "Steps" {
+		t.Errorf("unexpected issue task %+v", first)
+	}
+	if byID["octo/app#2"].Status != backend.StatusCancelled || byID["octo/app#4"].Status != backend.StatusCompleted {
+		t.Errorf("expected closed issues to be cancelled/completed, got %s and %s", byID["octo/app#2"].Status, byID["octo/app#4"].Status)
+	}
+	if byID["octo/app#4"].Completed == nil {
+		t.Error("expected completion time from closed_at")
+	}
+}
+
+func TestGitHubMilestonesAsDueDates(t *testing.T) {
+	m := newMockForge(t, map[string]string{
+		"GET /repos/octo/app/issues?state=all&per_page=100&page=1": githubIssuesJSON,
+	})
+	be, err := New(Config{Token: "t", BaseURL: m.server.URL, Repos: []string{"octo/app"}, Milestones: MilestonesAsDueDates})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	tasks, err := be.GetTasks(context.Background(), "octo/app")
+	if err != nil {
+		t.Fatalf("GetTasks: %v", err)
+	}
+	if len(tasks) != 3 {
+		t.Fatalf("expected no milestone tasks, got %d", len(tasks))
+	}
+	if tasks[0].ParentID != "" || tasks[0].DueDate == nil || tasks[0].DueDate.Day() != 1 {
+		t.Errorf("expected milestone due date on the issue, got %+v", tasks[0])
+	}
+
+	if _, err := be.CreateTask(context.Background(), "octo/app", &backend.Task{Summary: "x", ParentID: "octo/app/milestones/3"}); err == nil {
+		t.Error("expected error for a parent in due_dates mode")
+	}
+}
+
+func TestGitHubCreateAndUpdateTask(t *testing.T) {
+	issueJSON := `{"number": 7, "title": "New bug", "state": "open", "labels": [{"name": "bug"}],
+	  "milestone": {"number": 3, "title": "v1.0", "state": "open"}}`
+	m := newMockForge(t, map[string]string{
+		"POST /repos/octo/app/issues":    issueJSON,
+		"PATCH /repos/octo/app/issues/7": `{"number": 7, "title": "New bug", "state": "closed", "state_reason": "completed"}`,
+	})
+	be, err := New(Config{Token: "t", BaseURL: m.server.URL, Repos: []string{"octo/app"}, Assignee: "alice"})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	created, err := be.CreateTask(context.Background(), "octo/app", &backend.Task{
+		Summary:    "New bug",
+		Categories: "bug",
+		ParentID:   "octo/app/milestones/3",
+		Status:     backend.StatusNeedsAction,
+	})
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	if created.ID != "octo/app#7" || created.ParentID != "octo/app/milestones/3" {
+		t.Errorf("unexpected created task %+v", created)
+	}
+	body := m.body("POST /repos/octo/app/issues")
+	if body["title"] != "New bug" || body["milestone"] != float64(3) {
+		t.Errorf("unexpected create body %v", body)
+	}
+	if assignees, _ := body["assignees"].([]interface{}); len(assignees) != 1 || assignees[0] != "alice" {
+		t.Errorf("expected new issue assigned to alice, got %v", body["assignees"])
+	}
+
+	created.Status = backend.StatusCompleted
+	created.ParentID = ""
+	updated, err := be.UpdateTask(context.Background(), "octo/app", created)
+	if err != nil {
+		t.Fatalf("UpdateTask: %v", err)
+	}
+	if updated.Status != backend.StatusCompleted {
+		t.Errorf("expected completed task, got %s", updated.Status)
+	}
+	body = m.body("PATCH /repos/octo/app/issues/7")
+	if body["state"] != "closed" || body["state_reason"] != "completed" || body["milestone"] != nil {
+		t.Errorf("unexpected update body %v", body)
+	}
+
+	if _, err := be.UpdateTask(context.Background(), "octo/app", &backend.Task{ID: "octo/app/milestones/3", Summary: "v1.0"}); err == nil || !strings.Contains(err.Error(), "read-only") {
+		t.Errorf("expected read-only error for a milestone, got %v", err)
+	}
+	if _, err := be.CreateTask(context.Background(), "octo/app", &backend.Task{Summary: "x", ParentID: "octo/app#7"}); err == nil {
+		t.Error("expected error for an issue as parent")
+	}
+	if _, err := be.CreateList(context.Background(), "other"); err == nil {
+		t.Error("expected error creating a list")
+	}
+}
+
+func TestGitLabTasksRoundTrip(t *testing.T) {
+	m := newMockForge(t, map[string]string{
+		"GET /projects/group%2Fsub%2Fapp/issues?state=all&per_page=100&page=1&assignee_username=bob": `[
+		  {"iid": 1, "title": "Task", "description": "Body", "state": "opened", "labels": ["ops"], "due_date": "2026-04-02",
+		   "milestone": {"id": 42, "title": "Sprint 1", "state": "active", "due_date": "2026-04-10"}},
+		  {"iid": 2, "title": "Done", "state": "closed", "labels": [], "closed_at": "2026-03-01T10:00:00Z",
+		   "milestone": {"id": 42, "title": "Sprint 1", "state": "active", "due_date": "2026-04-10"}}
+		]`,
+		"GET /users?username=bob":                  `[{"id": 99}]`,
+		"POST /projects/group%2Fsub%2Fapp/issues":  `{"iid": 3, "title": "New", "state": "opened", "labels": ["ops"]}`,
+		"PUT /projects/group%2Fsub%2Fapp/issues/3": `{"iid": 3, "title": "New", "state": "closed", "labels": ["ops"]}`,
+	})
+	be, err := New(Config{Provider: ProviderGitLab, Token: "glpat", BaseURL: m.server.URL, Repos: []string{"group/sub/app"}, Assignee: "bob", Milestones: MilestonesAsDueDates})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	tasks, err := be.GetTasks(context.Background(), "group/sub/app")
+	if err != nil {
+		t.Fatalf("GetTasks: %v", err)
+	}
+	if m.headers.Get("PRIVATE-TOKEN") != "glpat" {
+		t.Errorf("expected PRIVATE-TOKEN header, got %v", m.headers)
+	}
+	if len(tasks) != 2 {
+		t.Fatalf("expected 2 tasks, got %+v", tasks)
+	}
+	if tasks[0].DueDate == nil || tasks[0].DueDate.Day() != 2 {
+		t.Errorf("expected the issue's own due date to win over the milestone's, got %v", tasks[0].DueDate)
+	}
+	if tasks[1].Status != backend.StatusCompleted || tasks[1].DueDate == nil || tasks[1].DueDate.Day() != 10 {
+		t.Errorf("expected completed task with milestone due date, got %+v", tasks[1])
+	}
+
+	created, err := be.CreateTask(context.Background(), "group/sub/app", &backend.Task{Summary: "New", Categories: "ops", Status: backend.StatusCompleted})
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	if created.ID != "group/sub/app#3" || created.Status != backend.StatusCompleted {
+		t.Errorf("expected the new issue to be closed after creation, got %+v", created)
+	}
+	body := m.body("POST /projects/group%2Fsub%2Fapp/issues")
+	if body["labels"] != "ops" {
+		t.Errorf("expected labels as a comma-separated string, got %v", body["labels"])
+	}
+	if ids, _ := body["assignee_ids"].([]interface{}); len(ids) != 1 || ids[0] != float64(99) {
+		t.Errorf("expected assignee id 99, got %v", body["assignee_ids"])
+	}
+	if m.body("PUT /projects/group%2Fsub%2Fapp/issues/3")["state_event"] != "close" {
+		t.Error("expected close state event")
+	}
+}
@@ -0,0 +1,48 @@
+package backend
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// LinkScheme is the URL scheme of task links, as in todoat://Work/<uid>
+const LinkScheme = "todoat"
+
+// TaskLinker is an optional interface for backends whose tasks have a page in
+// the backend's web app, which 'todoat open' launches in the browser.
+// Supported by the Todoist, Nextcloud and Microsoft To Do backends.
+type TaskLinker interface {
+	// TaskURL returns the web address of a task of the list listID
+	TaskURL(listID, taskID string) string
+}
+
+// FindTaskLinker returns tm, or the first backend it wraps, if its tasks have web pages
+func FindTaskLinker(tm TaskManager) (TaskLinker, bool) {
+	return findOptional[TaskLinker](tm)
+}
+
+// TaskLink returns the todoat:// link of a task, which 'todoat open' resolves
+// on any backend that has the list
+func TaskLink(listName, taskID string) string {
+	return LinkScheme + "://" + url.PathEscape(listName) + "/" + url.PathEscape(taskID)
+}
+
+// ParseTaskLink returns the list name and task ID of a todoat:// link
+func ParseTaskLink(link string) (listName, taskID string, err error) {
+	rest, ok := strings.CutPrefix(link, LinkScheme+"://")
+	if !ok {
+		return "", "", fmt.Errorf("not a %s:// link: %s", LinkScheme, link)
+	}
+	escapedList, escapedID, ok := strings.Cut(rest, "/")
+	if !ok || escapedList == "" || escapedID == "" {
+		return "", "", fmt.Errorf("invalid task link %s (expected %s://<list>/<uid>)", link, LinkScheme)
+	}
+	if listName, err = url.PathUnescape(escapedList); err != nil {
+		return "", "", fmt.Errorf("invalid task link %s: %w", link, err)
+	}
+	if taskID, err = url.PathUnescape(escapedID); err != nil {
+		return "", "", fmt.Errorf("invalid task link %s: %w", link, err)
+	}
+	return listName, taskID, nil
+}
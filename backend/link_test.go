@@ -0,0 +1,25 @@
+package backend_test
+
+import (
+	"testing"
+
+	"todoat/backend"
+)
+
+// TestTaskLinkRoundTrip verifies links escape list names and parse back
+func TestTaskLinkRoundTrip(t *testing.T) {
+	link := backend.TaskLink("Home / Garden", "abc-123")
+	if link != "todoat://Home%20%2F%20Garden/abc-123" {
+		t.Errorf("TaskLink = %q", link)
+	}
+	list, id, err := backend.ParseTaskLink(link)
+	if err != nil || list != "Home / Garden" || id != "abc-123" {
+		t.Errorf("ParseTaskLink = %q, %q, %v", list, id, err)
+	}
+
+	for _, bad := range []string{"https://example.com/x", "todoat://Work", "todoat:///abc", "todoat://Work/%zz"} {
+		if _, _, err := backend.ParseTaskLink(bad); err == nil {
+			t.Errorf("ParseTaskLink(%q) should fail", bad)
+		}
+	}
+}
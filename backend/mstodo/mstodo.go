@@ -8,10 +8,13 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"time"
 
 	"todoat/backend"
+	"todoat/internal/credentials"
+	"todoat/internal/utils"
 )
 
 const (
@@ -19,6 +22,8 @@ const (
 	DefaultBaseURL = "https://graph.microsoft.com"
 	// DefaultTokenURL is the Microsoft identity platform token endpoint
 	DefaultTokenURL = "https://login.microsoftonline.com/common/oauth2/v2.0/token"
+	// WebAppURL is the address of the Microsoft To Do web app
+	WebAppURL = "https://to-do.office.com"
 )
 
 // Config holds Microsoft To Do connection settings
@@ -29,6 +34,14 @@ type Config struct {
 	ClientSecret string
 	BaseURL      string // Override for testing
 	TokenURL     string // Override for testing
+
+	// Expiry of AccessToken, if known; the token is refreshed shortly before it
+	Expiry time.Time
+	// OnTokenRefresh is called after a refresh so the new token can be persisted
+	OnTokenRefresh func(token *credentials.OAuthToken)
+	// PriorityMap maps importance ("high", "normal", "low") to todoat
+	// priorities; nil uses DefaultPriorityMap
+	PriorityMap map[string][]int
 }
 
 // ConfigFromEnv creates a Config from environment variables
@@ -49,6 +62,8 @@ type Backend struct {
 	tokenURL     string
 	accessToken  string
 	refreshToken string
+	expiry       time.Time
+	priorities   *backend.PriorityMap
 }
 
 // New creates a new Microsoft To Do backend
@@ -67,6 +82,14 @@ func New(cfg Config) (*Backend, error) {
 		tokenURL = DefaultTokenURL
 	}
 
+	priorities := defaultPriorities
+	if cfg.PriorityMap != nil {
+		var err error
+		if priorities, err = backend.NewPriorityMap(cfg.PriorityMap, priorityValues); err != nil {
+			return nil, err
+		}
+	}
+
 	return &Backend{
 		config:       cfg,
 		client:       createHTTPClient(),
@@ -74,6 +97,8 @@ func New(cfg Config) (*Backend, error) {
 		tokenURL:     tokenURL,
 		accessToken:  cfg.AccessToken,
 		refreshToken: cfg.RefreshToken,
+		expiry:       cfg.Expiry,
+		priorities:   priorities,
 	}, nil
 }
 
@@ -94,62 +119,40 @@ func (b *Backend) Close() error {
 
 // refreshAccessToken refreshes the OAuth2 access token using the refresh token
 func (b *Backend) refreshAccessToken(ctx context.Context) error {
-	if b.refreshToken == "" {
-		return fmt.Errorf("no refresh token available")
-	}
-
-	data := map[string]string{
-		"grant_type":    "refresh_token",
-		"refresh_token": b.refreshToken,
-	}
-
-	if b.config.ClientID != "" {
-		data["client_id"] = b.config.ClientID
-	}
-	if b.config.ClientSecret != "" {
-		data["client_secret"] = b.config.ClientSecret
-	}
-
-	jsonBody, err := json.Marshal(data)
-	if err != nil {
-		return err
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.tokenURL, bytes.NewReader(jsonBody))
-	if err != nil {
-		return err
+	client := credentials.OAuthClient{
+		TokenURL:     b.tokenURL,
+		ClientID:     b.config.ClientID,
+		ClientSecret: b.config.ClientSecret,
+		HTTPClient:   b.client,
 	}
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := b.client.Do(req)
+	token, err := client.Refresh(ctx, b.refreshToken)
 	if err != nil {
 		return err
 	}
-	defer func() { _ = resp.Body.Close() }()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("token refresh failed: status %d", resp.StatusCode)
-	}
-
-	var tokenResp struct {
-		AccessToken  string `json:"access_token"`
-		RefreshToken string `json:"refresh_token"`
+	b.accessToken = token.AccessToken
+	b.refreshToken = token.RefreshToken
+	b.expiry = token.Expiry
+	if b.config.OnTokenRefresh != nil {
+		b.config.OnTokenRefresh(token)
 	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
-		return err
-	}
-
-	b.accessToken = tokenResp.AccessToken
-	if tokenResp.RefreshToken != "" {
-		b.refreshToken = tokenResp.RefreshToken
-	}
-
 	return nil
 }
 
+// RawRequest sends an authenticated request to path, relative to the API base URL
+func (b *Backend) RawRequest(ctx context.Context, method, path string) (*http.Response, error) {
+	return b.doRequest(ctx, method, path, nil)
+}
+
 // doRequest performs an authenticated Microsoft Graph API request
 func (b *Backend) doRequest(ctx context.Context, method, path string, body interface{}) (*http.Response, error) {
+	// Refresh an access token known to be expired before using it
+	if b.refreshToken != "" && !b.expiry.IsZero() && time.Now().Add(credentials.ExpiryLeeway).After(b.expiry) {
+		if err := b.refreshAccessToken(ctx); err != nil {
+			return nil, fmt.Errorf("token refresh failed: %w", err)
+		}
+	}
+
 	url := b.baseURL + path
 
 	var bodyReader io.Reader
@@ -414,6 +417,11 @@ func (b *Backend) SupportsTrash() bool { return false }
 // Task Operations
 // =============================================================================
 
+// TaskURL returns the page of a task in the Microsoft To Do web app
+func (b *Backend) TaskURL(listID, taskID string) string {
+	return WebAppURL + "/tasks/id/" + url.PathEscape(taskID) + "/details"
+}
+
 // GetTasks returns all tasks in a task list
 func (b *Backend) GetTasks(ctx context.Context, listID string) ([]backend.Task, error) {
 	resp, err := b.doRequest(ctx, http.MethodGet, "/v1.0/me/todo/lists/"+listID+"/tasks", nil)
@@ -447,7 +455,7 @@ func (b *Backend) GetTasks(ctx context.Context, listID string) ([]backend.Task,
 			ID:       item.ID,
 			Summary:  item.Title,
 			Status:   msToBackendStatus(item.Status),
-			Priority: importanceToPriority(item.Importance),
+			Priority: b.importancePriority(item.Importance),
 			ListID:   listID,
 			Modified: modified,
 			Created:  created,
@@ -458,7 +466,7 @@ func (b *Backend) GetTasks(ctx context.Context, listID string) ([]backend.Task,
 		}
 
 		if item.DueDateTime != nil && item.DueDateTime.DateTime != "" {
-			dueDate := parseMSDateTime(item.DueDateTime)
+			dueDate := parseMSDueDate(item.DueDateTime)
 			if dueDate != nil {
 				tasks[i].DueDate = dueDate
 			}
@@ -503,7 +511,7 @@ func (b *Backend) GetTask(ctx context.Context, listID, taskID string) (*backend.
 		ID:       item.ID,
 		Summary:  item.Title,
 		Status:   msToBackendStatus(item.Status),
-		Priority: importanceToPriority(item.Importance),
+		Priority: b.importancePriority(item.Importance),
 		ListID:   listID,
 		Modified: modified,
 		Created:  created,
@@ -514,7 +522,7 @@ func (b *Backend) GetTask(ctx context.Context, listID, taskID string) (*backend.
 	}
 
 	if item.DueDateTime != nil && item.DueDateTime.DateTime != "" {
-		task.DueDate = parseMSDateTime(item.DueDateTime)
+		task.DueDate = parseMSDueDate(item.DueDateTime)
 	}
 
 	if item.CompletedDateTime != nil && item.CompletedDateTime.DateTime != "" {
@@ -529,7 +537,7 @@ func (b *Backend) CreateTask(ctx context.Context, listID string, task *backend.T
 	body := map[string]interface{}{
 		"title":      task.Summary,
 		"status":     backendToMSStatus(task.Status),
-		"importance": priorityToImportance(task.Priority),
+		"importance": b.importance(task.Priority),
 	}
 
 	if task.Description != "" {
@@ -541,7 +549,7 @@ func (b *Backend) CreateTask(ctx context.Context, listID string, task *backend.T
 
 	if task.DueDate != nil {
 		body["dueDateTime"] = map[string]string{
-			"dateTime": task.DueDate.Format("2006-01-02T15:04:05.0000000"),
+			"dateTime": utils.DateAsUTC(*task.DueDate).Format("2006-01-02T15:04:05.0000000"),
 			"timeZone": "UTC",
 		}
 	}
@@ -568,7 +576,7 @@ func (b *Backend) CreateTask(ctx context.Context, listID string, task *backend.T
 		ID:       item.ID,
 		Summary:  item.Title,
 		Status:   msToBackendStatus(item.Status),
-		Priority: importanceToPriority(item.Importance),
+		Priority: b.importancePriority(item.Importance),
 		ListID:   listID,
 		Modified: modified,
 		Created:  created,
@@ -579,7 +587,7 @@ func (b *Backend) CreateTask(ctx context.Context, listID string, task *backend.T
 	}
 
 	if item.DueDateTime != nil && item.DueDateTime.DateTime != "" {
-		createdTask.DueDate = parseMSDateTime(item.DueDateTime)
+		createdTask.DueDate = parseMSDueDate(item.DueDateTime)
 	}
 
 	return createdTask, nil
@@ -590,7 +598,7 @@ func (b *Backend) UpdateTask(ctx context.Context, listID string, task *backend.T
 	body := map[string]interface{}{
 		"title":      task.Summary,
 		"status":     backendToMSStatus(task.Status),
-		"importance": priorityToImportance(task.Priority),
+		"importance": b.importance(task.Priority),
 	}
 
 	if task.Description != "" {
@@ -602,12 +610,52 @@ func (b *Backend) UpdateTask(ctx context.Context, listID string, task *backend.T
 
 	if task.DueDate != nil {
 		body["dueDateTime"] = map[string]string{
-			"dateTime": task.DueDate.Format("2006-01-02T15:04:05.0000000"),
+			"dateTime": utils.DateAsUTC(*task.DueDate).Format("2006-01-02T15:04:05.0000000"),
 			"timeZone": "UTC",
 		}
 	}
 
-	resp, err := b.doRequest(ctx, http.MethodPatch, "/v1.0/me/todo/lists/"+listID+"/tasks/"+task.ID, body)
+	return b.sendTaskPatch(ctx, listID, task.ID, body)
+}
+
+// PatchTask updates only the given fields of a task (backend.FieldPatcher).
+// Fields Microsoft To Do does not store are ignored.
+func (b *Backend) PatchTask(ctx context.Context, listID string, task *backend.Task, fields []string) (*backend.Task, error) {
+	body := map[string]interface{}{}
+	for _, field := range fields {
+		switch field {
+		case backend.FieldSummary:
+			body["title"] = task.Summary
+		case backend.FieldStatus:
+			body["status"] = backendToMSStatus(task.Status)
+		case backend.FieldPriority:
+			body["importance"] = b.importance(task.Priority)
+		case backend.FieldDescription:
+			body["body"] = map[string]string{
+				"content":     task.Description,
+				"contentType": "text",
+			}
+		case backend.FieldDueDate:
+			if task.DueDate == nil {
+				body["dueDateTime"] = nil
+			} else {
+				body["dueDateTime"] = map[string]string{
+					"dateTime": utils.DateAsUTC(*task.DueDate).Format("2006-01-02T15:04:05.0000000"),
+					"timeZone": "UTC",
+				}
+			}
+		}
+	}
+	if len(body) == 0 {
+		return task, nil
+	}
+
+	return b.sendTaskPatch(ctx, listID, task.ID, body)
+}
+
+// sendTaskPatch sends a PATCH request for a task and returns the updated task
+func (b *Backend) sendTaskPatch(ctx context.Context, listID, taskID string, body map[string]interface{}) (*backend.Task, error) {
+	resp, err := b.doRequest(ctx, http.MethodPatch, "/v1.0/me/todo/lists/"+listID+"/tasks/"+taskID, body)
 	if err != nil {
 		return nil, err
 	}
@@ -628,7 +676,7 @@ func (b *Backend) UpdateTask(ctx context.Context, listID string, task *backend.T
 		ID:       item.ID,
 		Summary:  item.Title,
 		Status:   msToBackendStatus(item.Status),
-		Priority: importanceToPriority(item.Importance),
+		Priority: b.importancePriority(item.Importance),
 		ListID:   listID,
 		Modified: modified,
 	}
@@ -638,7 +686,7 @@ func (b *Backend) UpdateTask(ctx context.Context, listID string, task *backend.T
 	}
 
 	if item.DueDateTime != nil && item.DueDateTime.DateTime != "" {
-		updated.DueDate = parseMSDateTime(item.DueDateTime)
+		updated.DueDate = parseMSDueDate(item.DueDateTime)
 	}
 
 	if item.CompletedDateTime != nil && item.CompletedDateTime.DateTime != "" {
@@ -679,9 +727,9 @@ func msToBackendStatus(status string) backend.TaskStatus {
 	}
 }
 
-// backendToMSStatus converts backend status to Microsoft To Do status
+// backendToMSStatus converts backend status to Microsoft To Do status; custom statuses use their base
 func backendToMSStatus(status backend.TaskStatus) string {
-	switch status {
+	switch status.Base() {
 	case backend.StatusCompleted, backend.StatusCancelled:
 		return "completed"
 	case backend.StatusInProgress:
@@ -691,28 +739,54 @@ func backendToMSStatus(status backend.TaskStatus) string {
 	}
 }
 
-// importanceToPriority converts Microsoft importance (low/normal/high) to priority (1-9)
+// DefaultPriorityMap maps Microsoft importance to todoat priorities
+var DefaultPriorityMap = map[string][]int{
+	"high":   {1, 2, 3},
+	"normal": {5, 0, 4, 6},
+	"low":    {9, 7, 8},
+}
+
+// priorityValues are the Microsoft importance values
+var priorityValues = []string{"low", "normal", "high"}
+
+var defaultPriorities = backend.MustPriorityMap(DefaultPriorityMap, priorityValues)
+
+// PriorityMap returns the mapping between todoat priorities and importance
+func (b *Backend) PriorityMap() *backend.PriorityMap {
+	return b.priorities
+}
+
+// importancePriority converts Microsoft importance to a todoat priority
+func (b *Backend) importancePriority(importance string) int {
+	return fromImportance(b.priorities, importance)
+}
+
+// importance converts a todoat priority to Microsoft importance
+func (b *Backend) importance(priority int) string {
+	return b.priorities.ToNative(priority)
+}
+
+// importanceToPriority converts Microsoft importance (low/normal/high) to priority (1-9) using DefaultPriorityMap
 func importanceToPriority(importance string) int {
-	switch importance {
-	case "high":
-		return 1
-	case "low":
-		return 9
-	default: // normal
-		return 5
-	}
+	return fromImportance(defaultPriorities, importance)
 }
 
-// priorityToImportance converts priority (1-9) to Microsoft importance (low/normal/high)
+// priorityToImportance converts priority (1-9) to Microsoft importance (low/normal/high) using DefaultPriorityMap
 func priorityToImportance(priority int) string {
-	switch {
-	case priority >= 1 && priority <= 3:
-		return "high"
-	case priority >= 7 && priority <= 9:
-		return "low"
-	default:
-		return "normal"
+	return defaultPriorities.ToNative(priority)
+}
+
+// fromImportance reads an importance value; values missing from m fall back
+// to the default mapping, and unknown values count as normal
+func fromImportance(m *backend.PriorityMap, importance string) int {
+	if p, ok := m.FromNative(importance); ok {
+		return p
+	}
+	if p, ok := defaultPriorities.FromNative(importance); ok {
+		return p
 	}
+	p, _ := defaultPriorities.FromNative("normal")
+	return p
 }
 
 // parseMSDateTime parses Microsoft dateTime format to Go time.Time
@@ -739,5 +813,17 @@ func parseMSDateTime(dt *msDateTime) *time.Time {
 	return nil
 }
 
+// parseMSDueDate parses a Microsoft To Do due date. To Do only keeps the date,
+// sent as midnight in UTC, so it becomes midnight of that date in the local zone.
+func parseMSDueDate(dt *msDateTime) *time.Time {
+	parsed := parseMSDateTime(dt)
+	if parsed == nil {
+		return nil
+	}
+	local := utils.DateAsLocal(*parsed)
+	return &local
+}
+
 // Verify interface compliance at compile time
 var _ backend.TaskManager = (*Backend)(nil)
+var _ backend.TaskLinker = (*Backend)(nil)
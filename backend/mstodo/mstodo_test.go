@@ -676,6 +676,40 @@ func TestMSTodoUpdateTask(t *testing.T) {
 	}
 }
 
+// TestMSTodoPatchTask - sync sends only the changed fields in the PATCH request
+func TestMSTodoPatchTask(t *testing.T) {
+	server := newMockMSGraphServer("test-access-token", "test-refresh-token")
+	defer server.Close()
+
+	server.AddTaskList("list-1", "MyList")
+	server.AddTask("list-1", "task-1", "Existing Task", "notStarted", "high", nil)
+
+	be, err := New(Config{
+		AccessToken:  "test-access-token",
+		RefreshToken: "test-refresh-token",
+		BaseURL:      server.URL(),
+		TokenURL:     server.URL() + "/token",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create backend: %v", err)
+	}
+	defer func() { _ = be.Close() }()
+
+	ctx := context.Background()
+	task := &backend.Task{ID: "task-1", Summary: "Existing Task", Status: backend.StatusCompleted, Priority: 9}
+	updated, err := backend.PatchTask(ctx, be, "list-1", task, []string{backend.FieldStatus})
+	if err != nil {
+		t.Fatalf("PatchTask failed: %v", err)
+	}
+
+	if updated.Status != backend.StatusCompleted {
+		t.Errorf("Expected status COMPLETED, got %s", updated.Status)
+	}
+	if got := server.tasks["list-1"]["task-1"].Importance; got != "high" {
+		t.Errorf("Importance was not in the changed fields but became %q", got)
+	}
+}
+
 // TestMSTodoDeleteTask - todoat --backend=mstodo MyList delete "Task" removes task
 func TestMSTodoDeleteTask(t *testing.T) {
 	server := newMockMSGraphServer("test-access-token", "test-refresh-token")
@@ -11,13 +11,16 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"path"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"todoat/backend"
+	"todoat/internal/ical"
 	"todoat/internal/utils"
 )
 
@@ -96,7 +99,7 @@ func New(cfg Config) (*Backend, error) {
 // createHTTPClient creates an HTTP client with proper connection pooling
 func createHTTPClient(cfg Config) *http.Client {
 	if cfg.InsecureSkipVerify {
-		utils.Warnf("TLS certificate verification is disabled (insecure_skip_verify: true). Connections are vulnerable to man-in-the-middle attacks.")
+		utils.Component("nextcloud").Warn("TLS certificate verification is disabled (insecure_skip_verify: true). Connections are vulnerable to man-in-the-middle attacks.")
 	}
 
 	transport := &http.Transport{
@@ -123,6 +126,12 @@ func (b *Backend) Close() error {
 	return nil
 }
 
+// RawRequest sends an authenticated request to path, relative to the user's calendar home
+// (remote.php/dav/calendars/<user>/)
+func (b *Backend) RawRequest(ctx context.Context, method, path string) (*http.Response, error) {
+	return b.doRequest(ctx, method, b.baseURL+strings.TrimPrefix(path, "/"), nil)
+}
+
 // doRequest performs an authenticated CalDAV request
 func (b *Backend) doRequest(ctx context.Context, method, url string, body []byte) (*http.Response, error) {
 	var bodyReader io.Reader
@@ -146,12 +155,14 @@ func (b *Backend) doRequest(ctx context.Context, method, url string, body []byte
 // Only returns calendars that support VTODO components (task lists)
 func (b *Backend) GetLists(ctx context.Context) ([]backend.List, error) {
 	propfindBody := `<?xml version="1.0" encoding="UTF-8"?>
-<d:propfind xmlns:d="DAV:" xmlns:cs="http://calendarserver.org/ns/" xmlns:cal="urn:ietf:params:xml:ns:caldav">
+<d:propfind xmlns:d="DAV:" xmlns:cs="http://calendarserver.org/ns/" xmlns:cal="urn:ietf:params:xml:ns:caldav" xmlns:oc="http://owncloud.org/ns" xmlns:nc="http://nextcloud.com/ns">
   <d:prop>
     <d:displayname/>
     <d:resourcetype/>
     <cs:getctag/>
     <cal:supported-calendar-component-set/>
+    <oc:owner-principal/>
+    <nc:owner-displayname/>
   </d:prop>
 </d:propfind>`
 
@@ -210,6 +221,9 @@ func (b *Backend) UpdateList(ctx context.Context, list *backend.List) (*backend.
 	return nil, fmt.Errorf("updating calendars is not supported via CalDAV")
 }
 
+// AccountName returns the Nextcloud user the backend is logged in as
+func (b *Backend) AccountName() string { return b.username }
+
 // SupportsTrash returns false because CalDAV does not support soft-delete.
 func (b *Backend) SupportsTrash() bool { return false }
 
@@ -239,6 +253,19 @@ func (b *Backend) PurgeList(ctx context.Context, listID string) error {
 	return fmt.Errorf("purging calendars is not supported via CalDAV")
 }
 
+// TaskURL returns the page of a task in the Nextcloud Tasks app, on the server
+// of the CalDAV base URL
+func (b *Backend) TaskURL(listID, taskID string) string {
+	server, _, _ := strings.Cut(b.baseURL, "/remote.php/")
+	return server + "/apps/tasks/#/calendars/" + url.PathEscape(listID) + "/tasks/" + url.PathEscape(taskID) + ".ics"
+}
+
+// ReadsConcurrently reports that calendars can be read in parallel: requests
+// share no state beyond the HTTP client
+func (b *Backend) ReadsConcurrently() bool {
+	return true
+}
+
 // GetTasks returns all tasks (VTODOs) in a calendar
 func (b *Backend) GetTasks(ctx context.Context, listID string) ([]backend.Task, error) {
 	calendarURL := b.baseURL + listID + "/"
@@ -298,6 +325,15 @@ func (b *Backend) CreateTask(ctx context.Context, listID string, task *backend.T
 		uid = uuid.New().String()
 	}
 
+	// Record who created the task so collaborators' syncs can tell it apart
+	metadata := make(map[string]string, len(task.Metadata)+1)
+	for key, value := range task.Metadata {
+		metadata[key] = value
+	}
+	if metadata[backend.CreatedByKey] == "" {
+		metadata[backend.CreatedByKey] = b.username
+	}
+
 	newTask := &backend.Task{
 		ID:          uid,
 		Summary:     task.Summary,
@@ -311,6 +347,7 @@ func (b *Backend) CreateTask(ctx context.Context, listID string, task *backend.T
 		Created:     time.Now().UTC(),
 		Modified:    time.Now().UTC(),
 		ParentID:    task.ParentID,
+		Metadata:    metadata,
 	}
 
 	if newTask.Status == "" {
@@ -775,9 +812,9 @@ var _ backend.ListPublisher = (*Backend)(nil)
 // Status Conversion Functions
 // =============================================================================
 
-// statusToCalDAV converts internal status to CalDAV status
+// statusToCalDAV converts internal status to CalDAV status; custom statuses use their base
 func statusToCalDAV(status backend.TaskStatus) string {
-	switch status {
+	switch status.Base() {
 	case backend.StatusNeedsAction:
 		return "NEEDS-ACTION"
 	case backend.StatusCompleted:
@@ -849,19 +886,9 @@ func parseVTODO(vtodo string) (*backend.Task, error) {
 		task.Categories = categories
 	}
 
-	// Extract DUE
-	if due := extractProperty(vtodo, "DUE"); due != "" {
-		if t, err := parseCalendarDate(due); err == nil {
-			task.DueDate = &t
-		}
-	}
-
-	// Extract DTSTART
-	if dtstart := extractProperty(vtodo, "DTSTART"); dtstart != "" {
-		if t, err := parseCalendarDate(dtstart); err == nil {
-			task.StartDate = &t
-		}
-	}
+	// Extract DUE and DTSTART, honoring VALUE=DATE and TZID
+	task.DueDate = extractTimeProperty(vtodo, "DUE")
+	task.StartDate = extractTimeProperty(vtodo, "DTSTART")
 
 	// Extract CREATED
 	if created := extractProperty(vtodo, "CREATED"); created != "" {
@@ -890,6 +917,8 @@ func parseVTODO(vtodo string) (*backend.Task, error) {
 		task.ParentID = parentUID
 	}
 
+	task.Metadata = extractMetadata(vtodo)
+
 	return task, nil
 }
 
@@ -904,6 +933,25 @@ func extractProperty(content, property string) string {
 	return ""
 }
 
+// extractTimeProperty extracts a DATE or DATE-TIME property with its parameters:
+// dates and floating times are read in local time and TZID values in their zone
+func extractTimeProperty(content, property string) *time.Time {
+	pattern := regexp.MustCompile(`(?m)^` + property + `(?:;[^:]*)?:.*$`)
+	line := strings.TrimSpace(pattern.FindString(content))
+	if line == "" {
+		return nil
+	}
+	prop, err := ical.ParseProperty(line)
+	if err != nil {
+		return nil
+	}
+	t, _, err := ical.ParseTime(prop)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
 // extractRelatedToParent extracts the parent UID from RELATED-TO property with RELTYPE=PARENT.
 // iCalendar format: RELATED-TO;RELTYPE=PARENT:<parent-uid>
 // Also handles implicit PARENT type (when RELTYPE is not specified, PARENT is the default).
@@ -927,6 +975,26 @@ func extractRelatedToParent(content string) string {
 	return ""
 }
 
+// metadataPattern matches the X-TODOAT-META extended properties that carry custom metadata
+var metadataPattern = regexp.MustCompile(`(?m)^X-TODOAT-META(?:;[^:]*)?:(.*)$`)
+
+// extractMetadata extracts custom metadata from X-TODOAT-META:key=value properties.
+// Returns nil when there are none, so the local cache keeps its stored metadata.
+func extractMetadata(content string) map[string]string {
+	var metadata map[string]string
+	for _, match := range metadataPattern.FindAllStringSubmatch(content, -1) {
+		key, value, ok := strings.Cut(ical.Unescape(strings.TrimSpace(match[1])), "=")
+		if !ok || key == "" {
+			continue
+		}
+		if metadata == nil {
+			metadata = make(map[string]string)
+		}
+		metadata[key] = value
+	}
+	return metadata
+}
+
 // parseCalendarDate parses various iCalendar date formats
 func parseCalendarDate(dateStr string) (time.Time, error) {
 	// Try different formats
@@ -945,6 +1013,16 @@ func parseCalendarDate(dateStr string) (time.Time, error) {
 	return time.Time{}, fmt.Errorf("unable to parse date: %s", dateStr)
 }
 
+// formatTimeProperty formats a DUE or DTSTART line: dates without a time of day
+// as VALUE=DATE, timed values in UTC
+func formatTimeProperty(name string, t time.Time) string {
+	value, params := ical.FormatTime(t)
+	for _, param := range params {
+		name += ";" + param
+	}
+	return name + ":" + value
+}
+
 // generateVTODO generates a VTODO iCalendar component from a Task
 func generateVTODO(task *backend.Task) string {
 	now := time.Now().UTC()
@@ -977,11 +1055,11 @@ func generateVTODO(task *backend.Task) string {
 	}
 
 	if task.DueDate != nil {
-		lines = append(lines, fmt.Sprintf("DUE:%s", task.DueDate.UTC().Format(iCalendarDateFormat)))
+		lines = append(lines, formatTimeProperty("DUE", *task.DueDate))
 	}
 
 	if task.StartDate != nil {
-		lines = append(lines, fmt.Sprintf("DTSTART:%s", task.StartDate.UTC().Format(iCalendarDateFormat)))
+		lines = append(lines, formatTimeProperty("DTSTART", *task.StartDate))
 	}
 
 	if !task.Created.IsZero() {
@@ -1005,6 +1083,16 @@ func generateVTODO(task *backend.Task) string {
 		lines = append(lines, fmt.Sprintf("RELATED-TO;RELTYPE=PARENT:%s", task.ParentID))
 	}
 
+	// Custom metadata as extended properties, which other CalDAV clients preserve
+	metaKeys := make([]string, 0, len(task.Metadata))
+	for key := range task.Metadata {
+		metaKeys = append(metaKeys, key)
+	}
+	sort.Strings(metaKeys)
+	for _, key := range metaKeys {
+		lines = append(lines, fmt.Sprintf("X-TODOAT-META:%s", ical.Escape(key+"="+task.Metadata[key])))
+	}
+
 	lines = append(lines, "END:VTODO")
 	lines = append(lines, "END:VCALENDAR")
 
@@ -1034,6 +1122,8 @@ type Prop struct {
 	} `xml:"resourcetype"`
 	CTag                          string                        `xml:"getctag"`
 	SupportedCalendarComponentSet SupportedCalendarComponentSet `xml:"supported-calendar-component-set"`
+	OwnerPrincipal                string                        `xml:"owner-principal"`
+	OwnerDisplayName              string                        `xml:"owner-displayname"`
 }
 
 // PropStat represents a property status
@@ -1078,6 +1168,7 @@ func parseCalendarList(xmlBody, username string) ([]backend.List, error) {
 						ID:       calID,
 						Name:     ps.Prop.DisplayName,
 						Modified: time.Now(), // CalDAV doesn't provide modified time for collections
+						SharedBy: sharedBy(ps.Prop.OwnerPrincipal, ps.Prop.OwnerDisplayName, username),
 					})
 				}
 			}
@@ -1110,6 +1201,8 @@ func parseCalendarListRegex(xmlBody, username string) ([]backend.List, error) {
 	responsePattern := regexp.MustCompile(`(?s)<d:response>(.*?)</d:response>`)
 	hrefPattern := regexp.MustCompile(`<d:href>([^<]+)</d:href>`)
 	displayPattern := regexp.MustCompile(`<d:displayname>([^<]+)</d:displayname>`)
+	ownerPattern := regexp.MustCompile(`<oc:owner-principal>([^<]+)</oc:owner-principal>`)
+	ownerNamePattern := regexp.MustCompile(`<nc:owner-displayname>([^<]+)</nc:owner-displayname>`)
 	vtodoPattern := regexp.MustCompile(`<cal:comp\s+name="VTODO"\s*/?>`)
 
 	responses := responsePattern.FindAllStringSubmatch(xmlBody, -1)
@@ -1143,16 +1236,38 @@ func parseCalendarListRegex(xmlBody, username string) ([]backend.List, error) {
 			continue // Skip calendars that don't support VTODO
 		}
 
+		var owner, ownerName string
+		if m := ownerPattern.FindStringSubmatch(respBody); len(m) == 2 {
+			owner = m[1]
+		}
+		if m := ownerNamePattern.FindStringSubmatch(respBody); len(m) == 2 {
+			ownerName = m[1]
+		}
+
 		lists = append(lists, backend.List{
 			ID:       calID,
 			Name:     displayMatch[1],
 			Modified: time.Now(),
+			SharedBy: sharedBy(owner, ownerName, username),
 		})
 	}
 
 	return lists, nil
 }
 
+// sharedBy returns who shared a calendar with username, from its owner principal
+// (e.g. "principals/users/alice") and display name; empty for the user's own calendars
+func sharedBy(ownerPrincipal, ownerDisplayName, username string) string {
+	owner := path.Base(strings.TrimSuffix(strings.TrimSpace(ownerPrincipal), "/"))
+	if ownerPrincipal == "" || owner == "" || owner == "." || owner == "/" || owner == username {
+		return ""
+	}
+	if ownerDisplayName != "" {
+		return ownerDisplayName
+	}
+	return owner
+}
+
 // extractCalendarID extracts the calendar ID from a CalDAV href
 func extractCalendarID(href, username string) string {
 	// href format: /remote.php/dav/calendars/username/calendarid/
@@ -1200,3 +1315,4 @@ func parseTaskList(xmlBody, listID string) ([]backend.Task, error) {
 
 // Verify interface compliance at compile time
 var _ backend.TaskManager = (*Backend)(nil)
+var _ backend.TaskLinker = (*Backend)(nil)
@@ -1179,6 +1179,33 @@ func TestGenerateVTODO(t *testing.T) {
 	}
 }
 
+// TestVTODOMetadataRoundTrip verifies custom metadata survives as X-TODOAT-META properties
+func TestVTODOMetadataRoundTrip(t *testing.T) {
+	task := &backend.Task{
+		ID:       "meta-uid",
+		Summary:  "Invoice",
+		Metadata: map[string]string{"client": "acme, inc", "estimate": "3h"},
+	}
+
+	vtodo := generateVTODO(task)
+	if !strings.Contains(vtodo, "X-TODOAT-META:client=acme\\, inc") {
+		t.Errorf("VTODO should contain escaped metadata, got:\n%s", vtodo)
+	}
+
+	parsed, err := parseVTODO(vtodo)
+	if err != nil {
+		t.Fatalf("parseVTODO error: %v", err)
+	}
+	if parsed.Metadata["client"] != "acme, inc" || parsed.Metadata["estimate"] != "3h" {
+		t.Errorf("expected metadata to round-trip, got %v", parsed.Metadata)
+	}
+
+	plain, _ := parseVTODO(generateVTODO(&backend.Task{ID: "plain", Summary: "Plain"}))
+	if plain.Metadata != nil {
+		t.Errorf("expected nil metadata without X-TODOAT-META, got %v", plain.Metadata)
+	}
+}
+
 // Helper function tests
 func TestConfigFromEnv(t *testing.T) {
 	// Set environment variables (auto-restored after test)
@@ -1671,3 +1698,81 @@ func TestIssue037CreateTaskPreservesParentID(t *testing.T) {
 		t.Errorf("VTODO should contain parent UID 'parent-task-uid-456', got:\n%s", storedVTODO)
 	}
 }
+
+// TestSharedCalendarOwner verifies calendars shared by another user report their owner
+func TestSharedCalendarOwner(t *testing.T) {
+	body := `<?xml version="1.0"?>
+<d:multistatus xmlns:d="DAV:" xmlns:cal="urn:ietf:params:xml:ns:caldav" xmlns:oc="http://owncloud.org/ns" xmlns:nc="http://nextcloud.com/ns">
+  <d:response>
+    <d:href>/remote.php/dav/calendars/bob/personal/</d:href>
+    <d:propstat><d:prop><d:displayname>Personal</d:displayname>
+      <oc:owner-principal>principals/users/bob</oc:owner-principal></d:prop>
+      <d:status>HTTP/1.1 200 OK</d:status></d:propstat>
+  </d:response>
+  <d:response>
+    <d:href>/remote.php/dav/calendars/bob/household_shared_by_alice/</d:href>
+    <d:propstat><d:prop><d:displayname>Household</d:displayname>
+      <oc:owner-principal>principals/users/alice</oc:owner-principal>
+      <nc:owner-displayname>Alice Smith</nc:owner-displayname></d:prop>
+      <d:status>HTTP/1.1 200 OK</d:status></d:propstat>
+  </d:response>
+</d:multistatus>`
+
+	lists, err := parseCalendarList(body, "bob")
+	if err != nil || len(lists) != 2 {
+		t.Fatalf("parseCalendarList = %v, %v; want 2 lists", lists, err)
+	}
+	if lists[0].SharedBy != "" {
+		t.Errorf("own calendar SharedBy = %q, want empty", lists[0].SharedBy)
+	}
+	if lists[1].SharedBy != "Alice Smith" {
+		t.Errorf("shared calendar SharedBy = %q, want Alice Smith", lists[1].SharedBy)
+	}
+	if got := sharedBy("principals/users/carol/", "", "bob"); got != "carol" {
+		t.Errorf("sharedBy without display name = %q, want carol", got)
+	}
+}
+
+// TestCreateTaskRecordsCreator verifies new tasks carry the creating account in their metadata
+func TestCreateTaskRecordsCreator(t *testing.T) {
+	server := newMockCalDAVServer("testuser", "testpass")
+	defer server.Close()
+	server.AddCalendar("Tasks")
+
+	be, err := New(Config{Host: strings.TrimPrefix(server.URL(), "http://"), Username: "testuser", Password: "testpass", AllowHTTP: true})
+	if err != nil {
+		t.Fatalf("Failed to create backend: %v", err)
+	}
+	defer func() { _ = be.Close() }()
+
+	ctx := context.Background()
+	if _, err := be.CreateTask(ctx, "Tasks", &backend.Task{Summary: "Mine"}); err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	if _, err := be.CreateTask(ctx, "Tasks", &backend.Task{Summary: "Synced", Metadata: map[string]string{backend.CreatedByKey: "alice"}}); err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	tasks, err := be.GetTasks(ctx, "Tasks")
+	if err != nil {
+		t.Fatalf("GetTasks failed: %v", err)
+	}
+	creators := map[string]string{}
+	for _, task := range tasks {
+		creators[task.Summary] = task.Metadata[backend.CreatedByKey]
+	}
+	if creators["Mine"] != "testuser" || creators["Synced"] != "alice" {
+		t.Errorf("creators = %v, want Mine=testuser and Synced=alice", creators)
+	}
+}
+
+// TestTaskURL verifies task links point at the Tasks app of the configured server
+func TestTaskURL(t *testing.T) {
+	be, err := New(Config{Host: "https://cloud.example.com/", Username: "alice", Password: "secret"})
+	if err != nil {
+		t.Fatalf("Failed to create backend: %v", err)
+	}
+	want := "https://cloud.example.com/apps/tasks/#/calendars/personal/tasks/abc-123.ics"
+	if got := be.TaskURL("personal", "abc-123"); got != want {
+		t.Errorf("TaskURL = %q, want %q", got, want)
+	}
+}
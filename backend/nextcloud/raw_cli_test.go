@@ -0,0 +1,64 @@
+package nextcloud_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"todoat/internal/testutil"
+)
+
+// TestBackendRawCLI verifies 'backend raw' sends an authenticated read-only request
+// relative to the calendar home and prints the response as-is
+func TestBackendRawCLI(t *testing.T) {
+	var gotMethod, gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "alice" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		gotMethod, gotPath = r.Method, r.URL.Path
+		w.WriteHeader(http.StatusMultiStatus)
+		_, _ = fmt.Fprint(w, `<d:multistatus xmlns:d="DAV:"><d:response><d:href>/tasks/</d:href></d:response></d:multistatus>`)
+	}))
+	defer server.Close()
+
+	cli := testutil.NewCLITestWithConfig(t)
+	cli.SetFullConfig(fmt.Sprintf(`backends:
+  nc-raw:
+    type: nextcloud
+    host: %s
+    username: alice
+    password: secret
+    allow_http: true
+`, strings.TrimPrefix(server.URL, "http://")))
+
+	// The debugging commands require explicit confirmation
+	_, stderr := cli.ExecuteAndFail("backend", "raw", "nc-raw", "PROPFIND", "/tasks/")
+	testutil.AssertContains(t, stderr, "--yes-i-know")
+
+	stdout := cli.MustExecute("backend", "raw", "nc-raw", "propfind", "/tasks/", "--yes-i-know")
+	testutil.AssertContains(t, stdout, "207 Multi-Status")
+	testutil.AssertContains(t, stdout, "<d:href>/tasks/</d:href>")
+	if gotMethod != "PROPFIND" || gotPath != "/remote.php/dav/calendars/alice/tasks/" {
+		t.Errorf("expected PROPFIND on the calendar home, got %s %s", gotMethod, gotPath)
+	}
+
+	stdout = cli.MustExecute("--json", "backend", "raw", "nc-raw", "GET", "tasks/", "--yes-i-know")
+	testutil.AssertContains(t, stdout, `"status": 207`)
+	testutil.AssertContains(t, stdout, `"path": "/tasks/"`)
+
+	// Methods that modify remote data are refused before any request is sent
+	gotMethod = ""
+	_, stderr = cli.ExecuteAndFail("backend", "raw", "nc-raw", "DELETE", "/tasks/", "--yes-i-know")
+	testutil.AssertContains(t, stderr, "only sends read-only requests")
+	if gotMethod != "" {
+		t.Errorf("expected no request for DELETE, got %s", gotMethod)
+	}
+
+	_, stderr = cli.ExecuteAndFail("backend", "raw", "sqlite", "GET", "/", "--yes-i-know")
+	testutil.AssertContains(t, stderr, "does not support raw requests")
+}
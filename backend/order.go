@@ -0,0 +1,218 @@
+package backend
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrTaskOrderNotSupported is returned when a backend cannot store the manual order of tasks.
+var ErrTaskOrderNotSupported = errors.New("manual task order is not supported by this backend")
+
+// TaskOrderStorer is implemented by backends that store the Position and
+// Pinned fields of tasks as they are given
+type TaskOrderStorer interface {
+	StoresTaskOrder() bool
+}
+
+// StoresTaskOrder reports whether tm stores the manual order and pins of tasks
+func StoresTaskOrder(tm TaskManager) bool {
+	storer, ok := findOptional[TaskOrderStorer](tm)
+	return ok && storer.StoresTaskOrder()
+}
+
+// TaskMover is an optional interface for backends that keep a manual order of
+// tasks on the server. Sync uses it to push a changed Position: the task is
+// moved directly after the sibling before it in the local order. Pins are not
+// pushed; no remote API has them.
+// Supported by the Todoist and Google Tasks backends.
+type TaskMover interface {
+	// MoveTask places a task directly after previousID among the children of
+	// parentID ("" for top-level tasks), or first when previousID is empty.
+	MoveTask(ctx context.Context, listID, taskID, parentID, previousID string) error
+}
+
+// OnlyOrderChanged reports whether fields, as returned by ChangedFields, only
+// name the position and pin of a task, which sync does not send with the task
+func OnlyOrderChanged(fields []string) bool {
+	for _, f := range fields {
+		if f != FieldPosition && f != FieldPinned {
+			return false
+		}
+	}
+	return len(fields) > 0
+}
+
+// ComparePosition compares two tasks by manual order: pinned tasks come first,
+// then tasks with a position (lowest first), then tasks never reordered
+func ComparePosition(a, b Task) int {
+	if a.Pinned != b.Pinned {
+		if a.Pinned {
+			return -1
+		}
+		return 1
+	}
+	switch {
+	case a.Position == b.Position:
+		return 0
+	case a.Position == 0:
+		return 1
+	case b.Position == 0:
+		return -1
+	}
+	return cmp.Compare(a.Position, b.Position)
+}
+
+// SortByPosition sorts tasks into their manual order. The sort is stable, so
+// tasks that were never reordered keep the order they are in.
+func SortByPosition(tasks []Task) {
+	slices.SortStableFunc(tasks, ComparePosition)
+}
+
+// PreviousSibling returns the ID of the task before taskID among its siblings
+// (the tasks with the same parent) in manual order, or "" if it is the first
+func PreviousSibling(tasks []Task, taskID string) string {
+	task := findTask(tasks, taskID)
+	if task == nil {
+		return ""
+	}
+	siblings := siblingsInOrder(tasks, task.ParentID)
+	i := slices.IndexFunc(siblings, func(t Task) bool { return t.ID == taskID })
+	if i <= 0 {
+		return ""
+	}
+	return siblings[i-1].ID
+}
+
+// MoveTask moves a task directly before or after target among its siblings,
+// taking target's pin state, and numbers the siblings from 1 in their new
+// order. It returns the siblings whose Position or Pinned changed, with the
+// new values set.
+func MoveTask(tasks []Task, taskID, targetID string, after bool) ([]Task, error) {
+	task, target := findTask(tasks, taskID), findTask(tasks, targetID)
+	if task == nil {
+		return nil, fmt.Errorf("task not found: %s", taskID)
+	}
+	if target == nil {
+		return nil, fmt.Errorf("task not found: %s", targetID)
+	}
+	if taskID == targetID {
+		return nil, fmt.Errorf("cannot move task '%s' relative to itself", task.Summary)
+	}
+	if task.ParentID != target.ParentID {
+		return nil, fmt.Errorf("'%s' and '%s' are not siblings: only tasks with the same parent can be reordered", task.Summary, target.Summary)
+	}
+
+	siblings := siblingsInOrder(tasks, task.ParentID)
+	before := orderOf(siblings)
+	siblings, moved := removeTask(siblings, taskID)
+	moved.Pinned = target.Pinned
+	i := slices.IndexFunc(siblings, func(t Task) bool { return t.ID == targetID })
+	if after {
+		i++
+	}
+	return renumber(before, slices.Insert(siblings, i, moved)), nil
+}
+
+// PinTask pins or unpins a task. It goes to the end of the pinned tasks, or
+// to the start of the unpinned ones, so it stays where it was shown. The
+// siblings are numbered from 1 and those that changed are returned.
+func PinTask(tasks []Task, taskID string, pinned bool) ([]Task, error) {
+	task := findTask(tasks, taskID)
+	if task == nil {
+		return nil, fmt.Errorf("task not found: %s", taskID)
+	}
+
+	siblings := siblingsInOrder(tasks, task.ParentID)
+	before := orderOf(siblings)
+	siblings, moved := removeTask(siblings, taskID)
+	moved.Pinned = pinned
+	i := 0
+	for i < len(siblings) && siblings[i].Pinned {
+		i++
+	}
+	return renumber(before, slices.Insert(siblings, i, moved)), nil
+}
+
+// ShiftTask moves a task delta places down (up when negative) among its
+// siblings with the same pin state, stopping at the first or last of them. The
+// siblings are numbered from 1 and those that changed are returned.
+func ShiftTask(tasks []Task, taskID string, delta int) ([]Task, error) {
+	task := findTask(tasks, taskID)
+	if task == nil {
+		return nil, fmt.Errorf("task not found: %s", taskID)
+	}
+
+	siblings := siblingsInOrder(tasks, task.ParentID)
+	before := orderOf(siblings)
+	from := slices.IndexFunc(siblings, func(t Task) bool { return t.ID == taskID })
+	first, last := from, from
+	for first > 0 && siblings[first-1].Pinned == task.Pinned {
+		first--
+	}
+	for last < len(siblings)-1 && siblings[last+1].Pinned == task.Pinned {
+		last++
+	}
+	to := min(max(from+delta, first), last)
+	siblings, moved := removeTask(siblings, taskID)
+	return renumber(before, slices.Insert(siblings, to, moved)), nil
+}
+
+// findTask returns the task with id, or nil
+func findTask(tasks []Task, id string) *Task {
+	for i := range tasks {
+		if tasks[i].ID == id {
+			return &tasks[i]
+		}
+	}
+	return nil
+}
+
+// siblingsInOrder returns copies of the children of parentID in manual order
+func siblingsInOrder(tasks []Task, parentID string) []Task {
+	var siblings []Task
+	for _, t := range tasks {
+		if t.ParentID == parentID {
+			siblings = append(siblings, t)
+		}
+	}
+	SortByPosition(siblings)
+	return siblings
+}
+
+// removeTask removes the task with id from tasks and returns it
+func removeTask(tasks []Task, id string) ([]Task, Task) {
+	i := slices.IndexFunc(tasks, func(t Task) bool { return t.ID == id })
+	removed := tasks[i]
+	return slices.Delete(tasks, i, i+1), removed
+}
+
+// taskOrder is the position and pin state of a task
+type taskOrder struct {
+	position int
+	pinned   bool
+}
+
+// orderOf records the position and pin state of tasks by ID
+func orderOf(tasks []Task) map[string]taskOrder {
+	order := make(map[string]taskOrder, len(tasks))
+	for _, t := range tasks {
+		order[t.ID] = taskOrder{t.Position, t.Pinned}
+	}
+	return order
+}
+
+// renumber numbers ordered from 1 and returns the tasks whose position or pin
+// state differs from before
+func renumber(before map[string]taskOrder, ordered []Task) []Task {
+	var changed []Task
+	for i := range ordered {
+		ordered[i].Position = i + 1
+		if before[ordered[i].ID] != (taskOrder{ordered[i].Position, ordered[i].Pinned}) {
+			changed = append(changed, ordered[i])
+		}
+	}
+	return changed
+}
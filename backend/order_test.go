@@ -0,0 +1,121 @@
+package backend_test
+
+import (
+	"slices"
+	"testing"
+
+	"todoat/backend"
+)
+
+// ids returns the IDs of tasks in order
+func ids(tasks []backend.Task) []string {
+	result := make([]string, len(tasks))
+	for i, t := range tasks {
+		result[i] = t.ID
+	}
+	return result
+}
+
+// applyOrder returns tasks with changed applied, sorted into manual order
+func applyOrder(tasks, changed []backend.Task) []backend.Task {
+	result := slices.Clone(tasks)
+	for _, c := range changed {
+		for i := range result {
+			if result[i].ID == c.ID {
+				result[i] = c
+			}
+		}
+	}
+	backend.SortByPosition(result)
+	return result
+}
+
+// TestSortByPosition verifies pinned tasks come first and never-reordered tasks last
+func TestSortByPosition(t *testing.T) {
+	tasks := []backend.Task{
+		{ID: "new"},
+		{ID: "second", Position: 2},
+		{ID: "pinned", Position: 3, Pinned: true},
+		{ID: "first", Position: 1},
+		{ID: "newer"},
+	}
+	backend.SortByPosition(tasks)
+	want := []string{"pinned", "first", "second", "new", "newer"}
+	if got := ids(tasks); !slices.Equal(got, want) {
+		t.Errorf("SortByPosition = %v, want %v", got, want)
+	}
+}
+
+// TestMoveTask verifies moves renumber the siblings and only return those that changed
+func TestMoveTask(t *testing.T) {
+	tasks := []backend.Task{{ID: "a"}, {ID: "b"}, {ID: "c"}, {ID: "child", ParentID: "a"}}
+
+	changed, err := backend.MoveTask(tasks, "c", "a", false)
+	if err != nil {
+		t.Fatalf("MoveTask error: %v", err)
+	}
+	tasks = applyOrder(tasks, changed)
+	if got, want := ids(tasks), []string{"c", "a", "b", "child"}; !slices.Equal(got, want) {
+		t.Errorf("order after move = %v, want %v", got, want)
+	}
+	if backend.PreviousSibling(tasks, "a") != "c" || backend.PreviousSibling(tasks, "c") != "" {
+		t.Errorf("PreviousSibling does not follow the new order: %v", ids(tasks))
+	}
+
+	changed, err = backend.MoveTask(tasks, "b", "a", false)
+	if err != nil {
+		t.Fatalf("MoveTask error: %v", err)
+	}
+	if got, want := ids(changed), []string{"b", "a"}; !slices.Equal(got, want) {
+		t.Errorf("changed = %v, want %v", got, want)
+	}
+
+	if _, err := backend.MoveTask(tasks, "child", "b", true); err == nil {
+		t.Error("MoveTask of a subtask next to a top-level task should fail")
+	}
+}
+
+// TestPinAndShiftTask verifies pins keep tasks on top and shifts stay within the pin group
+func TestPinAndShiftTask(t *testing.T) {
+	tasks := []backend.Task{{ID: "a"}, {ID: "b"}, {ID: "c"}}
+
+	changed, err := backend.PinTask(tasks, "c", true)
+	if err != nil {
+		t.Fatalf("PinTask error: %v", err)
+	}
+	tasks = applyOrder(tasks, changed)
+	if got, want := ids(tasks), []string{"c", "a", "b"}; !slices.Equal(got, want) {
+		t.Errorf("order after pin = %v, want %v", got, want)
+	}
+
+	// An unpinned task cannot move above a pinned one
+	changed, err = backend.ShiftTask(tasks, "a", -1)
+	if err != nil {
+		t.Fatalf("ShiftTask error: %v", err)
+	}
+	if len(changed) != 0 {
+		t.Errorf("ShiftTask past a pinned task changed %v", ids(changed))
+	}
+
+	changed, err = backend.ShiftTask(tasks, "a", 1)
+	if err != nil {
+		t.Fatalf("ShiftTask error: %v", err)
+	}
+	tasks = applyOrder(tasks, changed)
+	if got, want := ids(tasks), []string{"c", "b", "a"}; !slices.Equal(got, want) {
+		t.Errorf("order after shift = %v, want %v", got, want)
+	}
+}
+
+// TestOnlyOrderChanged verifies order-only changes are told apart from field changes
+func TestOnlyOrderChanged(t *testing.T) {
+	if !backend.OnlyOrderChanged([]string{backend.FieldPosition, backend.FieldPinned}) {
+		t.Error("position and pinned should be an order-only change")
+	}
+	if backend.OnlyOrderChanged([]string{backend.FieldPosition, backend.FieldSummary}) {
+		t.Error("a summary change is not an order-only change")
+	}
+	if backend.OnlyOrderChanged(nil) {
+		t.Error("no change is not an order-only change")
+	}
+}
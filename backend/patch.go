@@ -0,0 +1,76 @@
+package backend
+
+import (
+	"context"
+	"maps"
+	"time"
+)
+
+// Task field names recorded for queued updates and sent by FieldPatcher
+const (
+	FieldSummary     = "summary"
+	FieldDescription = "description"
+	FieldStatus      = "status"
+	FieldPriority    = "priority"
+	FieldDueDate     = "due_date"
+	FieldStartDate   = "start_date"
+	FieldCategories  = "categories"
+	FieldRecurrence  = "recurrence"
+	FieldParent      = "parent"
+	FieldMetadata    = "metadata"
+	FieldPosition    = "position"
+	FieldPinned      = "pinned"
+)
+
+// FieldPatcher is an optional interface for backends whose API can change some
+// fields of a task and leave the others untouched (PATCH semantics). Sync uses
+// it to push only the fields changed locally, which saves API quota and keeps
+// fields todoat does not model from being overwritten.
+// Currently supported by the Todoist and Microsoft To Do backends.
+type FieldPatcher interface {
+	// PatchTask sends the given fields of task to the backend. Fields not
+	// listed keep their value on the server.
+	PatchTask(ctx context.Context, listID string, task *Task, fields []string) (*Task, error)
+}
+
+// ChangedFields returns the names of the fields that differ between two
+// versions of a task, in the order of the Field constants.
+func ChangedFields(old, updated *Task) []string {
+	var fields []string
+	add := func(changed bool, name string) {
+		if changed {
+			fields = append(fields, name)
+		}
+	}
+	add(old.Summary != updated.Summary, FieldSummary)
+	add(old.Description != updated.Description, FieldDescription)
+	add(old.Status != updated.Status, FieldStatus)
+	add(old.Priority != updated.Priority, FieldPriority)
+	add(!sameTime(old.DueDate, updated.DueDate), FieldDueDate)
+	add(!sameTime(old.StartDate, updated.StartDate), FieldStartDate)
+	add(old.Categories != updated.Categories, FieldCategories)
+	add(old.Recurrence != updated.Recurrence || old.RecurFromDue != updated.RecurFromDue, FieldRecurrence)
+	add(old.ParentID != updated.ParentID, FieldParent)
+	add(updated.Metadata != nil && !maps.Equal(old.Metadata, updated.Metadata), FieldMetadata)
+	add(old.Position != updated.Position, FieldPosition)
+	add(old.Pinned != updated.Pinned, FieldPinned)
+	return fields
+}
+
+// sameTime reports whether two optional times are both unset or equal
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// PatchTask sends only the given fields of task when the backend supports
+// FieldPatcher and fields is not empty, and the whole task with UpdateTask
+// otherwise.
+func PatchTask(ctx context.Context, tm TaskManager, listID string, task *Task, fields []string) (*Task, error) {
+	if patcher, ok := tm.(FieldPatcher); ok && len(fields) > 0 {
+		return patcher.PatchTask(ctx, listID, task, fields)
+	}
+	return tm.UpdateTask(ctx, listID, task)
+}
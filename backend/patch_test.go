@@ -0,0 +1,64 @@
+package backend_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"todoat/backend"
+	"todoat/backend/sqlite"
+)
+
+// TestChangedFields verifies the fields reported for two versions of a task
+func TestChangedFields(t *testing.T) {
+	due := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	old := &backend.Task{Summary: "Pay rent", Priority: 5, DueDate: &due, Categories: "home"}
+
+	same := *old
+	sameDue := due
+	same.DueDate = &sameDue
+	if fields := backend.ChangedFields(old, &same); len(fields) != 0 {
+		t.Errorf("ChangedFields of equal tasks = %v, want none", fields)
+	}
+
+	updated := *old
+	updated.Summary = "Pay the rent"
+	updated.DueDate = nil
+	updated.Status = backend.StatusCompleted
+	want := []string{backend.FieldSummary, backend.FieldStatus, backend.FieldDueDate}
+	if fields := backend.ChangedFields(old, &updated); !slices.Equal(fields, want) {
+		t.Errorf("ChangedFields = %v, want %v", fields, want)
+	}
+}
+
+// TestPatchTaskFallsBackToUpdate verifies backends without FieldPatcher get a full update
+func TestPatchTaskFallsBackToUpdate(t *testing.T) {
+	ctx := context.Background()
+	be, err := sqlite.New(":memory:")
+	if err != nil {
+		t.Fatalf("sqlite.New error: %v", err)
+	}
+	defer func() { _ = be.Close() }()
+	list, err := be.CreateList(ctx, "Home")
+	if err != nil {
+		t.Fatalf("CreateList error: %v", err)
+	}
+	task, err := be.CreateTask(ctx, list.ID, &backend.Task{Summary: "Pay rent", Priority: 5})
+	if err != nil {
+		t.Fatalf("CreateTask error: %v", err)
+	}
+
+	task.Summary = "Pay the rent"
+	task.Priority = 1
+	if _, err := backend.PatchTask(ctx, be, list.ID, task, []string{backend.FieldSummary}); err != nil {
+		t.Fatalf("PatchTask error: %v", err)
+	}
+	got, err := be.GetTask(ctx, list.ID, task.ID)
+	if err != nil {
+		t.Fatalf("GetTask error: %v", err)
+	}
+	if got.Summary != "Pay the rent" || got.Priority != 1 {
+		t.Errorf("got %q priority %d, want the whole task updated", got.Summary, got.Priority)
+	}
+}
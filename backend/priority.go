@@ -0,0 +1,102 @@
+package backend
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// PriorityMap translates todoat priorities (0-9, 1 = highest, 0 = unset) to a
+// backend's native priority values and back. Native scales are coarser, so a
+// mapping is many-to-one: several todoat priorities share one native value,
+// which reads back as the first priority listed for it.
+type PriorityMap struct {
+	toNative   map[int]string
+	fromNative map[string]int
+}
+
+// NewPriorityMap builds a PriorityMap from native value -> todoat priorities,
+// e.g. {"high": {1, 2, 3}, "normal": {5, 0, 4, 6}, "low": {7, 8, 9}}. Every
+// priority 0-9 must appear exactly once, and every native value must be one
+// of values.
+func NewPriorityMap(native map[string][]int, values []string) (*PriorityMap, error) {
+	m := &PriorityMap{
+		toNative:   make(map[int]string, 10),
+		fromNative: make(map[string]int, len(native)),
+	}
+	for value, priorities := range native {
+		if !slices.Contains(values, value) {
+			return nil, fmt.Errorf("priority_map: unknown value %q (expected %s)", value, strings.Join(values, ", "))
+		}
+		if len(priorities) == 0 {
+			return nil, fmt.Errorf("priority_map: %q has no priorities", value)
+		}
+		m.fromNative[value] = priorities[0]
+		for _, p := range priorities {
+			if p < 0 || p > 9 {
+				return nil, fmt.Errorf("priority_map: priority %d for %q is outside 0-9", p, value)
+			}
+			if other, ok := m.toNative[p]; ok {
+				return nil, fmt.Errorf("priority_map: priority %d is mapped to both %q and %q", p, other, value)
+			}
+			m.toNative[p] = value
+		}
+	}
+	var missing []string
+	for p := 0; p <= 9; p++ {
+		if _, ok := m.toNative[p]; !ok {
+			missing = append(missing, fmt.Sprint(p))
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("priority_map: priorities %s are not mapped", strings.Join(missing, ", "))
+	}
+	return m, nil
+}
+
+// MustPriorityMap is like NewPriorityMap but panics on an invalid mapping. It
+// is meant for the built-in defaults of backends.
+func MustPriorityMap(native map[string][]int, values []string) *PriorityMap {
+	m, err := NewPriorityMap(native, values)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// ToNative returns the native value for a todoat priority. Out-of-range
+// priorities are clamped to 0-9.
+func (m *PriorityMap) ToNative(priority int) string {
+	return m.toNative[min(max(priority, 0), 9)]
+}
+
+// FromNative returns the todoat priority for a native value, and false if the
+// value is not in the map
+func (m *PriorityMap) FromNative(value string) (int, bool) {
+	p, ok := m.fromNative[value]
+	return p, ok
+}
+
+// Same reports whether two todoat priorities map to the same native value, i.e.
+// the backend cannot tell them apart
+func (m *PriorityMap) Same(a, b int) bool {
+	return m.ToNative(a) == m.ToNative(b)
+}
+
+// PriorityMapper is implemented by backends whose native priority scale is
+// coarser than todoat's 0-9
+type PriorityMapper interface {
+	PriorityMap() *PriorityMap
+}
+
+// SamePriority reports whether tm stores priorities a and b as the same native
+// value. Sync uses it to keep the local priority when the remote one only
+// differs because of the backend's coarser scale. Backends storing 0-9 as-is
+// only treat equal priorities as the same.
+func SamePriority(tm TaskManager, a, b int) bool {
+	if a == b {
+		return true
+	}
+	mapper, ok := findOptional[PriorityMapper](tm)
+	return ok && mapper.PriorityMap().Same(a, b)
+}
@@ -0,0 +1,76 @@
+package backend_test
+
+import (
+	"testing"
+
+	"todoat/backend"
+	"todoat/backend/sqlite"
+)
+
+var importanceValues = []string{"low", "normal", "high"}
+
+// TestPriorityMap verifies conversions and that the first priority listed is read back
+func TestPriorityMap(t *testing.T) {
+	m, err := backend.NewPriorityMap(map[string][]int{"high": {2, 1, 3}, "normal": {5, 0, 4, 6}, "low": {7, 8, 9}}, importanceValues)
+	if err != nil {
+		t.Fatalf("NewPriorityMap error: %v", err)
+	}
+	for priority, want := range map[int]string{0: "normal", 1: "high", 3: "high", 6: "normal", 9: "low", 12: "low", -1: "normal"} {
+		if got := m.ToNative(priority); got != want {
+			t.Errorf("ToNative(%d) = %q, want %q", priority, got, want)
+		}
+	}
+	if p, ok := m.FromNative("high"); !ok || p != 2 {
+		t.Errorf("FromNative(high) = %d, %v; want 2", p, ok)
+	}
+	if _, ok := m.FromNative("urgent"); ok {
+		t.Error("FromNative(urgent) should not be found")
+	}
+	if !m.Same(1, 3) || m.Same(3, 4) {
+		t.Error("Same should compare native values")
+	}
+}
+
+// TestNewPriorityMapValidates verifies unknown values, duplicates and gaps are rejected
+func TestNewPriorityMapValidates(t *testing.T) {
+	tests := map[string]map[string][]int{
+		"unknown value": {"urgent": {0, 1, 2, 3, 4, 5, 6, 7, 8, 9}},
+		"out of range":  {"high": {1, 2, 3, 10}, "normal": {0, 4, 5, 6}, "low": {7, 8, 9}},
+		"duplicate":     {"high": {1, 2, 3}, "normal": {3, 0, 4, 5, 6}, "low": {7, 8, 9}},
+		"missing":       {"high": {1, 2, 3}, "low": {7, 8, 9}},
+		"empty":         {"high": {}, "normal": {0, 1, 2, 3, 4, 5, 6}, "low": {7, 8, 9}},
+	}
+	for name, native := range tests {
+		if _, err := backend.NewPriorityMap(native, importanceValues); err == nil {
+			t.Errorf("%s: NewPriorityMap should fail", name)
+		}
+	}
+}
+
+type coarseBackend struct {
+	*sqlite.Backend
+	m *backend.PriorityMap
+}
+
+func (b *coarseBackend) PriorityMap() *backend.PriorityMap { return b.m }
+
+// TestSamePriority verifies mappers are found through wrappers and other backends compare exactly
+func TestSamePriority(t *testing.T) {
+	db, err := sqlite.New(":memory:")
+	if err != nil {
+		t.Fatalf("sqlite.New error: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+	m := backend.MustPriorityMap(map[string][]int{"high": {1, 2, 3}, "normal": {5, 0, 4, 6}, "low": {9, 7, 8}}, importanceValues)
+	wrapped := backend.NewReadOnly(&coarseBackend{Backend: db, m: m}, "test")
+
+	if !backend.SamePriority(wrapped, 1, 2) {
+		t.Error("1 and 2 share a level behind a read-only wrapper")
+	}
+	if backend.SamePriority(wrapped, 3, 4) {
+		t.Error("3 and 4 are different levels")
+	}
+	if backend.SamePriority(db, 1, 2) || !backend.SamePriority(db, 4, 4) {
+		t.Error("backends without a mapping should compare priorities exactly")
+	}
+}
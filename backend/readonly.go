@@ -0,0 +1,200 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrReadOnly is returned for every operation that would modify data on a read-only backend.
+var ErrReadOnly = errors.New("backend is read-only")
+
+// ReadOnlyBackend wraps a TaskManager and rejects every operation that would modify
+// data with an error wrapping ErrReadOnly. Reads pass through, including the read
+// parts of the optional interfaces. Used for --read-only and backends configured
+// with read_only: true.
+type ReadOnlyBackend struct {
+	TaskManager
+	reason string // why the backend is read-only, shown in errors (e.g. "--read-only")
+}
+
+// NewReadOnly wraps tm so it cannot be modified. reason explains where read-only
+// mode comes from in error messages. A backend that is already read-only is
+// returned unchanged.
+func NewReadOnly(tm TaskManager, reason string) TaskManager {
+	if _, ok := tm.(*ReadOnlyBackend); ok {
+		return tm
+	}
+	return &ReadOnlyBackend{TaskManager: tm, reason: reason}
+}
+
+// Unwrap returns the wrapped backend
+func (b *ReadOnlyBackend) Unwrap() TaskManager {
+	return b.TaskManager
+}
+
+// denied returns the error for a blocked operation
+func (b *ReadOnlyBackend) denied(operation string) error {
+	return fmt.Errorf("cannot %s: %w (%s)", operation, ErrReadOnly, b.reason)
+}
+
+// CreateList is rejected
+func (b *ReadOnlyBackend) CreateList(ctx context.Context, name string) (*List, error) {
+	return nil, b.denied("create list")
+}
+
+// UpdateList is rejected
+func (b *ReadOnlyBackend) UpdateList(ctx context.Context, list *List) (*List, error) {
+	return nil, b.denied("update list")
+}
+
+// DeleteList is rejected
+func (b *ReadOnlyBackend) DeleteList(ctx context.Context, listID string) error {
+	return b.denied("delete list")
+}
+
+// RestoreList is rejected
+func (b *ReadOnlyBackend) RestoreList(ctx context.Context, listID string) error {
+	return b.denied("restore list")
+}
+
+// PurgeList is rejected
+func (b *ReadOnlyBackend) PurgeList(ctx context.Context, listID string) error {
+	return b.denied("purge list")
+}
+
+// CreateTask is rejected
+func (b *ReadOnlyBackend) CreateTask(ctx context.Context, listID string, task *Task) (*Task, error) {
+	return nil, b.denied("add task")
+}
+
+// UpdateTask is rejected
+func (b *ReadOnlyBackend) UpdateTask(ctx context.Context, listID string, task *Task) (*Task, error) {
+	return nil, b.denied("update task")
+}
+
+// DeleteTask is rejected
+func (b *ReadOnlyBackend) DeleteTask(ctx context.Context, listID, taskID string) error {
+	return b.denied("delete task")
+}
+
+// UpdateTasks is rejected
+func (b *ReadOnlyBackend) UpdateTasks(ctx context.Context, listID string, tasks []Task) ([]Task, error) {
+	return nil, b.denied("update tasks")
+}
+
+// DeleteTasks is rejected
+func (b *ReadOnlyBackend) DeleteTasks(ctx context.Context, listID string, taskIDs []string) error {
+	return b.denied("delete tasks")
+}
+
+// ShareList is rejected
+func (b *ReadOnlyBackend) ShareList(ctx context.Context, listID string, username string, permission string) error {
+	return b.denied("share list")
+}
+
+// UnshareList is rejected
+func (b *ReadOnlyBackend) UnshareList(ctx context.Context, listID string, username string) error {
+	return b.denied("unshare list")
+}
+
+// PublishList is rejected
+func (b *ReadOnlyBackend) PublishList(ctx context.Context, listID string) (string, error) {
+	return "", b.denied("publish list")
+}
+
+// UnpublishList is rejected
+func (b *ReadOnlyBackend) UnpublishList(ctx context.Context, listID string) error {
+	return b.denied("unpublish list")
+}
+
+// SubscribeList is rejected
+func (b *ReadOnlyBackend) SubscribeList(ctx context.Context, sourceURL string) (*List, error) {
+	return nil, b.denied("subscribe to list")
+}
+
+// UnsubscribeList is rejected
+func (b *ReadOnlyBackend) UnsubscribeList(ctx context.Context, listID string) error {
+	return b.denied("unsubscribe from list")
+}
+
+// ArchiveList is rejected
+func (b *ReadOnlyBackend) ArchiveList(ctx context.Context, listID string) error {
+	return b.denied("archive list")
+}
+
+// UnarchiveList is rejected
+func (b *ReadOnlyBackend) UnarchiveList(ctx context.Context, listID string) error {
+	return b.denied("unarchive list")
+}
+
+// GetArchivedLists returns the archived lists of the wrapped backend
+func (b *ReadOnlyBackend) GetArchivedLists(ctx context.Context) ([]List, error) {
+	archiver, ok := b.TaskManager.(ListArchiver)
+	if !ok {
+		return nil, ErrListArchiveNotSupported
+	}
+	return archiver.GetArchivedLists(ctx)
+}
+
+// GetListDefaults returns the list defaults of the wrapped backend
+func (b *ReadOnlyBackend) GetListDefaults(ctx context.Context, listID string) (ListDefaults, error) {
+	defaulter, ok := b.TaskManager.(ListDefaulter)
+	if !ok {
+		return ListDefaults{}, ErrListDefaultsNotSupported
+	}
+	return defaulter.GetListDefaults(ctx, listID)
+}
+
+// SetListDefaults is rejected
+func (b *ReadOnlyBackend) SetListDefaults(ctx context.Context, listID string, defaults ListDefaults) error {
+	return b.denied("set list defaults")
+}
+
+// GetTasksPage returns a page of tasks from the wrapped backend
+func (b *ReadOnlyBackend) GetTasksPage(ctx context.Context, listID string, offset, limit int) ([]Task, error) {
+	return GetTasksPage(ctx, b.TaskManager, listID, offset, limit)
+}
+
+// GetTaskCounts counts the tasks of the wrapped backend's lists
+func (b *ReadOnlyBackend) GetTaskCounts(ctx context.Context) (map[string]int, error) {
+	lists, err := b.TaskManager.GetLists(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return GetTaskCounts(ctx, b.TaskManager, lists)
+}
+
+// RawRequest forwards read-only requests to the wrapped backend
+func (b *ReadOnlyBackend) RawRequest(ctx context.Context, method, path string) (*http.Response, error) {
+	requester, ok := b.TaskManager.(RawRequester)
+	if !ok {
+		return nil, errors.New("raw requests are not supported by this backend")
+	}
+	return requester.RawRequest(ctx, method, path)
+}
+
+// QueryReadOnly forwards read-only queries to the wrapped backend
+func (b *ReadOnlyBackend) QueryReadOnly(ctx context.Context, query string) ([]string, [][]string, error) {
+	querier, ok := b.TaskManager.(RawQuerier)
+	if !ok {
+		return nil, nil, errors.New("SQL queries are not supported by this backend")
+	}
+	return querier.QueryReadOnly(ctx, query)
+}
+
+// Verify interface compliance at compile time
+var (
+	_ TaskManager    = (*ReadOnlyBackend)(nil)
+	_ ListSharer     = (*ReadOnlyBackend)(nil)
+	_ ListPublisher  = (*ReadOnlyBackend)(nil)
+	_ ListSubscriber = (*ReadOnlyBackend)(nil)
+	_ ListArchiver   = (*ReadOnlyBackend)(nil)
+	_ ListDefaulter  = (*ReadOnlyBackend)(nil)
+	_ TaskPager      = (*ReadOnlyBackend)(nil)
+	_ TaskCounter    = (*ReadOnlyBackend)(nil)
+	_ TaskBatcher    = (*ReadOnlyBackend)(nil)
+	_ RawRequester   = (*ReadOnlyBackend)(nil)
+	_ RawQuerier     = (*ReadOnlyBackend)(nil)
+)
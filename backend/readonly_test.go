@@ -0,0 +1,56 @@
+package backend_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"todoat/backend"
+	"todoat/backend/sqlite"
+)
+
+// TestReadOnlyBackend verifies reads pass through and every write fails with ErrReadOnly
+func TestReadOnlyBackend(t *testing.T) {
+	ctx := context.Background()
+	be, err := sqlite.New(":memory:")
+	if err != nil {
+		t.Fatalf("sqlite.New error: %v", err)
+	}
+	defer func() { _ = be.Close() }()
+	list, err := be.CreateList(ctx, "Team")
+	if err != nil {
+		t.Fatalf("CreateList error: %v", err)
+	}
+	task, err := be.CreateTask(ctx, list.ID, &backend.Task{Summary: "Review"})
+	if err != nil {
+		t.Fatalf("CreateTask error: %v", err)
+	}
+
+	ro := backend.NewReadOnly(be, "--read-only")
+	if backend.NewReadOnly(ro, "again") != ro {
+		t.Error("NewReadOnly should not wrap a read-only backend twice")
+	}
+	tasks, err := ro.GetTasks(ctx, list.ID)
+	if err != nil || len(tasks) != 1 {
+		t.Fatalf("GetTasks = %d tasks, %v; want 1", len(tasks), err)
+	}
+
+	writes := map[string]error{}
+	_, writes["CreateList"] = ro.CreateList(ctx, "Other")
+	_, writes["CreateTask"] = ro.CreateTask(ctx, list.ID, &backend.Task{Summary: "New"})
+	_, writes["UpdateTask"] = ro.UpdateTask(ctx, list.ID, task)
+	writes["DeleteTask"] = ro.DeleteTask(ctx, list.ID, task.ID)
+	writes["DeleteList"] = ro.DeleteList(ctx, list.ID)
+	writes["ArchiveList"] = ro.(backend.ListArchiver).ArchiveList(ctx, list.ID)
+	writes["SetListDefaults"] = ro.(backend.ListDefaulter).SetListDefaults(ctx, list.ID, backend.ListDefaults{Priority: 1})
+	_, writes["UpdateTasks"] = backend.UpdateTasks(ctx, ro, list.ID, []backend.Task{*task})
+	for name, err := range writes {
+		if !errors.Is(err, backend.ErrReadOnly) {
+			t.Errorf("%s error = %v, want ErrReadOnly", name, err)
+		}
+	}
+
+	if tasks, _ := be.GetTasks(ctx, list.ID); len(tasks) != 1 || tasks[0].Summary != "Review" {
+		t.Errorf("underlying backend was modified: %+v", tasks)
+	}
+}
@@ -0,0 +1,187 @@
+package remote
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"todoat/backend"
+	"todoat/internal/tz"
+)
+
+// Error codes sent in APIError.Code
+const (
+	codeBadRequest   = "bad_request"
+	codeUnauthorized = "unauthorized"
+	codeNotFound     = "not_found"
+	codeReadOnly     = "read_only"
+	codeInternal     = "internal"
+)
+
+// APIError is the JSON body of an error response
+type APIError struct {
+	Message string `json:"error"`
+	Code    string `json:"code"`
+}
+
+// Error returns the server's message
+func (e *APIError) Error() string {
+	return "remote server: " + e.Message
+}
+
+// Unwrap maps error codes back to the backend errors they came from, so
+// errors.Is(err, backend.ErrReadOnly) works across the connection
+func (e *APIError) Unwrap() error {
+	if e.Code == codeReadOnly {
+		return backend.ErrReadOnly
+	}
+	return nil
+}
+
+// serverInfo is returned by GET /info
+type serverInfo struct {
+	SupportsTrash        bool `json:"supports_trash"`
+	StoresCustomStatuses bool `json:"stores_custom_statuses"`
+}
+
+// createListRequest is the body of POST /lists
+type createListRequest struct {
+	Name string `json:"name"`
+}
+
+// listJSON is the wire form of backend.List
+type listJSON struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Color       string     `json:"color,omitempty"`
+	Description string     `json:"description,omitempty"`
+	Modified    time.Time  `json:"modified"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+	ArchivedAt  *time.Time `json:"archived_at,omitempty"`
+}
+
+// taskJSON is the wire form of backend.Task. Due and start dates are strings so
+// date-only values ("2026-03-14") keep their calendar day across time zones.
+type taskJSON struct {
+	ID           string            `json:"id"`
+	Summary      string            `json:"summary"`
+	Description  string            `json:"description,omitempty"`
+	Status       string            `json:"status"`
+	Priority     int               `json:"priority,omitempty"`
+	DueDate      string            `json:"due,omitempty"`
+	StartDate    string            `json:"start,omitempty"`
+	Completed    *time.Time        `json:"completed,omitempty"`
+	Created      time.Time         `json:"created"`
+	Modified     time.Time         `json:"modified"`
+	ListID       string            `json:"list_id,omitempty"`
+	ParentID     string            `json:"parent_id,omitempty"`
+	Categories   string            `json:"categories,omitempty"`
+	Recurrence   string            `json:"recurrence,omitempty"`
+	RecurFromDue bool              `json:"recur_from_due,omitempty"`
+	Metadata     map[string]string `json:"metadata,omitempty"`
+}
+
+// dateOnlyLayout is the wire format of due and start dates without a time
+const dateOnlyLayout = "2006-01-02"
+
+func listToJSON(l *backend.List) listJSON {
+	return listJSON{
+		ID:          l.ID,
+		Name:        l.Name,
+		Color:       l.Color,
+		Description: l.Description,
+		Modified:    l.Modified,
+		DeletedAt:   l.DeletedAt,
+		ArchivedAt:  l.ArchivedAt,
+	}
+}
+
+func (l listJSON) toList() *backend.List {
+	return &backend.List{
+		ID:          l.ID,
+		Name:        l.Name,
+		Color:       l.Color,
+		Description: l.Description,
+		Modified:    l.Modified,
+		DeletedAt:   l.DeletedAt,
+		ArchivedAt:  l.ArchivedAt,
+	}
+}
+
+func taskToJSON(t *backend.Task) taskJSON {
+	return taskJSON{
+		ID:           t.ID,
+		Summary:      t.Summary,
+		Description:  t.Description,
+		Status:       string(t.Status),
+		Priority:     t.Priority,
+		DueDate:      formatDate(t.DueDate),
+		StartDate:    formatDate(t.StartDate),
+		Completed:    t.Completed,
+		Created:      t.Created,
+		Modified:     t.Modified,
+		ListID:       t.ListID,
+		ParentID:     t.ParentID,
+		Categories:   t.Categories,
+		Recurrence:   t.Recurrence,
+		RecurFromDue: t.RecurFromDue,
+		Metadata:     t.Metadata,
+	}
+}
+
+func (t taskJSON) toTask() (*backend.Task, error) {
+	due, err := parseDate(t.DueDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid due date %q: %w", t.DueDate, err)
+	}
+	start, err := parseDate(t.StartDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start date %q: %w", t.StartDate, err)
+	}
+	return &backend.Task{
+		ID:           t.ID,
+		Summary:      t.Summary,
+		Description:  t.Description,
+		Status:       backend.TaskStatus(t.Status),
+		Priority:     t.Priority,
+		DueDate:      due,
+		StartDate:    start,
+		Completed:    t.Completed,
+		Created:      t.Created,
+		Modified:     t.Modified,
+		ListID:       t.ListID,
+		ParentID:     t.ParentID,
+		Categories:   t.Categories,
+		Recurrence:   t.Recurrence,
+		RecurFromDue: t.RecurFromDue,
+		Metadata:     t.Metadata,
+	}, nil
+}
+
+// formatDate encodes a due or start date: local midnight as a plain date,
+// anything else as an RFC 3339 timestamp
+func formatDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	local := t.In(tz.Location())
+	if local.Hour() == 0 && local.Minute() == 0 && local.Second() == 0 && local.Nanosecond() == 0 {
+		return local.Format(dateOnlyLayout)
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// parseDate decodes a date written by formatDate; plain dates become local midnight
+func parseDate(s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+	if t, err := time.ParseInLocation(dateOnlyLayout, s, tz.Location()); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return nil, errors.New("expected YYYY-MM-DD or an RFC 3339 timestamp")
+	}
+	return &t, nil
+}
@@ -0,0 +1,395 @@
+// Package remote provides a backend that uses another todoat instance as its
+// storage over HTTP. The other instance runs 'todoat serve', which exposes its
+// own backend through the JSON API implemented by Handler; Backend is the
+// client for that API. Requests are authenticated with a bearer token, and
+// HTTPS is required unless explicitly allowed.
+package remote
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"todoat/backend"
+	"todoat/internal/utils"
+)
+
+// APIPrefix is the path prefix of every API endpoint
+const APIPrefix = "/api/v1"
+
+// Config holds the connection settings of a remote todoat instance
+type Config struct {
+	URL                string // Base URL of the server, e.g. https://home.example:8766
+	Token              string // Bearer token the server was started with
+	CACert             string // PEM file with the CA (or self-signed) certificate to trust
+	AllowHTTP          bool   // Allow plain http:// URLs (tokens are then sent unencrypted)
+	InsecureSkipVerify bool   // Skip TLS certificate verification
+}
+
+// ConfigFromEnv creates a Config from environment variables
+func ConfigFromEnv() Config {
+	return Config{
+		URL:   os.Getenv("TODOAT_REMOTE_URL"),
+		Token: os.Getenv("TODOAT_REMOTE_TOKEN"),
+	}
+}
+
+// Backend implements backend.TaskManager by calling a remote todoat server
+type Backend struct {
+	config  Config
+	client  *http.Client
+	baseURL string
+
+	infoOnce sync.Once
+	info     serverInfo
+}
+
+// New creates a new remote backend. It does not contact the server.
+func New(cfg Config) (*Backend, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("remote server URL is required")
+	}
+	if cfg.Token == "" {
+		return nil, fmt.Errorf("remote server token is required")
+	}
+
+	raw := cfg.URL
+	if !strings.Contains(raw, "://") {
+		if cfg.AllowHTTP {
+			raw = "http://" + raw
+		} else {
+			raw = "https://" + raw
+		}
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid remote server URL %q", cfg.URL)
+	}
+	switch u.Scheme {
+	case "https":
+	case "http":
+		if !cfg.AllowHTTP {
+			return nil, fmt.Errorf("remote server URL %q uses http; set allow_http: true to send the token unencrypted", cfg.URL)
+		}
+	default:
+		return nil, fmt.Errorf("invalid remote server URL %q (use https://host:port)", cfg.URL)
+	}
+
+	client, err := createHTTPClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Backend{
+		config:  cfg,
+		client:  client,
+		baseURL: strings.TrimSuffix(u.String(), "/") + APIPrefix,
+	}, nil
+}
+
+// createHTTPClient creates an HTTP client trusting the configured CA certificate
+func createHTTPClient(cfg Config) (*http.Client, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: cfg.InsecureSkipVerify}
+	if cfg.InsecureSkipVerify {
+		utils.Component("remote").Warn("TLS certificate verification is disabled (insecure_skip_verify: true). Connections are vulnerable to man-in-the-middle attacks.")
+	}
+	if cfg.CACert != "" {
+		pem, err := os.ReadFile(cfg.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ca_cert: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("ca_cert %s contains no PEM certificates", cfg.CACert)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return &http.Client{
+		Transport: &http.Transport{
+			MaxIdleConns:        10,
+			MaxIdleConnsPerHost: 2,
+			IdleConnTimeout:     30 * time.Second,
+			TLSClientConfig:     tlsConfig,
+		},
+		Timeout: 30 * time.Second,
+	}, nil
+}
+
+// Close closes the backend
+func (b *Backend) Close() error {
+	if transport, ok := b.client.Transport.(*http.Transport); ok {
+		transport.CloseIdleConnections()
+	}
+	return nil
+}
+
+// RawRequest sends an authenticated request to path, relative to the API prefix
+func (b *Backend) RawRequest(ctx context.Context, method, path string) (*http.Response, error) {
+	return b.doRequest(ctx, method, path, nil)
+}
+
+// doRequest performs an authenticated API request with a JSON body
+func (b *Backend) doRequest(ctx context.Context, method, path string, body interface{}) (*http.Response, error) {
+	var bodyReader io.Reader
+	if body != nil {
+		jsonBody, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		bodyReader = bytes.NewReader(jsonBody)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, b.baseURL+path, bodyReader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+b.config.Token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return b.client.Do(req)
+}
+
+// doJSON performs a request and decodes a successful response into out. A 404
+// is reported as errNotFound so lookups can return nil.
+func (b *Backend) doJSON(ctx context.Context, method, path string, body, out interface{}) error {
+	resp, err := b.doRequest(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var apiErr APIError
+		if err := json.NewDecoder(resp.Body).Decode(&apiErr); err != nil || apiErr.Message == "" {
+			apiErr = APIError{Message: fmt.Sprintf("status %d", resp.StatusCode)}
+		}
+		switch resp.StatusCode {
+		case http.StatusUnauthorized:
+			return fmt.Errorf("authentication failed: invalid remote server token")
+		case http.StatusNotFound:
+			return errNotFound
+		}
+		return &apiErr
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// errNotFound is returned by doJSON for a 404 response
+var errNotFound = errors.New("not found")
+
+// =============================================================================
+// List Operations
+// =============================================================================
+
+// GetLists returns all active lists of the server
+func (b *Backend) GetLists(ctx context.Context) ([]backend.List, error) {
+	return b.getLists(ctx, "/lists")
+}
+
+// GetList returns a list by ID, or nil if it does not exist
+func (b *Backend) GetList(ctx context.Context, listID string) (*backend.List, error) {
+	return b.getList(ctx, "/lists/"+url.PathEscape(listID))
+}
+
+// GetListByName returns a list by name, or nil if it does not exist
+func (b *Backend) GetListByName(ctx context.Context, name string) (*backend.List, error) {
+	return b.getList(ctx, "/lists/by-name?name="+url.QueryEscape(name))
+}
+
+// CreateList creates a new list
+func (b *Backend) CreateList(ctx context.Context, name string) (*backend.List, error) {
+	var out listJSON
+	if err := b.doJSON(ctx, http.MethodPost, "/lists", createListRequest{Name: name}, &out); err != nil {
+		return nil, fmt.Errorf("failed to create list: %w", err)
+	}
+	return out.toList(), nil
+}
+
+// UpdateList updates a list's name, color and description
+func (b *Backend) UpdateList(ctx context.Context, list *backend.List) (*backend.List, error) {
+	var out listJSON
+	if err := b.doJSON(ctx, http.MethodPut, "/lists/"+url.PathEscape(list.ID), listToJSON(list), &out); err != nil {
+		return nil, fmt.Errorf("failed to update list: %w", err)
+	}
+	return out.toList(), nil
+}
+
+// DeleteList deletes a list (moved to trash when the server supports it)
+func (b *Backend) DeleteList(ctx context.Context, listID string) error {
+	if err := b.doJSON(ctx, http.MethodDelete, "/lists/"+url.PathEscape(listID), nil, nil); err != nil {
+		return fmt.Errorf("failed to delete list: %w", err)
+	}
+	return nil
+}
+
+// GetDeletedLists returns the lists in the server's trash
+func (b *Backend) GetDeletedLists(ctx context.Context) ([]backend.List, error) {
+	return b.getLists(ctx, "/trash")
+}
+
+// GetDeletedListByName returns a list in the trash by name, or nil
+func (b *Backend) GetDeletedListByName(ctx context.Context, name string) (*backend.List, error) {
+	return b.getList(ctx, "/trash/by-name?name="+url.QueryEscape(name))
+}
+
+// RestoreList restores a list from the trash
+func (b *Backend) RestoreList(ctx context.Context, listID string) error {
+	if err := b.doJSON(ctx, http.MethodPost, "/trash/"+url.PathEscape(listID)+"/restore", nil, nil); err != nil {
+		return fmt.Errorf("failed to restore list: %w", err)
+	}
+	return nil
+}
+
+// PurgeList permanently deletes a list from the trash
+func (b *Backend) PurgeList(ctx context.Context, listID string) error {
+	if err := b.doJSON(ctx, http.MethodDelete, "/trash/"+url.PathEscape(listID), nil, nil); err != nil {
+		return fmt.Errorf("failed to purge list: %w", err)
+	}
+	return nil
+}
+
+// getLists fetches a list collection
+func (b *Backend) getLists(ctx context.Context, path string) ([]backend.List, error) {
+	var out []listJSON
+	if err := b.doJSON(ctx, http.MethodGet, path, nil, &out); err != nil {
+		return nil, fmt.Errorf("failed to get lists: %w", err)
+	}
+	lists := make([]backend.List, len(out))
+	for i := range out {
+		lists[i] = *out[i].toList()
+	}
+	return lists, nil
+}
+
+// getList fetches a single list, returning nil if it does not exist
+func (b *Backend) getList(ctx context.Context, path string) (*backend.List, error) {
+	var out listJSON
+	if err := b.doJSON(ctx, http.MethodGet, path, nil, &out); err != nil {
+		if errors.Is(err, errNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get list: %w", err)
+	}
+	return out.toList(), nil
+}
+
+// =============================================================================
+// Task Operations
+// =============================================================================
+
+// GetTasks returns all tasks of a list
+func (b *Backend) GetTasks(ctx context.Context, listID string) ([]backend.Task, error) {
+	var out []taskJSON
+	if err := b.doJSON(ctx, http.MethodGet, tasksPath(listID), nil, &out); err != nil {
+		if errors.Is(err, errNotFound) {
+			return nil, fmt.Errorf("list not found: %s", listID)
+		}
+		return nil, fmt.Errorf("failed to get tasks: %w", err)
+	}
+	tasks := make([]backend.Task, 0, len(out))
+	for i := range out {
+		task, err := out[i].toTask()
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, *task)
+	}
+	return tasks, nil
+}
+
+// GetTask returns a task by ID, or nil if it does not exist
+func (b *Backend) GetTask(ctx context.Context, listID, taskID string) (*backend.Task, error) {
+	var out taskJSON
+	if err := b.doJSON(ctx, http.MethodGet, tasksPath(listID)+"/"+url.PathEscape(taskID), nil, &out); err != nil {
+		if errors.Is(err, errNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get task: %w", err)
+	}
+	return out.toTask()
+}
+
+// CreateTask creates a task in a list
+func (b *Backend) CreateTask(ctx context.Context, listID string, task *backend.Task) (*backend.Task, error) {
+	var out taskJSON
+	if err := b.doJSON(ctx, http.MethodPost, tasksPath(listID), taskToJSON(task), &out); err != nil {
+		return nil, fmt.Errorf("failed to create task: %w", err)
+	}
+	return out.toTask()
+}
+
+// UpdateTask updates a task
+func (b *Backend) UpdateTask(ctx context.Context, listID string, task *backend.Task) (*backend.Task, error) {
+	var out taskJSON
+	if err := b.doJSON(ctx, http.MethodPut, tasksPath(listID)+"/"+url.PathEscape(task.ID), taskToJSON(task), &out); err != nil {
+		if errors.Is(err, errNotFound) {
+			return nil, fmt.Errorf("task not found: %s", task.ID)
+		}
+		return nil, fmt.Errorf("failed to update task: %w", err)
+	}
+	return out.toTask()
+}
+
+// DeleteTask deletes a task
+func (b *Backend) DeleteTask(ctx context.Context, listID, taskID string) error {
+	if err := b.doJSON(ctx, http.MethodDelete, tasksPath(listID)+"/"+url.PathEscape(taskID), nil, nil); err != nil {
+		if errors.Is(err, errNotFound) {
+			return fmt.Errorf("task not found: %s", taskID)
+		}
+		return fmt.Errorf("failed to delete task: %w", err)
+	}
+	return nil
+}
+
+// tasksPath returns the path of a list's task collection
+func tasksPath(listID string) string {
+	return "/lists/" + url.PathEscape(listID) + "/tasks"
+}
+
+// SupportsTrash reports whether the server's backend keeps deleted lists in a
+// trash
+func (b *Backend) SupportsTrash() bool {
+	return b.serverInfo().SupportsTrash
+}
+
+// StoresCustomStatuses reports whether the server's backend keeps custom
+// statuses as-is
+func (b *Backend) StoresCustomStatuses() bool {
+	return b.serverInfo().StoresCustomStatuses
+}
+
+// serverInfo asks the server about its backend once. If it cannot be reached,
+// every capability is assumed unsupported.
+func (b *Backend) serverInfo() serverInfo {
+	b.infoOnce.Do(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := b.doJSON(ctx, http.MethodGet, "/info", nil, &b.info); err != nil {
+			utils.Debugf("remote: failed to get server info: %v", err)
+		}
+	})
+	return b.info
+}
+
+// Verify interface compliance at compile time
+var (
+	_ backend.TaskManager        = (*Backend)(nil)
+	_ backend.RawRequester       = (*Backend)(nil)
+	_ backend.CustomStatusStorer = (*Backend)(nil)
+)
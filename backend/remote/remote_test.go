@@ -0,0 +1,192 @@
+package remote_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"todoat/backend"
+	"todoat/backend/remote"
+	"todoat/backend/sqlite"
+)
+
+// newTestServer serves an in-memory sqlite backend and returns it with a client
+func newTestServer(t *testing.T, wrap func(backend.TaskManager) backend.TaskManager) (backend.TaskManager, *remote.Backend) {
+	t.Helper()
+	be, err := sqlite.New(":memory:")
+	if err != nil {
+		t.Fatalf("sqlite.New error: %v", err)
+	}
+	t.Cleanup(func() { _ = be.Close() })
+	var tm backend.TaskManager = be
+	if wrap != nil {
+		tm = wrap(tm)
+	}
+	srv := httptest.NewServer(remote.NewHandler(tm, "secret"))
+	t.Cleanup(srv.Close)
+
+	client, err := remote.New(remote.Config{URL: srv.URL, Token: "secret", AllowHTTP: true})
+	if err != nil {
+		t.Fatalf("remote.New error: %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+	return be, client
+}
+
+// TestRemoteBackendRoundTrip verifies lists and tasks made through the client land in the served backend
+func TestRemoteBackendRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	server, client := newTestServer(t, nil)
+
+	list, err := client.CreateList(ctx, "Home")
+	if err != nil {
+		t.Fatalf("CreateList error: %v", err)
+	}
+	if got, _ := server.GetListByName(ctx, "Home"); got == nil || got.ID != list.ID {
+		t.Fatalf("server list = %+v, want ID %s", got, list.ID)
+	}
+
+	due := time.Date(2026, 3, 14, 0, 0, 0, 0, time.Local)
+	start := time.Date(2026, 3, 10, 9, 30, 0, 0, time.Local)
+	task, err := client.CreateTask(ctx, list.ID, &backend.Task{
+		Summary:    "Water plants",
+		Priority:   3,
+		DueDate:    &due,
+		StartDate:  &start,
+		Categories: "home",
+		Metadata:   map[string]string{"room": "kitchen"},
+	})
+	if err != nil {
+		t.Fatalf("CreateTask error: %v", err)
+	}
+	got, err := client.GetTask(ctx, list.ID, task.ID)
+	if err != nil || got == nil {
+		t.Fatalf("GetTask = %v, %v", got, err)
+	}
+	if got.Summary != "Water plants" || got.Priority != 3 || got.Categories != "home" || got.Metadata["room"] != "kitchen" {
+		t.Errorf("GetTask = %+v", got)
+	}
+	if got.DueDate == nil || !got.DueDate.Equal(due) || got.StartDate == nil || !got.StartDate.Equal(start) {
+		t.Errorf("dates = %v, %v; want %v, %v", got.DueDate, got.StartDate, due, start)
+	}
+
+	got.Status = backend.StatusCompleted
+	if _, err := client.UpdateTask(ctx, list.ID, got); err != nil {
+		t.Fatalf("UpdateTask error: %v", err)
+	}
+	if stored, _ := server.GetTask(ctx, list.ID, task.ID); stored == nil || stored.Status != backend.StatusCompleted {
+		t.Errorf("server task = %+v, want completed", stored)
+	}
+
+	if missing, err := client.GetTask(ctx, list.ID, "nope"); missing != nil || err != nil {
+		t.Errorf("GetTask(missing) = %v, %v; want nil, nil", missing, err)
+	}
+	if missing, err := client.GetListByName(ctx, "Nope"); missing != nil || err != nil {
+		t.Errorf("GetListByName(missing) = %v, %v; want nil, nil", missing, err)
+	}
+
+	if err := client.DeleteTask(ctx, list.ID, task.ID); err != nil {
+		t.Fatalf("DeleteTask error: %v", err)
+	}
+	if tasks, err := client.GetTasks(ctx, list.ID); err != nil || len(tasks) != 0 {
+		t.Errorf("GetTasks after delete = %d, %v", len(tasks), err)
+	}
+
+	if !client.SupportsTrash() {
+		t.Fatal("SupportsTrash should follow the sqlite server")
+	}
+	if err := client.DeleteList(ctx, list.ID); err != nil {
+		t.Fatalf("DeleteList error: %v", err)
+	}
+	if deleted, err := client.GetDeletedListByName(ctx, "Home"); err != nil || deleted == nil {
+		t.Fatalf("GetDeletedListByName = %v, %v", deleted, err)
+	}
+	if err := client.RestoreList(ctx, list.ID); err != nil {
+		t.Fatalf("RestoreList error: %v", err)
+	}
+	if lists, err := client.GetLists(ctx); err != nil || len(lists) != 1 {
+		t.Errorf("GetLists after restore = %d, %v", len(lists), err)
+	}
+}
+
+// TestRemoteBackendAuthAndReadOnly verifies a wrong token is rejected and read-only servers report ErrReadOnly
+func TestRemoteBackendAuthAndReadOnly(t *testing.T) {
+	ctx := context.Background()
+	_, client := newTestServer(t, func(tm backend.TaskManager) backend.TaskManager {
+		return backend.NewReadOnly(tm, "read_only: true")
+	})
+
+	if _, err := client.GetLists(ctx); err != nil {
+		t.Fatalf("GetLists error: %v", err)
+	}
+	if _, err := client.CreateList(ctx, "Home"); !errors.Is(err, backend.ErrReadOnly) {
+		t.Errorf("CreateList error = %v, want ErrReadOnly", err)
+	}
+
+	srv := httptest.NewServer(remote.NewHandler(nil, "secret"))
+	defer srv.Close()
+	bad, err := remote.New(remote.Config{URL: srv.URL, Token: "wrong", AllowHTTP: true})
+	if err != nil {
+		t.Fatalf("remote.New error: %v", err)
+	}
+	if _, err := bad.GetLists(ctx); err == nil {
+		t.Error("GetLists with a wrong token should fail")
+	}
+}
+
+// TestRemoteNewValidatesConfig verifies URL, token and scheme checks
+func TestRemoteNewValidatesConfig(t *testing.T) {
+	tests := map[string]remote.Config{
+		"missing url":   {Token: "t"},
+		"missing token": {URL: "https://home:8766"},
+		"plain http":    {URL: "http://home:8766", Token: "t"},
+		"bad scheme":    {URL: "ftp://home", Token: "t"},
+		"missing ca":    {URL: "https://home:8766", Token: "t", CACert: "/nonexistent/ca.pem"},
+	}
+	for name, cfg := range tests {
+		if _, err := remote.New(cfg); err == nil {
+			t.Errorf("%s: remote.New should fail", name)
+		}
+	}
+	if _, err := remote.New(remote.Config{URL: "home:8766", Token: "t"}); err != nil {
+		t.Errorf("remote.New without scheme error: %v", err)
+	}
+}
+
+// TestLockWrites verifies that only requests changing data take the lock and
+// that a lock failure is reported as 503
+func TestLockWrites(t *testing.T) {
+	locked := 0
+	lockErr := error(nil)
+	handler := remote.LockWrites(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}), func(fn func() error) error {
+		locked++
+		if lockErr != nil {
+			return lockErr
+		}
+		return fn()
+	})
+
+	for _, tt := range []struct {
+		method string
+		locks  int
+	}{{http.MethodGet, 0}, {http.MethodPost, 1}, {http.MethodDelete, 1}} {
+		locked = 0
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(tt.method, remote.APIPrefix+"/lists", nil))
+		if rec.Code != http.StatusNoContent || locked != tt.locks {
+			t.Errorf("%s: status %d with %d locks, want %d with %d", tt.method, rec.Code, locked, http.StatusNoContent, tt.locks)
+		}
+	}
+
+	lockErr = errors.New("timed out")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, remote.APIPrefix+"/lists/1", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("lock failure: status %d, want 503", rec.Code)
+	}
+}
@@ -0,0 +1,310 @@
+package remote
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+
+	"todoat/backend"
+	"todoat/internal/utils"
+)
+
+// server serves a TaskManager over the JSON API used by Backend
+type server struct {
+	tm    backend.TaskManager
+	token string
+	mu    sync.Mutex // Serializes access to tm, which is not safe for concurrent use
+}
+
+// NewHandler returns an HTTP handler exposing tm under APIPrefix. Every request
+// must carry "Authorization: Bearer <token>". A read-only tm answers writes with
+// 403 and the read_only code.
+func NewHandler(tm backend.TaskManager, token string) http.Handler {
+	s := &server{tm: tm, token: token}
+	mux := http.NewServeMux()
+	p := APIPrefix
+	mux.HandleFunc("GET "+p+"/info", s.handleInfo)
+	mux.HandleFunc("GET "+p+"/lists", s.handleGetLists)
+	mux.HandleFunc("POST "+p+"/lists", s.handleCreateList)
+	mux.HandleFunc("GET "+p+"/lists/by-name", s.handleGetListByName)
+	mux.HandleFunc("GET "+p+"/lists/{id}", s.handleGetList)
+	mux.HandleFunc("PUT "+p+"/lists/{id}", s.handleUpdateList)
+	mux.HandleFunc("DELETE "+p+"/lists/{id}", s.handleDeleteList)
+	mux.HandleFunc("GET "+p+"/trash", s.handleGetDeletedLists)
+	mux.HandleFunc("GET "+p+"/trash/by-name", s.handleGetDeletedListByName)
+	mux.HandleFunc("POST "+p+"/trash/{id}/restore", s.handleRestoreList)
+	mux.HandleFunc("DELETE "+p+"/trash/{id}", s.handlePurgeList)
+	mux.HandleFunc("GET "+p+"/lists/{id}/tasks", s.handleGetTasks)
+	mux.HandleFunc("POST "+p+"/lists/{id}/tasks", s.handleCreateTask)
+	mux.HandleFunc("GET "+p+"/lists/{id}/tasks/{tid}", s.handleGetTask)
+	mux.HandleFunc("PUT "+p+"/lists/{id}/tasks/{tid}", s.handleUpdateTask)
+	mux.HandleFunc("DELETE "+p+"/lists/{id}/tasks/{tid}", s.handleDeleteTask)
+	return s.authenticate(mux)
+}
+
+// LockWrites wraps a handler so requests that change lists or tasks run under
+// lock, which serializes them with other processes writing the same database.
+// A request whose lock cannot be taken fails with 503.
+func LockWrites(next http.Handler, lock func(fn func() error) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+		err := lock(func() error {
+			next.ServeHTTP(w, r)
+			return nil
+		})
+		if err != nil {
+			writeError(w, http.StatusServiceUnavailable, codeInternal, err.Error())
+		}
+	})
+}
+
+// authenticate rejects requests without the server token
+func (s *server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			writeError(w, http.StatusUnauthorized, codeUnauthorized, "invalid or missing token")
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *server) handleInfo(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, serverInfo{
+		SupportsTrash:        s.tm.SupportsTrash(),
+		StoresCustomStatuses: backend.StoresCustomStatuses(s.tm),
+	})
+}
+
+func (s *server) handleGetLists(w http.ResponseWriter, r *http.Request) {
+	lists, err := s.tm.GetLists(r.Context())
+	s.writeLists(w, lists, err)
+}
+
+func (s *server) handleGetDeletedLists(w http.ResponseWriter, r *http.Request) {
+	lists, err := s.tm.GetDeletedLists(r.Context())
+	s.writeLists(w, lists, err)
+}
+
+func (s *server) handleGetList(w http.ResponseWriter, r *http.Request) {
+	list, err := s.tm.GetList(r.Context(), r.PathValue("id"))
+	s.writeList(w, http.StatusOK, list, err)
+}
+
+func (s *server) handleGetListByName(w http.ResponseWriter, r *http.Request) {
+	list, err := s.tm.GetListByName(r.Context(), r.URL.Query().Get("name"))
+	s.writeList(w, http.StatusOK, list, err)
+}
+
+func (s *server) handleGetDeletedListByName(w http.ResponseWriter, r *http.Request) {
+	list, err := s.tm.GetDeletedListByName(r.Context(), r.URL.Query().Get("name"))
+	s.writeList(w, http.StatusOK, list, err)
+}
+
+func (s *server) handleCreateList(w http.ResponseWriter, r *http.Request) {
+	var req createListRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		writeError(w, http.StatusBadRequest, codeBadRequest, "list name is required")
+		return
+	}
+	list, err := s.tm.CreateList(r.Context(), req.Name)
+	s.writeList(w, http.StatusCreated, list, err)
+}
+
+func (s *server) handleUpdateList(w http.ResponseWriter, r *http.Request) {
+	var req listJSON
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if !s.listExists(r.Context(), w, r.PathValue("id")) {
+		return
+	}
+	list := req.toList()
+	list.ID = r.PathValue("id")
+	updated, err := s.tm.UpdateList(r.Context(), list)
+	s.writeList(w, http.StatusOK, updated, err)
+}
+
+func (s *server) handleDeleteList(w http.ResponseWriter, r *http.Request) {
+	if !s.listExists(r.Context(), w, r.PathValue("id")) {
+		return
+	}
+	s.writeResult(w, s.tm.DeleteList(r.Context(), r.PathValue("id")))
+}
+
+func (s *server) handleRestoreList(w http.ResponseWriter, r *http.Request) {
+	s.writeResult(w, s.tm.RestoreList(r.Context(), r.PathValue("id")))
+}
+
+func (s *server) handlePurgeList(w http.ResponseWriter, r *http.Request) {
+	s.writeResult(w, s.tm.PurgeList(r.Context(), r.PathValue("id")))
+}
+
+func (s *server) handleGetTasks(w http.ResponseWriter, r *http.Request) {
+	if !s.listExists(r.Context(), w, r.PathValue("id")) {
+		return
+	}
+	tasks, err := s.tm.GetTasks(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeBackendError(w, err)
+		return
+	}
+	out := make([]taskJSON, len(tasks))
+	for i := range tasks {
+		out[i] = taskToJSON(&tasks[i])
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *server) handleGetTask(w http.ResponseWriter, r *http.Request) {
+	task, err := s.tm.GetTask(r.Context(), r.PathValue("id"), r.PathValue("tid"))
+	s.writeTask(w, http.StatusOK, task, err)
+}
+
+func (s *server) handleCreateTask(w http.ResponseWriter, r *http.Request) {
+	task, ok := readTask(w, r)
+	if !ok || !s.listExists(r.Context(), w, r.PathValue("id")) {
+		return
+	}
+	task.ListID = r.PathValue("id")
+	created, err := s.tm.CreateTask(r.Context(), task.ListID, task)
+	s.writeTask(w, http.StatusCreated, created, err)
+}
+
+func (s *server) handleUpdateTask(w http.ResponseWriter, r *http.Request) {
+	task, ok := readTask(w, r)
+	if !ok {
+		return
+	}
+	existing, err := s.tm.GetTask(r.Context(), r.PathValue("id"), r.PathValue("tid"))
+	if err != nil || existing == nil {
+		s.writeTask(w, http.StatusOK, existing, err)
+		return
+	}
+	task.ID = r.PathValue("tid")
+	task.ListID = r.PathValue("id")
+	updated, err := s.tm.UpdateTask(r.Context(), task.ListID, task)
+	s.writeTask(w, http.StatusOK, updated, err)
+}
+
+func (s *server) handleDeleteTask(w http.ResponseWriter, r *http.Request) {
+	existing, err := s.tm.GetTask(r.Context(), r.PathValue("id"), r.PathValue("tid"))
+	if err != nil || existing == nil {
+		s.writeTask(w, http.StatusOK, existing, err)
+		return
+	}
+	s.writeResult(w, s.tm.DeleteTask(r.Context(), r.PathValue("id"), r.PathValue("tid")))
+}
+
+// listExists writes a 404 (or the lookup error) and returns false if listID
+// is not an active list
+func (s *server) listExists(ctx context.Context, w http.ResponseWriter, listID string) bool {
+	list, err := s.tm.GetList(ctx, listID)
+	if err != nil {
+		writeBackendError(w, err)
+		return false
+	}
+	if list == nil {
+		writeError(w, http.StatusNotFound, codeNotFound, "list not found: "+listID)
+		return false
+	}
+	return true
+}
+
+func (s *server) writeLists(w http.ResponseWriter, lists []backend.List, err error) {
+	if err != nil {
+		writeBackendError(w, err)
+		return
+	}
+	out := make([]listJSON, len(lists))
+	for i := range lists {
+		out[i] = listToJSON(&lists[i])
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *server) writeList(w http.ResponseWriter, status int, list *backend.List, err error) {
+	switch {
+	case err != nil:
+		writeBackendError(w, err)
+	case list == nil:
+		writeError(w, http.StatusNotFound, codeNotFound, "list not found")
+	default:
+		writeJSON(w, status, listToJSON(list))
+	}
+}
+
+func (s *server) writeTask(w http.ResponseWriter, status int, task *backend.Task, err error) {
+	switch {
+	case err != nil:
+		writeBackendError(w, err)
+	case task == nil:
+		writeError(w, http.StatusNotFound, codeNotFound, "task not found")
+	default:
+		writeJSON(w, status, taskToJSON(task))
+	}
+}
+
+func (s *server) writeResult(w http.ResponseWriter, err error) {
+	if err != nil {
+		writeBackendError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// readTask decodes a task body, writing a 400 on invalid input
+func readTask(w http.ResponseWriter, r *http.Request) (*backend.Task, bool) {
+	var req taskJSON
+	if !readJSON(w, r, &req) {
+		return nil, false
+	}
+	task, err := req.toTask()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, codeBadRequest, err.Error())
+		return nil, false
+	}
+	return task, true
+}
+
+// readJSON decodes the request body, writing a 400 on invalid JSON
+func readJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 10<<20)).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, codeBadRequest, "invalid JSON body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+// writeBackendError reports a backend error, keeping read-only rejections apart
+func writeBackendError(w http.ResponseWriter, err error) {
+	if errors.Is(err, backend.ErrReadOnly) {
+		writeError(w, http.StatusForbidden, codeReadOnly, err.Error())
+		return
+	}
+	utils.Component("serve").Warn("request failed", "error", err)
+	writeError(w, http.StatusInternalServerError, codeInternal, err.Error())
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, APIError{Message: message, Code: code})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -1,14 +1,23 @@
 package sqlite_test
 
 import (
+	"context"
 	"encoding/json"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"runtime"
 	"strconv"
 	"strings"
 	"testing"
 	"time"
 
+	"todoat/backend"
+	"todoat/backend/remote"
+	"todoat/backend/sqlite"
+	"todoat/internal/reminder"
 	"todoat/internal/testutil"
+	"todoat/internal/utils"
 )
 
 // =============================================================================
@@ -696,6 +705,37 @@ func TestListViewShowsColorSQLiteCLI(t *testing.T) {
 	testutil.AssertContains(t, stdout, "#0066CC")
 }
 
+// TestListViewColumnsAndSortSQLiteCLI verifies --columns, --sort and the totals footer of the list view
+func TestListViewColumnsAndSortSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+
+	cli.MustExecute("-y", "list", "create", "Errands", "--description", "Things to pick up in town on the way home")
+	cli.MustExecute("-y", "Work", "add", "Write report")
+	cli.MustExecute("-y", "Work", "add", "Review")
+
+	stdout := cli.MustExecute("-y", "list")
+	testutil.AssertContains(t, stdout, "MODIFIED")
+	testutil.AssertNotContains(t, stdout, "COLOR")
+	testutil.AssertContains(t, stdout, "Total: 2 lists, 2 tasks")
+
+	stdout = cli.MustExecute("-y", "list", "--sort", "tasks", "--columns", "name,description,backend")
+	testutil.AssertContains(t, stdout, "NAME    DESCRIPTION                    BACKEND")
+	testutil.AssertContains(t, stdout, "Things to pick up in town on …")
+	if strings.Index(stdout, "Work") > strings.Index(stdout, "Errands") {
+		t.Errorf("--sort tasks should show Work first, got:\n%s", stdout)
+	}
+
+	stdout = cli.MustExecute("-y", "list", "--sort", "name")
+	if strings.Index(stdout, "Errands") > strings.Index(stdout, "Work") {
+		t.Errorf("--sort name should show Errands first, got:\n%s", stdout)
+	}
+
+	_, stderr := cli.ExecuteAndFail("-y", "list", "--columns", "owner")
+	testutil.AssertContains(t, stderr, "unknown column 'owner'")
+	_, stderr = cli.ExecuteAndFail("-y", "list", "--sort", "size")
+	testutil.AssertContains(t, stderr, "invalid --sort value")
+}
+
 // TestNoArgsShowsListsSQLiteCLI verifies that running todoat without arguments shows available lists
 // Issue #0: todoat should show available list when run without args
 func TestNoArgsShowsListsSQLiteCLI(t *testing.T) {
@@ -1730,6 +1770,530 @@ func TestListRestoreDuplicateNameSQLiteCLI(t *testing.T) {
 	testutil.AssertResultCode(t, stdout, testutil.ResultError)
 }
 
+// TestListArchiveSQLiteCLI verifies `todoat list archive` hides a list but keeps its tasks
+func TestListArchiveSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+
+	cli.MustExecute("-y", "list", "create", "OldProject")
+	cli.MustExecute("-y", "OldProject", "add", "Final report")
+
+	// Populate the list cache before archiving
+	testutil.AssertContains(t, cli.MustExecute("-y", "list"), "OldProject")
+
+	stdout := cli.MustExecute("-y", "list", "archive", "OldProject")
+	testutil.AssertContains(t, stdout, "Archived list: OldProject")
+	testutil.AssertResultCode(t, stdout, testutil.ResultActionCompleted)
+
+	// Hidden from the default listing, shown with --archived
+	testutil.AssertNotContains(t, cli.MustExecute("-y", "list"), "OldProject")
+	stdout = cli.MustExecute("-y", "list", "--archived")
+	testutil.AssertContains(t, stdout, "OldProject")
+
+	// Not in trash, and tasks are still readable
+	testutil.AssertNotContains(t, cli.MustExecute("-y", "list", "trash"), "OldProject")
+	testutil.AssertContains(t, cli.MustExecute("-y", "OldProject"), "Final report")
+
+	// Adding to an archived list does not create a duplicate active list
+	cli.MustExecute("-y", "OldProject", "add", "Late note")
+	testutil.AssertNotContains(t, cli.MustExecute("-y", "list"), "OldProject")
+
+	stdout = cli.MustExecute("-y", "list", "unarchive", "OldProject")
+	testutil.AssertContains(t, stdout, "Unarchived list: OldProject")
+	testutil.AssertContains(t, cli.MustExecute("-y", "list"), "OldProject")
+	testutil.AssertNotContains(t, cli.MustExecute("-y", "list", "--archived"), "OldProject")
+}
+
+// TestListArchiveJSONSQLiteCLI verifies JSON output of archive and `list --archived`
+func TestListArchiveJSONSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+
+	cli.MustExecute("-y", "list", "create", "JSONArchive")
+	cli.MustExecute("-y", "JSONArchive", "add", "Task")
+
+	stdout := cli.MustExecute("-y", "--json", "list", "archive", "JSONArchive")
+	testutil.AssertContains(t, stdout, `"action":"archived"`)
+
+	stdout = cli.MustExecute("-y", "--json", "list", "--archived")
+	testutil.AssertContains(t, stdout, `"name":"JSONArchive"`)
+	testutil.AssertContains(t, stdout, `"tasks":1`)
+	testutil.AssertContains(t, stdout, `"archived_at"`)
+}
+
+// TestListArchiveErrorsSQLiteCLI verifies archive/unarchive validation
+func TestListArchiveErrorsSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+
+	cli.MustExecute("-y", "list", "create", "ArchiveErrors")
+
+	_, stderr := cli.ExecuteAndFail("-y", "list", "unarchive", "ArchiveErrors")
+	testutil.AssertContains(t, stderr, "is not archived")
+
+	cli.MustExecute("-y", "list", "archive", "ArchiveErrors")
+	_, stderr = cli.ExecuteAndFail("-y", "list", "archive", "ArchiveErrors")
+	testutil.AssertContains(t, stderr, "already archived")
+
+	_, stderr = cli.ExecuteAndFail("-y", "list", "archive", "NoSuchList")
+	testutil.AssertContains(t, stderr, "not found")
+}
+
+// TestListDefaultsSQLiteCLI verifies per-list default tags and priority are applied on add unless overridden
+func TestListDefaultsSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+
+	cli.MustExecute("-y", "list", "create", "Work")
+	stdout := cli.MustExecute("-y", "list", "update", "Work", "--default-tags", "work", "--default-priority", "3", "--default-sort", "priority:desc")
+	testutil.AssertResultCode(t, stdout, testutil.ResultActionCompleted)
+
+	stdout = cli.MustExecute("-y", "list", "info", "Work")
+	testutil.AssertContains(t, stdout, "Tags:     work")
+	testutil.AssertContains(t, stdout, "Priority: 3")
+	testutil.AssertContains(t, stdout, "Sort:     priority:desc")
+
+	cli.MustExecute("-y", "Work", "add", "Defaulted")
+	cli.MustExecute("-y", "Work", "add", "Overridden", "--priority", "7", "--tag", "urgent")
+
+	stdout = cli.MustExecute("-y", "--json", "Work")
+	testutil.AssertContains(t, stdout, `"priority":3`)
+	testutil.AssertContains(t, stdout, `"priority":7`)
+	testutil.AssertContains(t, stdout, `"work"`)
+	testutil.AssertContains(t, stdout, `"urgent"`)
+
+	// Default sort: highest priority first
+	stdout = cli.MustExecute("-y", "Work")
+	if strings.Index(stdout, "Overridden") > strings.Index(stdout, "Defaulted") {
+		t.Errorf("expected default sort priority:desc to list Overridden first:\n%s", stdout)
+	}
+
+	// Clearing defaults
+	cli.MustExecute("-y", "list", "update", "Work", "--default-tags", "", "--default-priority", "0", "--default-sort", "")
+	testutil.AssertNotContains(t, cli.MustExecute("-y", "list", "info", "Work"), "Defaults:")
+}
+
+// TestListDefaultViewSQLiteCLI verifies the per-list default view is used unless -v is given
+func TestListDefaultViewSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+
+	cli.MustExecute("-y", "list", "create", "Work")
+	cli.MustExecute("-y", "Work", "add", "Task with notes", "--description", "hidden by default")
+	testutil.AssertNotContains(t, cli.MustExecute("-y", "Work"), "hidden by default")
+
+	cli.MustExecute("-y", "list", "update", "Work", "--default-view", "all")
+	testutil.AssertContains(t, cli.MustExecute("-y", "Work"), "hidden by default")
+	testutil.AssertNotContains(t, cli.MustExecute("-y", "Work", "-v", "default"), "hidden by default")
+
+	_, stderr := cli.ExecuteAndFail("-y", "list", "update", "Work", "--default-view", "nosuchview")
+	testutil.AssertContains(t, stderr, "invalid --default-view")
+	_, stderr = cli.ExecuteAndFail("-y", "list", "update", "Work", "--default-sort", "priority:sideways")
+	testutil.AssertContains(t, stderr, "invalid --default-sort")
+	_, stderr = cli.ExecuteAndFail("-y", "list", "update", "Work", "--default-backend", "nextcloud")
+	testutil.AssertContains(t, stderr, "lists cannot have a default backend")
+}
+
+// TestListFlagSQLiteCLI verifies -L/--list selects the list so positional arguments are [action] [task]
+func TestListFlagSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+
+	stdout := cli.MustExecute("-y", "-L", "Work", "add", "Buy milk")
+	testutil.AssertResultCode(t, stdout, testutil.ResultActionCompleted)
+
+	testutil.AssertContains(t, cli.MustExecute("-y", "--list", "Work"), "Buy milk")
+	testutil.AssertContains(t, cli.MustExecute("-y", "Work"), "Buy milk")
+
+	cli.MustExecute("-y", "-L", "Work", "complete", "Buy milk")
+	testutil.AssertContains(t, cli.MustExecute("-y", "-L", "Work", "get", "--status", "DONE"), "Buy milk")
+
+	_, stderr := cli.ExecuteAndFail("-y", "-L", "Work", "Work", "add")
+	testutil.AssertContains(t, stderr, "unknown action")
+	_, stderr = cli.ExecuteAndFail("-y", "-L", "Work", "add", "Task", "extra")
+	testutil.AssertContains(t, stderr, "too many arguments")
+}
+
+// TestDefaultListSQLiteCLI verifies default_list lets the list name be omitted before an action
+func TestDefaultListSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITestWithConfig(t)
+	cli.SetFullConfig("default_list: Inbox\n")
+
+	stdout := cli.MustExecute("-y", "add", "Buy milk")
+	testutil.AssertResultCode(t, stdout, testutil.ResultActionCompleted)
+	testutil.AssertContains(t, cli.MustExecute("-y", "get"), "Buy milk")
+	testutil.AssertContains(t, cli.MustExecute("-y", "Inbox"), "Buy milk")
+
+	// Without arguments the available lists are still shown
+	testutil.AssertContains(t, cli.MustExecute("-y"), "Inbox")
+
+	// A positional list name and -L still take precedence
+	cli.MustExecute("-y", "Work", "add", "Report")
+	testutil.AssertNotContains(t, cli.MustExecute("-y", "get"), "Report")
+	testutil.AssertContains(t, cli.MustExecute("-y", "-L", "Work", "get"), "Report")
+
+	// An existing list named like an action is still addressed by name
+	cli.MustExecute("-y", "list", "create", "copy")
+	cli.MustExecute("-y", "-L", "copy", "add", "Copier paper")
+	testutil.AssertContains(t, cli.MustExecute("-y", "copy"), "Copier paper")
+}
+
+// TestShellSQLiteCLI verifies that `todoat shell` runs one command per input line
+func TestShellSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+
+	input := `list create Groceries
+Groceries add "Buy milk"
+todoat Groceries add 'Buy bread'
+Groceries complete "Buy milk"
+Groceries get -s DONE
+Missing complete "Nothing"
+shell
+exit
+Groceries add "After exit"
+`
+	stdout, stderr, exitCode := cli.ExecuteWithStdin(input, "-y", "shell")
+	testutil.AssertExitCode(t, exitCode, 0)
+	testutil.AssertContains(t, stdout, "Buy milk")
+	testutil.AssertContains(t, stdout, "already in a todoat shell")
+	// Errors of a command are reported without ending the session
+	testutil.AssertContains(t, stderr+stdout, "list not found: Missing")
+
+	tasks := cli.MustExecute("-y", "Groceries", "get", "-s", "TODO,DONE")
+	testutil.AssertContains(t, tasks, "Buy bread")
+	testutil.AssertContains(t, tasks, "Buy milk")
+	testutil.AssertNotContains(t, tasks, "After exit")
+}
+
+// TestShellMoveToBackendSQLiteCLI verifies a move into the shell's own backend keeps the session usable
+func TestShellMoveToBackendSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+
+	input := `list create Inbox
+Inbox add "File taxes"
+Inbox move "File taxes" --to-backend sqlite:Admin --create
+Inbox add "After move"
+`
+	stdout, stderr, exitCode := cli.ExecuteWithStdin(input, "-y", "-b", "sqlite", "shell")
+	testutil.AssertExitCode(t, exitCode, 0)
+	testutil.AssertContains(t, stdout, "Moved task: File taxes to sqlite:Admin")
+	testutil.AssertNotContains(t, stderr+stdout, "database is closed")
+
+	testutil.AssertContains(t, cli.MustExecute("-y", "Inbox"), "After move")
+	testutil.AssertContains(t, cli.MustExecute("-y", "Admin"), "File taxes")
+}
+
+// TestPluginRPCSQLiteCLI verifies that `todoat plugin rpc` answers one JSON command per input line
+func TestPluginRPCSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+
+	input := `{"id": 1, "args": ["list", "create", "Inbox"]}
+{"id": "two", "args": ["Inbox", "add", "Buy milk", "-p", "2"]}
+{"args": ["Inbox", "get"]}
+{"id": 4, "args": ["Missing", "complete", "Nothing"]}
+not json
+{"id": 6, "args": ["shell"]}
+`
+	stdout, _, exitCode := cli.ExecuteWithStdin(input, "plugin", "rpc")
+	testutil.AssertExitCode(t, exitCode, 0)
+
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 6 {
+		t.Fatalf("expected 6 responses, got %d:\n%s", len(lines), stdout)
+	}
+	type response struct {
+		ID       any             `json:"id"`
+		ExitCode int             `json:"exit_code"`
+		Output   json.RawMessage `json:"output"`
+		Error    string          `json:"error"`
+	}
+	responses := make([]response, len(lines))
+	for i, line := range lines {
+		if err := json.Unmarshal([]byte(line), &responses[i]); err != nil {
+			t.Fatalf("invalid response %q: %v", line, err)
+		}
+	}
+	if responses[1].ID != "two" || responses[1].ExitCode != 0 || !strings.Contains(string(responses[1].Output), `"priority":2`) {
+		t.Errorf("unexpected add response: %s", lines[1])
+	}
+	var tasks struct {
+		Tasks []struct {
+			Summary string `json:"summary"`
+		} `json:"tasks"`
+	}
+	if err := json.Unmarshal(responses[2].Output, &tasks); err != nil || len(tasks.Tasks) != 1 || tasks.Tasks[0].Summary != "Buy milk" {
+		t.Errorf("unexpected get response: %s", lines[2])
+	}
+	if responses[3].ID != float64(4) || responses[3].ExitCode != 1 || !strings.Contains(string(responses[3].Output), "list not found") {
+		t.Errorf("unexpected error response: %s", lines[3])
+	}
+	if !strings.Contains(responses[4].Error, "invalid request") || !strings.Contains(responses[5].Error, "cannot run through the plugin protocol") {
+		t.Errorf("unexpected rejected requests: %s\n%s", lines[4], lines[5])
+	}
+}
+
+// TestExternalPluginSQLiteCLI verifies that unknown commands run a todoat-<name> executable from PATH
+func TestExternalPluginSQLiteCLI(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin script needs a POSIX shell")
+	}
+	cli := testutil.NewCLITest(t)
+	binDir := filepath.Join(cli.TmpDir(), "bin")
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	script := "#!/bin/sh\necho \"args: $*\"\necho \"db: $TODOAT_DB_PATH\"\necho \"no_prompt: $TODOAT_NO_PROMPT\"\nexit 3\n"
+	if err := os.WriteFile(filepath.Join(binDir, "todoat-hello"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	stdout, stderr, exitCode := cli.Execute("-y", "hello", "world", "--loud")
+	testutil.AssertExitCode(t, exitCode, 3)
+	testutil.AssertContains(t, stdout, "args: world --loud")
+	testutil.AssertContains(t, stdout, "db: "+cli.Config().DBPath)
+	testutil.AssertContains(t, stdout, "no_prompt: 1")
+	testutil.AssertNotContains(t, stderr, "Error")
+
+	stdout = cli.MustExecute("plugin", "list")
+	testutil.AssertContains(t, stdout, filepath.Join(binDir, "todoat-hello"))
+
+	// Built-in commands take precedence over plugins of the same name
+	if err := os.WriteFile(filepath.Join(binDir, "todoat-version"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	testutil.AssertNotContains(t, cli.MustExecute("version"), "args:")
+	testutil.AssertContains(t, cli.MustExecute("plugin", "list"), "shadowed by a built-in command")
+
+	// Existing lists and -L/--list take precedence over plugins of the same name
+	if err := os.WriteFile(filepath.Join(binDir, "todoat-shopping"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	cli.MustExecute("-y", "list", "create", "Shopping")
+	stdout = cli.MustExecute("-y", "shopping", "add", "Milk")
+	testutil.AssertNotContains(t, stdout, "args:")
+	testutil.AssertContains(t, cli.MustExecute("-y", "Shopping"), "Milk")
+	stdout, stderr = cli.ExecuteAndFail("-y", "-L", "Shopping", "hello")
+	testutil.AssertNotContains(t, stdout, "args:")
+	testutil.AssertContains(t, stderr, "unknown action: hello")
+}
+
+// setFakeEditor points $EDITOR at a script that edits the file with sed
+// expressions and appends lines to it
+func setFakeEditor(t *testing.T, dir, name string, sedExprs []string, appended string) {
+	t.Helper()
+	script := "#!/bin/sh\nsed -e 's/^//'"
+	for _, expr := range sedExprs {
+		script += " -e '" + expr + "'"
+	}
+	script += " \"$1\" > \"$1.tmp\" && mv \"$1.tmp\" \"$1\"\n"
+	if appended != "" {
+		script += "printf '%b' '" + appended + "' >> \"$1\"\n"
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("EDITOR", path)
+}
+
+// TestEditTaskSQLiteCLI verifies that `todoat List edit "Task"` applies the task fields edited in $EDITOR
+func TestEditTaskSQLiteCLI(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("editor script needs a POSIX shell")
+	}
+	cli := testutil.NewCLITest(t)
+	cli.MustExecute("-y", "Work", "add", "Write report", "-p", "2", "--tag", "docs")
+
+	setFakeEditor(t, cli.TmpDir(), "edit-fields.sh", []string{
+		"s/^summary: .*/summary: Write final report/",
+		"s/^priority: .*/priority: 1/",
+		"s/^due_date: .*/due_date: 2030-01-15/",
+		"s/^tags: .*/tags: [docs, review]/",
+	}, "")
+	stdout := cli.MustExecute("-y", "Work", "edit", "Write report")
+	testutil.AssertContains(t, stdout, "Updated task: Write final report")
+	testutil.AssertResultCode(t, stdout, testutil.ResultActionCompleted)
+
+	stdout = cli.MustExecute("-y", "--json", "Work", "get")
+	testutil.AssertContains(t, stdout, `"summary":"Write final report"`)
+	testutil.AssertContains(t, stdout, `"priority":1`)
+	testutil.AssertContains(t, stdout, `"due_date":"2030-01-15`)
+	testutil.AssertContains(t, stdout, `"review"`)
+
+	// Setting the status to DONE completes the task
+	setFakeEditor(t, cli.TmpDir(), "edit-status.sh", []string{"s/^status: .*/status: DONE/"}, "")
+	stdout = cli.MustExecute("-y", "Work", "e", "Write final report")
+	testutil.AssertContains(t, stdout, "Completed task: Write final report")
+
+	// An invalid file is rejected without changing the task
+	setFakeEditor(t, cli.TmpDir(), "edit-invalid.sh", []string{"s/^priority: .*/priority: 12/"}, "")
+	_, stderr := cli.ExecuteAndFail("-y", "Work", "edit", "Write final report")
+	testutil.AssertContains(t, stderr, "invalid priority 12")
+
+	// Saving the file unchanged changes nothing
+	setFakeEditor(t, cli.TmpDir(), "edit-none.sh", nil, "")
+	stdout = cli.MustExecute("-y", "Work", "edit", "Write final report")
+	testutil.AssertContains(t, stdout, "No changes made.")
+}
+
+// TestEditAllSQLiteCLI verifies that `todoat List edit --all` applies the buffer diff: adds, edits, completions and deletions
+func TestEditAllSQLiteCLI(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("editor script needs a POSIX shell")
+	}
+	cli := testutil.NewCLITest(t)
+	cli.MustExecute("-y", "Work", "add", "Write report", "-p", "2")
+	cli.MustExecute("-y", "Work", "add", "Buy milk")
+	cli.MustExecute("-y", "Work", "add", "Old idea")
+
+	setFakeEditor(t, cli.TmpDir(), "edit-all.sh", []string{
+		"s/Write report !2/Write final report !1/",
+		"s/\\[ \\] Buy milk/[x] Buy milk/",
+		"/Old idea/d",
+	}, "- [ ] Call Bob #phone\\n  - [ ] Find number\\n")
+	stdout := cli.MustExecute("-y", "Work", "edit", "--all")
+	testutil.AssertContains(t, stdout, "Edited list Work: 2 added, 1 updated, 1 completed, 1 deleted")
+
+	stdout = cli.MustExecute("-y", "--json", "Work", "get", "-s", "TODO,DONE")
+	testutil.AssertContains(t, stdout, `"summary":"Write final report"`)
+	testutil.AssertContains(t, stdout, `"priority":1`)
+	testutil.AssertNotContains(t, stdout, "Old idea")
+	testutil.AssertContains(t, stdout, `"summary":"Call Bob"`)
+	if !strings.Contains(stdout, `"summary":"Buy milk"`) || !strings.Contains(stdout, `"status":"DONE"`) {
+		t.Errorf("expected Buy milk to be completed:\n%s", stdout)
+	}
+
+	// The indented new line became a subtask of the line above it
+	stdout = cli.MustExecute("-y", "Work", "get", "--tree")
+	testutil.AssertContains(t, stdout, "Find number")
+	var tasks struct {
+		Tasks []struct {
+			UID      string `json:"uid"`
+			Summary  string `json:"summary"`
+			ParentID string `json:"parent_id"`
+		} `json:"tasks"`
+	}
+	if err := json.Unmarshal([]byte(cli.MustExecute("-y", "--json", "Work", "get")), &tasks); err != nil {
+		t.Fatal(err)
+	}
+	parents := map[string]string{}
+	for _, task := range tasks.Tasks {
+		parents[task.Summary] = task.UID
+	}
+	for _, task := range tasks.Tasks {
+		if task.Summary == "Find number" && task.ParentID != parents["Call Bob"] {
+			t.Errorf("expected Find number under Call Bob, got parent %q", task.ParentID)
+		}
+	}
+
+	// An unknown id is rejected
+	setFakeEditor(t, cli.TmpDir(), "edit-bad-id.sh", nil, "- [ ] Ghost id:99\\n")
+	_, stderr := cli.ExecuteAndFail("-y", "Work", "edit", "--all")
+	testutil.AssertContains(t, stderr, "unknown id 99")
+}
+
+// assertOrder fails unless the summaries appear in stdout in the given order
+func assertOrder(t *testing.T, stdout string, summaries ...string) {
+	t.Helper()
+	last := -1
+	for _, s := range summaries {
+		i := strings.Index(stdout, s)
+		if i < 0 || i < last {
+			t.Fatalf("expected %q in order, got:\n%s", summaries, stdout)
+		}
+		last = i
+	}
+}
+
+// TestReorderSQLiteCLI verifies that `todoat List reorder` moves and pins tasks and that get shows the manual order
+func TestReorderSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+	cli.MustExecute("-y", "Work", "add", "Alpha")
+	cli.MustExecute("-y", "Work", "add", "Bravo")
+	cli.MustExecute("-y", "Work", "add", "Charlie")
+
+	stdout := cli.MustExecute("-y", "Work", "reorder", "Charlie", "--before", "Alpha")
+	testutil.AssertContains(t, stdout, "Moved task: Charlie before Alpha")
+	testutil.AssertResultCode(t, stdout, testutil.ResultActionCompleted)
+	assertOrder(t, cli.MustExecute("-y", "Work", "get"), "Charlie", "Alpha", "Bravo")
+
+	stdout = cli.MustExecute("-y", "Work", "reorder", "Charlie", "--after", "Bravo")
+	testutil.AssertContains(t, stdout, "Moved task: Charlie after Bravo")
+	assertOrder(t, cli.MustExecute("-y", "Work", "get"), "Alpha", "Bravo", "Charlie")
+
+	// A pinned task stays above the others, and a task moved next to it is pinned too
+	stdout = cli.MustExecute("-y", "Work", "reorder", "Bravo", "--pin")
+	testutil.AssertContains(t, stdout, "Pinned task: Bravo")
+	assertOrder(t, cli.MustExecute("-y", "Work", "get"), "Bravo", "Alpha", "Charlie")
+	cli.MustExecute("-y", "Work", "reorder", "Charlie", "--after", "Bravo")
+	stdout = cli.MustExecute("-y", "--json", "Work", "get")
+	assertOrder(t, stdout, "Bravo", "Charlie", "Alpha")
+	testutil.AssertContains(t, stdout, `"pinned":true`)
+
+	stdout = cli.MustExecute("-y", "--json", "Work", "reorder", "Bravo", "--unpin")
+	testutil.AssertContains(t, stdout, `"action":"reorder"`)
+	testutil.AssertContains(t, stdout, `"position":2`)
+	assertOrder(t, cli.MustExecute("-y", "Work", "get"), "Charlie", "Bravo", "Alpha")
+
+	_, stderr := cli.ExecuteAndFail("-y", "Work", "reorder", "Alpha")
+	testutil.AssertContains(t, stderr, "reorder requires --before <task>, --after <task>, --pin or --unpin")
+	_, stderr = cli.ExecuteAndFail("-y", "Work", "reorder", "Alpha", "--before", "Alpha")
+	testutil.AssertContains(t, stderr, "relative to itself")
+	_, stderr = cli.ExecuteAndFail("-y", "Work", "reorder", "Alpha", "--before", "Bravo", "--pin")
+	testutil.AssertContains(t, stderr, "cannot be combined")
+}
+
+// TestReorderSubtasksSQLiteCLI verifies that only siblings can be reordered relative to each other
+func TestReorderSubtasksSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+	cli.MustExecute("-y", "Work", "add", "Release")
+	cli.MustExecute("-y", "Work", "add", "Write notes", "-P", "Release")
+	cli.MustExecute("-y", "Work", "add", "Tag build", "-P", "Release")
+	cli.MustExecute("-y", "Work", "add", "Other")
+
+	cli.MustExecute("-y", "Work", "reorder", "Tag build", "--before", "Write notes")
+	assertOrder(t, cli.MustExecute("-y", "Work", "get"), "Tag build", "Write notes")
+
+	_, stderr := cli.ExecuteAndFail("-y", "Work", "reorder", "Other", "--before", "Write notes")
+	testutil.AssertContains(t, stderr, "are not siblings")
+}
+
+// TestTaskHistorySQLiteCLI verifies `todoat MyList history "Task"` shows the changes made to a task
+func TestTaskHistorySQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+	cli.MustExecute("-y", "Work", "add", "Write report", "-p", "2")
+	cli.MustExecute("-y", "Work", "update", "Write report", "-p", "5", "--due-date", "2026-11-01")
+	cli.MustExecute("-y", "Work", "complete", "Write report")
+
+	stdout := cli.MustExecute("-y", "Work", "history", "Write report")
+	testutil.AssertContains(t, stdout, "History of task: Write report")
+	testutil.AssertContains(t, stdout, "created")
+	testutil.AssertContains(t, stdout, "priority: 2 → 5")
+	testutil.AssertContains(t, stdout, "due_date: (none) → 2026-11-01")
+	testutil.AssertContains(t, stdout, "status: TODO → DONE")
+	testutil.AssertResultCode(t, stdout, testutil.ResultInfoOnly)
+
+	var output struct {
+		Task    struct{ UID string }
+		History []struct {
+			Actor  string
+			Action string
+			Field  string
+		}
+	}
+	stdout = cli.MustExecute("-y", "--json", "Work", "history", "Write report")
+	if err := json.Unmarshal([]byte(stdout), &output); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, stdout)
+	}
+	if len(output.History) != 4 || output.History[0].Action != "created" || output.History[3].Field != "status" {
+		t.Fatalf("unexpected history: %+v", output.History)
+	}
+	if !strings.Contains(output.History[0].Actor, "@") {
+		t.Errorf("expected the actor to name the user and host, got %q", output.History[0].Actor)
+	}
+
+	// A deleted task's history is still shown by its UID
+	cli.MustExecute("-y", "Work", "delete", "Write report")
+	stdout = cli.MustExecute("-y", "Work", "history", "--uid", output.Task.UID)
+	testutil.AssertContains(t, stdout, "History of task: Write report")
+	testutil.AssertContains(t, stdout, "deleted")
+}
+
 // TestListPurge verifies that `todoat -y list trash purge "Name"` permanently deletes
 func TestListPurgeSQLiteCLI(t *testing.T) {
 	cli := testutil.NewCLITest(t)
@@ -1840,7 +2404,39 @@ func TestAddSubtaskWithParentPathFlagSQLiteCLI(t *testing.T) {
 	testutil.AssertContains(t, stdout, `"Write unit tests"`)
 }
 
-// TestPathBasedHierarchyCreation verifies `todoat MyList add "A/B/C"` creates 3-level hierarchy
+// TestAddSubtaskUnderUIDSQLiteCLI verifies `todoat MyList add "Child" --under-uid <uid>` picks the exact parent
+// even when several tasks share the parent's summary
+func TestAddSubtaskUnderUIDSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+
+	cli.MustExecute("-y", "list", "create", "UnderUIDTest")
+	cli.MustExecute("-y", "UnderUIDTest", "add", "Release")
+	parentUID := extractUID(t, cli.MustExecute("-y", "--json", "UnderUIDTest", "add", "Release", "-l"))
+
+	// -P would be ambiguous here; --under-uid is not
+	stdout := cli.MustExecute("-y", "--json", "UnderUIDTest", "add", "Write notes", "--under-uid", parentUID)
+	testutil.AssertContains(t, stdout, `"parent_id":"`+parentUID+`"`)
+
+	// Slashes are not parsed as a path when the parent is given explicitly
+	stdout = cli.MustExecute("-y", "--json", "UnderUIDTest", "add", "v1/v2 diff", "--under-uid", parentUID)
+	testutil.AssertContains(t, stdout, `"summary":"v1/v2 diff"`)
+	testutil.AssertContains(t, stdout, `"parent_id":"`+parentUID+`"`)
+}
+
+// TestAddSubtaskUnderUIDNotFoundSQLiteCLI verifies --under-uid with an unknown UID fails
+func TestAddSubtaskUnderUIDNotFoundSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+
+	cli.MustExecute("-y", "list", "create", "UnderUIDMissing")
+
+	_, stderr := cli.ExecuteAndFail("-y", "UnderUIDMissing", "add", "Child", "--under-uid", "550e8400-e29b-41d4-a716-446655440000")
+	testutil.AssertContains(t, stderr, "parent task not found")
+
+	_, stderr = cli.ExecuteAndFail("-y", "UnderUIDMissing", "add", "Child", "-P", "Parent", "--under-uid", "550e8400-e29b-41d4-a716-446655440000")
+	testutil.AssertContains(t, stderr, "cannot be used together")
+}
+
+// TestPathBasedHierarchyCreation verifies `todoat MyList add "A/B/C" --path` creates 3-level hierarchy
 func TestPathBasedHierarchyCreationSQLiteCLI(t *testing.T) {
 	cli := testutil.NewCLITest(t)
 
@@ -1848,7 +2444,7 @@ func TestPathBasedHierarchyCreationSQLiteCLI(t *testing.T) {
 	cli.MustExecute("-y", "list", "create", "HierarchyTest")
 
 	// Add task with path-based hierarchy
-	stdout := cli.MustExecute("-y", "HierarchyTest", "add", "ProjectA/FeatureB/TaskC")
+	stdout := cli.MustExecute("-y", "HierarchyTest", "add", "ProjectA/FeatureB/TaskC", "--path")
 
 	testutil.AssertResultCode(t, stdout, testutil.ResultActionCompleted)
 
@@ -1966,16 +2562,74 @@ func TestLiteralSlashFlagSQLiteCLI(t *testing.T) {
 	testutil.AssertNotContains(t, stdout, `"UI"`)
 }
 
-// TestPathResolutionExisting verifies adding `A/B/C` when `A/B` exists only creates `C` under existing `B`
+// TestEscapedSlashSQLiteCLI verifies `todoat MyList add "UI\/UX/Mockups" --path` keeps escaped slashes in the summary
+func TestEscapedSlashSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+
+	cli.MustExecute("-y", "list", "create", "EscapeTest")
+
+	// Only escaped slashes: single root-level task
+	stdout := cli.MustExecute("-y", "--json", "EscapeTest", "add", `UI\/UX Review`, "--path")
+	testutil.AssertContains(t, stdout, `"summary":"UI/UX Review"`)
+	testutil.AssertNotContains(t, stdout, `"parent_id"`)
+
+	// Mixed: escaped slash inside a path component
+	stdout = cli.MustExecute("-y", "--json", "EscapeTest", "add", `UI\/UX Review/Mockups`, "--path")
+	testutil.AssertContains(t, stdout, `"summary":"Mockups"`)
+	testutil.AssertContains(t, stdout, `"parent_id"`)
+
+	stdout = cli.MustExecute("-y", "--json", "EscapeTest")
+	testutil.AssertNotContains(t, stdout, `"UI"`)
+}
+
+// TestPathHierarchyConfigEnabledSQLiteCLI verifies slashes are literal by default
+// and path_hierarchy: true parses them as a hierarchy path
+func TestPathHierarchyConfigEnabledSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITestWithConfig(t)
+	cli.SetFullConfig("default_backend: sqlite\n")
+
+	stdout := cli.MustExecute("-y", "--json", "PathConfigTest", "add", "Client/Server sync")
+	testutil.AssertContains(t, stdout, `"summary":"Client/Server sync"`)
+	testutil.AssertNotContains(t, stdout, `"parent_id"`)
+
+	cli.SetFullConfig("default_backend: sqlite\npath_hierarchy: true\n")
+	stdout = cli.MustExecute("-y", "--json", "PathConfigTest", "add", "Project/Design")
+	testutil.AssertContains(t, stdout, `"summary":"Design"`)
+	testutil.AssertContains(t, stdout, `"parent_id"`)
+
+	// -l keeps the summary literal
+	stdout = cli.MustExecute("-y", "--json", "PathConfigTest", "add", "-l", "UI/UX Review")
+	testutil.AssertContains(t, stdout, `"summary":"UI/UX Review"`)
+}
+
+// TestPathHierarchyConfigDisabledSQLiteCLI verifies path_hierarchy: false makes slashes literal unless --path is given
+func TestPathHierarchyConfigDisabledSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITestWithConfig(t)
+	cli.SetFullConfig("default_backend: sqlite\npath_hierarchy: false\n")
+
+	stdout := cli.MustExecute("-y", "--json", "PathConfigTest", "add", "Client/Server sync")
+	testutil.AssertContains(t, stdout, `"summary":"Client/Server sync"`)
+	testutil.AssertNotContains(t, stdout, `"parent_id"`)
+
+	// --path explicitly requests hierarchy creation
+	stdout = cli.MustExecute("-y", "--json", "PathConfigTest", "add", "Project/Design", "--path")
+	testutil.AssertContains(t, stdout, `"summary":"Design"`)
+	testutil.AssertContains(t, stdout, `"parent_id"`)
+
+	_, stderr := cli.ExecuteAndFail("-y", "PathConfigTest", "add", "A/B", "--path", "-l")
+	testutil.AssertContains(t, stderr, "cannot be used together")
+}
+
+// TestPathResolutionExisting verifies adding `A/B/C --path` when `A/B` exists only creates `C` under existing `B`
 func TestPathResolutionExistingSQLiteCLI(t *testing.T) {
 	cli := testutil.NewCLITest(t)
 
 	// Create a list with existing parent structure
 	cli.MustExecute("-y", "list", "create", "PathResTest")
-	cli.MustExecute("-y", "PathResTest", "add", "ExistingParent/ExistingChild")
+	cli.MustExecute("-y", "PathResTest", "add", "ExistingParent/ExistingChild", "--path")
 
 	// Add a new leaf under existing hierarchy
-	stdout := cli.MustExecute("-y", "PathResTest", "add", "ExistingParent/ExistingChild/NewGrandchild")
+	stdout := cli.MustExecute("-y", "PathResTest", "add", "ExistingParent/ExistingChild/NewGrandchild", "--path")
 
 	testutil.AssertResultCode(t, stdout, testutil.ResultActionCompleted)
 
@@ -2479,6 +3133,70 @@ func TestListExportICalendarCLI(t *testing.T) {
 	}
 }
 
+// TestListImportICalendarRoundTripCLI verifies that an iCalendar import handles folded lines,
+// escaped text, TZID dates, RELATED-TO and VALARM, and that export writes them back
+func TestListImportICalendarRoundTripCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+
+	ics := "BEGIN:VCALENDAR\r\n" +
+		"VERSION:2.0\r\n" +
+		"BEGIN:VTODO\r\n" +
+		"UID:parent-1\r\n" +
+		"SUMMARY:Plan trip\\, book hotel\\; pack bags for the long weekend away with the \r\n" +
+		" family\r\n" +
+		"DESCRIPTION:First line\\nSecond line\r\n" +
+		"CATEGORIES:travel,family\r\n" +
+		"DUE;TZID=Europe/Paris:20300301T090000\r\n" +
+		"BEGIN:VALARM\r\n" +
+		"ACTION:DISPLAY\r\n" +
+		"TRIGGER;RELATED=END:-PT15M\r\n" +
+		"END:VALARM\r\n" +
+		"END:VTODO\r\n" +
+		"BEGIN:VTODO\r\n" +
+		"UID:child-1\r\n" +
+		"SUMMARY:Book flights\r\n" +
+		"RELATED-TO;RELTYPE=PARENT:parent-1\r\n" +
+		"END:VTODO\r\n" +
+		"END:VCALENDAR\r\n"
+	importPath := cli.TmpDir() + "/Trip.ics"
+	if err := os.WriteFile(importPath, []byte(ics), 0644); err != nil {
+		t.Fatalf("failed to write import file: %v", err)
+	}
+
+	stdout := cli.MustExecute("-y", "list", "import", importPath)
+	testutil.AssertContains(t, stdout, "Imported 2 tasks")
+
+	stdout = cli.MustExecute("-y", "--json", "Trip", "get", "Plan trip")
+	testutil.AssertContains(t, stdout, "Plan trip, book hotel; pack bags for the long weekend away with the family")
+	testutil.AssertContains(t, stdout, `First line\nSecond line`)
+
+	exportPath := cli.TmpDir() + "/TripExport.ics"
+	cli.MustExecute("-y", "list", "export", "Trip", "--format", "ical", "--output", exportPath)
+	data, err := os.ReadFile(exportPath)
+	if err != nil {
+		t.Fatalf("failed to read export file: %v", err)
+	}
+	content := string(data)
+	for _, want := range []string{
+		"SUMMARY:Plan trip\\, book hotel\\; pack",
+		"DESCRIPTION:First line\\nSecond line",
+		"CATEGORIES:travel,family",
+		"DUE:20300301T080000Z",
+		"RELATED-TO;RELTYPE=PARENT:",
+		"BEGIN:VALARM",
+		"TRIGGER;RELATED=END:-PT15M",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("expected export to contain %q, got:\n%s", want, content)
+		}
+	}
+	for _, line := range strings.Split(content, "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("expected folded lines of at most 75 octets, got %q", line)
+		}
+	}
+}
+
 // TestListImport verifies that `todoat list import backup.db` restores a list from exported file
 func TestListImportCLI(t *testing.T) {
 	cli := testutil.NewCLITest(t)
@@ -2636,62 +3354,390 @@ func TestListImportDuplicateNameCLI(t *testing.T) {
 	}
 }
 
-// TestIssue43_ReimportAfterDeleteCLI verifies that importing tasks after deleting the list
-// succeeds even when the exported file contains UIDs that were soft-deleted.
-// Regression test for issue #43: Import fails with UNIQUE constraint when reimporting tasks.
-func TestIssue43_ReimportAfterDeleteCLI(t *testing.T) {
+// strictImportCSV has an invalid due date and a non-numeric priority
+const strictImportCSV = `id,summary,description,status,priority,due_date,start_date,completed,created,modified,list_id,parent_id,categories
+a1,Valid task,,NEEDS-ACTION,2,2026-03-01T00:00:00Z,,,,,,,
+a2,Bad date,,NEEDS-ACTION,1,2026-13-45,,,,,,,
+a3,Bad priority,,NEEDS-ACTION,high,,,,,,,,
+`
+
+// TestListImportLenientWarnsCLI verifies that invalid values are dropped with a warning instead of silently
+func TestListImportLenientWarnsCLI(t *testing.T) {
 	cli := testutil.NewCLITest(t)
 
-	// Step 1: Create a list with tasks
-	cli.MustExecute("-y", "list", "create", "DemoList")
-	cli.MustExecute("-y", "DemoList", "add", "Task 1")
-	cli.MustExecute("-y", "DemoList", "add", "Task 2")
+	importPath := cli.TmpDir() + "/Lenient.csv"
+	if err := os.WriteFile(importPath, []byte(strictImportCSV), 0644); err != nil {
+		t.Fatalf("failed to write import file: %v", err)
+	}
 
-	// Step 2: Export the list
-	exportPath := cli.TmpDir() + "/export.json"
-	cli.MustExecute("-y", "list", "export", "DemoList", "--format", "json", "--output", exportPath)
+	stdout, stderr, exitCode := cli.Execute("-y", "list", "import", importPath)
+	if exitCode != 0 {
+		t.Fatalf("expected lenient import to succeed, got %d: stdout=%s stderr=%s", exitCode, stdout, stderr)
+	}
+	testutil.AssertContains(t, stdout, "Imported 3 tasks")
+	testutil.AssertContains(t, stderr, "row 2, field due_date")
+	testutil.AssertContains(t, stderr, "row 3, field priority")
 
-	// Step 3: Delete the list and its tasks (soft-delete)
-	cli.MustExecute("-y", "list", "delete", "DemoList")
+	// The bad due date is dropped rather than stored as year 0001
+	stdout = cli.MustExecute("-y", "--json", "Lenient")
+	testutil.AssertNotContains(t, stdout, "0001-01-01")
+}
 
-	// Step 4: Try to import the same file - should succeed, not fail with UNIQUE constraint
-	// The fix should generate new UIDs for imported tasks
-	stdout := cli.MustExecute("-y", "list", "import", exportPath)
+// TestListImportStrictCLI verifies that --strict fails with row/field context and imports nothing
+func TestListImportStrictCLI(t *testing.T) {
+	cli := testutil.NewCLITestWithConfig(t)
 
-	// Verify import succeeded
-	testutil.AssertContains(t, stdout, "Imported")
-	testutil.AssertContains(t, stdout, "2") // 2 tasks
+	importPath := cli.TmpDir() + "/Strict.csv"
+	if err := os.WriteFile(importPath, []byte(strictImportCSV), 0644); err != nil {
+		t.Fatalf("failed to write import file: %v", err)
+	}
 
-	// Verify the tasks are actually visible
-	listOutput := cli.MustExecute("-y", "DemoList")
-	testutil.AssertContains(t, listOutput, "Task 1")
-	testutil.AssertContains(t, listOutput, "Task 2")
-}
+	_, stderr := cli.ExecuteAndFail("-y", "list", "import", importPath, "--strict")
+	testutil.AssertContains(t, stderr, "2 invalid values")
+	testutil.AssertContains(t, stderr, `row 2, field due_date: invalid value "2026-13-45"`)
+	testutil.AssertContains(t, stderr, `row 3, field priority: invalid value "high"`)
+	testutil.AssertNotContains(t, cli.MustExecute("-y", "list"), "Strict")
 
-// =============================================================================
-// Database Maintenance Tests (039-database-maintenance)
-// =============================================================================
+	// strict_parsing in config has the same effect, and --strict=false overrides it
+	cli.SetConfigValue("strict_parsing", "true")
+	_, stderr = cli.ExecuteAndFail("-y", "list", "import", importPath)
+	testutil.AssertContains(t, stderr, "strict import")
+	cli.MustExecute("-y", "list", "import", importPath, "--strict=false")
+}
 
-// TestListStatsSQLiteCLI verifies `todoat -y list stats` displays database statistics
-func TestListStatsSQLiteCLI(t *testing.T) {
+// TestListImportCSVHeaderMappingCLI verifies that a spreadsheet export from another tool imports by
+// matching its header row, with --map for columns the auto-mapper does not know
+func TestListImportCSVHeaderMappingCLI(t *testing.T) {
 	cli := testutil.NewCLITest(t)
 
-	// Create lists with varying task counts
-	cli.MustExecute("-y", "list", "create", "StatsWork")
-	cli.MustExecute("-y", "StatsWork", "add", "Task 1")
-	cli.MustExecute("-y", "StatsWork", "add", "Task 2")
-	cli.MustExecute("-y", "StatsWork", "complete", "Task 2")
+	asana := "Task ID;Name;Notes;Due Date;Completed At;Tags;Workload\n" +
+		"1;Write brief;Two pages;2026-05-01;;work,docs;Q2 launch\n" +
+		"2;Book venue;;;2026-04-02;;Q2 launch\n"
+	importPath := cli.TmpDir() + "/Asana.csv"
+	if err := os.WriteFile(importPath, []byte(asana), 0644); err != nil {
+		t.Fatalf("failed to write import file: %v", err)
+	}
 
-	cli.MustExecute("-y", "list", "create", "StatsPersonal")
-	cli.MustExecute("-y", "StatsPersonal", "add", "Task A")
+	stdout := cli.MustExecute("-y", "list", "import", importPath, "--delimiter", ";", "--map", "Workload=description")
+	testutil.AssertContains(t, stdout, "Imported 2 tasks")
 
-	// Get database stats
-	stdout := cli.MustExecute("-y", "list", "stats")
+	stdout = cli.MustExecute("-y", "--json", "Asana", "get", "Write brief")
+	testutil.AssertContains(t, stdout, `"description":"Q2 launch"`)
+	testutil.AssertContains(t, stdout, `"due_date":"2026-05-01`)
+	testutil.AssertContains(t, stdout, `"tags":["work","docs"]`)
+	stdout = cli.MustExecute("-y", "--json", "Asana", "-s", "DONE")
+	testutil.AssertContains(t, stdout, "Book venue")
 
-	// Should show total tasks
-	testutil.AssertContains(t, stdout, "3") // 3 total tasks
+	_, stderr := cli.ExecuteAndFail("-y", "list", "import", importPath, "--map", "Workload=effort")
+	testutil.AssertContains(t, stderr, `unknown field "effort"`)
+	_, stderr = cli.ExecuteAndFail("-y", "list", "import", importPath, "--format", "json", "--delimiter", ";")
+	testutil.AssertContains(t, stderr, "only apply to --format csv")
+}
 
-	// Should show tasks per list
+// TestListExportCSVMappingCLI verifies that --map selects and renames exported columns and
+// --delimiter changes the separator
+func TestListExportCSVMappingCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+
+	cli.MustExecute("-y", "list", "create", "Sheet")
+	cli.MustExecute("-y", "Sheet", "add", "Send invoice", "--due-date", "2026-06-01", "--tag", "billing")
+
+	exportPath := cli.TmpDir() + "/Sheet.csv"
+	cli.MustExecute("-y", "list", "export", "Sheet", "--format", "csv", "--output", exportPath,
+		"--map", "Title=summary,Deadline=due_date,Labels=categories", "--delimiter", "tab")
+
+	data, err := os.ReadFile(exportPath)
+	if err != nil {
+		t.Fatalf("failed to read export file: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 || lines[0] != "Title\tDeadline\tLabels" {
+		t.Fatalf("expected mapped tab-separated header, got:\n%s", data)
+	}
+	if !strings.HasPrefix(lines[1], "Send invoice\t2026-06-01") || !strings.HasSuffix(lines[1], "\tbilling") {
+		t.Errorf("expected mapped row, got %q", lines[1])
+	}
+}
+
+// markdownImportFile is a meeting-notes style checklist used by the Markdown import tests
+const markdownImportFile = `# Sprint Notes
+
+Agenda and action items from Monday.
+
+- [ ] Release 2.0 #work (due: 2026-05-01)
+  Coordinate with the ops team.
+  - [x] Write changelog
+  - [ ] Tag release #urgent (priority: 1)
+    - [ ] Build artifacts
+- [~] Update README
+* [-] Drop old API
+- plain bullet, not a task
+`
+
+// TestListImportMarkdownCLI verifies checklist items, nesting, #tags and annotations are imported
+func TestListImportMarkdownCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+
+	importPath := cli.TmpDir() + "/notes.md"
+	if err := os.WriteFile(importPath, []byte(markdownImportFile), 0644); err != nil {
+		t.Fatalf("failed to write import file: %v", err)
+	}
+
+	stdout := cli.MustExecute("-y", "list", "import", importPath)
+	testutil.AssertContains(t, stdout, "Imported 6 tasks")
+
+	stdout = cli.MustExecute("-y", "--json", "Sprint Notes", "-v", "all")
+	var result struct {
+		Tasks []struct {
+			UID         string   `json:"uid"`
+			Summary     string   `json:"summary"`
+			Description string   `json:"description"`
+			Status      string   `json:"status"`
+			Priority    int      `json:"priority"`
+			ParentID    string   `json:"parent_id"`
+			DueDate     string   `json:"due_date"`
+			Tags        []string `json:"tags"`
+		} `json:"tasks"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, stdout)
+	}
+	bySummary := make(map[string]int)
+	for i, task := range result.Tasks {
+		bySummary[task.Summary] = i
+	}
+	get := func(summary string) (i int) {
+		i, ok := bySummary[summary]
+		if !ok {
+			t.Fatalf("task %q not imported: %s", summary, stdout)
+		}
+		return i
+	}
+
+	release := result.Tasks[get("Release 2.0")]
+	if release.DueDate != "2026-05-01" || len(release.Tags) != 1 || release.Tags[0] != "work" {
+		t.Errorf("expected due date and #work tag on release, got %+v", release)
+	}
+	if release.Description != "Coordinate with the ops team." {
+		t.Errorf("expected indented text as description, got %q", release.Description)
+	}
+	changelog := result.Tasks[get("Write changelog")]
+	if changelog.Status != "DONE" || changelog.ParentID != release.UID {
+		t.Errorf("expected done subtask of release, got %+v", changelog)
+	}
+	tag := result.Tasks[get("Tag release")]
+	if tag.Priority != 1 || tag.ParentID != release.UID {
+		t.Errorf("expected priority 1 subtask of release, got %+v", tag)
+	}
+	if build := result.Tasks[get("Build artifacts")]; build.ParentID != tag.UID {
+		t.Errorf("expected grandchild of release, got %+v", build)
+	}
+	if readme := result.Tasks[get("Update README")]; readme.Status != "IN-PROGRESS" || readme.ParentID != "" {
+		t.Errorf("expected in-progress root task, got %+v", readme)
+	}
+	if api := result.Tasks[get("Drop old API")]; api.Status != "CANCELLED" {
+		t.Errorf("expected cancelled task, got %+v", api)
+	}
+}
+
+// TestListExportMarkdownCLI verifies a Markdown export can be imported back unchanged
+func TestListExportMarkdownCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+
+	importPath := cli.TmpDir() + "/notes.md"
+	if err := os.WriteFile(importPath, []byte(markdownImportFile), 0644); err != nil {
+		t.Fatalf("failed to write import file: %v", err)
+	}
+	cli.MustExecute("-y", "list", "import", importPath)
+
+	exportPath := cli.TmpDir() + "/export.md"
+	stdout := cli.MustExecute("-y", "list", "export", "Sprint Notes", "--format", "markdown", "--output", exportPath)
+	testutil.AssertContains(t, stdout, "Exported 6 tasks")
+
+	data, err := os.ReadFile(exportPath)
+	if err != nil {
+		t.Fatalf("failed to read export file: %v", err)
+	}
+	exported := string(data)
+	testutil.AssertContains(t, exported, "# Sprint Notes")
+	testutil.AssertContains(t, exported, "- [ ] Release 2.0 #work (due: 2026-05-01)")
+	testutil.AssertContains(t, exported, "  Coordinate with the ops team.")
+	testutil.AssertContains(t, exported, "  - [x] Write changelog")
+	testutil.AssertContains(t, exported, "  - [ ] Tag release #urgent (priority: 1)")
+	testutil.AssertContains(t, exported, "    - [ ] Build artifacts")
+	testutil.AssertContains(t, exported, "- [~] Update README")
+	testutil.AssertContains(t, exported, "- [-] Drop old API")
+
+	// Re-importing the export under a new name gives the same checklist
+	if err := os.WriteFile(exportPath, []byte(strings.Replace(exported, "# Sprint Notes", "# Sprint Copy", 1)), 0644); err != nil {
+		t.Fatalf("failed to rewrite export file: %v", err)
+	}
+	cli.MustExecute("-y", "list", "import", exportPath)
+	copyPath := cli.TmpDir() + "/copy.md"
+	cli.MustExecute("-y", "list", "export", "Sprint Copy", "--format", "markdown", "--output", copyPath)
+	copied, err := os.ReadFile(copyPath)
+	if err != nil {
+		t.Fatalf("failed to read export file: %v", err)
+	}
+	if want := strings.Replace(exported, "# Sprint Notes", "# Sprint Copy", 1); string(copied) != want {
+		t.Errorf("round trip changed the checklist:\n%s\nwant:\n%s", copied, want)
+	}
+}
+
+// TestListExportCompressedRoundTripCLI verifies --compress writes a gzip file that
+// import reads back, in batches of --batch-size
+func TestListExportCompressedRoundTripCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+
+	cli.MustExecute("-y", "list", "create", "Archive")
+	for _, summary := range []string{"Task A", "Task B", "Task C"} {
+		cli.MustExecute("-y", "Archive", "add", summary)
+	}
+
+	// Without --output the .gz suffix is added to the default path
+	wd, _ := os.Getwd()
+	if err := os.Chdir(cli.TmpDir()); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+	defer func() { _ = os.Chdir(wd) }()
+	stdout := cli.MustExecute("-y", "list", "export", "Archive", "--format", "csv", "--compress")
+	testutil.AssertContains(t, stdout, "Exported 3 tasks to Archive.csv.gz")
+
+	data, err := os.ReadFile(filepath.Join(cli.TmpDir(), "Archive.csv.gz"))
+	if err != nil {
+		t.Fatalf("failed to read export file: %v", err)
+	}
+	if len(data) < 2 || data[0] != 0x1f || data[1] != 0x8b {
+		t.Fatalf("export is not gzip-compressed")
+	}
+
+	copyPath := filepath.Join(cli.TmpDir(), "Copy.csv.gz")
+	if err := os.WriteFile(copyPath, data, 0644); err != nil {
+		t.Fatalf("failed to write import file: %v", err)
+	}
+	stdout = cli.MustExecute("-y", "list", "import", copyPath, "--batch-size", "2")
+	testutil.AssertContains(t, stdout, "Imported 3 tasks")
+	stdout = cli.MustExecute("-y", "Copy")
+	testutil.AssertContains(t, stdout, "Task A")
+	testutil.AssertContains(t, stdout, "Task C")
+
+	_, stderr := cli.ExecuteAndFail("-y", "list", "import", copyPath, "--batch-size", "0")
+	testutil.AssertContains(t, stderr, "--batch-size must be at least 1")
+}
+
+// TestListImportMergeIntoExistingListCLI verifies --into and --on-conflict merge an
+// import into an existing list, matching rows by normalized summary
+func TestListImportMergeIntoExistingListCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+
+	cli.MustExecute("-y", "list", "create", "Team")
+	cli.MustExecute("-y", "Team", "add", "Write report", "-p", "5")
+
+	importPath := filepath.Join(cli.TmpDir(), "Team.csv")
+	content := "summary,priority,description\nwrite  REPORT!,1,Quarterly numbers\nBook room,,\n"
+	if err := os.WriteFile(importPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write import file: %v", err)
+	}
+
+	_, stderr := cli.ExecuteAndFail("-y", "list", "import", importPath)
+	testutil.AssertContains(t, stderr, "already exists; use --on-conflict")
+
+	stdout := cli.MustExecute("-y", "list", "import", importPath, "--into", "Team", "--on-conflict", "update")
+	testutil.AssertContains(t, stdout, "Merged into 'Team': 1 created, 1 updated, 0 skipped")
+	stdout = cli.MustExecute("-y", "--json", "Team", "get")
+	testutil.AssertContains(t, stdout, "Quarterly numbers")
+	testutil.AssertContains(t, stdout, "Book room")
+
+	// Importing again matches both rows; the default strategy leaves them alone
+	stdout = cli.MustExecute("-y", "list", "import", importPath, "--on-conflict", "skip")
+	testutil.AssertContains(t, stdout, "Merged into 'Team': 0 created, 0 updated, 2 skipped")
+
+	stdout = cli.MustExecute("-y", "list", "import", importPath, "--on-conflict", "duplicate")
+	testutil.AssertContains(t, stdout, "Merged into 'Team': 2 created, 0 updated, 0 skipped")
+	stdout = cli.MustExecute("-y", "--json", "Team", "get")
+	if count := strings.Count(stdout, `"summary":"Book room"`); count != 2 {
+		t.Errorf("expected 2 'Book room' tasks after --on-conflict duplicate, got %d:\n%s", count, stdout)
+	}
+
+	_, stderr = cli.ExecuteAndFail("-y", "list", "import", importPath, "--on-conflict", "replace")
+	testutil.AssertContains(t, stderr, `invalid --on-conflict value "replace"`)
+}
+
+// TestListImportMarkdownInvalidAnnotationCLI verifies invalid annotations follow the strict/lenient rules
+func TestListImportMarkdownInvalidAnnotationCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+
+	importPath := cli.TmpDir() + "/Bad.md"
+	if err := os.WriteFile(importPath, []byte("- [ ] Task (due: someday)\n"), 0644); err != nil {
+		t.Fatalf("failed to write import file: %v", err)
+	}
+
+	_, stderr := cli.ExecuteAndFail("-y", "list", "import", importPath, "--strict")
+	testutil.AssertContains(t, stderr, `line 1, field due: invalid value "someday"`)
+
+	_, stderr, exitCode := cli.Execute("-y", "list", "import", importPath)
+	if exitCode != 0 {
+		t.Fatalf("expected lenient import to succeed, got %d: %s", exitCode, stderr)
+	}
+	testutil.AssertContains(t, stderr, "line 1, field due")
+}
+
+// TestIssue43_ReimportAfterDeleteCLI verifies that importing tasks after deleting the list
+// succeeds even when the exported file contains UIDs that were soft-deleted.
+// Regression test for issue #43: Import fails with UNIQUE constraint when reimporting tasks.
+func TestIssue43_ReimportAfterDeleteCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+
+	// Step 1: Create a list with tasks
+	cli.MustExecute("-y", "list", "create", "DemoList")
+	cli.MustExecute("-y", "DemoList", "add", "Task 1")
+	cli.MustExecute("-y", "DemoList", "add", "Task 2")
+
+	// Step 2: Export the list
+	exportPath := cli.TmpDir() + "/export.json"
+	cli.MustExecute("-y", "list", "export", "DemoList", "--format", "json", "--output", exportPath)
+
+	// Step 3: Delete the list and its tasks (soft-delete)
+	cli.MustExecute("-y", "list", "delete", "DemoList")
+
+	// Step 4: Try to import the same file - should succeed, not fail with UNIQUE constraint
+	// The fix should generate new UIDs for imported tasks
+	stdout := cli.MustExecute("-y", "list", "import", exportPath)
+
+	// Verify import succeeded
+	testutil.AssertContains(t, stdout, "Imported")
+	testutil.AssertContains(t, stdout, "2") // 2 tasks
+
+	// Verify the tasks are actually visible
+	listOutput := cli.MustExecute("-y", "DemoList")
+	testutil.AssertContains(t, listOutput, "Task 1")
+	testutil.AssertContains(t, listOutput, "Task 2")
+}
+
+// =============================================================================
+// Database Maintenance Tests (039-database-maintenance)
+// =============================================================================
+
+// TestListStatsSQLiteCLI verifies `todoat -y list stats` displays database statistics
+func TestListStatsSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+
+	// Create lists with varying task counts
+	cli.MustExecute("-y", "list", "create", "StatsWork")
+	cli.MustExecute("-y", "StatsWork", "add", "Task 1")
+	cli.MustExecute("-y", "StatsWork", "add", "Task 2")
+	cli.MustExecute("-y", "StatsWork", "complete", "Task 2")
+
+	cli.MustExecute("-y", "list", "create", "StatsPersonal")
+	cli.MustExecute("-y", "StatsPersonal", "add", "Task A")
+
+	// Get database stats
+	stdout := cli.MustExecute("-y", "list", "stats")
+
+	// Should show total tasks
+	testutil.AssertContains(t, stdout, "3") // 3 total tasks
+
+	// Should show tasks per list
 	testutil.AssertContains(t, stdout, "StatsWork")
 	testutil.AssertContains(t, stdout, "StatsPersonal")
 
@@ -3020,6 +4066,51 @@ func extractUID(t *testing.T, jsonOutput string) string {
 	return jsonOutput[start : start+end]
 }
 
+// TestTaskLinksSQLiteCLI verifies task lists print todoat:// links in JSON and that
+// open reports local tasks have no web page
+func TestTaskLinksSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+
+	uid := extractUID(t, cli.MustExecute("-y", "--json", "Work", "add", "Write report"))
+	stdout := cli.MustExecute("-y", "--json", "Work")
+	testutil.AssertContains(t, stdout, `"link":"todoat://Work/`+uid+`"`)
+
+	_, stderr := cli.ExecuteAndFail("-y", "open", "todoat://Work/"+uid)
+	testutil.AssertContains(t, stderr, "have no web page")
+	_, stderr = cli.ExecuteAndFail("-y", "Work", "open", "Write report")
+	testutil.AssertContains(t, stderr, "have no web page")
+	_, stderr = cli.ExecuteAndFail("-y", "open", "no-such-uid")
+	testutil.AssertContains(t, stderr, "no task with UID no-such-uid")
+}
+
+// TestDedupeMergesAndUndoesSQLiteCLI tests that dedupe lists, merges and restores duplicates
+func TestDedupeMergesAndUndoesSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+
+	cli.MustExecute("-y", "Work", "add", "Pay rent", "--tag", "home", "-d", "Landlord IBAN in notes")
+	cli.MustExecute("-y", "Work", "add", "pay rent!", "--tag", "money", "-p", "2")
+	cli.MustExecute("-y", "Work", "add", "Walk dog")
+
+	stdout := cli.MustExecute("-y", "dedupe", "--list", "Work")
+	testutil.AssertContains(t, stdout, "2 duplicates of 'Pay rent'")
+	testutil.AssertResultCode(t, stdout, testutil.ResultInfoOnly)
+
+	stdout = cli.MustExecute("-y", "dedupe", "--list", "Work", "--strategy", "oldest")
+	testutil.AssertContains(t, stdout, "Merged 1 duplicates into 'Pay rent'")
+	stdout = cli.MustExecute("-y", "--json", "Work", "get", "Pay rent")
+	testutil.AssertContains(t, stdout, `"priority":2`)
+	testutil.AssertContains(t, stdout, `"money"`)
+	testutil.AssertContains(t, stdout, "Landlord IBAN in notes")
+	testutil.AssertNotContains(t, cli.MustExecute("-y", "Work"), "pay rent!")
+
+	stdout = cli.MustExecute("-y", "dedupe", "undo")
+	testutil.AssertContains(t, stdout, "Restored 1 task")
+	stdout = cli.MustExecute("-y", "Work")
+	testutil.AssertContains(t, stdout, "pay rent!")
+	_, stderr := cli.ExecuteAndFail("-y", "dedupe", "undo")
+	testutil.AssertContains(t, stderr, "no dedupe to undo")
+}
+
 // TestUpdateByUID tests updating a task by its UID
 func TestUpdateByUID(t *testing.T) {
 	cli := testutil.NewCLITest(t)
@@ -4484,7 +5575,15 @@ func TestAddTaskWithTimeSQLiteCLI(t *testing.T) {
 
 // TestAddTaskWithTimeZoneSQLiteCLI verifies that `todoat -y MyList add "Call" --due-date "2026-01-20T14:30-05:00"` handles timezone
 func TestAddTaskWithTimeZoneSQLiteCLI(t *testing.T) {
-	cli := testutil.NewCLITest(t)
+	t.Cleanup(func() { _ = utils.SetTimezone("") })
+	cli := testutil.NewCLITestWithConfig(t)
+	cli.SetFullConfig(`
+backends:
+  sqlite:
+    enabled: true
+no_prompt: true
+timezone: Asia/Tokyo
+`)
 
 	stdout := cli.MustExecute("-y", "Work", "add", "Call", "--due-date", "2026-01-20T14:30-05:00")
 
@@ -4494,13 +5593,21 @@ func TestAddTaskWithTimeZoneSQLiteCLI(t *testing.T) {
 	stdout = cli.MustExecute("-y", "--json", "Work")
 
 	testutil.AssertContains(t, stdout, "Call")
-	// Should contain the date portion at minimum
-	testutil.AssertContains(t, stdout, "2026-01-20")
+	// Shown in the configured timezone, where it is already the next day
+	testutil.AssertContains(t, stdout, "2026-01-21T04:30:00+09:00")
 }
 
 // TestAddTaskWithTimeUTCSQLiteCLI verifies that `todoat -y MyList add "Task" --due-date "2026-01-20T14:30Z"` handles UTC timezone
 func TestAddTaskWithTimeUTCSQLiteCLI(t *testing.T) {
-	cli := testutil.NewCLITest(t)
+	t.Cleanup(func() { _ = utils.SetTimezone("") })
+	cli := testutil.NewCLITestWithConfig(t)
+	cli.SetFullConfig(`
+backends:
+  sqlite:
+    enabled: true
+no_prompt: true
+timezone: America/Los_Angeles
+`)
 
 	stdout := cli.MustExecute("-y", "Work", "add", "UTC Task", "--due-date", "2026-01-20T14:30Z")
 
@@ -4510,7 +5617,7 @@ func TestAddTaskWithTimeUTCSQLiteCLI(t *testing.T) {
 	stdout = cli.MustExecute("-y", "--json", "Work")
 
 	testutil.AssertContains(t, stdout, "UTC Task")
-	testutil.AssertContains(t, stdout, "2026-01-20")
+	testutil.AssertContains(t, stdout, "2026-01-20T06:30:00-08:00")
 }
 
 // TestDateOnlyStillWorksSQLiteCLI verifies that `todoat -y MyList add "Task" --due-date "2026-01-20"` works (date only, midnight assumed)
@@ -4851,9 +5958,8 @@ func TestRecurringFromCompletionSQLiteCLI(t *testing.T) {
 	cli := testutil.NewCLITest(t)
 
 	// Set a due date for yesterday (task is overdue)
-	// Use UTC to match the backend's storage format
-	yesterday := time.Now().UTC().AddDate(0, 0, -1).Format("2006-01-02")
-	tomorrow := time.Now().UTC().AddDate(0, 0, 1).Format("2006-01-02")
+	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	tomorrow := time.Now().AddDate(0, 0, 1).Format("2006-01-02")
 
 	cli.MustExecute("-y", "Work", "add", "Check logs", "--recur", "daily", "--recur-from-completion", "--due-date", yesterday)
 
@@ -4864,7 +5970,6 @@ func TestRecurringFromCompletionSQLiteCLI(t *testing.T) {
 	stdout := cli.MustExecute("-y", "--json", "Work")
 
 	// The new pending task should have tomorrow as due date (completion date + 1 day)
-	// Due dates are stored in UTC, so we compare with UTC-based tomorrow
 	testutil.AssertContains(t, stdout, tomorrow)
 }
 
@@ -4903,6 +6008,68 @@ func TestRemoveRecurrenceSQLiteCLI(t *testing.T) {
 	testutil.AssertNotContains(t, stdout, "FREQ=DAILY")
 }
 
+// TestRecurShowAndSkipSQLiteCLI tests that `recur show` previews the next due dates
+// and `recur skip` moves the task to the next one without completing it
+func TestRecurShowAndSkipSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+
+	due := time.Now().AddDate(0, 0, 1)
+	day := func(weeks int) string { return due.AddDate(0, 0, 7*weeks).Format("2006-01-02") }
+	cli.MustExecute("-y", "Work", "add", "Team sync", "--recur", "weekly", "--due-date", day(0), "--start-date", due.AddDate(0, 0, -2).Format("2006-01-02"))
+
+	stdout := cli.MustExecute("-y", "Work", "recur", "show", "Team sync", "--limit", "3")
+	testutil.AssertContains(t, stdout, "Next 3 occurrences")
+	testutil.AssertContains(t, stdout, day(1))
+	testutil.AssertContains(t, stdout, day(3))
+	testutil.AssertNotContains(t, stdout, day(4))
+	testutil.AssertResultCode(t, stdout, testutil.ResultInfoOnly)
+
+	stdout = cli.MustExecute("-y", "--json", "Work", "recur", "show", "Team sync")
+	testutil.AssertContains(t, stdout, `"occurrences":["`+day(1))
+	testutil.AssertContains(t, stdout, day(5))
+
+	stdout = cli.MustExecute("-y", "Work", "recur", "skip", "Team sync")
+	testutil.AssertContains(t, stdout, "next due: ")
+	testutil.AssertContains(t, stdout, day(1))
+
+	// Still one open task, due a week later, with its start date moved along
+	stdout = cli.MustExecute("-y", "--json", "Work")
+	testutil.AssertContains(t, stdout, `"due_date":"`+day(1))
+	testutil.AssertContains(t, stdout, `"start_date":"`+due.AddDate(0, 0, 5).Format("2006-01-02"))
+	testutil.AssertNotContains(t, stdout, "DONE")
+
+	cli.MustExecute("-y", "Work", "add", "One-off")
+	_, stderr := cli.ExecuteAndFail("-y", "Work", "recur", "skip", "One-off")
+	testutil.AssertContains(t, stderr, "does not recur")
+	_, stderr = cli.ExecuteAndFail("-y", "Work", "recur", "later", "Team sync")
+	testutil.AssertContains(t, stderr, "unknown recur subcommand")
+}
+
+// TestRecurringSkipsWeekendsAndHolidaysSQLiteCLI tests that with dates.skip_weekends
+// and dates.holidays the next occurrence moves to a working day, and that the
+// rule is anchored so later occurrences keep their weekday
+func TestRecurringSkipsWeekendsAndHolidaysSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITestWithConfig(t)
+	holidays := filepath.Join(cli.TmpDir(), "holidays.ics")
+	ics := "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nDTSTART;VALUE=DATE:20201225\r\nRRULE:FREQ=YEARLY\r\nSUMMARY:Christmas\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+	if err := os.WriteFile(holidays, []byte(ics), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cli.SetFullConfig("dates:\n  skip_weekends: true\n  holidays: " + holidays + "\n")
+
+	// Friday 2026-12-25 is a holiday, followed by a weekend
+	cli.MustExecute("-y", "Work", "add", "Weekly report", "--recur", "weekly", "--due-date", "2026-12-18")
+	stdout := cli.MustExecute("-y", "Work", "complete", "Weekly report")
+	testutil.AssertContains(t, stdout, "due: 2026-12-28")
+
+	stdout = cli.MustExecute("-y", "--json", "Work", "-s", "TODO")
+	testutil.AssertContains(t, stdout, "FREQ=WEEKLY;INTERVAL=1;BYDAY=FR")
+
+	stdout = cli.MustExecute("-y", "Work", "recur", "show", "--uid", extractUID(t, stdout), "--limit", "2")
+	testutil.AssertContains(t, stdout, "Fri 2027-01-01")
+	testutil.AssertContains(t, stdout, "Fri 2027-01-08")
+}
+
 // TestRecurringTaskDisplaySQLiteCLI tests that recurring tasks show indicator in list
 func TestRecurringTaskDisplaySQLiteCLI(t *testing.T) {
 	cli := testutil.NewCLITest(t)
@@ -5336,3 +6503,1564 @@ func TestViewNonExistentListSQLiteCLI(t *testing.T) {
 	listOutput = cli.MustExecute("-y", "list")
 	testutil.AssertNotContains(t, listOutput, "MyLsit")
 }
+
+// =============================================================================
+// Task Move/Copy Between Lists and Backends
+// =============================================================================
+
+// TestMoveTaskToListSQLiteCLI verifies `todoat MyList move "Task" --to Other --create` moves the task with subtasks, tags and dates
+func TestMoveTaskToListSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+
+	cli.MustExecute("-y", "list", "create", "MoveSource")
+	cli.MustExecute("-y", "MoveSource", "add", "Release", "--tag", "work", "--due-date", "2026-03-01", "-p", "2")
+	cli.MustExecute("-y", "MoveSource", "add", "Changelog", "-P", "Release")
+	cli.MustExecute("-y", "MoveSource", "add", "Keep me")
+
+	stdout := cli.MustExecute("-y", "MoveSource", "move", "Release", "--to", "MoveTarget", "--create")
+	testutil.AssertContains(t, stdout, "Moved task: Release to MoveTarget")
+	testutil.AssertContains(t, stdout, "1 subtasks")
+	testutil.AssertResultCode(t, stdout, testutil.ResultActionCompleted)
+
+	// Source keeps only the unrelated task
+	stdout = cli.MustExecute("-y", "--json", "MoveSource")
+	testutil.AssertNotContains(t, stdout, `"Release"`)
+	testutil.AssertNotContains(t, stdout, `"Changelog"`)
+	testutil.AssertContains(t, stdout, `"Keep me"`)
+
+	// Target has the task with its fields and subtask hierarchy
+	stdout = cli.MustExecute("-y", "--json", "MoveTarget")
+	var resp struct {
+		Tasks []struct {
+			UID      string   `json:"uid"`
+			Summary  string   `json:"summary"`
+			Priority int      `json:"priority"`
+			ParentID string   `json:"parent_id"`
+			DueDate  *string  `json:"due_date"`
+			Tags     []string `json:"tags"`
+		} `json:"tasks"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &resp); err != nil {
+		t.Fatalf("failed to parse JSON: %v\n%s", err, stdout)
+	}
+	ids := map[string]string{}
+	for _, task := range resp.Tasks {
+		ids[task.Summary] = task.UID
+	}
+	for _, task := range resp.Tasks {
+		switch task.Summary {
+		case "Release":
+			if task.Priority != 2 || task.DueDate == nil || len(task.Tags) != 1 || task.Tags[0] != "work" {
+				t.Errorf("expected moved task to keep priority, due date and tags, got %+v", task)
+			}
+		case "Changelog":
+			if task.ParentID != ids["Release"] {
+				t.Errorf("expected subtask to be under moved parent %q, got %q", ids["Release"], task.ParentID)
+			}
+		}
+	}
+	if len(resp.Tasks) != 2 {
+		t.Errorf("expected 2 tasks in target, got %d:\n%s", len(resp.Tasks), stdout)
+	}
+}
+
+// TestCopyTaskToListSQLiteCLI verifies `todoat MyList copy "Task" --to Other` keeps the original
+func TestCopyTaskToListSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+
+	cli.MustExecute("-y", "list", "create", "CopySource")
+	cli.MustExecute("-y", "list", "create", "CopyTarget")
+	cli.MustExecute("-y", "CopySource", "add", "Template task")
+
+	stdout := cli.MustExecute("-y", "--json", "CopySource", "copy", "Template task", "--to", "CopyTarget")
+	testutil.AssertContains(t, stdout, `"action":"copy"`)
+
+	testutil.AssertContains(t, cli.MustExecute("-y", "CopySource"), "Template task")
+	testutil.AssertContains(t, cli.MustExecute("-y", "CopyTarget"), "Template task")
+}
+
+// TestMoveTaskToBackendSQLiteCLI verifies `--to-backend <backend>:<list>` moves a task into another backend
+func TestMoveTaskToBackendSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITestWithConfig(t)
+	archivePath := cli.TmpDir() + "/archive.db"
+	cli.SetFullConfig("default_backend: sqlite\nbackends:\n  archive:\n    type: sqlite\n    enabled: true\n    path: " + archivePath + "\n")
+
+	cli.MustExecute("-y", "list", "create", "Active")
+	cli.MustExecute("-y", "Active", "add", "Old project")
+
+	stdout := cli.MustExecute("-y", "Active", "move", "Old project", "--to-backend", "archive:Done", "--create")
+	testutil.AssertContains(t, stdout, "Moved task: Old project to archive:Done")
+
+	testutil.AssertNotContains(t, cli.MustExecute("-y", "Active"), "Old project")
+	testutil.AssertContains(t, cli.MustExecute("-y", "-b", "archive", "Done"), "Old project")
+}
+
+// TestMoveTaskErrorsSQLiteCLI verifies move validates its target flags
+func TestMoveTaskErrorsSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+
+	cli.MustExecute("-y", "list", "create", "MoveErrors")
+	cli.MustExecute("-y", "MoveErrors", "add", "Task")
+
+	_, stderr := cli.ExecuteAndFail("-y", "MoveErrors", "move", "Task")
+	testutil.AssertContains(t, stderr, "--to")
+
+	_, stderr = cli.ExecuteAndFail("-y", "MoveErrors", "move", "Task", "--to", "MoveErrors")
+	testutil.AssertContains(t, stderr, "already in list")
+
+	_, stderr = cli.ExecuteAndFail("-y", "MoveErrors", "move", "Task", "--to-backend", "sqlite")
+	testutil.AssertContains(t, stderr, "expected <backend>:<list>")
+
+	// --to-backend naming the current backend and list is the same list
+	_, stderr = cli.ExecuteAndFail("-y", "MoveErrors", "copy", "Task", "--to-backend", "sqlite:MoveErrors")
+	testutil.AssertContains(t, stderr, "already in list")
+	if stdout := cli.MustExecute("-y", "--json", "MoveErrors"); strings.Count(stdout, `"summary":"Task"`) != 1 {
+		t.Errorf("copy to the same list via --to-backend duplicated the task:\n%s", stdout)
+	}
+
+	// A misspelled target list is not created without --create
+	_, stderr = cli.ExecuteAndFail("-y", "MoveErrors", "move", "Task", "--to", "MoveErorrs2")
+	testutil.AssertContains(t, stderr, "target list 'MoveErorrs2' not found")
+	testutil.AssertNotContains(t, cli.MustExecute("-y", "list"), "MoveErorrs2")
+
+	// Failed moves leave the task in place
+	testutil.AssertContains(t, cli.MustExecute("-y", "MoveErrors"), "Task")
+}
+
+// =============================================================================
+// Virtual List Tests (@overdue, @today, @week, @no-date)
+// =============================================================================
+
+// addVirtualListFixtures creates tasks across two lists with due dates relative to today
+func addVirtualListFixtures(t *testing.T, cli *testutil.CLITest) {
+	t.Helper()
+	day := func(offset int) string {
+		return time.Now().AddDate(0, 0, offset).Format("2006-01-02")
+	}
+
+	cli.MustExecute("-y", "Work", "add", "Pay invoice", "--due-date", day(-2))
+	cli.MustExecute("-y", "Work", "add", "Standup notes", "--due-date", day(0))
+	cli.MustExecute("-y", "Home", "add", "Book dentist", "--due-date", day(3))
+	cli.MustExecute("-y", "Home", "add", "Renew passport", "--due-date", day(10))
+	cli.MustExecute("-y", "Home", "add", "Someday idea")
+	cli.MustExecute("-y", "Work", "add", "Old report", "--due-date", day(-5), "-s", "DONE")
+}
+
+func TestVirtualListsSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+	addVirtualListFixtures(t, cli)
+
+	stdout := cli.MustExecute("-y", "@overdue")
+	testutil.AssertContains(t, stdout, "Pay invoice")
+	testutil.AssertNotContains(t, stdout, "Standup notes")
+	testutil.AssertNotContains(t, stdout, "Old report")
+
+	stdout = cli.MustExecute("-y", "@today")
+	testutil.AssertContains(t, stdout, "Standup notes")
+	testutil.AssertNotContains(t, stdout, "Pay invoice")
+	testutil.AssertNotContains(t, stdout, "Book dentist")
+
+	stdout = cli.MustExecute("-y", "@week")
+	testutil.AssertContains(t, stdout, "Standup notes")
+	testutil.AssertContains(t, stdout, "Book dentist")
+	testutil.AssertNotContains(t, stdout, "Renew passport")
+	testutil.AssertNotContains(t, stdout, "Pay invoice")
+
+	stdout = cli.MustExecute("-y", "@no-date")
+	testutil.AssertContains(t, stdout, "Someday idea")
+	testutil.AssertNotContains(t, stdout, "Book dentist")
+
+	// Names are case-insensitive
+	testutil.AssertContains(t, cli.MustExecute("-y", "@TODAY"), "Standup notes")
+}
+
+func TestVirtualListActionsSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+	addVirtualListFixtures(t, cli)
+	cli.MustExecute("-y", "Home", "add", "Chores")
+	cli.MustExecute("-y", "Home", "add", "Take out trash", "-P", "Chores", "--due-date", time.Now().Format("2006-01-02"))
+
+	// Complete a task through a virtual list; it is updated in its real list
+	stdout := cli.MustExecute("-y", "@overdue", "complete", "Pay invoice")
+	testutil.AssertContains(t, stdout, "Completed task: Pay invoice")
+	testutil.AssertNotContains(t, cli.MustExecute("-y", "@overdue"), "Pay invoice")
+	testutil.AssertContains(t, cli.MustExecute("-y", "Work", "-s", "DONE"), "Pay invoice")
+
+	// Update a subtask through a virtual list without losing its parent
+	cli.MustExecute("-y", "@today", "update", "Take out trash", "-p", "3")
+	stdout = cli.MustExecute("-y", "--json", "Home")
+	var result struct {
+		Tasks []struct {
+			Summary  string `json:"summary"`
+			Priority int    `json:"priority"`
+			ParentID string `json:"parent_id"`
+		} `json:"tasks"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, stdout)
+	}
+	found := false
+	for _, task := range result.Tasks {
+		if task.Summary == "Take out trash" {
+			found = true
+			if task.Priority != 3 || task.ParentID == "" {
+				t.Errorf("expected priority 3 with parent kept, got %+v", task)
+			}
+		}
+	}
+	if !found {
+		t.Fatalf("subtask not found in Home list: %s", stdout)
+	}
+
+	// Delete through a virtual list
+	cli.MustExecute("-y", "@no-date", "delete", "Someday idea")
+	testutil.AssertNotContains(t, cli.MustExecute("-y", "Home"), "Someday idea")
+}
+
+func TestVirtualListErrorsSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+	addVirtualListFixtures(t, cli)
+
+	_, stderr := cli.ExecuteAndFail("-y", "@today", "add", "New task")
+	testutil.AssertContains(t, stderr, "cannot add tasks to virtual list")
+
+	_, stderr = cli.ExecuteAndFail("-y", "@week", "complete", "Home/*")
+	testutil.AssertContains(t, stderr, "bulk patterns are not supported")
+
+	_, stderr = cli.ExecuteAndFail("-y", "list", "create", "@Overdue")
+	testutil.AssertContains(t, stderr, "reserved for a virtual list")
+
+	_, stderr = cli.ExecuteAndFail("-y", "list", "update", "Work", "--name", "@week")
+	testutil.AssertContains(t, stderr, "reserved for a virtual list")
+
+	// Tasks outside the view cannot be selected through it
+	_, stderr = cli.ExecuteAndFail("-y", "@today", "complete", "Renew passport")
+	testutil.AssertContains(t, stderr, "no task found")
+}
+
+// TestSubtaskProgressSQLiteCLI verifies parent tasks show "done/total" progress of their subtasks
+func TestSubtaskProgressSQLiteCLI(t *testing.T) {
+	cli, _ := testutil.NewCLITestWithViews(t)
+
+	cli.MustExecute("-y", "Progress", "add", "Release/Docs", "--path")
+	cli.MustExecute("-y", "Progress", "add", "Release/Tests", "--path")
+	cli.MustExecute("-y", "Progress", "add", "Release/Build", "--path")
+	cli.MustExecute("-y", "Progress", "complete", "Docs")
+
+	// Completed subtasks are hidden by the default view but still counted
+	stdout := cli.MustExecute("-y", "Progress")
+	testutil.AssertContains(t, stdout, "[1/3]")
+
+	stdout = cli.MustExecute("-y", "--json", "Progress")
+	testutil.AssertContains(t, stdout, `"progress":{"done":1,"total":3}`)
+
+	// Progress is off unless enabled: completing every subtask leaves the parent open
+	cli.MustExecute("-y", "Progress", "complete", "Release/*")
+	stdout = cli.MustExecute("-y", "Progress")
+	testutil.AssertContains(t, stdout, "Release")
+	testutil.AssertContains(t, stdout, "[3/3]")
+}
+
+// TestAutoCompleteParentSQLiteCLI verifies auto_complete_parent and reopen_parent
+func TestAutoCompleteParentSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITestWithConfig(t)
+	cli.SetFullConfig("default_backend: sqlite\nauto_complete_parent: true\nreopen_parent: true\n")
+	cli.Config().ViewsPath = filepath.Join(cli.TmpDir(), "views")
+
+	cli.MustExecute("-y", "Rollup", "add", "Release/Docs", "--path")
+	cli.MustExecute("-y", "Rollup", "add", "Release/Tests", "--path")
+	cli.MustExecute("-y", "Rollup", "add", "Release/Tests/Unit", "--path")
+
+	stdout := cli.MustExecute("-y", "Rollup", "complete", "Docs")
+	testutil.AssertNotContains(t, stdout, "parent task")
+
+	// Completing the last open subtask completes its parent and, in turn, the grandparent
+	stdout = cli.MustExecute("-y", "Rollup", "complete", "Unit")
+	testutil.AssertContains(t, stdout, "Completed parent task: Tests")
+	testutil.AssertContains(t, stdout, "Completed parent task: Release")
+	testutil.AssertNotContains(t, cli.MustExecute("-y", "Rollup"), "Release")
+
+	// Adding a subtask reopens the completed parent
+	stdout = cli.MustExecute("-y", "Rollup", "add", "Changelog", "-P", "Release")
+	testutil.AssertContains(t, stdout, "Reopened parent task: Release")
+	stdout = cli.MustExecute("-y", "Rollup")
+	testutil.AssertContains(t, stdout, "Release")
+	testutil.AssertContains(t, stdout, "[3/4]")
+}
+
+// TestBackendSQLSQLiteCLI verifies 'backend sql' runs read-only queries on the local database
+func TestBackendSQLSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+	cli.MustExecute("-y", "Debug", "add", "Inspect me")
+
+	_, stderr := cli.ExecuteAndFail("backend", "sql", "SELECT summary FROM tasks")
+	testutil.AssertContains(t, stderr, "--yes-i-know")
+
+	stdout := cli.MustExecute("backend", "sql", "SELECT summary, status FROM tasks", "--yes-i-know")
+	testutil.AssertContains(t, stdout, "summary")
+	testutil.AssertContains(t, stdout, "Inspect me")
+	testutil.AssertContains(t, stdout, "(1 rows)")
+
+	stdout = cli.MustExecute("--json", "backend", "sql", "SELECT summary FROM tasks", "--yes-i-know")
+	testutil.AssertContains(t, stdout, `"columns": [`)
+	testutil.AssertContains(t, stdout, `"Inspect me"`)
+
+	// Writes are rejected and leave the data untouched
+	_, stderr = cli.ExecuteAndFail("backend", "sql", "DELETE FROM tasks", "--yes-i-know")
+	testutil.AssertContains(t, stderr, "query failed")
+	testutil.AssertContains(t, cli.MustExecute("-y", "Debug"), "Inspect me")
+	cli.MustExecute("-y", "Debug", "add", "Still writable")
+}
+
+// TestBackendSQLCustomDBPathSQLiteCLI verifies 'backend sql' queries the database set by backends.sqlite.path
+func TestBackendSQLCustomDBPathSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITestWithCustomDBPath(t)
+	cli.MustExecute("-y", "Debug", "add", "In custom database")
+
+	stdout := cli.MustExecute("backend", "sql", "SELECT summary FROM tasks", "--yes-i-know")
+	testutil.AssertContains(t, stdout, "In custom database")
+	testutil.AssertContains(t, stdout, "(1 rows)")
+
+	if _, err := os.Stat(cli.DefaultDBPath()); err == nil {
+		t.Errorf("backend sql should not create the default database %q", cli.DefaultDBPath())
+	}
+}
+
+// completedReportImport is a JSON export with tasks completed on fixed dates
+const completedReportImport = `{"list_name": "Work", "tasks": [
+  {"id": "1", "summary": "Ship login page", "status": "COMPLETED", "completed": "2024-01-05T10:00:00Z", "categories": "frontend"},
+  {"id": "2", "summary": "Fix flaky tests", "status": "COMPLETED", "completed": "2024-02-20T10:00:00Z", "categories": "ci,backend"},
+  {"id": "3", "summary": "Write runbook", "status": "COMPLETED", "completed": "2024-03-31T10:00:00Z"},
+  {"id": "4", "summary": "Old cleanup", "status": "COMPLETED", "completed": "2023-12-30T10:00:00Z"},
+  {"id": "5", "summary": "Still open", "status": "NEEDS-ACTION"}
+]}`
+
+// TestReportCompletedSQLiteCLI verifies 'report completed' lists tasks completed in a period
+func TestReportCompletedSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+
+	importPath := filepath.Join(cli.TmpDir(), "work.json")
+	if err := os.WriteFile(importPath, []byte(completedReportImport), 0644); err != nil {
+		t.Fatalf("failed to write import file: %v", err)
+	}
+	cli.MustExecute("-y", "list", "import", importPath)
+	cli.MustExecute("-y", "list", "create", "Home")
+	cli.MustExecute("-y", "Home", "add", "Done today")
+	cli.MustExecute("-y", "Home", "complete", "Done today")
+
+	stdout := cli.MustExecute("-y", "report", "completed", "--from", "2024-01-01", "--to", "2024-03-31")
+	testutil.AssertContains(t, stdout, "Completed tasks (2024-01-01 to 2024-03-31): 3")
+	testutil.AssertContains(t, stdout, "Work (3)")
+	testutil.AssertContains(t, stdout, "2024-01-05  Ship login page #frontend")
+	testutil.AssertContains(t, stdout, "Write runbook")
+	testutil.AssertNotContains(t, stdout, "Old cleanup")
+	testutil.AssertNotContains(t, stdout, "Still open")
+	testutil.AssertNotContains(t, stdout, "Done today")
+
+	// The default period is the current month
+	stdout = cli.MustExecute("-y", "report", "completed")
+	testutil.AssertContains(t, stdout, "Home (1)")
+	testutil.AssertContains(t, stdout, "Done today")
+	testutil.AssertNotContains(t, stdout, "Ship login page")
+
+	stdout = cli.MustExecute("-y", "report", "completed", "--group-by", "none")
+	testutil.AssertContains(t, stdout, "Done today [Home]")
+
+	stdout = cli.MustExecute("-y", "report", "completed", "--from", "2024-01-01", "--to", "2024-03-31", "--group-by", "tag", "--format", "markdown")
+	testutil.AssertContains(t, stdout, "# Completed tasks: 2024-01-01 to 2024-03-31")
+	testutil.AssertContains(t, stdout, "3 tasks completed.")
+	testutil.AssertContains(t, stdout, "## backend (1)")
+	testutil.AssertContains(t, stdout, "## ci (1)")
+	testutil.AssertContains(t, stdout, "- 2024-02-20: Fix flaky tests _Work_ #ci #backend")
+	if !strings.HasSuffix(strings.TrimSpace(stdout), "- 2024-03-31: Write runbook _Work_") {
+		t.Errorf("expected untagged tasks last, got:\n%s", stdout)
+	}
+
+	stdout = cli.MustExecute("-y", "--json", "report", "completed", "--from", "2024-01-01", "--to", "2024-03-31", "--group-by", "month")
+	var report struct {
+		Total  int `json:"total"`
+		Groups []struct {
+			Name  string `json:"name"`
+			Count int    `json:"count"`
+		} `json:"groups"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &report); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, stdout)
+	}
+	if report.Total != 3 || len(report.Groups) != 3 || report.Groups[0].Name != "2024-01" || report.Groups[2].Name != "2024-03" {
+		t.Errorf("unexpected month grouping: %+v", report)
+	}
+
+	_, stderr := cli.ExecuteAndFail("-y", "report", "completed", "--group-by", "priority")
+	testutil.AssertContains(t, stderr, "invalid --group-by")
+	_, stderr = cli.ExecuteAndFail("-y", "report", "completed", "--from", "2024-03-01", "--to", "2024-02-01")
+	testutil.AssertContains(t, stderr, "--from must not be after --to")
+}
+
+// TestConcurrentBulkMutationsSQLiteCLI verifies concurrent hierarchy adds and bulk deletes serialize cleanly
+func TestConcurrentBulkMutationsSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+
+	cli.MustExecute("-y", "Work", "add", "Seed/Child")
+
+	const numConcurrent = 4
+	results := make(chan string, numConcurrent*2)
+	for i := 1; i <= numConcurrent; i++ {
+		go func(idx int) {
+			stdout, stderr, _ := cli.Execute("-y", "Work", "add", "Project "+strconv.Itoa(idx)+"/Phase/Step")
+			results <- stdout + stderr
+		}(i)
+		go func() {
+			stdout, stderr, _ := cli.Execute("-y", "Work", "delete", "Seed/*")
+			results <- stdout + stderr
+		}()
+	}
+	for i := 0; i < numConcurrent*2; i++ {
+		output := <-results
+		if strings.Contains(output, "database is locked") || strings.Contains(output, "SQLITE_BUSY") {
+			t.Errorf("concurrent mutation hit a lock error: %s", output)
+		}
+	}
+
+	stdout := cli.MustExecute("-y", "Work", "get", "--json")
+	for i := 1; i <= numConcurrent; i++ {
+		testutil.AssertContains(t, stdout, "Project "+strconv.Itoa(i))
+	}
+}
+
+// TestTaskMetadataSQLiteCLI verifies --meta sets, removes and filters custom fields and that views can show them
+func TestTaskMetadataSQLiteCLI(t *testing.T) {
+	cli, viewsDir := testutil.NewCLITestWithViews(t)
+
+	cli.MustExecute("-y", "Work", "add", "Invoice", "--meta", "estimate=3h", "--meta", "client=acme")
+	cli.MustExecute("-y", "Work", "add", "Review")
+
+	stdout := cli.MustExecute("-y", "Work", "get", "--json")
+	testutil.AssertContains(t, stdout, `"metadata":{"client":"acme","estimate":"3h"}`)
+
+	stdout = cli.MustExecute("-y", "Work", "get", "--meta", "client=acme")
+	testutil.AssertContains(t, stdout, "Invoice")
+	testutil.AssertNotContains(t, stdout, "Review")
+	stdout = cli.MustExecute("-y", "Work", "get", "--meta", "estimate")
+	testutil.AssertContains(t, stdout, "Invoice")
+	testutil.AssertNotContains(t, stdout, "Review")
+
+	viewYAML := `name: billing
+fields:
+  - name: summary
+  - name: meta.client
+filters:
+  - field: meta.client
+    operator: eq
+    value: globex
+`
+	if err := os.WriteFile(viewsDir+"/billing.yaml", []byte(viewYAML), 0644); err != nil {
+		t.Fatalf("failed to write view file: %v", err)
+	}
+	cli.MustExecute("-y", "Work", "update", "Invoice", "--meta", "client=globex", "--meta", "estimate=")
+	stdout = cli.MustExecute("-y", "Work", "-v", "billing")
+	testutil.AssertContains(t, stdout, "globex")
+	testutil.AssertNotContains(t, stdout, "Review")
+
+	stdout = cli.MustExecute("-y", "Work", "get", "--json")
+	testutil.AssertContains(t, stdout, `"metadata":{"client":"globex"}`)
+
+	_, stderr := cli.ExecuteAndFail("-y", "Work", "add", "Bad", "--meta", "no value")
+	testutil.AssertContains(t, stderr, "expected key=value")
+}
+
+// TestEstimatesAndPlanSQLiteCLI verifies --estimate, the estimate rollup and the capacity planner
+func TestEstimatesAndPlanSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+
+	cli.MustExecute("-y", "Work", "add", "Fix login", "--estimate", "2h", "-p", "1", "--tag", "dev")
+	cli.MustExecute("-y", "Work", "add", "Write docs", "--estimate", "90m", "-p", "5", "--tag", "dev")
+	cli.MustExecute("-y", "Work", "add", "Migrate database", "--estimate", "1.5h", "-p", "2")
+	cli.MustExecute("-y", "Home", "add", "Call plumber")
+
+	stdout := cli.MustExecute("-y", "Work", "get", "--json")
+	testutil.AssertContains(t, stdout, `"estimate":"1h30m"`)
+
+	stdout = cli.MustExecute("-y", "report", "estimates")
+	testutil.AssertContains(t, stdout, "Estimated effort: 5h across 4 tasks (1 without estimate)")
+	testutil.AssertContains(t, stdout, "5h  3 tasks")
+	testutil.AssertContains(t, stdout, "1 task (1 without estimate)")
+	stdout = cli.MustExecute("-y", "report", "estimates", "--group-by", "tag")
+	testutil.AssertContains(t, stdout, "3h30m  2 tasks")
+
+	stdout = cli.MustExecute("-y", "plan", "--capacity", "3h")
+	testutil.AssertContains(t, stdout, "Plan for today: 2h of 3h")
+	testutil.AssertContains(t, stdout, "1. [Work] Fix login (2h) [P1]")
+	testutil.AssertContains(t, stdout, "Did not fit (2)")
+	testutil.AssertContains(t, stdout, "[Home] Call plumber")
+
+	cli.MustExecute("-y", "Work", "update", "Migrate database", "--estimate", "45m")
+	stdout = cli.MustExecute("-y", "plan", "--capacity", "3h", "--json")
+	testutil.AssertContains(t, stdout, `"planned": "2h45m"`)
+
+	_, stderr := cli.ExecuteAndFail("-y", "Work", "add", "Bad", "--estimate", "soon")
+	testutil.AssertContains(t, stderr, "invalid --estimate")
+	_, stderr = cli.ExecuteAndFail("-y", "plan", "--capacity", "lots")
+	testutil.AssertContains(t, stderr, "invalid --capacity")
+}
+
+// TestGetOutputFormatsSQLiteCLI verifies the table, markdown, tsv and template output formats of get
+func TestGetOutputFormatsSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+
+	cli.MustExecute("-y", "Work", "add", "Release", "-p", "1", "--tag", "ops", "--due-date", "2026-05-01")
+	cli.MustExecute("-y", "Work", "add", "Release/Tag build", "--path")
+
+	stdout := cli.MustExecute("-y", "Work", "--output", "markdown")
+	testutil.AssertContains(t, stdout, "- [ ] Release (P1, due 2026-05-01, #ops, 0/1 done)")
+	testutil.AssertContains(t, stdout, "  - [ ] Tag build")
+	testutil.AssertNotContains(t, stdout, "Tasks in")
+
+	stdout = cli.MustExecute("-y", "Work", "--output", "tsv")
+	testutil.AssertContains(t, stdout, "status\tsummary\tpriority\tdue_date\ttags\trecurrence\tprogress\n")
+	testutil.AssertContains(t, stdout, "TODO\tRelease\t1\t2026-05-01\tops\t\t0/1\n")
+
+	stdout = cli.MustExecute("-y", "Work", "--output", "table")
+	testutil.AssertContains(t, stdout, "STATUS")
+	testutil.AssertContains(t, stdout, "  Tag build")
+
+	stdout = cli.MustExecute("-y", "Work", "--template", "{{.Summary}}|{{.Priority}}|{{.Depth}}")
+	testutil.AssertContains(t, stdout, "Release|1|0\nTag build|0|1\n")
+
+	stdout = cli.MustExecute("-y", "Work", "--output", "json")
+	testutil.AssertContains(t, stdout, `"summary":"Release"`)
+
+	_, stderr := cli.ExecuteAndFail("-y", "Work", "--output", "yaml")
+	testutil.AssertContains(t, stderr, "invalid --output")
+	_, stderr = cli.ExecuteAndFail("-y", "Work", "--template", "{{.Summary")
+	testutil.AssertContains(t, stderr, "invalid template")
+}
+
+// TestGetTreeOutputSQLiteCLI verifies --tree connectors, --depth collapsing and
+// nested JSON output of get
+func TestGetTreeOutputSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+
+	cli.MustExecute("-y", "Work", "add", "Release/Build/Compile", "--path")
+	cli.MustExecute("-y", "Work", "add", "Release/Notes", "--path")
+	cli.MustExecute("-y", "Work", "add", "Hiring")
+
+	stdout := cli.MustExecute("-y", "Work", "--tree", "--output", "table")
+	testutil.AssertContains(t, stdout, "TREE")
+	testutil.AssertContains(t, stdout, "├─ Build")
+	testutil.AssertContains(t, stdout, "│  └─ Compile")
+	testutil.AssertContains(t, stdout, "└─ Notes")
+
+	stdout = cli.MustExecute("-y", "Work", "--depth", "1")
+	testutil.AssertContains(t, stdout, "Release (+3 hidden)")
+	testutil.AssertNotContains(t, stdout, "Build")
+
+	stdout = cli.MustExecute("-y", "Work", "--tree", "--depth", "2", "--output", "tsv")
+	testutil.AssertContains(t, stdout, "├─ Build\t")
+	testutil.AssertNotContains(t, stdout, "Compile")
+
+	stdout = cli.MustExecute("-y", "--json", "Work", "--nested")
+	var resp struct {
+		Count int `json:"count"`
+		Tasks []struct {
+			Summary  string `json:"summary"`
+			Children []struct {
+				Summary  string `json:"summary"`
+				Children []struct {
+					Summary string `json:"summary"`
+				} `json:"children"`
+			} `json:"children"`
+		} `json:"tasks"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &resp); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout)
+	}
+	if resp.Count != 5 || len(resp.Tasks) != 2 {
+		t.Fatalf("expected 5 tasks under 2 roots, got %+v", resp)
+	}
+	for _, root := range resp.Tasks {
+		if root.Summary == "Release" && (len(root.Children) != 2 || len(root.Children[0].Children)+len(root.Children[1].Children) != 1) {
+			t.Errorf("Release children = %+v", root.Children)
+		}
+	}
+
+	_, stderr := cli.ExecuteAndFail("-y", "Work", "--nested")
+	testutil.AssertContains(t, stderr, "--nested requires --json")
+	_, stderr = cli.ExecuteAndFail("-y", "Work", "--depth", "-1")
+	testutil.AssertContains(t, stderr, "invalid --depth")
+}
+
+// TestThemeColorsSQLiteCLI verifies theme colors in text and table output and
+// that --no-color and NO_COLOR turn them off
+func TestThemeColorsSQLiteCLI(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	cli := testutil.NewCLITestWithConfig(t)
+	cli.SetFullConfig(`
+backends:
+  sqlite:
+    enabled: true
+no_prompt: true
+theme:
+  color: always
+  priority_high: magenta
+`)
+
+	cli.MustExecute("-y", "Work", "add", "Ship release", "-p", "1", "--due-date", "2020-01-01")
+
+	stdout := cli.MustExecute("-y", "Work")
+	testutil.AssertContains(t, stdout, "\x1b[35m[P1]\x1b[0m")
+	testutil.AssertContains(t, stdout, "\x1b[1;31mJan 01\x1b[0m")
+
+	stdout = cli.MustExecute("-y", "Work", "--output", "table")
+	testutil.AssertContains(t, stdout, "\x1b[1mSTATUS")
+	testutil.AssertContains(t, stdout, "\x1b[35m1\x1b[0m")
+
+	stdout = cli.MustExecute("-y", "Work", "--output", "tsv")
+	testutil.AssertNotContains(t, stdout, "\x1b[")
+
+	stdout = cli.MustExecute("-y", "--no-color", "Work")
+	testutil.AssertNotContains(t, stdout, "\x1b[")
+	testutil.AssertContains(t, stdout, "[P1]")
+
+	t.Setenv("NO_COLOR", "1")
+	stdout = cli.MustExecute("-y", "Work")
+	testutil.AssertNotContains(t, stdout, "\x1b[")
+}
+
+// TestNaturalDatesAndDateDisplaySQLiteCLI verifies natural dates in date flags and
+// the dates.format and dates.relative display settings
+func TestNaturalDatesAndDateDisplaySQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITestWithConfig(t)
+	cli.SetFullConfig(`
+backends:
+  sqlite:
+    enabled: true
+no_prompt: true
+dates:
+  relative: true
+`)
+
+	cli.MustExecute("-y", "Work", "add", "Call back", "--due-date", "in 3 days")
+	cli.MustExecute("-y", "Work", "add", "Late report", "--due-date", "2 days ago")
+	cli.MustExecute("-y", "Work", "add", "Standup", "--due-date", "tomorrow at 9am")
+
+	stdout := cli.MustExecute("-y", "Work")
+	testutil.AssertContains(t, stdout, "in 3 days")
+	testutil.AssertContains(t, stdout, "2d overdue")
+	testutil.AssertContains(t, stdout, "tomorrow 09:00")
+
+	stdout = cli.MustExecute("-y", "Work", "--due-before", "in 1 week", "--due-after", "today")
+	testutil.AssertContains(t, stdout, "Call back")
+	testutil.AssertNotContains(t, stdout, "Late report")
+
+	_, stderr := cli.ExecuteAndFail("-y", "Work", "add", "Bad", "--due-date", "someday")
+	testutil.AssertContains(t, stderr, "invalid date")
+
+	cli.SetFullConfig(`
+backends:
+  sqlite:
+    enabled: true
+no_prompt: true
+dates:
+  format: eu
+`)
+	cli.MustExecute("-y", "Work", "add", "Renew passport", "--due-date", "15/01/2030")
+	stdout = cli.MustExecute("-y", "Work")
+	testutil.AssertContains(t, stdout, "15/01/2030")
+}
+
+// TestDueTimesSQLiteCLI verifies due dates keep a time of day through add, update,
+// JSON output and iCalendar export
+func TestDueTimesSQLiteCLI(t *testing.T) {
+	t.Cleanup(func() { _ = utils.SetTimezone("") })
+	cli := testutil.NewCLITestWithConfig(t)
+	cli.SetFullConfig(`
+backends:
+  sqlite:
+    enabled: true
+no_prompt: true
+timezone: Europe/Paris
+`)
+
+	cli.MustExecute("-y", "Work", "add", "Submit report", "--due-date", "2030-06-01 17:00")
+	stdout := cli.MustExecute("-y", "--json", "Work")
+	testutil.AssertContains(t, stdout, "2030-06-01T17:00:00")
+
+	cli.MustExecute("-y", "Work", "update", "Submit report", "--due-time", "9am")
+	stdout = cli.MustExecute("-y", "--json", "Work")
+	testutil.AssertContains(t, stdout, "2030-06-01T09:00:00")
+
+	cli.MustExecute("-y", "Work", "add", "Pay rent", "--due-date", "2030-07-01", "--due-time", "08:30")
+	stdout = cli.MustExecute("-y", "--json", "Work")
+	testutil.AssertContains(t, stdout, "2030-07-01T08:30:00")
+
+	cli.MustExecute("-y", "Work", "update", "Submit report", "--due-time", "")
+	exportPath := filepath.Join(cli.TmpDir(), "work.ics")
+	cli.MustExecute("-y", "list", "export", "Work", "--format", "ical", "--output", exportPath)
+	data, err := os.ReadFile(exportPath)
+	if err != nil {
+		t.Fatalf("failed to read export file: %v", err)
+	}
+	testutil.AssertContains(t, string(data), "DUE;VALUE=DATE:20300601")
+	testutil.AssertContains(t, string(data), "DUE:20300701T063000Z")
+
+	_, stderr := cli.ExecuteAndFail("-y", "Work", "add", "No date", "--due-time", "17:00")
+	testutil.AssertContains(t, stderr, "--due-time requires a due date")
+}
+
+// TestTimezoneSQLiteCLI verifies dates are entered and shown in the configured
+// timezone and recurring due times keep their wall-clock time across DST
+func TestTimezoneSQLiteCLI(t *testing.T) {
+	t.Cleanup(func() { _ = utils.SetTimezone("") })
+	cli := testutil.NewCLITestWithConfig(t)
+	cli.SetFullConfig(`
+backends:
+  sqlite:
+    enabled: true
+no_prompt: true
+timezone: America/New_York
+`)
+
+	cli.MustExecute("-y", "Work", "add", "Renew passport", "--due-date", "2030-06-01")
+	cli.MustExecute("-y", "Work", "add", "Water plants", "--due-date", "2030-03-09 09:00", "--recur", "daily")
+	stdout := cli.MustExecute("-y", "--json", "Work")
+	testutil.AssertContains(t, stdout, `"2030-06-01"`)
+	testutil.AssertContains(t, stdout, "2030-03-09T09:00:00-05:00")
+
+	// Daylight saving time starts on 2030-03-10 in New York
+	cli.MustExecute("-y", "Work", "complete", "Water plants")
+	stdout = cli.MustExecute("-y", "--json", "Work")
+	testutil.AssertContains(t, stdout, "2030-03-10T09:00:00-04:00")
+
+	cli.SetFullConfig(`
+backends:
+  sqlite:
+    enabled: true
+no_prompt: true
+timezone: Mars/Olympus
+`)
+	_, stderr := cli.ExecuteAndFail("-y", "Work")
+	testutil.AssertContains(t, stderr, "unknown timezone")
+}
+
+// TestTimezoneChangeKeepsDueDaySQLiteCLI verifies that a due date without a time
+// of day stays on its day when the timezone changes
+func TestTimezoneChangeKeepsDueDaySQLiteCLI(t *testing.T) {
+	t.Cleanup(func() { _ = utils.SetTimezone("") })
+	cli := testutil.NewCLITestWithConfig(t)
+	cli.SetFullConfig(`
+backends:
+  sqlite:
+    enabled: true
+no_prompt: true
+timezone: Europe/Berlin
+`)
+	cli.MustExecute("-y", "Work", "add", "Renew passport", "--due-date", "2030-06-01")
+	cli.MustExecute("-y", "Work", "add", "Call bank", "--due-date", "2030-06-01 09:00")
+
+	cli.SetFullConfig(`
+backends:
+  sqlite:
+    enabled: true
+no_prompt: true
+timezone: America/New_York
+`)
+	stdout := cli.MustExecute("-y", "--json", "Work")
+	testutil.AssertContains(t, stdout, `"2030-06-01"`)
+	testutil.AssertNotContains(t, stdout, "2030-05-31")
+	// A due time is the same instant, shown in the new timezone
+	testutil.AssertContains(t, stdout, "2030-06-01T03:00:00-04:00")
+}
+
+// TestReadOnlySQLiteCLI verifies --read-only and read_only: true reject changes but allow reads
+func TestReadOnlySQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITestWithConfig(t)
+	cli.SetFullConfig(`
+backends:
+  sqlite:
+    enabled: true
+no_prompt: true
+`)
+	cli.MustExecute("-y", "Team", "add", "Review budget")
+
+	_, stderr := cli.ExecuteAndFail("-y", "--read-only", "Team", "add", "Another")
+	testutil.AssertContains(t, stderr, "read-only")
+	_, stderr = cli.ExecuteAndFail("-y", "--read-only", "Team", "complete", "Review budget")
+	testutil.AssertContains(t, stderr, "read-only")
+	stdout := cli.MustExecute("-y", "--read-only", "Team")
+	testutil.AssertContains(t, stdout, "Review budget")
+
+	cli.SetFullConfig(`
+backends:
+  sqlite:
+    enabled: true
+    read_only: true
+no_prompt: true
+`)
+	_, stderr = cli.ExecuteAndFail("-y", "list", "create", "Other")
+	testutil.AssertContains(t, stderr, "backends.sqlite.read_only is set")
+	_, stderr = cli.ExecuteAndFail("-y", "Team", "delete", "Review budget")
+	testutil.AssertContains(t, stderr, "read-only")
+	stdout = cli.MustExecute("-y", "--json", "Team")
+	testutil.AssertContains(t, stdout, "Review budget")
+}
+
+// TestDryRunSQLiteCLI verifies --dry-run reports what delete, bulk operations,
+// trash purge and import would change without changing anything
+func TestDryRunSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+
+	cli.MustExecute("-y", "list", "create", "DryRunTest")
+	cli.MustExecute("-y", "DryRunTest", "add", "Parent")
+	cli.MustExecute("-y", "DryRunTest", "add", "Child1", "-P", "Parent")
+	cli.MustExecute("-y", "DryRunTest", "add", "Child2", "-P", "Parent")
+	cli.MustExecute("-y", "DryRunTest", "add", "GrandChild", "-P", "Child1")
+
+	stdout := cli.MustExecute("-y", "--dry-run", "DryRunTest", "delete", "Parent/**")
+	testutil.AssertContains(t, stdout, `Dry run: would delete 3 tasks under "Parent"`)
+	testutil.AssertContains(t, stdout, "GrandChild (")
+	testutil.AssertResultCode(t, stdout, testutil.ResultInfoOnly)
+
+	stdout = cli.MustExecute("-y", "--dry-run", "--json", "DryRunTest", "delete", "Parent/**")
+	var plan struct {
+		DryRun bool   `json:"dry_run"`
+		Action string `json:"action"`
+		Count  int    `json:"count"`
+		Items  []struct {
+			UID     string `json:"uid"`
+			Summary string `json:"summary"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &plan); err != nil {
+		t.Fatalf("invalid JSON plan: %v\n%s", err, stdout)
+	}
+	if !plan.DryRun || plan.Action != "delete" || plan.Count != 3 || len(plan.Items) != 3 || plan.Items[0].UID == "" {
+		t.Errorf("unexpected plan: %+v", plan)
+	}
+
+	stdout = cli.MustExecute("-y", "--dry-run", "DryRunTest", "delete", "Parent")
+	testutil.AssertContains(t, stdout, `would delete task "Parent" and 3 subtasks`)
+	stdout = cli.MustExecute("-y", "--dry-run", "DryRunTest", "update", "Parent/*", "-p", "2")
+	testutil.AssertContains(t, stdout, "priority → 2")
+	cli.MustExecute("-y", "--dry-run", "DryRunTest", "complete", "Parent/**")
+
+	stdout = cli.MustExecute("-y", "--json", "DryRunTest")
+	for _, summary := range []string{"Parent", "Child1", "Child2", "GrandChild"} {
+		testutil.AssertContains(t, stdout, summary)
+	}
+	testutil.AssertNotContains(t, stdout, "COMPLETED")
+
+	cli.MustExecute("-y", "list", "delete", "DryRunTest")
+	stdout = cli.MustExecute("-y", "--dry-run", "list", "trash", "purge", "DryRunTest")
+	testutil.AssertContains(t, stdout, `would permanently delete list "DryRunTest" with 4 tasks`)
+	stdout = cli.MustExecute("-y", "list", "trash")
+	testutil.AssertContains(t, stdout, "DryRunTest")
+
+	ics := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n" +
+		"BEGIN:VTODO\r\nUID:dry-1\r\nSUMMARY:Pack bags\r\nEND:VTODO\r\n" +
+		"END:VCALENDAR\r\n"
+	importPath := cli.TmpDir() + "/Weekend.ics"
+	if err := os.WriteFile(importPath, []byte(ics), 0644); err != nil {
+		t.Fatalf("failed to write import file: %v", err)
+	}
+	stdout = cli.MustExecute("-y", "--dry-run", "list", "import", importPath)
+	testutil.AssertContains(t, stdout, `would create list "Weekend" with 1 task`)
+	testutil.AssertContains(t, stdout, "Pack bags (dry-1)")
+	stdout = cli.MustExecute("-y", "list")
+	testutil.AssertNotContains(t, stdout, "Weekend")
+}
+
+// TestDryRunRejectedByUnsupportedCommandsSQLiteCLI verifies --dry-run fails
+// on commands that would otherwise ignore it and make their changes
+func TestDryRunRejectedByUnsupportedCommandsSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+
+	cli.MustExecute("-y", "list", "create", "Work")
+	cli.MustExecute("-y", "Work", "add", "Write report")
+
+	_, stderr := cli.ExecuteAndFail("-y", "--dry-run", "Work", "complete", "Write report")
+	testutil.AssertContains(t, stderr, "--dry-run is not supported")
+	_, stderr = cli.ExecuteAndFail("-y", "--dry-run", "Work", "update", "Write report", "-p", "1")
+	testutil.AssertContains(t, stderr, "--dry-run is not supported")
+	_, stderr = cli.ExecuteAndFail("-y", "--dry-run", "db", "restore", filepath.Join(cli.TmpDir(), "backup.db"))
+	testutil.AssertContains(t, stderr, "--dry-run is not supported by 'todoat db restore'")
+
+	stdout := cli.MustExecute("-y", "--json", "Work")
+	testutil.AssertNotContains(t, stdout, "COMPLETED")
+	testutil.AssertNotContains(t, stdout, `"priority":1`)
+
+	stdout = cli.MustExecute("-y", "--dry-run", "Work", "delete", "Write report")
+	testutil.AssertContains(t, stdout, `would delete task "Write report"`)
+}
+
+// TestDeleteConfirmationSQLiteCLI verifies cascade and bulk deletes list the
+// affected tasks and ask before deleting when prompts are enabled
+func TestDeleteConfirmationSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+
+	cli.MustExecute("-y", "list", "create", "ConfirmTest")
+	cli.MustExecute("-y", "ConfirmTest", "add", "Parent")
+	cli.MustExecute("-y", "ConfirmTest", "add", "Child1", "-P", "Parent")
+	cli.MustExecute("-y", "ConfirmTest", "add", "Child2", "-P", "Parent")
+	cli.MustExecute("-y", "ConfirmTest", "add", "Solo")
+
+	cli.Config().NoPrompt = false
+
+	stdout, _, exitCode := cli.ExecuteWithStdin("n\n", "ConfirmTest", "delete", "Parent")
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", exitCode, stdout)
+	}
+	testutil.AssertContains(t, stdout, "This will delete 3 tasks:")
+	testutil.AssertContains(t, stdout, "  - Child2")
+	testutil.AssertContains(t, stdout, "Continue? [y/N]")
+	testutil.AssertContains(t, stdout, "Cancelled.")
+
+	stdout, _, _ = cli.ExecuteWithStdin("\n", "ConfirmTest", "delete", "Parent/*")
+	testutil.AssertContains(t, stdout, "This will delete 2 tasks:")
+	testutil.AssertContains(t, stdout, "Cancelled.")
+
+	stdout, _, _ = cli.ExecuteWithStdin("y\n", "ConfirmTest", "delete", "Parent/*")
+	testutil.AssertContains(t, stdout, `Deleted 2 tasks under "Parent"`)
+
+	// A task without subtasks is deleted without asking
+	stdout, _, _ = cli.ExecuteWithStdin("", "ConfirmTest", "delete", "Solo")
+	testutil.AssertNotContains(t, stdout, "Continue?")
+	testutil.AssertContains(t, stdout, "Deleted task: Solo")
+
+	cli.Config().NoPrompt = true
+	stdout = cli.MustExecute("-y", "ConfirmTest")
+	testutil.AssertContains(t, stdout, "Parent")
+	testutil.AssertNotContains(t, stdout, "Child1")
+}
+
+// TestStatsSQLiteCLI verifies `todoat stats` computes completion, overdue, weekly
+// and tag statistics from the tasks of every list
+func TestStatsSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+
+	cli.MustExecute("-y", "Work", "add", "Write report", "--tag", "writing", "--due-date", "2020-01-01")
+	cli.MustExecute("-y", "Work", "add", "Review PR", "--tag", "code,writing")
+	cli.MustExecute("-y", "Work", "complete", "Review PR")
+	cli.MustExecute("-y", "Home", "add", "Water plants")
+	cli.MustExecute("-y", "Home", "add", "Fix sink")
+	cli.MustExecute("-y", "Home", "update", "Fix sink", "-s", "CANCELLED")
+
+	stdout := cli.MustExecute("-y", "stats")
+	testutil.AssertContains(t, stdout, "Tasks: 4 (2 open, 1 completed, 1 cancelled)")
+	testutil.AssertContains(t, stdout, "33%")
+	testutil.AssertContains(t, stdout, "Overdue:     1")
+	testutil.AssertContains(t, stdout, "This week:   4 added, 1 completed")
+	testutil.AssertContains(t, stdout, "Oldest open:")
+	testutil.AssertContains(t, stdout, "writing")
+	testutil.AssertContains(t, stdout, "1/2 done, 1 overdue")
+
+	stdout = cli.MustExecute("-y", "--json", "stats", "--list", "Work")
+	var report struct {
+		Totals struct {
+			Total           int     `json:"total"`
+			Overdue         int     `json:"overdue"`
+			CompletionRatio float64 `json:"completion_ratio"`
+		} `json:"totals"`
+		AddedPerWeek []int `json:"added_per_week"`
+		Tags         []struct {
+			Name  string `json:"name"`
+			Count int    `json:"count"`
+		} `json:"tags"`
+		Result string `json:"result"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &report); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout)
+	}
+	if report.Totals.Total != 2 || report.Totals.Overdue != 1 || report.Totals.CompletionRatio != 0.5 {
+		t.Errorf("unexpected totals: %+v", report.Totals)
+	}
+	if len(report.AddedPerWeek) != 8 || report.AddedPerWeek[7] != 2 {
+		t.Errorf("unexpected added_per_week: %v", report.AddedPerWeek)
+	}
+	if len(report.Tags) != 2 || report.Tags[0].Name != "writing" || report.Tags[0].Count != 2 {
+		t.Errorf("unexpected tags: %+v", report.Tags)
+	}
+	if report.Result != testutil.ResultInfoOnly {
+		t.Errorf("result = %q, want %q", report.Result, testutil.ResultInfoOnly)
+	}
+}
+
+// TestCalendarPublishSQLiteCLI verifies `todoat calendar publish` writes due
+// dates as calendar events and leaves an unchanged file alone
+func TestCalendarPublishSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+
+	cli.MustExecute("-y", "Work", "add", "Quarterly report", "--due-date", "2030-03-31")
+	cli.MustExecute("-y", "Work", "add", "Standup", "--due-date", "2030-03-30 09:30")
+	cli.MustExecute("-y", "Home", "add", "Someday task")
+
+	output := filepath.Join(cli.TmpDir(), "tasks.ics")
+	stdout := cli.MustExecute("-y", "calendar", "publish", "--output", output)
+	testutil.AssertContains(t, stdout, "Published 2 tasks with due dates to "+output)
+	testutil.AssertResultCode(t, stdout, testutil.ResultActionCompleted)
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("failed to read feed: %v", err)
+	}
+	feed := string(data)
+	testutil.AssertContains(t, feed, "BEGIN:VEVENT")
+	testutil.AssertContains(t, feed, "DTSTART;VALUE=DATE:20300331")
+	testutil.AssertContains(t, feed, "DTSTART:"+time.Date(2030, 3, 30, 9, 30, 0, 0, time.Local).UTC().Format("20060102T150405Z"))
+	testutil.AssertNotContains(t, feed, "Someday task")
+	if strings.Index(feed, "Standup") > strings.Index(feed, "Quarterly report") {
+		t.Error("events should be ordered by due date")
+	}
+
+	// Regenerating without changes gives the same feed
+	cli.MustExecute("-y", "calendar", "publish", "--output", output)
+	again, _ := os.ReadFile(output)
+	if string(again) != feed {
+		t.Error("feed changed although no task changed")
+	}
+
+	stdout = cli.MustExecute("-y", "calendar", "publish", "--type", "todo", "--list", "Work")
+	testutil.AssertContains(t, stdout, "BEGIN:VTODO")
+	testutil.AssertContains(t, stdout, "X-WR-CALNAME:todoat: Work")
+
+	_, stderr := cli.ExecuteAndFail("-y", "calendar", "publish", "--serve", "127.0.0.1:0")
+	testutil.AssertContains(t, stderr, "rest_server is an experimental feature")
+}
+
+// TestRemoteBackendSQLiteCLI verifies a remote backend reads and writes another instance's database
+func TestRemoteBackendSQLiteCLI(t *testing.T) {
+	ctx := context.Background()
+	cli := testutil.NewCLITestWithConfig(t)
+
+	server, err := sqlite.New(filepath.Join(cli.TmpDir(), "server.db"))
+	if err != nil {
+		t.Fatalf("sqlite.New error: %v", err)
+	}
+	defer func() { _ = server.Close() }()
+	list, err := server.CreateList(ctx, "Home")
+	if err != nil {
+		t.Fatalf("CreateList error: %v", err)
+	}
+	if _, err := server.CreateTask(ctx, list.ID, &backend.Task{Summary: "Fix the fence"}); err != nil {
+		t.Fatalf("CreateTask error: %v", err)
+	}
+	srv := httptest.NewServer(remote.NewHandler(server, "secret"))
+	defer srv.Close()
+
+	cli.SetFullConfig("default_backend: homeserver\nbackends:\n  homeserver:\n    type: remote\n    enabled: true\n    url: " + srv.URL + "\n    token: secret\n    allow_http: true\n")
+
+	testutil.AssertContains(t, cli.MustExecute("-y", "Home"), "Fix the fence")
+	cli.MustExecute("-y", "Home", "add", "Paint the shed")
+	tasks, err := server.GetTasks(ctx, list.ID)
+	if err != nil || len(tasks) != 2 {
+		t.Fatalf("server has %d tasks (%v), want 2", len(tasks), err)
+	}
+
+	cli.SetFullConfig("default_backend: homeserver\nbackends:\n  homeserver:\n    type: remote\n    enabled: true\n    url: " + srv.URL + "\n    token: wrong\n    allow_http: true\n")
+	_, stderr := cli.ExecuteAndFail("-y", "Home")
+	testutil.AssertContains(t, stderr, "invalid remote server token")
+
+	_, stderr = cli.ExecuteAndFail("-y", "serve", "--token", "secret")
+	testutil.AssertContains(t, stderr, "rest_server is an experimental feature")
+}
+
+// TestAssigneeSQLiteCLI verifies --assignee adds, updates and filters @name tags
+func TestAssigneeSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+
+	cli.MustExecute("-y", "Chores", "add", "Mow lawn", "--assignee", "alice")
+	cli.MustExecute("-y", "Chores", "add", "Clean gutters")
+	cli.MustExecute("-y", "Chores", "update", "Clean gutters", "--assignee", "@bob")
+
+	stdout := cli.MustExecute("-y", "Chores", "--assignee", "alice")
+	testutil.AssertContains(t, stdout, "Mow lawn")
+	testutil.AssertNotContains(t, stdout, "Clean gutters")
+
+	stdout = cli.MustExecute("-y", "Chores", "--tag", "@bob")
+	testutil.AssertContains(t, stdout, "Clean gutters")
+	testutil.AssertNotContains(t, stdout, "Mow lawn")
+}
+
+// TestCustomStatusesSQLiteCLI verifies custom statuses in updates, filters and JSON, and transition checks
+func TestCustomStatusesSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITestWithConfig(t)
+	cli.SetFullConfig(`default_backend: sqlite
+statuses:
+  custom:
+    - name: WAITING
+    - name: REVIEW
+      base: IN-PROGRESS
+  transitions:
+    WAITING: [TODO, IN-PROGRESS]
+`)
+
+	cli.MustExecute("-y", "Work", "add", "Invoice")
+	cli.MustExecute("-y", "Work", "add", "Report")
+	cli.MustExecute("-y", "Work", "update", "Invoice", "--status", "waiting")
+	cli.MustExecute("-y", "Work", "update", "Report", "-s", "REVIEW")
+
+	stdout := cli.MustExecute("-y", "Work", "-s", "WAITING")
+	testutil.AssertContains(t, stdout, "Invoice")
+	testutil.AssertNotContains(t, stdout, "Report")
+	testutil.AssertContains(t, cli.MustExecute("-y", "--json", "Work", "-s", "REVIEW"), `"status":"REVIEW"`)
+
+	_, stderr := cli.ExecuteAndFail("-y", "Work", "complete", "Invoice")
+	testutil.AssertContains(t, stderr, "status cannot change from WAITING to DONE")
+	cli.MustExecute("-y", "Work", "update", "Invoice", "-s", "TODO")
+	cli.MustExecute("-y", "Work", "complete", "Invoice")
+
+	_, stderr = cli.ExecuteAndFail("-y", "Work", "update", "Report", "-s", "LATER")
+	testutil.AssertContains(t, stderr, "TODO, IN-PROGRESS, DONE, CANCELLED, WAITING, REVIEW")
+}
+
+// TestRulesRunSQLiteCLI verifies rules escalate and tag matching open tasks, with --dry-run only reporting
+func TestRulesRunSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITestWithConfig(t)
+	cli.SetFullConfig(`default_backend: sqlite
+rules:
+  - name: escalate
+    when:
+      - {field: due_date, operator: lte, value: "+2d"}
+      - {field: priority, operator: gt, value: 3}
+    then:
+      set_priority: 2
+  - name: waiting
+    lists: [Home]
+    when:
+      - {field: summary, operator: contains, value: "call"}
+    then:
+      add_tags: [phone]
+`)
+
+	cli.MustExecute("-y", "Work", "add", "Ship release", "--due-date", "tomorrow", "-p", "5")
+	cli.MustExecute("-y", "Work", "add", "Plan offsite", "--due-date", "+10d", "-p", "5")
+	cli.MustExecute("-y", "Work", "add", "Call vendor")
+	cli.MustExecute("-y", "Home", "add", "Call plumber")
+
+	stdout := cli.MustExecute("-y", "rules", "list")
+	testutil.AssertContains(t, stdout, "escalate (all lists)")
+	testutil.AssertContains(t, stdout, "then: add tag phone")
+
+	stdout = cli.MustExecute("-y", "--dry-run", "rules", "run")
+	testutil.AssertContains(t, stdout, "Dry run: rules would take 2 action(s)")
+	testutil.AssertContains(t, stdout, "[Work] Ship release: priority 5 -> 2 (escalate)")
+	testutil.AssertContains(t, stdout, "[Home] Call plumber: add tag phone (waiting)")
+	testutil.AssertNotContains(t, stdout, "Plan offsite")
+	testutil.AssertNotContains(t, stdout, "Call vendor")
+	testutil.AssertContains(t, cli.MustExecute("-y", "--json", "Work"), `"priority":5`)
+
+	stdout = cli.MustExecute("-y", "rules", "run")
+	testutil.AssertContains(t, stdout, "Rules took 2 action(s)")
+	testutil.AssertResultCode(t, stdout, "ACTION_COMPLETED")
+	testutil.AssertContains(t, cli.MustExecute("-y", "Home", "--tag", "phone"), "Call plumber")
+
+	stdout = cli.MustExecute("-y", "rules", "run")
+	testutil.AssertContains(t, stdout, "No rule actions to take")
+}
+
+// TestRulesInvalidConfigSQLiteCLI verifies invalid rules are rejected with the rule name
+func TestRulesInvalidConfigSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITestWithConfig(t)
+	cli.SetFullConfig(`default_backend: sqlite
+rules:
+  - name: broken
+    when:
+      - {field: colour, operator: eq, value: red}
+    then:
+      set_priority: 1
+`)
+
+	_, stderr := cli.ExecuteAndFail("-y", "rules", "run")
+	testutil.AssertContains(t, stderr, "rules: broken: unknown filter field: colour")
+}
+
+// stepClock is a focus clock that advances by the requested duration on every After call
+type stepClock struct {
+	now time.Time
+}
+
+func (c *stepClock) Now() time.Time { return c.now }
+
+func (c *stepClock) After(d time.Duration) <-chan time.Time {
+	c.now = c.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+// TestFocusSessionSQLiteCLI verifies a focus session marks the task IN-PROGRESS, notifies and logs the time spent
+func TestFocusSessionSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITestWithReminder(t)
+	cli.SetReminderConfig(&reminder.Config{Enabled: true, LogNotification: true})
+	cli.Config().FocusClock = &stepClock{now: time.Now()}
+
+	cli.MustExecute("-y", "Work", "add", "Write report")
+	cli.MustExecute("-y", "Work", "add", "Review report")
+	cli.MustExecute("-y", "Home", "add", "Write letter")
+
+	stdout := cli.MustExecute("-y", "focus", "Write report", "--duration", "25m", "--start")
+	testutil.AssertContains(t, stdout, "Focusing on 'Write report' for 25m0s")
+	testutil.AssertContains(t, stdout, "Focus session on 'Write report' complete")
+	testutil.AssertContains(t, stdout, "Logged 25m (total spent: 25m)")
+	testutil.AssertContains(t, cli.GetNotificationLog(), "Focus session on 'Write report' complete (25m)")
+
+	stdout = cli.MustExecute("-y", "--json", "focus", "write REPORT", "-d", "1h")
+	testutil.AssertContains(t, stdout, `"spent":"1h25m"`)
+	testutil.AssertContains(t, stdout, `"completed":true`)
+
+	stdout = cli.MustExecute("-y", "--json", "Work")
+	testutil.AssertContains(t, stdout, `"status":"IN-PROGRESS"`)
+	testutil.AssertContains(t, stdout, `"spent":"1h25m"`)
+
+	_, stderr := cli.ExecuteAndFail("-y", "focus", "Write")
+	testutil.AssertContains(t, stderr, "multiple tasks match 'Write'")
+	cli.MustExecute("-y", "focus", "Write", "--list", "Home", "-d", "5m")
+
+	_, stderr = cli.ExecuteAndFail("-y", "focus", "Write report", "-d", "soon")
+	testutil.AssertContains(t, stderr, "invalid --duration")
+}
+
+// TestReviewSQLiteCLI verifies `todoat review` walks through overdue and undated
+// tasks, applies the chosen actions and reports the remaining tasks as JSON
+func TestReviewSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+
+	cli.MustExecute("-y", "Work", "add", "Pay invoice", "--due-date", "2020-01-01")
+	cli.MustExecute("-y", "Work", "add", "Renew passport", "--due-date", "2020-02-01")
+	cli.MustExecute("-y", "Work", "add", "Someday idea")
+	cli.MustExecute("-y", "Work", "add", "Plan trip", "--due-date", "2099-01-01")
+	cli.MustExecute("-y", "Home", "add", "Fix sink")
+
+	stdout := cli.MustExecute("-y", "review")
+	testutil.AssertContains(t, stdout, "Overdue (2):")
+	testutil.AssertContains(t, stdout, "Pay invoice (due 2020-01-01)")
+	testutil.AssertContains(t, stdout, "No due date (1):")
+	testutil.AssertContains(t, stdout, "Summary: 2 overdue, 0 stale, 2 without due date")
+	testutil.AssertNotContains(t, stdout, "Plan trip")
+
+	cli.Config().NoPrompt = false
+	input := "x\nr\nbogus\n2099-02-01\nc\nd\n\n"
+	stdout, _, exitCode := cli.ExecuteWithStdin(input, "review")
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", exitCode, stdout)
+	}
+	testutil.AssertContains(t, stdout, `Unknown choice "x"`)
+	testutil.AssertContains(t, stdout, `Invalid date "bogus"`)
+	testutil.AssertContains(t, stdout, "Rescheduled to 2099-02-01")
+	testutil.AssertContains(t, stdout, "Review complete: 4 of 4 reviewed (2 overdue, 0 stale, 2 without due date)")
+	testutil.AssertContains(t, stdout, "1 rescheduled, 1 completed, 1 deleted, 1 kept")
+	cli.Config().NoPrompt = true
+
+	stdout = cli.MustExecute("-y", "review", "--report")
+	testutil.AssertContains(t, stdout, `"summary":"Fix sink"`)
+	testutil.AssertContains(t, stdout, `"category":"no_due_date"`)
+	testutil.AssertContains(t, stdout, `"counts":{"no_due_date":1,"overdue":0,"stale":0}`)
+	testutil.AssertNotContains(t, stdout, "Pay invoice")
+	testutil.AssertNotContains(t, stdout, "Someday idea")
+
+	stdout = cli.MustExecute("-y", "review", "--list", "Work")
+	testutil.AssertContains(t, stdout, "Nothing to review")
+}
+
+// TestAddDuplicateCheckSQLiteCLI verifies that with duplicates.check an add of a
+// near-duplicate of an open task fails without --force, or asks when prompting
+func TestAddDuplicateCheckSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITestWithConfig(t)
+	cli.SetFullConfig(`default_backend: sqlite
+duplicates:
+  check: true
+`)
+
+	cli.MustExecute("-y", "Work", "add", "Renew passport")
+	cli.MustExecute("-y", "Work", "add", "Buy milk")
+	cli.MustExecute("-y", "Work", "complete", "Buy milk")
+
+	_, stderr := cli.ExecuteAndFail("-y", "Work", "add", "renew pasport!")
+	testutil.AssertContains(t, stderr, "similar task already exists in 'Work': 'Renew passport' (use --force to add anyway)")
+
+	// Completed tasks and other lists are not duplicates
+	cli.MustExecute("-y", "Work", "add", "Buy milk")
+	cli.MustExecute("-y", "Home", "add", "Renew passport")
+	cli.MustExecute("-y", "Work", "add", "Renew car insurance")
+
+	stdout := cli.MustExecute("-y", "Work", "add", "Renew passport", "--force")
+	testutil.AssertContains(t, stdout, "Created task: Renew passport")
+
+	cli.Config().NoPrompt = false
+	stdout, _, _ = cli.ExecuteWithStdin("n\n", "Work", "add", "Buy milk.")
+	testutil.AssertContains(t, stdout, "Similar task already exists in 'Work':")
+	testutil.AssertContains(t, stdout, "  - Buy milk")
+	testutil.AssertContains(t, stdout, "Cancelled.")
+	stdout, _, _ = cli.ExecuteWithStdin("y\n", "Work", "add", "Buy milk.")
+	testutil.AssertContains(t, stdout, "Created task: Buy milk.")
+	cli.Config().NoPrompt = true
+
+	cli.MustExecute("-y", "config", "set", "duplicates.check", "false")
+	cli.MustExecute("-y", "Work", "add", "Renew passport")
+}
+
+// TestRowNumberReferencesSQLiteCLI verifies that with ui.row_numbers the rows of
+// a listing are numbered and the numbers select tasks in the next commands
+func TestRowNumberReferencesSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITestWithConfig(t)
+	cli.SetFullConfig(`default_backend: sqlite
+ui:
+  row_numbers: true
+`)
+
+	cli.MustExecute("-y", "Work", "add", "Release")
+	cli.MustExecute("-y", "Work", "add", "Release/Build", "--path")
+	cli.MustExecute("-y", "Work", "add", "Hiring")
+	cli.MustExecute("-y", "Home", "add", "Water plants")
+
+	// Before any listing, a number is a summary
+	_, stderr := cli.ExecuteAndFail("-y", "Work", "complete", "2")
+	testutil.AssertContains(t, stderr, "no task found")
+
+	stdout := cli.MustExecute("-y", "Work")
+	testutil.AssertContains(t, stdout, "1   [TODO]")
+	testutil.AssertContains(t, stdout, "2   └─ [TODO]")
+	testutil.AssertContains(t, stdout, "3   [TODO]")
+
+	stdout = cli.MustExecute("-y", "Work", "complete", "2")
+	testutil.AssertContains(t, stdout, "Completed task: Build")
+	stdout = cli.MustExecute("-y", "Work", "update", "3", "-p", "1")
+	testutil.AssertContains(t, stdout, "Hiring")
+
+	_, stderr = cli.ExecuteAndFail("-y", "Work", "delete", "7")
+	testutil.AssertContains(t, stderr, "row 7 is not in the last listing of 'Work' (rows 1-3)")
+
+	// The rows belong to the listed list only
+	_, stderr = cli.ExecuteAndFail("-y", "Home", "complete", "1")
+	testutil.AssertContains(t, stderr, "no task found")
+}
+
+func TestDemoBackendSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITestWithConfig(t)
+	cli.SetFullConfig("default_backend: sqlite\n")
+	cli.Config().DemoDBPath = filepath.Join(t.TempDir(), "demo.db")
+
+	stdout := cli.MustExecute("-y", "-b", "demo", "MyProjects")
+	testutil.AssertContains(t, stdout, "Launch personal website")
+	testutil.AssertContains(t, stdout, "Write the About page")
+
+	stdout = cli.MustExecute("-y", "-b", "demo", "list")
+	testutil.AssertContains(t, stdout, "Home")
+	testutil.AssertContains(t, stdout, "Reading")
+
+	// Changes persist in the demo database but never reach the real one
+	cli.MustExecute("-y", "-b", "demo", "MyProjects", "add", "Try todoat")
+	stdout = cli.MustExecute("-y", "-b", "demo", "MyProjects")
+	testutil.AssertContains(t, stdout, "Try todoat")
+	cli.Config().Backend = "" // -b sticks to the shared test config
+	stdout = cli.MustExecute("-y", "list")
+	testutil.AssertNotContains(t, stdout, "MyProjects")
+
+	stdout = cli.MustExecute("-y", "demo", "reset")
+	testutil.AssertContains(t, stdout, "Demo data reset: 3 lists")
+	stdout = cli.MustExecute("-y", "-b", "demo", "MyProjects")
+	testutil.AssertNotContains(t, stdout, "Try todoat")
+	testutil.AssertContains(t, stdout, "Launch personal website")
+}
+
+// TestDBMigrateStatusAndRevertCLI verifies 'db migrate' lists, applies and reverts schema migrations
+func TestDBMigrateStatusAndRevertCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+	cli.MustExecute("-y", "list", "create", "Work")
+	latest := sqlite.SchemaMigrator().Latest()
+
+	stdout := cli.MustExecute("-y", "db", "migrate", "--status")
+	testutil.AssertContains(t, stdout, "tasks schema: version "+strconv.Itoa(latest)+" of "+strconv.Itoa(latest))
+	testutil.AssertContains(t, stdout, "add_list_shared_by")
+	testutil.AssertContains(t, stdout, "initial_sync_schema")
+
+	cli.MustExecute("-y", "db", "migrate")
+	stdout = cli.MustExecute("-y", "db", "migrate")
+	testutil.AssertContains(t, stdout, "tasks schema: up to date at version "+strconv.Itoa(latest))
+	testutil.AssertContains(t, stdout, "sync schema: up to date")
+
+	stdout = cli.MustExecute("-y", "db", "migrate", "--schema", "tasks", "--to", strconv.Itoa(latest-1))
+	testutil.AssertContains(t, stdout, "tasks schema: reverted "+strconv.Itoa(latest)+"_")
+	testutil.AssertContains(t, stdout, "now at version "+strconv.Itoa(latest-1))
+
+	// --dry-run lists the plan and leaves the schema as it is
+	stdout = cli.MustExecute("-y", "--dry-run", "db", "migrate", "--schema", "tasks")
+	testutil.AssertContains(t, stdout, "would apply tasks schema migrations "+strconv.Itoa(latest)+"_")
+	testutil.AssertContains(t, stdout, "No changes made")
+	stdout = cli.MustExecute("-y", "--dry-run", "db", "migrate", "--schema", "tasks", "--to", strconv.Itoa(latest-2))
+	testutil.AssertContains(t, stdout, "would revert tasks schema migrations "+strconv.Itoa(latest-1)+"_")
+	stdout = cli.MustExecute("-y", "--json", "--dry-run", "db", "migrate", "--schema", "tasks")
+	testutil.AssertContains(t, stdout, `"dry_run":true`)
+	testutil.AssertContains(t, stdout, `"applied":["`+strconv.Itoa(latest)+"_")
+
+	stdout = cli.MustExecute("-y", "--json", "db", "migrate", "--status", "--schema", "tasks")
+	var status []struct {
+		Version    int `json:"version"`
+		Migrations []struct {
+			Version int  `json:"version"`
+			Applied bool `json:"applied"`
+		} `json:"migrations"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &status); err != nil {
+		t.Fatalf("invalid JSON status: %v\n%s", err, stdout)
+	}
+	if len(status) != 1 || status[0].Version != latest-1 || status[0].Migrations[latest-1].Applied {
+		t.Errorf("unexpected status after revert: %s", stdout)
+	}
+
+	// Opening the database applies the reverted migration again
+	cli.MustExecute("-y", "list")
+	stdout = cli.MustExecute("-y", "db", "migrate", "--status", "--schema", "tasks")
+	testutil.AssertContains(t, stdout, "version "+strconv.Itoa(latest)+" of")
+
+	_, stderr := cli.ExecuteAndFail("-y", "db", "migrate", "--to", "1")
+	testutil.AssertContains(t, stderr, "--to requires --schema")
+}
+
+// TestDBBackupAndRestoreCLI verifies 'db backup' snapshots the database and 'db restore' brings it back
+func TestDBBackupAndRestoreCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+	cli.MustExecute("-y", "list", "create", "Work")
+	cli.MustExecute("-y", "Work", "add", "Before backup")
+
+	backupPath := filepath.Join(cli.TmpDir(), "snapshot.db")
+	stdout := cli.MustExecute("-y", "db", "backup", "--output", backupPath)
+	testutil.AssertContains(t, stdout, "Backed up")
+	testutil.AssertContains(t, stdout, "snapshot.db")
+
+	_, stderr := cli.ExecuteAndFail("-y", "db", "backup", "--output", backupPath)
+	testutil.AssertContains(t, stderr, "already exists")
+
+	cli.MustExecute("-y", "Work", "add", "After backup")
+	stdout = cli.MustExecute("-y", "db", "restore", backupPath)
+	testutil.AssertContains(t, stdout, "Restored")
+	testutil.AssertContains(t, stdout, "Previous database saved to")
+
+	stdout = cli.MustExecute("-y", "Work", "get")
+	testutil.AssertContains(t, stdout, "Before backup")
+	if strings.Contains(stdout, "After backup") {
+		t.Errorf("restored database should not have the task added after the backup:\n%s", stdout)
+	}
+
+	notDB := filepath.Join(cli.TmpDir(), "notes.txt")
+	if err := os.WriteFile(notDB, []byte("not a database"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, stderr = cli.ExecuteAndFail("-y", "db", "restore", notDB)
+	testutil.AssertContains(t, stderr, "cannot restore")
+}
+
+// TestDBRestorePromptCLI verifies 'db restore' asks before replacing the
+// database unless -y is given, and fails when the answer is no
+func TestDBRestorePromptCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+	cli.MustExecute("-y", "Work", "add", "Before backup")
+	backupPath := filepath.Join(cli.TmpDir(), "snapshot.db")
+	cli.MustExecute("-y", "db", "backup", "--output", backupPath)
+	cli.MustExecute("-y", "Work", "add", "After backup")
+	cli.Config().NoPrompt = false
+
+	stdout, stderr, exitCode := cli.ExecuteWithStdin("n\n", "db", "restore", backupPath)
+	if exitCode == 0 {
+		t.Fatalf("declined restore should fail, got exit code 0:\n%s", stdout)
+	}
+	testutil.AssertContains(t, stdout, "Continue? [y/N]")
+	testutil.AssertContains(t, stderr, "restore cancelled")
+	cli.Config().NoPrompt = true
+	testutil.AssertContains(t, cli.MustExecute("-y", "Work"), "After backup")
+
+	cli.Config().NoPrompt = false
+	stdout = cli.MustExecute("-y", "db", "restore", backupPath)
+	testutil.AssertNotContains(t, stdout, "Continue?")
+	testutil.AssertContains(t, stdout, "Restored")
+	testutil.AssertNotContains(t, cli.MustExecute("-y", "Work"), "After backup")
+}
+
+// TestAutomaticBackupRotationCLI verifies destructive operations keep backup.keep automatic backups
+func TestAutomaticBackupRotationCLI(t *testing.T) {
+	cli := testutil.NewCLITestWithConfig(t)
+	cli.SetFullConfig("backup:\n  keep: 2\n")
+	cli.MustExecute("-y", "list", "create", "Work")
+
+	for i := 0; i < 3; i++ {
+		importPath := filepath.Join(cli.TmpDir(), "Import"+strconv.Itoa(i)+".csv")
+		if err := os.WriteFile(importPath, []byte("summary\nTask "+strconv.Itoa(i)+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		cli.MustExecute("-y", "list", "import", importPath)
+	}
+
+	backups, err := filepath.Glob(filepath.Join(filepath.Dir(cli.Config().DBPath), "backups", "*-before-import.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 2 {
+		t.Fatalf("expected 2 automatic backups, got %v", backups)
+	}
+
+	// The newest backup was taken before the last import
+	stdout := cli.MustExecute("-y", "db", "restore", backups[1])
+	testutil.AssertContains(t, stdout, "Restored")
+	stdout = cli.MustExecute("-y", "list")
+	testutil.AssertContains(t, stdout, "Import1")
+	if strings.Contains(stdout, "Import2") {
+		t.Errorf("backup before the last import should not have its list:\n%s", stdout)
+	}
+}
+
+// TestStatuslineSQLiteCLI verifies that `todoat statusline` summarizes due,
+// overdue and queued tasks of the local database in one line
+func TestStatuslineSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITestWithConfig(t)
+
+	cli.MustExecute("-y", "Work", "add", "Due today", "--due-date", "today")
+	cli.MustExecute("-y", "Work", "add", "Late", "--due-date", "yesterday")
+	cli.MustExecute("-y", "Work", "add", "Someday")
+	cli.MustExecute("-y", "Work", "add", "Done", "--due-date", "yesterday")
+	cli.MustExecute("-y", "Work", "complete", "Done")
+
+	stdout := cli.MustExecute("statusline")
+	if stdout != "1 due today · 1 overdue · sync off\n" {
+		t.Errorf("unexpected status line: %q", stdout)
+	}
+
+	stdout = cli.MustExecute("--json", "statusline", "--format", "{overdue}/{open}")
+	testutil.AssertContains(t, stdout, `"line":"1/3"`)
+	testutil.AssertContains(t, stdout, `"today":1`)
+
+	cli.ExecuteAndFail("statusline", "--format", "{unknown}")
+
+	// Changes made with sync enabled wait in the queue until the next sync
+	cli.SetFullConfig("sync:\n  enabled: true\n  local_backend: sqlite\nstatusline:\n  format: \"{today} today, sync {sync}\"\n")
+	cli.MustExecute("-y", "Work", "add", "Queued")
+	stdout = cli.MustExecute("statusline")
+	testutil.AssertContains(t, stdout, "1 today, sync ↑ 1")
+}
+
+// TestTaskCommandByUIDSQLiteCLI verifies that `todoat task <uid>` shows and
+// changes a task without naming its list
+func TestTaskCommandByUIDSQLiteCLI(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+
+	cli.MustExecute("-y", "Work", "add", "Write report", "--tag", "docs")
+	cli.MustExecute("-y", "Home", "add", "Water plants")
+	uid := extractUID(t, cli.MustExecute("-y", "--json", "Work"))
+
+	stdout := cli.MustExecute("-y", "task", uid)
+	testutil.AssertContains(t, stdout, "Write report")
+	testutil.AssertContains(t, stdout, "List:        Work")
+	testutil.AssertResultCode(t, stdout, testutil.ResultInfoOnly)
+
+	stdout = cli.MustExecute("-y", "task", uid, "update", "--priority", "2", "--add-tag", "q4")
+	testutil.AssertResultCode(t, stdout, testutil.ResultActionCompleted)
+	stdout = cli.MustExecute("-y", "--json", "task", "todoat://Work/"+uid, "show")
+	testutil.AssertContains(t, stdout, `"list":"Work"`)
+	testutil.AssertContains(t, stdout, `"priority":2`)
+	testutil.AssertContains(t, stdout, `"tags":["docs","q4"]`)
+
+	cli.MustExecute("-y", "task", uid, "complete")
+	testutil.AssertContains(t, cli.MustExecute("-y", "Work", "-v", "all"), "DONE")
+
+	cli.ExecuteAndFail("-y", "task", uid, "add")
+	cli.ExecuteAndFail("-y", "task", "no-such-uid")
+}
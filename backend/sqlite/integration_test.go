@@ -214,13 +214,13 @@ func TestPathBasedHierarchyIntegration(t *testing.T) {
 	cli.MustExecute("-y", "list", "create", "PathProject")
 
 	// Step 1: Create multi-level hierarchy with path notation
-	stdout := cli.MustExecute("-y", "PathProject", "add", "Release/Backend/API")
+	stdout := cli.MustExecute("-y", "PathProject", "add", "Release/Backend/API", "--path")
 	testutil.AssertResultCode(t, stdout, testutil.ResultActionCompleted)
 
 	// Step 2: Add more tasks at various levels
-	cli.MustExecute("-y", "PathProject", "add", "Release/Backend/Database")
-	cli.MustExecute("-y", "PathProject", "add", "Release/Frontend/UI")
-	cli.MustExecute("-y", "PathProject", "add", "Release/Frontend/Styling")
+	cli.MustExecute("-y", "PathProject", "add", "Release/Backend/Database", "--path")
+	cli.MustExecute("-y", "PathProject", "add", "Release/Frontend/UI", "--path")
+	cli.MustExecute("-y", "PathProject", "add", "Release/Frontend/Styling", "--path")
 
 	// Step 3: Verify hierarchy structure
 	stdout = cli.MustExecute("-y", "PathProject")
@@ -238,7 +238,7 @@ func TestPathBasedHierarchyIntegration(t *testing.T) {
 	}
 
 	// Step 5: Add another leaf to existing path (should not create duplicates)
-	cli.MustExecute("-y", "PathProject", "add", "Release/Backend/Cache")
+	cli.MustExecute("-y", "PathProject", "add", "Release/Backend/Cache", "--path")
 	stdout = cli.MustExecute("-y", "--json", "PathProject")
 
 	// Should only have one Release and one Backend
@@ -4,6 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"testing"
+	"time"
+
+	"todoat/internal/tz"
 
 	_ "modernc.org/sqlite"
 )
@@ -230,3 +233,105 @@ func TestGetSchemaVersion(t *testing.T) {
 		t.Errorf("schema version = %d, want >= 1", version)
 	}
 }
+
+// TestMigrationNormalizesTaskDatesToUTC verifies dates stored with a local offset are rewritten in UTC
+func TestMigrationNormalizesTaskDatesToUTC(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("LoadLocation error: %v", err)
+	}
+	tz.Set(newYork)
+	t.Cleanup(func() { tz.Set(nil) })
+
+	b, err := New(":memory:")
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	defer func() { _ = b.Close() }()
+
+	_, err = b.db.Exec(`INSERT INTO task_lists (id, name, modified) VALUES ('l1', 'Work', '2026-01-01T00:00:00Z')`)
+	if err != nil {
+		t.Fatalf("insert list error: %v", err)
+	}
+	_, err = b.db.Exec(`INSERT INTO tasks (id, list_id, summary, status, due_date, created, modified)
+		VALUES ('t1', 'l1', 'Report', 'NEEDS-ACTION', '2026-06-01T00:00:00-04:00', '2026-05-01T10:00:00Z', '2026-05-01T10:00:00+02:00')`)
+	if err != nil {
+		t.Fatalf("insert task error: %v", err)
+	}
+
+	for _, m := range migrations {
+		if m.Name == "normalize_task_dates_to_utc" {
+			if err := m.Up(b.db); err != nil {
+				t.Fatalf("migration error: %v", err)
+			}
+		}
+	}
+
+	var due, created, modified string
+	if err := b.db.QueryRow("SELECT due_date, created, modified FROM tasks WHERE id = 't1'").Scan(&due, &created, &modified); err != nil {
+		t.Fatalf("query error: %v", err)
+	}
+	// The due date has no time of day in New York, so it becomes a calendar date
+	if due != "2026-06-01" || created != "2026-05-01T10:00:00Z" || modified != "2026-05-01T08:00:00Z" {
+		t.Errorf("dates not normalized to UTC: due=%s created=%s modified=%s", due, created, modified)
+	}
+}
+
+// TestMigrationStoresDateOnlyDueDates verifies that due dates stored as UTC
+// midnight of the configured timezone become calendar dates, and back on Down
+func TestMigrationStoresDateOnlyDueDates(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("LoadLocation error: %v", err)
+	}
+	tz.Set(berlin)
+	t.Cleanup(func() { tz.Set(nil) })
+
+	b, err := New(":memory:")
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	defer func() { _ = b.Close() }()
+
+	_, err = b.db.Exec(`INSERT INTO task_lists (id, name, modified) VALUES ('l1', 'Work', '2026-01-01T00:00:00Z')`)
+	if err != nil {
+		t.Fatalf("insert list error: %v", err)
+	}
+	_, err = b.db.Exec(`INSERT INTO tasks (id, list_id, summary, status, due_date, start_date, created, modified) VALUES
+		('t1', 'l1', 'Renew passport', 'NEEDS-ACTION', '2030-05-31T22:00:00Z', '2030-05-19T22:00:00Z', '2026-05-01T10:00:00Z', '2026-05-01T10:00:00Z'),
+		('t2', 'l1', 'Call bank', 'NEEDS-ACTION', '2030-06-01T07:00:00Z', NULL, '2026-05-01T10:00:00Z', '2026-05-01T10:00:00Z')`)
+	if err != nil {
+		t.Fatalf("insert task error: %v", err)
+	}
+
+	var migration Migration
+	for _, m := range migrations {
+		if m.Name == "store_date_only_due_dates" {
+			migration = m
+		}
+	}
+	if err := migration.Up(b.db); err != nil {
+		t.Fatalf("migration error: %v", err)
+	}
+
+	dates := func(id string) (string, string) {
+		var due, start sql.NullString
+		if err := b.db.QueryRow("SELECT due_date, start_date FROM tasks WHERE id = ?", id).Scan(&due, &start); err != nil {
+			t.Fatalf("query error: %v", err)
+		}
+		return due.String, start.String
+	}
+	if due, start := dates("t1"); due != "2030-06-01" || start != "2030-05-20" {
+		t.Errorf("date-only values not stored as dates: due=%s start=%s", due, start)
+	}
+	if due, _ := dates("t2"); due != "2030-06-01T07:00:00Z" {
+		t.Errorf("due time changed: %s", due)
+	}
+
+	if err := migration.Down(b.db); err != nil {
+		t.Fatalf("down migration error: %v", err)
+	}
+	if due, start := dates("t1"); due != "2030-05-31T22:00:00Z" || start != "2030-05-19T22:00:00Z" {
+		t.Errorf("dates not restored: due=%s start=%s", due, start)
+	}
+}
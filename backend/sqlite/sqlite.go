@@ -6,11 +6,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	_ "modernc.org/sqlite"
 	"todoat/backend"
+	"todoat/internal/schema"
+	"todoat/internal/utils"
 )
 
 // Backend implements backend.TaskManager using SQLite
@@ -26,11 +29,7 @@ func (b *Backend) BackendID() string {
 }
 
 // Migration represents a database schema migration
-type Migration struct {
-	Version int
-	Name    string
-	Up      func(db *sql.DB) error
-}
+type Migration = schema.Migration
 
 // validTables is an allowlist of table names that can be used with columnExists.
 // This prevents SQL injection since PRAGMA statements cannot use parameterized queries.
@@ -46,25 +45,20 @@ func columnExists(db *sql.DB, table, column string) (bool, error) {
 	if !validTables[table] {
 		return false, fmt.Errorf("invalid table name: %s", table)
 	}
-	rows, err := db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
-	if err != nil {
-		return false, err
-	}
-	defer func() { _ = rows.Close() }()
+	return schema.ColumnExists(db, table, column)
+}
 
-	for rows.Next() {
-		var cid int
-		var name, colType string
-		var notNull, pk int
-		var dfltValue interface{}
-		if err := rows.Scan(&cid, &name, &colType, &notNull, &dfltValue, &pk); err != nil {
-			return false, err
-		}
-		if name == column {
-			return true, nil
+// dropColumns removes columns added by a migration, for its Down function
+func dropColumns(db *sql.DB, table string, columns ...string) error {
+	if !validTables[table] {
+		return fmt.Errorf("invalid table name: %s", table)
+	}
+	for _, column := range columns {
+		if err := schema.DropColumn(db, table, column); err != nil {
+			return err
 		}
 	}
-	return false, rows.Err()
+	return nil
 }
 
 // migrations is the ordered list of all schema migrations
@@ -105,6 +99,10 @@ var migrations = []Migration{
 			_, err := db.Exec(schema)
 			return err
 		},
+		Down: func(db *sql.DB) error {
+			_, err := db.Exec("DROP TABLE IF EXISTS tasks; DROP TABLE IF EXISTS task_lists")
+			return err
+		},
 	},
 	{
 		Version: 2,
@@ -122,6 +120,9 @@ var migrations = []Migration{
 			_, err = db.Exec("ALTER TABLE task_lists ADD COLUMN description TEXT DEFAULT ''")
 			return err
 		},
+		Down: func(db *sql.DB) error {
+			return dropColumns(db, "task_lists", "description")
+		},
 	},
 	{
 		Version: 3,
@@ -149,6 +150,9 @@ var migrations = []Migration{
 			}
 			return nil
 		},
+		Down: func(db *sql.DB) error {
+			return dropColumns(db, "tasks", "recurrence", "recur_from_due")
+		},
 	},
 	{
 		Version: 4,
@@ -186,9 +190,267 @@ var migrations = []Migration{
 			}
 			return nil
 		},
+		Down: func(db *sql.DB) error {
+			if _, err := db.Exec("DROP INDEX IF EXISTS idx_tasks_backend_id; DROP INDEX IF EXISTS idx_task_lists_backend_id"); err != nil {
+				return err
+			}
+			if err := dropColumns(db, "tasks", "backend_id"); err != nil {
+				return err
+			}
+			return dropColumns(db, "task_lists", "backend_id")
+		},
+	},
+	{
+		Version: 5,
+		Name:    "add_list_archived_at",
+		Up: func(db *sql.DB) error {
+			exists, err := columnExists(db, "task_lists", "archived_at")
+			if err != nil {
+				return err
+			}
+			if exists {
+				return nil
+			}
+			_, err = db.Exec("ALTER TABLE task_lists ADD COLUMN archived_at TEXT")
+			return err
+		},
+		Down: func(db *sql.DB) error {
+			return dropColumns(db, "task_lists", "archived_at")
+		},
+	},
+	{
+		Version: 6,
+		Name:    "add_list_defaults",
+		Up: func(db *sql.DB) error {
+			columns := []string{
+				"default_view TEXT NOT NULL DEFAULT ''",
+				"default_sort TEXT NOT NULL DEFAULT ''",
+				"default_tags TEXT NOT NULL DEFAULT ''",
+				"default_priority INTEGER NOT NULL DEFAULT 0",
+			}
+			for _, column := range columns {
+				name := strings.Fields(column)[0]
+				exists, err := columnExists(db, "task_lists", name)
+				if err != nil {
+					return err
+				}
+				if exists {
+					continue
+				}
+				if _, err := db.Exec("ALTER TABLE task_lists ADD COLUMN " + column); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(db *sql.DB) error {
+			return dropColumns(db, "task_lists", "default_view", "default_sort", "default_tags", "default_priority")
+		},
+	},
+	{
+		Version: 7,
+		Name:    "add_task_last_synced_at",
+		Up: func(db *sql.DB) error {
+			exists, err := columnExists(db, "tasks", "last_synced_at")
+			if err != nil {
+				return err
+			}
+			if exists {
+				return nil
+			}
+			if _, err := db.Exec("ALTER TABLE tasks ADD COLUMN last_synced_at TEXT"); err != nil {
+				return err
+			}
+			// Existing remote caches are assumed in sync; pending pushes are still in the sync queue
+			_, err = db.Exec("UPDATE tasks SET last_synced_at = modified WHERE backend_id != 'sqlite'")
+			return err
+		},
+		Down: func(db *sql.DB) error {
+			return dropColumns(db, "tasks", "last_synced_at")
+		},
+	},
+	{
+		Version: 8,
+		Name:    "add_task_metadata",
+		Up: func(db *sql.DB) error {
+			_, err := db.Exec(`
+				CREATE TABLE IF NOT EXISTS task_metadata (
+					task_id TEXT NOT NULL,
+					key TEXT NOT NULL,
+					value TEXT NOT NULL,
+					PRIMARY KEY (task_id, key),
+					FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE
+				)
+			`)
+			return err
+		},
+		Down: func(db *sql.DB) error {
+			_, err := db.Exec("DROP TABLE IF EXISTS task_metadata")
+			return err
+		},
+	},
+	{
+		Version: 9,
+		Name:    "normalize_task_dates_to_utc",
+		Up: func(db *sql.DB) error {
+			// Dates used to be stored with the local offset; rewrite them in UTC so
+			// they compare and sort correctly whatever zone they were written in.
+			// Due and start dates without a time of day become calendar dates.
+			for _, column := range []string{"due_date", "start_date", "completed", "created", "modified"} {
+				dateColumn := column == "due_date" || column == "start_date"
+				err := rewriteTaskDates(db, column, "NOT LIKE '%Z'", func(value string) (string, bool) {
+					parsed, err := time.Parse(time.RFC3339Nano, value)
+					if err != nil {
+						return "", false
+					}
+					if dateColumn {
+						return backend.FormatStoredDate(parsed), true
+					}
+					return parsed.UTC().Format(time.RFC3339Nano), true
+				})
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(db *sql.DB) error {
+			// UTC dates are valid for the older schema too; nothing to undo
+			return nil
+		},
+	},
+	{
+		Version: 10,
+		Name:    "add_list_shared_by",
+		Up: func(db *sql.DB) error {
+			exists, err := columnExists(db, "task_lists", "shared_by")
+			if err != nil {
+				return err
+			}
+			if exists {
+				return nil
+			}
+			_, err = db.Exec("ALTER TABLE task_lists ADD COLUMN shared_by TEXT NOT NULL DEFAULT ''")
+			return err
+		},
+		Down: func(db *sql.DB) error {
+			return dropColumns(db, "task_lists", "shared_by")
+		},
+	},
+	{
+		Version: 11,
+		Name:    "add_task_order",
+		Up: func(db *sql.DB) error {
+			for _, column := range []string{"position INTEGER NOT NULL DEFAULT 0", "pinned INTEGER NOT NULL DEFAULT 0"} {
+				if err := schema.AddColumn(db, "tasks", column); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(db *sql.DB) error {
+			return dropColumns(db, "tasks", "position", "pinned")
+		},
+	},
+	{
+		Version: 12,
+		Name:    "add_task_history",
+		Up: func(db *sql.DB) error {
+			// No foreign key on the task: its history outlives it
+			_, err := db.Exec(`
+				CREATE TABLE IF NOT EXISTS task_history (
+					id INTEGER PRIMARY KEY AUTOINCREMENT,
+					task_id TEXT NOT NULL,
+					list_id TEXT NOT NULL,
+					backend_id TEXT NOT NULL DEFAULT 'sqlite',
+					changed_at TEXT NOT NULL,
+					actor TEXT NOT NULL DEFAULT '',
+					action TEXT NOT NULL,
+					field TEXT NOT NULL DEFAULT '',
+					old_value TEXT NOT NULL DEFAULT '',
+					new_value TEXT NOT NULL DEFAULT ''
+				);
+				CREATE INDEX IF NOT EXISTS idx_task_history_task ON task_history(task_id, backend_id)
+			`)
+			return err
+		},
+		Down: func(db *sql.DB) error {
+			_, err := db.Exec("DROP TABLE IF EXISTS task_history")
+			return err
+		},
+	},
+	{
+		Version: 13,
+		Name:    "store_date_only_due_dates",
+		Up: func(db *sql.DB) error {
+			// Due and start dates without a time of day were stored as UTC midnight
+			// of the timezone they were entered in, and moved to another day when
+			// the timezone changed; store them as calendar dates instead
+			for _, column := range []string{"due_date", "start_date"} {
+				err := rewriteTaskDates(db, column, "LIKE '%Z'", func(value string) (string, bool) {
+					parsed, err := time.Parse(time.RFC3339Nano, value)
+					if err != nil || !backend.IsDateOnly(parsed) {
+						return "", false
+					}
+					return backend.FormatStoredDate(parsed), true
+				})
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(db *sql.DB) error {
+			for _, column := range []string{"due_date", "start_date"} {
+				err := rewriteTaskDates(db, column, "NOT LIKE '%T%'", func(value string) (string, bool) {
+					parsed, err := backend.ParseStoredDate(value)
+					if err != nil {
+						return "", false
+					}
+					return parsed.UTC().Format(time.RFC3339Nano), true
+				})
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
 	},
 }
 
+// rewriteTaskDates replaces the values of a task date column matching the LIKE
+// condition with what rewrite returns for them; values it rejects are kept.
+func rewriteTaskDates(db *sql.DB, column, condition string, rewrite func(string) (string, bool)) error {
+	rows, err := db.Query("SELECT id, " + column + " FROM tasks WHERE " + column + " IS NOT NULL AND " + column + " " + condition)
+	if err != nil {
+		return err
+	}
+	updates := make(map[string]string)
+	for rows.Next() {
+		var id, value string
+		if err := rows.Scan(&id, &value); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		if rewritten, ok := rewrite(value); ok {
+			updates[id] = rewritten
+		}
+	}
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		return err
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	for id, value := range updates {
+		if _, err := db.Exec("UPDATE tasks SET "+column+" = ? WHERE id = ?", value, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // New creates a new SQLite backend and initializes the database schema.
 // Uses "sqlite" as the default backend ID for backward compatibility.
 func New(path string) (*Backend, error) {
@@ -208,6 +470,10 @@ func NewWithBackendID(path string, backendID string) (*Backend, error) {
 		return nil, err
 	}
 
+	// A single connection serializes this process's writes and makes the busy timeout
+	// and other connection pragmas below apply to every statement
+	db.SetMaxOpenConns(1)
+
 	b := &Backend{db: db, backendID: backendID}
 	if err := b.initSchema(); err != nil {
 		_ = db.Close()
@@ -217,6 +483,67 @@ func NewWithBackendID(path string, backendID string) (*Backend, error) {
 	return b, nil
 }
 
+// busyRetries bounds how often a write is retried after SQLITE_BUSY
+const busyRetries = 5
+
+// busyRetryDelay is the delay before the first retry; later retries wait longer
+const busyRetryDelay = 100 * time.Millisecond
+
+// isBusy reports whether err means another connection holds the database
+func isBusy(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "SQLITE_BUSY") || strings.Contains(msg, "database is locked")
+}
+
+// retryBusy runs fn, running it again when it fails with SQLITE_BUSY. The busy
+// timeout does not cover every case: a transaction that read before writing
+// fails at once when another process wrote in between. Retries are logged at
+// debug level; the error is returned once they are used up.
+func retryBusy(ctx context.Context, operation string, fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if !isBusy(err) || attempt > busyRetries {
+			return err
+		}
+		utils.Debugf("SQLite database busy during %s, retrying (attempt %d): %v", operation, attempt, err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(busyRetryDelay * time.Duration(attempt)):
+		}
+	}
+}
+
+// exec runs a write statement, retrying it while the database is busy
+func (b *Backend) exec(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	var res sql.Result
+	err := retryBusy(ctx, "write", func() error {
+		var err error
+		res, err = b.db.ExecContext(ctx, query, args...)
+		return err
+	})
+	return res, err
+}
+
+// inTx runs fn in a transaction and commits it. The whole transaction is run
+// again while the database is busy, so fn must not keep state across calls.
+func (b *Backend) inTx(ctx context.Context, operation string, fn func(tx *sql.Tx) error) error {
+	return retryBusy(ctx, operation, func() error {
+		tx, err := b.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = tx.Rollback() }()
+		if err := fn(tx); err != nil {
+			return err
+		}
+		return tx.Commit()
+	})
+}
+
 // initSchema runs database migrations to ensure the schema is up to date
 func (b *Backend) initSchema() error {
 	// Configure SQLite for concurrent access
@@ -235,52 +562,24 @@ func (b *Backend) initSchema() error {
 		return err
 	}
 
-	// Create schema_version table if it doesn't exist
-	_, err := b.db.Exec(`
-		CREATE TABLE IF NOT EXISTS schema_version (
-			version INTEGER PRIMARY KEY,
-			applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-		)
-	`)
-	if err != nil {
-		return fmt.Errorf("failed to create schema_version table: %w", err)
-	}
-
-	// Get current schema version
-	currentVersion, err := b.getSchemaVersionInternal()
-	if err != nil {
-		return fmt.Errorf("failed to get schema version: %w", err)
+	if _, err := schemaMigrator.Up(b.db); err != nil {
+		return err
 	}
+	return nil
+}
 
-	// Apply pending migrations in order
-	for _, m := range migrations {
-		if m.Version <= currentVersion {
-			continue
-		}
-
-		// Run the migration
-		if err := m.Up(b.db); err != nil {
-			return fmt.Errorf("migration %d (%s) failed: %w", m.Version, m.Name, err)
-		}
-
-		// Record the migration
-		_, err = b.db.Exec("INSERT OR REPLACE INTO schema_version (version, applied_at) VALUES (?, CURRENT_TIMESTAMP)", m.Version)
-		if err != nil {
-			return fmt.Errorf("failed to record migration %d: %w", m.Version, err)
-		}
-	}
+// schemaMigrator records the migrations applied to a database in schema_version
+var schemaMigrator = schema.Migrator{Table: "schema_version", Migrations: migrations}
 
-	return nil
+// SchemaMigrator returns the migrator of the task database schema, to report
+// or change its version without opening a backend
+func SchemaMigrator() *schema.Migrator {
+	return &schemaMigrator
 }
 
 // getSchemaVersionInternal returns the current schema version (0 if no migrations applied)
 func (b *Backend) getSchemaVersionInternal() (int, error) {
-	var version int
-	err := b.db.QueryRow("SELECT COALESCE(MAX(version), 0) FROM schema_version").Scan(&version)
-	if err != nil {
-		return 0, err
-	}
-	return version, nil
+	return schemaMigrator.Version(b.db)
 }
 
 // GetSchemaVersion returns the current schema version
@@ -288,10 +587,10 @@ func (b *Backend) GetSchemaVersion() (int, error) {
 	return b.getSchemaVersionInternal()
 }
 
-// GetLists returns all active (non-deleted) task lists for this backend
+// GetLists returns all active (non-deleted, non-archived) task lists for this backend
 func (b *Backend) GetLists(ctx context.Context) ([]backend.List, error) {
 	rows, err := b.db.QueryContext(ctx,
-		"SELECT id, name, color, description, modified FROM task_lists WHERE deleted_at IS NULL AND backend_id = ?",
+		"SELECT id, name, color, description, modified, shared_by FROM task_lists WHERE deleted_at IS NULL AND archived_at IS NULL AND backend_id = ?",
 		b.backendID)
 	if err != nil {
 		return nil, err
@@ -302,7 +601,7 @@ func (b *Backend) GetLists(ctx context.Context) ([]backend.List, error) {
 	for rows.Next() {
 		var l backend.List
 		var modifiedStr string
-		if err := rows.Scan(&l.ID, &l.Name, &l.Color, &l.Description, &modifiedStr); err != nil {
+		if err := rows.Scan(&l.ID, &l.Name, &l.Color, &l.Description, &modifiedStr, &l.SharedBy); err != nil {
 			return nil, err
 		}
 		l.Modified, _ = time.Parse(time.RFC3339Nano, modifiedStr)
@@ -315,14 +614,16 @@ func (b *Backend) GetLists(ctx context.Context) ([]backend.List, error) {
 	return lists, rows.Err()
 }
 
-// GetList returns a specific active list by ID for this backend
+// GetList returns a specific non-deleted list by ID for this backend.
+// Archived lists are included with ArchivedAt set.
 func (b *Backend) GetList(ctx context.Context, listID string) (*backend.List, error) {
 	var l backend.List
 	var modifiedStr string
+	var archivedAtStr sql.NullString
 	err := b.db.QueryRowContext(ctx,
-		"SELECT id, name, color, description, modified FROM task_lists WHERE id = ? AND deleted_at IS NULL AND backend_id = ?",
+		"SELECT id, name, color, description, modified, archived_at, shared_by FROM task_lists WHERE id = ? AND deleted_at IS NULL AND backend_id = ?",
 		listID, b.backendID,
-	).Scan(&l.ID, &l.Name, &l.Color, &l.Description, &modifiedStr)
+	).Scan(&l.ID, &l.Name, &l.Color, &l.Description, &modifiedStr, &archivedAtStr, &l.SharedBy)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -332,17 +633,23 @@ func (b *Backend) GetList(ctx context.Context, listID string) (*backend.List, er
 	}
 
 	l.Modified, _ = time.Parse(time.RFC3339Nano, modifiedStr)
+	if archivedAtStr.Valid {
+		t, _ := time.Parse(time.RFC3339Nano, archivedAtStr.String)
+		l.ArchivedAt = &t
+	}
 	return &l, nil
 }
 
-// GetListByName returns a specific active list by name (case-insensitive) for this backend
+// GetListByName returns a specific non-deleted list by name (case-insensitive) for this backend.
+// Archived lists are included with ArchivedAt set.
 func (b *Backend) GetListByName(ctx context.Context, name string) (*backend.List, error) {
 	var l backend.List
 	var modifiedStr string
+	var archivedAtStr sql.NullString
 	err := b.db.QueryRowContext(ctx,
-		"SELECT id, name, color, description, modified FROM task_lists WHERE LOWER(name) = LOWER(?) AND deleted_at IS NULL AND backend_id = ?",
+		"SELECT id, name, color, description, modified, archived_at, shared_by FROM task_lists WHERE LOWER(name) = LOWER(?) AND deleted_at IS NULL AND backend_id = ?",
 		name, b.backendID,
-	).Scan(&l.ID, &l.Name, &l.Color, &l.Description, &modifiedStr)
+	).Scan(&l.ID, &l.Name, &l.Color, &l.Description, &modifiedStr, &archivedAtStr, &l.SharedBy)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -352,6 +659,10 @@ func (b *Backend) GetListByName(ctx context.Context, name string) (*backend.List
 	}
 
 	l.Modified, _ = time.Parse(time.RFC3339Nano, modifiedStr)
+	if archivedAtStr.Valid {
+		t, _ := time.Parse(time.RFC3339Nano, archivedAtStr.String)
+		l.ArchivedAt = &t
+	}
 	return &l, nil
 }
 
@@ -361,7 +672,7 @@ func (b *Backend) CreateList(ctx context.Context, name string) (*backend.List, e
 	now := time.Now().UTC()
 	nowStr := now.Format(time.RFC3339Nano)
 
-	_, err := b.db.ExecContext(ctx,
+	_, err := b.exec(ctx,
 		"INSERT INTO task_lists (id, name, color, description, modified, backend_id) VALUES (?, ?, '', '', ?, ?)",
 		id, name, nowStr, b.backendID,
 	)
@@ -383,9 +694,9 @@ func (b *Backend) UpdateList(ctx context.Context, list *backend.List) (*backend.
 	now := time.Now().UTC()
 	nowStr := now.Format(time.RFC3339Nano)
 
-	_, err := b.db.ExecContext(ctx,
-		"UPDATE task_lists SET name = ?, color = ?, description = ?, modified = ? WHERE id = ? AND deleted_at IS NULL AND backend_id = ?",
-		list.Name, list.Color, list.Description, nowStr, list.ID, b.backendID,
+	_, err := b.exec(ctx,
+		"UPDATE task_lists SET name = ?, color = ?, description = ?, shared_by = ?, modified = ? WHERE id = ? AND deleted_at IS NULL AND backend_id = ?",
+		list.Name, list.Color, list.Description, list.SharedBy, nowStr, list.ID, b.backendID,
 	)
 	if err != nil {
 		return nil, err
@@ -398,10 +709,16 @@ func (b *Backend) UpdateList(ctx context.Context, list *backend.List) (*backend.
 // SupportsTrash returns true because SQLite supports soft-delete with restore.
 func (b *Backend) SupportsTrash() bool { return true }
 
+// StoresCustomStatuses returns true because SQLite stores statuses as text.
+func (b *Backend) StoresCustomStatuses() bool { return true }
+
+// StoresTaskOrder returns true because tasks have position and pinned columns.
+func (b *Backend) StoresTaskOrder() bool { return true }
+
 // DeleteList soft-deletes a task list (moves to trash) for this backend
 func (b *Backend) DeleteList(ctx context.Context, listID string) error {
 	now := time.Now().UTC().Format(time.RFC3339Nano)
-	_, err := b.db.ExecContext(ctx, "UPDATE task_lists SET deleted_at = ? WHERE id = ? AND backend_id = ?", now, listID, b.backendID)
+	_, err := b.exec(ctx, "UPDATE task_lists SET deleted_at = ? WHERE id = ? AND backend_id = ?", now, listID, b.backendID)
 	return err
 }
 
@@ -464,104 +781,351 @@ func (b *Backend) GetDeletedListByName(ctx context.Context, name string) (*backe
 
 // RestoreList restores a deleted list from trash for this backend
 func (b *Backend) RestoreList(ctx context.Context, listID string) error {
-	_, err := b.db.ExecContext(ctx, "UPDATE task_lists SET deleted_at = NULL WHERE id = ? AND backend_id = ?", listID, b.backendID)
+	_, err := b.exec(ctx, "UPDATE task_lists SET deleted_at = NULL WHERE id = ? AND backend_id = ?", listID, b.backendID)
 	return err
 }
 
 // PurgeList permanently deletes a list and all its tasks for this backend
 func (b *Backend) PurgeList(ctx context.Context, listID string) error {
 	// First delete all tasks in this list for this backend
-	_, err := b.db.ExecContext(ctx, "DELETE FROM tasks WHERE list_id = ? AND backend_id = ?", listID, b.backendID)
+	_, err := b.exec(ctx, "DELETE FROM tasks WHERE list_id = ? AND backend_id = ?", listID, b.backendID)
 	if err != nil {
 		return err
 	}
 
-	_, err = b.db.ExecContext(ctx, "DELETE FROM task_lists WHERE id = ? AND backend_id = ?", listID, b.backendID)
+	_, err = b.exec(ctx, "DELETE FROM task_lists WHERE id = ? AND backend_id = ?", listID, b.backendID)
 	return err
 }
 
-// GetTasks returns all tasks in a list for this backend
-func (b *Backend) GetTasks(ctx context.Context, listID string) ([]backend.Task, error) {
-	rows, err := b.db.QueryContext(ctx,
-		`SELECT id, list_id, summary, description, status, priority, due_date, start_date, completed, created, modified, parent_id, categories, recurrence, recur_from_due
-		 FROM tasks WHERE list_id = ? AND backend_id = ?`,
-		listID, b.backendID,
-	)
+// PurgeResult contains the result of purging a backend's data
+type PurgeResult struct {
+	Lists int      `json:"lists"`
+	Tasks int      `json:"tasks"`
+	IDs   []string `json:"-"` // IDs of the removed lists and tasks
+}
+
+// QueryReadOnly runs a query on a connection in query_only mode, so statements that
+// would modify the database fail. Values are returned as strings, NULL as "".
+func (b *Backend) QueryReadOnly(ctx context.Context, query string) ([]string, [][]string, error) {
+	conn, err := b.db.Conn(ctx)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	defer func() { _ = conn.Close() }()
+
+	if _, err := conn.ExecContext(ctx, "PRAGMA query_only = ON"); err != nil {
+		return nil, nil, err
+	}
+	// The connection returns to the pool, so writes must be allowed again afterwards
+	defer func() { _, _ = conn.ExecContext(context.Background(), "PRAGMA query_only = OFF") }()
+
+	rows, err := conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, nil, err
 	}
 	defer func() { _ = rows.Close() }()
 
-	var tasks []backend.Task
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, nil, err
+	}
+	var result [][]string
 	for rows.Next() {
-		t, err := scanTask(rows)
-		if err != nil {
-			return nil, err
+		values := make([]sql.NullString, len(columns))
+		ptrs := make([]any, len(columns))
+		for i := range values {
+			ptrs[i] = &values[i]
 		}
-		tasks = append(tasks, *t)
-	}
-
-	if tasks == nil {
-		tasks = []backend.Task{}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, nil, err
+		}
+		row := make([]string, len(columns))
+		for i, v := range values {
+			row[i] = v.String
+		}
+		result = append(result, row)
 	}
-	return tasks, rows.Err()
+	return columns, result, rows.Err()
 }
 
-// GetTask returns a specific task for this backend
-func (b *Backend) GetTask(ctx context.Context, listID, taskID string) (*backend.Task, error) {
-	row := b.db.QueryRowContext(ctx,
-		`SELECT id, list_id, summary, description, status, priority, due_date, start_date, completed, created, modified, parent_id, categories, recurrence, recur_from_due
-		 FROM tasks WHERE list_id = ? AND id = ? AND backend_id = ?`,
-		listID, taskID, b.backendID,
-	)
+// PurgeBackendData permanently deletes all lists and tasks (including trashed and
+// archived ones) stored for this backend. Data of other backends is not touched.
+func (b *Backend) PurgeBackendData(ctx context.Context) (*PurgeResult, error) {
+	var result *PurgeResult
+	err := b.inTx(ctx, "purge backend data", func(tx *sql.Tx) error {
+		result = &PurgeResult{}
+		for _, table := range []string{"tasks", "task_lists"} {
+			rows, err := tx.QueryContext(ctx, "SELECT id FROM "+table+" WHERE backend_id = ?", b.backendID)
+			if err != nil {
+				return err
+			}
+			for rows.Next() {
+				var id string
+				if err := rows.Scan(&id); err != nil {
+					_ = rows.Close()
+					return err
+				}
+				result.IDs = append(result.IDs, id)
+			}
+			_ = rows.Close()
+			if err := rows.Err(); err != nil {
+				return err
+			}
 
-	t, err := scanTaskRow(row)
-	if err == sql.ErrNoRows {
-		return nil, nil
+			res, err := tx.ExecContext(ctx, "DELETE FROM "+table+" WHERE backend_id = ?", b.backendID)
+			if err != nil {
+				return err
+			}
+			count, _ := res.RowsAffected()
+			if table == "tasks" {
+				result.Tasks = int(count)
+			} else {
+				result.Lists = int(count)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	return t, err
+	return result, nil
 }
 
-// GetTaskByLocalID returns a task by its SQLite rowid (local ID) for this backend
-func (b *Backend) GetTaskByLocalID(ctx context.Context, listID string, localID int64) (*backend.Task, error) {
-	row := b.db.QueryRowContext(ctx,
-		`SELECT id, list_id, summary, description, status, priority, due_date, start_date, completed, created, modified, parent_id, categories, recurrence, recur_from_due
-		 FROM tasks WHERE list_id = ? AND rowid = ? AND backend_id = ?`,
-		listID, localID, b.backendID,
-	)
+// ArchiveList marks a list as archived for this backend
+func (b *Backend) ArchiveList(ctx context.Context, listID string) error {
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	_, err := b.exec(ctx, "UPDATE task_lists SET archived_at = ? WHERE id = ? AND deleted_at IS NULL AND backend_id = ?", now, listID, b.backendID)
+	return err
+}
 
-	t, err := scanTaskRow(row)
-	if err == sql.ErrNoRows {
-		return nil, nil
-	}
-	return t, err
+// UnarchiveList returns an archived list to the active lists for this backend
+func (b *Backend) UnarchiveList(ctx context.Context, listID string) error {
+	_, err := b.exec(ctx, "UPDATE task_lists SET archived_at = NULL WHERE id = ? AND backend_id = ?", listID, b.backendID)
+	return err
 }
 
-// GetTaskLocalID returns the SQLite rowid for a task for this backend
-func (b *Backend) GetTaskLocalID(ctx context.Context, taskID string) (int64, error) {
-	var localID int64
+// GetListDefaults returns the per-list defaults stored for a list of this backend
+func (b *Backend) GetListDefaults(ctx context.Context, listID string) (backend.ListDefaults, error) {
+	var d backend.ListDefaults
+	var tags string
 	err := b.db.QueryRowContext(ctx,
-		`SELECT rowid FROM tasks WHERE id = ? AND backend_id = ?`,
-		taskID, b.backendID,
-	).Scan(&localID)
+		"SELECT default_view, default_sort, default_tags, default_priority FROM task_lists WHERE id = ? AND backend_id = ?",
+		listID, b.backendID,
+	).Scan(&d.View, &d.Sort, &tags, &d.Priority)
 	if err == sql.ErrNoRows {
-		return 0, nil
+		return backend.ListDefaults{}, nil
 	}
-	return localID, err
+	if err != nil {
+		return backend.ListDefaults{}, err
+	}
+	if tags != "" {
+		d.Tags = strings.Split(tags, ",")
+	}
+	return d, nil
 }
 
-// timeToNullString converts a *time.Time to sql.NullString for database storage.
+// SetListDefaults replaces the per-list defaults for a list of this backend
+func (b *Backend) SetListDefaults(ctx context.Context, listID string, d backend.ListDefaults) error {
+	res, err := b.exec(ctx,
+		"UPDATE task_lists SET default_view = ?, default_sort = ?, default_tags = ?, default_priority = ? WHERE id = ? AND backend_id = ?",
+		d.View, d.Sort, strings.Join(d.Tags, ","), d.Priority, listID, b.backendID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("list not found: %s", listID)
+	}
+	return nil
+}
+
+// GetArchivedLists returns all archived (non-deleted) task lists for this backend
+func (b *Backend) GetArchivedLists(ctx context.Context) ([]backend.List, error) {
+	rows, err := b.db.QueryContext(ctx,
+		"SELECT id, name, color, description, modified, archived_at FROM task_lists WHERE deleted_at IS NULL AND archived_at IS NOT NULL AND backend_id = ?",
+		b.backendID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var lists []backend.List
+	for rows.Next() {
+		var l backend.List
+		var modifiedStr string
+		var archivedAtStr sql.NullString
+		if err := rows.Scan(&l.ID, &l.Name, &l.Color, &l.Description, &modifiedStr, &archivedAtStr); err != nil {
+			return nil, err
+		}
+		l.Modified, _ = time.Parse(time.RFC3339Nano, modifiedStr)
+		if archivedAtStr.Valid {
+			t, _ := time.Parse(time.RFC3339Nano, archivedAtStr.String)
+			l.ArchivedAt = &t
+		}
+		lists = append(lists, l)
+	}
+
+	if lists == nil {
+		lists = []backend.List{}
+	}
+	return lists, rows.Err()
+}
+
+// taskOrder sorts tasks into their manual order (see backend.ComparePosition),
+// and tasks never reordered in the order they were added (rowid, i.e. local ID)
+const taskOrder = "pinned DESC, position = 0, position, rowid"
+
+// GetTasks returns all tasks in a list for this backend in manual order, the same
+// order GetTasksPage pages through
+func (b *Backend) GetTasks(ctx context.Context, listID string) ([]backend.Task, error) {
+	rows, err := b.db.QueryContext(ctx,
+		`SELECT id, list_id, summary, description, status, priority, due_date, start_date, completed, created, modified, parent_id, categories, recurrence, recur_from_due, position, pinned
+		 FROM tasks WHERE list_id = ? AND backend_id = ?
+		 ORDER BY `+taskOrder,
+		listID, b.backendID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var tasks []backend.Task
+	for rows.Next() {
+		t, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, *t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if tasks == nil {
+		tasks = []backend.Task{}
+	}
+	return tasks, b.loadListMetadata(ctx, listID, tasks)
+}
+
+// GetTasksPage returns up to limit tasks of a list in GetTasks order. The rowid is
+// used rather than the created timestamp, whose RFC 3339 text does not sort reliably.
+func (b *Backend) GetTasksPage(ctx context.Context, listID string, offset, limit int) ([]backend.Task, error) {
+	rows, err := b.db.QueryContext(ctx,
+		`SELECT id, list_id, summary, description, status, priority, due_date, start_date, completed, created, modified, parent_id, categories, recurrence, recur_from_due, position, pinned
+		 FROM tasks WHERE list_id = ? AND backend_id = ?
+		 ORDER BY `+taskOrder+` LIMIT ? OFFSET ?`,
+		listID, b.backendID, limit, offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	tasks := []backend.Task{}
+	for rows.Next() {
+		t, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, *t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return tasks, b.loadListMetadata(ctx, listID, tasks)
+}
+
+// GetTask returns a specific task for this backend
+func (b *Backend) GetTask(ctx context.Context, listID, taskID string) (*backend.Task, error) {
+	row := b.db.QueryRowContext(ctx,
+		`SELECT id, list_id, summary, description, status, priority, due_date, start_date, completed, created, modified, parent_id, categories, recurrence, recur_from_due, position, pinned
+		 FROM tasks WHERE list_id = ? AND id = ? AND backend_id = ?`,
+		listID, taskID, b.backendID,
+	)
+
+	t, err := scanTaskRow(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return t, b.loadTaskMetadata(ctx, t)
+}
+
+// FindTask returns a task by ID from any list of this backend that is not in the
+// trash. Task IDs are the primary key of the tasks table, so the lookup is an
+// index search rather than a scan of every list.
+func (b *Backend) FindTask(ctx context.Context, taskID string) (*backend.Task, error) {
+	row := b.db.QueryRowContext(ctx,
+		`SELECT t.id, t.list_id, t.summary, t.description, t.status, t.priority, t.due_date, t.start_date, t.completed, t.created, t.modified, t.parent_id, t.categories, t.recurrence, t.recur_from_due, t.position, t.pinned
+		 FROM tasks t JOIN task_lists l ON l.id = t.list_id
+		 WHERE t.id = ? AND t.backend_id = ? AND l.deleted_at IS NULL`,
+		taskID, b.backendID,
+	)
+
+	t, err := scanTaskRow(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return t, b.loadTaskMetadata(ctx, t)
+}
+
+// GetTaskByLocalID returns a task by its SQLite rowid (local ID) for this backend
+func (b *Backend) GetTaskByLocalID(ctx context.Context, listID string, localID int64) (*backend.Task, error) {
+	row := b.db.QueryRowContext(ctx,
+		`SELECT id, list_id, summary, description, status, priority, due_date, start_date, completed, created, modified, parent_id, categories, recurrence, recur_from_due, position, pinned
+		 FROM tasks WHERE list_id = ? AND rowid = ? AND backend_id = ?`,
+		listID, localID, b.backendID,
+	)
+
+	t, err := scanTaskRow(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return t, b.loadTaskMetadata(ctx, t)
+}
+
+// GetTaskLocalID returns the SQLite rowid for a task for this backend
+func (b *Backend) GetTaskLocalID(ctx context.Context, taskID string) (int64, error) {
+	var localID int64
+	err := b.db.QueryRowContext(ctx,
+		`SELECT rowid FROM tasks WHERE id = ? AND backend_id = ?`,
+		taskID, b.backendID,
+	).Scan(&localID)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return localID, err
+}
+
+// timeToNullString converts a *time.Time to sql.NullString for database storage.
 func timeToNullString(t *time.Time) sql.NullString {
 	if t == nil {
 		return sql.NullString{}
 	}
-	return sql.NullString{String: t.Format(time.RFC3339Nano), Valid: true}
+	return sql.NullString{String: t.UTC().Format(time.RFC3339Nano), Valid: true}
 }
 
-// parseOptionalDate parses a nullable date string and returns a pointer to time.Time.
+// dateToNullString converts a due or start date for storage; a date without a
+// time of day is stored as a calendar date (see backend.FormatStoredDate).
+func dateToNullString(t *time.Time) sql.NullString {
+	if t == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: backend.FormatStoredDate(*t), Valid: true}
+}
+
+// parseOptionalDate parses a nullable date string and returns a pointer to time.Time
+// in the display timezone. Calendar dates are midnight in that timezone.
 func parseOptionalDate(str sql.NullString) *time.Time {
 	if str.Valid && str.String != "" {
-		if parsed, err := time.Parse(time.RFC3339Nano, str.String); err == nil {
+		if parsed, err := backend.ParseStoredDate(str.String); err == nil {
 			return &parsed
 		}
 	}
@@ -570,11 +1134,11 @@ func parseOptionalDate(str sql.NullString) *time.Time {
 
 // parseDateStrings parses the nullable date strings and populates the task's date fields.
 func parseDateStrings(t *backend.Task, dueDateStr, startDateStr, completedStr, createdStr, modifiedStr sql.NullString) {
-	if createdStr.Valid {
-		t.Created, _ = time.Parse(time.RFC3339Nano, createdStr.String)
+	if created := parseOptionalDate(createdStr); created != nil {
+		t.Created = *created
 	}
-	if modifiedStr.Valid {
-		t.Modified, _ = time.Parse(time.RFC3339Nano, modifiedStr.String)
+	if modified := parseOptionalDate(modifiedStr); modified != nil {
+		t.Modified = *modified
 	}
 	t.DueDate = parseOptionalDate(dueDateStr)
 	t.StartDate = parseOptionalDate(startDateStr)
@@ -596,7 +1160,7 @@ func scanTaskFrom(s scanner) (*backend.Task, error) {
 	err := s.Scan(
 		&t.ID, &t.ListID, &t.Summary, &t.Description, &t.Status,
 		&t.Priority, &dueDateStr, &startDateStr, &completedStr, &createdStr, &modifiedStr, &t.ParentID, &categoriesStr,
-		&recurrenceStr, &recurFromDue,
+		&recurrenceStr, &recurFromDue, &t.Position, &t.Pinned,
 	)
 	if err != nil {
 		return nil, err
@@ -637,8 +1201,8 @@ func (b *Backend) CreateTask(ctx context.Context, listID string, task *backend.T
 	now := time.Now().UTC()
 	nowStr := now.Format(time.RFC3339Nano)
 
-	dueDateStr := timeToNullString(task.DueDate)
-	startDateStr := timeToNullString(task.StartDate)
+	dueDateStr := dateToNullString(task.DueDate)
+	startDateStr := dateToNullString(task.StartDate)
 	completedStr := timeToNullString(task.Completed)
 
 	status := task.Status
@@ -652,15 +1216,21 @@ func (b *Backend) CreateTask(ctx context.Context, listID string, task *backend.T
 		recurFromDueInt = 0
 	}
 
-	_, err := b.db.ExecContext(ctx,
-		`INSERT INTO tasks (id, list_id, summary, description, status, priority, due_date, start_date, completed, created, modified, parent_id, categories, recurrence, recur_from_due, backend_id)
-		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+	_, err := b.exec(ctx,
+		`INSERT INTO tasks (id, list_id, summary, description, status, priority, due_date, start_date, completed, created, modified, parent_id, categories, recurrence, recur_from_due, position, pinned, backend_id)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		id, listID, task.Summary, task.Description, status, task.Priority,
-		dueDateStr, startDateStr, completedStr, nowStr, nowStr, task.ParentID, task.Categories, task.Recurrence, recurFromDueInt, b.backendID,
+		dueDateStr, startDateStr, completedStr, nowStr, nowStr, task.ParentID, task.Categories, task.Recurrence, recurFromDueInt, task.Position, task.Pinned, b.backendID,
 	)
 	if err != nil {
 		return nil, err
 	}
+	if err := saveTaskMetadata(ctx, b.db, id, task.Metadata); err != nil {
+		return nil, err
+	}
+	if err := b.recordHistory(ctx, b.db, listID, id, now, backend.HistoryCreated, "", "", task.Summary); err != nil {
+		return nil, err
+	}
 
 	return &backend.Task{
 		ID:           id,
@@ -678,16 +1248,25 @@ func (b *Backend) CreateTask(ctx context.Context, listID string, task *backend.T
 		Categories:   task.Categories,
 		Recurrence:   task.Recurrence,
 		RecurFromDue: task.RecurFromDue,
+		Position:     task.Position,
+		Pinned:       task.Pinned,
+		Metadata:     task.Metadata,
 	}, nil
 }
 
 // UpdateTask modifies an existing task for this backend
 func (b *Backend) UpdateTask(ctx context.Context, listID string, task *backend.Task) (*backend.Task, error) {
+	// The stored task is compared with the result to record the changed fields
+	old, err := b.GetTask(ctx, listID, task.ID)
+	if err != nil {
+		return nil, err
+	}
+
 	now := time.Now().UTC()
 	nowStr := now.Format(time.RFC3339Nano)
 
-	dueDateStr := timeToNullString(task.DueDate)
-	startDateStr := timeToNullString(task.StartDate)
+	dueDateStr := dateToNullString(task.DueDate)
+	startDateStr := dateToNullString(task.StartDate)
 	completedStr := timeToNullString(task.Completed)
 
 	// Convert bool to int for SQLite storage
@@ -696,26 +1275,322 @@ func (b *Backend) UpdateTask(ctx context.Context, listID string, task *backend.T
 		recurFromDueInt = 0
 	}
 
-	_, err := b.db.ExecContext(ctx,
-		`UPDATE tasks SET summary = ?, description = ?, status = ?, priority = ?, due_date = ?, start_date = ?, completed = ?, modified = ?, parent_id = ?, categories = ?, recurrence = ?, recur_from_due = ?
+	_, err = b.exec(ctx,
+		`UPDATE tasks SET summary = ?, description = ?, status = ?, priority = ?, due_date = ?, start_date = ?, completed = ?, modified = ?, parent_id = ?, categories = ?, recurrence = ?, recur_from_due = ?, position = ?, pinned = ?
 		 WHERE id = ? AND list_id = ? AND backend_id = ?`,
-		task.Summary, task.Description, task.Status, task.Priority, dueDateStr, startDateStr, completedStr, nowStr, task.ParentID, task.Categories, task.Recurrence, recurFromDueInt,
+		task.Summary, task.Description, task.Status, task.Priority, dueDateStr, startDateStr, completedStr, nowStr, task.ParentID, task.Categories, task.Recurrence, recurFromDueInt, task.Position, task.Pinned,
 		task.ID, listID, b.backendID,
 	)
 	if err != nil {
 		return nil, err
 	}
+	if err := saveTaskMetadata(ctx, b.db, task.ID, task.Metadata); err != nil {
+		return nil, err
+	}
 
 	// Fetch the updated task to get all fields including Created
-	return b.GetTask(ctx, listID, task.ID)
+	updated, err := b.GetTask(ctx, listID, task.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.recordChanges(ctx, b.db, listID, old, updated, now); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// loadListMetadata fills in the custom metadata of tasks read from a list
+func (b *Backend) loadListMetadata(ctx context.Context, listID string, tasks []backend.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	rows, err := b.db.QueryContext(ctx,
+		`SELECT m.task_id, m.key, m.value FROM task_metadata m
+		 JOIN tasks t ON t.id = m.task_id
+		 WHERE t.list_id = ? AND t.backend_id = ?`,
+		listID, b.backendID,
+	)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rows.Close() }()
+
+	byID := make(map[string]map[string]string)
+	for rows.Next() {
+		var taskID, key, value string
+		if err := rows.Scan(&taskID, &key, &value); err != nil {
+			return err
+		}
+		if byID[taskID] == nil {
+			byID[taskID] = make(map[string]string)
+		}
+		byID[taskID][key] = value
+	}
+	for i := range tasks {
+		tasks[i].Metadata = byID[tasks[i].ID]
+	}
+	return rows.Err()
+}
+
+// loadTaskMetadata fills in the custom metadata of a single task
+func (b *Backend) loadTaskMetadata(ctx context.Context, t *backend.Task) error {
+	rows, err := b.db.QueryContext(ctx, "SELECT key, value FROM task_metadata WHERE task_id = ?", t.ID)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return err
+		}
+		if t.Metadata == nil {
+			t.Metadata = make(map[string]string)
+		}
+		t.Metadata[key] = value
+	}
+	return rows.Err()
+}
+
+// execer is satisfied by both *sql.DB and *sql.Tx
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// saveTaskMetadata replaces the stored metadata of a task. A nil map leaves it
+// unchanged, so updates from backends that do not carry metadata keep it.
+func saveTaskMetadata(ctx context.Context, db execer, taskID string, metadata map[string]string) error {
+	if metadata == nil {
+		return nil
+	}
+	if _, err := db.ExecContext(ctx, "DELETE FROM task_metadata WHERE task_id = ?", taskID); err != nil {
+		return err
+	}
+	for key, value := range metadata {
+		if _, err := db.ExecContext(ctx,
+			"INSERT INTO task_metadata (task_id, key, value) VALUES (?, ?, ?)", taskID, key, value); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // DeleteTask removes a task for this backend
 func (b *Backend) DeleteTask(ctx context.Context, listID, taskID string) error {
-	_, err := b.db.ExecContext(ctx, "DELETE FROM tasks WHERE id = ? AND list_id = ? AND backend_id = ?", taskID, listID, b.backendID)
+	// The summary is kept in the history of the deleted task
+	var summary string
+	err := b.db.QueryRowContext(ctx, "SELECT summary FROM tasks WHERE id = ? AND list_id = ? AND backend_id = ?",
+		taskID, listID, b.backendID).Scan(&summary)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	res, err := b.exec(ctx, "DELETE FROM tasks WHERE id = ? AND list_id = ? AND backend_id = ?", taskID, listID, b.backendID)
+	if err != nil {
+		return err
+	}
+	if count, _ := res.RowsAffected(); count == 0 {
+		return nil
+	}
+	return b.recordHistory(ctx, b.db, listID, taskID, time.Now().UTC(), backend.HistoryDeleted, "", summary, "")
+}
+
+// GetTaskCounts returns the number of tasks in each list for this backend, keyed by list ID
+func (b *Backend) GetTaskCounts(ctx context.Context) (map[string]int, error) {
+	rows, err := b.db.QueryContext(ctx,
+		"SELECT list_id, COUNT(*) FROM tasks WHERE backend_id = ? GROUP BY list_id", b.backendID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var listID string
+		var count int
+		if err := rows.Scan(&listID, &count); err != nil {
+			return nil, err
+		}
+		counts[listID] = count
+	}
+	return counts, rows.Err()
+}
+
+// UpdateTasks modifies several tasks of a list in a single transaction for this
+// backend. If any task does not exist, no task is changed.
+func (b *Backend) UpdateTasks(ctx context.Context, listID string, tasks []backend.Task) ([]backend.Task, error) {
+	before, err := b.GetTasks(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	nowStr := now.Format(time.RFC3339Nano)
+	err = b.inTx(ctx, "update tasks", func(tx *sql.Tx) error {
+		stmt, err := tx.PrepareContext(ctx,
+			`UPDATE tasks SET summary = ?, description = ?, status = ?, priority = ?, due_date = ?, start_date = ?, completed = ?, modified = ?, parent_id = ?, categories = ?, recurrence = ?, recur_from_due = ?, position = ?, pinned = ?
+			 WHERE id = ? AND list_id = ? AND backend_id = ?`)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = stmt.Close() }()
+
+		for i := range tasks {
+			task := &tasks[i]
+			recurFromDueInt := 1
+			if !task.RecurFromDue {
+				recurFromDueInt = 0
+			}
+			res, err := stmt.ExecContext(ctx,
+				task.Summary, task.Description, task.Status, task.Priority,
+				dateToNullString(task.DueDate), dateToNullString(task.StartDate), timeToNullString(task.Completed),
+				nowStr, task.ParentID, task.Categories, task.Recurrence, recurFromDueInt, task.Position, task.Pinned,
+				task.ID, listID, b.backendID,
+			)
+			if err != nil {
+				return err
+			}
+			if count, _ := res.RowsAffected(); count == 0 {
+				return fmt.Errorf("task not found: %s", task.ID)
+			}
+			if err := saveTaskMetadata(ctx, tx, task.ID, task.Metadata); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Read the stored tasks back in one query, in the order they were given
+	stored, err := b.GetTasks(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]backend.Task, len(stored))
+	for _, t := range stored {
+		byID[t.ID] = t
+	}
+	oldByID := make(map[string]backend.Task, len(before))
+	for _, t := range before {
+		oldByID[t.ID] = t
+	}
+	updated := make([]backend.Task, 0, len(tasks))
+	for _, t := range tasks {
+		old, task := oldByID[t.ID], byID[t.ID]
+		if err := b.recordChanges(ctx, b.db, listID, &old, &task, now); err != nil {
+			return nil, err
+		}
+		updated = append(updated, task)
+	}
+	return updated, nil
+}
+
+// DeleteTasks removes several tasks of a list in a single transaction for this backend
+func (b *Backend) DeleteTasks(ctx context.Context, listID string, taskIDs []string) error {
+	summaries, err := b.taskSummaries(ctx, listID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	return b.inTx(ctx, "delete tasks", func(tx *sql.Tx) error {
+		stmt, err := tx.PrepareContext(ctx, "DELETE FROM tasks WHERE id = ? AND list_id = ? AND backend_id = ?")
+		if err != nil {
+			return err
+		}
+		defer func() { _ = stmt.Close() }()
+
+		for _, id := range taskIDs {
+			res, err := stmt.ExecContext(ctx, id, listID, b.backendID)
+			if err != nil {
+				return err
+			}
+			if count, _ := res.RowsAffected(); count == 0 {
+				continue
+			}
+			if err := b.recordHistory(ctx, tx, listID, id, now, backend.HistoryDeleted, "", summaries[id], ""); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// taskSummaries returns the summaries of the tasks of a list by ID, recorded
+// in the history of deleted tasks
+func (b *Backend) taskSummaries(ctx context.Context, listID string) (map[string]string, error) {
+	rows, err := b.db.QueryContext(ctx, "SELECT id, summary FROM tasks WHERE list_id = ? AND backend_id = ?", listID, b.backendID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	summaries := make(map[string]string)
+	for rows.Next() {
+		var id, summary string
+		if err := rows.Scan(&id, &summary); err != nil {
+			return nil, err
+		}
+		summaries[id] = summary
+	}
+	return summaries, rows.Err()
+}
+
+// recordHistory adds an entry to the history of a task, made by the actor of ctx
+func (b *Backend) recordHistory(ctx context.Context, db execer, listID, taskID string, at time.Time, action, field, oldValue, newValue string) error {
+	_, err := db.ExecContext(ctx,
+		`INSERT INTO task_history (task_id, list_id, backend_id, changed_at, actor, action, field, old_value, new_value)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		taskID, listID, b.backendID, at.Format(time.RFC3339Nano), backend.ActorFrom(ctx), action, field, oldValue, newValue,
+	)
 	return err
 }
 
+// recordChanges adds an entry to the history of a task for each field that
+// differs between old and updated. Saving a task unchanged records nothing.
+func (b *Backend) recordChanges(ctx context.Context, db execer, listID string, old, updated *backend.Task, at time.Time) error {
+	if old == nil || updated == nil {
+		return nil
+	}
+	for _, field := range backend.ChangedFields(old, updated) {
+		if err := b.recordHistory(ctx, db, listID, updated.ID, at, backend.HistoryUpdated, field,
+			backend.FieldValue(old, field), backend.FieldValue(updated, field)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TaskHistory returns the recorded changes of a task for this backend, oldest first
+func (b *Backend) TaskHistory(ctx context.Context, taskID string) ([]backend.HistoryEntry, error) {
+	rows, err := b.db.QueryContext(ctx,
+		`SELECT task_id, list_id, changed_at, actor, action, field, old_value, new_value
+		 FROM task_history WHERE task_id = ? AND backend_id = ? ORDER BY id`,
+		taskID, b.backendID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var entries []backend.HistoryEntry
+	for rows.Next() {
+		var e backend.HistoryEntry
+		var changedAt string
+		if err := rows.Scan(&e.TaskID, &e.ListID, &changedAt, &e.Actor, &e.Action, &e.Field, &e.OldValue, &e.NewValue); err != nil {
+			return nil, err
+		}
+		e.ChangedAt, _ = time.Parse(time.RFC3339Nano, changedAt)
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
 // Close closes the database connection
 func (b *Backend) Close() error {
 	if b.db != nil {
@@ -834,7 +1709,7 @@ func (b *Backend) Vacuum(ctx context.Context) (*VacuumResult, error) {
 	result.SizeBefore = pageCount * pageSize
 
 	// Run VACUUM
-	if _, err := b.db.ExecContext(ctx, "VACUUM"); err != nil {
+	if _, err := b.exec(ctx, "VACUUM"); err != nil {
 		return nil, fmt.Errorf("vacuum failed: %w", err)
 	}
 
@@ -848,14 +1723,160 @@ func (b *Backend) Vacuum(ctx context.Context) (*VacuumResult, error) {
 	// Store last vacuum time in metadata table
 	b.ensureMetadataTable(ctx)
 	now := time.Now().UTC().Format(time.RFC3339)
-	_, _ = b.db.ExecContext(ctx, "INSERT OR REPLACE INTO metadata (key, value) VALUES ('last_vacuum', ?)", now)
+	_, _ = b.exec(ctx, "INSERT OR REPLACE INTO metadata (key, value) VALUES ('last_vacuum', ?)", now)
 
 	return result, nil
 }
 
+// BackupFile writes a consistent snapshot of the database at src to dest with
+// VACUUM INTO. It is safe while other connections use the database. dest must
+// not exist.
+func BackupFile(ctx context.Context, src, dest string) error {
+	if _, err := os.Stat(src); err != nil {
+		return err
+	}
+	if _, err := os.Stat(dest); err == nil {
+		return fmt.Errorf("%s already exists", dest)
+	}
+	db, err := sql.Open("sqlite", src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+	db.SetMaxOpenConns(1)
+	if _, err := db.ExecContext(ctx, "PRAGMA busy_timeout = 5000"); err != nil {
+		return err
+	}
+	if _, err := db.ExecContext(ctx, "VACUUM INTO ?", dest); err != nil {
+		return fmt.Errorf("backup failed: %w", err)
+	}
+	return nil
+}
+
+// CheckFile reports an error unless path is an intact todoat SQLite database
+func CheckFile(ctx context.Context, path string) error {
+	if _, err := os.Stat(path); err != nil {
+		return err
+	}
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	var result string
+	if err := db.QueryRowContext(ctx, "PRAGMA integrity_check").Scan(&result); err != nil {
+		return fmt.Errorf("%s is not a SQLite database: %w", path, err)
+	}
+	if result != "ok" {
+		return fmt.Errorf("%s is damaged: %s", path, result)
+	}
+	var tables int
+	err = db.QueryRowContext(ctx, "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name IN ('tasks', 'task_lists')").Scan(&tables)
+	if err != nil {
+		return err
+	}
+	if tables != 2 {
+		return fmt.Errorf("%s is not a todoat database", path)
+	}
+	return nil
+}
+
+// LocalChange is a task modified locally since it was last synced with the remote
+type LocalChange struct {
+	Task         backend.Task
+	ListName     string
+	LastSyncedAt *time.Time // nil if the task has never been synced
+}
+
+// MarkTasksSynced records that the given tasks match the remote as of at
+func (b *Backend) MarkTasksSynced(ctx context.Context, taskIDs []string, at time.Time) error {
+	atStr := at.UTC().Format(time.RFC3339Nano)
+	for _, id := range taskIDs {
+		if _, err := b.exec(ctx,
+			"UPDATE tasks SET last_synced_at = ? WHERE id = ? AND backend_id = ?",
+			atStr, id, b.backendID,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetTaskSyncedAt returns when a task was last synced, or nil if it never was
+func (b *Backend) GetTaskSyncedAt(ctx context.Context, taskID string) (*time.Time, error) {
+	var syncedStr sql.NullString
+	err := b.db.QueryRowContext(ctx,
+		"SELECT last_synced_at FROM tasks WHERE id = ? AND backend_id = ?",
+		taskID, b.backendID,
+	).Scan(&syncedStr)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseOptionalDate(syncedStr), nil
+}
+
+// MarkAllSynced records that every task of this backend matches the remote as of at,
+// except the given tasks whose changes could not be pushed
+func (b *Backend) MarkAllSynced(ctx context.Context, at time.Time, except []string) error {
+	query := "UPDATE tasks SET last_synced_at = ? WHERE backend_id = ?"
+	args := []any{at.UTC().Format(time.RFC3339Nano), b.backendID}
+	if len(except) > 0 {
+		query += " AND id NOT IN (?" + strings.Repeat(", ?", len(except)-1) + ")"
+		for _, id := range except {
+			args = append(args, id)
+		}
+	}
+	_, err := b.exec(ctx, query, args...)
+	return err
+}
+
+// GetLocalChanges returns the tasks modified since their last sync (or never synced),
+// ordered by list name and modification time
+func (b *Backend) GetLocalChanges(ctx context.Context) ([]LocalChange, error) {
+	rows, err := b.db.QueryContext(ctx,
+		`SELECT t.id, t.list_id, t.summary, t.description, t.status, t.priority, t.due_date, t.start_date, t.completed, t.created, t.modified, t.parent_id, t.categories, t.recurrence, t.recur_from_due, t.position, t.pinned,
+		        l.name, t.last_synced_at
+		 FROM tasks t JOIN task_lists l ON l.id = t.list_id
+		 WHERE t.backend_id = ? AND l.deleted_at IS NULL
+		   AND (t.last_synced_at IS NULL OR julianday(t.modified) > julianday(t.last_synced_at))
+		 ORDER BY l.name, t.modified`,
+		b.backendID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	changes := []LocalChange{}
+	for rows.Next() {
+		var c LocalChange
+		var dueDateStr, startDateStr, completedStr, createdStr, modifiedStr sql.NullString
+		var categoriesStr, recurrenceStr, lastSyncedStr sql.NullString
+		var recurFromDue sql.NullInt64
+		if err := rows.Scan(
+			&c.Task.ID, &c.Task.ListID, &c.Task.Summary, &c.Task.Description, &c.Task.Status,
+			&c.Task.Priority, &dueDateStr, &startDateStr, &completedStr, &createdStr, &modifiedStr, &c.Task.ParentID, &categoriesStr,
+			&recurrenceStr, &recurFromDue, &c.Task.Position, &c.Task.Pinned, &c.ListName, &lastSyncedStr,
+		); err != nil {
+			return nil, err
+		}
+		parseDateStrings(&c.Task, dueDateStr, startDateStr, completedStr, createdStr, modifiedStr)
+		c.Task.Categories = categoriesStr.String
+		c.Task.Recurrence = recurrenceStr.String
+		c.Task.RecurFromDue = !recurFromDue.Valid || recurFromDue.Int64 == 1
+		c.LastSyncedAt = parseOptionalDate(lastSyncedStr)
+		changes = append(changes, c)
+	}
+	return changes, rows.Err()
+}
+
 // ensureMetadataTable creates the metadata table if it doesn't exist
 func (b *Backend) ensureMetadataTable(ctx context.Context) {
-	_, _ = b.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS metadata (key TEXT PRIMARY KEY, value TEXT)`)
+	_, _ = b.exec(ctx, `CREATE TABLE IF NOT EXISTS metadata (key TEXT PRIMARY KEY, value TEXT)`)
 }
 
 // DetectableBackend wraps Backend with auto-detection capabilities
@@ -898,3 +1919,4 @@ func (d *DetectableBackend) DetectionInfo() string {
 // Verify interface compliance at compile time
 var _ backend.TaskManager = (*Backend)(nil)
 var _ backend.DetectableBackend = (*DetectableBackend)(nil)
+var _ backend.ListArchiver = (*Backend)(nil)
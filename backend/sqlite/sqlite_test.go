@@ -2,6 +2,8 @@ package sqlite
 
 import (
 	"context"
+	"fmt"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -493,6 +495,160 @@ func TestRestoreList(t *testing.T) {
 	}
 }
 
+// TestGetTasksPage verifies that paging returns every task exactly once.
+func TestGetTasksPage(t *testing.T) {
+	b, ctx := mustNewBackend(t)
+
+	list := mustCreateList(t, b, ctx, "Paged")
+	for i := 0; i < 7; i++ {
+		if _, err := b.CreateTask(ctx, list.ID, &backend.Task{Summary: fmt.Sprintf("Task %d", i)}); err != nil {
+			t.Fatalf("CreateTask error: %v", err)
+		}
+	}
+
+	seen := make(map[string]bool)
+	var sizes []int
+	for offset := 0; ; offset += 3 {
+		page, err := b.GetTasksPage(ctx, list.ID, offset, 3)
+		if err != nil {
+			t.Fatalf("GetTasksPage error: %v", err)
+		}
+		sizes = append(sizes, len(page))
+		for _, task := range page {
+			if seen[task.ID] {
+				t.Errorf("task %q returned on more than one page", task.Summary)
+			}
+			seen[task.ID] = true
+		}
+		if len(page) < 3 {
+			break
+		}
+	}
+
+	if len(seen) != 7 {
+		t.Errorf("expected 7 distinct tasks across pages, got %d", len(seen))
+	}
+	if fmt.Sprint(sizes) != "[3 3 1]" {
+		t.Errorf("expected page sizes [3 3 1], got %v", sizes)
+	}
+}
+
+// TestGetTasksPageMatchesGetTasks verifies that paged and unpaged loading return the
+// same tasks in the same order, including after updates and deletions.
+func TestGetTasksPageMatchesGetTasks(t *testing.T) {
+	b, ctx := mustNewBackend(t)
+
+	list := mustCreateList(t, b, ctx, "Paged")
+	var created []*backend.Task
+	for i := 0; i < 9; i++ {
+		task, err := b.CreateTask(ctx, list.ID, &backend.Task{Summary: fmt.Sprintf("Task %d", i)})
+		if err != nil {
+			t.Fatalf("CreateTask error: %v", err)
+		}
+		created = append(created, task)
+	}
+	created[2].Summary = "Task 2 (edited)"
+	if _, err := b.UpdateTask(ctx, list.ID, created[2]); err != nil {
+		t.Fatalf("UpdateTask error: %v", err)
+	}
+	if err := b.DeleteTask(ctx, list.ID, created[5].ID); err != nil {
+		t.Fatalf("DeleteTask error: %v", err)
+	}
+
+	all, err := b.GetTasks(ctx, list.ID)
+	if err != nil {
+		t.Fatalf("GetTasks error: %v", err)
+	}
+	var unpaged, paged []string
+	for _, task := range all {
+		unpaged = append(unpaged, task.Summary)
+	}
+	for offset := 0; ; offset += 2 {
+		page, err := b.GetTasksPage(ctx, list.ID, offset, 2)
+		if err != nil {
+			t.Fatalf("GetTasksPage error: %v", err)
+		}
+		for _, task := range page {
+			paged = append(paged, task.Summary)
+		}
+		if len(page) < 2 {
+			break
+		}
+	}
+
+	if fmt.Sprint(paged) != fmt.Sprint(unpaged) {
+		t.Errorf("paged order differs from unpaged order:\npaged:   %v\nunpaged: %v", paged, unpaged)
+	}
+	if len(unpaged) != 8 || unpaged[0] != "Task 0" || unpaged[2] != "Task 2 (edited)" || unpaged[7] != "Task 8" {
+		t.Errorf("expected tasks in the order they were added, got %v", unpaged)
+	}
+}
+
+// TestArchiveList verifies that archived lists are hidden from GetLists but keep their tasks.
+func TestArchiveList(t *testing.T) {
+	b, ctx := mustNewBackend(t)
+
+	list := mustCreateList(t, b, ctx, "Finished")
+	if _, err := b.CreateTask(ctx, list.ID, &backend.Task{Summary: "Done work"}); err != nil {
+		t.Fatalf("CreateTask error: %v", err)
+	}
+
+	if err := b.ArchiveList(ctx, list.ID); err != nil {
+		t.Fatalf("ArchiveList error: %v", err)
+	}
+
+	lists, err := b.GetLists(ctx)
+	if err != nil {
+		t.Fatalf("GetLists error: %v", err)
+	}
+	for _, l := range lists {
+		if l.ID == list.ID {
+			t.Error("archived list should not be in active lists")
+		}
+	}
+
+	// Still reachable by name, with ArchivedAt set
+	byName, err := b.GetListByName(ctx, "Finished")
+	if err != nil {
+		t.Fatalf("GetListByName error: %v", err)
+	}
+	if byName == nil || byName.ArchivedAt == nil {
+		t.Fatalf("expected archived list by name with ArchivedAt set, got %+v", byName)
+	}
+
+	tasks, err := b.GetTasks(ctx, list.ID)
+	if err != nil {
+		t.Fatalf("GetTasks error: %v", err)
+	}
+	if len(tasks) != 1 {
+		t.Errorf("archived list should keep its tasks, got %d", len(tasks))
+	}
+
+	archived, err := b.GetArchivedLists(ctx)
+	if err != nil {
+		t.Fatalf("GetArchivedLists error: %v", err)
+	}
+	if len(archived) != 1 || archived[0].ID != list.ID {
+		t.Errorf("expected archived list in GetArchivedLists, got %+v", archived)
+	}
+
+	// Unarchive returns it to the active lists
+	if err := b.UnarchiveList(ctx, list.ID); err != nil {
+		t.Fatalf("UnarchiveList error: %v", err)
+	}
+	restored, err := b.GetList(ctx, list.ID)
+	if err != nil {
+		t.Fatalf("GetList error: %v", err)
+	}
+	if restored == nil || restored.ArchivedAt != nil {
+		t.Errorf("expected unarchived list, got %+v", restored)
+	}
+	archived, _ = b.GetArchivedLists(ctx)
+	if len(archived) != 0 {
+		t.Errorf("expected no archived lists after unarchive, got %d", len(archived))
+	}
+}
+
 // TestGetNonExistentList tests getting a list that doesn't exist.
 func TestGetNonExistentList(t *testing.T) {
 	b, ctx := mustNewBackend(t)
@@ -742,3 +898,330 @@ func TestBackendIsolation_Issue007(t *testing.T) {
 		t.Errorf("nextcloud backend should NOT see sqlite's task, but found: %+v", crossCheckTask2)
 	}
 }
+
+// TestUpdateTasksIsAtomic verifies a batch update changes every task, or none when one task is missing
+func TestUpdateTasksIsAtomic(t *testing.T) {
+	b, ctx := mustNewBackend(t)
+
+	list := mustCreateList(t, b, ctx, "Batch")
+	var tasks []backend.Task
+	for i := 0; i < 3; i++ {
+		task, err := b.CreateTask(ctx, list.ID, &backend.Task{Summary: fmt.Sprintf("Task %d", i)})
+		if err != nil {
+			t.Fatalf("CreateTask error: %v", err)
+		}
+		tasks = append(tasks, *task)
+	}
+
+	for i := range tasks {
+		tasks[i].Status = backend.StatusCompleted
+	}
+	updated, err := b.UpdateTasks(ctx, list.ID, tasks)
+	if err != nil {
+		t.Fatalf("UpdateTasks error: %v", err)
+	}
+	for i, task := range updated {
+		if task.ID != tasks[i].ID || task.Status != backend.StatusCompleted {
+			t.Errorf("updated[%d] = %s %s, want %s DONE", i, task.ID, task.Status, tasks[i].ID)
+		}
+	}
+
+	batch := []backend.Task{tasks[0], {ID: "missing", Summary: "Ghost"}}
+	batch[0].Summary = "Renamed"
+	if _, err := b.UpdateTasks(ctx, list.ID, batch); err == nil {
+		t.Fatal("expected an error for a missing task")
+	}
+	stored, err := b.GetTask(ctx, list.ID, tasks[0].ID)
+	if err != nil {
+		t.Fatalf("GetTask error: %v", err)
+	}
+	if stored.Summary != "Task 0" {
+		t.Errorf("failed batch should be rolled back, got summary %q", stored.Summary)
+	}
+}
+
+// TestDeleteTasksRemovesTree verifies a batch delete removes children and parents in one call
+func TestDeleteTasksRemovesTree(t *testing.T) {
+	b, ctx := mustNewBackend(t)
+
+	list := mustCreateList(t, b, ctx, "Batch")
+	parent, _ := b.CreateTask(ctx, list.ID, &backend.Task{Summary: "Parent"})
+	child, _ := b.CreateTask(ctx, list.ID, &backend.Task{Summary: "Child", ParentID: parent.ID})
+	keep, _ := b.CreateTask(ctx, list.ID, &backend.Task{Summary: "Keep"})
+
+	if err := b.DeleteTasks(ctx, list.ID, []string{child.ID, parent.ID}); err != nil {
+		t.Fatalf("DeleteTasks error: %v", err)
+	}
+	remaining, err := b.GetTasks(ctx, list.ID)
+	if err != nil {
+		t.Fatalf("GetTasks error: %v", err)
+	}
+	if len(remaining) != 1 || remaining[0].ID != keep.ID {
+		t.Errorf("expected only %q to remain, got %+v", keep.Summary, remaining)
+	}
+}
+
+// TestGetTaskCounts verifies per-list counts match the tasks GetTasks returns
+func TestGetTaskCounts(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "tasks.db")
+	b, err := New(dbPath)
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	defer func() { _ = b.Close() }()
+	ctx := context.Background()
+
+	work := mustCreateList(t, b, ctx, "Work")
+	home := mustCreateList(t, b, ctx, "Home")
+	empty := mustCreateList(t, b, ctx, "Empty")
+	for i := 0; i < 3; i++ {
+		if _, err := b.CreateTask(ctx, work.ID, &backend.Task{Summary: fmt.Sprintf("Work %d", i)}); err != nil {
+			t.Fatalf("CreateTask error: %v", err)
+		}
+	}
+	if _, err := b.CreateTask(ctx, home.ID, &backend.Task{Summary: "Home"}); err != nil {
+		t.Fatalf("CreateTask error: %v", err)
+	}
+
+	counts, err := b.GetTaskCounts(ctx)
+	if err != nil {
+		t.Fatalf("GetTaskCounts error: %v", err)
+	}
+	if counts[work.ID] != 3 || counts[home.ID] != 1 || counts[empty.ID] != 0 {
+		t.Errorf("unexpected counts: %v", counts)
+	}
+
+	other, err := NewWithBackendID(dbPath, "nextcloud")
+	if err != nil {
+		t.Fatalf("NewWithBackendID error: %v", err)
+	}
+	defer func() { _ = other.Close() }()
+	otherCounts, err := other.GetTaskCounts(ctx)
+	if err != nil {
+		t.Fatalf("GetTaskCounts error: %v", err)
+	}
+	if len(otherCounts) != 0 {
+		t.Errorf("counts should be isolated per backend, got %v", otherCounts)
+	}
+}
+
+func TestFindTask(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "tasks.db")
+	b, err := New(dbPath)
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	defer func() { _ = b.Close() }()
+	ctx := context.Background()
+
+	mustCreateList(t, b, ctx, "Work")
+	home := mustCreateList(t, b, ctx, "Home")
+	task, err := b.CreateTask(ctx, home.ID, &backend.Task{Summary: "Water plants", Categories: "garden"})
+	if err != nil {
+		t.Fatalf("CreateTask error: %v", err)
+	}
+
+	found, err := b.FindTask(ctx, task.ID)
+	if err != nil {
+		t.Fatalf("FindTask error: %v", err)
+	}
+	if found == nil || found.ListID != home.ID || found.Summary != "Water plants" || found.Categories != "garden" {
+		t.Fatalf("FindTask = %+v, want the task in Home", found)
+	}
+	if missing, err := b.FindTask(ctx, "no-such-uid"); err != nil || missing != nil {
+		t.Errorf("FindTask of an unknown ID = %v, %v; want nil", missing, err)
+	}
+
+	// Tasks of other backends sharing the database and of lists in the trash are not found
+	other, err := NewWithBackendID(dbPath, "nextcloud")
+	if err != nil {
+		t.Fatalf("NewWithBackendID error: %v", err)
+	}
+	defer func() { _ = other.Close() }()
+	if found, err := other.FindTask(ctx, task.ID); err != nil || found != nil {
+		t.Errorf("FindTask on another backend = %v, %v; want nil", found, err)
+	}
+	if err := b.DeleteList(ctx, home.ID); err != nil {
+		t.Fatalf("DeleteList error: %v", err)
+	}
+	if found, err := b.FindTask(ctx, task.ID); err != nil || found != nil {
+		t.Errorf("FindTask in a deleted list = %v, %v; want nil", found, err)
+	}
+}
+
+func TestGetLocalChangesTracksLastSync(t *testing.T) {
+	b, ctx := mustNewBackend(t)
+	list := mustCreateList(t, b, ctx, "Inbox")
+	first := mustCreateTask(t, b, ctx, list.ID, &backend.Task{Summary: "First"})
+	second := mustCreateTask(t, b, ctx, list.ID, &backend.Task{Summary: "Second"})
+
+	changes, err := b.GetLocalChanges(ctx)
+	if err != nil {
+		t.Fatalf("GetLocalChanges error: %v", err)
+	}
+	if len(changes) != 2 || changes[0].LastSyncedAt != nil || changes[0].ListName != "Inbox" {
+		t.Fatalf("expected 2 never-synced changes in Inbox, got %+v", changes)
+	}
+
+	// A failed push keeps the second task pending
+	if err := b.MarkAllSynced(ctx, time.Now().Add(time.Millisecond), []string{second.ID}); err != nil {
+		t.Fatalf("MarkAllSynced error: %v", err)
+	}
+	changes, _ = b.GetLocalChanges(ctx)
+	if len(changes) != 1 || changes[0].Task.ID != second.ID {
+		t.Fatalf("expected only the second task pending, got %+v", changes)
+	}
+
+	if err := b.MarkTasksSynced(ctx, []string{second.ID}, time.Now().Add(time.Millisecond)); err != nil {
+		t.Fatalf("MarkTasksSynced error: %v", err)
+	}
+	if changes, _ = b.GetLocalChanges(ctx); len(changes) != 0 {
+		t.Fatalf("expected no pending changes, got %+v", changes)
+	}
+
+	// Editing after the sync makes the task pending again
+	time.Sleep(5 * time.Millisecond)
+	first.Summary = "First (edited)"
+	if _, err := b.UpdateTask(ctx, list.ID, first); err != nil {
+		t.Fatalf("UpdateTask error: %v", err)
+	}
+	changes, _ = b.GetLocalChanges(ctx)
+	if len(changes) != 1 || changes[0].Task.Summary != "First (edited)" || changes[0].LastSyncedAt == nil {
+		t.Fatalf("expected edited task with last sync time, got %+v", changes)
+	}
+}
+
+// TestTaskMetadata verifies custom metadata is stored, kept on updates without it, and removed with the task
+func TestTaskMetadata(t *testing.T) {
+	b, ctx := mustNewBackend(t)
+
+	list := mustCreateList(t, b, ctx, "Meta")
+	task, err := b.CreateTask(ctx, list.ID, &backend.Task{
+		Summary:  "Invoice",
+		Metadata: map[string]string{"client": "acme", "estimate": "3h"},
+	})
+	if err != nil {
+		t.Fatalf("CreateTask error: %v", err)
+	}
+
+	stored, err := b.GetTask(ctx, list.ID, task.ID)
+	if err != nil {
+		t.Fatalf("GetTask error: %v", err)
+	}
+	if stored.Metadata["client"] != "acme" || stored.Metadata["estimate"] != "3h" {
+		t.Errorf("expected metadata to round-trip, got %v", stored.Metadata)
+	}
+
+	stored.Metadata = nil
+	stored.Summary = "Send invoice"
+	if _, err := b.UpdateTask(ctx, list.ID, stored); err != nil {
+		t.Fatalf("UpdateTask error: %v", err)
+	}
+	tasks, err := b.GetTasks(ctx, list.ID)
+	if err != nil {
+		t.Fatalf("GetTasks error: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].Metadata["client"] != "acme" {
+		t.Errorf("nil metadata should leave stored metadata unchanged, got %+v", tasks)
+	}
+
+	tasks[0].Metadata = map[string]string{"client": "globex"}
+	updated, err := b.UpdateTasks(ctx, list.ID, tasks)
+	if err != nil {
+		t.Fatalf("UpdateTasks error: %v", err)
+	}
+	if len(updated[0].Metadata) != 1 || updated[0].Metadata["client"] != "globex" {
+		t.Errorf("expected metadata to be replaced, got %v", updated[0].Metadata)
+	}
+
+	if err := b.DeleteTask(ctx, list.ID, task.ID); err != nil {
+		t.Fatalf("DeleteTask error: %v", err)
+	}
+	var count int
+	if err := b.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM task_metadata").Scan(&count); err != nil {
+		t.Fatalf("query error: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("expected metadata to be deleted with the task, got %d rows", count)
+	}
+}
+
+// TestTaskHistory verifies changes are recorded per field with the actor of their context
+func TestTaskHistory(t *testing.T) {
+	b, ctx := mustNewBackend(t)
+	list := mustCreateList(t, b, ctx, "History")
+
+	task, err := b.CreateTask(backend.WithActor(ctx, "cli:alice@laptop"), list.ID, &backend.Task{Summary: "Review PR", Priority: 2})
+	if err != nil {
+		t.Fatalf("CreateTask error: %v", err)
+	}
+	task.Priority = 5
+	task.Status = backend.StatusCompleted
+	if _, err := b.UpdateTask(backend.WithActor(ctx, "sync:todoist"), list.ID, task); err != nil {
+		t.Fatalf("UpdateTask error: %v", err)
+	}
+	// Saving a task unchanged records nothing
+	if _, err := b.UpdateTasks(ctx, list.ID, []backend.Task{*task}); err != nil {
+		t.Fatalf("UpdateTasks error: %v", err)
+	}
+	if err := b.DeleteTask(backend.WithActor(ctx, "cli:alice@laptop"), list.ID, task.ID); err != nil {
+		t.Fatalf("DeleteTask error: %v", err)
+	}
+
+	entries, err := b.TaskHistory(ctx, task.ID)
+	if err != nil {
+		t.Fatalf("TaskHistory error: %v", err)
+	}
+	want := []backend.HistoryEntry{
+		{Actor: "cli:alice@laptop", Action: backend.HistoryCreated, NewValue: "Review PR"},
+		{Actor: "sync:todoist", Action: backend.HistoryUpdated, Field: backend.FieldStatus, OldValue: "NEEDS-ACTION", NewValue: "COMPLETED"},
+		{Actor: "sync:todoist", Action: backend.HistoryUpdated, Field: backend.FieldPriority, OldValue: "2", NewValue: "5"},
+		{Actor: "cli:alice@laptop", Action: backend.HistoryDeleted, OldValue: "Review PR"},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("expected %d history entries, got %+v", len(want), entries)
+	}
+	for i, e := range entries {
+		e.TaskID, e.ListID, e.ChangedAt = "", "", time.Time{}
+		if e != want[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, e, want[i])
+		}
+	}
+
+}
+
+// TestRetryBusy verifies writes are retried while the database is busy and
+// other errors are returned at once
+func TestRetryBusy(t *testing.T) {
+	ctx := context.Background()
+
+	calls := 0
+	err := retryBusy(ctx, "write", func() error {
+		calls++
+		if calls < 3 {
+			return fmt.Errorf("database is locked (5) (SQLITE_BUSY)")
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Errorf("busy write: err = %v after %d calls, want success after 3", err, calls)
+	}
+
+	calls = 0
+	err = retryBusy(ctx, "write", func() error {
+		calls++
+		return fmt.Errorf("UNIQUE constraint failed")
+	})
+	if err == nil || calls != 1 {
+		t.Errorf("failed write: err = %v after %d calls, want the error after 1", err, calls)
+	}
+
+	calls = 0
+	err = retryBusy(ctx, "write", func() error {
+		calls++
+		return fmt.Errorf("database is locked")
+	})
+	if !isBusy(err) || calls != busyRetries+1 {
+		t.Errorf("always busy: err = %v after %d calls, want the busy error after %d", err, calls, busyRetries+1)
+	}
+}
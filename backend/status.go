@@ -0,0 +1,140 @@
+package backend
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// CustomStatus is a user-defined open status such as WAITING or BLOCKED.
+// Backends that store statuses as text (SQLite, remote) keep it as-is; the
+// others store its Base, and sync keeps the custom status locally while the
+// remote still has that base.
+type CustomStatus struct {
+	Name TaskStatus // Upper-case name, e.g. "WAITING"
+	Base TaskStatus // StatusNeedsAction or StatusInProgress
+}
+
+// StatusWorkflow holds the configured custom statuses and the allowed
+// transitions between statuses
+type StatusWorkflow struct {
+	Custom []CustomStatus
+	// Transitions lists the statuses each status may change to. A status
+	// without an entry may change to any status; nil allows every transition.
+	Transitions map[TaskStatus][]TaskStatus
+}
+
+// statusWorkflow is the workflow in effect, set from the config at startup
+var statusWorkflow StatusWorkflow
+
+// SetStatusWorkflow makes w the workflow used by status lookups and
+// transition checks. The zero value restores the four built-in statuses.
+func SetStatusWorkflow(w StatusWorkflow) {
+	statusWorkflow = w
+}
+
+// CustomStatuses returns the configured custom statuses in config order
+func CustomStatuses() []CustomStatus {
+	return statusWorkflow.Custom
+}
+
+// IsBuiltin reports whether s is one of the four built-in statuses
+func (s TaskStatus) IsBuiltin() bool {
+	switch s {
+	case StatusNeedsAction, StatusCompleted, StatusInProgress, StatusCancelled:
+		return true
+	}
+	return false
+}
+
+// IsCustom reports whether s is a configured custom status
+func (s TaskStatus) IsCustom() bool {
+	_, ok := s.custom()
+	return ok
+}
+
+// Base returns the built-in status s is stored as by backends with a fixed
+// set of statuses: s itself for built-in statuses, the configured base for
+// custom ones, and StatusNeedsAction for anything else
+func (s TaskStatus) Base() TaskStatus {
+	if s.IsBuiltin() {
+		return s
+	}
+	if c, ok := s.custom(); ok {
+		return c.Base
+	}
+	return StatusNeedsAction
+}
+
+// Name returns the user-facing name of s: TODO, IN-PROGRESS, DONE, CANCELLED
+// or the name of a custom status. Unknown statuses show as TODO.
+func (s TaskStatus) Name() string {
+	switch s {
+	case StatusCompleted:
+		return "DONE"
+	case StatusInProgress:
+		return "IN-PROGRESS"
+	case StatusCancelled:
+		return "CANCELLED"
+	}
+	if s.IsCustom() {
+		return string(s)
+	}
+	return "TODO"
+}
+
+func (s TaskStatus) custom() (CustomStatus, bool) {
+	for _, c := range statusWorkflow.Custom {
+		if c.Name == s {
+			return c, true
+		}
+	}
+	return CustomStatus{}, false
+}
+
+// CheckStatusTransition returns an error if the workflow does not allow a
+// task to change from one status to another
+func CheckStatusTransition(from, to TaskStatus) error {
+	if from == to {
+		return nil
+	}
+	allowed, ok := statusWorkflow.Transitions[from]
+	if !ok || slices.Contains(allowed, to) {
+		return nil
+	}
+	names := make([]string, len(allowed))
+	for i, s := range allowed {
+		names[i] = s.Name()
+	}
+	return fmt.Errorf("status cannot change from %s to %s (allowed: %s)", from.Name(), to.Name(), strings.Join(names, ", "))
+}
+
+// NextOpenStatus returns the open status after s in the order TODO,
+// IN-PROGRESS, then the custom statuses, skipping statuses s may not change
+// to. It returns s when there is none.
+func NextOpenStatus(s TaskStatus) TaskStatus {
+	cycle := []TaskStatus{StatusNeedsAction, StatusInProgress}
+	for _, c := range statusWorkflow.Custom {
+		cycle = append(cycle, c.Name)
+	}
+	start := slices.Index(cycle, s)
+	for i := 1; i <= len(cycle); i++ {
+		next := cycle[(start+i+len(cycle))%len(cycle)]
+		if next != s && CheckStatusTransition(s, next) == nil {
+			return next
+		}
+	}
+	return s
+}
+
+// CustomStatusStorer is implemented by backends that store custom statuses
+// as-is instead of their base
+type CustomStatusStorer interface {
+	StoresCustomStatuses() bool
+}
+
+// StoresCustomStatuses reports whether tm keeps custom statuses as-is
+func StoresCustomStatuses(tm TaskManager) bool {
+	storer, ok := findOptional[CustomStatusStorer](tm)
+	return ok && storer.StoresCustomStatuses()
+}
@@ -0,0 +1,78 @@
+package backend_test
+
+import (
+	"testing"
+
+	"todoat/backend"
+)
+
+// useWorkflow sets a status workflow for the duration of a test
+func useWorkflow(t *testing.T, w backend.StatusWorkflow) {
+	t.Helper()
+	backend.SetStatusWorkflow(w)
+	t.Cleanup(func() { backend.SetStatusWorkflow(backend.StatusWorkflow{}) })
+}
+
+// TestCustomStatusBaseAndName verifies custom statuses map to their base and keep their name
+func TestCustomStatusBaseAndName(t *testing.T) {
+	useWorkflow(t, backend.StatusWorkflow{Custom: []backend.CustomStatus{
+		{Name: "WAITING", Base: backend.StatusNeedsAction},
+		{Name: "REVIEW", Base: backend.StatusInProgress},
+	}})
+
+	tests := []struct {
+		status backend.TaskStatus
+		base   backend.TaskStatus
+		name   string
+	}{
+		{"WAITING", backend.StatusNeedsAction, "WAITING"},
+		{"REVIEW", backend.StatusInProgress, "REVIEW"},
+		{backend.StatusCompleted, backend.StatusCompleted, "DONE"},
+		{"UNKNOWN", backend.StatusNeedsAction, "TODO"},
+	}
+	for _, tt := range tests {
+		if got := tt.status.Base(); got != tt.base {
+			t.Errorf("%s.Base() = %s, want %s", tt.status, got, tt.base)
+		}
+		if got := tt.status.Name(); got != tt.name {
+			t.Errorf("%s.Name() = %s, want %s", tt.status, got, tt.name)
+		}
+	}
+	if backend.TaskStatus("UNKNOWN").IsCustom() || !backend.TaskStatus("WAITING").IsCustom() {
+		t.Error("IsCustom should only match configured statuses")
+	}
+}
+
+// TestStatusTransitions verifies the transition graph and the open-status cycle
+func TestStatusTransitions(t *testing.T) {
+	useWorkflow(t, backend.StatusWorkflow{
+		Custom: []backend.CustomStatus{{Name: "BLOCKED", Base: backend.StatusNeedsAction}},
+		Transitions: map[backend.TaskStatus][]backend.TaskStatus{
+			"BLOCKED":                {backend.StatusNeedsAction},
+			backend.StatusInProgress: {"BLOCKED", backend.StatusCompleted},
+		},
+	})
+
+	if err := backend.CheckStatusTransition("BLOCKED", backend.StatusCompleted); err == nil {
+		t.Error("BLOCKED -> DONE should not be allowed")
+	}
+	for _, tt := range [][2]backend.TaskStatus{
+		{"BLOCKED", backend.StatusNeedsAction},
+		{backend.StatusNeedsAction, backend.StatusCancelled}, // no entry allows anything
+		{"BLOCKED", "BLOCKED"},
+	} {
+		if err := backend.CheckStatusTransition(tt[0], tt[1]); err != nil {
+			t.Errorf("%s -> %s: %v", tt[0], tt[1], err)
+		}
+	}
+
+	if got := backend.NextOpenStatus(backend.StatusNeedsAction); got != backend.StatusInProgress {
+		t.Errorf("NextOpenStatus(TODO) = %s", got)
+	}
+	if got := backend.NextOpenStatus(backend.StatusInProgress); got != "BLOCKED" {
+		t.Errorf("NextOpenStatus(IN-PROGRESS) = %s", got)
+	}
+	if got := backend.NextOpenStatus("BLOCKED"); got != backend.StatusNeedsAction {
+		t.Errorf("NextOpenStatus(BLOCKED) = %s", got)
+	}
+}
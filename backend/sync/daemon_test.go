@@ -1,6 +1,7 @@
 package sync_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
@@ -84,6 +85,42 @@ func TestSyncDaemonStatusCLI(t *testing.T) {
 	cli.MustExecute("-y", "sync", "daemon", "stop")
 }
 
+// TestSyncDaemonStatusJSONMetricsCLI tests that 'sync daemon status --json' reports health metrics
+func TestSyncDaemonStatusJSONMetricsCLI(t *testing.T) {
+	cli := testutil.NewCLITestWithDaemon(t)
+	cli.SetDaemonInterval(100 * time.Millisecond)
+
+	cli.MustExecute("-y", "sync", "daemon", "start")
+	defer cli.MustExecute("-y", "sync", "daemon", "stop")
+	cli.WaitForSyncCount(5*time.Second, 1)
+
+	stdout := cli.MustExecute("-y", "--json", "sync", "daemon", "status")
+	var status struct {
+		Running           bool   `json:"running"`
+		StartedAt         string `json:"started_at"`
+		UptimeSecs        *int   `json:"uptime_secs"`
+		NextSync          string `json:"next_sync"`
+		QueueDepth        *int   `json:"queue_depth"`
+		ErrorCount        *int   `json:"error_count"`
+		ConsecutiveErrors *int   `json:"consecutive_errors"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &status); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout)
+	}
+	if !status.Running || status.StartedAt == "" || status.NextSync == "" {
+		t.Errorf("status should include start and next sync times: %s", stdout)
+	}
+	if status.UptimeSecs == nil || status.QueueDepth == nil || status.ErrorCount == nil || status.ConsecutiveErrors == nil {
+		t.Errorf("status should always include uptime, queue depth and error counts: %s", stdout)
+	}
+
+	stdout = cli.MustExecute("-y", "sync", "daemon", "status")
+	testutil.AssertContains(t, stdout, "Uptime:")
+	testutil.AssertContains(t, stdout, "Next sync:")
+	testutil.AssertContains(t, stdout, "Queue depth: 0")
+	testutil.AssertContains(t, stdout, "Errors: 0 (0 consecutive)")
+}
+
 // TestSyncDaemonIntervalCLI tests that sync runs at configured interval
 func TestSyncDaemonIntervalCLI(t *testing.T) {
 	cli := testutil.NewCLITestWithDaemon(t)
@@ -1640,3 +1677,118 @@ default_backend: sqlite
 		t.Errorf("CLI took %v to return, expected <1s for local operation", elapsed)
 	}
 }
+
+// =============================================================================
+// Notification Callback Task Actions (snooze/complete via daemon IPC)
+// =============================================================================
+
+// addTaskForCallback adds a task and returns its UID
+func addTaskForCallback(t *testing.T, cli *testutil.DaemonCLITest, summary string) string {
+	t.Helper()
+	stdout := cli.MustExecute("-y", "--json", "Work", "add", summary)
+	var resp struct {
+		Task struct {
+			UID string `json:"uid"`
+		} `json:"task"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &resp); err != nil || resp.Task.UID == "" {
+		t.Fatalf("failed to get task UID from %q: %v", stdout, err)
+	}
+	return resp.Task.UID
+}
+
+// TestDaemonCallbackSnoozeCLI verifies 'sync daemon callback <uid> snooze' defers the task's due date
+func TestDaemonCallbackSnoozeCLI(t *testing.T) {
+	cli := testutil.NewCLITestWithDaemon(t)
+
+	uid := addTaskForCallback(t, cli, "Snooze via notification")
+
+	cli.MustExecute("-y", "sync", "daemon", "start")
+	defer cli.MustExecute("-y", "sync", "daemon", "stop")
+
+	stdout := cli.MustExecute("-y", "sync", "daemon", "callback", uid, "snooze", "--duration", "48h")
+	testutil.AssertContains(t, stdout, "Sent snooze")
+	testutil.AssertResultCode(t, stdout, testutil.ResultActionCompleted)
+
+	expected := time.Now().Add(48 * time.Hour).Format("2006-01-02")
+	stdout = cli.MustExecute("-y", "--json", "Work")
+	testutil.AssertContains(t, stdout, expected)
+}
+
+// TestDaemonCallbackCompleteCLI verifies 'sync daemon callback <uid> complete' completes the task
+func TestDaemonCallbackCompleteCLI(t *testing.T) {
+	cli := testutil.NewCLITestWithDaemon(t)
+
+	uid := addTaskForCallback(t, cli, "Complete via notification")
+
+	cli.MustExecute("-y", "sync", "daemon", "start")
+	defer cli.MustExecute("-y", "sync", "daemon", "stop")
+
+	cli.MustExecute("-y", "sync", "daemon", "callback", uid, "complete")
+
+	stdout := cli.MustExecute("-y", "Work", "-s", "DONE")
+	testutil.AssertContains(t, stdout, "Complete via notification")
+}
+
+// TestDaemonCallbackErrorsCLI verifies invalid callbacks are rejected
+func TestDaemonCallbackErrorsCLI(t *testing.T) {
+	cli := testutil.NewCLITestWithDaemon(t)
+
+	// Daemon not running
+	_, stderr := cli.ExecuteAndFail("-y", "sync", "daemon", "callback", "some-uid", "complete")
+	testutil.AssertContains(t, stderr, "not running")
+
+	cli.MustExecute("-y", "sync", "daemon", "start")
+	defer cli.MustExecute("-y", "sync", "daemon", "stop")
+
+	_, stderr = cli.ExecuteAndFail("-y", "sync", "daemon", "callback", "some-uid", "archive")
+	testutil.AssertContains(t, stderr, "unknown task action")
+
+	_, stderr = cli.ExecuteAndFail("-y", "sync", "daemon", "callback", "missing-uid", "complete")
+	testutil.AssertContains(t, stderr, "task not found")
+}
+
+// TestDaemonCallbackCompleteSubtaskCLI verifies completing a subtask through the daemon
+// applies auto_complete_parent instead of crashing the daemon
+func TestDaemonCallbackCompleteSubtaskCLI(t *testing.T) {
+	cli := testutil.NewCLITestWithDaemon(t)
+	if err := os.WriteFile(cli.ConfigPath(), []byte("default_backend: sqlite\nauto_complete_parent: true\n"), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	cli.MustExecute("-y", "Work", "add", "Release")
+	uid := addTaskForCallback(t, cli, "Release/Changelog")
+
+	cli.MustExecute("-y", "sync", "daemon", "start")
+	defer cli.MustExecute("-y", "sync", "daemon", "stop")
+
+	cli.MustExecute("-y", "sync", "daemon", "callback", uid, "complete")
+
+	stdout := cli.MustExecute("-y", "Work", "-s", "DONE")
+	testutil.AssertContains(t, stdout, "Changelog")
+	testutil.AssertContains(t, stdout, "Release")
+}
+
+// TestSyncWatchCLI tests that 'todoat sync watch' runs sync cycles in the foreground and exits after --max-cycles
+func TestSyncWatchCLI(t *testing.T) {
+	cli, tmpDir := newSyncTestCLI(t)
+	createSyncConfig(t, tmpDir, true)
+
+	stdout := cli.MustExecute("-y", "sync", "watch", "--interval", "1", "--max-cycles", "2", "--no-change-detection")
+
+	testutil.AssertContains(t, stdout, "Watching for changes (interval: 1s)")
+	testutil.AssertContains(t, stdout, "sync #1 (start): ok, 0 pending")
+	testutil.AssertContains(t, stdout, "sync #2 (interval): ok")
+	testutil.AssertContains(t, stdout, "Sync watch stopped after 2 sync(s)")
+}
+
+// TestSyncWatchRefusesWhileDaemonRunningCLI tests that sync watch does not run alongside the daemon
+func TestSyncWatchRefusesWhileDaemonRunningCLI(t *testing.T) {
+	cli := testutil.NewCLITestWithDaemon(t)
+
+	cli.MustExecute("-y", "sync", "daemon", "start")
+	defer cli.MustExecute("-y", "sync", "daemon", "stop")
+
+	_, stderr := cli.ExecuteAndFail("-y", "sync", "watch", "--max-cycles", "1")
+	testutil.AssertContains(t, stderr, "sync daemon is running")
+}
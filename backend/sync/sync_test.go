@@ -1525,6 +1525,181 @@ func TestConflictResolveServerWinsApplied(t *testing.T) {
 	}
 }
 
+// TestConflictsPendingBadgeCLI verifies that get and list warn about pending
+// conflicts and report them as conflicts_pending in JSON
+func TestConflictsPendingBadgeCLI(t *testing.T) {
+	cli, tmpDir := newSyncTestCLI(t)
+	createSyncConfig(t, tmpDir, true)
+
+	cli.MustExecute("-y", "Work", "add", "Local Task")
+	stdout := cli.MustExecute("-y", "--json", "Work", "get")
+	testutil.AssertContains(t, stdout, `"conflicts_pending":0`)
+	_, stderr, _ := cli.Execute("-y", "Work", "get")
+	testutil.AssertNotContains(t, stderr, "conflict")
+
+	db, err := sql.Open("sqlite", cli.Config().DBPath)
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+	for _, uid := range []string{"uid-1", "uid-2"} {
+		if _, err := db.Exec(`
+			INSERT INTO sync_conflicts (task_uid, task_summary, list_id, local_version, remote_version,
+			                            local_modified, remote_modified, detected_at, status)
+			VALUES (?, 'Local Task', 0, '{}', '{}', datetime('now'), datetime('now'), datetime('now'), 'pending')
+		`, uid); err != nil {
+			t.Fatalf("failed to insert conflict: %v", err)
+		}
+	}
+
+	_, stderr, exitCode := cli.Execute("-y", "Work", "get")
+	if exitCode != 0 {
+		t.Fatalf("get failed: %s", stderr)
+	}
+	testutil.AssertContains(t, stderr, "2 sync conflicts pending — run 'todoat sync conflicts'")
+	_, stderr, _ = cli.Execute("-y", "list")
+	testutil.AssertContains(t, stderr, "2 sync conflicts pending")
+
+	testutil.AssertContains(t, cli.MustExecute("-y", "--json", "Work", "get"), `"conflicts_pending":2`)
+	testutil.AssertContains(t, cli.MustExecute("-y", "--json", "list"), `"conflicts_pending":2`)
+}
+
+// TestSyncRoutesListsToBackendsCLI verifies that with routes each list of the
+// local database is pushed to and pulled from its own backend, and that
+// unrouted lists stay local
+func TestSyncRoutesListsToBackendsCLI(t *testing.T) {
+	cli, tmpDir := newSyncTestCLI(t)
+	alphaPath := filepath.Join(tmpDir, "alpha.db")
+	betaPath := filepath.Join(tmpDir, "beta.db")
+	configContent := `
+sync:
+  enabled: true
+  local_backend: sqlite
+  auto_sync_after_operation: false
+backends:
+  sqlite:
+    type: sqlite
+    enabled: true
+  alpha:
+    type: sqlite
+    enabled: true
+    path: "` + alphaPath + `"
+  beta:
+    type: sqlite
+    enabled: true
+    path: "` + betaPath + `"
+routes:
+  Work: alpha
+  personal: beta
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "config.yaml"), []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	cli.MustExecute("-y", "Work", "add", "Work task")
+	cli.MustExecute("-y", "Personal", "add", "Personal task")
+	cli.MustExecute("-y", "Notes", "add", "Local note")
+
+	stdout := cli.MustExecute("-y", "--json", "sync", "queue")
+	testutil.AssertContains(t, stdout, `"task_summary":"Work task","retry_count":0,"backend":"alpha"`)
+	testutil.AssertContains(t, stdout, `"task_summary":"Personal task","retry_count":0,"backend":"beta"`)
+	testutil.AssertNotContains(t, stdout, "Local note")
+
+	cli.MustExecute("-y", "sync")
+	remoteSummaries := func(path string) string {
+		db, err := sql.Open("sqlite", path)
+		if err != nil {
+			t.Fatalf("failed to open %s: %v", path, err)
+		}
+		defer func() { _ = db.Close() }()
+		rows, err := db.Query("SELECT summary FROM tasks")
+		if err != nil {
+			t.Fatalf("failed to read tasks of %s: %v", path, err)
+		}
+		defer func() { _ = rows.Close() }()
+		var summaries []string
+		for rows.Next() {
+			var summary string
+			_ = rows.Scan(&summary)
+			summaries = append(summaries, summary)
+		}
+		return strings.Join(summaries, ",")
+	}
+	if got := remoteSummaries(alphaPath); got != "Work task" {
+		t.Errorf("alpha tasks = %q, want only the Work task", got)
+	}
+	if got := remoteSummaries(betaPath); got != "Personal task" {
+		t.Errorf("beta tasks = %q, want only the Personal task", got)
+	}
+
+	// A task added on beta is pulled into its routed list only
+	betaDB, err := sql.Open("sqlite", betaPath)
+	if err != nil {
+		t.Fatalf("failed to open beta: %v", err)
+	}
+	defer func() { _ = betaDB.Close() }()
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	if _, err := betaDB.Exec(`INSERT INTO tasks (id, list_id, summary, status, created, modified, backend_id)
+		SELECT 'beta-task-1', id, 'Remote personal task', 'NEEDS-ACTION', ?, ?, 'beta' FROM task_lists WHERE name = 'Personal'`, now, now); err != nil {
+		t.Fatalf("failed to add remote task: %v", err)
+	}
+	cli.MustExecute("-y", "sync")
+	testutil.AssertContains(t, cli.MustExecute("-y", "Personal"), "Remote personal task")
+	testutil.AssertNotContains(t, cli.MustExecute("-y", "Work"), "Remote personal task")
+	testutil.AssertContains(t, cli.MustExecute("-y", "Notes"), "Local note")
+	testutil.AssertContains(t, cli.MustExecute("-y", "Work"), "Work task")
+}
+
+// TestSyncErrorMarkerCLI verifies a push error recorded against a task is
+// marked in get and shown by task show, in text and JSON
+func TestSyncErrorMarkerCLI(t *testing.T) {
+	cli, tmpDir := newSyncTestCLI(t)
+	createSyncConfig(t, tmpDir, true)
+
+	cli.MustExecute("-y", "Work", "add", "Rejected Task")
+	cli.MustExecute("-y", "Work", "add", "Fine Task")
+	stdout := cli.MustExecute("-y", "--json", "Work", "get")
+	testutil.AssertNotContains(t, stdout, "sync_error")
+	var tasks struct {
+		Tasks []struct {
+			UID     string `json:"uid"`
+			Summary string `json:"summary"`
+		} `json:"tasks"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &tasks); err != nil {
+		t.Fatalf("failed to parse task JSON: %v", err)
+	}
+	var uid string
+	for _, task := range tasks.Tasks {
+		if task.Summary == "Rejected Task" {
+			uid = task.UID
+		}
+	}
+
+	db, err := sql.Open("sqlite", cli.Config().DBPath)
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+	if _, err := db.Exec(`
+		INSERT INTO sync_errors (task_uid, backend_id, operation_type, error, attempts, failed_at)
+		VALUES (?, 'todoist', 'create', 'failed to create task: status 400', 1, ?)
+	`, uid, time.Now().UTC().Format(time.RFC3339Nano)); err != nil {
+		t.Fatalf("failed to insert sync error: %v", err)
+	}
+
+	stdout = cli.MustExecute("-y", "Work", "get")
+	testutil.AssertContains(t, stdout, "Rejected Task [sync error]")
+	testutil.AssertNotContains(t, stdout, "Fine Task [sync error]")
+	stdout = cli.MustExecute("-y", "--json", "Work", "get")
+	testutil.AssertContains(t, stdout, `"sync_error":{"backend":"todoist","operation":"create","error":"failed to create task: status 400","attempts":1`)
+
+	stdout = cli.MustExecute("-y", "task", uid)
+	testutil.AssertContains(t, stdout, "Sync error:  create to 'todoist' failed after 1 attempt(s)")
+	testutil.AssertContains(t, stdout, "status 400")
+	testutil.AssertContains(t, cli.MustExecute("-y", "--json", "task", uid), `"sync_error":{"backend":"todoist"`)
+}
+
 // TestConflictResolveLocalWinsQueuesUpdate tests that local_wins strategy keeps local version
 // and queues an update operation to push the local version to remote. This is Issue #008.
 func TestConflictResolveLocalWinsQueuesUpdate(t *testing.T) {
@@ -2841,7 +3016,7 @@ default_backend: sqlite-remote
 	}
 
 	// Create a hierarchy task "t1/ct1" - should queue TWO create operations
-	stdout := cli.MustExecute("-y", "Work", "add", "t1/ct1")
+	stdout := cli.MustExecute("-y", "Work", "add", "t1/ct1", "--path")
 	testutil.AssertContains(t, stdout, "Created task")
 
 	// Verify 2 pending operations are queued (parent + child)
@@ -4230,3 +4405,359 @@ func TestFieldTimestampTracking(t *testing.T) {
 		}
 	}
 }
+
+// =============================================================================
+// Archived Lists Are Excluded From Sync
+// =============================================================================
+
+// TestSyncSkipsArchivedListsCLI verifies archived lists are neither pushed nor re-pulled
+func TestSyncSkipsArchivedListsCLI(t *testing.T) {
+	cli, tmpDir := newSyncTestCLI(t)
+	remoteDBPath := filepath.Join(tmpDir, "remote.db")
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	remoteDB, err := sql.Open("sqlite", remoteDBPath)
+	if err != nil {
+		t.Fatalf("failed to open remote database: %v", err)
+	}
+	if err := setupRemoteDBWithBackendID(remoteDB, "list-1", "Project", "task-1", "Remote task", "remote-backend"); err != nil {
+		t.Fatalf("failed to setup remote database: %v", err)
+	}
+	_ = remoteDB.Close()
+
+	configContent := `
+sync:
+  enabled: true
+  local_backend: sqlite
+  auto_sync_after_operation: false
+backends:
+  sqlite:
+    type: sqlite
+    enabled: true
+  remote-backend:
+    type: sqlite
+    enabled: true
+    path: "` + remoteDBPath + `"
+default_backend: remote-backend
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	// Pull the remote list, then archive it locally
+	cli.MustExecute("-y", "sync")
+	testutil.AssertContains(t, cli.MustExecute("-y", "Project"), "Remote task")
+	cli.MustExecute("-y", "list", "archive", "Project")
+
+	// Changes to the archived list stay local
+	cli.MustExecute("-y", "Project", "add", "Archived local task")
+	cli.MustExecute("-y", "sync")
+
+	remoteDB, err = sql.Open("sqlite", remoteDBPath)
+	if err != nil {
+		t.Fatalf("failed to open remote database: %v", err)
+	}
+	defer func() { _ = remoteDB.Close() }()
+	var count int
+	if err := remoteDB.QueryRow("SELECT COUNT(*) FROM tasks WHERE summary = ?", "Archived local task").Scan(&count); err != nil {
+		t.Fatalf("failed to query remote: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("task in archived list should not be pushed to remote, found %d", count)
+	}
+
+	// Pull must not recreate the archived list as an active one
+	testutil.AssertNotContains(t, cli.MustExecute("-y", "list"), "Project")
+	stdout := cli.MustExecute("-y", "list", "--archived")
+	if strings.Count(stdout, "Project") != 1 {
+		t.Errorf("expected exactly one archived Project list, got:\n%s", stdout)
+	}
+}
+
+// TestSyncPushesArchivedListChangesAfterUnarchiveCLI verifies operations queued
+// for an archived list are kept and pushed once the list is unarchived
+func TestSyncPushesArchivedListChangesAfterUnarchiveCLI(t *testing.T) {
+	cli, tmpDir := newSyncTestCLI(t)
+	remoteDBPath := filepath.Join(tmpDir, "remote.db")
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	remoteDB, err := sql.Open("sqlite", remoteDBPath)
+	if err != nil {
+		t.Fatalf("failed to open remote database: %v", err)
+	}
+	if err := setupRemoteDBWithBackendID(remoteDB, "list-1", "Project", "task-1", "Remote task", "remote-backend"); err != nil {
+		t.Fatalf("failed to setup remote database: %v", err)
+	}
+	_ = remoteDB.Close()
+
+	configContent := `
+sync:
+  enabled: true
+  local_backend: sqlite
+  auto_sync_after_operation: false
+backends:
+  sqlite:
+    type: sqlite
+    enabled: true
+  remote-backend:
+    type: sqlite
+    enabled: true
+    path: "` + remoteDBPath + `"
+default_backend: remote-backend
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	// Queue an edit, archive the list before it is synced, then change it while archived
+	cli.MustExecute("-y", "sync")
+	cli.MustExecute("-y", "Project", "update", "Remote task", "-p", "2")
+	cli.MustExecute("-y", "list", "archive", "Project")
+	cli.MustExecute("-y", "Project", "add", "Archived local task")
+	cli.MustExecute("-y", "sync")
+
+	remoteDB, err = sql.Open("sqlite", remoteDBPath)
+	if err != nil {
+		t.Fatalf("failed to open remote database: %v", err)
+	}
+	defer func() { _ = remoteDB.Close() }()
+	remoteState := func() (priority, added int) {
+		t.Helper()
+		if err := remoteDB.QueryRow("SELECT priority FROM tasks WHERE summary = ?", "Remote task").Scan(&priority); err != nil {
+			t.Fatalf("failed to query remote: %v", err)
+		}
+		if err := remoteDB.QueryRow("SELECT COUNT(*) FROM tasks WHERE summary = ?", "Archived local task").Scan(&added); err != nil {
+			t.Fatalf("failed to query remote: %v", err)
+		}
+		return priority, added
+	}
+	if priority, added := remoteState(); priority == 2 || added != 0 {
+		t.Errorf("archived list was pushed: priority %d, %d added tasks", priority, added)
+	}
+	testutil.AssertContains(t, cli.MustExecute("-y", "sync", "queue"), "Remote task")
+
+	// Once unarchived, the kept operations are pushed
+	cli.MustExecute("-y", "list", "unarchive", "Project")
+	cli.MustExecute("-y", "sync")
+	if priority, added := remoteState(); priority != 2 || added != 1 {
+		t.Errorf("after unarchive: remote priority %d and %d added tasks, want 2 and 1", priority, added)
+	}
+}
+
+// TestBackendLogoutClearsCachedData verifies 'backend logout' removes a backend's cached
+// lists and tasks and its queued operations while leaving local sqlite data intact
+func TestBackendLogoutClearsCachedData(t *testing.T) {
+	cli, tmpDir := newSyncTestCLI(t)
+
+	remoteDBPath := filepath.Join(tmpDir, "remote.db")
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	writeConfig := func(defaultBackend string) {
+		configContent := `
+sync:
+  enabled: true
+  local_backend: sqlite
+  offline_mode: offline
+backends:
+  sqlite:
+    type: sqlite
+    enabled: true
+  remote-backend:
+    type: sqlite
+    enabled: true
+    path: "` + remoteDBPath + `"
+default_backend: ` + defaultBackend + `
+`
+		if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+	}
+
+	// Local data in the sqlite backend must survive the logout
+	writeConfig("sqlite")
+	cli.MustExecute("-y", "Personal", "add", "Local task")
+
+	// Cached data for the remote backend, with queued operations
+	writeConfig("remote-backend")
+	cli.MustExecute("-y", "Work", "add", "Cached task")
+	cli.MustExecute("-y", "Work", "add", "Another cached task")
+	cli.MustExecute("-y", "Work", "update", "Another cached task", "-p", "2")
+	// A queued delete whose task is no longer in the cache
+	cli.MustExecute("-y", "Work", "add", "Deleted task")
+	cli.MustExecute("-y", "Work", "delete", "Deleted task")
+	testutil.AssertContains(t, cli.MustExecute("-y", "sync", "queue"), "Cached task")
+
+	stdout := cli.MustExecute("-y", "--json", "backend", "logout", "remote-backend")
+	var result map[string]interface{}
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, stdout)
+	}
+	if result["tasks"] != float64(2) || result["lists"] != float64(1) {
+		t.Errorf("expected 1 list and 2 tasks removed, got %s", stdout)
+	}
+	if result["queued_operations"].(float64) < 2 {
+		t.Errorf("expected queued operations to be removed, got %s", stdout)
+	}
+
+	stdout = cli.MustExecute("-y", "sync", "queue")
+	testutil.AssertNotContains(t, stdout, "Cached task")
+	testutil.AssertNotContains(t, stdout, "Deleted task")
+	testutil.AssertNotContains(t, cli.MustExecute("-y", "list"), "Work")
+
+	// Per-task sync state is gone as well, while the local task's operation is kept
+	stdout = cli.MustExecute("--json", "backend", "sql", "SELECT (SELECT COUNT(*) FROM sync_queue WHERE task_summary != 'Local task') || '/' || (SELECT COUNT(*) FROM sync_metadata WHERE key LIKE 'field_timestamps:%')", "--yes-i-know")
+	testutil.AssertContains(t, stdout, `"0/0"`)
+
+	writeConfig("sqlite")
+	testutil.AssertContains(t, cli.MustExecute("-y", "Personal"), "Local task")
+}
+
+// TestBackendLogoutRefusesLocalBackend verifies 'backend logout' never purges local sqlite data
+func TestBackendLogoutRefusesLocalBackend(t *testing.T) {
+	cli, _ := newSyncTestCLI(t)
+
+	_, stderr := cli.ExecuteAndFail("-y", "backend", "logout", "sqlite")
+	testutil.AssertContains(t, stderr, "cannot log out of the local backend")
+
+	_, stderr = cli.ExecuteAndFail("-y", "backend", "logout", "no-such-backend")
+	testutil.AssertContains(t, stderr, "not configured")
+}
+
+// TestSyncFieldLevelConflictPolicies verifies that sync.conflict_resolution applies
+// per-field policies when a task changed locally and on the remote since the last sync,
+// and only escalates fields with the "manual" policy to the conflicts table
+func TestSyncFieldLevelConflictPolicies(t *testing.T) {
+	cli, tmpDir := newSyncTestCLI(t)
+	remoteDBPath := filepath.Join(tmpDir, "remote.db")
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	writeConfig := func(mode string) {
+		t.Helper()
+		configContent := `
+sync:
+  enabled: true
+  local_backend: sqlite
+  conflict_resolution:
+    status: local_wins
+    description: server_wins
+    summary: manual
+    default: newest_wins
+  offline_mode: ` + mode + `
+  auto_sync_after_operation: false
+backends:
+  sqlite:
+    type: sqlite
+    enabled: true
+  sqlite-remote:
+    type: sqlite
+    enabled: true
+    path: "` + remoteDBPath + `"
+default_backend: sqlite-remote
+`
+		if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+	}
+
+	// Create the task and sync it so both sides share a baseline
+	writeConfig("offline")
+	cli.MustExecute("-y", "Work", "add", "Shared task", "-d", "original")
+	writeConfig("online")
+	cli.MustExecute("-y", "sync")
+
+	// Change status, description and summary locally
+	writeConfig("offline")
+	cli.MustExecute("-y", "Work", "update", "Shared task", "-s", "IN-PROGRESS", "-d", "local notes", "--summary", "Local title")
+
+	// Change description and summary on the remote after the last sync
+	remoteDB, err := sql.Open("sqlite", remoteDBPath)
+	if err != nil {
+		t.Fatalf("failed to open remote db: %v", err)
+	}
+	defer func() { _ = remoteDB.Close() }()
+	future := time.Now().Add(time.Minute).UTC().Format(time.RFC3339Nano)
+	if _, err := remoteDB.Exec(`UPDATE tasks SET summary = 'Remote title', description = 'remote notes', modified = ? WHERE summary = 'Shared task'`, future); err != nil {
+		t.Fatalf("failed to update remote task: %v", err)
+	}
+
+	writeConfig("online")
+	stdout, stderr, exitCode := cli.Execute("-y", "sync")
+	if exitCode != 0 {
+		t.Fatalf("sync failed: stdout=%s stderr=%s", stdout, stderr)
+	}
+	testutil.AssertContains(t, stderr, "Conflict on task")
+
+	var summary, description, status string
+	if err := remoteDB.QueryRow(`SELECT summary, description, status FROM tasks`).Scan(&summary, &description, &status); err != nil {
+		t.Fatalf("failed to read remote task: %v", err)
+	}
+	if status != "IN-PROGRESS" {
+		t.Errorf("status policy local_wins: expected local status pushed, got %q", status)
+	}
+	if description != "remote notes" {
+		t.Errorf("description policy server_wins: expected remote description kept, got %q", description)
+	}
+	if summary != "Remote title" {
+		t.Errorf("summary policy manual: expected remote summary kept until resolved, got %q", summary)
+	}
+
+	// Only the manually resolved field is escalated
+	stdout = cli.MustExecute("-y", "sync", "conflicts")
+	testutil.AssertContains(t, stdout, "Local title")
+}
+
+// TestSyncRunsRulesBeforePush verifies configured rules run on the local cache
+// during sync and their changes are pushed to the remote in the same sync
+func TestSyncRunsRulesBeforePush(t *testing.T) {
+	cli, tmpDir := newSyncTestCLI(t)
+	remoteDBPath := filepath.Join(tmpDir, "remote.db")
+
+	configContent := `
+sync:
+  enabled: true
+  local_backend: sqlite
+  auto_sync_after_operation: false
+backends:
+  sqlite:
+    type: sqlite
+    enabled: true
+  sqlite-remote:
+    type: sqlite
+    enabled: true
+    path: "` + remoteDBPath + `"
+default_backend: sqlite-remote
+rules:
+  - name: escalate
+    when:
+      - {field: due_date, operator: lte, value: "+2d"}
+      - {field: priority, operator: gt, value: 3}
+    then:
+      set_priority: 2
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "config.yaml"), []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	cli.MustExecute("-y", "Work", "add", "Ship release", "--due-date", "tomorrow", "-p", "5")
+
+	stdout, stderr, exitCode := cli.Execute("-y", "sync")
+	if exitCode != 0 {
+		t.Fatalf("sync failed: stdout=%s stderr=%s", stdout, stderr)
+	}
+	testutil.AssertContains(t, stdout, "Rules took 1 action(s) on 'sqlite-remote'")
+
+	remoteDB, err := sql.Open("sqlite", remoteDBPath)
+	if err != nil {
+		t.Fatalf("failed to open remote db: %v", err)
+	}
+	defer func() { _ = remoteDB.Close() }()
+	var priority int
+	if err := remoteDB.QueryRow(`SELECT priority FROM tasks WHERE summary = 'Ship release'`).Scan(&priority); err != nil {
+		t.Fatalf("failed to query remote db: %v", err)
+	}
+	if priority != 2 {
+		t.Errorf("remote priority = %d, want 2 set by the rule", priority)
+	}
+
+	stdout = cli.MustExecute("-y", "sync")
+	testutil.AssertNotContains(t, stdout, "Rules took")
+}
@@ -8,16 +8,22 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"todoat/backend"
+	"todoat/internal/tz"
 )
 
 const (
 	// DefaultBaseURL is the Todoist API v1 base URL
 	DefaultBaseURL = "https://api.todoist.com"
+	// WebAppURL is the address of the Todoist web app
+	WebAppURL = "https://app.todoist.com"
 )
 
 // Config holds Todoist connection settings
@@ -29,6 +35,9 @@ type Config struct {
 	MaxRetries      int
 	RetryDelay      time.Duration
 	EnableRateLimit bool
+	// PriorityMap maps Todoist priorities ("1"-"4", 4 = highest) to todoat
+	// priorities; nil uses DefaultPriorityMap
+	PriorityMap map[string][]int
 }
 
 // ConfigFromEnv creates a Config from environment variables
@@ -40,9 +49,10 @@ func ConfigFromEnv() Config {
 
 // Backend implements backend.TaskManager using Todoist API v1
 type Backend struct {
-	config  Config
-	client  *http.Client
-	baseURL string
+	config     Config
+	client     *http.Client
+	baseURL    string
+	priorities *backend.PriorityMap
 }
 
 // New creates a new Todoist backend
@@ -56,10 +66,19 @@ func New(cfg Config) (*Backend, error) {
 		baseURL = DefaultBaseURL
 	}
 
+	priorities := defaultPriorities
+	if cfg.PriorityMap != nil {
+		var err error
+		if priorities, err = backend.NewPriorityMap(cfg.PriorityMap, priorityValues); err != nil {
+			return nil, err
+		}
+	}
+
 	return &Backend{
-		config:  cfg,
-		client:  createHTTPClient(),
-		baseURL: baseURL,
+		config:     cfg,
+		client:     createHTTPClient(),
+		baseURL:    baseURL,
+		priorities: priorities,
 	}, nil
 }
 
@@ -81,6 +100,11 @@ func (b *Backend) Close() error {
 	return nil
 }
 
+// RawRequest sends an authenticated request to path, relative to the API base URL
+func (b *Backend) RawRequest(ctx context.Context, method, path string) (*http.Response, error) {
+	return b.doRequest(ctx, method, path, nil)
+}
+
 // doRequest performs an authenticated Todoist API request with rate limiting support
 func (b *Backend) doRequest(ctx context.Context, method, path string, body interface{}) (*http.Response, error) {
 	url := b.baseURL + path
@@ -333,6 +357,17 @@ func (b *Backend) PurgeList(ctx context.Context, listID string) error {
 // Task Operations
 // =============================================================================
 
+// TaskURL returns the page of a task in the Todoist web app
+func (b *Backend) TaskURL(listID, taskID string) string {
+	return WebAppURL + "/app/task/" + url.PathEscape(taskID)
+}
+
+// ReadsConcurrently reports that projects can be read in parallel: requests
+// share no state beyond the HTTP client
+func (b *Backend) ReadsConcurrently() bool {
+	return true
+}
+
 // GetTasks returns all tasks in a project, including completed tasks.
 // Active tasks are fetched from the REST API, completed tasks from the Sync API.
 func (b *Backend) GetTasks(ctx context.Context, listID string) ([]backend.Task, error) {
@@ -373,18 +408,17 @@ func (b *Backend) getActiveTasks(ctx context.Context, listID string) ([]backend.
 
 	var response struct {
 		Results []struct {
-			ID          string   `json:"id"`
-			ProjectID   string   `json:"project_id"`
-			Content     string   `json:"content"`
-			Description string   `json:"description"`
-			Checked     bool     `json:"checked"`
-			Priority    int      `json:"priority"`
-			Labels      []string `json:"labels"`
-			ParentID    string   `json:"parent_id"`
-			AddedAt     string   `json:"added_at"`
-			Due         *struct {
-				Date string `json:"date"`
-			} `json:"due"`
+			ID          string      `json:"id"`
+			ProjectID   string      `json:"project_id"`
+			Content     string      `json:"content"`
+			Description string      `json:"description"`
+			Checked     bool        `json:"checked"`
+			Priority    int         `json:"priority"`
+			Labels      []string    `json:"labels"`
+			ParentID    string      `json:"parent_id"`
+			ChildOrder  int         `json:"child_order"`
+			AddedAt     string      `json:"added_at"`
+			Due         *todoistDue `json:"due"`
 		} `json:"results"`
 	}
 
@@ -401,20 +435,16 @@ func (b *Backend) getActiveTasks(ctx context.Context, listID string) ([]backend.
 			Summary:     t.Content,
 			Description: t.Description,
 			Status:      todoistToBackendStatus(t.Checked),
-			Priority:    todoistToInternalPriority(t.Priority),
+			Priority:    b.internalPriority(t.Priority),
 			ListID:      t.ProjectID,
 			ParentID:    t.ParentID,
 			Categories:  labelsToCategories(t.Labels),
+			Position:    t.ChildOrder,
 			Created:     created,
 			Modified:    time.Now(),
 		}
 
-		if t.Due != nil && t.Due.Date != "" {
-			dueDate, err := time.Parse("2006-01-02", t.Due.Date)
-			if err == nil {
-				tasks[i].DueDate = &dueDate
-			}
-		}
+		tasks[i].DueDate = t.Due.Time()
 	}
 
 	return tasks, nil
@@ -486,18 +516,17 @@ func (b *Backend) GetTask(ctx context.Context, listID, taskID string) (*backend.
 	}
 
 	var t struct {
-		ID          string   `json:"id"`
-		ProjectID   string   `json:"project_id"`
-		Content     string   `json:"content"`
-		Description string   `json:"description"`
-		Checked     bool     `json:"checked"`
-		Priority    int      `json:"priority"`
-		Labels      []string `json:"labels"`
-		ParentID    string   `json:"parent_id"`
-		AddedAt     string   `json:"added_at"`
-		Due         *struct {
-			Date string `json:"date"`
-		} `json:"due"`
+		ID          string      `json:"id"`
+		ProjectID   string      `json:"project_id"`
+		Content     string      `json:"content"`
+		Description string      `json:"description"`
+		Checked     bool        `json:"checked"`
+		Priority    int         `json:"priority"`
+		Labels      []string    `json:"labels"`
+		ParentID    string      `json:"parent_id"`
+		ChildOrder  int         `json:"child_order"`
+		AddedAt     string      `json:"added_at"`
+		Due         *todoistDue `json:"due"`
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&t); err != nil {
@@ -511,20 +540,16 @@ func (b *Backend) GetTask(ctx context.Context, listID, taskID string) (*backend.
 		Summary:     t.Content,
 		Description: t.Description,
 		Status:      todoistToBackendStatus(t.Checked),
-		Priority:    todoistToInternalPriority(t.Priority),
+		Priority:    b.internalPriority(t.Priority),
 		ListID:      t.ProjectID,
 		ParentID:    t.ParentID,
 		Categories:  labelsToCategories(t.Labels),
+		Position:    t.ChildOrder,
 		Created:     created,
 		Modified:    time.Now(),
 	}
 
-	if t.Due != nil && t.Due.Date != "" {
-		dueDate, err := time.Parse("2006-01-02", t.Due.Date)
-		if err == nil {
-			task.DueDate = &dueDate
-		}
-	}
+	task.DueDate = t.Due.Time()
 
 	return task, nil
 }
@@ -534,7 +559,7 @@ func (b *Backend) CreateTask(ctx context.Context, listID string, task *backend.T
 	body := map[string]interface{}{
 		"content":    task.Summary,
 		"project_id": listID,
-		"priority":   internalToTodoistPriority(task.Priority),
+		"priority":   b.todoistPriority(task.Priority),
 	}
 
 	if task.Description != "" {
@@ -549,9 +574,7 @@ func (b *Backend) CreateTask(ctx context.Context, listID string, task *backend.T
 		body["parent_id"] = task.ParentID
 	}
 
-	if task.DueDate != nil {
-		body["due_date"] = task.DueDate.Format("2006-01-02")
-	}
+	setTodoistDue(body, task.DueDate)
 
 	resp, err := b.doRequest(ctx, http.MethodPost, "/api/v1/tasks", body)
 	if err != nil {
@@ -579,7 +602,7 @@ func (b *Backend) CreateTask(ctx context.Context, listID string, task *backend.T
 		Summary:     created.Content,
 		Description: task.Description,
 		Status:      backend.StatusNeedsAction,
-		Priority:    todoistToInternalPriority(created.Priority),
+		Priority:    b.internalPriority(created.Priority),
 		ListID:      created.ProjectID,
 		Categories:  task.Categories,
 		Created:     time.Now(),
@@ -592,7 +615,7 @@ func (b *Backend) UpdateTask(ctx context.Context, listID string, task *backend.T
 	// First update the task content/priority/etc
 	body := map[string]interface{}{
 		"content":  task.Summary,
-		"priority": internalToTodoistPriority(task.Priority),
+		"priority": b.todoistPriority(task.Priority),
 	}
 
 	if task.Description != "" {
@@ -603,6 +626,8 @@ func (b *Backend) UpdateTask(ctx context.Context, listID string, task *backend.T
 		body["labels"] = categoriesToLabels(task.Categories)
 	}
 
+	setTodoistDue(body, task.DueDate)
+
 	resp, err := b.doRequest(ctx, http.MethodPost, "/api/v1/tasks/"+task.ID, body)
 	if err != nil {
 		return nil, err
@@ -614,7 +639,7 @@ func (b *Backend) UpdateTask(ctx context.Context, listID string, task *backend.T
 	}
 
 	// Handle status changes separately (Todoist uses close/reopen endpoints)
-	switch task.Status {
+	switch task.Status.Base() {
 	case backend.StatusCompleted:
 		resp, err = b.doRequest(ctx, http.MethodPost, "/api/v1/tasks/"+task.ID+"/close", nil)
 		if err != nil {
@@ -633,6 +658,126 @@ func (b *Backend) UpdateTask(ctx context.Context, listID string, task *backend.T
 	return task, nil
 }
 
+// PatchTask updates only the given fields of a task (backend.FieldPatcher).
+// Fields Todoist does not store are ignored; the status is changed with the
+// close/reopen endpoints only when it is one of the fields.
+func (b *Backend) PatchTask(ctx context.Context, listID string, task *backend.Task, fields []string) (*backend.Task, error) {
+	body := map[string]interface{}{}
+	statusChanged := false
+	for _, field := range fields {
+		switch field {
+		case backend.FieldSummary:
+			body["content"] = task.Summary
+		case backend.FieldDescription:
+			body["description"] = task.Description
+		case backend.FieldPriority:
+			body["priority"] = b.todoistPriority(task.Priority)
+		case backend.FieldCategories:
+			body["labels"] = categoriesToLabels(task.Categories)
+		case backend.FieldDueDate:
+			if task.DueDate == nil {
+				body["due_string"] = "no date"
+			} else {
+				setTodoistDue(body, task.DueDate)
+			}
+		case backend.FieldStatus:
+			statusChanged = true
+		}
+	}
+
+	if len(body) > 0 {
+		resp, err := b.doRequest(ctx, http.MethodPost, "/api/v1/tasks/"+task.ID, body)
+		if err != nil {
+			return nil, err
+		}
+		_ = resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("failed to update task: status %d", resp.StatusCode)
+		}
+	}
+
+	if statusChanged {
+		endpoint := ""
+		switch task.Status.Base() {
+		case backend.StatusCompleted:
+			endpoint = "/close"
+		case backend.StatusNeedsAction:
+			endpoint = "/reopen"
+		}
+		if endpoint != "" {
+			resp, err := b.doRequest(ctx, http.MethodPost, "/api/v1/tasks/"+task.ID+endpoint, nil)
+			if err != nil {
+				return nil, err
+			}
+			_ = resp.Body.Close()
+		}
+	}
+
+	task.Modified = time.Now()
+	return task, nil
+}
+
+// MoveTask places a task after previousID among its siblings (backend.TaskMover).
+// Todoist orders siblings by child_order, so the active siblings are renumbered
+// in their new order with an item_reorder command of the Sync API.
+func (b *Backend) MoveTask(ctx context.Context, listID, taskID, parentID, previousID string) error {
+	active, err := b.getActiveTasks(ctx, listID)
+	if err != nil {
+		return err
+	}
+	var siblings []backend.Task
+	for _, t := range active {
+		if t.ParentID == parentID && t.ID != taskID {
+			siblings = append(siblings, t)
+		}
+	}
+	backend.SortByPosition(siblings)
+
+	// A previous sibling that is not active (e.g. completed) puts the task first
+	at := 0
+	for i, t := range siblings {
+		if t.ID == previousID {
+			at = i + 1
+		}
+	}
+	siblings = slices.Insert(siblings, at, backend.Task{ID: taskID})
+
+	items := make([]map[string]interface{}, len(siblings))
+	for i, t := range siblings {
+		items[i] = map[string]interface{}{"id": t.ID, "child_order": i + 1}
+	}
+	commandID := backend.GenerateID()
+	body := map[string]interface{}{
+		"commands": []map[string]interface{}{{
+			"type": "item_reorder",
+			"uuid": commandID,
+			"args": map[string]interface{}{"items": items},
+		}},
+	}
+	resp, err := b.doRequest(ctx, http.MethodPost, "/api/v1/sync", body)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to reorder tasks: status %d", resp.StatusCode)
+	}
+
+	// Commands fail individually: their status is "ok" or an error object
+	var result struct {
+		SyncStatus map[string]json.RawMessage `json:"sync_status"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return err
+	}
+	if status := result.SyncStatus[commandID]; string(status) != `"ok"` {
+		return fmt.Errorf("failed to reorder tasks: %s", status)
+	}
+	return nil
+}
+
 // DeleteTask removes a task
 func (b *Backend) DeleteTask(ctx context.Context, listID, taskID string) error {
 	resp, err := b.doRequest(ctx, http.MethodDelete, "/api/v1/tasks/"+taskID, nil)
@@ -648,43 +793,116 @@ func (b *Backend) DeleteTask(ctx context.Context, listID, taskID string) error {
 	return nil
 }
 
+// =============================================================================
+// Due Date Conversion Functions
+// =============================================================================
+
+// todoistDue is a Todoist due object: date is always set, datetime only for
+// tasks due at a time of day (UTC with "Z", or floating local time without)
+type todoistDue struct {
+	Date     string `json:"date"`
+	Datetime string `json:"datetime,omitempty"`
+}
+
+// Time converts a due object to a due date: timed dues keep their time, dates
+// are at local midnight. Returns nil for a missing or unparsable due.
+func (d *todoistDue) Time() *time.Time {
+	if d == nil {
+		return nil
+	}
+	if d.Datetime != "" {
+		if t, err := time.Parse(time.RFC3339, d.Datetime); err == nil {
+			return &t
+		}
+		if t, err := time.ParseInLocation("2006-01-02T15:04:05", d.Datetime, tz.Location()); err == nil {
+			return &t
+		}
+	}
+	if d.Date == "" {
+		return nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", d.Date, tz.Location())
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+// setTodoistDue adds a due date to a task request body: due_datetime in UTC for
+// due dates with a time of day, due_date otherwise
+func setTodoistDue(body map[string]interface{}, due *time.Time) {
+	if due == nil {
+		return
+	}
+	if due.Hour() != 0 || due.Minute() != 0 || due.Second() != 0 {
+		body["due_datetime"] = due.UTC().Format(time.RFC3339)
+		return
+	}
+	body["due_date"] = due.Format("2006-01-02")
+}
+
 // =============================================================================
 // Priority Conversion Functions
 // =============================================================================
 
-// internalToTodoistPriority converts internal priority (1-9, 1=highest) to Todoist (1-4, 4=highest)
-// Priority 0 (unset) maps to Todoist priority 1 (lowest/no priority) - fixes issue #011
+// DefaultPriorityMap maps Todoist priorities (4 = highest, 1 = none) to todoat
+// priorities. Unset (0) is sent as no priority - fixes issue #011.
+var DefaultPriorityMap = map[string][]int{
+	"4": {1, 2},
+	"3": {3, 4},
+	"2": {5, 6},
+	"1": {7, 8, 9, 0},
+}
+
+// priorityValues are the native Todoist priorities
+var priorityValues = []string{"1", "2", "3", "4"}
+
+var defaultPriorities = backend.MustPriorityMap(DefaultPriorityMap, priorityValues)
+
+// PriorityMap returns the mapping between todoat and Todoist priorities
+func (b *Backend) PriorityMap() *backend.PriorityMap {
+	return b.priorities
+}
+
+// todoistPriority converts a todoat priority (0-9, 1=highest) to Todoist (1-4, 4=highest)
+func (b *Backend) todoistPriority(internal int) int {
+	return toTodoistPriority(b.priorities, internal)
+}
+
+// internalPriority converts a Todoist priority (1-4, 4=highest) to todoat (1-9, 1=highest)
+func (b *Backend) internalPriority(todoist int) int {
+	return fromTodoistPriority(b.priorities, todoist)
+}
+
+// internalToTodoistPriority converts a todoat priority to Todoist using DefaultPriorityMap
 func internalToTodoistPriority(internal int) int {
-	// Internal: 1-9 where 1 is highest, 0 = unset/default
-	// Todoist: 1-4 where 4 is highest, 1 = no priority
-	switch {
-	case internal <= 0:
-		return 1 // Unset (0) = no priority (Todoist 1)
-	case internal <= 2:
-		return 4 // Highest
-	case internal <= 4:
-		return 3
-	case internal <= 6:
-		return 2
-	default:
-		return 1 // Lowest
-	}
-}
-
-// todoistToInternalPriority converts Todoist priority (1-4, 4=highest) to internal (1-9, 1=highest)
+	return toTodoistPriority(defaultPriorities, internal)
+}
+
+// todoistToInternalPriority converts a Todoist priority to todoat using DefaultPriorityMap
 func todoistToInternalPriority(todoist int) int {
-	// Todoist: 1-4 where 4 is highest
-	// Internal: 1-9 where 1 is highest
-	switch todoist {
-	case 4:
-		return 1 // Highest
-	case 3:
-		return 3
-	case 2:
-		return 5
-	default:
-		return 7 // Lowest
+	return fromTodoistPriority(defaultPriorities, todoist)
+}
+
+func toTodoistPriority(m *backend.PriorityMap, internal int) int {
+	native, err := strconv.Atoi(m.ToNative(internal))
+	if err != nil {
+		return 1
+	}
+	return native
+}
+
+// fromTodoistPriority reads a Todoist priority; values missing from m fall
+// back to the default mapping
+func fromTodoistPriority(m *backend.PriorityMap, todoist int) int {
+	if p, ok := m.FromNative(strconv.Itoa(todoist)); ok {
+		return p
+	}
+	if p, ok := defaultPriorities.FromNative(strconv.Itoa(todoist)); ok {
+		return p
 	}
+	p, _ := defaultPriorities.FromNative("1")
+	return p
 }
 
 // =============================================================================
@@ -734,6 +952,7 @@ func (b *Backend) DetectionInfo() string {
 
 // Verify interface compliance at compile time
 var _ backend.TaskManager = (*Backend)(nil)
+var _ backend.TaskLinker = (*Backend)(nil)
 var _ backend.DetectableBackend = (*Backend)(nil)
 
 // init registers the todoist backend as detectable
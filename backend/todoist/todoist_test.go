@@ -49,6 +49,7 @@ type todoistTask struct {
 	Labels      []string `json:"labels,omitempty"`
 	ParentID    string   `json:"parent_id,omitempty"`
 	Order       int      `json:"order"`
+	ChildOrder  int      `json:"child_order"`
 	AddedAt     string   `json:"added_at"`
 }
 
@@ -141,6 +142,8 @@ func (m *mockTodoistServer) handler(w http.ResponseWriter, r *http.Request) {
 		m.handleGetTasks(w, r)
 	case path == "/api/v1/tasks" && r.Method == http.MethodPost:
 		m.handleCreateTask(w, r)
+	case path == "/api/v1/sync" && r.Method == http.MethodPost:
+		m.handleSync(w, r)
 	case path == "/api/v1/tasks/completed" && r.Method == http.MethodGet:
 		m.handleGetCompletedTasks(w, r)
 	case strings.HasPrefix(path, "/api/v1/tasks/") && strings.HasSuffix(path, "/close") && r.Method == http.MethodPost:
@@ -228,6 +231,48 @@ func (m *mockTodoistServer) handleDeleteProject(w http.ResponseWriter, r *http.R
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// handleSync runs item_reorder commands, the only sync command the backend sends
+func (m *mockTodoistServer) handleSync(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var req struct {
+		Commands []struct {
+			Type string `json:"type"`
+			UUID string `json:"uuid"`
+			Args struct {
+				Items []struct {
+					ID         string `json:"id"`
+					ChildOrder int    `json:"child_order"`
+				} `json:"items"`
+			} `json:"args"`
+		} `json:"commands"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	status := make(map[string]interface{})
+	for _, cmd := range req.Commands {
+		if cmd.Type != "item_reorder" {
+			status[cmd.UUID] = map[string]string{"error": "unsupported command"}
+			continue
+		}
+		status[cmd.UUID] = "ok"
+		for _, item := range cmd.Args.Items {
+			if task, ok := m.tasks[item.ID]; ok {
+				task.ChildOrder = item.ChildOrder
+			} else {
+				status[cmd.UUID] = map[string]string{"error": "item not found"}
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"sync_status": status})
+}
+
 func (m *mockTodoistServer) handleGetTasks(w http.ResponseWriter, r *http.Request) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -511,6 +556,50 @@ func TestTodoistGetTasks(t *testing.T) {
 	}
 }
 
+// TestTodoistMoveTask verifies reorders are sent as an item_reorder command and read back as positions
+func TestTodoistMoveTask(t *testing.T) {
+	server := newMockTodoistServer("test-api-token")
+	defer server.Close()
+
+	server.AddProject("proj-1", "MyProject")
+	server.AddTask("task-1", "proj-1", "Buy groceries", 1, nil, "")
+	server.AddTask("task-2", "proj-1", "Review PR", 1, nil, "")
+	server.AddTask("task-3", "proj-1", "Call Bob", 1, nil, "")
+
+	be, err := New(Config{
+		APIToken: "test-api-token",
+		BaseURL:  server.URL(),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create backend: %v", err)
+	}
+	defer func() { _ = be.Close() }()
+
+	ctx := context.Background()
+	if err := be.MoveTask(ctx, "proj-1", "task-2", "", ""); err != nil {
+		t.Fatalf("MoveTask failed: %v", err)
+	}
+	if err := be.MoveTask(ctx, "proj-1", "task-1", "", "task-3"); err != nil {
+		t.Fatalf("MoveTask failed: %v", err)
+	}
+	if err := be.MoveTask(ctx, "proj-1", "task-3", "", "task-2"); err != nil {
+		t.Fatalf("MoveTask failed: %v", err)
+	}
+
+	tasks, err := be.GetTasks(ctx, "proj-1")
+	if err != nil {
+		t.Fatalf("GetTasks failed: %v", err)
+	}
+	backend.SortByPosition(tasks)
+	var order []string
+	for _, task := range tasks {
+		order = append(order, task.ID)
+	}
+	if want := []string{"task-2", "task-3", "task-1"}; strings.Join(order, ",") != strings.Join(want, ",") {
+		t.Errorf("order after moves = %v, want %v", order, want)
+	}
+}
+
 // TestTodoistAddTask - todoat --backend=todoist MyProject add "Task" creates task via API
 func TestTodoistAddTask(t *testing.T) {
 	server := newMockTodoistServer("test-api-token")
@@ -648,6 +737,45 @@ func TestTodoistUpdateTask(t *testing.T) {
 	}
 }
 
+// TestTodoistPatchTask - sync pushes only the changed fields of a task
+func TestTodoistPatchTask(t *testing.T) {
+	server := newMockTodoistServer("test-api-token")
+	defer server.Close()
+
+	server.AddProject("proj-1", "MyProject")
+	server.AddTask("task-1", "proj-1", "Existing Task", 2, []string{"work"}, "")
+
+	be, err := New(Config{
+		APIToken: "test-api-token",
+		BaseURL:  server.URL(),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create backend: %v", err)
+	}
+	defer func() { _ = be.Close() }()
+
+	ctx := context.Background()
+	task := &backend.Task{ID: "task-1", Summary: "Renamed Task", Priority: 9, Status: backend.StatusNeedsAction}
+	if _, err := backend.PatchTask(ctx, be, "proj-1", task, []string{backend.FieldSummary}); err != nil {
+		t.Fatalf("PatchTask failed: %v", err)
+	}
+
+	server.mu.Lock()
+	stored := *server.tasks["task-1"]
+	server.mu.Unlock()
+	if stored.Content != "Renamed Task" {
+		t.Errorf("Expected content 'Renamed Task', got %q", stored.Content)
+	}
+	if stored.Priority != 2 || len(stored.Labels) != 1 {
+		t.Errorf("Unchanged fields were overwritten: priority=%d labels=%v", stored.Priority, stored.Labels)
+	}
+	for _, req := range server.GetRequestLog() {
+		if strings.HasSuffix(req, "/reopen") || strings.HasSuffix(req, "/close") {
+			t.Errorf("Status was not changed but %s was requested", req)
+		}
+	}
+}
+
 // TestTodoistDeleteTask - todoat --backend=todoist MyProject delete "Task" removes task
 func TestTodoistDeleteTask(t *testing.T) {
 	server := newMockTodoistServer("test-api-token")
@@ -935,6 +1063,27 @@ func TestNewBackend(t *testing.T) {
 	}
 }
 
+// TestTodoistCustomPriorityMap verifies a configured priority_map replaces the default
+func TestTodoistCustomPriorityMap(t *testing.T) {
+	be, err := New(Config{APIToken: "t", PriorityMap: map[string][]int{"4": {1}, "3": {2}, "2": {3}, "1": {5, 0, 4, 6, 7, 8, 9}}})
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	if got := be.todoistPriority(2); got != 3 {
+		t.Errorf("todoistPriority(2) = %d, want 3", got)
+	}
+	if got := be.internalPriority(1); got != 5 {
+		t.Errorf("internalPriority(1) = %d, want 5", got)
+	}
+	if !be.PriorityMap().Same(4, 9) {
+		t.Error("4 and 9 should share Todoist priority 1")
+	}
+
+	if _, err := New(Config{APIToken: "t", PriorityMap: map[string][]int{"5": {0, 1, 2, 3, 4, 5, 6, 7, 8, 9}}}); err == nil {
+		t.Error("New should reject Todoist priority 5")
+	}
+}
+
 func TestBackendImplementsInterface(t *testing.T) {
 	var _ backend.TaskManager = (*Backend)(nil)
 }
@@ -1343,3 +1492,31 @@ func TestTodoistFindCompletedTaskBySummaryCLI(t *testing.T) {
 		t.Errorf("Bug #001: Completed task 'Test from todoat' not found in GetTasks - this prevents deletion by summary")
 	}
 }
+
+// TestTodoistDueTimes verifies due dates and due times convert both ways
+func TestTodoistDueTimes(t *testing.T) {
+	due := (&todoistDue{Date: "2026-06-01", Datetime: "2026-06-01T15:00:00Z"}).Time()
+	if due == nil || !due.Equal(time.Date(2026, 6, 1, 15, 0, 0, 0, time.UTC)) {
+		t.Errorf("timed due = %v, want 2026-06-01 15:00 UTC", due)
+	}
+	due = (&todoistDue{Date: "2026-06-01"}).Time()
+	if due == nil || due.Hour() != 0 || due.Day() != 1 || due.Location() != time.Local {
+		t.Errorf("date due = %v, want local midnight on June 1", due)
+	}
+	if (*todoistDue)(nil).Time() != nil {
+		t.Error("missing due should convert to nil")
+	}
+
+	body := map[string]interface{}{}
+	timed := time.Date(2026, 6, 1, 17, 0, 0, 0, time.FixedZone("CEST", 2*3600))
+	setTodoistDue(body, &timed)
+	if body["due_datetime"] != "2026-06-01T15:00:00Z" || body["due_date"] != nil {
+		t.Errorf("timed body = %v", body)
+	}
+	body = map[string]interface{}{}
+	date := time.Date(2026, 6, 1, 0, 0, 0, 0, time.Local)
+	setTodoistDue(body, &date)
+	if body["due_date"] != "2026-06-01" || body["due_datetime"] != nil {
+		t.Errorf("date body = %v", body)
+	}
+}
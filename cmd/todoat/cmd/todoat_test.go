@@ -5,16 +5,29 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	_ "modernc.org/sqlite"
+	"todoat/backend"
+	"todoat/backend/sqlite"
 	"todoat/internal/config"
 	"todoat/internal/credentials"
+	"todoat/internal/daemon"
+	"todoat/internal/features"
+	"todoat/internal/notification"
+	"todoat/internal/trello"
+	"todoat/internal/utils"
 )
 
 // =============================================================================
@@ -4252,6 +4265,213 @@ func TestAnalyticsTrackingDisabled(t *testing.T) {
 	}
 }
 
+// TestAnalyticsReportCommand verifies task lifecycle events feed 'todoat analytics report'
+func TestAnalyticsReportCommand(t *testing.T) {
+	tmpDir := t.TempDir()
+	analyticsPath := filepath.Join(tmpDir, "analytics.db")
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	dbPath := filepath.Join(tmpDir, "tasks.db")
+
+	configContent := `default_backend: sqlite
+analytics:
+  enabled: true
+  retention_days: 365
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	cfg := &Config{
+		DBPath:        dbPath,
+		ConfigPath:    configPath,
+		AnalyticsPath: analyticsPath,
+		NoPrompt:      true,
+	}
+
+	for _, args := range [][]string{
+		{"Work", "add", "Write report", "--tags", "docs"},
+		{"Work", "add", "Review PR"},
+		{"Home", "add", "Buy milk", "--tags", "errand"},
+		{"Work", "complete", "Write report"},
+		{"Home", "delete", "Buy milk"},
+	} {
+		var stdout, stderr bytes.Buffer
+		if exitCode := Execute(args, &stdout, &stderr, cfg); exitCode != 0 {
+			t.Fatalf("%v: expected exit code 0, got %d: stderr=%s", args, exitCode, stderr.String())
+		}
+	}
+
+	var stdout, stderr bytes.Buffer
+	exitCode := Execute([]string{"analytics", "report", "--period", "week", "--json"}, &stdout, &stderr, cfg)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d: stderr=%s", exitCode, stderr.String())
+	}
+
+	var report struct {
+		Period    string `json:"period"`
+		Created   int    `json:"created"`
+		Completed int    `json:"completed"`
+		Deleted   int    `json:"deleted"`
+		ByList    []struct {
+			Name      string `json:"name"`
+			Created   int    `json:"created"`
+			Completed int    `json:"completed"`
+		} `json:"by_list"`
+		ByTag []struct {
+			Name string `json:"name"`
+		} `json:"by_tag"`
+		BurnDown []struct {
+			Open int `json:"open"`
+		} `json:"burn_down"`
+		Result string `json:"result"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("expected valid JSON output, got: %s, error: %v", stdout.String(), err)
+	}
+
+	if report.Period != "week" || report.Result != ResultInfoOnly {
+		t.Errorf("unexpected period/result: %s/%s", report.Period, report.Result)
+	}
+	if report.Created != 3 || report.Completed != 1 || report.Deleted != 1 {
+		t.Errorf("expected created=3 completed=1 deleted=1, got created=%d completed=%d deleted=%d",
+			report.Created, report.Completed, report.Deleted)
+	}
+	if len(report.ByList) == 0 || report.ByList[0].Name != "Work" || report.ByList[0].Completed != 1 {
+		t.Errorf("expected Work list first with 1 completion, got %+v", report.ByList)
+	}
+	if len(report.ByTag) != 2 {
+		t.Errorf("expected 2 tags in breakdown, got %+v", report.ByTag)
+	}
+	if len(report.BurnDown) != 7 || report.BurnDown[6].Open != 1 {
+		t.Errorf("expected 7 burn-down points ending with 1 open task, got %+v", report.BurnDown)
+	}
+
+	// Human-readable output includes the burn-down chart
+	stdout.Reset()
+	exitCode = Execute([]string{"analytics", "report", "--period", "week"}, &stdout, &stderr, cfg)
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d: stderr=%s", exitCode, stderr.String())
+	}
+	for _, want := range []string{"Productivity Report", "Completed per Week", "By List", "Burn-down"} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("expected report output to contain %q, got:\n%s", want, stdout.String())
+		}
+	}
+
+	// Invalid period is rejected
+	stdout.Reset()
+	stderr.Reset()
+	if exitCode := Execute([]string{"analytics", "report", "--period", "decade"}, &stdout, &stderr, cfg); exitCode == 0 {
+		t.Error("expected non-zero exit code for invalid period")
+	}
+}
+
+// TestTaskEventsRecordedOutsideCLIHandlers verifies that move, import, daemon and TUI
+// mutations write task lifecycle events, not only the add/update/complete/delete commands
+func TestTaskEventsRecordedOutsideCLIHandlers(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	if err := os.WriteFile(configPath, []byte("default_backend: sqlite\nanalytics:\n  enabled: true\n"), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	cfg := &Config{
+		DBPath:        filepath.Join(tmpDir, "tasks.db"),
+		ConfigPath:    configPath,
+		AnalyticsPath: filepath.Join(tmpDir, "analytics.db"),
+		CachePath:     filepath.Join(tmpDir, "cache", "lists.json"),
+		NoPrompt:      true,
+	}
+	execute := func(args ...string) {
+		t.Helper()
+		var stdout, stderr bytes.Buffer
+		if exitCode := Execute(args, &stdout, &stderr, cfg); exitCode != 0 {
+			t.Fatalf("%v: expected exit code 0, got %d: stderr=%s", args, exitCode, stderr.String())
+		}
+	}
+
+	importPath := filepath.Join(tmpDir, "import.json")
+	importData := `{"list_name": "Imported", "tasks": [{"id": "1", "summary": "Old work", "status": "COMPLETED"}, {"id": "2", "summary": "New work"}]}`
+	if err := os.WriteFile(importPath, []byte(importData), 0644); err != nil {
+		t.Fatalf("failed to write import file: %v", err)
+	}
+	execute("list", "import", importPath)
+	execute("Work", "add", "Move me")
+	execute("Work", "move", "Move me", "--to", "Done", "--create")
+
+	// Daemon callback completion
+	ctx := context.Background()
+	be, err := getBackend(cfg)
+	if err != nil {
+		t.Fatalf("failed to open backend: %v", err)
+	}
+	imported, _ := be.GetListByName(ctx, "Imported")
+	tasks, _ := be.GetTasks(ctx, imported.ID)
+	var newWork backend.Task
+	for _, task := range tasks {
+		if task.Summary == "New work" {
+			newWork = task
+		}
+	}
+	_ = be.Close()
+	if err := applyDaemonTaskAction(cfg, newWork.ID, daemon.TaskActionComplete, 0); err != nil {
+		t.Fatalf("daemon complete failed: %v", err)
+	}
+
+	// TUI edits
+	tuiCfg := *cfg
+	tuiCfg.analyticsTracker = initAnalyticsTracker(cfg)
+	be, err = getBackend(&tuiCfg)
+	if err != nil {
+		t.Fatalf("failed to open backend: %v", err)
+	}
+	adapter := &tuiBackendAdapter{TaskManager: be, cfg: &tuiCfg}
+	done, _ := be.GetListByName(ctx, "Done")
+	created, err := adapter.CreateTask(ctx, done.ID, &backend.Task{Summary: "From TUI", Status: backend.StatusNeedsAction})
+	if err != nil {
+		t.Fatalf("TUI create failed: %v", err)
+	}
+	created.Status = backend.StatusCompleted
+	if _, err := adapter.UpdateTask(ctx, done.ID, created); err != nil {
+		t.Fatalf("TUI update failed: %v", err)
+	}
+	_ = be.Close()
+	_ = tuiCfg.analyticsTracker.Close()
+
+	db, err := sql.Open("sqlite", cfg.AnalyticsPath)
+	if err != nil {
+		t.Fatalf("failed to open analytics db: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+	counts := map[string]int{}
+	rows, err := db.Query("SELECT event_type || ':' || COALESCE(list_name, ''), COUNT(*) FROM task_events GROUP BY 1")
+	if err != nil {
+		t.Fatalf("failed to query task events: %v", err)
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var key string
+		var count int
+		if err := rows.Scan(&key, &count); err != nil {
+			t.Fatal(err)
+		}
+		counts[key] = count
+	}
+
+	expected := map[string]int{
+		"created:Imported":   2, // list import
+		"completed:Imported": 2, // imported as done, then completed by the daemon
+		"created:Work":       1,
+		"deleted:Work":       1, // moved away
+		"created:Done":       2, // moved in, then added in the TUI
+		"completed:Done":     1, // completed in the TUI
+	}
+	for key, want := range expected {
+		if counts[key] != want {
+			t.Errorf("expected %d %s events, got %d (all: %v)", want, key, counts[key], counts)
+		}
+	}
+}
+
 // TestIssue011BackendDataIsolation verifies that different backends have isolated
 // data in the SQLite cache when sync is enabled.
 // Issue #011: SQLite cache mixes data between backends because createSyncFallbackBackend()
@@ -4859,3 +5079,1741 @@ func TestOutputFormatJSONFlagOverridesConfig(t *testing.T) {
 		t.Fatalf("--json flag should produce JSON output regardless of config, got: %s, error: %v", output, err)
 	}
 }
+
+// TestMatchesVirtualList verifies the due-date rules behind @overdue, @today, @week and @no-date
+func TestMatchesVirtualList(t *testing.T) {
+	now := time.Date(2026, 3, 10, 15, 0, 0, 0, time.Local)
+	due := func(offset int) *time.Time {
+		d := time.Date(2026, 3, 10+offset, 0, 0, 0, 0, time.Local)
+		return &d
+	}
+
+	tests := []struct {
+		name string
+		task backend.Task
+		want map[string]bool
+	}{
+		{"yesterday", backend.Task{DueDate: due(-1)}, map[string]bool{VirtualListOverdue: true}},
+		{"today", backend.Task{DueDate: due(0)}, map[string]bool{VirtualListToday: true, VirtualListWeek: true}},
+		{"in 6 days", backend.Task{DueDate: due(6)}, map[string]bool{VirtualListWeek: true}},
+		{"in 7 days", backend.Task{DueDate: due(7)}, map[string]bool{}},
+		{"no date", backend.Task{}, map[string]bool{VirtualListNoDate: true}},
+		{"completed overdue", backend.Task{DueDate: due(-1), Status: backend.StatusCompleted}, map[string]bool{}},
+		{"cancelled no date", backend.Task{Status: backend.StatusCancelled}, map[string]bool{}},
+	}
+
+	for _, tt := range tests {
+		for _, name := range virtualListNames {
+			if got := matchesVirtualList(name, &tt.task, now); got != tt.want[name] {
+				t.Errorf("%s: matchesVirtualList(%s) = %v, want %v", tt.name, name, got, tt.want[name])
+			}
+		}
+	}
+}
+
+// TestTUIAdapterVirtualLists verifies the TUI shows virtual lists that aggregate tasks from all lists
+func TestTUIAdapterVirtualLists(t *testing.T) {
+	ctx := context.Background()
+	mock := NewMockBackend("mock", "")
+	work, _ := mock.CreateList(ctx, "Work")
+	home, _ := mock.CreateList(ctx, "Home")
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	_, _ = mock.CreateTask(ctx, work.ID, &backend.Task{Summary: "Work today", DueDate: &today})
+	_, _ = mock.CreateTask(ctx, home.ID, &backend.Task{Summary: "Home today", DueDate: &today})
+	_, _ = mock.CreateTask(ctx, home.ID, &backend.Task{Summary: "Undated"})
+
+	adapter := &tuiBackendAdapter{TaskManager: newVirtualListBackend(mock)}
+
+	lists, err := adapter.GetLists(ctx)
+	if err != nil {
+		t.Fatalf("GetLists() error = %v", err)
+	}
+	if len(lists) != 2+len(virtualListNames) || lists[2].Name != VirtualListOverdue {
+		t.Fatalf("expected regular lists followed by virtual lists, got %+v", lists)
+	}
+
+	tasks, err := adapter.GetTasks(ctx, VirtualListToday)
+	if err != nil {
+		t.Fatalf("GetTasks() error = %v", err)
+	}
+	if len(tasks) != 2 {
+		t.Fatalf("expected 2 tasks due today across lists, got %d", len(tasks))
+	}
+
+	if _, err := adapter.CreateTask(ctx, VirtualListToday, &backend.Task{Summary: "New"}); err == nil {
+		t.Error("expected error creating a task in a virtual list")
+	}
+
+	// Deleting through a virtual list removes the task from its real list
+	var homeTaskID string
+	for _, task := range tasks {
+		if task.Summary == "Home today" {
+			homeTaskID = task.ID
+		}
+	}
+	if err := adapter.DeleteTask(ctx, VirtualListToday, homeTaskID); err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+	homeTasks, _ := mock.GetTasks(ctx, home.ID)
+	if len(homeTasks) != 1 || homeTasks[0].Summary != "Undated" {
+		t.Errorf("expected only 'Undated' left in Home, got %+v", homeTasks)
+	}
+}
+
+// TestDaemonPathsPerWorkspace verifies daemon socket and PID paths are derived from the active database
+func TestDaemonPathsPerWorkspace(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+
+	defaultCfg := &Config{ConfigPath: configPath}
+	if got := getDaemonSocketPath(defaultCfg); got != daemon.GetSocketPath() {
+		t.Errorf("default database should keep the default socket path, got %s", got)
+	}
+
+	workCfg := &Config{ConfigPath: configPath, DBPath: filepath.Join(t.TempDir(), "work.db")}
+	personalCfg := &Config{ConfigPath: configPath, DBPath: filepath.Join(t.TempDir(), "personal.db")}
+	if getDaemonSocketPath(workCfg) == getDaemonSocketPath(personalCfg) {
+		t.Error("different databases should use different daemon sockets")
+	}
+	if getDaemonPIDPath(workCfg) == getDaemonPIDPath(defaultCfg) {
+		t.Error("a non-default database should use its own daemon PID file")
+	}
+
+	// Explicit overrides win over workspace derivation
+	explicitCfg := &Config{DBPath: workCfg.DBPath, DaemonSocketPath: "/tmp/custom.sock"}
+	if got := getDaemonSocketPath(explicitCfg); got != "/tmp/custom.sock" {
+		t.Errorf("expected explicit socket path, got %s", got)
+	}
+}
+
+// failingImportBackend fails CreateTask for selected summaries, and optionally
+// cancels the import context after a number of successful creates
+type failingImportBackend struct {
+	*MockBackend
+	fail        map[string]bool
+	cancelAfter int
+	cancel      context.CancelFunc
+	crashAfter  int
+	creates     int
+}
+
+// errImportCrash is the panic value used to simulate a process dying mid-import
+var errImportCrash = errors.New("simulated crash")
+
+func (b *failingImportBackend) CreateTask(ctx context.Context, listID string, task *backend.Task) (*backend.Task, error) {
+	if b.fail[task.Summary] {
+		return nil, fmt.Errorf("rejected by server")
+	}
+	b.creates++
+	if b.cancel != nil && b.creates == b.cancelAfter {
+		defer b.cancel()
+	}
+	created, err := b.MockBackend.CreateTask(ctx, listID, task)
+	if b.crashAfter > 0 && b.creates == b.crashAfter {
+		panic(errImportCrash)
+	}
+	return created, err
+}
+
+// TestTransferRollbackRemovesCreatedList verifies a failed move deletes the copies made
+// so far and the target list created for it, and leaves the source untouched
+func TestTransferRollbackRemovesCreatedList(t *testing.T) {
+	ctx := context.Background()
+	cfg := &Config{CachePath: filepath.Join(t.TempDir(), "cache", "lists.json")}
+
+	mock := NewMockBackend("mock", "")
+	source, _ := mock.CreateList(ctx, "Source")
+	parent, _ := mock.CreateTask(ctx, source.ID, &backend.Task{Summary: "Release"})
+	_, _ = mock.CreateTask(ctx, source.ID, &backend.Task{Summary: "Changelog", ParentID: parent.ID})
+	be := &failingImportBackend{MockBackend: mock, fail: map[string]bool{"Changelog": true}}
+
+	var stdout bytes.Buffer
+	err := doTransfer(ctx, be, source, parent, "Target", "", true, true, cfg, &stdout, false)
+	if err == nil || !strings.Contains(err.Error(), "rejected by server") {
+		t.Fatalf("expected transfer to fail, got %v", err)
+	}
+
+	if target, _ := mock.GetListByName(ctx, "Target"); target != nil {
+		t.Errorf("target list created for the failed move should be removed")
+	}
+	if tasks, _ := mock.GetTasks(ctx, source.ID); len(tasks) != 2 {
+		t.Errorf("expected source to keep its 2 tasks, got %d", len(tasks))
+	}
+}
+
+// writeImportFile writes a JSON import file with a parent task and the given child summaries
+func writeImportFile(t *testing.T, dir string, summaries ...string) string {
+	t.Helper()
+	tasks := []map[string]string{{"id": "parent", "summary": "Parent"}}
+	for i, summary := range summaries {
+		tasks = append(tasks, map[string]string{"id": fmt.Sprintf("t%d", i), "summary": summary, "parent_id": "parent"})
+	}
+	data, _ := json.Marshal(map[string]interface{}{"list_name": "Imported", "tasks": tasks})
+	path := filepath.Join(dir, "import.json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("failed to write import file: %v", err)
+	}
+	return path
+}
+
+// TestListImportPartialFailureReportAndResume verifies failed rows are reported with reasons
+// and that re-running the import only creates the remaining rows
+func TestListImportPartialFailureReportAndResume(t *testing.T) {
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	cfg := &Config{CachePath: filepath.Join(tmpDir, "cache", "lists.json")}
+	inputPath := writeImportFile(t, tmpDir, "Good task", "Bad task")
+	reportPath := filepath.Join(tmpDir, "report.json")
+
+	mock := NewMockBackend("mock", "")
+	be := &failingImportBackend{MockBackend: mock, fail: map[string]bool{"Bad task": true}}
+
+	var stdout bytes.Buffer
+	err := doListImport(ctx, be, inputPath, "", csvOptions{}, cfg, &stdout, false, false, reportPath, false, 0, importMergeOptions{})
+	if err == nil || !strings.Contains(err.Error(), "1 tasks failed") {
+		t.Fatalf("expected partial failure error, got %v", err)
+	}
+	if !strings.Contains(stdout.String(), "Failed row 3 'Bad task': rejected by server") {
+		t.Errorf("expected failed row in output, got:\n%s", stdout.String())
+	}
+
+	var report importReport
+	data, _ := os.ReadFile(reportPath)
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("invalid report: %v", err)
+	}
+	if len(report.Created) != 2 || len(report.Failed) != 1 || report.Failed[0].Reason != "rejected by server" || report.StateFile == "" {
+		t.Errorf("unexpected report: %+v", report)
+	}
+
+	// Re-run once the server accepts the row: only the failed row is created
+	be.fail = nil
+	stdout.Reset()
+	if err := doListImport(ctx, be, inputPath, "", csvOptions{}, cfg, &stdout, true, false, "", false, 0, importMergeOptions{}); err != nil {
+		t.Fatalf("resumed import failed: %v", err)
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+	if !report.Resumed || report.Skipped != 2 || len(report.Created) != 1 || report.TaskCount != 3 {
+		t.Errorf("unexpected resumed report: %+v", report)
+	}
+
+	list, _ := mock.GetListByName(ctx, "Imported")
+	tasks, _ := mock.GetTasks(ctx, list.ID)
+	if len(tasks) != 3 {
+		t.Fatalf("expected 3 tasks without duplicates, got %d", len(tasks))
+	}
+	for _, task := range tasks {
+		if task.Summary != "Parent" && task.ParentID == "" {
+			t.Errorf("task %q should have its parent set", task.Summary)
+		}
+	}
+	if _, err := os.Stat(importStatePath(cfg, inputPath)); !os.IsNotExist(err) {
+		t.Errorf("import state should be removed after a complete import")
+	}
+}
+
+// TestListImportInterruptRollsBackChunk verifies an interrupted import removes the tasks of
+// the unfinished chunk, so resuming does not create duplicates
+func TestListImportInterruptRollsBackChunk(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := &Config{CachePath: filepath.Join(tmpDir, "cache", "lists.json")}
+	summaries := make([]string, importChunkSize+10)
+	for i := range summaries {
+		summaries[i] = fmt.Sprintf("Task %d", i)
+	}
+	inputPath := writeImportFile(t, tmpDir, summaries...)
+
+	mock := NewMockBackend("mock", "")
+	ctx, cancel := context.WithCancel(context.Background())
+	be := &failingImportBackend{MockBackend: mock, cancelAfter: importChunkSize + 5, cancel: cancel}
+
+	var stdout bytes.Buffer
+	err := doListImport(ctx, be, inputPath, "", csvOptions{}, cfg, &stdout, false, false, "", false, 0, importMergeOptions{})
+	if err == nil || !strings.Contains(err.Error(), "interrupted") {
+		t.Fatalf("expected interrupted error, got %v", err)
+	}
+	list, _ := mock.GetListByName(context.Background(), "Imported")
+	tasks, _ := mock.GetTasks(context.Background(), list.ID)
+	if len(tasks) != importChunkSize {
+		t.Fatalf("expected only the first committed chunk (%d tasks), got %d", importChunkSize, len(tasks))
+	}
+
+	stdout.Reset()
+	if err := doListImport(context.Background(), mock, inputPath, "", csvOptions{}, cfg, &stdout, false, false, "", false, 0, importMergeOptions{}); err != nil {
+		t.Fatalf("resumed import failed: %v", err)
+	}
+	tasks, _ = mock.GetTasks(context.Background(), list.ID)
+	if len(tasks) != len(summaries)+1 {
+		t.Errorf("expected %d tasks after resume, got %d", len(summaries)+1, len(tasks))
+	}
+}
+
+// TestListImportResumeAfterCrashMidChunk verifies that tasks created by a run that died
+// before checkpointing its chunk are adopted on resume instead of being created twice
+func TestListImportResumeAfterCrashMidChunk(t *testing.T) {
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	cfg := &Config{CachePath: filepath.Join(tmpDir, "cache", "lists.json")}
+	summaries := make([]string, importChunkSize+10)
+	for i := range summaries {
+		summaries[i] = fmt.Sprintf("Task %d", i)
+	}
+	inputPath := writeImportFile(t, tmpDir, summaries...)
+
+	mock := NewMockBackend("mock", "")
+	be := &failingImportBackend{MockBackend: mock, crashAfter: importChunkSize + 5}
+	func() {
+		defer func() {
+			if r := recover(); r != errImportCrash {
+				panic(r)
+			}
+		}()
+		_ = doListImport(ctx, be, inputPath, "", csvOptions{}, cfg, &bytes.Buffer{}, false, false, "", false, 0, importMergeOptions{})
+		t.Fatal("expected the import to crash")
+	}()
+
+	var stdout bytes.Buffer
+	if err := doListImport(ctx, mock, inputPath, "", csvOptions{}, cfg, &stdout, true, false, "", false, 0, importMergeOptions{}); err != nil {
+		t.Fatalf("resumed import failed: %v", err)
+	}
+	var report importReport
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+	if !report.Resumed || report.Skipped != importChunkSize || report.TaskCount != len(summaries)+1 {
+		t.Errorf("unexpected resumed report: skipped=%d task_count=%d resumed=%v", report.Skipped, report.TaskCount, report.Resumed)
+	}
+
+	list, _ := mock.GetListByName(ctx, "Imported")
+	tasks, _ := mock.GetTasks(ctx, list.ID)
+	seen := make(map[string]bool)
+	for _, task := range tasks {
+		if seen[task.Summary] {
+			t.Errorf("task %q was imported twice", task.Summary)
+		}
+		seen[task.Summary] = true
+	}
+	if len(tasks) != len(summaries)+1 {
+		t.Errorf("expected %d tasks after resume, got %d", len(summaries)+1, len(tasks))
+	}
+}
+
+// TestResolveSMTPPassword verifies the reminder email password comes from the credential
+// store before the config file, and that a plaintext config password triggers a warning
+func TestResolveSMTPPassword(t *testing.T) {
+	email := notification.EmailNotificationConfig{Enabled: true, Username: "me@example.com", Password: "plaintext"}
+
+	var stderr bytes.Buffer
+	cfg := &Config{Stderr: &stderr}
+	t.Setenv("TODOAT_SMTP_PASSWORD", "from-env")
+	if got := resolveSMTPPassword(cfg, email); got != "from-env" {
+		t.Errorf("expected credential store password, got %q", got)
+	}
+	if stderr.Len() != 0 {
+		t.Errorf("expected no warning when the credential store has the password, got %q", stderr.String())
+	}
+
+	t.Setenv("TODOAT_SMTP_PASSWORD", "")
+	if got := resolveSMTPPassword(cfg, email); got != "plaintext" {
+		t.Errorf("expected config password fallback, got %q", got)
+	}
+	if !strings.Contains(stderr.String(), "todoat credentials set smtp me@example.com --prompt") {
+		t.Errorf("expected plaintext warning, got %q", stderr.String())
+	}
+}
+
+// TestListExportToBackend verifies a list is pushed to another backend with its hierarchy,
+// and that exporting again updates the pushed tasks instead of duplicating them
+func TestListExportToBackend(t *testing.T) {
+	ctx := context.Background()
+	cfg := &Config{CachePath: filepath.Join(t.TempDir(), "cache", "lists.json")}
+
+	local := NewMockBackend("local", "")
+	work, _ := local.CreateList(ctx, "Work")
+	release, _ := local.CreateTask(ctx, work.ID, &backend.Task{Summary: "Release"})
+	_, _ = local.CreateTask(ctx, work.ID, &backend.Task{Summary: "Changelog", ParentID: release.ID})
+	remote := NewMockBackend("remote", "")
+
+	var stdout bytes.Buffer
+	if err := doListExportToBackend(ctx, local, remote, "work", "remote", cfg, &stdout, true); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	var report struct {
+		RemoteID string             `json:"remote_list_id"`
+		Tasks    []backendExportRow `json:"tasks"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+	if len(report.Tasks) != 2 || report.Tasks[0].Action != "created" || report.Tasks[0].RemoteID == "" {
+		t.Fatalf("unexpected mapping report: %+v", report.Tasks)
+	}
+
+	remoteList, _ := remote.GetListByName(ctx, "Work")
+	if remoteList == nil || remoteList.ID != report.RemoteID {
+		t.Fatalf("expected list Work on the target backend, got %+v", remoteList)
+	}
+	pushed, _ := remote.GetTasks(ctx, remoteList.ID)
+	byName := make(map[string]backend.Task)
+	for _, task := range pushed {
+		byName[task.Summary] = task
+	}
+	if byName["Changelog"].ParentID != byName["Release"].ID {
+		t.Errorf("expected hierarchy to be kept on the target")
+	}
+
+	// A second export updates the pushed tasks
+	local.tasks[work.ID][0].Summary = "Release 1.0"
+	stdout.Reset()
+	if err := doListExportToBackend(ctx, local, remote, "Work", "remote", cfg, &stdout, false); err != nil {
+		t.Fatalf("second export failed: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "updated Release 1.0 -> "+byName["Release"].ID) {
+		t.Errorf("expected update in mapping report, got:\n%s", stdout.String())
+	}
+	if pushed, _ = remote.GetTasks(ctx, remoteList.ID); len(pushed) != 2 {
+		t.Errorf("expected 2 tasks on the target after re-export, got %d", len(pushed))
+	}
+}
+
+// TestShellCompletions verifies the tab completion candidates offered by 'todoat shell'
+func TestShellCompletions(t *testing.T) {
+	ctx := context.Background()
+	mock := NewMockBackend("mock", "")
+	work, _ := mock.CreateList(ctx, "Work")
+	_, _ = mock.CreateTask(ctx, work.ID, &backend.Task{Summary: "Write report"})
+
+	cfg := &Config{ConfigPath: filepath.Join(t.TempDir(), "config.yaml"), session: &shellSession{be: mock}}
+	root := NewTodoAt(&bytes.Buffer{}, &bytes.Buffer{}, cfg)
+
+	tests := []struct {
+		args    []string
+		word    string
+		want    string
+		notWant string
+	}{
+		{nil, "", "Work", "shell"},
+		{nil, "", "list", ""},
+		{[]string{"Work"}, "", "complete", ""},
+		{[]string{"Work", "complete"}, "", "Write report", ""},
+		{[]string{"Work", "-p", "1", "update"}, "", "Write report", ""},
+		{[]string{"-L", "Work"}, "", "add", "Work"},
+		{[]string{"-L"}, "", "Work", ""},
+		{[]string{"Work", "add"}, "", "", "Write report"},
+		{[]string{"list"}, "", "create", ""},
+		{[]string{"list", "info"}, "", "Work", ""},
+		{[]string{"Work"}, "--", "--priority", ""},
+		{[]string{"list", "create"}, "-", "--json", ""},
+	}
+	for _, tt := range tests {
+		got := strings.Join(shellCompletions(root, cfg, tt.args, tt.word), "|")
+		if tt.want != "" && !strings.Contains("|"+got+"|", "|"+tt.want+"|") {
+			t.Errorf("shellCompletions(%q, %q) = %s, want %q among candidates", tt.args, tt.word, got, tt.want)
+		}
+		if tt.notWant != "" && strings.Contains("|"+got+"|", "|"+tt.notWant+"|") {
+			t.Errorf("shellCompletions(%q, %q) = %s, should not offer %q", tt.args, tt.word, got, tt.notWant)
+		}
+	}
+}
+
+// TestRequireFeature verifies experimental subsystems are refused until enabled,
+// and that enabled uses warn and are counted locally
+func TestRequireFeature(t *testing.T) {
+	cfg := &Config{CachePath: filepath.Join(t.TempDir(), "cache", "lists.json")}
+
+	var stderr bytes.Buffer
+	err := requireFeature(cfg, &stderr, "crdt_sync")
+	if err == nil || !strings.Contains(err.Error(), "--enable-feature crdt_sync") {
+		t.Fatalf("expected disabled feature error, got %v", err)
+	}
+
+	cfg.features, _ = features.NewSet([]string{"crdt_sync"})
+	for i := 0; i < 2; i++ {
+		if err := requireFeature(cfg, &stderr, "crdt_sync"); err != nil {
+			t.Fatalf("requireFeature: %v", err)
+		}
+	}
+	if !strings.Contains(stderr.String(), "Warning: crdt_sync is experimental") {
+		t.Errorf("expected experimental warning, got %q", stderr.String())
+	}
+	usage, err := features.LoadUsage(getFeatureUsagePath(cfg))
+	if err != nil || usage["crdt_sync"].Count != 2 {
+		t.Errorf("expected 2 recorded uses, got %+v (err %v)", usage, err)
+	}
+}
+
+// TestFeaturesCLI verifies 'features' reports features enabled in config and by --enable-feature,
+// and that unknown feature names are rejected
+func TestFeaturesCLI(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	if err := os.WriteFile(configPath, []byte("features:\n  experimental: [board_view]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &Config{
+		DBPath:     filepath.Join(tmpDir, "test.db"),
+		ConfigPath: configPath,
+		CachePath:  filepath.Join(tmpDir, "cache", "lists.json"),
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := Execute([]string{"--enable-feature", "rest_server", "features", "--json"}, &stdout, &stderr, cfg); code != 0 {
+		t.Fatalf("features failed: %s", stderr.String())
+	}
+	var out FeaturesOutput
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout.String())
+	}
+	enabled := map[string]bool{}
+	for _, f := range out.Features {
+		enabled[f.Name] = f.Enabled
+	}
+	if !enabled["board_view"] || !enabled["rest_server"] || enabled["crdt_sync"] {
+		t.Errorf("unexpected enabled features: %+v", out.Features)
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	if code := Execute([]string{"--enable-feature", "time_travel", "features"}, &stdout, &stderr, cfg); code == 0 {
+		t.Fatal("expected unknown feature to fail")
+	}
+	if !strings.Contains(stderr.String(), `unknown experimental feature "time_travel"`) {
+		t.Errorf("expected unknown feature error, got %q", stderr.String())
+	}
+}
+
+// countingBackend counts the GetLists and GetTasks calls that reach a backend
+type countingBackend struct {
+	backend.TaskManager
+	getLists int
+	getTasks int
+}
+
+func (c *countingBackend) GetLists(ctx context.Context) ([]backend.List, error) {
+	c.getLists++
+	return c.TaskManager.GetLists(ctx)
+}
+
+func (c *countingBackend) GetTasks(ctx context.Context, listID string) ([]backend.Task, error) {
+	c.getTasks++
+	return c.TaskManager.GetTasks(ctx, listID)
+}
+
+// TestCachedBackendMemoizesUntilWrite verifies repeated reads within a command reach the
+// backend once, that writes invalidate the cache, and that callers get independent copies
+func TestCachedBackendMemoizesUntilWrite(t *testing.T) {
+	ctx := context.Background()
+	mock := NewMockBackend("mock", "")
+	work, _ := mock.CreateList(ctx, "Work")
+	_, _ = mock.CreateTask(ctx, work.ID, &backend.Task{Summary: "Write report"})
+
+	counter := &countingBackend{TaskManager: mock}
+	be := newCachedBackend(counter)
+
+	for i := 0; i < 3; i++ {
+		if _, err := be.GetLists(ctx); err != nil {
+			t.Fatal(err)
+		}
+		tasks, err := be.GetTasks(ctx, work.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tasks[0].Summary = "changed by caller"
+	}
+	if counter.getLists != 1 || counter.getTasks != 1 {
+		t.Errorf("expected 1 GetLists and 1 GetTasks call, got %d and %d", counter.getLists, counter.getTasks)
+	}
+	tasks, _ := be.GetTasks(ctx, work.ID)
+	if tasks[0].Summary != "Write report" {
+		t.Errorf("cached tasks should not be affected by callers, got %q", tasks[0].Summary)
+	}
+	if task, _ := be.GetTask(ctx, work.ID, tasks[0].ID); task == nil || task.Summary != "Write report" {
+		t.Errorf("GetTask should be served from the cache, got %+v", task)
+	}
+
+	if _, err := be.CreateTask(ctx, work.ID, &backend.Task{Summary: "Review"}); err != nil {
+		t.Fatal(err)
+	}
+	tasks, _ = be.GetTasks(ctx, work.ID)
+	if counter.getTasks != 2 || len(tasks) != 2 {
+		t.Errorf("expected a write to invalidate the list's tasks, got %d calls and %d tasks", counter.getTasks, len(tasks))
+	}
+
+	if _, err := be.CreateList(ctx, "Home"); err != nil {
+		t.Fatal(err)
+	}
+	lists, _ := be.GetLists(ctx)
+	if counter.getLists != 2 || len(lists) != 2 {
+		t.Errorf("expected a list write to invalidate the lists, got %d calls and %d lists", counter.getLists, len(lists))
+	}
+}
+
+// TestResponseCacheBackendReusesResponses verifies online mode responses are reused
+// across commands until a write or --refresh, and expire after the TTL
+func TestResponseCacheBackendReusesResponses(t *testing.T) {
+	ctx := context.Background()
+	mock := NewMockBackend("mock", "")
+	work, _ := mock.CreateList(ctx, "Work")
+	_, _ = mock.CreateTask(ctx, work.ID, &backend.Task{Summary: "Write report"})
+	counter := &countingBackend{TaskManager: mock}
+	cfg := &Config{CachePath: filepath.Join(t.TempDir(), "lists.json")}
+
+	// Each command opens the backend again
+	for i := 0; i < 3; i++ {
+		be := newResponseCacheBackend(cfg, counter, "todoist", time.Minute)
+		if list, err := be.GetListByName(ctx, "work"); err != nil || list == nil {
+			t.Fatalf("GetListByName = %v, %v", list, err)
+		}
+		if tasks, err := be.GetTasks(ctx, work.ID); err != nil || len(tasks) != 1 {
+			t.Fatalf("GetTasks = %v, %v", tasks, err)
+		}
+	}
+	if counter.getLists != 1 || counter.getTasks != 1 {
+		t.Errorf("expected 1 GetLists and 1 GetTasks call, got %d and %d", counter.getLists, counter.getTasks)
+	}
+
+	be := newResponseCacheBackend(cfg, counter, "todoist", time.Minute)
+	if _, err := be.CreateTask(ctx, work.ID, &backend.Task{Summary: "Review"}); err != nil {
+		t.Fatal(err)
+	}
+	if tasks, _ := be.GetTasks(ctx, work.ID); counter.getTasks != 2 || len(tasks) != 2 {
+		t.Errorf("expected a write to invalidate the list's tasks, got %d calls and %d tasks", counter.getTasks, len(tasks))
+	}
+
+	cfg.Refresh = true
+	be = newResponseCacheBackend(cfg, counter, "todoist", time.Minute)
+	_, _ = be.GetLists(ctx)
+	if counter.getLists != 2 {
+		t.Errorf("expected --refresh to bypass the cache, got %d GetLists calls", counter.getLists)
+	}
+
+	cfg.Refresh = false
+	be = newResponseCacheBackend(cfg, counter, "todoist", time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	_, _ = be.GetLists(ctx)
+	if counter.getLists != 3 {
+		t.Errorf("expected an expired response to be fetched again, got %d GetLists calls", counter.getLists)
+	}
+}
+
+// TestLogLevelFlagWritesLogFile verifies --log-level and logging.file route records to the configured log file
+func TestLogLevelFlagWritesLogFile(t *testing.T) {
+	logger := utils.GetLogger()
+	defer func() {
+		logger.SetOutputFile(nil)
+		logger.SetLevel(slog.LevelInfo)
+		_ = logger.SetFormat(utils.LogFormatText)
+	}()
+
+	tmpDir := t.TempDir()
+	logPath := filepath.Join(tmpDir, "logs", "todoat.log")
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	if err := os.WriteFile(configPath, []byte("logging:\n  level: warn\n  format: json\n  file: "+logPath+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &Config{
+		DBPath:     filepath.Join(tmpDir, "test.db"),
+		ConfigPath: configPath,
+		CachePath:  filepath.Join(tmpDir, "cache", "lists.json"),
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := Execute([]string{"--log-level", "debug", "-y", "list"}, &stdout, &stderr, cfg); code != 0 {
+		t.Fatalf("list failed: %s", stderr.String())
+	}
+	if logger.Level() != slog.LevelDebug {
+		t.Errorf("--log-level debug should override logging.level, got %v", logger.Level())
+	}
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("log file should be created: %v", err)
+	}
+	if !strings.Contains(string(data), `"level":"DEBUG"`) {
+		t.Errorf("log file should contain JSON debug records, got: %s", data)
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	if code := Execute([]string{"--log-level", "loud", "-y", "list"}, &stdout, &stderr, cfg); code == 0 {
+		t.Error("invalid --log-level should fail")
+	}
+	if !strings.Contains(stderr.String(), "invalid log level") {
+		t.Errorf("expected invalid log level error, got: %s", stderr.String())
+	}
+}
+
+func TestSyncDaemonInstallUninstall(t *testing.T) {
+	var commands []string
+	orig := runServiceCommand
+	runServiceCommand = func(name string, args ...string) error {
+		commands = append(commands, name+" "+strings.Join(args, " "))
+		return nil
+	}
+	defer func() { runServiceCommand = orig }()
+
+	tmpDir := t.TempDir()
+	serviceDir := filepath.Join(tmpDir, "services")
+	cfg := &Config{
+		DBPath:           filepath.Join(tmpDir, "test.db"),
+		ConfigPath:       filepath.Join(tmpDir, "config.yaml"),
+		CachePath:        filepath.Join(tmpDir, "cache", "lists.json"),
+		DaemonPIDPath:    filepath.Join(tmpDir, "daemon.pid"),
+		DaemonSocketPath: filepath.Join(tmpDir, "daemon.sock"),
+		DaemonLogPath:    filepath.Join(tmpDir, "daemon.log"),
+		DaemonBinaryPath: "/usr/local/bin/todoat",
+		DaemonServiceDir: serviceDir,
+	}
+	manager, err := daemon.DefaultServiceManager()
+	if err != nil {
+		t.Skip(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := Execute([]string{"-y", "sync", "daemon", "install", "--print", "--interval", "90"}, &stdout, &stderr, cfg); code != 0 {
+		t.Fatalf("install --print failed: %s", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "/usr/local/bin/todoat") || !strings.Contains(stdout.String(), "--db-path") {
+		t.Errorf("printed service should run the binary against this database, got: %s", stdout.String())
+	}
+	if _, err := os.Stat(serviceDir); !os.IsNotExist(err) {
+		t.Error("--print should not write any files")
+	}
+	if len(commands) != 0 {
+		t.Errorf("--print should not run %s, ran %v", manager, commands)
+	}
+
+	stdout.Reset()
+	if code := Execute([]string{"-y", "sync", "daemon", "install"}, &stdout, &stderr, cfg); code != 0 {
+		t.Fatalf("install failed: %s", stderr.String())
+	}
+	entries, _ := os.ReadDir(serviceDir)
+	if len(entries) != 1 {
+		t.Fatalf("expected one service file, got %d", len(entries))
+	}
+	if len(commands) == 0 || !strings.Contains(stdout.String(), "installed and enabled") {
+		t.Errorf("install should enable the service, ran %v, output: %s", commands, stdout.String())
+	}
+
+	stdout.Reset()
+	commands = nil
+	if code := Execute([]string{"-y", "sync", "daemon", "uninstall"}, &stdout, &stderr, cfg); code != 0 {
+		t.Fatalf("uninstall failed: %s", stderr.String())
+	}
+	entries, _ = os.ReadDir(serviceDir)
+	if len(entries) != 0 || len(commands) == 0 {
+		t.Errorf("uninstall should disable and remove the service, ran %v, %d files left", commands, len(entries))
+	}
+	if !strings.Contains(stdout.String(), "uninstalled") {
+		t.Errorf("unexpected uninstall output: %s", stdout.String())
+	}
+}
+
+func TestBuildBackendConfigUsesPasswordCmd(t *testing.T) {
+	credentials.ResetCommandCache()
+	defer credentials.ResetCommandCache()
+
+	raw := map[string]interface{}{
+		"backends": map[string]interface{}{
+			"cloud": map[string]interface{}{
+				"type":         "nextcloud",
+				"host":         "cloud.example.com",
+				"username":     "me",
+				"password_cmd": "echo from-pass",
+			},
+			"tasks": map[string]interface{}{
+				"type":      "todoist",
+				"token_cmd": "echo todoist-token",
+			},
+		},
+	}
+	if got := buildNextcloudConfigWithKeyring("cloud", raw).Password; got != "from-pass" {
+		t.Errorf("nextcloud password should come from password_cmd, got %q", got)
+	}
+	if got := buildTodoistConfigWithKeyring("tasks", raw).APIToken; got != "todoist-token" {
+		t.Errorf("todoist token should come from token_cmd, got %q", got)
+	}
+}
+
+// TestConfigPriorityMap verifies priority_map accepts the integer keys YAML produces for Todoist
+func TestConfigPriorityMap(t *testing.T) {
+	got := configPriorityMap(map[interface{}]interface{}{4: []interface{}{1, 2}, "1": []interface{}{7, 8}})
+	if len(got) != 2 || len(got["4"]) != 2 || got["1"][1] != 8 {
+		t.Errorf("configPriorityMap = %v", got)
+	}
+	if configPriorityMap(nil) != nil {
+		t.Error("unset priority_map should be nil")
+	}
+}
+
+func TestCredentialsRefreshStoresOAuthToken(t *testing.T) {
+	var gotRefresh []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		gotRefresh = append(gotRefresh, r.PostForm.Get("refresh_token"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"fresh-access","refresh_token":"rotated-refresh","expires_in":3600}`))
+	}))
+	defer server.Close()
+
+	keyring := credentials.NewMockKeyring()
+	credentialKeyring = keyring
+	defer func() { credentialKeyring = nil }()
+	t.Setenv("TODOAT_GOOGLE_ACCESS_TOKEN", "")
+	t.Setenv("TODOAT_GOOGLE_REFRESH_TOKEN", "")
+
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	configContent := `
+backends:
+  sqlite:
+    enabled: true
+  work:
+    type: google
+    refresh_token: config-refresh
+    token_url: ` + server.URL + `
+default_backend: sqlite
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to create config: %v", err)
+	}
+	cfg := &Config{DBPath: filepath.Join(tmpDir, "test.db"), ConfigPath: configPath}
+
+	// The first refresh uses the config token, the second the rotated one from the keyring
+	for i := 0; i < 2; i++ {
+		var stdout, stderr bytes.Buffer
+		if code := Execute([]string{"credentials", "refresh", "work"}, &stdout, &stderr, cfg); code != 0 {
+			t.Fatalf("credentials refresh failed: %s", stderr.String())
+		}
+		if !strings.Contains(stdout.String(), "Refreshed OAuth token for work") {
+			t.Errorf("unexpected output: %s", stdout.String())
+		}
+	}
+	if strings.Join(gotRefresh, ",") != "config-refresh,rotated-refresh" {
+		t.Errorf("unexpected refresh tokens sent: %v", gotRefresh)
+	}
+
+	token, err := credentials.NewManager(credentials.WithKeyring(keyring)).GetOAuthToken(context.Background(), "work")
+	if err != nil || token == nil {
+		t.Fatalf("expected stored OAuth token, got %v, %v", token, err)
+	}
+	if token.AccessToken != "fresh-access" || token.Expiry.IsZero() {
+		t.Errorf("unexpected stored token: %+v", token)
+	}
+	if got := buildGoogleConfigWithKeyring("work", nil); got.AccessToken != "fresh-access" || got.Expiry.IsZero() || got.OnTokenRefresh == nil {
+		t.Errorf("google config should load the stored OAuth token, got %+v", got)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := Execute([]string{"credentials", "refresh", "sqlite"}, &stdout, &stderr, cfg); code == 0 {
+		t.Error("refreshing a non-OAuth backend should fail")
+	}
+}
+
+func TestSyncPendingGroupsLocalChanges(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "test.db")
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	configContent := `
+backends:
+  mycloud:
+    type: nextcloud
+    enabled: true
+default_backend: mycloud
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to create config: %v", err)
+	}
+	cfg := &Config{DBPath: dbPath, ConfigPath: configPath}
+
+	ctx := context.Background()
+	localBE, err := sqlite.NewWithBackendID(dbPath, "mycloud")
+	if err != nil {
+		t.Fatalf("failed to open local cache: %v", err)
+	}
+	inbox, _ := localBE.CreateList(ctx, "Inbox")
+	work, _ := localBE.CreateList(ctx, "Work")
+	synced, _ := localBE.CreateTask(ctx, inbox.ID, &backend.Task{Summary: "Already synced"})
+	_ = localBE.MarkAllSynced(ctx, time.Now().Add(time.Millisecond), nil)
+	added, _ := localBE.CreateTask(ctx, work.ID, &backend.Task{Summary: "Write report"})
+	_, _ = localBE.CreateTask(ctx, inbox.ID, &backend.Task{Summary: "Buy milk"})
+	_ = localBE.Close()
+
+	syncMgr, err := getSyncManager(cfg)
+	if err != nil {
+		t.Fatalf("sync manager: %v", err)
+	}
+	_ = syncMgr.QueueOperationByStringID(added.ID, added.Summary, work.ID, "create")
+	_ = syncMgr.QueueOperationByStringID("gone-uid", "Old task", inbox.ID, "delete")
+	_ = syncMgr.Close()
+
+	var stdout, stderr bytes.Buffer
+	if code := Execute([]string{"sync", "pending"}, &stdout, &stderr, cfg); code != 0 {
+		t.Fatalf("sync pending failed: %s", stderr.String())
+	}
+	out := stdout.String()
+	for _, want := range []string{"Local changes since last sync: 3", "Inbox: 1 change", "Work: 1 change", "(deleted): 1 change"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, synced.Summary) {
+		t.Errorf("synced task should not be listed:\n%s", out)
+	}
+
+	stdout.Reset()
+	if code := Execute([]string{"sync", "pending", "--detailed"}, &stdout, &stderr, cfg); code != 0 {
+		t.Fatalf("sync pending --detailed failed: %s", stderr.String())
+	}
+	out = stdout.String()
+	for _, want := range []string{"Buy milk", "unsynced", "Write report", "create", "Old task", "delete"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in detailed output:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, synced.Summary) {
+		t.Errorf("synced task should not be listed:\n%s", out)
+	}
+}
+
+// trelloBoardJSON is a board export with an archived card and a card with two checklists
+const trelloBoardJSON = `{
+  "id": "board-1", "name": "Launch",
+  "lists": [{"id": "todo", "name": "To Do", "pos": 1}, {"id": "old", "name": "Old", "closed": true, "pos": 2}],
+  "cards": [
+    {"id": "c1", "name": "Write post", "desc": "Blog", "idList": "todo", "pos": 1, "due": "2026-05-01T12:00:00.000Z",
+     "labels": [{"name": "Marketing", "color": "green"}]},
+    {"id": "c2", "name": "Ship", "idList": "todo", "pos": 2, "dueComplete": true},
+    {"id": "c3", "name": "Archived", "idList": "todo", "pos": 3, "closed": true},
+    {"id": "c4", "name": "In closed list", "idList": "old", "pos": 4}
+  ],
+  "checklists": [
+    {"id": "k1", "idCard": "c1", "name": "Draft", "pos": 1, "checkItems": [{"id": "i1", "name": "Outline", "state": "complete", "pos": 1}]},
+    {"id": "k2", "idCard": "c1", "name": "Review", "pos": 2, "checkItems": [{"id": "i2", "name": "Proofread", "state": "incomplete", "pos": 1}]}
+  ]
+}`
+
+// TestMigrateFromTrelloMapsBoardAndReruns verifies the card, label and checklist mapping and
+// that importing the same board again updates the tasks instead of duplicating them
+func TestMigrateFromTrelloMapsBoardAndReruns(t *testing.T) {
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	cfg := &Config{CachePath: filepath.Join(tmpDir, "cache", "lists.json")}
+	board, err := trello.ParseBoard([]byte(trelloBoardJSON))
+	if err != nil {
+		t.Fatalf("ParseBoard: %v", err)
+	}
+	be := NewMockBackend("mock", "")
+
+	reportPath := filepath.Join(tmpDir, "report.json")
+	var stdout bytes.Buffer
+	if err := doMigrateFromTrello(ctx, be, board, "", "tags", false, reportPath, cfg, &stdout, false); err != nil {
+		t.Fatalf("first import: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "6 created, 0 updated") || !strings.Contains(stdout.String(), "2 archived cards skipped") {
+		t.Errorf("unexpected summary:\n%s", stdout.String())
+	}
+
+	list, _ := be.GetListByName(ctx, "Launch")
+	tasks, _ := be.GetTasks(ctx, list.ID)
+	bySummary := make(map[string]backend.Task)
+	for _, task := range tasks {
+		bySummary[task.Summary] = task
+	}
+	post := bySummary["Write post"]
+	if post.Categories != "To Do,Marketing" || post.Description != "Blog" || post.DueDate == nil {
+		t.Errorf("card not mapped: %+v", post)
+	}
+	if bySummary["Ship"].Status != backend.StatusCompleted {
+		t.Errorf("expected completed card to be DONE, got %s", bySummary["Ship"].Status)
+	}
+	// Two checklists on one card each become a parent of their items
+	if bySummary["Draft"].ParentID != post.ID || bySummary["Outline"].ParentID != bySummary["Draft"].ID {
+		t.Errorf("expected checklist hierarchy, got Draft=%q Outline=%q", bySummary["Draft"].ParentID, bySummary["Outline"].ParentID)
+	}
+	if bySummary["Outline"].Status != backend.StatusCompleted || bySummary["Proofread"].Status != backend.StatusNeedsAction {
+		t.Errorf("expected check item states to map to status")
+	}
+	if data, err := os.ReadFile(reportPath); err != nil || !strings.Contains(string(data), `"trello_id": "i2"`) {
+		t.Errorf("expected mapping report with Trello IDs, got %s (%v)", data, err)
+	}
+
+	stdout.Reset()
+	if err := doMigrateFromTrello(ctx, be, board, "", "tags", false, "", cfg, &stdout, false); err != nil {
+		t.Fatalf("second import: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "0 created, 6 updated") {
+		t.Errorf("expected re-run to update, got:\n%s", stdout.String())
+	}
+	if tasks, _ := be.GetTasks(ctx, list.ID); len(tasks) != 6 {
+		t.Errorf("expected 6 tasks after re-run, got %d", len(tasks))
+	}
+}
+
+// TestMigrateFromTrelloListsAsParents verifies that --lists-as parents nests cards under a task per list
+func TestMigrateFromTrelloListsAsParents(t *testing.T) {
+	board, err := trello.ParseBoard([]byte(trelloBoardJSON))
+	if err != nil {
+		t.Fatalf("ParseBoard: %v", err)
+	}
+	planned, _ := planTrelloTasks(board, "parents")
+	if planned[0].Kind != "list" || planned[0].Task.Summary != "To Do" {
+		t.Fatalf("expected the list parent first, got %+v", planned[0])
+	}
+	for _, p := range planned {
+		if p.Kind == "card" && (p.ParentKey != "todo" || strings.Contains(p.Task.Categories, "To Do")) {
+			t.Errorf("expected card %q under its list without a list tag, got %+v", p.Task.Summary, p)
+		}
+	}
+}
+
+// TestCalendarFeedHandler verifies the served feed holds an event per open task
+// with a due date and answers If-None-Match with 304 while nothing changed
+func TestCalendarFeedHandler(t *testing.T) {
+	ctx := context.Background()
+	mock := NewMockBackend("mock", "")
+	list, _ := mock.CreateList(ctx, "Work")
+	due := time.Date(2030, 5, 1, 0, 0, 0, 0, time.Local)
+	_, _ = mock.CreateTask(ctx, list.ID, &backend.Task{Summary: "Ship release", DueDate: &due, Categories: "release"})
+	_, _ = mock.CreateTask(ctx, list.ID, &backend.Task{Summary: "No due date"})
+	done, _ := mock.CreateTask(ctx, list.ID, &backend.Task{Summary: "Already done", DueDate: &due, Status: backend.StatusCompleted})
+
+	handler := calendarFeedHandler(func(ctx context.Context) (string, int, error) {
+		return buildCalendarFeed(ctx, mock, calendarFeedOptions{Type: "event"})
+	})
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/calendar.ics", nil))
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/calendar") {
+		t.Fatalf("unexpected response: %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	body := rec.Body.String()
+	for _, want := range []string{"BEGIN:VEVENT", "SUMMARY:Ship release", "DTSTART;VALUE=DATE:20300501", "CATEGORIES:release", "DESCRIPTION:List: Work"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("feed missing %q:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "No due date") || strings.Contains(body, done.ID) {
+		t.Errorf("feed should only contain open tasks with a due date:\n%s", body)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/calendar.ics", nil)
+	req.Header.Set("If-None-Match", rec.Header().Get("ETag"))
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotModified {
+		t.Errorf("expected 304 for an unchanged feed, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/calendar.ics", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405 for POST, got %d", rec.Code)
+	}
+}
+
+// sharedRemote is a remote backend that reports the account it is logged in as
+type sharedRemote struct {
+	*sqlite.Backend
+	account string
+}
+
+func (r *sharedRemote) AccountName() string { return r.account }
+
+// coarseRemote is a remote that stores priorities as three levels
+type coarseRemote struct {
+	*sqlite.Backend
+}
+
+func (r *coarseRemote) StoresCustomStatuses() bool { return false }
+
+func (r *coarseRemote) PriorityMap() *backend.PriorityMap {
+	return backend.MustPriorityMap(map[string][]int{"high": {1, 2, 3}, "normal": {5, 0, 4, 6}, "low": {9, 7, 8}}, []string{"high", "normal", "low"})
+}
+
+// TestSyncPullKeepsPriorityWithinNativeLevel verifies pull keeps the local
+// priority when the remote reads it back as another priority of the same level
+func TestSyncPullKeepsPriorityWithinNativeLevel(t *testing.T) {
+	ctx := context.Background()
+	localBE, err := sqlite.New(":memory:")
+	if err != nil {
+		t.Fatalf("sqlite.New error: %v", err)
+	}
+	defer func() { _ = localBE.Close() }()
+	localList, _ := localBE.CreateList(ctx, "Work")
+	_, _ = localBE.CreateTask(ctx, localList.ID, &backend.Task{ID: "t1", Summary: "Report", Priority: 2})
+	_, _ = localBE.CreateTask(ctx, localList.ID, &backend.Task{ID: "t2", Summary: "Email", Priority: 2})
+
+	remoteDB, err := sqlite.New(":memory:")
+	if err != nil {
+		t.Fatalf("sqlite.New error: %v", err)
+	}
+	defer func() { _ = remoteDB.Close() }()
+	time.Sleep(10 * time.Millisecond)
+	remoteList, _ := remoteDB.CreateList(ctx, "Work")
+	_, _ = remoteDB.CreateTask(ctx, remoteList.ID, &backend.Task{ID: "t1", Summary: "Quarterly report", Priority: 1})
+	_, _ = remoteDB.CreateTask(ctx, remoteList.ID, &backend.Task{ID: "t2", Summary: "Email", Priority: 9})
+
+	var stderr bytes.Buffer
+	if _, _, _, err := syncPullFromRemote(ctx, localBE, &coarseRemote{Backend: remoteDB}, false, 1, noProgress{}, &stderr); err != nil {
+		t.Fatalf("syncPullFromRemote error: %v", err)
+	}
+	if task, _ := localBE.GetTask(ctx, localList.ID, "t1"); task == nil || task.Summary != "Quarterly report" || task.Priority != 2 {
+		t.Errorf("t1 = %+v, want the remote summary and local priority 2", task)
+	}
+	if task, _ := localBE.GetTask(ctx, localList.ID, "t2"); task == nil || task.Priority != 9 {
+		t.Errorf("t2 = %+v, want remote priority 9 from another level", task)
+	}
+}
+
+// TestSyncPullKeepsCustomStatus verifies pull keeps a custom status while a
+// remote without custom statuses still has its base status
+func TestSyncPullKeepsCustomStatus(t *testing.T) {
+	backend.SetStatusWorkflow(backend.StatusWorkflow{Custom: []backend.CustomStatus{{Name: "WAITING", Base: backend.StatusNeedsAction}}})
+	defer backend.SetStatusWorkflow(backend.StatusWorkflow{})
+
+	ctx := context.Background()
+	localBE, err := sqlite.New(":memory:")
+	if err != nil {
+		t.Fatalf("sqlite.New error: %v", err)
+	}
+	defer func() { _ = localBE.Close() }()
+	localList, _ := localBE.CreateList(ctx, "Work")
+	_, _ = localBE.CreateTask(ctx, localList.ID, &backend.Task{ID: "t1", Summary: "Invoice", Status: "WAITING"})
+	_, _ = localBE.CreateTask(ctx, localList.ID, &backend.Task{ID: "t2", Summary: "Offer", Status: "WAITING"})
+
+	remoteDB, err := sqlite.New(":memory:")
+	if err != nil {
+		t.Fatalf("sqlite.New error: %v", err)
+	}
+	defer func() { _ = remoteDB.Close() }()
+	time.Sleep(10 * time.Millisecond)
+	remoteList, _ := remoteDB.CreateList(ctx, "Work")
+	_, _ = remoteDB.CreateTask(ctx, remoteList.ID, &backend.Task{ID: "t1", Summary: "Invoice ACME", Status: backend.StatusNeedsAction})
+	_, _ = remoteDB.CreateTask(ctx, remoteList.ID, &backend.Task{ID: "t2", Summary: "Offer", Status: backend.StatusCompleted})
+
+	var stderr bytes.Buffer
+	if _, _, _, err := syncPullFromRemote(ctx, localBE, &coarseRemote{Backend: remoteDB}, false, 1, noProgress{}, &stderr); err != nil {
+		t.Fatalf("syncPullFromRemote error: %v", err)
+	}
+	if task, _ := localBE.GetTask(ctx, localList.ID, "t1"); task == nil || task.Summary != "Invoice ACME" || task.Status != "WAITING" {
+		t.Errorf("t1 = %+v, want the remote summary and status WAITING", task)
+	}
+	if task, _ := localBE.GetTask(ctx, localList.ID, "t2"); task == nil || task.Status != backend.StatusCompleted {
+		t.Errorf("t2 = %+v, want the remote status COMPLETED", task)
+	}
+}
+
+// TestBuildStatusWorkflowValidates verifies invalid custom statuses and transitions are rejected
+func TestBuildStatusWorkflowValidates(t *testing.T) {
+	tests := map[string]config.StatusesConfig{
+		"built-in name": {Custom: []config.CustomStatusConfig{{Name: "done"}}},
+		"bad name":      {Custom: []config.CustomStatusConfig{{Name: "on hold"}}},
+		"duplicate":     {Custom: []config.CustomStatusConfig{{Name: "WAITING"}, {Name: "waiting"}}},
+		"closed base":   {Custom: []config.CustomStatusConfig{{Name: "ARCHIVED", Base: "DONE"}}},
+		"unknown":       {Transitions: map[string][]string{"TODO": {"LATER"}}},
+	}
+	for name, cfg := range tests {
+		if _, err := buildStatusWorkflow(cfg); err == nil {
+			t.Errorf("%s: buildStatusWorkflow should fail", name)
+		}
+	}
+
+	workflow, err := buildStatusWorkflow(config.StatusesConfig{
+		Custom:      []config.CustomStatusConfig{{Name: "review", Base: "in-progress"}},
+		Transitions: map[string][]string{"Review": {"DONE", "I"}},
+	})
+	if err != nil {
+		t.Fatalf("buildStatusWorkflow error: %v", err)
+	}
+	if len(workflow.Custom) != 1 || workflow.Custom[0].Name != "REVIEW" || workflow.Custom[0].Base != backend.StatusInProgress {
+		t.Errorf("Custom = %+v", workflow.Custom)
+	}
+	if got := workflow.Transitions["REVIEW"]; len(got) != 2 || got[0] != backend.StatusCompleted {
+		t.Errorf("Transitions = %v", workflow.Transitions)
+	}
+}
+
+// TestSyncPullKeepsOthersTasks verifies pull keeps tasks other users created
+// when they vanish from a shared remote, and copies who shared each list
+func TestSyncPullKeepsOthersTasks(t *testing.T) {
+	ctx := context.Background()
+	remoteDB, err := sqlite.New(":memory:")
+	if err != nil {
+		t.Fatalf("sqlite.New error: %v", err)
+	}
+	defer func() { _ = remoteDB.Close() }()
+	remoteList, _ := remoteDB.CreateList(ctx, "Household")
+	remoteList.SharedBy = "alice"
+	_, _ = remoteDB.UpdateList(ctx, remoteList)
+	remoteBE := &sharedRemote{Backend: remoteDB, account: "bob"}
+
+	localBE, err := sqlite.NewWithBackendID(filepath.Join(t.TempDir(), "local.db"), "mycloud")
+	if err != nil {
+		t.Fatalf("failed to open local cache: %v", err)
+	}
+	defer func() { _ = localBE.Close() }()
+	localList, _ := localBE.CreateList(ctx, "Household")
+	_, _ = localBE.CreateTask(ctx, localList.ID, &backend.Task{Summary: "Alice's chore", Metadata: map[string]string{backend.CreatedByKey: "alice"}})
+	_, _ = localBE.CreateTask(ctx, localList.ID, &backend.Task{Summary: "Bob's chore", Metadata: map[string]string{backend.CreatedByKey: "bob"}})
+
+	var stderr bytes.Buffer
+	_, _, deleted, err := syncPullFromRemote(ctx, localBE, remoteBE, false, 1, noProgress{}, &stderr)
+	if err != nil {
+		t.Fatalf("syncPullFromRemote error: %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("deleted = %d, want only bob's own task", deleted)
+	}
+	if !strings.Contains(stderr.String(), "Kept 1 task created by other users") {
+		t.Errorf("expected kept notice, got %q", stderr.String())
+	}
+	if list, _ := localBE.GetListByName(ctx, "Household"); list == nil || list.SharedBy != "alice" {
+		t.Errorf("local list = %+v, want SharedBy alice", list)
+	}
+
+	_, _, deleted, err = syncPullFromRemote(ctx, localBE, remoteBE, true, 1, noProgress{}, &stderr)
+	if err != nil || deleted != 1 {
+		t.Errorf("with delete_others_tasks: deleted = %d, %v; want 1", deleted, err)
+	}
+}
+
+// parallelRemote is a remote that reads lists concurrently when concurrent is
+// set, counting the fetches in flight and failing the lists named in fail
+type parallelRemote struct {
+	*sqlite.Backend
+	concurrent bool
+	mu         sync.Mutex
+	inFlight   int
+	peak       int
+	fail       map[string]bool
+	names      map[string]string
+}
+
+func (r *parallelRemote) ReadsConcurrently() bool { return r.concurrent }
+
+func (r *parallelRemote) GetTasks(ctx context.Context, listID string) ([]backend.Task, error) {
+	r.mu.Lock()
+	r.inFlight++
+	r.peak = max(r.peak, r.inFlight)
+	r.mu.Unlock()
+	time.Sleep(20 * time.Millisecond)
+	r.mu.Lock()
+	r.inFlight--
+	r.mu.Unlock()
+	if r.fail[r.names[listID]] {
+		return nil, errors.New("server error")
+	}
+	return r.Backend.GetTasks(ctx, listID)
+}
+
+// TestSyncPullFetchesListsInParallel verifies pull fetches up to the configured
+// number of lists at once, writes every list, and reports the lists it could not fetch
+func TestSyncPullFetchesListsInParallel(t *testing.T) {
+	ctx := context.Background()
+	remoteDB, err := sqlite.New(":memory:")
+	if err != nil {
+		t.Fatalf("sqlite.New error: %v", err)
+	}
+	defer func() { _ = remoteDB.Close() }()
+	remote := &parallelRemote{Backend: remoteDB, concurrent: true, fail: map[string]bool{"List 3": true}, names: map[string]string{}}
+	for i := range 8 {
+		list, _ := remoteDB.CreateList(ctx, fmt.Sprintf("List %d", i))
+		remote.names[list.ID] = list.Name
+		_, _ = remoteDB.CreateTask(ctx, list.ID, &backend.Task{Summary: fmt.Sprintf("Task %d", i)})
+	}
+
+	localBE, err := sqlite.NewWithBackendID(filepath.Join(t.TempDir(), "local.db"), "nextcloud")
+	if err != nil {
+		t.Fatalf("failed to open local cache: %v", err)
+	}
+	defer func() { _ = localBE.Close() }()
+
+	var stderr bytes.Buffer
+	newCount, _, _, err := syncPullFromRemote(ctx, localBE, remote, false, 3, noProgress{}, &stderr)
+	if err == nil || !strings.Contains(err.Error(), "1 remote list(s)") || !strings.Contains(err.Error(), "list 'List 3': server error") {
+		t.Errorf("expected the failed list in the error, got %v", err)
+	}
+	if newCount != 7 {
+		t.Errorf("newCount = %d, want the tasks of the 7 lists fetched", newCount)
+	}
+	if remote.peak < 2 || remote.peak > 3 {
+		t.Errorf("peak concurrent fetches = %d, want 2 or 3", remote.peak)
+	}
+
+	// A backend that does not allow it is read one list at a time
+	remote.concurrent, remote.peak = false, 0
+	_, _, _, _ = syncPullFromRemote(ctx, localBE, remote, false, 3, noProgress{}, &stderr)
+	if remote.peak != 1 {
+		t.Errorf("peak concurrent fetches = %d, want 1 for a backend that reads serially", remote.peak)
+	}
+}
+
+// TestQueuedUpdateRecordsChangedFields verifies updates queue the fields they changed
+// and operations queued without them are read back as full updates
+func TestQueuedUpdateRecordsChangedFields(t *testing.T) {
+	sm, err := NewSyncManager(filepath.Join(t.TempDir(), "sync.db"))
+	if err != nil {
+		t.Fatalf("NewSyncManager failed: %v", err)
+	}
+	defer func() { _ = sm.db.Close() }()
+
+	fields := []string{backend.FieldSummary, backend.FieldDueDate}
+	if err := sm.QueueBackendOperations("todoist", "update", []queuedOperation{{TaskID: "t1", Summary: "Pay rent", Fields: fields}}); err != nil {
+		t.Fatalf("QueueBackendOperations failed: %v", err)
+	}
+	if err := sm.QueueBackendOperation("todoist", "t2", "Legacy", "", "update"); err != nil {
+		t.Fatalf("QueueBackendOperation failed: %v", err)
+	}
+
+	ops, err := sm.GetPendingOperations()
+	if err != nil || len(ops) != 2 {
+		t.Fatalf("GetPendingOperations = %d ops, %v; want 2", len(ops), err)
+	}
+	for _, op := range ops {
+		switch op.TaskUID {
+		case "t1":
+			if strings.Join(op.ChangedFields, ",") != "summary,due_date" {
+				t.Errorf("changed fields of t1 = %v, want %v", op.ChangedFields, fields)
+			}
+		case "t2":
+			if len(op.ChangedFields) != 0 {
+				t.Errorf("changed fields of t2 = %v, want none", op.ChangedFields)
+			}
+		}
+	}
+}
+
+// TestExportJSONStreamsIndentedDocument verifies the streamed JSON export matches
+// encoding the whole list at once
+func TestExportJSONStreamsIndentedDocument(t *testing.T) {
+	list := &backend.List{Name: "Work <team>"}
+	created := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	tasks := []backend.Task{
+		{ID: "1", Summary: "Plan", Status: backend.StatusNeedsAction, Created: created, Modified: created, Metadata: map[string]string{"owner": "sam"}},
+		{ID: "2", Summary: "Ship", Status: backend.StatusCompleted, Priority: 1, ParentID: "1", Created: created, Modified: created},
+	}
+
+	for _, tc := range []struct {
+		name  string
+		tasks []backend.Task
+	}{{"tasks", tasks}, {"empty", []backend.Task{}}} {
+		var streamed bytes.Buffer
+		if err := exportJSON(&streamed, list, tc.tasks, noProgress{}); err != nil {
+			t.Fatalf("%s: exportJSON: %v", tc.name, err)
+		}
+		var doc struct {
+			ListName string            `json:"list_name"`
+			Tasks    []json.RawMessage `json:"tasks"`
+		}
+		if err := json.Unmarshal(streamed.Bytes(), &doc); err != nil {
+			t.Fatalf("%s: invalid JSON: %v\n%s", tc.name, err, streamed.String())
+		}
+		want, _ := json.MarshalIndent(doc, "", "  ")
+		if streamed.String() != string(want) {
+			t.Errorf("%s: streamed export differs from the indented document:\n%s\nwant:\n%s", tc.name, streamed.String(), want)
+		}
+	}
+}
+
+// TestTransferProgressReportsLargeLists verifies progress is reported every
+// transferProgressStep tasks and at the end, and only for large lists
+func TestTransferProgressReportsLargeLists(t *testing.T) {
+	if p := newTransferProgress(&Config{}, "Exported", transferProgressMinTasks-1); p != (noProgress{}) {
+		t.Errorf("expected no progress for a small list")
+	}
+
+	var stderr bytes.Buffer
+	p := newTransferProgress(&Config{Stderr: &stderr}, "Imported", 2500)
+	for done := 0; done < 2500; done += 50 {
+		p.Add(50)
+	}
+	want := "Imported 1000/2500 tasks (40%)\nImported 2000/2500 tasks (80%)\nImported 2500/2500 tasks (100%)\n"
+	if stderr.String() != want {
+		t.Errorf("progress output = %q, want %q", stderr.String(), want)
+	}
+}
+
+// TestSyncProgressReporters verifies the plain reporter prints by interval with
+// the elapsed time and the terminal reporter leaves the final count on its line
+func TestSyncProgressReporters(t *testing.T) {
+	var plain bytes.Buffer
+	p := newProgressReporter(&plain, 0, time.Nanosecond)
+	p.Start("Pushed", "operations", 4)
+	time.Sleep(time.Millisecond)
+	p.Add(1)
+	p.Finish()
+	if !strings.HasPrefix(plain.String(), "Pushed 1/4 operations (25%), ") || !strings.Contains(plain.String(), "elapsed") {
+		t.Errorf("plain progress output = %q", plain.String())
+	}
+
+	var live bytes.Buffer
+	tp := &terminalProgress{w: &live}
+	tp.Start("Pulled", "lists", 2)
+	tp.Add(2)
+	tp.Finish()
+	tp.Finish() // Finishing twice prints nothing more
+	if !strings.Contains(live.String(), "\r\033[K✓ Pulled 2/2 lists (100%) in ") || strings.Count(live.String(), "✓") != 1 {
+		t.Errorf("terminal progress output = %q", live.String())
+	}
+}
+
+// TestSyncSummaryTable verifies the summary table has a row per backend
+func TestSyncSummaryTable(t *testing.T) {
+	var stdout bytes.Buffer
+	printSyncSummaryTable(&stdout, []syncBackendResult{
+		{Name: "todoist", Pushed: 12, New: 3, Updated: 5, Elapsed: 1200 * time.Millisecond},
+		{Name: "nextcloud", ReadOnly: true, Queued: 2, Deleted: 1},
+	}, 2*time.Second)
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected a header, 2 rows and a total, got:\n%s", stdout.String())
+	}
+	if fields := strings.Fields(lines[1]); !slices.Equal(fields, []string{"todoist", "12", "0", "3", "5", "0", "1.2s"}) {
+		t.Errorf("unexpected row %q", lines[1])
+	}
+	if !strings.Contains(lines[2], "read-only (2 queued)") || lines[3] != "Sync completed in 2s" {
+		t.Errorf("unexpected output:\n%s", stdout.String())
+	}
+}
+
+func TestAskAnalyticsConsentWritesChoice(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte("default_backend: sqlite\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &Config{ConfigPath: configPath}
+	if !analyticsConsentPending(cfg) {
+		t.Fatal("consent should be pending without analytics.enabled")
+	}
+
+	var stdout bytes.Buffer
+	askAnalyticsConsent(cfg, strings.NewReader("n\n"), &stdout)
+	if !strings.Contains(stdout.String(), "Analytics disabled") {
+		t.Errorf("unexpected output: %s", stdout.String())
+	}
+	appConfig, err := config.LoadFromPath(configPath)
+	if err != nil {
+		t.Fatalf("LoadFromPath: %v", err)
+	}
+	if appConfig.Analytics.Enabled {
+		t.Error("analytics.enabled should be false after answering n")
+	}
+	if analyticsConsentPending(cfg) {
+		t.Error("consent should not be asked again once answered")
+	}
+
+	// An empty answer accepts the default
+	if err := os.WriteFile(configPath, []byte("default_backend: sqlite\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	askAnalyticsConsent(cfg, strings.NewReader("\n"), &stdout)
+	if appConfig, _ := config.LoadFromPath(configPath); appConfig == nil || !appConfig.Analytics.Enabled {
+		t.Error("analytics.enabled should be true after an empty answer")
+	}
+}
+
+// TestAnalyticsExportAndPurgeCommands verifies 'analytics export' dumps the recorded events and 'analytics purge' deletes them
+func TestAnalyticsExportAndPurgeCommands(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	if err := os.WriteFile(configPath, []byte("default_backend: sqlite\nanalytics:\n  enabled: true\n"), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	cfg := &Config{
+		DBPath:        filepath.Join(tmpDir, "tasks.db"),
+		ConfigPath:    configPath,
+		AnalyticsPath: filepath.Join(tmpDir, "analytics.db"),
+		NoPrompt:      true,
+	}
+	run := func(args ...string) string {
+		t.Helper()
+		var stdout, stderr bytes.Buffer
+		if exitCode := Execute(args, &stdout, &stderr, cfg); exitCode != 0 {
+			t.Fatalf("%v: expected exit code 0, got %d: stderr=%s", args, exitCode, stderr.String())
+		}
+		return stdout.String()
+	}
+
+	run("Work", "add", "Write report")
+	run("Work", "add", "Review PR")
+
+	var export struct {
+		Events []struct {
+			Command    string `json:"command"`
+			DurationMs int64  `json:"duration_ms"`
+		} `json:"events"`
+		TaskEvents []struct {
+			EventType string `json:"event_type"`
+			ListName  string `json:"list_name"`
+		} `json:"task_events"`
+	}
+	out := run("analytics", "export", "--json")
+	if err := json.Unmarshal([]byte(out), &export); err != nil {
+		t.Fatalf("invalid export JSON: %v\n%s", err, out)
+	}
+	if len(export.Events) < 2 || export.Events[0].Command != "Work" {
+		t.Errorf("expected the two add commands in the export, got %+v", export.Events)
+	}
+	if len(export.TaskEvents) != 2 || export.TaskEvents[0].EventType != "created" || export.TaskEvents[0].ListName != "Work" {
+		t.Errorf("expected two created task events, got %+v", export.TaskEvents)
+	}
+
+	out = run("analytics", "report", "--period", "week")
+	if !strings.Contains(out, "Command Timings (ms)") || !strings.Contains(out, "p99") {
+		t.Errorf("report should list command timing percentiles:\n%s", out)
+	}
+
+	out = run("analytics", "purge", "--older-than", "1d")
+	if !strings.Contains(out, "Deleted 0 analytics events") {
+		t.Errorf("recent events should be kept by --older-than: %s", out)
+	}
+	out = run("analytics", "purge")
+	if !strings.Contains(out, "Deleted ") || strings.Contains(out, "Deleted 0 ") {
+		t.Errorf("purge should delete the recorded events: %s", out)
+	}
+	out = run("analytics", "export")
+	if err := json.Unmarshal([]byte(out), &export); err != nil {
+		t.Fatalf("invalid export JSON: %v\n%s", err, out)
+	}
+	if len(export.TaskEvents) != 0 {
+		t.Errorf("task events left after purge: %+v", export.TaskEvents)
+	}
+}
+
+func TestDaemonRPCHandlerTaskMethods(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	if err := os.WriteFile(configPath, []byte("default_backend: sqlite\n"), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	handler := daemonRPCHandler(&Config{
+		DBPath:     filepath.Join(tmpDir, "tasks.db"),
+		ConfigPath: configPath,
+	})
+	call := func(method, params string) (json.RawMessage, error) {
+		t.Helper()
+		result, err := handler(context.Background(), method, json.RawMessage(params))
+		if err != nil {
+			return nil, err
+		}
+		return result.(json.RawMessage), nil
+	}
+
+	if _, err := call("lists.create", `{"name": "Work"}`); err != nil {
+		t.Fatalf("lists.create failed: %v", err)
+	}
+	result, err := call("tasks.create", `{"list": "Work", "summary": "Write report", "priority": 2, "tags": ["docs"]}`)
+	if err != nil {
+		t.Fatalf("tasks.create failed: %v", err)
+	}
+	var created struct {
+		Task struct {
+			UID string `json:"uid"`
+		} `json:"task"`
+	}
+	if err := json.Unmarshal(result, &created); err != nil || created.Task.UID == "" {
+		t.Fatalf("tasks.create returned no uid: %s", result)
+	}
+
+	params := fmt.Sprintf(`{"list": "Work", "uid": %q, "summary": "Write final report"}`, created.Task.UID)
+	if _, err := call("tasks.update", params); err != nil {
+		t.Fatalf("tasks.update failed: %v", err)
+	}
+	if _, err := call("tasks.complete", fmt.Sprintf(`{"list": "Work", "uid": %q}`, created.Task.UID)); err != nil {
+		t.Fatalf("tasks.complete failed: %v", err)
+	}
+
+	result, err = call("tasks.list", `{"list": "Work", "status": "DONE"}`)
+	if err != nil {
+		t.Fatalf("tasks.list failed: %v", err)
+	}
+	if !strings.Contains(string(result), `"summary":"Write final report"`) || !strings.Contains(string(result), `"priority":2`) {
+		t.Errorf("tasks.list: unexpected result %s", result)
+	}
+
+	// Missing params and failing commands map to JSON-RPC errors
+	var rpcErr *daemon.RPCError
+	if _, err := call("tasks.create", `{"list": "Work"}`); !errors.As(err, &rpcErr) || rpcErr.Code != daemon.RPCInvalidParams {
+		t.Errorf("tasks.create without summary: expected invalid params, got %v", err)
+	}
+	if _, err := call("tasks.list", `{"list": "Missing"}`); !errors.As(err, &rpcErr) || rpcErr.Code != daemon.RPCCommandFailed || !strings.Contains(rpcErr.Message, "not found") {
+		t.Errorf("tasks.list on a missing list: expected command failure, got %v", err)
+	}
+	if _, err := call("cli.run", `{"args": ["shell"]}`); !errors.As(err, &rpcErr) || rpcErr.Code != daemon.RPCInvalidParams {
+		t.Errorf("cli.run shell: expected invalid params, got %v", err)
+	}
+}
+
+// TestSendScheduledDigest verifies the daemon's digest job sends once per day after the digest time
+func TestSendScheduledDigest(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	configContent := "default_backend: sqlite\nreminder:\n  log_notification: true\n  digest:\n    enabled: true\n    time: \"07:30\"\n"
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	logPath := filepath.Join(tmpDir, "notifications.log")
+	cfg := &Config{
+		DBPath:              filepath.Join(tmpDir, "test.db"),
+		ConfigPath:          configPath,
+		NotificationLogPath: logPath,
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := Execute([]string{"-y", "Work", "add", "Pay rent", "--due-date", "today"}, &stdout, &stderr, cfg); code != 0 {
+		t.Fatalf("add failed: %s", stderr.String())
+	}
+
+	now := time.Now()
+	before := time.Date(now.Year(), now.Month(), now.Day(), 7, 0, 0, 0, time.Local)
+	after := before.Add(time.Hour)
+	for _, at := range []time.Time{before, after, after.Add(time.Minute)} {
+		if err := sendScheduledDigest(cfg, at); err != nil {
+			t.Fatalf("sendScheduledDigest(%v) failed: %v", at, err)
+		}
+	}
+
+	data, _ := os.ReadFile(logPath)
+	if got := strings.Count(string(data), "[DIGEST]"); got != 1 {
+		t.Errorf("expected one digest after the digest time, got %d in log:\n%s", got, data)
+	}
+	if !strings.Contains(string(data), "Pay rent") {
+		t.Errorf("expected the digest to list the task due today, got:\n%s", data)
+	}
+}
+
+// linkedRemote is a remote whose tasks have web pages
+type linkedRemote struct {
+	*sqlite.Backend
+}
+
+func (r *linkedRemote) TaskURL(listID, taskID string) string {
+	return "https://tasks.example.com/" + listID + "/" + taskID
+}
+
+// TestOpenTaskResolvesLinksAndLaunchesBrowser verifies UIDs and todoat:// links resolve
+// to the task, that the browser gets the backend's address, and that backends
+// without web pages are reported
+func TestOpenTaskResolvesLinksAndLaunchesBrowser(t *testing.T) {
+	ctx := context.Background()
+	db, err := sqlite.New(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = db.Close() }()
+	work, _ := db.CreateList(ctx, "Work")
+	home, _ := db.CreateList(ctx, "Home")
+	task, _ := db.CreateTask(ctx, home.ID, &backend.Task{Summary: "Water plants"})
+
+	var opened []string
+	defer func(orig func(string) error) { openInBrowser = orig }(openInBrowser)
+	openInBrowser = func(url string) error {
+		opened = append(opened, url)
+		return nil
+	}
+
+	be := &linkedRemote{Backend: db}
+	// The link names the list the task was in before it moved to Home
+	for _, ref := range []string{task.ID, backend.TaskLink("Work", task.ID), backend.TaskLink("Home", task.ID)} {
+		list, found, err := resolveTaskReference(ctx, be, ref)
+		if err != nil {
+			t.Fatalf("resolveTaskReference(%q) error: %v", ref, err)
+		}
+		if list.ID != home.ID || found.ID != task.ID {
+			t.Errorf("resolveTaskReference(%q) = %s/%s, want Home/%s", ref, list.Name, found.ID, task.ID)
+		}
+	}
+	if _, _, err := resolveTaskReference(ctx, be, "missing"); err == nil {
+		t.Error("resolveTaskReference of an unknown UID should fail")
+	}
+
+	var stdout bytes.Buffer
+	if err := doOpenTask(ctx, be, home, task, &Config{}, &stdout, false, false); err != nil {
+		t.Fatalf("doOpenTask error: %v", err)
+	}
+	want := "https://tasks.example.com/" + home.ID + "/" + task.ID
+	if len(opened) != 1 || opened[0] != want {
+		t.Errorf("opened %v, want %s", opened, want)
+	}
+
+	stdout.Reset()
+	if err := doOpenTask(ctx, be, home, task, &Config{}, &stdout, true, false); err != nil {
+		t.Fatalf("doOpenTask --print error: %v", err)
+	}
+	if strings.TrimSpace(stdout.String()) != want || len(opened) != 1 {
+		t.Errorf("--print should only print the address, got %q and %d launches", stdout.String(), len(opened))
+	}
+
+	err = doOpenTask(ctx, db, work, task, &Config{}, &stdout, true, false)
+	if err == nil || !strings.Contains(err.Error(), "have no web page") {
+		t.Errorf("expected a no web page error for sqlite, got %v", err)
+	}
+}
+
+// TestRecordPushFailure verifies rejected pushes are recorded against their
+// task at once, temporary failures only after maxPushAttempts, and that a
+// successful push clears the record
+func TestRecordPushFailure(t *testing.T) {
+	for err, want := range map[error]bool{
+		errors.New("failed to create task: status 400"):  true,
+		errors.New("PUT failed with status 422"):         true,
+		errors.New("failed to update task: status 404"):  true,
+		errors.New("failed to create task: status 429"):  false,
+		errors.New("failed to create task: status 401"):  false,
+		errors.New("PUT failed with status 503"):         false,
+		fmt.Errorf("push: %w", context.DeadlineExceeded): false,
+		fmt.Errorf("push: %w", backend.ErrReadOnly):      true,
+		errors.New("connection refused"):                 false,
+	} {
+		if got := isRejectedPush(err); got != want {
+			t.Errorf("isRejectedPush(%q) = %v, want %v", err, got, want)
+		}
+	}
+
+	syncMgr, err := NewSyncManager(filepath.Join(t.TempDir(), "sync.db"))
+	if err != nil {
+		t.Fatalf("NewSyncManager error: %v", err)
+	}
+	defer func() { _ = syncMgr.Close() }()
+	for _, uid := range []string{"rejected", "offline"} {
+		if err := syncMgr.QueueBackendOperation("todoist", uid, uid, "1", "create"); err != nil {
+			t.Fatalf("QueueBackendOperation error: %v", err)
+		}
+	}
+	ops, _ := syncMgr.GetPendingOperations()
+	if len(ops) != 2 {
+		t.Fatalf("expected 2 queued operations, got %d", len(ops))
+	}
+
+	var stderr bytes.Buffer
+	recordPushFailure(syncMgr, "todoist", ops[0], errors.New("failed to create task: status 400"), &stderr)
+	for i := 0; i < maxPushAttempts-1; i++ {
+		recordPushFailure(syncMgr, "todoist", ops[1], errors.New("PUT failed with status 503"), &stderr)
+	}
+	errs, _ := syncMgr.GetSyncErrors()
+	if e := errs["rejected"]; e == nil || e.Attempts != 1 || e.BackendID != "todoist" || e.Operation != "create" || !strings.Contains(e.Message, "status 400") {
+		t.Errorf("rejected push error = %+v", e)
+	}
+	if errs["offline"] != nil {
+		t.Errorf("temporary failure recorded before %d attempts", maxPushAttempts)
+	}
+
+	recordPushFailure(syncMgr, "todoist", ops[1], errors.New("PUT failed with status 503"), &stderr)
+	errs, _ = syncMgr.GetSyncErrors()
+	if e := errs["offline"]; e == nil || e.Attempts != maxPushAttempts {
+		t.Errorf("temporary failure after %d attempts = %+v", maxPushAttempts, e)
+	}
+	ops, _ = syncMgr.GetPendingOperations()
+	if len(ops) != 2 || ops[1].RetryCount != maxPushAttempts {
+		t.Errorf("failed operations should stay queued with their attempts, got %+v", ops)
+	}
+
+	_ = syncMgr.ClearSyncError("rejected")
+	if errs, _ = syncMgr.GetSyncErrors(); errs["rejected"] != nil || len(errs) != 1 {
+		t.Errorf("errors after clearing = %v", errs)
+	}
+}
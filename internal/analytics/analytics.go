@@ -1,20 +1,40 @@
 // Package analytics provides local SQLite-based analytics for tracking command
-// usage, success rates, and backend performance.
+// usage, success rates, backend performance, and task lifecycle events used for
+// productivity reports.
 package analytics
 
 import "os"
 
 // Event represents a single analytics event
 type Event struct {
-	ID         int64
-	Timestamp  int64
-	Command    string
-	Subcommand string
-	Backend    string
-	Success    bool
-	DurationMs int64
-	ErrorType  string
-	Flags      string // JSON string of flags
+	ID         int64  `json:"id"`
+	Timestamp  int64  `json:"timestamp"`
+	Command    string `json:"command"`
+	Subcommand string `json:"subcommand,omitempty"`
+	Backend    string `json:"backend,omitempty"`
+	Success    bool   `json:"success"`
+	DurationMs int64  `json:"duration_ms"`
+	ErrorType  string `json:"error_type,omitempty"`
+	Flags      string `json:"flags,omitempty"` // JSON string of flags
+}
+
+// Task lifecycle event types
+const (
+	TaskEventCreated   = "created"
+	TaskEventCompleted = "completed"
+	TaskEventReopened  = "reopened"
+	TaskEventDeleted   = "deleted"
+)
+
+// TaskEvent represents a single task lifecycle event
+type TaskEvent struct {
+	ID          int64  `json:"id"`
+	Timestamp   int64  `json:"timestamp"`  // When the event happened (Unix seconds)
+	EventType   string `json:"event_type"` // One of the TaskEvent* constants
+	TaskUID     string `json:"task_uid"`
+	ListName    string `json:"list_name,omitempty"`
+	Tags        string `json:"tags,omitempty"`         // Comma-separated tags at the time of the event
+	TaskCreated int64  `json:"task_created,omitempty"` // Task creation time (Unix seconds), 0 if unknown
 }
 
 // IsEnabledFromEnv checks the TODOAT_ANALYTICS_ENABLED environment variable
@@ -395,6 +395,109 @@ func TestTracker_ConcurrentReadWrite(t *testing.T) {
 	}
 }
 
+// TestBuildReport verifies task lifecycle events are summarized into a productivity report
+func TestBuildReport(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "analytics.db")
+
+	tracker, err := NewTracker(dbPath, true)
+	if err != nil {
+		t.Fatalf("NewTracker() error = %v", err)
+	}
+	defer func() { _ = tracker.Close() }()
+
+	now := time.Date(2026, 3, 10, 18, 0, 0, 0, time.Local)
+	day := func(offset, hour int) int64 {
+		return time.Date(2026, 3, 10+offset, hour, 0, 0, 0, time.Local).Unix()
+	}
+
+	// Created before the report period, completed inside it
+	tracker.TrackTaskEvent(TaskEvent{Timestamp: day(-10, 9), EventType: TaskEventCreated, TaskUID: "old", ListName: "Work"})
+	tracker.TrackTaskEvent(TaskEvent{Timestamp: day(-2, 9), EventType: TaskEventCompleted, TaskUID: "old", ListName: "Work", TaskCreated: day(-10, 9)})
+	// Created and completed within the period (2 hours latency)
+	tracker.TrackTaskEvent(TaskEvent{Timestamp: day(-1, 8), EventType: TaskEventCreated, TaskUID: "a", ListName: "Home", Tags: "errand,urgent"})
+	tracker.TrackTaskEvent(TaskEvent{Timestamp: day(-1, 10), EventType: TaskEventCompleted, TaskUID: "a", ListName: "Home", Tags: "errand,urgent", TaskCreated: day(-1, 8)})
+	// Still open
+	tracker.TrackTaskEvent(TaskEvent{Timestamp: day(0, 9), EventType: TaskEventCreated, TaskUID: "b", ListName: "Home", Tags: "errand"})
+	// Created then deleted
+	tracker.TrackTaskEvent(TaskEvent{Timestamp: day(0, 10), EventType: TaskEventCreated, TaskUID: "c", ListName: "Work"})
+	tracker.TrackTaskEvent(TaskEvent{Timestamp: day(0, 11), EventType: TaskEventDeleted, TaskUID: "c", ListName: "Work"})
+
+	start, end, err := ReportRange("week", now)
+	if err != nil {
+		t.Fatalf("ReportRange() error = %v", err)
+	}
+
+	report, err := BuildReport(tracker.db, "week", start, end)
+	if err != nil {
+		t.Fatalf("BuildReport() error = %v", err)
+	}
+
+	if report.Created != 3 || report.Completed != 2 || report.Deleted != 1 {
+		t.Errorf("expected created=3 completed=2 deleted=1, got created=%d completed=%d deleted=%d",
+			report.Created, report.Completed, report.Deleted)
+	}
+	if len(report.CompletedPerDay) != 7 {
+		t.Fatalf("expected 7 days, got %d", len(report.CompletedPerDay))
+	}
+	if got := report.CompletedPerDay[5]; got.Period != "2026-03-09" || got.Count != 1 {
+		t.Errorf("expected 1 completion on 2026-03-09, got %+v", got)
+	}
+	if len(report.CompletedPerWeek) == 0 {
+		t.Error("expected completions per week")
+	}
+
+	// Average of 8 days and 2 hours
+	expectedHours := (8*24.0 + 2) / 2
+	if report.AvgCompletionHours != expectedHours {
+		t.Errorf("expected avg completion %.1fh, got %.1fh", expectedHours, report.AvgCompletionHours)
+	}
+
+	if len(report.ByTag) != 2 || report.ByTag[0].Name != "errand" || report.ByTag[0].Created != 2 || report.ByTag[0].Completed != 1 {
+		t.Errorf("unexpected tag breakdown: %+v", report.ByTag)
+	}
+	if len(report.ByList) != 2 {
+		t.Errorf("expected 2 lists in breakdown, got %+v", report.ByList)
+	}
+
+	// Burn-down: the old task is open at the start, then closes; "b" remains open at the end
+	if len(report.BurnDown) != 7 {
+		t.Fatalf("expected 7 burn-down points, got %d", len(report.BurnDown))
+	}
+	if report.BurnDown[0].Open != 1 {
+		t.Errorf("expected 1 open task on first day, got %d", report.BurnDown[0].Open)
+	}
+	if last := report.BurnDown[6]; last.Date != "2026-03-10" || last.Open != 1 {
+		t.Errorf("expected 1 open task on 2026-03-10, got %+v", last)
+	}
+
+	if _, _, err := ReportRange("decade", now); err == nil {
+		t.Error("expected error for invalid period")
+	}
+}
+
+// TestTrackTaskEvent_Disabled verifies task events are not recorded when analytics is disabled
+func TestTrackTaskEvent_Disabled(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "analytics.db")
+
+	tracker, err := NewTracker(dbPath, false)
+	if err != nil {
+		t.Fatalf("NewTracker() error = %v", err)
+	}
+	defer func() { _ = tracker.Close() }()
+
+	tracker.TrackTaskEvent(TaskEvent{EventType: TaskEventCreated, TaskUID: "a"})
+
+	var count int
+	if err := tracker.db.QueryRow("SELECT COUNT(*) FROM task_events").Scan(&count); err != nil {
+		t.Fatalf("failed to count task events: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("expected no task events when disabled, got %d", count)
+	}
+}
+
 // Helper function to access db for tests
 func (t *Tracker) QueryEvents(query string) ([]Event, error) {
 	rows, err := t.db.Query(query)
@@ -450,3 +553,35 @@ func (t *Tracker) QueryEvents(query string) ([]Event, error) {
 
 	return events, rows.Err()
 }
+
+func TestReportCommandTimingPercentiles(t *testing.T) {
+	tracker, err := NewTracker(filepath.Join(t.TempDir(), "analytics.db"), true)
+	if err != nil {
+		t.Fatalf("NewTracker: %v", err)
+	}
+	defer func() { _ = tracker.Close() }()
+
+	now := time.Now()
+	for i := 1; i <= 100; i++ {
+		tracker.logEvent(Event{Timestamp: now.Unix(), Command: "add", Success: true, DurationMs: int64(i)})
+	}
+	tracker.logEvent(Event{Timestamp: now.Unix(), Command: "sync", Success: true, DurationMs: 1500})
+
+	start, end, _ := ReportRange("week", now)
+	report, err := BuildReport(tracker.db, "week", start, end)
+	if err != nil {
+		t.Fatalf("BuildReport: %v", err)
+	}
+	want := []CommandTiming{
+		{Command: "add", Count: 100, P50Ms: 50, P90Ms: 90, P99Ms: 99, MaxMs: 100},
+		{Command: "sync", Count: 1, P50Ms: 1500, P90Ms: 1500, P99Ms: 1500, MaxMs: 1500},
+	}
+	if len(report.CommandTimings) != len(want) {
+		t.Fatalf("got timings %+v, want %+v", report.CommandTimings, want)
+	}
+	for i := range want {
+		if report.CommandTimings[i] != want[i] {
+			t.Errorf("timing %d = %+v, want %+v", i, report.CommandTimings[i], want[i])
+		}
+	}
+}
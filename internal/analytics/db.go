@@ -29,6 +29,19 @@ CREATE INDEX IF NOT EXISTS idx_command ON events(command);
 CREATE INDEX IF NOT EXISTS idx_backend ON events(backend);
 CREATE INDEX IF NOT EXISTS idx_success ON events(success);
 CREATE INDEX IF NOT EXISTS idx_created_at ON events(created_at);
+
+CREATE TABLE IF NOT EXISTS task_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    timestamp INTEGER NOT NULL,
+    event_type TEXT NOT NULL,
+    task_uid TEXT NOT NULL,
+    list_name TEXT,
+    tags TEXT,
+    task_created INTEGER
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_events_timestamp ON task_events(timestamp);
+CREATE INDEX IF NOT EXISTS idx_task_events_type ON task_events(event_type);
 `
 
 // openDB opens or creates the analytics database at the given path
@@ -0,0 +1,98 @@
+package analytics
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Export is everything the analytics database holds, as written by 'analytics export'
+type Export struct {
+	ExportedAt string      `json:"exported_at"`
+	Since      string      `json:"since,omitempty"`
+	Events     []Event     `json:"events"`
+	TaskEvents []TaskEvent `json:"task_events"`
+}
+
+// ExportData returns the command and task events recorded at or after since
+// (Unix seconds; 0 exports everything), oldest first
+func ExportData(db *sql.DB, since int64, now time.Time) (*Export, error) {
+	export := &Export{
+		ExportedAt: now.UTC().Format(time.RFC3339),
+		Events:     []Event{},
+		TaskEvents: []TaskEvent{},
+	}
+	if since > 0 {
+		export.Since = time.Unix(since, 0).UTC().Format(time.RFC3339)
+	}
+
+	rows, err := db.Query(`
+		SELECT id, timestamp, command, COALESCE(subcommand, ''), COALESCE(backend, ''), success,
+			COALESCE(duration_ms, 0), COALESCE(error_type, ''), COALESCE(flags, '')
+		FROM events
+		WHERE timestamp >= ?
+		ORDER BY timestamp, id
+	`, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query events: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var e Event
+		var success int
+		if err := rows.Scan(&e.ID, &e.Timestamp, &e.Command, &e.Subcommand, &e.Backend, &success, &e.DurationMs, &e.ErrorType, &e.Flags); err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+		e.Success = success != 0
+		export.Events = append(export.Events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading events: %w", err)
+	}
+
+	taskEvents, err := loadTaskEvents(db, now)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range taskEvents {
+		if e.Timestamp >= since {
+			export.TaskEvents = append(export.TaskEvents, e)
+		}
+	}
+	return export, nil
+}
+
+// Purge deletes the command and task events recorded before before (Unix
+// seconds; 0 deletes everything) and returns how many were deleted
+func Purge(db *sql.DB, before int64) (int64, error) {
+	where, args := "", []any{}
+	if before > 0 {
+		where, args = " WHERE timestamp < ?", []any{before}
+	}
+
+	var deleted int64
+	for _, table := range []string{"events", "task_events"} {
+		// Databases created before task events were tracked have no task_events table
+		var name string
+		err := db.QueryRow("SELECT name FROM sqlite_master WHERE type = 'table' AND name = ?", table).Scan(&name)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return deleted, err
+		}
+		result, err := db.Exec("DELETE FROM "+table+where, args...)
+		if err != nil {
+			return deleted, fmt.Errorf("failed to purge %s: %w", table, err)
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return deleted, err
+		}
+		deleted += n
+	}
+
+	// Vacuum so the deleted data is gone from the file too
+	_, _ = db.Exec("VACUUM")
+	return deleted, nil
+}
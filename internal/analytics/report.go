@@ -0,0 +1,304 @@
+package analytics
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Report periods accepted by ReportRange
+var reportPeriodDays = map[string]int{
+	"week":    7,
+	"month":   30,
+	"quarter": 90,
+	"year":    365,
+}
+
+// PeriodCount is a number of events within a day or week
+type PeriodCount struct {
+	Period string `json:"period"` // "2006-01-02" for days, "2006-W01" for ISO weeks
+	Count  int    `json:"count"`
+}
+
+// GroupStats holds created/completed counts for a tag or list
+type GroupStats struct {
+	Name      string `json:"name"`
+	Created   int    `json:"created"`
+	Completed int    `json:"completed"`
+}
+
+// BurnDownPoint is the number of open tasks at the end of a day
+type BurnDownPoint struct {
+	Date string `json:"date"`
+	Open int    `json:"open"`
+}
+
+// CommandTiming holds the duration percentiles of a command, in milliseconds
+type CommandTiming struct {
+	Command string `json:"command"`
+	Count   int    `json:"count"`
+	P50Ms   int64  `json:"p50_ms"`
+	P90Ms   int64  `json:"p90_ms"`
+	P99Ms   int64  `json:"p99_ms"`
+	MaxMs   int64  `json:"max_ms"`
+}
+
+// Report summarizes task lifecycle events over a period
+type Report struct {
+	Period             string          `json:"period"`
+	Start              string          `json:"start"`
+	End                string          `json:"end"`
+	Created            int             `json:"created"`
+	Completed          int             `json:"completed"`
+	Deleted            int             `json:"deleted"`
+	AvgCompletionHours float64         `json:"avg_completion_hours"`
+	CompletedPerDay    []PeriodCount   `json:"completed_per_day"`
+	CompletedPerWeek   []PeriodCount   `json:"completed_per_week"`
+	ByTag              []GroupStats    `json:"by_tag"`
+	ByList             []GroupStats    `json:"by_list"`
+	BurnDown           []BurnDownPoint `json:"burn_down"`
+	CommandTimings     []CommandTiming `json:"command_timings"`
+}
+
+// ReportRange returns the start (local midnight) and end of a report period ending at now.
+func ReportRange(period string, now time.Time) (time.Time, time.Time, error) {
+	days, ok := reportPeriodDays[period]
+	if !ok {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid period: %s (valid: week, month, quarter, year)", period)
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	return today.AddDate(0, 0, -(days - 1)), now, nil
+}
+
+// BuildReport builds a productivity report from the task_events table.
+// Events before start are replayed so the burn-down starts from the correct open count.
+func BuildReport(db *sql.DB, period string, start, end time.Time) (*Report, error) {
+	events, err := loadTaskEvents(db, end)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{
+		Period:           period,
+		Start:            start.Format("2006-01-02"),
+		End:              end.Format("2006-01-02"),
+		CompletedPerDay:  []PeriodCount{},
+		CompletedPerWeek: []PeriodCount{},
+		ByTag:            []GroupStats{},
+		ByList:           []GroupStats{},
+		BurnDown:         []BurnDownPoint{},
+	}
+
+	perDay := make(map[string]int)
+	perWeek := make(map[string]int)
+	byTag := make(map[string]*GroupStats)
+	byList := make(map[string]*GroupStats)
+	var latencyTotal time.Duration
+	latencyCount := 0
+
+	group := func(groups map[string]*GroupStats, name string) *GroupStats {
+		if groups[name] == nil {
+			groups[name] = &GroupStats{Name: name}
+		}
+		return groups[name]
+	}
+
+	open := make(map[string]bool)
+	dayEnd := start.AddDate(0, 0, 1)
+	i := 0
+	for dayStart := start; dayStart.Before(end); dayStart = dayEnd.AddDate(0, 0, -1) {
+		// Replay every event up to the end of this day
+		for ; i < len(events) && events[i].Timestamp < dayEnd.Unix(); i++ {
+			e := events[i]
+			switch e.EventType {
+			case TaskEventCreated, TaskEventReopened:
+				open[e.TaskUID] = true
+			case TaskEventCompleted, TaskEventDeleted:
+				delete(open, e.TaskUID)
+			}
+
+			if e.Timestamp < start.Unix() {
+				continue
+			}
+
+			switch e.EventType {
+			case TaskEventCreated:
+				report.Created++
+				if e.ListName != "" {
+					group(byList, e.ListName).Created++
+				}
+				for _, tag := range splitTags(e.Tags) {
+					group(byTag, tag).Created++
+				}
+			case TaskEventCompleted:
+				report.Completed++
+				ts := time.Unix(e.Timestamp, 0).In(start.Location())
+				perDay[ts.Format("2006-01-02")]++
+				year, week := ts.ISOWeek()
+				perWeek[fmt.Sprintf("%d-W%02d", year, week)]++
+				if e.ListName != "" {
+					group(byList, e.ListName).Completed++
+				}
+				for _, tag := range splitTags(e.Tags) {
+					group(byTag, tag).Completed++
+				}
+				if e.TaskCreated > 0 && e.Timestamp >= e.TaskCreated {
+					latencyTotal += time.Duration(e.Timestamp-e.TaskCreated) * time.Second
+					latencyCount++
+				}
+			case TaskEventDeleted:
+				report.Deleted++
+			}
+		}
+
+		report.BurnDown = append(report.BurnDown, BurnDownPoint{
+			Date: dayEnd.AddDate(0, 0, -1).Format("2006-01-02"),
+			Open: len(open),
+		})
+		dayEnd = dayEnd.AddDate(0, 0, 1)
+	}
+
+	if latencyCount > 0 {
+		report.AvgCompletionHours = (latencyTotal / time.Duration(latencyCount)).Hours()
+	}
+
+	// Completed per day covers every day in the period, including empty ones
+	for _, point := range report.BurnDown {
+		report.CompletedPerDay = append(report.CompletedPerDay, PeriodCount{Period: point.Date, Count: perDay[point.Date]})
+	}
+	for week, count := range perWeek {
+		report.CompletedPerWeek = append(report.CompletedPerWeek, PeriodCount{Period: week, Count: count})
+	}
+	sort.Slice(report.CompletedPerWeek, func(a, b int) bool {
+		return report.CompletedPerWeek[a].Period < report.CompletedPerWeek[b].Period
+	})
+
+	report.ByTag = sortedGroups(byTag)
+	report.ByList = sortedGroups(byList)
+
+	if report.CommandTimings, err = commandTimings(db, start, end); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// commandTimings returns the duration percentiles of each command run between
+// start and end, most used first
+func commandTimings(db *sql.DB, start, end time.Time) ([]CommandTiming, error) {
+	rows, err := db.Query(`
+		SELECT command, duration_ms
+		FROM events
+		WHERE timestamp >= ? AND timestamp <= ? AND duration_ms IS NOT NULL
+	`, start.Unix(), end.Unix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query command durations: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	durations := make(map[string][]int64)
+	for rows.Next() {
+		var command string
+		var ms int64
+		if err := rows.Scan(&command, &ms); err != nil {
+			return nil, fmt.Errorf("failed to scan command duration: %w", err)
+		}
+		durations[command] = append(durations[command], ms)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading command durations: %w", err)
+	}
+
+	timings := make([]CommandTiming, 0, len(durations))
+	for command, ms := range durations {
+		slices.Sort(ms)
+		timings = append(timings, CommandTiming{
+			Command: command,
+			Count:   len(ms),
+			P50Ms:   percentile(ms, 50),
+			P90Ms:   percentile(ms, 90),
+			P99Ms:   percentile(ms, 99),
+			MaxMs:   ms[len(ms)-1],
+		})
+	}
+	sort.Slice(timings, func(a, b int) bool {
+		if timings[a].Count != timings[b].Count {
+			return timings[a].Count > timings[b].Count
+		}
+		return timings[a].Command < timings[b].Command
+	})
+	return timings, nil
+}
+
+// percentile returns the nearest-rank p-th percentile of sorted values
+func percentile(sorted []int64, p int) int64 {
+	rank := (p*len(sorted) + 99) / 100
+	return sorted[max(rank, 1)-1]
+}
+
+// loadTaskEvents returns all task events up to end, oldest first.
+// Databases created before task events were tracked yield no events.
+func loadTaskEvents(db *sql.DB, end time.Time) ([]TaskEvent, error) {
+	var name string
+	err := db.QueryRow("SELECT name FROM sqlite_master WHERE type = 'table' AND name = 'task_events'").Scan(&name)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to check task events table: %w", err)
+	}
+
+	rows, err := db.Query(`
+		SELECT id, timestamp, event_type, task_uid, COALESCE(list_name, ''), COALESCE(tags, ''), COALESCE(task_created, 0)
+		FROM task_events
+		WHERE timestamp <= ?
+		ORDER BY timestamp, id
+	`, end.Unix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query task events: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var events []TaskEvent
+	for rows.Next() {
+		var e TaskEvent
+		if err := rows.Scan(&e.ID, &e.Timestamp, &e.EventType, &e.TaskUID, &e.ListName, &e.Tags, &e.TaskCreated); err != nil {
+			return nil, fmt.Errorf("failed to scan task event: %w", err)
+		}
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading task events: %w", err)
+	}
+
+	return events, nil
+}
+
+// splitTags splits a comma-separated tag string, dropping empty entries
+func splitTags(tags string) []string {
+	var result []string
+	for _, tag := range strings.Split(tags, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
+
+// sortedGroups returns groups ordered by completed count, then name
+func sortedGroups(groups map[string]*GroupStats) []GroupStats {
+	result := make([]GroupStats, 0, len(groups))
+	for _, g := range groups {
+		result = append(result, *g)
+	}
+	sort.Slice(result, func(a, b int) bool {
+		if result[a].Completed != result[b].Completed {
+			return result[a].Completed > result[b].Completed
+		}
+		return result[a].Name < result[b].Name
+	})
+	return result
+}
@@ -92,6 +92,26 @@ func (t *Tracker) logEvent(event Event) {
 		boolToInt(event.Success), event.DurationMs, nullString(event.ErrorType), nullString(event.Flags))
 }
 
+// TrackTaskEvent records a task lifecycle event (created, completed, reopened, deleted).
+// Events are only recorded when analytics is enabled.
+func (t *Tracker) TrackTaskEvent(event TaskEvent) {
+	if !t.enabled {
+		return
+	}
+	if event.Timestamp == 0 {
+		event.Timestamp = time.Now().Unix()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	_, _ = t.db.Exec(`
+		INSERT INTO task_events (timestamp, event_type, task_uid, list_name, tags, task_created)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, event.Timestamp, event.EventType, event.TaskUID, nullString(event.ListName),
+		nullString(event.Tags), nullInt(event.TaskCreated))
+}
+
 // Cleanup removes events older than the specified retention period.
 // Returns the number of deleted events.
 func (t *Tracker) Cleanup(retentionDays int) (int64, error) {
@@ -107,6 +127,13 @@ func (t *Tracker) Cleanup(retentionDays int) (int64, error) {
 		return 0, err
 	}
 
+	// Task lifecycle events follow the same retention period
+	if result, err := t.db.Exec("DELETE FROM task_events WHERE timestamp < ?", cutoff); err == nil {
+		if n, err := result.RowsAffected(); err == nil {
+			deleted += n
+		}
+	}
+
 	// Vacuum to reclaim space
 	_, _ = t.db.Exec("VACUUM")
 
@@ -145,6 +172,14 @@ func nullString(s string) interface{} {
 	return s
 }
 
+// nullInt returns nil for zero values, otherwise the value
+func nullInt(n int64) interface{} {
+	if n == 0 {
+		return nil
+	}
+	return n
+}
+
 // boolToInt converts a bool to 1 (true) or 0 (false)
 func boolToInt(b bool) int {
 	if b {
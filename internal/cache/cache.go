@@ -1,4 +1,5 @@
-// Package cache provides list metadata caching functionality.
+// Package cache provides list metadata caching and the response cache of
+// remote backends in online mode.
 package cache
 
 import (
@@ -11,6 +12,7 @@ type CachedList struct {
 	Name        string    `json:"name"`
 	Description string    `json:"description,omitempty"`
 	Color       string    `json:"color,omitempty"`
+	SharedBy    string    `json:"shared_by,omitempty"`
 	TaskCount   int       `json:"task_count"`
 	Modified    time.Time `json:"modified"`
 }
@@ -0,0 +1,80 @@
+package cache
+
+import (
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// cachedResponse is a stored response with the time it was fetched
+type cachedResponse struct {
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// ResponseCache stores the responses of a remote backend as JSON files in a
+// directory, one file per key, and serves them until they are older than TTL.
+// Errors reading or writing the cache are ignored: the caller then fetches the
+// data again, as on a cache miss.
+type ResponseCache struct {
+	Dir string        // Directory of the cached responses of one backend
+	TTL time.Duration // Age after which a response is fetched again
+}
+
+// NewResponseCache returns a cache of the responses stored in dir
+func NewResponseCache(dir string, ttl time.Duration) *ResponseCache {
+	return &ResponseCache{Dir: dir, TTL: ttl}
+}
+
+// path returns the file of a key; keys may contain any character
+func (c *ResponseCache) path(key string) string {
+	return filepath.Join(c.Dir, url.PathEscape(key)+".json")
+}
+
+// Load decodes the response stored for key into v and reports whether it was
+// found and is younger than TTL
+func (c *ResponseCache) Load(key string, v any) bool {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return false
+	}
+	var resp cachedResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		_ = os.Remove(c.path(key))
+		return false
+	}
+	if time.Since(resp.CreatedAt) > c.TTL {
+		return false
+	}
+	return json.Unmarshal(resp.Data, v) == nil
+}
+
+// Store saves v as the response for key
+func (c *ResponseCache) Store(key string, v any) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	data, err := json.Marshal(cachedResponse{CreatedAt: time.Now(), Data: body})
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(c.Dir, 0700); err != nil {
+		return
+	}
+	_ = os.WriteFile(c.path(key), data, 0600)
+}
+
+// Invalidate deletes the responses stored for keys
+func (c *ResponseCache) Invalidate(keys ...string) {
+	for _, key := range keys {
+		_ = os.Remove(c.path(key))
+	}
+}
+
+// Clear deletes every stored response
+func (c *ResponseCache) Clear() {
+	_ = os.RemoveAll(c.Dir)
+}
@@ -0,0 +1,41 @@
+package cache_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"todoat/internal/cache"
+)
+
+// TestResponseCacheLoadStore verifies responses round-trip, expire and are invalidated
+func TestResponseCacheLoadStore(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "responses", "todoist")
+	c := cache.NewResponseCache(dir, time.Minute)
+
+	var got []string
+	if c.Load("tasks-a/b", &got) {
+		t.Fatal("Load on an empty cache should miss")
+	}
+	c.Store("tasks-a/b", []string{"one", "two"})
+	if !c.Load("tasks-a/b", &got) || len(got) != 2 || got[1] != "two" {
+		t.Fatalf("Load = %v, want [one two]", got)
+	}
+
+	expired := cache.NewResponseCache(dir, 0)
+	if expired.Load("tasks-a/b", &got) {
+		t.Error("Load should miss a response older than the TTL")
+	}
+
+	c.Invalidate("tasks-a/b")
+	if c.Load("tasks-a/b", &got) {
+		t.Error("Load should miss an invalidated response")
+	}
+
+	c.Store("lists", []string{"Work"})
+	c.Clear()
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("Clear should remove the cache directory, got %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// aliasNameRe matches valid alias names
+var aliasNameRe = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// ValidateAliasName checks that an alias name can be typed as a command:
+// lowercase letters, digits, '-' and '_', not starting with '-' or '_'
+func ValidateAliasName(name string) error {
+	if !aliasNameRe.MatchString(name) {
+		return fmt.Errorf("invalid alias name %q: use lowercase letters, digits, '-' and '_'", name)
+	}
+	return nil
+}
+
+// ExpandAlias returns the arguments alias name stands for. An alias whose
+// first word is itself an alias is expanded recursively; an alias that leads
+// back to itself is an error.
+func ExpandAlias(aliases map[string]string, name string) ([]string, error) {
+	return expandAlias(aliases, name, nil)
+}
+
+func expandAlias(aliases map[string]string, name string, chain []string) ([]string, error) {
+	for _, seen := range chain {
+		if seen == name {
+			return nil, fmt.Errorf("alias cycle: %s", strings.Join(append(chain, name), " -> "))
+		}
+	}
+	chain = append(chain, name)
+
+	words, err := SplitArgs(aliases[name])
+	if err != nil {
+		return nil, fmt.Errorf("alias %s: %w", name, err)
+	}
+	if len(words) == 0 {
+		return nil, fmt.Errorf("alias %s is empty", name)
+	}
+	if _, ok := aliases[words[0]]; !ok {
+		return words, nil
+	}
+	head, err := expandAlias(aliases, words[0], chain)
+	if err != nil {
+		return nil, err
+	}
+	return append(head, words[1:]...), nil
+}
+
+// SplitArgs splits s into words like a POSIX shell does, without expansions:
+// words are separated by whitespace, quotes group words, and a backslash
+// escapes the next character outside single quotes.
+func SplitArgs(s string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+	for _, r := range s {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inWord = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if escaped || quote != 0 {
+		return nil, fmt.Errorf("unterminated quote or escape in %q", s)
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestExpandAlias(t *testing.T) {
+	aliases := map[string]string{
+		"wip":   "Work get -s IN-PROGRESS",
+		"tom":   `add --due-date tomorrow --tag "next up"`,
+		"mywip": "wip --json",
+		"a":     "b one",
+		"b":     "c two",
+		"c":     "a three",
+		"blank": "  ",
+	}
+
+	tests := []struct {
+		name    string
+		want    []string
+		wantErr string
+	}{
+		{name: "wip", want: []string{"Work", "get", "-s", "IN-PROGRESS"}},
+		{name: "tom", want: []string{"add", "--due-date", "tomorrow", "--tag", "next up"}},
+		{name: "mywip", want: []string{"Work", "get", "-s", "IN-PROGRESS", "--json"}},
+		{name: "a", wantErr: "alias cycle: a -> b -> c -> a"},
+		{name: "blank", wantErr: "alias blank is empty"},
+	}
+	for _, tt := range tests {
+		got, err := ExpandAlias(aliases, tt.name)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ExpandAlias(%s) error = %v, want %q", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || !slices.Equal(got, tt.want) {
+			t.Errorf("ExpandAlias(%s) = %q, %v, want %q", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestSplitArgs(t *testing.T) {
+	got, err := SplitArgs(`add "Call mom" --tag 'a b' it\'s  ""`)
+	want := []string{"add", "Call mom", "--tag", "a b", "it's", ""}
+	if err != nil || !slices.Equal(got, want) {
+		t.Errorf("SplitArgs() = %q, %v, want %q", got, err, want)
+	}
+	if _, err := SplitArgs(`add "unterminated`); err == nil {
+		t.Error("SplitArgs() expected error for an unterminated quote")
+	}
+}
+
+func TestValidateYAMLAliases(t *testing.T) {
+	issues := ValidateYAML([]byte("aliases:\n  wip: Work get\n  Bad Name: get\n  loop: loop --json\n"))
+	var messages []string
+	for _, issue := range issues {
+		messages = append(messages, issue.Path+": "+issue.Message)
+	}
+	got := strings.Join(messages, "\n")
+	if !strings.Contains(got, `aliases.Bad Name: invalid alias name "Bad Name"`) || !strings.Contains(got, "aliases.loop: alias cycle: loop -> loop") {
+		t.Errorf("unexpected issues:\n%s", got)
+	}
+	if strings.Contains(got, "aliases.wip") {
+		t.Errorf("valid alias reported:\n%s", got)
+	}
+}
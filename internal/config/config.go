@@ -7,9 +7,15 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
+	"todoat/internal/dates"
+	"todoat/internal/features"
+	"todoat/internal/notification"
+	"todoat/internal/utils"
+
 	"gopkg.in/yaml.v3"
 )
 
@@ -24,28 +30,147 @@ func GetSampleConfig() string {
 // UIConfig holds user interface settings
 type UIConfig struct {
 	InteractivePromptForAllTasks bool `yaml:"interactive_prompt_for_all_tasks"`
+	RowNumbers                   bool `yaml:"row_numbers"` // Number the rows of get and accept row numbers as task references in the next command
 }
 
 // LoggingConfig holds logging settings
 type LoggingConfig struct {
-	BackgroundEnabled *bool `yaml:"background_enabled"` // Controls background log file creation (default: true)
+	BackgroundEnabled *bool  `yaml:"background_enabled"` // Controls background log file creation (default: true)
+	Level             string `yaml:"level"`              // Minimum log level: debug, info, warn, error (default: info)
+	Format            string `yaml:"format"`             // Log record format: text or json (default: text)
+	File              string `yaml:"file"`               // Also write log records to this file (default: none)
+	MaxSizeMB         int    `yaml:"max_size_mb"`        // Rotate log files at this size (default: 10)
+	MaxBackups        *int   `yaml:"max_backups"`        // Rotated log files to keep (default: 3)
 }
 
 // Config represents the application configuration
 type Config struct {
-	Backends          BackendsConfig  `yaml:"backends"`
-	DefaultBackend    string          `yaml:"default_backend"`
-	DefaultView       string          `yaml:"default_view"`
-	NoPrompt          bool            `yaml:"no_prompt"`
-	OutputFormat      string          `yaml:"output_format"`
-	Sync              SyncConfig      `yaml:"sync"`
-	AutoDetectBackend bool            `yaml:"auto_detect_backend"`
-	Trash             TrashConfig     `yaml:"trash"`
-	Analytics         AnalyticsConfig `yaml:"analytics"`
-	Reminder          ReminderConfig  `yaml:"reminder"`
-	UI                UIConfig        `yaml:"ui"`
-	Logging           LoggingConfig   `yaml:"logging"`
-	CacheTTL          string          `yaml:"cache_ttl"` // List metadata cache TTL (e.g., "5m", "30s", "10m")
+	Backends           BackendsConfig      `yaml:"backends"`
+	DefaultBackend     string              `yaml:"default_backend"`
+	DefaultView        string              `yaml:"default_view"`
+	DefaultList        string              `yaml:"default_list"` // List used when the list name is omitted (e.g., `todoat add "Task"`)
+	NoPrompt           bool                `yaml:"no_prompt"`
+	OutputFormat       string              `yaml:"output_format"`
+	Sync               SyncConfig          `yaml:"sync"`
+	AutoDetectBackend  bool                `yaml:"auto_detect_backend"`
+	Trash              TrashConfig         `yaml:"trash"`
+	Backup             BackupConfig        `yaml:"backup"`
+	Analytics          AnalyticsConfig     `yaml:"analytics"`
+	Reminder           ReminderConfig      `yaml:"reminder"`
+	UI                 UIConfig            `yaml:"ui"`
+	Logging            LoggingConfig       `yaml:"logging"`
+	CacheTTL           string              `yaml:"cache_ttl"`            // List metadata cache TTL (e.g., "5m", "30s", "10m")
+	PathHierarchy      *bool               `yaml:"path_hierarchy"`       // Parse "/" in added task summaries as a hierarchy path (default: false)
+	StrictParsing      bool                `yaml:"strict_parsing"`       // Fail imports on invalid dates, priorities or malformed rows, and reject ambiguous input dates, instead of guessing
+	AutoCompleteParent bool                `yaml:"auto_complete_parent"` // Complete a parent task when its last open subtask is completed
+	ReopenParent       bool                `yaml:"reopen_parent"`        // Reopen a completed parent task when a subtask is added to it
+	Defaults           map[string][]string `yaml:"defaults"`             // Default flags per command (e.g., "add": ["--priority", "5"])
+	Aliases            map[string]string   `yaml:"aliases"`              // Command shortcuts expanded before parsing (e.g., "wip": "Work get -s IN-PROGRESS")
+	Features           FeaturesConfig      `yaml:"features"`
+	Theme              ThemeConfig         `yaml:"theme"`
+	Dates              DatesConfig         `yaml:"dates"`
+	Timezone           string              `yaml:"timezone"` // IANA zone for parsing and showing dates (e.g. "Europe/Berlin"); empty or "local" uses the system zone
+	Statuses           StatusesConfig      `yaml:"statuses"`
+	Rules              []RuleConfig        `yaml:"rules"` // Automation rules run by `todoat rules run` and before each sync
+	Duplicates         DuplicatesConfig    `yaml:"duplicates"`
+	Statusline         StatuslineConfig    `yaml:"statusline"`
+	Routes             map[string]string   `yaml:"routes"` // Remote backend each list syncs with, by list name (e.g., "Work": "nextcloud")
+}
+
+// StatuslineConfig holds settings of the `todoat statusline` command
+type StatuslineConfig struct {
+	Format string `yaml:"format"` // Template of the line, with placeholders such as {today}, {overdue} and {sync}
+}
+
+// DuplicatesConfig holds duplicate detection settings for adding tasks
+type DuplicatesConfig struct {
+	Check     bool    `yaml:"check"`     // Warn before adding a task similar to an open task in the list
+	Threshold float64 `yaml:"threshold"` // Summary similarity (0-1] from which tasks count as duplicates (default: 0.85)
+}
+
+// RuleConfig defines an automation rule that changes open tasks matching all
+// of its conditions
+type RuleConfig struct {
+	Name  string          `yaml:"name"`
+	Lists []string        `yaml:"lists"` // Lists the rule applies to; empty applies to every list
+	When  []RuleCondition `yaml:"when"`
+	Then  RuleActions     `yaml:"then"`
+}
+
+// RuleCondition is a rule condition, written like a view filter
+type RuleCondition struct {
+	Field    string `yaml:"field"`
+	Operator string `yaml:"operator"` // eq, ne, lt, lte, gt, gte, contains, in, not_in, regex
+	Value    any    `yaml:"value"`
+}
+
+// RuleActions are the changes a rule makes to a matching task
+type RuleActions struct {
+	SetPriority *int     `yaml:"set_priority"` // 0-9; 0 clears the priority
+	SetStatus   string   `yaml:"set_status"`   // TODO, IN-PROGRESS or a custom status
+	AddTags     []string `yaml:"add_tags"`
+	RemoveTags  []string `yaml:"remove_tags"`
+}
+
+// StatusesConfig holds custom task statuses and the allowed status transitions
+type StatusesConfig struct {
+	Custom      []CustomStatusConfig `yaml:"custom"`
+	Transitions map[string][]string  `yaml:"transitions"` // Status -> statuses it may change to; statuses without an entry may change to any
+}
+
+// CustomStatusConfig defines one custom status
+type CustomStatusConfig struct {
+	Name string `yaml:"name"` // Status name, e.g. WAITING
+	Base string `yaml:"base"` // Built-in status stored by backends without custom statuses: TODO (default) or IN-PROGRESS
+}
+
+// DatesConfig holds date display and input settings
+type DatesConfig struct {
+	Format    string `yaml:"format"`     // short (default), iso, us, eu, long, or a Go time layout; us and eu also accept numeric dates as input
+	Relative  bool   `yaml:"relative"`   // Show dates relative to today in views ("in 3 days", "2d overdue")
+	WeekStart string `yaml:"week_start"` // First day of the week: monday (default) or sunday; used by eow, stats and weekly reports
+	DateOrder string `yaml:"date_order"` // Order of day and month in numeric input dates: DMY or MDY (default: from format)
+
+	SkipWeekends bool   `yaml:"skip_weekends"` // Move due dates of recurring tasks off Saturdays and Sundays
+	Holidays     string `yaml:"holidays"`      // Country code (US, CA, GB, DE, FR) or path of an .ics file of days to skip
+}
+
+// ThemeConfig holds terminal color settings. Styles are space-separated color
+// names, 256-color indexes or #rrggbb colors plus attributes (e.g. "bold red");
+// "none" turns coloring off for that element and empty keeps the default.
+type ThemeConfig struct {
+	Color          string `yaml:"color"`           // auto (default: color on terminals), always or never
+	PriorityHigh   string `yaml:"priority_high"`   // Priorities 1-3
+	PriorityMedium string `yaml:"priority_medium"` // Priorities 4-6
+	PriorityLow    string `yaml:"priority_low"`    // Priorities 7-9
+	Overdue        string `yaml:"overdue"`         // Due dates before today on open tasks
+	DueToday       string `yaml:"due_today"`       // Due dates today on open tasks
+	Todo           string `yaml:"todo"`            // Status TODO
+	InProgress     string `yaml:"in_progress"`     // Status IN-PROGRESS
+	Completed      string `yaml:"completed"`       // Status DONE
+	Cancelled      string `yaml:"cancelled"`       // Status CANCELLED
+	Header         string `yaml:"header"`          // Table and list headers
+}
+
+// Styles returns the theme styles by their config key
+func (t ThemeConfig) Styles() map[string]string {
+	return map[string]string{
+		"priority_high":   t.PriorityHigh,
+		"priority_medium": t.PriorityMedium,
+		"priority_low":    t.PriorityLow,
+		"overdue":         t.Overdue,
+		"due_today":       t.DueToday,
+		"todo":            t.Todo,
+		"in_progress":     t.InProgress,
+		"completed":       t.Completed,
+		"cancelled":       t.Cancelled,
+		"header":          t.Header,
+	}
+}
+
+// FeaturesConfig holds feature flag settings
+type FeaturesConfig struct {
+	Experimental []string `yaml:"experimental"` // Experimental features to enable (e.g., ["crdt_sync", "board_view"])
 }
 
 // ReminderConfig holds reminder settings
@@ -54,6 +179,12 @@ type ReminderConfig struct {
 	Intervals       []string `yaml:"intervals"`
 	OSNotification  bool     `yaml:"os_notification"`
 	LogNotification bool     `yaml:"log_notification"`
+
+	Email            notification.EmailNotificationConfig   `yaml:"email"`
+	Webhook          notification.WebhookNotificationConfig `yaml:"webhook"`
+	Push             notification.PushNotificationConfig    `yaml:"push"`
+	IntervalChannels map[string][]string                    `yaml:"interval_channels"` // Per-interval channel selection (e.g., "1h": ["push"])
+	Digest           notification.DigestConfig              `yaml:"digest"`            // Daily digest of tasks due today and overdue, sent by the daemon
 }
 
 // AnalyticsConfig holds analytics settings
@@ -67,16 +198,23 @@ type TrashConfig struct {
 	RetentionDays *int `yaml:"retention_days"`
 }
 
+// BackupConfig holds the settings of automatic database backups
+type BackupConfig struct {
+	Keep *int `yaml:"keep"` // Automatic backups to keep (default: 5, 0 = no automatic backups)
+}
+
 // SyncConfig holds synchronization settings
 type SyncConfig struct {
-	Enabled                bool         `yaml:"enabled"`
-	LocalBackend           string       `yaml:"local_backend"`
-	ConflictResolution     string       `yaml:"conflict_resolution"`
-	OfflineMode            string       `yaml:"offline_mode"`              // auto, online, offline
-	ConnectivityTimeout    string       `yaml:"connectivity_timeout"`      // e.g., "5s"
-	AutoSyncAfterOperation *bool        `yaml:"auto_sync_after_operation"` // sync immediately after operations (default: true when sync enabled)
-	BackgroundPullCooldown string       `yaml:"background_pull_cooldown"`  // cooldown between background pull syncs (default: "30s", minimum: "5s")
-	Daemon                 DaemonConfig `yaml:"daemon"`
+	Enabled                bool           `yaml:"enabled"`
+	LocalBackend           string         `yaml:"local_backend"`
+	ConflictResolution     ConflictPolicy `yaml:"conflict_resolution"`
+	OfflineMode            string         `yaml:"offline_mode"`              // auto, online, offline
+	ConnectivityTimeout    string         `yaml:"connectivity_timeout"`      // e.g., "5s"
+	AutoSyncAfterOperation *bool          `yaml:"auto_sync_after_operation"` // sync immediately after operations (default: true when sync enabled)
+	BackgroundPullCooldown string         `yaml:"background_pull_cooldown"`  // cooldown between background pull syncs (default: "30s", minimum: "5s")
+	PullConcurrency        int            `yaml:"pull_concurrency"`          // lists pulled at once from backends that allow it (default: 4, 1 = one at a time)
+	ResponseCacheTTL       string         `yaml:"response_cache_ttl"`        // how long online mode reuses remote responses (default: "30s", "0" disables)
+	Daemon                 DaemonConfig   `yaml:"daemon"`
 }
 
 // DaemonConfig holds background daemon settings
@@ -90,6 +228,7 @@ type DaemonConfig struct {
 	FileWatcher       bool   `yaml:"file_watcher"`       // Enable file watcher for real-time sync triggers (Issue #41)
 	SmartTiming       bool   `yaml:"smart_timing"`       // Enable smart timing to avoid sync during active editing (Issue #41)
 	DebounceMs        int    `yaml:"debounce_ms"`        // Debounce duration in milliseconds (Issue #41)
+	Notify            bool   `yaml:"notify"`             // Send a notification when a background sync completes or fails
 }
 
 // BackendsConfig holds configuration for all backends
@@ -182,7 +321,7 @@ func Load(configPath string) (*Config, error) {
 	}
 
 	// Read existing config
-	data, err := os.ReadFile(configPath)
+	data, err := ReadFile(configPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
@@ -205,6 +344,9 @@ func Load(configPath string) (*Config, error) {
 	if cfg.Backends.SQLite.Path != "" {
 		cfg.Backends.SQLite.Path = ExpandPath(cfg.Backends.SQLite.Path)
 	}
+	if cfg.Logging.File != "" {
+		cfg.Logging.File = ExpandPath(cfg.Logging.File)
+	}
 
 	return cfg, nil
 }
@@ -267,6 +409,10 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	if err := c.Sync.ConflictResolution.Validate(); err != nil {
+		return fmt.Errorf("invalid sync.conflict_resolution: %w", err)
+	}
+
 	// Validate cache_ttl if specified
 	if c.CacheTTL != "" {
 		_, err := time.ParseDuration(c.CacheTTL)
@@ -275,6 +421,132 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	// Validate reminder channels
+	if err := c.Reminder.validateChannels(); err != nil {
+		return err
+	}
+
+	// Validate logging settings
+	if c.Logging.Level != "" {
+		if _, err := utils.ParseLogLevel(c.Logging.Level); err != nil {
+			return fmt.Errorf("invalid logging.level: %q (must be 'debug', 'info', 'warn' or 'error')", c.Logging.Level)
+		}
+	}
+	if c.Logging.Format != "" && c.Logging.Format != utils.LogFormatText && c.Logging.Format != utils.LogFormatJSON {
+		return fmt.Errorf("invalid logging.format: %q (must be 'text' or 'json')", c.Logging.Format)
+	}
+	if c.Logging.MaxSizeMB < 0 {
+		return fmt.Errorf("invalid logging.max_size_mb: %d (must not be negative)", c.Logging.MaxSizeMB)
+	}
+	if c.Logging.MaxBackups != nil && *c.Logging.MaxBackups < 0 {
+		return fmt.Errorf("invalid logging.max_backups: %d (must not be negative)", *c.Logging.MaxBackups)
+	}
+
+	// Validate experimental feature names
+	if err := features.Validate(c.Features.Experimental); err != nil {
+		return fmt.Errorf("invalid features.experimental: %w", err)
+	}
+
+	// Validate theme color mode and styles
+	switch c.Theme.Color {
+	case "", "auto", "always", "never":
+	default:
+		return fmt.Errorf("invalid theme.color: %q (must be 'auto', 'always' or 'never')", c.Theme.Color)
+	}
+	for key, style := range c.Theme.Styles() {
+		if _, err := utils.ParseStyle(style); err != nil {
+			return fmt.Errorf("invalid theme.%s: %w", key, err)
+		}
+	}
+
+	// Validate date format
+	if _, err := utils.ResolveDateLayout(c.Dates.Format); err != nil {
+		return fmt.Errorf("invalid dates.format: %w", err)
+	}
+	if _, err := utils.ParseWeekStart(c.Dates.WeekStart); err != nil {
+		return fmt.Errorf("invalid dates.week_start: %w", err)
+	}
+	switch c.Dates.DateOrder {
+	case "", utils.DateOrderDMY, utils.DateOrderMDY:
+	default:
+		return fmt.Errorf("invalid dates.date_order: %q (must be 'DMY' or 'MDY')", c.Dates.DateOrder)
+	}
+	if c.Dates.Holidays != "" && !dates.IsICSPath(c.Dates.Holidays) && !dates.IsCountry(c.Dates.Holidays) {
+		return fmt.Errorf("invalid dates.holidays: %q (use a country code: %s, or the path of an .ics file)", c.Dates.Holidays, strings.Join(dates.Countries(), ", "))
+	}
+	if _, err := utils.LoadTimezone(c.Timezone); err != nil {
+		return fmt.Errorf("invalid timezone: %w", err)
+	}
+
+	// Validate default flags: each command's defaults must start with a flag
+	for command, flags := range c.Defaults {
+		if len(flags) > 0 && !strings.HasPrefix(flags[0], "-") {
+			return fmt.Errorf("invalid defaults.%s: %q is not a flag", command, flags[0])
+		}
+	}
+	for name := range c.Aliases {
+		if err := ValidateAliasName(name); err != nil {
+			return fmt.Errorf("invalid aliases.%s: %w", name, err)
+		}
+	}
+	for list, backendName := range c.Routes {
+		if strings.TrimSpace(list) == "" || strings.TrimSpace(backendName) == "" {
+			return fmt.Errorf("invalid routes: %q: %q (list and backend names must not be empty)", list, backendName)
+		}
+	}
+
+	return nil
+}
+
+// validateChannels checks reminder channel settings, per-interval channel names and the digest settings
+func (r *ReminderConfig) validateChannels() error {
+	if r.Email.Enabled && (r.Email.Host == "" || len(r.Email.To) == 0) {
+		return errors.New("reminder.email requires host and to when enabled")
+	}
+	if r.Webhook.Enabled && r.Webhook.URL == "" {
+		return errors.New("reminder.webhook requires url when enabled")
+	}
+	if r.Push.Enabled {
+		switch r.Push.Provider {
+		case "", "ntfy":
+			if r.Push.Topic == "" {
+				return errors.New("reminder.push requires topic for provider ntfy")
+			}
+		case "gotify":
+			if r.Push.URL == "" || r.Push.Token == "" {
+				return errors.New("reminder.push requires url and token for provider gotify")
+			}
+		default:
+			return fmt.Errorf("invalid reminder.push.provider: %q (must be 'ntfy' or 'gotify')", r.Push.Provider)
+		}
+	}
+	for _, retry := range []notification.RetryConfig{r.Email.RetryConfig, r.Webhook.RetryConfig, r.Push.RetryConfig} {
+		if retry.Retries < 0 {
+			return errors.New("reminder channel retries must not be negative")
+		}
+		if retry.RetryDelay != "" {
+			if _, err := time.ParseDuration(retry.RetryDelay); err != nil {
+				return fmt.Errorf("invalid duration for reminder retry_delay: %q", retry.RetryDelay)
+			}
+		}
+	}
+	for interval, channels := range r.IntervalChannels {
+		for _, ch := range channels {
+			if !notification.IsValidChannel(ch) {
+				return fmt.Errorf("invalid reminder.interval_channels.%s: unknown channel %q (valid: %s)", interval, ch, strings.Join(notification.ChannelNames, ", "))
+			}
+		}
+	}
+	if r.Digest.Time != "" {
+		if _, _, err := notification.ParseDigestTime(r.Digest.Time); err != nil {
+			return fmt.Errorf("invalid reminder.digest.time: %w", err)
+		}
+	}
+	for _, ch := range r.Digest.Channels {
+		if !notification.IsValidChannel(ch) {
+			return fmt.Errorf("invalid reminder.digest.channels: unknown channel %q (valid: %s)", ch, strings.Join(notification.ChannelNames, ", "))
+		}
+	}
 	return nil
 }
 
@@ -339,6 +611,37 @@ func (c *Config) GetBackgroundPullCooldownDuration() time.Duration {
 	return duration
 }
 
+// DefaultPullConcurrency is the number of lists a sync pulls at once by default
+const DefaultPullConcurrency = 4
+
+// GetPullConcurrency returns the number of lists a sync pulls at once.
+// Returns DefaultPullConcurrency if not configured.
+func (c *Config) GetPullConcurrency() int {
+	if c.Sync.PullConcurrency <= 0 {
+		return DefaultPullConcurrency
+	}
+	return c.Sync.PullConcurrency
+}
+
+// GetResponseCacheTTL returns how long online mode reuses the lists and tasks
+// fetched from the remote backend. Returns "30s" as default if not configured.
+func (c *Config) GetResponseCacheTTL() string {
+	if c.Sync.ResponseCacheTTL == "" {
+		return "30s"
+	}
+	return c.Sync.ResponseCacheTTL
+}
+
+// GetResponseCacheTTLDuration returns the response cache TTL as a time.Duration.
+// Returns 0 (no caching) if the value cannot be parsed.
+func (c *Config) GetResponseCacheTTLDuration() time.Duration {
+	duration, err := time.ParseDuration(c.GetResponseCacheTTL())
+	if err != nil {
+		return 0
+	}
+	return duration
+}
+
 // IsAutoSyncAfterOperationEnabled returns true if auto-sync after operation is enabled.
 // When sync is enabled and auto_sync_after_operation is not explicitly set, it defaults to true.
 // When sync is disabled, auto-sync is always disabled regardless of the setting.
@@ -388,6 +691,79 @@ func (c *Config) GetTrashRetentionDays() int {
 	return *c.Trash.RetentionDays
 }
 
+// GetBackupKeep returns how many automatic backups to keep before destructive
+// operations (0 means none are made)
+func (c *Config) GetBackupKeep() int {
+	if c.Backup.Keep == nil {
+		return 5
+	}
+	return *c.Backup.Keep
+}
+
+// IsPathHierarchyEnabled returns true if "/" in task summaries should be parsed
+// as a hierarchy path when adding tasks (e.g., "A/B/C" creates parents A and B).
+// Returns false (default) if not configured, so summaries are taken literally.
+func (c *Config) IsPathHierarchyEnabled() bool {
+	if c.PathHierarchy == nil {
+		return false // Default: disabled
+	}
+	return *c.PathHierarchy
+}
+
+// GetDuplicateThreshold returns the summary similarity from which an added
+// task counts as a duplicate. Returns 0.85 (default) if not configured.
+func (c *Config) GetDuplicateThreshold() float64 {
+	if c.Duplicates.Threshold <= 0 {
+		return 0.85
+	}
+	return c.Duplicates.Threshold
+}
+
+// DefaultStatuslineFormat is the statusline template used when none is configured
+const DefaultStatuslineFormat = "{today} due today · {overdue} overdue · sync {sync}"
+
+// GetStatuslineFormat returns the template of the status line.
+// Returns DefaultStatuslineFormat if not configured.
+func (c *Config) GetStatuslineFormat() string {
+	if c.Statusline.Format == "" {
+		return DefaultStatuslineFormat
+	}
+	return c.Statusline.Format
+}
+
+// ListBackend returns the remote backend a list syncs with when routes are
+// configured: the list's route, matched case-insensitively, or else the
+// default backend. Returns "" for lists that stay in the local database.
+func (c *Config) ListBackend(listName string) string {
+	backendName := c.DefaultBackend
+	for list, route := range c.Routes {
+		if strings.EqualFold(list, listName) {
+			backendName = route
+			break
+		}
+	}
+	if backendName == "sqlite" {
+		return ""
+	}
+	return backendName
+}
+
+// RoutedBackends returns the remote backends lists are routed to, including
+// the default backend that unrouted lists sync with, sorted by name
+func (c *Config) RoutedBackends() []string {
+	var names []string
+	for _, backendName := range c.Routes {
+		if backendName != "sqlite" && !slices.Contains(names, backendName) {
+			names = append(names, backendName)
+		}
+	}
+	if c.DefaultBackend != "" && c.DefaultBackend != "sqlite" && !slices.Contains(names, c.DefaultBackend) {
+		names = append(names, c.DefaultBackend)
+	}
+	slices.Sort(names)
+	return names
+}
+
 // IsAnalyticsEnabled returns true if analytics is enabled in config
 func (c *Config) IsAnalyticsEnabled() bool {
 	return c.Analytics.Enabled
@@ -489,6 +865,48 @@ func (c *Config) IsBackgroundLoggingEnabled() bool {
 	return *c.Logging.BackgroundEnabled
 }
 
+// GetLogLevel returns the configured minimum log level name.
+// Returns "info" (default) if not configured.
+func (c *Config) GetLogLevel() string {
+	if c.Logging.Level == "" {
+		return "info"
+	}
+	return c.Logging.Level
+}
+
+// GetLogFormat returns the configured log record format.
+// Returns "text" (default) if not configured.
+func (c *Config) GetLogFormat() string {
+	if c.Logging.Format == "" {
+		return utils.LogFormatText
+	}
+	return c.Logging.Format
+}
+
+// GetLogMaxSize returns the size in bytes at which log files are rotated.
+// Returns 10MB (default) if not configured.
+func (c *Config) GetLogMaxSize() int64 {
+	if c.Logging.MaxSizeMB <= 0 {
+		return utils.DefaultLogMaxSize
+	}
+	return int64(c.Logging.MaxSizeMB) * 1024 * 1024
+}
+
+// GetLogMaxBackups returns how many rotated log files are kept.
+// Returns 3 (default) if not configured.
+func (c *Config) GetLogMaxBackups() int {
+	if c.Logging.MaxBackups == nil {
+		return utils.DefaultLogMaxBackups
+	}
+	return *c.Logging.MaxBackups
+}
+
+// GetDefaultFlags returns the configured default flags for a command
+// (e.g., "add" or "list create"), or nil if none are set.
+func (c *Config) GetDefaultFlags(command string) []string {
+	return c.Defaults[command]
+}
+
 // GetCacheTTL returns the cache TTL setting as a string.
 // Returns "5m" (default) if not configured.
 func (c *Config) GetCacheTTL() string {
@@ -515,7 +933,7 @@ func LoadFromPath(configPath string) (*Config, error) {
 		return nil, fmt.Errorf("config path is required")
 	}
 
-	data, err := os.ReadFile(configPath)
+	data, err := ReadFile(configPath)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, nil // File doesn't exist, return nil config
@@ -657,6 +1075,29 @@ func IsBackendConfigured(raw map[string]interface{}, name string) bool {
 	return ok
 }
 
+// IsBackendReadOnly reports whether the backend with the given name is configured
+// with read_only: true, which blocks every change to its tasks and lists.
+func IsBackendReadOnly(raw map[string]interface{}, name string) bool {
+	backendCfg, _, err := GetBackendConfig(raw, name)
+	if err != nil {
+		return false
+	}
+	readOnly, _ := backendCfg["read_only"].(bool)
+	return readOnly
+}
+
+// BackendDeletesOthersTasks reports whether the backend with the given name is
+// configured with delete_others_tasks: true, which lets sync remove local copies of
+// tasks other users created once they disappear from the shared remote.
+func BackendDeletesOthersTasks(raw map[string]interface{}, name string) bool {
+	backendCfg, _, err := GetBackendConfig(raw, name)
+	if err != nil {
+		return false
+	}
+	deleteOthers, _ := backendCfg["delete_others_tasks"].(bool)
+	return deleteOthers
+}
+
 // LoadWithRaw loads configuration from the specified path and returns both the structured config
 // and the raw map. If the config file doesn't exist, it returns nil for the raw map.
 func LoadWithRaw(configPath string) (*Config, map[string]interface{}, error) {
@@ -676,7 +1117,7 @@ func LoadWithRaw(configPath string) (*Config, map[string]interface{}, error) {
 	}
 
 	// Read existing config
-	data, err := os.ReadFile(configPath)
+	data, err := ReadFile(configPath)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to read config file: %w", err)
 	}
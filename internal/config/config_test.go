@@ -3,8 +3,14 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 	"time"
+
+	"todoat/internal/notification"
+
+	"gopkg.in/yaml.v3"
 )
 
 // =============================================================================
@@ -1320,6 +1326,69 @@ func TestLoggingBackgroundEnabledDefaultValue(t *testing.T) {
 	}
 }
 
+// TestLoggingLevelFormatConfig verifies logging level, format and rotation settings load and validate
+func TestLoggingLevelFormatConfig(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.GetLogLevel() != "info" || cfg.GetLogFormat() != "text" {
+		t.Errorf("defaults = %s/%s, want info/text", cfg.GetLogLevel(), cfg.GetLogFormat())
+	}
+	if cfg.GetLogMaxSize() != 10*1024*1024 || cfg.GetLogMaxBackups() != 3 {
+		t.Errorf("rotation defaults = %d/%d, want 10MB/3", cfg.GetLogMaxSize(), cfg.GetLogMaxBackups())
+	}
+
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	content := "logging:\n  level: debug\n  format: json\n  file: " + filepath.Join(tmpDir, "todoat.log") + "\n  max_size_mb: 2\n  max_backups: 0\n"
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.GetLogLevel() != "debug" || cfg.GetLogFormat() != "json" || cfg.Logging.File == "" {
+		t.Errorf("loaded logging = %+v", cfg.Logging)
+	}
+	if cfg.GetLogMaxSize() != 2*1024*1024 || cfg.GetLogMaxBackups() != 0 {
+		t.Errorf("rotation = %d/%d, want 2MB/0", cfg.GetLogMaxSize(), cfg.GetLogMaxBackups())
+	}
+
+	for _, bad := range []string{"logging:\n  level: trace\n", "logging:\n  format: xml\n"} {
+		if err := os.WriteFile(configPath, []byte(bad), 0644); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+		cfg, err := Load(configPath)
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("Validate() should reject %q", bad)
+		}
+	}
+}
+
+// TestPathHierarchyConfig verifies path_hierarchy defaults to true and can be disabled
+func TestPathHierarchyConfig(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.IsPathHierarchyEnabled() {
+		t.Error("IsPathHierarchyEnabled() should default to false")
+	}
+
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	if err := os.WriteFile(configPath, []byte("path_hierarchy: true\n"), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !cfg.IsPathHierarchyEnabled() {
+		t.Error("IsPathHierarchyEnabled() = false, want true")
+	}
+}
+
 // =============================================================================
 // Tests for Issue 075: Configurable Cache TTL via Config File
 // =============================================================================
@@ -1686,3 +1755,165 @@ func TestCacheTTLValidation(t *testing.T) {
 		})
 	}
 }
+
+// TestValidateDefaults verifies per-command default flags must start with a flag
+func TestValidateDefaults(t *testing.T) {
+	base := func(defaults map[string][]string) *Config {
+		return &Config{
+			DefaultBackend: "sqlite",
+			OutputFormat:   "text",
+			Backends:       BackendsConfig{SQLite: SQLiteConfig{Enabled: true}},
+			Defaults:       defaults,
+		}
+	}
+
+	if err := base(map[string][]string{"add": {"--priority", "5"}, "get": {}}).Validate(); err != nil {
+		t.Errorf("Validate() unexpected error = %v", err)
+	}
+	if err := base(map[string][]string{"add": {"5", "--priority"}}).Validate(); err == nil {
+		t.Error("Validate() expected error for defaults not starting with a flag")
+	}
+
+	cfg := base(map[string][]string{"list create": {"--color", "#FF0000"}})
+	if got := cfg.GetDefaultFlags("list create"); len(got) != 2 || got[0] != "--color" {
+		t.Errorf("GetDefaultFlags() = %v", got)
+	}
+	if got := cfg.GetDefaultFlags("add"); got != nil {
+		t.Errorf("GetDefaultFlags() for unset command = %v, want nil", got)
+	}
+}
+
+func TestValidateTheme(t *testing.T) {
+	base := func(theme ThemeConfig) *Config {
+		return &Config{
+			DefaultBackend: "sqlite",
+			OutputFormat:   "text",
+			Backends:       BackendsConfig{SQLite: SQLiteConfig{Enabled: true}},
+			Theme:          theme,
+		}
+	}
+
+	if err := base(ThemeConfig{Color: "always", PriorityHigh: "bold #ff0000", Todo: "none"}).Validate(); err != nil {
+		t.Errorf("Validate() unexpected error = %v", err)
+	}
+	if err := base(ThemeConfig{Color: "sometimes"}).Validate(); err == nil {
+		t.Error("Validate() expected error for invalid theme.color")
+	}
+	if err := base(ThemeConfig{Overdue: "sparkly"}).Validate(); err == nil || !strings.Contains(err.Error(), "theme.overdue") {
+		t.Errorf("Validate() expected theme.overdue error, got %v", err)
+	}
+}
+
+func TestValidateReminderChannels(t *testing.T) {
+	base := func(reminder ReminderConfig) *Config {
+		return &Config{
+			DefaultBackend: "sqlite",
+			OutputFormat:   "text",
+			Backends:       BackendsConfig{SQLite: SQLiteConfig{Enabled: true}},
+			Reminder:       reminder,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		reminder ReminderConfig
+		wantErr  bool
+	}{
+		{"no channels", ReminderConfig{}, false},
+		{"ntfy with topic", ReminderConfig{Push: notification.PushNotificationConfig{Enabled: true, Topic: "tasks"}}, false},
+		{"ntfy without topic", ReminderConfig{Push: notification.PushNotificationConfig{Enabled: true}}, true},
+		{"gotify without token", ReminderConfig{Push: notification.PushNotificationConfig{Enabled: true, Provider: "gotify", URL: "https://gotify.example.com"}}, true},
+		{"unknown provider", ReminderConfig{Push: notification.PushNotificationConfig{Enabled: true, Provider: "pushover", Topic: "x"}}, true},
+		{"webhook without url", ReminderConfig{Webhook: notification.WebhookNotificationConfig{Enabled: true}}, true},
+		{"email without recipients", ReminderConfig{Email: notification.EmailNotificationConfig{Enabled: true, Host: "smtp.example.com"}}, true},
+		{"invalid retry delay", ReminderConfig{Webhook: notification.WebhookNotificationConfig{Enabled: true, URL: "https://example.com", RetryConfig: notification.RetryConfig{RetryDelay: "soon"}}}, true},
+		{"valid interval channels", ReminderConfig{IntervalChannels: map[string][]string{"1h": {"push", "os"}}}, false},
+		{"unknown interval channel", ReminderConfig{IntervalChannels: map[string][]string{"1h": {"sms"}}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := base(tt.reminder).Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestConflictPolicyForms verifies both forms of sync.conflict_resolution and their field policies
+func TestConflictPolicyForms(t *testing.T) {
+	var scalar SyncConfig
+	if err := yaml.Unmarshal([]byte("conflict_resolution: merge\n"), &scalar); err != nil {
+		t.Fatalf("unmarshal scalar: %v", err)
+	}
+	if got := scalar.ConflictResolution.For("status"); got != ConflictNewestWins {
+		t.Errorf("merge should compare timestamps per field, got %q", got)
+	}
+
+	var perField SyncConfig
+	if err := yaml.Unmarshal([]byte("conflict_resolution: {status: local_wins, description: server_wins, default: keep_both}\n"), &perField); err != nil {
+		t.Fatalf("unmarshal mapping: %v", err)
+	}
+	policy := perField.ConflictResolution
+	if err := policy.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	for field, want := range map[string]string{"status": ConflictLocalWins, "description": ConflictServerWins, "summary": ConflictManual} {
+		if got := policy.For(field); got != want {
+			t.Errorf("For(%q) = %q, want %q", field, got, want)
+		}
+	}
+	if got := policy.String(); got != "default=keep_both, description=server_wins, status=local_wins" {
+		t.Errorf("String() = %q", got)
+	}
+
+	// Round trip keeps the mapping form
+	data, err := yaml.Marshal(perField)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var again SyncConfig
+	if err := yaml.Unmarshal(data, &again); err != nil || again.ConflictResolution.String() != policy.String() {
+		t.Errorf("round trip changed the policy: %s", data)
+	}
+
+	if got := (ConflictPolicy{}).For("summary"); got != ConflictServerWins {
+		t.Errorf("unset policy should default to server_wins, got %q", got)
+	}
+}
+
+func TestListBackendRoutes(t *testing.T) {
+	cfg := &Config{
+		DefaultBackend: "sqlite",
+		OutputFormat:   "text",
+		Backends:       BackendsConfig{SQLite: SQLiteConfig{Enabled: true}},
+		Routes:         map[string]string{"Work": "nextcloud", "Personal": "todoist", "Scratch": "sqlite"},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() unexpected error = %v", err)
+	}
+	for list, want := range map[string]string{"Work": "nextcloud", "personal": "todoist", "Scratch": "", "Groceries": ""} {
+		if got := cfg.ListBackend(list); got != want {
+			t.Errorf("ListBackend(%q) = %q, want %q", list, got, want)
+		}
+	}
+	if got := cfg.RoutedBackends(); !slices.Equal(got, []string{"nextcloud", "todoist"}) {
+		t.Errorf("RoutedBackends() = %v", got)
+	}
+
+	// Unrouted lists sync with a remote default backend
+	cfg.DefaultBackend = "google"
+	if got := cfg.ListBackend("Groceries"); got != "google" {
+		t.Errorf("ListBackend() of an unrouted list = %q, want the default backend", got)
+	}
+	if got := cfg.RoutedBackends(); !slices.Equal(got, []string{"google", "nextcloud", "todoist"}) {
+		t.Errorf("RoutedBackends() = %v", got)
+	}
+
+	cfg.DefaultBackend = "sqlite"
+	cfg.Routes = map[string]string{"Work": ""}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() expected error for a route without backend")
+	}
+}
@@ -0,0 +1,176 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Conflict resolution strategies for sync.conflict_resolution
+const (
+	ConflictServerWins = "server_wins"
+	ConflictLocalWins  = "local_wins"
+	ConflictNewestWins = "newest_wins"
+	ConflictMerge      = "merge"
+	ConflictKeepBoth   = "keep_both"
+	ConflictManual     = "manual"
+)
+
+// ConflictFields are the task fields that accept their own conflict policy
+var ConflictFields = []string{"summary", "description", "status", "priority", "categories"}
+
+// conflictStrategies are the values accepted as a single strategy for all fields
+var conflictStrategies = []string{ConflictServerWins, ConflictLocalWins, ConflictMerge, ConflictKeepBoth, ConflictNewestWins, ConflictManual}
+
+// conflictFieldPolicies are the values accepted per field in the mapping form
+var conflictFieldPolicies = []string{ConflictServerWins, ConflictLocalWins, ConflictNewestWins, ConflictManual}
+
+// ConflictPolicy is sync.conflict_resolution: either a single strategy for all
+// fields, or a mapping of field to policy with an optional "default" entry:
+//
+//	conflict_resolution: {status: local_wins, description: server_wins, default: newest_wins}
+type ConflictPolicy struct {
+	Default string
+	Fields  map[string]string
+}
+
+// UnmarshalYAML accepts both the scalar and the mapping form
+func (p *ConflictPolicy) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		p.Default = node.Value
+		p.Fields = nil
+		return nil
+	}
+	var m map[string]string
+	if err := node.Decode(&m); err != nil {
+		return err
+	}
+	p.Default = m["default"]
+	delete(m, "default")
+	p.Fields = m
+	return nil
+}
+
+// MarshalYAML writes the scalar form when no field has its own policy
+func (p ConflictPolicy) MarshalYAML() (interface{}, error) {
+	return p.value(), nil
+}
+
+// MarshalJSON mirrors MarshalYAML
+func (p ConflictPolicy) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.value())
+}
+
+func (p ConflictPolicy) value() interface{} {
+	if len(p.Fields) == 0 {
+		return p.Default
+	}
+	m := make(map[string]string, len(p.Fields)+1)
+	for field, policy := range p.Fields {
+		m[field] = policy
+	}
+	if p.Default != "" {
+		m["default"] = p.Default
+	}
+	return m
+}
+
+// String formats the policy as "server_wins" or "default=newest_wins, status=local_wins"
+func (p ConflictPolicy) String() string {
+	if len(p.Fields) == 0 {
+		return p.Default
+	}
+	var parts []string
+	if p.Default != "" {
+		parts = append(parts, "default="+p.Default)
+	}
+	fields := make([]string, 0, len(p.Fields))
+	for field := range p.Fields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		parts = append(parts, field+"="+p.Fields[field])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// For returns the policy applied to a field during sync. The whole-task
+// strategies map onto field policies: merge compares per-field timestamps
+// like newest_wins, and keep_both leaves the conflict for manual resolution.
+func (p ConflictPolicy) For(field string) string {
+	policy := p.Fields[field]
+	if policy == "" {
+		policy = p.Default
+	}
+	switch policy {
+	case "":
+		return ConflictServerWins
+	case ConflictMerge:
+		return ConflictNewestWins
+	case ConflictKeepBoth:
+		return ConflictManual
+	}
+	return policy
+}
+
+// SetField sets the policy of one field, or the default for field "default"
+func (p *ConflictPolicy) SetField(field, policy string) error {
+	if field == "default" {
+		if err := checkConflictValue(conflictStrategies, policy); err != nil {
+			return err
+		}
+		p.Default = policy
+		return nil
+	}
+	if !isConflictField(field) {
+		return fmt.Errorf("unknown field %q (must be one of: default, %s)", field, strings.Join(ConflictFields, ", "))
+	}
+	if err := checkConflictValue(conflictFieldPolicies, policy); err != nil {
+		return err
+	}
+	if p.Fields == nil {
+		p.Fields = make(map[string]string)
+	}
+	p.Fields[field] = policy
+	return nil
+}
+
+// Validate checks the strategy and every field policy
+func (p ConflictPolicy) Validate() error {
+	if p.Default != "" {
+		if err := checkConflictValue(conflictStrategies, p.Default); err != nil {
+			return err
+		}
+	}
+	for field, policy := range p.Fields {
+		if !isConflictField(field) {
+			return fmt.Errorf("unknown field %q (must be one of: default, %s)", field, strings.Join(ConflictFields, ", "))
+		}
+		if err := checkConflictValue(conflictFieldPolicies, policy); err != nil {
+			return fmt.Errorf("%s: %w", field, err)
+		}
+	}
+	return nil
+}
+
+func isConflictField(field string) bool {
+	for _, f := range ConflictFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+func checkConflictValue(valid []string, value string) error {
+	for _, v := range valid {
+		if v == value {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid value %q (must be one of: %s)", value, strings.Join(valid, ", "))
+}
@@ -0,0 +1,254 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// IncludeKey is the top-level key naming other config files to load before the
+// file that includes them. Its value is a path or a list of paths, relative to
+// the including file unless absolute; ~ and $VAR are expanded.
+const IncludeKey = "include"
+
+// envNameRe matches the name of an environment variable in a ${VAR} reference
+var envNameRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ReadFile reads the configuration file at path with its includes merged in
+// and ${VAR} references replaced by environment variables. Keys of a file
+// override the same keys of the files it includes; mappings are merged key by
+// key. A file without includes or references is returned unchanged, and so is
+// one that is not valid YAML, so the caller reports the syntax error.
+func ReadFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil || len(doc.Content) == 0 {
+		return data, nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode || (!hasInclude(root) && !strings.Contains(string(data), "${")) {
+		return data, nil
+	}
+
+	merged, err := resolveIncludes(path, root, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := interpolateEnv(merged, ""); err != nil {
+		return nil, err
+	}
+	out, err := yaml.Marshal(merged)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge config includes: %w", err)
+	}
+	return out, nil
+}
+
+// hasInclude reports whether a mapping node has an include key
+func hasInclude(root *yaml.Node) bool {
+	keyNode, _ := mappingValue(root, IncludeKey)
+	return keyNode != nil
+}
+
+// resolveIncludes returns root (the content of path) merged over the files it
+// includes. stack holds the files being included, to detect include cycles.
+func resolveIncludes(path string, root *yaml.Node, stack []string) (*yaml.Node, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		abs = filepath.Clean(path)
+	}
+	for _, p := range stack {
+		if p == abs {
+			return nil, fmt.Errorf("config include cycle: %s", strings.Join(append(stack, abs), " -> "))
+		}
+	}
+	stack = append(stack, abs)
+
+	var includes []string
+	content := make([]*yaml.Node, 0, len(root.Content))
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		keyNode, valueNode := root.Content[i], root.Content[i+1]
+		if keyNode.Value != IncludeKey {
+			content = append(content, keyNode, valueNode)
+			continue
+		}
+		paths, err := includePaths(valueNode)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		includes = append(includes, paths...)
+	}
+	own := *root
+	own.Content = content
+
+	merged := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, inc := range includes {
+		incPath := ExpandPath(inc)
+		if !filepath.IsAbs(incPath) {
+			incPath = filepath.Join(filepath.Dir(abs), incPath)
+		}
+		incRoot, err := readIncludedFile(incPath)
+		if err != nil {
+			return nil, fmt.Errorf("%s: include %s: %w", path, inc, err)
+		}
+		resolved, err := resolveIncludes(incPath, incRoot, stack)
+		if err != nil {
+			return nil, err
+		}
+		mergeMapping(merged, resolved)
+	}
+	mergeMapping(merged, &own)
+	return merged, nil
+}
+
+// includePaths returns the paths of an include value: a path or a list of paths
+func includePaths(node *yaml.Node) ([]string, error) {
+	node = resolveAlias(node)
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if node.Tag == "!!null" || node.Value == "" {
+			return nil, nil
+		}
+		return []string{node.Value}, nil
+	case yaml.SequenceNode:
+		paths := make([]string, 0, len(node.Content))
+		for _, item := range node.Content {
+			item = resolveAlias(item)
+			if item.Kind != yaml.ScalarNode || item.Value == "" {
+				return nil, fmt.Errorf("%s must be a path or a list of paths", IncludeKey)
+			}
+			paths = append(paths, item.Value)
+		}
+		return paths, nil
+	default:
+		return nil, fmt.Errorf("%s must be a path or a list of paths", IncludeKey)
+	}
+}
+
+// readIncludedFile parses an included config file into its top-level mapping
+func readIncludedFile(path string) (*yaml.Node, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}, nil
+	}
+	root := resolveAlias(doc.Content[0])
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("configuration must be a mapping of keys to values")
+	}
+	return root, nil
+}
+
+// mergeMapping merges the keys of src into dst. Values of src replace those of
+// dst, except that two mappings are merged recursively.
+func mergeMapping(dst, src *yaml.Node) {
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		keyNode, valueNode := src.Content[i], src.Content[i+1]
+		j := mappingIndex(dst, keyNode.Value)
+		if j < 0 {
+			dst.Content = append(dst.Content, keyNode, valueNode)
+			continue
+		}
+		a, b := resolveAlias(dst.Content[j+1]), resolveAlias(valueNode)
+		if a.Kind == yaml.MappingNode && b.Kind == yaml.MappingNode {
+			merged := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			mergeMapping(merged, a)
+			mergeMapping(merged, b)
+			valueNode = merged
+		}
+		dst.Content[j+1] = valueNode
+	}
+}
+
+// mappingIndex returns the index of key's key node in a mapping node, or -1
+func mappingIndex(node *yaml.Node, key string) int {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// interpolateEnv replaces the ${VAR} references in the scalar values under
+// node. A plain (unquoted) scalar is typed from its new value, as if the value
+// had been written in the file; a quoted one stays a string.
+func interpolateEnv(node *yaml.Node, path string) error {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if err := interpolateEnv(node.Content[i+1], joinPath(path, node.Content[i].Value)); err != nil {
+				return err
+			}
+		}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			if err := interpolateEnv(item, path); err != nil {
+				return err
+			}
+		}
+	case yaml.ScalarNode:
+		if !strings.Contains(node.Value, "${") {
+			return nil
+		}
+		value, err := expandEnvRefs(node.Value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		node.Value = value
+		if node.Style == 0 {
+			node.Tag = ""
+		}
+	}
+	return nil
+}
+
+// expandEnvRefs replaces ${VAR} and ${VAR:-default} references in s; $${ is
+// a literal ${. A variable that is not set is an error unless it has a default,
+// which is also used when the variable is empty.
+func expandEnvRefs(s string) (string, error) {
+	var b strings.Builder
+	for {
+		i := strings.Index(s, "${")
+		if i < 0 {
+			b.WriteString(s)
+			return b.String(), nil
+		}
+		if i > 0 && s[i-1] == '$' {
+			b.WriteString(s[:i-1] + "${")
+			s = s[i+2:]
+			continue
+		}
+		end := strings.Index(s[i:], "}")
+		if end < 0 {
+			return "", fmt.Errorf("unterminated environment reference %q", s[i:])
+		}
+		ref := s[i+2 : i+end]
+		name, def, hasDefault := strings.Cut(ref, ":-")
+		if !envNameRe.MatchString(name) {
+			return "", fmt.Errorf("invalid environment reference ${%s}", ref)
+		}
+		value, ok := os.LookupEnv(name)
+		if hasDefault && value == "" {
+			value, ok = def, true
+		}
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", name)
+		}
+		b.WriteString(s[:i] + value)
+		s = s[i+end+1:]
+	}
+}
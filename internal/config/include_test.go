@@ -0,0 +1,127 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeConfigFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadFileMergesIncludes(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, filepath.Join(dir, "local", "backends.yaml"), `backends:
+  work:
+    type: nextcloud
+    host: cloud.example.com
+    username: alice
+sync:
+  enabled: true
+`)
+	writeConfigFile(t, filepath.Join(dir, "config.yaml"), `include: local/backends.yaml
+default_backend: work
+backends:
+  work:
+    username: bob
+no_prompt: true
+`)
+
+	cfg, raw, err := LoadWithRaw(filepath.Join(dir, "config.yaml"))
+	if err != nil {
+		t.Fatalf("LoadWithRaw: %v", err)
+	}
+	if cfg.DefaultBackend != "work" || !cfg.NoPrompt || !cfg.Sync.Enabled {
+		t.Errorf("unexpected config: default_backend=%q no_prompt=%v sync.enabled=%v", cfg.DefaultBackend, cfg.NoPrompt, cfg.Sync.Enabled)
+	}
+	backendCfg, backendType, err := GetBackendConfig(raw, "work")
+	if err != nil {
+		t.Fatalf("GetBackendConfig: %v", err)
+	}
+	if backendType != "nextcloud" || backendCfg["host"] != "cloud.example.com" || backendCfg["username"] != "bob" {
+		t.Errorf("backend work = %s %v, want nextcloud on cloud.example.com as bob", backendType, backendCfg)
+	}
+	if _, ok := raw[IncludeKey]; ok {
+		t.Error("include key should not be part of the merged config")
+	}
+}
+
+func TestReadFileDetectsIncludeCycles(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, filepath.Join(dir, "a.yaml"), "include: [b.yaml]\n")
+	writeConfigFile(t, filepath.Join(dir, "b.yaml"), "include: a.yaml\n")
+
+	_, err := ReadFile(filepath.Join(dir, "a.yaml"))
+	if err == nil || !strings.Contains(err.Error(), "config include cycle") {
+		t.Fatalf("expected include cycle error, got %v", err)
+	}
+
+	writeConfigFile(t, filepath.Join(dir, "c.yaml"), "include: missing.yaml\n")
+	if _, err := ReadFile(filepath.Join(dir, "c.yaml")); err == nil || !strings.Contains(err.Error(), "include missing.yaml") {
+		t.Fatalf("expected missing include error, got %v", err)
+	}
+}
+
+func TestReadFileInterpolatesEnvironment(t *testing.T) {
+	t.Setenv("TODOAT_TEST_TOKEN", "12345")
+	t.Setenv("TODOAT_TEST_INTERVAL", "90")
+	t.Setenv("TODOAT_TEST_EMPTY", "")
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	writeConfigFile(t, path, `backends:
+  todoist:
+    type: todoist
+    token: "${TODOAT_TEST_TOKEN}"
+    note: costs $${PRICE}
+output_format: ${TODOAT_TEST_EMPTY:-json}
+sync:
+  daemon:
+    interval: ${TODOAT_TEST_INTERVAL}
+`)
+
+	cfg, raw, err := LoadWithRaw(path)
+	if err != nil {
+		t.Fatalf("LoadWithRaw: %v", err)
+	}
+	if cfg.OutputFormat != "json" || cfg.Sync.Daemon.Interval != 90 {
+		t.Errorf("output_format=%q interval=%d, want json and 90", cfg.OutputFormat, cfg.Sync.Daemon.Interval)
+	}
+	backendCfg, _, _ := GetBackendConfig(raw, "todoist")
+	if backendCfg["token"] != "12345" || backendCfg["note"] != "costs ${PRICE}" {
+		t.Errorf("todoist backend = %v", backendCfg)
+	}
+
+	writeConfigFile(t, path, "output_format: ${TODOAT_TEST_UNSET_VARIABLE}\n")
+	if _, err := ReadFile(path); err == nil || !strings.Contains(err.Error(), "output_format: environment variable TODOAT_TEST_UNSET_VARIABLE is not set") {
+		t.Fatalf("expected unset variable error, got %v", err)
+	}
+}
+
+func TestValidateFileWithIncludes(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, filepath.Join(dir, "backends.yaml"), "backends:\n  work:\n    type: todoist\n")
+	path := filepath.Join(dir, "config.yaml")
+	writeConfigFile(t, path, "include: backends.yaml\ndefault_backend: work\nno_prompt: ${TODOAT_TEST_NO_PROMPT:-true}\n")
+
+	issues, err := ValidateFile(path)
+	if err != nil {
+		t.Fatalf("ValidateFile: %v", err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("expected no issues, got %+v", issues)
+	}
+
+	writeConfigFile(t, path, "include: [missing.yaml]\n")
+	issues, _ = ValidateFile(path)
+	if len(issues) != 1 || !strings.Contains(issues[0].Message, "include missing.yaml") {
+		t.Errorf("expected a missing include issue, got %+v", issues)
+	}
+}
@@ -0,0 +1,119 @@
+package config
+
+import "reflect"
+
+// durationPattern matches Go duration strings such as "30s", "5m" or "1h30m"
+const durationPattern = `^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+
+// JSONSchema returns a JSON Schema (draft 2020-12) describing config.yaml,
+// generated from the Config struct and the backend key table used by ValidateYAML.
+func JSONSchema() map[string]interface{} {
+	schema := schemaForType(reflect.TypeOf(Config{}), "")
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	schema["title"] = "todoat configuration"
+
+	properties := schema["properties"].(map[string]interface{})
+	properties["backends"] = map[string]interface{}{
+		"type":                 "object",
+		"additionalProperties": backendSchema(),
+	}
+	properties[IncludeKey] = map[string]interface{}{
+		"oneOf": []interface{}{
+			map[string]interface{}{"type": "string"},
+			map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+		},
+	}
+	// Top-level backend definitions are still accepted for backwards compatibility
+	schema["additionalProperties"] = true
+	return schema
+}
+
+// backendSchema describes a single entry under backends:
+func backendSchema() map[string]interface{} {
+	properties := map[string]interface{}{
+		"type":    map[string]interface{}{"type": "string", "enum": BackendTypes},
+		"enabled": map[string]interface{}{"type": "boolean"},
+	}
+	for _, backendType := range BackendTypes {
+		for key, kind := range backendKeys[backendType] {
+			switch kind {
+			case "bool":
+				properties[key] = map[string]interface{}{"type": "boolean"}
+			case "list":
+				properties[key] = map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}}
+			case "priority_map":
+				properties[key] = map[string]interface{}{
+					"type":                 "object",
+					"additionalProperties": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "integer", "minimum": 0, "maximum": 9}},
+				}
+			default:
+				properties[key] = map[string]interface{}{"type": "string"}
+			}
+		}
+	}
+	return map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+}
+
+// schemaForType converts a Go type at a config path into its JSON Schema representation
+func schemaForType(t reflect.Type, path string) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == reflect.TypeOf(ConflictPolicy{}) {
+		fieldPolicies := make(map[string]interface{}, len(ConflictFields)+1)
+		for _, field := range ConflictFields {
+			fieldPolicies[field] = map[string]interface{}{"type": "string", "enum": conflictFieldPolicies}
+		}
+		fieldPolicies["default"] = map[string]interface{}{"type": "string", "enum": conflictStrategies}
+		return map[string]interface{}{
+			"oneOf": []interface{}{
+				map[string]interface{}{"type": "string", "enum": conflictStrategies},
+				map[string]interface{}{"type": "object", "properties": fieldPolicies, "additionalProperties": false},
+			},
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		schema := map[string]interface{}{"type": "integer"}
+		if nonNegativeKeys[path] {
+			schema["minimum"] = 0
+		}
+		return schema
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		schema := map[string]interface{}{"type": "string"}
+		if values, ok := enumValues[path]; ok {
+			schema["enum"] = values
+		}
+		if durationKeys[path] {
+			schema["pattern"] = durationPattern
+		}
+		return schema
+	case reflect.Slice:
+		return map[string]interface{}{"type": "array", "items": schemaForType(t.Elem(), path)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": schemaForType(t.Elem(), path)}
+	case reflect.Struct:
+		fields := yamlFields(t)
+		properties := make(map[string]interface{}, len(fields))
+		for name, ft := range fields {
+			properties[name] = schemaForType(ft, joinPath(path, name))
+		}
+		return map[string]interface{}{
+			"type":                 "object",
+			"properties":           properties,
+			"additionalProperties": false,
+		}
+	default:
+		return map[string]interface{}{}
+	}
+}
@@ -0,0 +1,583 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"todoat/internal/features"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Validation issue severities
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Validation issue kinds
+const (
+	IssueSyntax             = "syntax"
+	IssueUnknownKey         = "unknown_key"
+	IssueTypeError          = "type_error"
+	IssueInvalidValue       = "invalid_value"
+	IssueUnreachableBackend = "unreachable_backend"
+	IssueDeprecated         = "deprecated"
+)
+
+// ValidationIssue describes a single problem found in a configuration file
+type ValidationIssue struct {
+	Line     int    `json:"line,omitempty"`
+	Path     string `json:"path,omitempty"`
+	Severity string `json:"severity"`
+	Kind     string `json:"kind"`
+	Message  string `json:"message"`
+}
+
+// BackendTypes lists the backend types that can be configured
+var BackendTypes = []string{"sqlite", "todoist", "nextcloud", "google", "mstodo", "issues", "remote", "git", "code", "file", "ics-url"}
+
+// backendKeys maps each backend type to its accepted keys and their value kinds
+// ("bool", "string", "list" or "priority_map"). The "type", "enabled" and "read_only" keys are accepted for every type.
+var backendKeys = map[string]map[string]string{
+	"sqlite":    {"path": "string"},
+	"todoist":   {"api_token": "string", "token": "string", "password_cmd": "string", "token_cmd": "string", "priority_map": "priority_map"},
+	"nextcloud": {"host": "string", "username": "string", "password": "string", "password_cmd": "string", "insecure_skip_verify": "bool", "allow_http": "bool", "suppress_ssl_warning": "bool", "suppress_http_warning": "bool", "delete_others_tasks": "bool"},
+	"google":    {"access_token": "string", "refresh_token": "string", "client_id": "string", "client_secret": "string", "token_url": "string", "password_cmd": "string", "token_cmd": "string"},
+	"mstodo":    {"access_token": "string", "refresh_token": "string", "client_id": "string", "client_secret": "string", "token_url": "string", "password_cmd": "string", "token_cmd": "string", "priority_map": "priority_map"},
+	"issues":    {"provider": "string", "token": "string", "base_url": "string", "repos": "list", "assignee": "string", "milestones": "string", "password_cmd": "string", "token_cmd": "string"},
+	"remote":    {"url": "string", "token": "string", "ca_cert": "string", "allow_http": "bool", "insecure_skip_verify": "bool", "password_cmd": "string", "token_cmd": "string"},
+	"git":       {"work_dir": "string", "file": "string", "fallback_files": "list", "auto_detect": "bool", "auto_commit": "bool"},
+	"code":      {"work_dir": "string", "markers": "list", "exclude": "list", "edit_comments": "bool"},
+	"file":      {"path": "string"},
+	"ics-url":   {"url": "string", "list": "string", "ttl": "string", "cache_path": "string"},
+}
+
+// enumValues lists the accepted values for string settings with a fixed set of choices
+var enumValues = map[string][]string{
+	"output_format":          {"text", "json"},
+	"sync.offline_mode":      {"auto", "online", "offline"},
+	"reminder.push.provider": {"ntfy", "gotify"},
+	"features.experimental":  features.Names(),
+	"logging.level":          {"debug", "info", "warn", "error"},
+	"logging.format":         {"text", "json"},
+	"theme.color":            {"auto", "always", "never"},
+	"dates.week_start":       {"monday", "sunday"},
+	"dates.date_order":       {"DMY", "MDY"},
+}
+
+// durationKeys lists string settings parsed with time.ParseDuration
+var durationKeys = map[string]bool{
+	"cache_ttl":                     true,
+	"sync.connectivity_timeout":     true,
+	"sync.background_pull_cooldown": true,
+	"sync.response_cache_ttl":       true,
+	"sync.daemon.task_timeout":      true,
+	"reminder.email.retry_delay":    true,
+	"reminder.webhook.retry_delay":  true,
+	"reminder.push.retry_delay":     true,
+}
+
+// nonNegativeKeys lists integer settings that must not be negative
+var nonNegativeKeys = map[string]bool{
+	"analytics.retention_days":       true,
+	"trash.retention_days":           true,
+	"backup.keep":                    true,
+	"sync.daemon.interval":           true,
+	"sync.pull_concurrency":          true,
+	"sync.daemon.heartbeat_interval": true,
+	"logging.max_size_mb":            true,
+	"logging.max_backups":            true,
+}
+
+// checkStringValue validates a string setting against its enum or duration format
+func checkStringValue(path, value string) error {
+	if values, ok := enumValues[path]; ok {
+		for _, v := range values {
+			if value == v {
+				return nil
+			}
+		}
+		return fmt.Errorf("invalid value %q (must be one of: %s)", value, strings.Join(values, ", "))
+	}
+	if durationKeys[path] {
+		if _, err := time.ParseDuration(value); err != nil {
+			return fmt.Errorf("invalid duration %q (use format like 30s, 5m, 1h)", value)
+		}
+	}
+	return nil
+}
+
+// ValidateFile validates the configuration file at path, and reports includes
+// or environment references that cannot be resolved
+func ValidateFile(path string) ([]ValidationIssue, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	issues := ValidateYAML(data)
+	for _, issue := range issues {
+		if issue.Kind == IssueSyntax {
+			return issues, nil
+		}
+	}
+	if _, err := ReadFile(path); err != nil {
+		issues = append(issues, ValidationIssue{Severity: SeverityError, Kind: IssueInvalidValue, Message: err.Error()})
+	}
+	return issues, nil
+}
+
+// yamlLineRe extracts the line number from yaml.v3 error messages
+var yamlLineRe = regexp.MustCompile(`line (\d+)`)
+
+// ValidateYAML checks configuration YAML against the config schema, reporting
+// syntax errors, unknown keys, type errors, invalid values, unreachable backend
+// definitions and deprecated options with their line numbers.
+func ValidateYAML(data []byte) []ValidationIssue {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		issue := ValidationIssue{Severity: SeverityError, Kind: IssueSyntax, Message: strings.TrimPrefix(err.Error(), "yaml: ")}
+		if m := yamlLineRe.FindStringSubmatch(err.Error()); m != nil {
+			issue.Line, _ = strconv.Atoi(m[1])
+		}
+		return []ValidationIssue{issue}
+	}
+	if len(doc.Content) == 0 {
+		return nil
+	}
+
+	v := &validator{}
+	root := resolveAlias(doc.Content[0])
+	if root.Kind != yaml.MappingNode {
+		v.add(root, "", SeverityError, IssueTypeError, "configuration must be a mapping of keys to values")
+		return v.issues
+	}
+
+	v.walkStruct(root, reflect.TypeOf(Config{}), "")
+	v.checkBackends(root)
+	v.checkSemantics(data, root)
+
+	sort.SliceStable(v.issues, func(i, j int) bool { return v.issues[i].Line < v.issues[j].Line })
+	return v.issues
+}
+
+// validator collects issues while walking the YAML tree
+type validator struct {
+	issues []ValidationIssue
+}
+
+func (v *validator) add(node *yaml.Node, path, severity, kind, message string) {
+	v.issues = append(v.issues, ValidationIssue{Line: node.Line, Path: path, Severity: severity, Kind: kind, Message: message})
+}
+
+func (v *validator) hasErrors() bool {
+	for _, issue := range v.issues {
+		if issue.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	return node
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// yamlFields returns a struct's fields keyed by YAML name, flattening inline fields
+func yamlFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		tag := f.Tag.Get("yaml")
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "-" {
+			continue
+		}
+		if opts == "inline" {
+			for k, ft := range yamlFields(f.Type) {
+				fields[k] = ft
+			}
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+// walkStruct validates a mapping node against a struct type
+func (v *validator) walkStruct(node *yaml.Node, t reflect.Type, path string) {
+	node = resolveAlias(node)
+	if node.Kind != yaml.MappingNode {
+		if node.Tag != "!!null" {
+			v.add(node, path, SeverityError, IssueTypeError, "expected a mapping")
+		}
+		return
+	}
+
+	fields := yamlFields(t)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, valueNode := node.Content[i], node.Content[i+1]
+		key := keyNode.Value
+		keyPath := joinPath(path, key)
+
+		// Backend sections are free-form and checked by checkBackends
+		if keyPath == "backends" {
+			continue
+		}
+		if keyPath == IncludeKey {
+			if _, err := includePaths(valueNode); err != nil {
+				v.add(valueNode, keyPath, SeverityError, IssueTypeError, err.Error())
+			}
+			continue
+		}
+
+		ft, ok := fields[key]
+		if !ok {
+			if path == "" && isLegacyBackendDefinition(key, valueNode) {
+				continue // Reported by checkBackends
+			}
+			v.add(keyNode, keyPath, SeverityWarning, IssueUnknownKey, fmt.Sprintf("unknown key %q (ignored)", key))
+			continue
+		}
+		v.walkValue(valueNode, ft, keyPath)
+	}
+}
+
+// walkValue validates a node against an arbitrary field type
+func (v *validator) walkValue(node *yaml.Node, t reflect.Type, path string) {
+	node = resolveAlias(node)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if node.Tag == "!!null" {
+		return
+	}
+
+	if t == reflect.TypeOf(ConflictPolicy{}) {
+		v.checkConflictPolicy(node, path)
+		return
+	}
+	if t.Kind() == reflect.Interface {
+		return // Free-form values such as rule condition values
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		v.walkStruct(node, t, path)
+	case reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			v.add(node, path, SeverityError, IssueTypeError, "expected a mapping")
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			v.walkValue(node.Content[i+1], t.Elem(), joinPath(path, node.Content[i].Value))
+		}
+		if path == "defaults" {
+			v.checkDefaults(node)
+		}
+		if path == "aliases" {
+			v.checkAliases(node)
+		}
+	case reflect.Slice:
+		if node.Kind != yaml.SequenceNode {
+			v.add(node, path, SeverityError, IssueTypeError, fmt.Sprintf("expected a list, got %q", node.Value))
+			return
+		}
+		for _, item := range node.Content {
+			v.walkValue(item, t.Elem(), path)
+		}
+	default:
+		v.checkScalar(node, t, path)
+	}
+}
+
+// checkScalar decodes a scalar node into its target type and runs any value checker
+func (v *validator) checkScalar(node *yaml.Node, t reflect.Type, path string) {
+	if node.Kind != yaml.ScalarNode {
+		v.add(node, path, SeverityError, IssueTypeError, fmt.Sprintf("expected %s", kindName(t)))
+		return
+	}
+	if strings.Contains(node.Value, "${") {
+		return // Environment references are resolved when the config is loaded
+	}
+	if err := node.Decode(reflect.New(t).Interface()); err != nil {
+		v.add(node, path, SeverityError, IssueTypeError, fmt.Sprintf("expected %s, got %q", kindName(t), node.Value))
+		return
+	}
+	if nonNegativeKeys[path] && strings.HasPrefix(node.Value, "-") {
+		v.add(node, path, SeverityError, IssueInvalidValue, fmt.Sprintf("invalid value %s (must not be negative)", node.Value))
+		return
+	}
+	if t.Kind() == reflect.String {
+		if err := checkStringValue(path, node.Value); err != nil {
+			v.add(node, path, SeverityError, IssueInvalidValue, err.Error())
+		}
+	}
+}
+
+func kindName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int64, reflect.Int32:
+		return "an integer"
+	case reflect.Float64, reflect.Float32:
+		return "a number"
+	case reflect.String:
+		return "a string"
+	case reflect.Slice:
+		return "a list"
+	default:
+		return "a mapping"
+	}
+}
+
+// checkConflictPolicy validates sync.conflict_resolution in its scalar or per-field form
+func (v *validator) checkConflictPolicy(node *yaml.Node, path string) {
+	if node.Kind != yaml.ScalarNode && node.Kind != yaml.MappingNode {
+		v.add(node, path, SeverityError, IssueTypeError, "expected a string or a mapping of field to policy")
+		return
+	}
+	if node.Kind == yaml.ScalarNode {
+		if err := checkConflictValue(conflictStrategies, node.Value); err != nil {
+			v.add(node, path, SeverityError, IssueInvalidValue, err.Error())
+		}
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, valueNode := node.Content[i], resolveAlias(node.Content[i+1])
+		var p ConflictPolicy
+		if err := p.SetField(keyNode.Value, valueNode.Value); err != nil {
+			v.add(valueNode, joinPath(path, keyNode.Value), SeverityError, IssueInvalidValue, err.Error())
+		}
+	}
+}
+
+// checkDefaults verifies that each command's default flags start with a flag
+func (v *validator) checkDefaults(node *yaml.Node) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		flags := resolveAlias(node.Content[i+1])
+		if flags.Kind == yaml.SequenceNode && len(flags.Content) > 0 && !strings.HasPrefix(flags.Content[0].Value, "-") {
+			v.add(flags.Content[0], "defaults."+node.Content[i].Value, SeverityError, IssueInvalidValue, fmt.Sprintf("%q is not a flag", flags.Content[0].Value))
+		}
+	}
+}
+
+// checkAliases verifies alias names and that every alias expands without a cycle
+func (v *validator) checkAliases(node *yaml.Node) {
+	aliases := make(map[string]string)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		aliases[node.Content[i].Value] = resolveAlias(node.Content[i+1]).Value
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode := node.Content[i]
+		path := "aliases." + keyNode.Value
+		if err := ValidateAliasName(keyNode.Value); err != nil {
+			v.add(keyNode, path, SeverityError, IssueInvalidValue, err.Error())
+			continue
+		}
+		if _, err := ExpandAlias(aliases, keyNode.Value); err != nil {
+			v.add(node.Content[i+1], path, SeverityError, IssueInvalidValue, err.Error())
+		}
+	}
+}
+
+// mappingValue returns the key and value nodes for key in a mapping node
+func mappingValue(node *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	node = resolveAlias(node)
+	if node.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i], resolveAlias(node.Content[i+1])
+		}
+	}
+	return nil, nil
+}
+
+// isLegacyBackendDefinition reports whether a top-level key looks like a backend
+// defined outside the backends: section (supported for backwards compatibility)
+func isLegacyBackendDefinition(key string, value *yaml.Node) bool {
+	value = resolveAlias(value)
+	if value.Kind != yaml.MappingNode {
+		return false
+	}
+	if _, ok := backendKeys[key]; ok {
+		return true
+	}
+	_, typeNode := mappingValue(value, "type")
+	return typeNode != nil
+}
+
+// checkBackends validates backend definitions and the backends they are referenced by
+func (v *validator) checkBackends(root *yaml.Node) {
+	defined := make(map[string]*yaml.Node)
+
+	if _, backends := mappingValue(root, "backends"); backends != nil {
+		if backends.Kind != yaml.MappingNode {
+			if backends.Tag != "!!null" {
+				v.add(backends, "backends", SeverityError, IssueTypeError, "expected a mapping")
+			}
+		} else {
+			for i := 0; i+1 < len(backends.Content); i += 2 {
+				name := backends.Content[i].Value
+				defined[name] = resolveAlias(backends.Content[i+1])
+				v.validateBackend(backends.Content[i], defined[name], "backends."+name)
+			}
+		}
+	}
+
+	// Top-level backend definitions are still read but deprecated
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		keyNode, valueNode := root.Content[i], root.Content[i+1]
+		if _, known := yamlFields(reflect.TypeOf(Config{}))[keyNode.Value]; known || !isLegacyBackendDefinition(keyNode.Value, valueNode) {
+			continue
+		}
+		name := keyNode.Value
+		if _, shadowed := defined[name]; shadowed {
+			v.add(keyNode, name, SeverityWarning, IssueUnreachableBackend, fmt.Sprintf("top-level backend %q is never used because backends.%s takes precedence", name, name))
+			continue
+		}
+		v.add(keyNode, name, SeverityWarning, IssueDeprecated, fmt.Sprintf("defining backend %q at the top level is deprecated; move it under backends:", name))
+		defined[name] = resolveAlias(valueNode)
+		v.validateBackend(keyNode, defined[name], name)
+	}
+
+	if hasInclude(root) {
+		return // The referenced backends may be defined in included files
+	}
+	v.checkBackendReference(root, "default_backend", "default_backend", defined)
+	if _, sync := mappingValue(root, "sync"); sync != nil {
+		v.checkBackendReference(sync, "local_backend", "sync.local_backend", defined)
+	}
+	if _, routes := mappingValue(root, "routes"); routes != nil && routes.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(routes.Content); i += 2 {
+			list := routes.Content[i].Value
+			v.checkBackendReference(routes, list, "routes."+list, defined)
+		}
+	}
+}
+
+// validateBackend checks a single backend definition's type and keys
+func (v *validator) validateBackend(keyNode, node *yaml.Node, path string) {
+	if node.Kind != yaml.MappingNode {
+		v.add(keyNode, path, SeverityError, IssueTypeError, "backend definition must be a mapping")
+		return
+	}
+
+	name := keyNode.Value
+	backendType := name
+	if _, typeNode := mappingValue(node, "type"); typeNode != nil && typeNode.Value != "" {
+		backendType = typeNode.Value
+	}
+	keys, known := backendKeys[backendType]
+	if !known {
+		v.add(keyNode, path, SeverityError, IssueUnreachableBackend, fmt.Sprintf("backend %q has unknown type %q and can never be used (valid types: %s)", name, backendType, strings.Join(BackendTypes, ", ")))
+		return
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i].Value, resolveAlias(node.Content[i+1])
+		keyPath := path + "." + key
+		kind := keys[key]
+		switch key {
+		case "type":
+			kind = "string"
+		case "enabled", "read_only":
+			kind = "bool"
+		}
+		if kind == "" {
+			v.add(node.Content[i], keyPath, SeverityWarning, IssueUnknownKey, fmt.Sprintf("unknown key %q for %s backend (ignored)", key, backendType))
+			continue
+		}
+
+		switch kind {
+		case "bool":
+			v.checkScalar(value, reflect.TypeOf(true), keyPath)
+		case "string":
+			v.checkScalar(value, reflect.TypeOf(""), keyPath)
+		case "list":
+			v.walkValue(value, reflect.TypeOf([]string{}), keyPath)
+		case "priority_map":
+			v.walkValue(value, reflect.TypeOf(map[string][]int{}), keyPath)
+		}
+
+		if backendType == "nextcloud" && key == "host" && hasEmbeddedCredentials(value.Value) {
+			v.add(value, keyPath, SeverityWarning, IssueDeprecated, "credentials embedded in the host URL are deprecated; use username and 'todoat credentials set'")
+		}
+	}
+}
+
+// hasEmbeddedCredentials reports whether a host URL contains user info (user:pass@host)
+func hasEmbeddedCredentials(host string) bool {
+	if !strings.Contains(host, "://") {
+		host = "https://" + host
+	}
+	u, err := url.Parse(host)
+	return err == nil && u.User != nil
+}
+
+// checkBackendReference verifies that key of section, found at path, names a
+// usable backend. key is passed separately because list names in routes may
+// contain dots.
+func (v *validator) checkBackendReference(section *yaml.Node, key, path string, defined map[string]*yaml.Node) {
+	_, node := mappingValue(section, key)
+	if node == nil || node.Kind != yaml.ScalarNode || node.Value == "" {
+		return
+	}
+
+	name := node.Value
+	def, isDefined := defined[name]
+	if !isDefined {
+		if _, builtin := backendKeys[name]; builtin {
+			return
+		}
+		v.add(node, path, SeverityError, IssueUnreachableBackend, fmt.Sprintf("%s %q is not a built-in backend and is not defined under backends:", path, name))
+		return
+	}
+	if _, enabled := mappingValue(def, "enabled"); enabled != nil && enabled.Value == "false" && path == "default_backend" {
+		v.add(node, path, SeverityError, IssueUnreachableBackend, fmt.Sprintf("default_backend %q is disabled (set backends.%s.enabled: true)", name, name))
+	}
+}
+
+// checkSemantics runs cross-field checks that need the decoded configuration
+func (v *validator) checkSemantics(data []byte, root *yaml.Node) {
+	if v.hasErrors() {
+		return // Decoding would fail or report the same problems again
+	}
+	cfg := &Config{}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return
+	}
+	if err := cfg.Reminder.validateChannels(); err != nil {
+		line := root
+		if keyNode, _ := mappingValue(root, "reminder"); keyNode != nil {
+			line = keyNode
+		}
+		v.add(line, "reminder", SeverityError, IssueInvalidValue, err.Error())
+	}
+}
@@ -0,0 +1,93 @@
+package credentials
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SourceCommand marks credentials produced by an external secret command
+// (e.g. "pass show nextcloud/me", "op read ...", "bw get password ...").
+const SourceCommand Source = "command"
+
+// DefaultCommandTimeout bounds how long a secret command may run. Password
+// managers that unlock interactively (op, bw) need a generous limit.
+const DefaultCommandTimeout = 30 * time.Second
+
+// commandCache holds secret command output for the lifetime of the process,
+// so a command that prompts for an unlock runs at most once per invocation.
+var commandCache = struct {
+	sync.Mutex
+	secrets map[string]string
+}{secrets: make(map[string]string)}
+
+// RunSecretCommand runs command through the shell and returns the first line
+// of its output, which is where pass, op and bw print the secret. Successful
+// results are cached for the process lifetime; failures are not.
+func RunSecretCommand(ctx context.Context, command string, timeout time.Duration) (string, error) {
+	command = strings.TrimSpace(command)
+	if command == "" {
+		return "", fmt.Errorf("secret command is empty")
+	}
+
+	commandCache.Lock()
+	defer commandCache.Unlock()
+	if secret, ok := commandCache.secrets[command]; ok {
+		return secret, nil
+	}
+
+	if timeout <= 0 {
+		timeout = DefaultCommandTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// Don't wait on grandchildren still holding the output pipes after a timeout
+	cmd.WaitDelay = time.Second
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return "", fmt.Errorf("secret command timed out after %v", timeout)
+		}
+		// Report stderr, never stdout, which may hold part of a secret
+		if msg := lastLine(stderr.String()); msg != "" {
+			return "", fmt.Errorf("secret command failed: %w: %s", err, msg)
+		}
+		return "", fmt.Errorf("secret command failed: %w", err)
+	}
+
+	secret, _, _ := strings.Cut(stdout.String(), "\n")
+	secret = strings.TrimRight(secret, "\r")
+	if strings.TrimSpace(secret) == "" {
+		return "", fmt.Errorf("secret command produced no output")
+	}
+	commandCache.secrets[command] = secret
+	return secret, nil
+}
+
+// ResetCommandCache forgets cached secret command output.
+func ResetCommandCache() {
+	commandCache.Lock()
+	defer commandCache.Unlock()
+	commandCache.secrets = make(map[string]string)
+}
+
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
@@ -0,0 +1,101 @@
+package credentials
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func skipWithoutShell(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("secret command tests use POSIX shell syntax")
+	}
+}
+
+// TestRunSecretCommandFirstLine tests that only the first output line is used, like pass show
+func TestRunSecretCommandFirstLine(t *testing.T) {
+	skipWithoutShell(t)
+	ResetCommandCache()
+
+	secret, err := RunSecretCommand(context.Background(), "printf 's3cret pass\\nurl: example.com\\n'", time.Second)
+	if err != nil {
+		t.Fatalf("RunSecretCommand failed: %v", err)
+	}
+	if secret != "s3cret pass" {
+		t.Errorf("expected first line, got %q", secret)
+	}
+}
+
+// TestRunSecretCommandCachesResult tests that a command runs once per process
+func TestRunSecretCommandCachesResult(t *testing.T) {
+	skipWithoutShell(t)
+	ResetCommandCache()
+
+	counter := filepath.Join(t.TempDir(), "runs")
+	command := "echo x >> " + counter + "; echo token"
+	for i := 0; i < 3; i++ {
+		if _, err := RunSecretCommand(context.Background(), command, time.Second); err != nil {
+			t.Fatalf("RunSecretCommand failed: %v", err)
+		}
+	}
+	data, _ := os.ReadFile(counter)
+	if runs := strings.Count(string(data), "x"); runs != 1 {
+		t.Errorf("expected command to run once, ran %d times", runs)
+	}
+}
+
+// TestRunSecretCommandErrors tests failures, timeouts and empty output
+func TestRunSecretCommandErrors(t *testing.T) {
+	skipWithoutShell(t)
+	ResetCommandCache()
+
+	_, err := RunSecretCommand(context.Background(), "echo leaked; echo 'vault is locked' >&2; exit 1", time.Second)
+	if err == nil || !strings.Contains(err.Error(), "vault is locked") {
+		t.Errorf("expected stderr in error, got %v", err)
+	}
+	if err != nil && strings.Contains(err.Error(), "leaked") {
+		t.Error("error must not include command stdout")
+	}
+
+	_, err = RunSecretCommand(context.Background(), "sleep 5", 50*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("expected timeout error, got %v", err)
+	}
+
+	_, err = RunSecretCommand(context.Background(), "true", time.Second)
+	if err == nil || !strings.Contains(err.Error(), "no output") {
+		t.Errorf("expected empty output error, got %v", err)
+	}
+}
+
+// TestManagerGetUsesSecretCommand tests that a configured command takes priority over the keyring
+func TestManagerGetUsesSecretCommand(t *testing.T) {
+	skipWithoutShell(t)
+	ResetCommandCache()
+
+	mockKeyring := NewMockKeyring()
+	_ = mockKeyring.Set("todoat-nextcloud", "myuser", "keyringpass")
+	manager := NewManager(WithKeyring(mockKeyring), WithSecretCommands(map[string]string{"Nextcloud": "echo commandpass"}))
+
+	info, err := manager.Get(context.Background(), "nextcloud", "myuser")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if info.Source != SourceCommand || info.Password != "commandpass" {
+		t.Errorf("expected password from command, got source %s password %q", info.Source, info.Password)
+	}
+
+	failing := NewManager(WithKeyring(mockKeyring), WithSecretCommands(map[string]string{"nextcloud": "exit 3"}))
+	statuses, err := failing.ListBackends(context.Background(), []BackendConfig{{Name: "nextcloud", Username: "myuser"}})
+	if err != nil {
+		t.Fatalf("ListBackends should not fail on a failing command: %v", err)
+	}
+	if statuses[0].HasCredentials || statuses[0].Source != SourceCommand {
+		t.Errorf("failing command should be reported as missing credentials, got %+v", statuses[0])
+	}
+}
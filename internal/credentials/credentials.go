@@ -11,6 +11,7 @@ import (
 	"io"
 	"os"
 	"strings"
+	"time"
 
 	"golang.org/x/term"
 )
@@ -73,7 +74,9 @@ type Keyring interface {
 
 // Manager handles credential operations
 type Manager struct {
-	keyring Keyring
+	keyring        Keyring
+	commands       map[string]string // Secret command per backend (password_cmd)
+	commandTimeout time.Duration
 }
 
 // ManagerOption is a functional option for Manager
@@ -86,10 +89,31 @@ func WithKeyring(k Keyring) ManagerOption {
 	}
 }
 
+// WithSecretCommands sets external secret commands by backend name. A backend
+// with a command gets its credentials from the command instead of the keyring
+// or environment.
+func WithSecretCommands(commands map[string]string) ManagerOption {
+	return func(m *Manager) {
+		for backend, command := range commands {
+			if strings.TrimSpace(command) != "" {
+				m.commands[normalizeBackend(backend)] = command
+			}
+		}
+	}
+}
+
+// WithCommandTimeout sets how long a secret command may run (default DefaultCommandTimeout)
+func WithCommandTimeout(timeout time.Duration) ManagerOption {
+	return func(m *Manager) {
+		m.commandTimeout = timeout
+	}
+}
+
 // NewManager creates a new credential manager
 func NewManager(opts ...ManagerOption) *Manager {
 	m := &Manager{
-		keyring: &systemKeyring{},
+		keyring:  &systemKeyring{},
+		commands: make(map[string]string),
 	}
 	for _, opt := range opts {
 		opt(m)
@@ -114,10 +138,28 @@ func (m *Manager) Set(ctx context.Context, backend, username, password string) e
 	return m.keyring.Set(service, username, password)
 }
 
-// Get retrieves credentials from available sources (keyring first, then env vars)
+// Get retrieves credentials from available sources (secret command if configured,
+// then keyring, then env vars). A failing secret command returns its error along
+// with a not-found CredentialInfo.
 func (m *Manager) Get(ctx context.Context, backend, username string) (*CredentialInfo, error) {
 	backend = normalizeBackend(backend)
 
+	// Priority 0: An explicitly configured secret command
+	if command, ok := m.commands[backend]; ok {
+		info := &CredentialInfo{
+			Source:   SourceCommand,
+			Backend:  backend,
+			Username: username,
+		}
+		password, err := RunSecretCommand(ctx, command, m.commandTimeout)
+		if err != nil {
+			return info, fmt.Errorf("%s: %w", backend, err)
+		}
+		info.Password = password
+		info.Found = true
+		return info, nil
+	}
+
 	// Priority 1: Try keyring
 	service := serviceName(backend)
 	password, err := m.keyring.Get(service, username)
@@ -200,9 +242,10 @@ func (m *Manager) ListBackends(ctx context.Context, backends []BackendConfig) ([
 
 	for _, bc := range backends {
 		info, err := m.Get(ctx, bc.Name, bc.Username)
-		if err != nil {
+		if err != nil && info == nil {
 			return nil, err
 		}
+		// A failing secret command is reported as missing credentials
 
 		statuses = append(statuses, BackendStatus{
 			Backend:        bc.Name,
@@ -0,0 +1,165 @@
+package credentials
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// OAuthAccount is the keyring account holding a backend's structured OAuth
+// credentials, next to plain "token"/username entries in the same service.
+const OAuthAccount = "oauth"
+
+// ExpiryLeeway is how long before expiry an access token is treated as expired,
+// so it is refreshed before requests start failing.
+const ExpiryLeeway = time.Minute
+
+// OAuthToken is an OAuth2 credential with its expiry metadata.
+type OAuthToken struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	TokenType    string    `json:"token_type,omitempty"`
+	Expiry       time.Time `json:"expiry,omitzero"`
+	Scopes       []string  `json:"scopes,omitempty"`
+}
+
+// Expired reports whether the access token has expired or expires within
+// ExpiryLeeway. Tokens without a known expiry never expire.
+func (t *OAuthToken) Expired() bool {
+	return !t.Expiry.IsZero() && time.Now().Add(ExpiryLeeway).After(t.Expiry)
+}
+
+// CanRefresh reports whether the token carries a refresh token.
+func (t *OAuthToken) CanRefresh() bool {
+	return t.RefreshToken != ""
+}
+
+// SetOAuthToken stores a structured OAuth credential as JSON in the keyring.
+func (m *Manager) SetOAuthToken(ctx context.Context, backend string, token *OAuthToken) error {
+	if token == nil || token.AccessToken == "" {
+		return fmt.Errorf("access token is required")
+	}
+	data, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+	return m.keyring.Set(serviceName(backend), OAuthAccount, string(data))
+}
+
+// GetOAuthToken returns the structured OAuth credential stored for a backend,
+// or nil if none is stored.
+func (m *Manager) GetOAuthToken(ctx context.Context, backend string) (*OAuthToken, error) {
+	data, err := m.keyring.Get(serviceName(backend), OAuthAccount)
+	if err != nil {
+		if errors.Is(err, ErrKeyringNotAvailable) {
+			return nil, err
+		}
+		return nil, nil
+	}
+	var token OAuthToken
+	if err := json.Unmarshal([]byte(data), &token); err != nil {
+		return nil, fmt.Errorf("stored OAuth credential for %s is invalid: %w", normalizeBackend(backend), err)
+	}
+	return &token, nil
+}
+
+// DeleteOAuthToken removes a backend's structured OAuth credential. Missing entries are ignored.
+func (m *Manager) DeleteOAuthToken(ctx context.Context, backend string) error {
+	err := m.keyring.Delete(serviceName(backend), OAuthAccount)
+	if err != nil && strings.Contains(err.Error(), "not found") {
+		return nil
+	}
+	return err
+}
+
+// OAuthClient refreshes tokens against an OAuth2 token endpoint.
+type OAuthClient struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	HTTPClient   *http.Client // Defaults to a client with a 30s timeout
+}
+
+// Refresh exchanges a refresh token for a new access token (RFC 6749 section 6).
+// The returned token keeps the old refresh token if the server does not rotate it.
+func (c OAuthClient) Refresh(ctx context.Context, refreshToken string) (*OAuthToken, error) {
+	if refreshToken == "" {
+		return nil, fmt.Errorf("no refresh token available")
+	}
+	form := url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+	}
+	if c.ClientID != "" {
+		form.Set("client_id", c.ClientID)
+	}
+	if c.ClientSecret != "" {
+		form.Set("client_secret", c.ClientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	client := c.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	var tokenResp struct {
+		AccessToken      string `json:"access_token"`
+		RefreshToken     string `json:"refresh_token"`
+		TokenType        string `json:"token_type"`
+		ExpiresIn        int64  `json:"expires_in"`
+		Scope            string `json:"scope"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	_ = json.Unmarshal(body, &tokenResp)
+
+	if resp.StatusCode != http.StatusOK {
+		if tokenResp.Error != "" {
+			msg := tokenResp.Error
+			if tokenResp.ErrorDescription != "" {
+				msg += ": " + tokenResp.ErrorDescription
+			}
+			return nil, fmt.Errorf("token endpoint returned status %d (%s)", resp.StatusCode, msg)
+		}
+		return nil, fmt.Errorf("token endpoint returned status %d", resp.StatusCode)
+	}
+	if tokenResp.AccessToken == "" {
+		return nil, fmt.Errorf("token endpoint response has no access token")
+	}
+
+	token := &OAuthToken{
+		AccessToken:  tokenResp.AccessToken,
+		RefreshToken: tokenResp.RefreshToken,
+		TokenType:    tokenResp.TokenType,
+		Scopes:       strings.Fields(tokenResp.Scope),
+	}
+	if token.RefreshToken == "" {
+		token.RefreshToken = refreshToken
+	}
+	if tokenResp.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second)
+	}
+	return token, nil
+}
@@ -0,0 +1,110 @@
+package credentials
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestOAuthTokenRoundTrip tests that structured OAuth credentials survive the keyring
+func TestOAuthTokenRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	mgr := NewManager(WithKeyring(NewMockKeyring()))
+
+	if token, err := mgr.GetOAuthToken(ctx, "google"); err != nil || token != nil {
+		t.Fatalf("expected no token before storing, got %v, %v", token, err)
+	}
+
+	expiry := time.Now().Add(time.Hour).Truncate(time.Second)
+	in := &OAuthToken{AccessToken: "access", RefreshToken: "refresh", TokenType: "Bearer", Expiry: expiry, Scopes: []string{"tasks"}}
+	if err := mgr.SetOAuthToken(ctx, "google", in); err != nil {
+		t.Fatalf("SetOAuthToken failed: %v", err)
+	}
+
+	out, err := mgr.GetOAuthToken(ctx, "google")
+	if err != nil || out == nil {
+		t.Fatalf("GetOAuthToken failed: %v, %v", out, err)
+	}
+	if out.AccessToken != "access" || out.RefreshToken != "refresh" || !out.Expiry.Equal(expiry) || len(out.Scopes) != 1 {
+		t.Errorf("unexpected token after round trip: %+v", out)
+	}
+
+	if err := mgr.DeleteOAuthToken(ctx, "google"); err != nil {
+		t.Fatalf("DeleteOAuthToken failed: %v", err)
+	}
+	if err := mgr.DeleteOAuthToken(ctx, "google"); err != nil {
+		t.Errorf("deleting a missing token should be ignored, got %v", err)
+	}
+}
+
+// TestOAuthTokenExpired tests expiry checks including the leeway
+func TestOAuthTokenExpired(t *testing.T) {
+	tests := []struct {
+		name   string
+		expiry time.Time
+		want   bool
+	}{
+		{"no expiry", time.Time{}, false},
+		{"valid", time.Now().Add(time.Hour), false},
+		{"within leeway", time.Now().Add(ExpiryLeeway / 2), true},
+		{"past", time.Now().Add(-time.Hour), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := &OAuthToken{AccessToken: "a", Expiry: tt.expiry}
+			if got := token.Expired(); got != tt.want {
+				t.Errorf("Expired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestOAuthClientRefresh tests the refresh grant request and response parsing
+func TestOAuthClientRefresh(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("ParseForm: %v", err)
+		}
+		if r.PostForm.Get("grant_type") != "refresh_token" || r.PostForm.Get("refresh_token") != "old-refresh" || r.PostForm.Get("client_id") != "id" {
+			t.Errorf("unexpected form: %v", r.PostForm)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"new-access","expires_in":3600,"token_type":"Bearer","scope":"a b"}`))
+	}))
+	defer server.Close()
+
+	client := OAuthClient{TokenURL: server.URL, ClientID: "id", ClientSecret: "secret"}
+	token, err := client.Refresh(context.Background(), "old-refresh")
+	if err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	if token.AccessToken != "new-access" {
+		t.Errorf("expected new access token, got %q", token.AccessToken)
+	}
+	if token.RefreshToken != "old-refresh" {
+		t.Errorf("refresh token should be kept when not rotated, got %q", token.RefreshToken)
+	}
+	if token.Expired() || token.Expiry.After(time.Now().Add(2*time.Hour)) {
+		t.Errorf("unexpected expiry %v", token.Expiry)
+	}
+	if strings.Join(token.Scopes, " ") != "a b" {
+		t.Errorf("unexpected scopes %v", token.Scopes)
+	}
+}
+
+// TestOAuthClientRefreshError tests that OAuth error descriptions are surfaced
+func TestOAuthClientRefreshError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":"invalid_grant","error_description":"Token has been revoked"}`))
+	}))
+	defer server.Close()
+
+	_, err := OAuthClient{TokenURL: server.URL}.Refresh(context.Background(), "revoked")
+	if err == nil || !strings.Contains(err.Error(), "Token has been revoked") {
+		t.Errorf("expected error description in error, got %v", err)
+	}
+}
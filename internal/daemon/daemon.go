@@ -5,8 +5,12 @@ package daemon
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"math"
 	"net"
 	"os"
@@ -16,9 +20,11 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 	"todoat/internal/notification"
+	"todoat/internal/utils"
 )
 
 // MaxConsecutiveErrors is the number of consecutive sync failures before the daemon shuts down.
@@ -48,7 +54,7 @@ const DefaultTaskTimeout = 5 * time.Minute
 // Config holds daemon configuration.
 type Config struct {
 	PIDPath           string        // Path to PID file
-	SocketPath        string        // Path to Unix socket
+	SocketPath        string        // Path to Unix socket (Windows derives a named pipe from it)
 	LogPath           string        // Path to log file
 	HeartbeatPath     string        // Path to heartbeat file (Issue #74)
 	Interval          time.Duration // Sync interval
@@ -60,12 +66,47 @@ type Config struct {
 	DBPath            string        // Path to database
 	CachePath         string        // Path to cache
 	Executable        string        // Optional: explicit path to executable (for testing)
+	LogLevel          slog.Level    // Minimum level written to the log file (default: info)
+	LogFormat         string        // Log record format: "text" or "json" (default: text)
+	LogMaxSize        int64         // Rotate the log file at this size in bytes (default: 10MB)
+	LogMaxBackups     int           // Rotated log files to keep (default: 3; negative keeps none)
+	IPCUserSID        string        // Windows: extra user allowed on the IPC pipe when running as a service
+	QueueDepth        func() int    // Optional: reports queued sync operations in status responses
+	ConflictCount     func() int    // Optional: reports unresolved sync conflicts; a sync that adds some sends a conflict notification
+	RPCHandler        RPCHandler    // Optional: serves the JSON-RPC list and task methods (see rpc.go)
+
+	// Hot reload: when LoadSettings is set, the daemon polls ConfigPath and applies changed settings
+	LoadSettings       func() (Settings, error) // Optional: reads the reloadable settings from the app config
+	ConfigPollInterval time.Duration            // How often to check ConfigPath for changes (default: 2s)
+
+	// Scheduled jobs such as the daily digest (see schedule.go)
+	Scheduled        ScheduledFunc // Optional: runs the jobs that are due
+	ScheduleInterval time.Duration // How often Scheduled is called (default: 1m)
+
+	Notifier notification.NotificationManager // Optional: sends sync notifications (see SetNotificationManager)
 }
 
+// DefaultSnoozeDuration is the snooze duration used when a task action omits one.
+const DefaultSnoozeDuration = 15 * time.Minute
+
+// Task actions accepted by the "task_action" message.
+const (
+	TaskActionSnooze   = "snooze"
+	TaskActionComplete = "complete"
+)
+
 // Message represents an IPC message between CLI and daemon.
 type Message struct {
-	Type string `json:"type"` // "notify", "status", "stop"
+	Type string `json:"type"` // "notify", "status", "stop", "task_action"
 	Data string `json:"data,omitempty"`
+
+	// Task action fields, used by external notification callbacks
+	UID      string `json:"uid,omitempty"`      // Task UID to act on
+	Action   string `json:"action,omitempty"`   // "snooze" or "complete"
+	Duration string `json:"duration,omitempty"` // Snooze duration (e.g., "15m", "1h")
+
+	// DBPath is the database the CLI is using; a notify is refused by a daemon bound to another database
+	DBPath string `json:"db_path,omitempty"`
 }
 
 // Response represents a daemon response to CLI.
@@ -77,6 +118,16 @@ type Response struct {
 	Running       bool                      `json:"running"`
 	IntervalSec   int                       `json:"interval_sec,omitempty"`   // Actual running interval in seconds (Issue #59)
 	BackendStates map[string]*BackendStatus `json:"backend_states,omitempty"` // Per-backend status (Issue #40)
+	DBPath        string                    `json:"db_path,omitempty"`        // Database the daemon is bound to
+
+	// Health metrics returned by "status"
+	StartedAt         string `json:"started_at,omitempty"`         // When the daemon started (RFC3339)
+	UptimeSec         int    `json:"uptime_sec,omitempty"`         // Seconds since start
+	NextSync          string `json:"next_sync,omitempty"`          // Next scheduled sync tick (RFC3339)
+	QueueDepth        int    `json:"queue_depth,omitempty"`        // Pending sync operations
+	ErrorCount        int    `json:"error_count,omitempty"`        // Failed sync cycles since start
+	ConsecutiveErrors int    `json:"consecutive_errors,omitempty"` // Failed sync cycles in a row
+	LastError         string `json:"last_error,omitempty"`         // Error of the last failed sync
 }
 
 // BackendStatus represents the status of a backend for API responses.
@@ -87,6 +138,7 @@ type BackendStatus struct {
 	LastError    string `json:"last_error,omitempty"`
 	Healthy      bool   `json:"healthy"`
 	CircuitState string `json:"circuit_state,omitempty"` // Issue #114: Circuit breaker state
+	NextSync     string `json:"next_sync,omitempty"`     // When the backend is next due (RFC3339)
 }
 
 // BackendState holds the per-backend sync state (Issue #40).
@@ -110,14 +162,20 @@ type backendEntry struct {
 
 // Daemon represents a running daemon process.
 type Daemon struct {
-	cfg       *Config
-	syncCount int
-	lastSync  time.Time
-	mu        sync.RWMutex
-	syncMu    sync.Mutex // Serializes performSync calls (Issue #52)
-	stopChan  chan struct{}
-	listener  net.Listener
-	syncFunc  func() error // Function to call for sync operations (legacy single-backend)
+	cfg             *Config
+	syncCount       int
+	lastSync        time.Time
+	lastSyncErr     error // Error of the last legacy single-backend sync
+	startedAt       time.Time
+	nextTick        time.Time // When the sync ticker fires next
+	errorTotal      int       // Failed sync cycles since start
+	lastErrMsg      string    // Error of the last failed sync cycle
+	mu              sync.RWMutex
+	syncMu          sync.Mutex // Serializes performSync calls (Issue #52)
+	stopChan        chan struct{}
+	listener        net.Listener
+	socketActivated bool         // Listener was passed in by systemd socket activation
+	syncFunc        func() error // Function to call for sync operations (legacy single-backend)
 
 	// Multi-backend support (Issue #40)
 	backends        []*backendEntry            // List of backends with their sync functions
@@ -134,6 +192,22 @@ type Daemon struct {
 
 	// Notification integration (Issue #115)
 	notifyMgr notification.NotificationManager
+
+	// Task actions from external notification callbacks
+	taskActionFunc func(uid, action string, duration time.Duration) error
+
+	// Config hot reload
+	settings    *Settings   // Settings read from the config file (nil when hot reload is off)
+	configStamp configStamp // Config file version the settings were read from
+
+	// Scheduled jobs
+	scheduledRunning atomic.Bool    // A Scheduled call is in progress
+	scheduledWG      sync.WaitGroup // Waits for the running Scheduled call on shutdown
+
+	// Structured log output, opened on first use
+	logger  *slog.Logger
+	logFile *utils.RotatingFile
+	logMu   sync.Mutex
 }
 
 // New creates a new Daemon instance.
@@ -144,6 +218,7 @@ func New(cfg *Config) *Daemon {
 		backends:        make([]*backendEntry, 0),
 		backendStates:   make(map[string]*BackendState),
 		circuitBreakers: make(map[string]*CircuitBreaker),
+		notifyMgr:       cfg.Notifier,
 	}
 }
 
@@ -160,6 +235,12 @@ func (d *Daemon) SetNotificationManager(mgr notification.NotificationManager) {
 	d.notifyMgr = mgr
 }
 
+// SetTaskActionFunc sets the function that applies task actions (snooze, complete)
+// requested by external notification daemons over the IPC socket.
+func (d *Daemon) SetTaskActionFunc(f func(uid, action string, duration time.Duration) error) {
+	d.taskActionFunc = f
+}
+
 // SetTestBackoffMultiplier sets a multiplier for backoff duration (for testing).
 // Pass 0 to skip sleep entirely, or a fraction to speed up tests.
 func (d *Daemon) SetTestBackoffMultiplier(multiplier float64) {
@@ -272,7 +353,11 @@ func (d *Daemon) GetAllBackendStates() map[string]*BackendState {
 func (d *Daemon) getMinTickInterval() time.Duration {
 	d.backendsMu.RLock()
 	defer d.backendsMu.RUnlock()
+	return d.getTickIntervalLocked()
+}
 
+// getTickIntervalLocked is getMinTickInterval for callers holding backendsMu.
+func (d *Daemon) getTickIntervalLocked() time.Duration {
 	minInterval := d.cfg.Interval
 	if minInterval == 0 {
 		minInterval = 5 * time.Minute // Default global interval
@@ -288,11 +373,16 @@ func (d *Daemon) getMinTickInterval() time.Duration {
 }
 
 // getBackendStatuses returns the current status of all backends for API responses.
+// Registered backends that have not synced yet are included with their next due time.
 func (d *Daemon) getBackendStatuses() map[string]*BackendStatus {
+	d.mu.RLock()
+	nextTick := d.nextTick
+	d.mu.RUnlock()
+
 	d.backendsMu.RLock()
 	defer d.backendsMu.RUnlock()
 
-	if len(d.backendStates) == 0 {
+	if len(d.backendStates) == 0 && len(d.backends) == 0 {
 		return nil
 	}
 
@@ -318,9 +408,80 @@ func (d *Daemon) getBackendStatuses() map[string]*BackendStatus {
 			CircuitState: circuitState,
 		}
 	}
+	for _, be := range d.backends {
+		status, ok := statuses[be.name]
+		if !ok {
+			status = &BackendStatus{Healthy: true}
+			if cb, ok := d.circuitBreakers[be.name]; ok {
+				status.CircuitState = cb.State().String()
+			}
+			statuses[be.name] = status
+		}
+		if next := d.backendNextSync(be, nextTick); !next.IsZero() {
+			status.NextSync = next.Format(time.RFC3339)
+		}
+	}
 	return statuses
 }
 
+// backendNextSync returns the first sync tick at which be is due: the next tick,
+// or later if the backend's own interval has not elapsed by then.
+// Caller must hold backendsMu.
+func (d *Daemon) backendNextSync(be *backendEntry, nextTick time.Time) time.Time {
+	if nextTick.IsZero() || be.lastSync.IsZero() {
+		return nextTick
+	}
+	interval := be.interval
+	if interval == 0 {
+		interval = d.cfg.Interval
+	}
+	due := be.lastSync.Add(interval)
+	if !due.After(nextTick) {
+		return nextTick
+	}
+	// Round up to the tick at or after the due time
+	tick := d.getTickIntervalLocked()
+	if tick <= 0 {
+		return due
+	}
+	ticks := (due.Sub(nextTick) + tick - 1) / tick
+	return nextTick.Add(ticks * tick)
+}
+
+// statusResponse builds the response to a "status" request.
+func (d *Daemon) statusResponse() Response {
+	d.mu.RLock()
+	resp := Response{
+		Status:            "ok",
+		Running:           true,
+		SyncCount:         d.syncCount,
+		IntervalSec:       int(d.cfg.Interval.Seconds()),
+		DBPath:            d.cfg.DBPath,
+		ErrorCount:        d.errorTotal,
+		ConsecutiveErrors: d.consecutiveErrors,
+		LastError:         d.lastErrMsg,
+	}
+	if !d.lastSync.IsZero() {
+		resp.LastSync = d.lastSync.Format(time.RFC3339)
+	}
+	if !d.startedAt.IsZero() {
+		resp.StartedAt = d.startedAt.Format(time.RFC3339)
+		resp.UptimeSec = int(time.Since(d.startedAt).Seconds())
+	}
+	if !d.nextTick.IsZero() {
+		resp.NextSync = d.nextTick.Format(time.RFC3339)
+	}
+	d.mu.RUnlock()
+
+	if d.cfg.QueueDepth != nil {
+		resp.QueueDepth = d.cfg.QueueDepth()
+	}
+
+	// Add per-backend status (Issue #40)
+	resp.BackendStates = d.getBackendStatuses()
+	return resp
+}
+
 // Start starts the daemon process. This should be called in the forked process.
 func (d *Daemon) Start() error {
 	// Write PID file
@@ -331,28 +492,40 @@ func (d *Daemon) Start() error {
 		return fmt.Errorf("failed to write PID file: %w", err)
 	}
 
-	// Create Unix socket
-	if err := os.MkdirAll(filepath.Dir(d.cfg.SocketPath), 0700); err != nil {
-		return fmt.Errorf("failed to create socket directory: %w", err)
-	}
-	// Remove existing socket file if present
-	_ = os.Remove(d.cfg.SocketPath)
-
-	listener, err := net.Listen("unix", d.cfg.SocketPath)
+	// Use the socket passed by systemd socket activation if present; the
+	// socket file then belongs to systemd and is left in place on exit.
+	listener, err := activatedListener()
 	if err != nil {
-		return fmt.Errorf("failed to create Unix socket: %w", err)
+		return err
+	}
+	if listener != nil {
+		d.socketActivated = true
+	} else if listener, err = listen(d.cfg.SocketPath, d.cfg.IPCUserSID); err != nil {
+		return err
 	}
 	d.listener = listener
 
-	// Set up signal handlers
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGTERM, syscall.SIGINT)
+	// Set up signal handlers; a signal or Stop also ends a sync backoff early
+	sigCtx, stopSignals := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer stopSignals()
+	ctx, cancel := context.WithCancel(sigCtx)
+	defer cancel()
+	go func() {
+		select {
+		case <-d.stopChan:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
 
 	// Create log file directory
 	if err := os.MkdirAll(filepath.Dir(d.cfg.LogPath), 0700); err != nil {
 		return fmt.Errorf("failed to create log directory: %w", err)
 	}
 
+	d.mu.Lock()
+	d.startedAt = time.Now()
+	d.mu.Unlock()
 	d.log("Daemon started (PID: %d, interval: %v)", os.Getpid(), d.cfg.Interval)
 
 	// Start IPC listener
@@ -372,9 +545,29 @@ func (d *Daemon) Start() error {
 	tickInterval := d.getMinTickInterval()
 	d.log("Using tick interval: %v (backends: %d)", tickInterval, len(d.backends))
 
+	// Watch the config file for changes
+	d.loadInitialSettings()
+	var configPoll <-chan time.Time
+	if d.hotReloadEnabled() {
+		configTicker := time.NewTicker(d.configPollInterval())
+		defer configTicker.Stop()
+		configPoll = configTicker.C
+		d.log("Watching %s for config changes", d.cfg.ConfigPath)
+	}
+
+	// Run scheduled jobs now and then every schedule interval
+	var schedule <-chan time.Time
+	if d.cfg.Scheduled != nil {
+		scheduleTicker := time.NewTicker(d.scheduleInterval())
+		defer scheduleTicker.Stop()
+		schedule = scheduleTicker.C
+		d.runScheduled(time.Now())
+	}
+
 	// Start sync loop
 	ticker := time.NewTicker(tickInterval)
 	defer ticker.Stop()
+	d.scheduleNextTick(tickInterval)
 	defer func() {
 		if heartbeatTicker != nil {
 			heartbeatTicker.Stop()
@@ -389,7 +582,7 @@ func (d *Daemon) Start() error {
 
 	for {
 		select {
-		case <-sigChan:
+		case <-sigCtx.Done():
 			d.log("Received shutdown signal")
 			d.cleanup()
 			return nil
@@ -399,38 +592,23 @@ func (d *Daemon) Start() error {
 			d.cleanup()
 			return nil
 
-		case <-ticker.C:
-			result := d.performSync()
-
-			// Issue #115: Send notifications for sync events
-			d.sendSyncNotification(result)
-
-			// Error loop prevention (Issue #82)
-			// syncNoOp means no backends were due to sync - don't affect error tracking
-			switch result {
-			case syncFailed:
-				d.consecutiveErrors++
-				d.log("Consecutive errors: %d/%d", d.consecutiveErrors, MaxConsecutiveErrors)
-
-				if d.consecutiveErrors >= MaxConsecutiveErrors {
-					d.log("Too many consecutive errors, shutting down daemon")
-					d.cleanup()
-					return nil
+		case <-configPoll:
+			if d.ReloadConfig() {
+				if interval := d.getMinTickInterval(); interval != tickInterval {
+					tickInterval = interval
+					ticker.Reset(tickInterval)
+					d.scheduleNextTick(tickInterval)
 				}
+			}
 
-				// Apply exponential backoff before next sync
-				backoff := d.getBackoffDuration(d.consecutiveErrors)
-				if backoff > 0 {
-					d.log("Backing off for %v before next sync", backoff)
-					time.Sleep(backoff)
-				}
-			case syncSuccess:
-				// Reset consecutive error count on success
-				if d.consecutiveErrors > 0 {
-					d.log("Sync succeeded, resetting consecutive error count (was %d)", d.consecutiveErrors)
-				}
-				d.consecutiveErrors = 0
-				// syncNoOp: no action needed, preserve current error count
+		case now := <-schedule:
+			d.runScheduled(now)
+
+		case <-ticker.C:
+			d.scheduleNextTick(tickInterval)
+			if _, stop := d.runSyncCycle(ctx); stop {
+				d.cleanup()
+				return nil
 			}
 
 			// Reset idle timer
@@ -457,9 +635,147 @@ func (d *Daemon) Start() error {
 	}
 }
 
+// scheduleNextTick records when the sync ticker fires next, for status responses.
+func (d *Daemon) scheduleNextTick(interval time.Duration) {
+	d.mu.Lock()
+	d.nextTick = time.Now().Add(interval)
+	d.mu.Unlock()
+}
+
+// runSyncCycle performs one sync and applies notifications, error loop prevention
+// and backoff. It is shared by the forked daemon (Start) and foreground watch mode
+// (Watch). stop reports that MaxConsecutiveErrors was reached and the loop should end.
+// The backoff after a failed sync ends early when ctx is cancelled.
+func (d *Daemon) runSyncCycle(ctx context.Context) (result syncResult, stop bool) {
+	if d.isOffline() {
+		d.log("Skipping sync: offline_mode is %s", OfflineModeOffline)
+		return syncNoOp, false
+	}
+	conflictsBefore := d.conflictCount()
+	result = d.performSync()
+
+	// Issue #115: Send notifications for sync events
+	d.sendSyncNotification(result)
+	d.sendConflictNotification(conflictsBefore, d.conflictCount())
+
+	// Error loop prevention (Issue #82)
+	// syncNoOp means no backends were due to sync - don't affect error tracking
+	switch result {
+	case syncFailed:
+		d.mu.Lock()
+		d.consecutiveErrors++
+		d.errorTotal++
+		consecutive := d.consecutiveErrors
+		d.mu.Unlock()
+		d.log("Consecutive errors: %d/%d", consecutive, MaxConsecutiveErrors)
+
+		if consecutive >= MaxConsecutiveErrors {
+			d.logError("Too many consecutive errors, shutting down daemon")
+			return result, true
+		}
+
+		// Apply exponential backoff before next sync
+		backoff := d.getBackoffDuration(consecutive)
+		if backoff > 0 {
+			d.log("Backing off for %v before next sync", backoff)
+			timer := time.NewTimer(backoff)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+			}
+		}
+	case syncSuccess:
+		// Reset consecutive error count on success
+		d.mu.Lock()
+		was := d.consecutiveErrors
+		d.consecutiveErrors = 0
+		d.mu.Unlock()
+		if was > 0 {
+			d.log("Sync succeeded, resetting consecutive error count (was %d)", was)
+		}
+		// syncNoOp: no action needed, preserve current error count
+	}
+	return result, false
+}
+
+// Watch triggers for SyncEvent
+const (
+	WatchTriggerStart    = "start"
+	WatchTriggerInterval = "interval"
+	WatchTriggerChange   = "change"
+)
+
+// SyncEvent describes one sync cycle run by Watch.
+type SyncEvent struct {
+	Count   int       // Sync cycle number
+	Time    time.Time // When the cycle finished
+	Trigger string    // WatchTriggerStart, WatchTriggerInterval or WatchTriggerChange
+	Failed  bool      // The sync failed
+	Skipped bool      // No backend was due to sync
+	Err     error     // Error of a failed legacy single-backend sync
+}
+
+// Watch runs the daemon sync loop in the foreground: once at start, then every
+// interval and whenever a value arrives on changes. It does not write a PID file,
+// open the IPC socket or fork, and returns nil when ctx is cancelled (e.g. on
+// SIGINT). report, if set, is called after each cycle. Watch returns an error
+// when MaxConsecutiveErrors syncs fail in a row, like the daemon's shutdown.
+func (d *Daemon) Watch(ctx context.Context, changes <-chan struct{}, report func(SyncEvent)) error {
+	tickInterval := d.getMinTickInterval()
+	ticker := time.NewTicker(tickInterval)
+	defer ticker.Stop()
+	d.mu.Lock()
+	d.startedAt = time.Now()
+	d.mu.Unlock()
+	d.scheduleNextTick(tickInterval)
+
+	cycle := func(trigger string) bool {
+		result, stop := d.runSyncCycle(ctx)
+		if report != nil {
+			d.mu.RLock()
+			event := SyncEvent{
+				Count:   d.syncCount,
+				Time:    time.Now(),
+				Trigger: trigger,
+				Failed:  result == syncFailed,
+				Skipped: result == syncNoOp,
+				Err:     d.lastSyncErr,
+			}
+			d.mu.RUnlock()
+			report(event)
+		}
+		return stop
+	}
+
+	if cycle(WatchTriggerStart) {
+		return fmt.Errorf("sync failed %d times in a row", MaxConsecutiveErrors)
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			d.scheduleNextTick(tickInterval)
+			if cycle(WatchTriggerInterval) {
+				return fmt.Errorf("sync failed %d times in a row", MaxConsecutiveErrors)
+			}
+		case <-changes:
+			if cycle(WatchTriggerChange) {
+				return fmt.Errorf("sync failed %d times in a row", MaxConsecutiveErrors)
+			}
+		}
+	}
+}
+
 // Stop signals the daemon to stop.
 func (d *Daemon) Stop() {
-	close(d.stopChan)
+	select {
+	case <-d.stopChan:
+		// Already stopping
+	default:
+		close(d.stopChan)
+	}
 }
 
 func (d *Daemon) handleConnections() {
@@ -479,7 +795,7 @@ func (d *Daemon) handleConnections() {
 					return
 				case <-time.After(1 * time.Millisecond):
 					// Not stopping, log the error
-					d.log("Accept error: %v", err)
+					d.logError("Accept error: %v", err)
 				}
 			}
 			continue
@@ -497,31 +813,41 @@ func (d *Daemon) handleConnection(conn net.Conn) {
 	decoder := json.NewDecoder(conn)
 	encoder := json.NewEncoder(conn)
 
+	// The first message tells an internal CLI message from a JSON-RPC client,
+	// which may keep the connection open for more requests
+	var raw json.RawMessage
+	if err := decoder.Decode(&raw); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			_ = encoder.Encode(rpcErrorResponse(nil, RPCParseError, "parse error: "+err.Error()))
+		}
+		return
+	}
+	if isRPCMessage(raw) {
+		d.serveRPC(conn, decoder, encoder, raw)
+		return
+	}
+
 	var msg Message
-	if err := decoder.Decode(&msg); err != nil {
+	if err := json.Unmarshal(raw, &msg); err != nil {
 		return
 	}
 
 	var resp Response
 	switch msg.Type {
 	case "notify":
+		// Refuse notifications from a CLI working on another database
+		if !SameDatabase(msg.DBPath, d.cfg.DBPath) {
+			d.log("Ignoring notify for database %s (bound to %s)", msg.DBPath, d.cfg.DBPath)
+			resp = Response{Status: "error", Message: WorkspaceMismatchMessage(d.cfg.DBPath), Running: true, DBPath: d.cfg.DBPath}
+			break
+		}
 		// Trigger immediate sync
 		go d.performSync()
 		resp = Response{Status: "ok", Running: true}
 
 	case "status":
-		d.mu.RLock()
-		resp = Response{
-			Status:      "ok",
-			Running:     true,
-			SyncCount:   d.syncCount,
-			LastSync:    d.lastSync.Format(time.RFC3339),
-			IntervalSec: int(d.cfg.Interval.Seconds()),
-		}
-		d.mu.RUnlock()
-
-		// Add per-backend status (Issue #40)
-		resp.BackendStates = d.getBackendStatuses()
+		resp = d.statusResponse()
 
 	case "stop":
 		resp = Response{Status: "ok", Running: false}
@@ -529,6 +855,9 @@ func (d *Daemon) handleConnection(conn net.Conn) {
 		d.Stop()
 		return
 
+	case "task_action":
+		resp = d.handleTaskAction(msg)
+
 	default:
 		resp = Response{Status: "error", Message: "unknown message type"}
 	}
@@ -536,6 +865,50 @@ func (d *Daemon) handleConnection(conn net.Conn) {
 	_ = encoder.Encode(resp)
 }
 
+// handleTaskAction validates and applies a task action message.
+func (d *Daemon) handleTaskAction(msg Message) Response {
+	if d.taskActionFunc == nil {
+		return Response{Status: "error", Message: "task actions are not supported by this daemon", Running: true}
+	}
+
+	duration, err := ParseTaskAction(msg)
+	if err != nil {
+		return Response{Status: "error", Message: err.Error(), Running: true}
+	}
+
+	if err := d.taskActionFunc(msg.UID, msg.Action, duration); err != nil {
+		d.logError("Task action %s failed for %s: %v", msg.Action, msg.UID, err)
+		return Response{Status: "error", Message: err.Error(), Running: true}
+	}
+
+	d.log("Task action %s applied to %s", msg.Action, msg.UID)
+	return Response{Status: "ok", Running: true}
+}
+
+// ParseTaskAction validates a task action message and returns the snooze duration.
+// The duration defaults to DefaultSnoozeDuration for snooze and is zero for complete.
+func ParseTaskAction(msg Message) (time.Duration, error) {
+	if msg.UID == "" {
+		return 0, fmt.Errorf("task action requires a uid")
+	}
+
+	switch msg.Action {
+	case TaskActionSnooze:
+		if msg.Duration == "" {
+			return DefaultSnoozeDuration, nil
+		}
+		duration, err := time.ParseDuration(msg.Duration)
+		if err != nil || duration <= 0 {
+			return 0, fmt.Errorf("invalid snooze duration: %s", msg.Duration)
+		}
+		return duration, nil
+	case TaskActionComplete:
+		return 0, nil
+	default:
+		return 0, fmt.Errorf("unknown task action: %s (valid: snooze, complete)", msg.Action)
+	}
+}
+
 // syncResult represents the outcome of a sync operation.
 type syncResult int
 
@@ -579,12 +952,19 @@ func (d *Daemon) performSync() syncResult {
 		}
 	} else if d.syncFunc != nil {
 		// Legacy single-backend sync
-		if err := d.syncFunc(); err != nil {
-			d.log("Sync error (count: %d): %v", count, err)
+		err := d.syncFunc()
+		if err != nil {
+			d.logError("Sync error (count: %d): %v", count, err)
 			result = syncFailed
 		} else {
 			d.log("Sync completed (count: %d)", count)
 		}
+		d.mu.Lock()
+		d.lastSyncErr = err
+		if err != nil {
+			d.lastErrMsg = err.Error()
+		}
+		d.mu.Unlock()
 	}
 
 	d.mu.Lock()
@@ -663,7 +1043,7 @@ func (d *Daemon) performMultiBackendSync(globalCount int) (allFailed bool, anySy
 				cb.RecordFailure()
 				state.CircuitState = cb.State().String()
 			}
-			d.log("Backend %s sync error: %v (error count: %d)", be.name, err, state.ErrorCount)
+			d.logError("Backend %s sync error: %v (error count: %d)", be.name, err, state.ErrorCount)
 		} else {
 			// Success - reset error count
 			state.SyncCount++
@@ -681,6 +1061,12 @@ func (d *Daemon) performMultiBackendSync(globalCount int) (allFailed bool, anySy
 		// Update backend's last sync time (regardless of success/failure)
 		be.lastSync = now
 		d.backendsMu.Unlock()
+
+		if err != nil {
+			d.mu.Lock()
+			d.lastErrMsg = fmt.Sprintf("%s: %v", be.name, err)
+			d.mu.Unlock()
+		}
 	}
 
 	// Issue #100: All backends failed → report as global sync failure
@@ -721,7 +1107,7 @@ func (d *Daemon) syncBackendWithTimeout(be *backendEntry, timeout time.Duration)
 	case <-ctx.Done():
 		// Timeout exceeded
 		elapsed := time.Since(startTime)
-		d.log("Backend %s timed out after %v (limit: %v)", be.name, elapsed, timeout)
+		d.logError("Backend %s timed out after %v (limit: %v)", be.name, elapsed, timeout)
 
 		// Invoke callback if registered
 		if d.onTaskTimeout != nil {
@@ -746,6 +1132,9 @@ func (d *Daemon) cleanup() {
 		_ = d.listener.Close()
 	}
 
+	// Let a running scheduled job finish, e.g. a digest being sent
+	d.scheduledWG.Wait()
+
 	// Log before removing any files to avoid race conditions where
 	// handleConnections() might recreate the log file after removal
 	d.log("Daemon stopped")
@@ -754,8 +1143,16 @@ func (d *Daemon) cleanup() {
 	// This prevents the race where log() is called after files are removed
 	time.Sleep(10 * time.Millisecond)
 
+	d.logMu.Lock()
+	if d.logFile != nil {
+		_ = d.logFile.Close()
+	}
+	d.logMu.Unlock()
+
 	_ = os.Remove(d.cfg.PIDPath)
-	_ = os.Remove(d.cfg.SocketPath)
+	if !d.socketActivated {
+		_ = os.Remove(d.cfg.SocketPath)
+	}
 	_ = os.Remove(d.cfg.LogPath)
 	// Clean up heartbeat file if it exists (Issue #74)
 	if d.cfg.HeartbeatPath != "" {
@@ -783,24 +1180,61 @@ func (d *Daemon) writeHeartbeat() {
 	}
 	// Ensure directory exists
 	if err := os.MkdirAll(filepath.Dir(d.cfg.HeartbeatPath), 0700); err != nil {
-		d.log("Failed to create heartbeat directory: %v", err)
+		d.logError("Failed to create heartbeat directory: %v", err)
 		return
 	}
 	// Write timestamp atomically
 	ts := time.Now().Format(time.RFC3339Nano)
 	if err := os.WriteFile(d.cfg.HeartbeatPath, []byte(ts), 0600); err != nil {
-		d.log("Failed to write heartbeat: %v", err)
+		d.logError("Failed to write heartbeat: %v", err)
 	}
 }
 
+// log writes an info record to the daemon log.
 func (d *Daemon) log(format string, args ...interface{}) {
-	entry := fmt.Sprintf("[%s] %s\n", time.Now().Format(time.RFC3339), fmt.Sprintf(format, args...))
-	f, err := os.OpenFile(d.cfg.LogPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
-	if err != nil {
-		return
+	d.logAt(slog.LevelInfo, format, args...)
+}
+
+// logError writes an error record to the daemon log.
+func (d *Daemon) logError(format string, args ...interface{}) {
+	d.logAt(slog.LevelError, format, args...)
+}
+
+// logAt writes a record to the rotating daemon log file, opening it on first use.
+// Records are tagged with component=daemon so they can be filtered alongside
+// other todoat logs.
+func (d *Daemon) logAt(level slog.Level, format string, args ...interface{}) {
+	d.logMu.Lock()
+	if d.logger == nil {
+		file, err := utils.OpenRotatingFile(d.cfg.LogPath, d.cfg.LogMaxSize, d.logMaxBackups())
+		if err != nil {
+			d.logMu.Unlock()
+			return
+		}
+		opts := &slog.HandlerOptions{Level: d.cfg.LogLevel}
+		var handler slog.Handler = slog.NewTextHandler(file, opts)
+		if d.cfg.LogFormat == utils.LogFormatJSON {
+			handler = slog.NewJSONHandler(file, opts)
+		}
+		d.logFile = file
+		d.logger = slog.New(handler).With("component", "daemon")
 	}
-	defer func() { _ = f.Close() }()
-	_, _ = f.WriteString(entry)
+	logger := d.logger
+	d.logMu.Unlock()
+
+	logger.Log(context.Background(), level, fmt.Sprintf(format, args...))
+}
+
+// logMaxBackups returns the configured backup count. Zero means unset and uses
+// the default; a negative value keeps no backups.
+func (d *Daemon) logMaxBackups() int {
+	switch {
+	case d.cfg.LogMaxBackups == 0:
+		return utils.DefaultLogMaxBackups
+	case d.cfg.LogMaxBackups < 0:
+		return 0
+	}
+	return d.cfg.LogMaxBackups
 }
 
 // Client provides methods to communicate with a running daemon.
@@ -818,6 +1252,19 @@ func (c *Client) Notify() error {
 	return c.send(Message{Type: "notify"})
 }
 
+// NotifyWorkspace asks the daemon to sync on behalf of a CLI using dbPath.
+// It returns an error if the daemon is bound to a different database.
+func (c *Client) NotifyWorkspace(dbPath string) error {
+	resp, err := c.sendAndReceive(Message{Type: "notify", DBPath: dbPath})
+	if err != nil {
+		return err
+	}
+	if resp.Status != "ok" {
+		return fmt.Errorf("%s", resp.Message)
+	}
+	return nil
+}
+
 // Status gets the daemon status.
 func (c *Client) Status() (*Response, error) {
 	return c.sendAndReceive(Message{Type: "status"})
@@ -829,8 +1276,25 @@ func (c *Client) Stop() error {
 	return err
 }
 
+// TaskAction asks the daemon to snooze or complete a task.
+// A zero duration lets the daemon pick its default snooze duration.
+func (c *Client) TaskAction(uid, action string, duration time.Duration) error {
+	msg := Message{Type: "task_action", UID: uid, Action: action}
+	if duration > 0 {
+		msg.Duration = duration.String()
+	}
+	resp, err := c.sendAndReceive(msg)
+	if err != nil {
+		return err
+	}
+	if resp.Status != "ok" {
+		return fmt.Errorf("%s", resp.Message)
+	}
+	return nil
+}
+
 func (c *Client) send(msg Message) error {
-	conn, err := net.DialTimeout("unix", c.socketPath, 500*time.Millisecond)
+	conn, err := dial(c.socketPath, 500*time.Millisecond)
 	if err != nil {
 		return err
 	}
@@ -841,7 +1305,7 @@ func (c *Client) send(msg Message) error {
 }
 
 func (c *Client) sendAndReceive(msg Message) (*Response, error) {
-	conn, err := net.DialTimeout("unix", c.socketPath, 500*time.Millisecond)
+	conn, err := dial(c.socketPath, 500*time.Millisecond)
 	if err != nil {
 		return nil, err
 	}
@@ -896,6 +1360,9 @@ func buildForkArgs(cfg *Config) []string {
 	if cfg.CachePath != "" {
 		args = append(args, "--cache-path", cfg.CachePath)
 	}
+	if cfg.IPCUserSID != "" {
+		args = append(args, "--daemon-ipc-sid", cfg.IPCUserSID)
+	}
 	return args
 }
 
@@ -921,9 +1388,7 @@ func Fork(cfg *Config) error {
 	cmd.Stdin = nil
 	cmd.Stdout = nil
 	cmd.Stderr = nil
-	cmd.SysProcAttr = &syscall.SysProcAttr{
-		Setsid: true, // Create new session
-	}
+	cmd.SysProcAttr = detachedProcAttr()
 
 	// Set environment
 	cmd.Env = os.Environ()
@@ -946,7 +1411,9 @@ func IsRunning(pidPath, socketPath string) bool {
 	// Check if PID file exists
 	data, err := os.ReadFile(pidPath)
 	if err != nil {
-		return false
+		// Without a PID file the daemon may still be reachable through a
+		// socket held by systemd socket activation; connecting starts it.
+		return socketAccepts(socketPath)
 	}
 
 	// Parse PID
@@ -956,44 +1423,90 @@ func IsRunning(pidPath, socketPath string) bool {
 	}
 
 	// Check if process exists
-	process, err := os.FindProcess(pid)
-	if err != nil {
-		return false
-	}
-
-	// On Unix, FindProcess always succeeds, so we need to send signal 0
-	// to check if process exists
-	if err := process.Signal(syscall.Signal(0)); err != nil {
+	if !ProcessAlive(pid) {
 		// Process doesn't exist, clean up stale PID file
 		_ = os.Remove(pidPath)
 		_ = os.Remove(socketPath)
 		return false
 	}
 
-	// Try to connect to socket
-	conn, err := net.DialTimeout("unix", socketPath, 100*time.Millisecond)
+	// Try to connect to socket; if unavailable, the process might be hung
+	return socketAccepts(socketPath)
+}
+
+// Listen creates a daemon IPC listener for socketPath: a Unix socket, or a
+// named pipe derived from the path on Windows.
+func Listen(socketPath string) (net.Listener, error) {
+	return listen(socketPath, "")
+}
+
+// socketAccepts reports whether something is listening on the daemon socket.
+func socketAccepts(socketPath string) bool {
+	if socketPath == "" {
+		return false
+	}
+	conn, err := dial(socketPath, 100*time.Millisecond)
 	if err != nil {
-		// Socket not available, process might be hung
 		return false
 	}
 	_ = conn.Close()
-
 	return true
 }
 
+// SameDatabase reports whether two database paths refer to the same file.
+// An empty path matches any database, since older clients and daemons do not report one.
+func SameDatabase(a, b string) bool {
+	if a == "" || b == "" {
+		return true
+	}
+	return cleanDBPath(a) == cleanDBPath(b)
+}
+
+// WorkspaceMismatchMessage is the error returned when a notify targets a daemon bound to another database.
+func WorkspaceMismatchMessage(boundDBPath string) string {
+	return fmt.Sprintf("daemon is bound to a different database (%s)", boundDBPath)
+}
+
+// WorkspacePath returns path with a short identifier of dbPath inserted before the extension
+// (e.g., daemon.sock -> daemon-1a2b3c4d.sock), so daemons for different databases use separate
+// socket, PID and heartbeat files. An empty dbPath returns path unchanged.
+func WorkspacePath(path, dbPath string) string {
+	if dbPath == "" {
+		return path
+	}
+	sum := sha256.Sum256([]byte(cleanDBPath(dbPath)))
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "-" + hex.EncodeToString(sum[:4]) + ext
+}
+
+// cleanDBPath returns an absolute, cleaned form of a database path for comparison
+func cleanDBPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
+
 // GetSocketPath returns the default socket path.
 func GetSocketPath() string {
+	return DefaultRuntimePath("sock")
+}
+
+// DefaultRuntimePath returns the default path of a daemon runtime file with the
+// given extension ("pid", "sock", "heartbeat"): under $XDG_RUNTIME_DIR/todoat if
+// set, otherwise a per-user location for the platform.
+func DefaultRuntimePath(ext string) string {
 	runtimeDir := os.Getenv("XDG_RUNTIME_DIR")
 	if runtimeDir != "" {
-		return filepath.Join(runtimeDir, "todoat", "daemon.sock")
+		return filepath.Join(runtimeDir, "todoat", "daemon."+ext)
 	}
-	return fmt.Sprintf("/tmp/todoat-daemon-%d.sock", os.Getuid())
+	return fallbackRuntimePath(ext)
 }
 
 // sendSyncNotification sends a notification based on the sync result.
 // Issue #115: Uses SendAsync for fire-and-forget delivery.
 func (d *Daemon) sendSyncNotification(result syncResult) {
-	if d.notifyMgr == nil {
+	if d.notifyMgr == nil || !d.notificationsEnabled() {
 		return
 	}
 
@@ -1020,12 +1533,44 @@ func (d *Daemon) sendSyncNotification(result syncResult) {
 	// syncNoOp: no notification needed
 }
 
+// conflictCount returns the unresolved sync conflicts, or 0 without ConflictCount
+func (d *Daemon) conflictCount() int {
+	if d.cfg.ConflictCount == nil {
+		return 0
+	}
+	return d.cfg.ConflictCount()
+}
+
+// sendConflictNotification notifies of the conflicts a sync detected, given the
+// unresolved conflicts before and after it
+func (d *Daemon) sendConflictNotification(before, after int) {
+	if after <= before || d.notifyMgr == nil || !d.notificationsEnabled() {
+		return
+	}
+	noun := "conflicts"
+	if after-before == 1 {
+		noun = "conflict"
+	}
+	d.notifyMgr.SendAsync(notification.Notification{
+		Type:      notification.NotifyConflict,
+		Title:     "todoat sync",
+		Message:   fmt.Sprintf("%d new sync %s (%d pending): run 'todoat sync conflicts'", after-before, noun, after),
+		Timestamp: time.Now(),
+	})
+}
+
 // RunDaemonMode is called when the executable is invoked with --daemon-mode.
 // This function runs the daemon and never returns (exits the process).
-func RunDaemonMode(ctx context.Context, cfg *Config, syncFunc func() error) {
+func RunDaemonMode(ctx context.Context, cfg *Config, syncFunc func() error, taskActionFunc func(uid, action string, duration time.Duration) error) {
 	d := New(cfg)
 	d.SetSyncFunc(syncFunc)
-	if err := d.Start(); err != nil {
+	d.SetTaskActionFunc(taskActionFunc)
+	// Report to the Windows service control manager when run as a service
+	handled, err := runAsService(d)
+	if !handled {
+		err = d.Start()
+	}
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "daemon error: %v\n", err)
 		os.Exit(1)
 	}
@@ -1083,9 +1628,5 @@ func CheckDaemonHealth(pidPath, socketPath, heartbeatPath string, heartbeatInter
 
 // GetHeartbeatPath returns the default heartbeat file path.
 func GetHeartbeatPath() string {
-	runtimeDir := os.Getenv("XDG_RUNTIME_DIR")
-	if runtimeDir != "" {
-		return filepath.Join(runtimeDir, "todoat", "daemon.heartbeat")
-	}
-	return fmt.Sprintf("/tmp/todoat-daemon-%d.heartbeat", os.Getuid())
+	return DefaultRuntimePath("heartbeat")
 }
@@ -2,7 +2,10 @@ package daemon
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"log/slog"
+	"net"
 	"os"
 	"path/filepath"
 	"strings"
@@ -257,6 +260,71 @@ func TestDaemonClientStatus(t *testing.T) {
 	time.Sleep(100 * time.Millisecond) // Wait for cleanup
 }
 
+// TestDaemonClientTaskAction verifies task_action messages reach the task action function
+func TestDaemonClientTaskAction(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &Config{
+		PIDPath:     filepath.Join(tmpDir, "daemon.pid"),
+		SocketPath:  filepath.Join(tmpDir, "daemon.sock"),
+		LogPath:     filepath.Join(tmpDir, "daemon.log"),
+		Interval:    time.Hour,
+		IdleTimeout: 0,
+	}
+
+	var gotUID, gotAction string
+	var gotDuration time.Duration
+	d := New(cfg)
+	d.SetSyncFunc(func() error { return nil })
+	d.SetTaskActionFunc(func(uid, action string, duration time.Duration) error {
+		if uid == "missing" {
+			return fmt.Errorf("task not found: %s", uid)
+		}
+		gotUID, gotAction, gotDuration = uid, action, duration
+		return nil
+	})
+
+	go func() {
+		_ = d.Start()
+	}()
+	defer func() {
+		d.Stop()
+		time.Sleep(100 * time.Millisecond) // Wait for cleanup
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	client := NewClient(cfg.SocketPath)
+
+	if err := client.TaskAction("task-1", TaskActionSnooze, 30*time.Minute); err != nil {
+		t.Fatalf("snooze failed: %v", err)
+	}
+	if gotUID != "task-1" || gotAction != TaskActionSnooze || gotDuration != 30*time.Minute {
+		t.Errorf("unexpected task action: uid=%q action=%q duration=%v", gotUID, gotAction, gotDuration)
+	}
+
+	// Snooze without duration uses the default
+	if err := client.TaskAction("task-1", TaskActionSnooze, 0); err != nil {
+		t.Fatalf("snooze failed: %v", err)
+	}
+	if gotDuration != DefaultSnoozeDuration {
+		t.Errorf("expected default snooze duration %v, got %v", DefaultSnoozeDuration, gotDuration)
+	}
+
+	if err := client.TaskAction("task-2", TaskActionComplete, 0); err != nil {
+		t.Fatalf("complete failed: %v", err)
+	}
+	if gotUID != "task-2" || gotAction != TaskActionComplete {
+		t.Errorf("unexpected task action: uid=%q action=%q", gotUID, gotAction)
+	}
+
+	if err := client.TaskAction("task-1", "archive", 0); err == nil {
+		t.Error("expected error for unknown action")
+	}
+	if err := client.TaskAction("missing", TaskActionComplete, 0); err == nil || !strings.Contains(err.Error(), "task not found") {
+		t.Errorf("expected task not found error, got %v", err)
+	}
+}
+
 // TestDaemonStatusReportsInterval verifies the daemon status response includes
 // the actual running interval, not a default (Issue #59).
 func TestDaemonStatusReportsInterval(t *testing.T) {
@@ -1107,6 +1175,29 @@ func TestDaemonMaxConsecutiveErrors(t *testing.T) {
 	// 2. It exited after exactly MaxConsecutiveErrors attempts
 }
 
+// TestDaemonBackoffEndsOnCancel verifies the backoff after a failed sync stops
+// waiting as soon as the daemon is stopped
+func TestDaemonBackoffEndsOnCancel(t *testing.T) {
+	tmpDir := t.TempDir()
+	d := New(&Config{
+		PIDPath:    filepath.Join(tmpDir, "daemon.pid"),
+		SocketPath: filepath.Join(tmpDir, "daemon.sock"),
+		LogPath:    filepath.Join(tmpDir, "daemon.log"),
+		Interval:   time.Minute,
+	})
+	d.SetSyncFunc(func() error { return fmt.Errorf("simulated sync failure") })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	started := time.Now()
+	if result, stop := d.runSyncCycle(ctx); result != syncFailed || stop {
+		t.Fatalf("runSyncCycle = %v, %v; want a failed sync that does not stop the daemon", result, stop)
+	}
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Errorf("backoff took %v after cancel, want it to end with the context (full backoff is %v)", elapsed, CalculateBackoff(1))
+	}
+}
+
 // TestDaemonErrorCountReset verifies successful operation resets consecutive error count
 func TestDaemonErrorCountReset(t *testing.T) {
 	tmpDir := t.TempDir()
@@ -2091,3 +2182,696 @@ func TestDaemonNotificationDisabled(t *testing.T) {
 		t.Errorf("expected no notifications when disabled, got %d", len(received))
 	}
 }
+
+func TestDaemonSendsNotificationOnNewConflicts(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	var conflicts atomic.Int32
+	cfg := &Config{
+		PIDPath:       filepath.Join(tmpDir, "daemon.pid"),
+		SocketPath:    filepath.Join(tmpDir, "daemon.sock"),
+		LogPath:       filepath.Join(tmpDir, "daemon.log"),
+		Interval:      time.Minute,
+		ConflictCount: func() int { return int(conflicts.Load()) },
+	}
+
+	d := New(cfg)
+	// The first sync detects two conflicts, the second none
+	syncs := 0
+	d.SetSyncFunc(func() error {
+		syncs++
+		if syncs == 1 {
+			conflicts.Add(2)
+		}
+		return nil
+	})
+	mgr := &mockNotificationManager{enabled: true}
+	d.SetNotificationManager(mgr)
+
+	d.runSyncCycle(context.Background())
+	d.runSyncCycle(context.Background())
+	_ = mgr.Close()
+
+	var conflictNotifications []notification.Notification
+	for _, n := range mgr.notifications() {
+		if n.Type == notification.NotifyConflict {
+			conflictNotifications = append(conflictNotifications, n)
+		}
+	}
+	if len(conflictNotifications) != 1 {
+		t.Fatalf("expected one conflict notification, got %d", len(conflictNotifications))
+	}
+	if msg := conflictNotifications[0].Message; !strings.Contains(msg, "2 new sync conflicts (2 pending)") {
+		t.Errorf("unexpected conflict notification message: %q", msg)
+	}
+}
+
+// =============================================================================
+// Workspace-aware daemon discovery
+// =============================================================================
+
+func TestWorkspacePath(t *testing.T) {
+	if got := WorkspacePath("/run/todoat/daemon.sock", ""); got != "/run/todoat/daemon.sock" {
+		t.Errorf("expected unchanged path for empty workspace, got %s", got)
+	}
+
+	work := WorkspacePath("/run/todoat/daemon.sock", "/data/work/tasks.db")
+	personal := WorkspacePath("/run/todoat/daemon.sock", "/data/personal/tasks.db")
+	if work == personal {
+		t.Errorf("expected different socket paths per database, both were %s", work)
+	}
+	if !strings.HasPrefix(work, "/run/todoat/daemon-") || !strings.HasSuffix(work, ".sock") {
+		t.Errorf("unexpected workspace socket path: %s", work)
+	}
+	if again := WorkspacePath("/run/todoat/daemon.sock", "/data/work/../work/tasks.db"); again != work {
+		t.Errorf("expected equivalent database paths to map to the same socket, got %s and %s", again, work)
+	}
+	if pid := WorkspacePath("/run/todoat/daemon.pid", "/data/work/tasks.db"); strings.TrimSuffix(pid, ".pid") != strings.TrimSuffix(work, ".sock") {
+		t.Errorf("expected pid and socket to share the workspace suffix, got %s and %s", pid, work)
+	}
+}
+
+func TestSameDatabase(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"/data/tasks.db", "/data/tasks.db", true},
+		{"/data/./tasks.db", "/data/tasks.db", true},
+		{"/data/work.db", "/data/personal.db", false},
+		{"", "/data/tasks.db", true},
+		{"/data/tasks.db", "", true},
+	}
+	for _, tt := range tests {
+		if got := SameDatabase(tt.a, tt.b); got != tt.want {
+			t.Errorf("SameDatabase(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestDaemonRefusesNotifyForOtherDatabase(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &Config{
+		PIDPath:     filepath.Join(tmpDir, "daemon.pid"),
+		SocketPath:  filepath.Join(tmpDir, "daemon.sock"),
+		LogPath:     filepath.Join(tmpDir, "daemon.log"),
+		Interval:    1 * time.Hour,
+		IdleTimeout: 0,
+		DBPath:      filepath.Join(tmpDir, "work.db"),
+	}
+
+	d := New(cfg)
+
+	var syncCount int32
+	d.SetSyncFunc(func() error {
+		atomic.AddInt32(&syncCount, 1)
+		return nil
+	})
+
+	done := make(chan struct{})
+	go func() {
+		_ = d.Start()
+		close(done)
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	client := NewClient(cfg.SocketPath)
+
+	err := client.NotifyWorkspace(filepath.Join(tmpDir, "personal.db"))
+	if err == nil || !strings.Contains(err.Error(), "different database") {
+		t.Errorf("expected notify for another database to be refused, got %v", err)
+	}
+
+	if err := client.NotifyWorkspace(cfg.DBPath); err != nil {
+		t.Errorf("notify for the daemon's database failed: %v", err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if atomic.LoadInt32(&syncCount) != 1 {
+		t.Errorf("expected exactly 1 sync, got %d", syncCount)
+	}
+
+	resp, err := client.Status()
+	if err != nil {
+		t.Fatalf("status failed: %v", err)
+	}
+	if resp.DBPath != cfg.DBPath {
+		t.Errorf("expected status to report db path %s, got %s", cfg.DBPath, resp.DBPath)
+	}
+
+	d.Stop()
+	<-done
+}
+
+// TestDaemonLogRotatesStructuredRecords verifies daemon log records are structured,
+// filtered by level and rotated at the configured size
+func TestDaemonLogRotatesStructuredRecords(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "daemon.log")
+	d := New(&Config{
+		LogPath:       logPath,
+		LogLevel:      slog.LevelError,
+		LogFormat:     "json",
+		LogMaxSize:    256,
+		LogMaxBackups: 1,
+	})
+
+	d.log("ignored below the error level")
+	for i := 0; i < 5; i++ {
+		d.logError("Backend %s sync error: attempt %d", "rotate_backend", i)
+	}
+	d.cleanup()
+
+	backup, err := os.ReadFile(logPath + ".1")
+	if err != nil {
+		t.Fatalf("daemon log should have been rotated: %v", err)
+	}
+	if _, err := os.Stat(logPath + ".2"); !os.IsNotExist(err) {
+		t.Error("only one rotated log should be kept")
+	}
+
+	line := strings.SplitN(strings.TrimSpace(string(backup)), "\n", 2)[0]
+	var record map[string]interface{}
+	if err := json.Unmarshal([]byte(line), &record); err != nil {
+		t.Fatalf("log record should be JSON, got %q: %v", line, err)
+	}
+	if record["level"] != "ERROR" || record["component"] != "daemon" || !strings.Contains(record["msg"].(string), "rotate_backend") {
+		t.Errorf("unexpected record: %v", record)
+	}
+	if strings.Contains(string(backup), "ignored below") {
+		t.Error("info records should be filtered at error level")
+	}
+}
+
+// TestWatchRunsSyncOnStartAndChanges verifies the foreground watch loop syncs at start
+// and on change signals, and returns when its context is cancelled
+func TestWatchRunsSyncOnStartAndChanges(t *testing.T) {
+	d := New(&Config{Interval: time.Hour})
+	var calls int32
+	d.SetSyncFunc(func() error {
+		atomic.AddInt32(&calls, 1)
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	changes := make(chan struct{}, 1)
+	var events []SyncEvent
+	done := make(chan error, 1)
+	go func() {
+		done <- d.Watch(ctx, changes, func(ev SyncEvent) {
+			events = append(events, ev)
+			if ev.Trigger == WatchTriggerStart {
+				changes <- struct{}{}
+			} else {
+				cancel()
+			}
+		})
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Watch returned error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Watch did not return after cancel")
+	}
+
+	if atomic.LoadInt32(&calls) != 2 || len(events) != 2 {
+		t.Fatalf("expected 2 syncs, got %d (events: %+v)", calls, events)
+	}
+	if events[1].Trigger != WatchTriggerChange || events[1].Count != 2 || events[1].Failed {
+		t.Errorf("unexpected change event: %+v", events[1])
+	}
+}
+
+// TestWatchStopsAfterConsecutiveErrors verifies watch mode shares the daemon's error loop prevention
+func TestWatchStopsAfterConsecutiveErrors(t *testing.T) {
+	d := New(&Config{Interval: 10 * time.Millisecond})
+	d.SetTestBackoffMultiplier(0)
+	d.SetSyncFunc(func() error { return fmt.Errorf("remote unavailable") })
+
+	var last SyncEvent
+	err := d.Watch(context.Background(), nil, func(ev SyncEvent) { last = ev })
+	if err == nil {
+		t.Fatal("Watch should fail after consecutive sync errors")
+	}
+	if last.Count != MaxConsecutiveErrors || !last.Failed || last.Err == nil || last.Err.Error() != "remote unavailable" {
+		t.Errorf("unexpected last event: %+v", last)
+	}
+}
+
+// TestDaemonStatusReportsHealthMetrics verifies the status IPC response carries uptime,
+// next sync, queue depth, error counts and per-backend schedule
+func TestDaemonStatusReportsHealthMetrics(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := &Config{
+		PIDPath:    filepath.Join(tmpDir, "daemon.pid"),
+		SocketPath: filepath.Join(tmpDir, "daemon.sock"),
+		LogPath:    filepath.Join(tmpDir, "daemon.log"),
+		Interval:   50 * time.Millisecond,
+		QueueDepth: func() int { return 3 },
+	}
+	d := New(cfg)
+	d.SetTestBackoffMultiplier(0)
+	d.AddBackendSyncFunc("healthy", func() error { return nil })
+	d.AddBackendSyncFunc("broken", func() error { return fmt.Errorf("connection refused") })
+
+	done := make(chan struct{})
+	go func() {
+		_ = d.Start()
+		close(done)
+	}()
+	defer func() {
+		d.Stop()
+		<-done
+	}()
+
+	client := NewClient(cfg.SocketPath)
+	var resp *Response
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		r, err := client.Status()
+		// One backend still succeeds, so no sync cycle counts as failed
+		if err == nil && r.ErrorCount == 0 && r.LastError != "" {
+			resp = r
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if resp == nil {
+		t.Fatal("daemon did not report the failing backend in time")
+	}
+
+	if resp.StartedAt == "" || resp.NextSync == "" {
+		t.Errorf("status should include start and next sync times: %+v", resp)
+	}
+	if resp.QueueDepth != 3 {
+		t.Errorf("QueueDepth = %d, want 3", resp.QueueDepth)
+	}
+	if !strings.Contains(resp.LastError, "broken: connection refused") {
+		t.Errorf("LastError = %q, want the broken backend's error", resp.LastError)
+	}
+	broken := resp.BackendStates["broken"]
+	if broken == nil || broken.ErrorCount == 0 || broken.Healthy || broken.NextSync == "" {
+		t.Errorf("unexpected broken backend status: %+v", broken)
+	}
+	if healthy := resp.BackendStates["healthy"]; healthy == nil || !healthy.Healthy {
+		t.Errorf("unexpected healthy backend status: %+v", healthy)
+	}
+}
+
+func TestInstallServiceSystemd(t *testing.T) {
+	dir := t.TempDir()
+	var commands []string
+	run := func(name string, args ...string) error {
+		commands = append(commands, name+" "+strings.Join(args, " "))
+		return nil
+	}
+	opts := ServiceOptions{
+		Manager:    ServiceManagerSystemd,
+		Dir:        dir,
+		Executable: "/opt/todo at/todoat",
+		Daemon: &Config{
+			PIDPath:     filepath.Join(dir, "daemon.pid"),
+			SocketPath:  filepath.Join(dir, "daemon.sock"),
+			LogPath:     filepath.Join(dir, "daemon.log"),
+			Interval:    2 * time.Minute,
+			IdleTimeout: 5 * time.Minute,
+		},
+	}
+
+	if _, err := InstallService(opts, run); err != nil {
+		t.Fatalf("InstallService failed: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, ServiceName+".service"))
+	if err != nil {
+		t.Fatalf("service unit should be written: %v", err)
+	}
+	unit := string(data)
+	for _, want := range []string{`ExecStart="/opt/todo at/todoat" --daemon-mode`, "--daemon-interval 120", "Restart=on-failure", "WantedBy=default.target"} {
+		if !strings.Contains(unit, want) {
+			t.Errorf("unit should contain %q, got:\n%s", want, unit)
+		}
+	}
+	if strings.Contains(unit, "--daemon-idle-timeout") {
+		t.Error("an always-on service should not exit when idle")
+	}
+	if len(commands) != 2 || commands[1] != "systemctl --user enable --now todoat-sync.service" {
+		t.Errorf("unexpected enable commands: %v", commands)
+	}
+
+	commands = nil
+	_, removed, err := UninstallService(ServiceOptions{Manager: ServiceManagerSystemd, Dir: dir}, run)
+	if err != nil {
+		t.Fatalf("UninstallService failed: %v", err)
+	}
+	if len(removed) != 1 {
+		t.Errorf("expected the service unit to be removed, got %v", removed)
+	}
+	if len(commands) == 0 || commands[0] != "systemctl --user disable --now todoat-sync.service" {
+		t.Errorf("unexpected disable commands: %v", commands)
+	}
+}
+
+func TestInstallServiceSocketActivation(t *testing.T) {
+	dir := t.TempDir()
+	socketPath := filepath.Join(dir, "daemon.sock")
+	opts := ServiceOptions{
+		Manager:          ServiceManagerSystemd,
+		Dir:              dir,
+		Executable:       "/usr/bin/todoat",
+		Daemon:           &Config{SocketPath: socketPath, Interval: time.Minute, IdleTimeout: 5 * time.Minute},
+		SocketActivation: true,
+	}
+	plan, err := InstallPlan(opts)
+	if err != nil {
+		t.Fatalf("InstallPlan failed: %v", err)
+	}
+	if len(plan.Files) != 2 {
+		t.Fatalf("expected service and socket units, got %d files", len(plan.Files))
+	}
+	if !strings.Contains(plan.Files[0].Content, "--daemon-idle-timeout 300") {
+		t.Error("socket-activated daemon should keep its idle timeout")
+	}
+	if !strings.Contains(plan.Files[1].Content, "ListenStream="+socketPath) {
+		t.Errorf("socket unit should listen on the daemon socket, got:\n%s", plan.Files[1].Content)
+	}
+	if last := plan.Commands[len(plan.Commands)-1]; last[len(last)-1] != ServiceName+".socket" {
+		t.Errorf("socket unit should be enabled, got %v", last)
+	}
+
+	opts.Manager = ServiceManagerLaunchd
+	if _, err := InstallPlan(opts); err == nil {
+		t.Error("socket activation should be rejected for launchd")
+	}
+}
+
+func TestLaunchdPlist(t *testing.T) {
+	plist := LaunchdPlist("/Applications/todo&at", []string{"--daemon-mode"}, "/tmp/daemon.log")
+	for _, want := range []string{"<string>" + LaunchdLabel + "</string>", "<string>/Applications/todo&amp;at</string>", "<string>--daemon-mode</string>", "<key>KeepAlive</key>"} {
+		if !strings.Contains(plist, want) {
+			t.Errorf("plist should contain %q, got:\n%s", want, plist)
+		}
+	}
+}
+
+func TestIsRunningWithoutPIDFileUsesSocket(t *testing.T) {
+	tmpDir := t.TempDir()
+	pidPath := filepath.Join(tmpDir, "daemon.pid")
+	socketPath := filepath.Join(tmpDir, "daemon.sock")
+
+	if IsRunning(pidPath, socketPath) {
+		t.Fatal("no PID file and no socket should not be running")
+	}
+	// A socket held open by a service manager counts as running
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = listener.Close() }()
+	if !IsRunning(pidPath, socketPath) {
+		t.Error("a listening socket without a PID file should be reported as running")
+	}
+}
+
+func TestInstallServiceWindowsPlan(t *testing.T) {
+	plan, err := InstallPlan(ServiceOptions{
+		Manager:    ServiceManagerWindows,
+		Executable: `C:\Program Files\todoat\todoat.exe`,
+		Daemon:     &Config{SocketPath: `C:\Users\me\AppData\Local\todoat\daemon.sock`, Interval: time.Minute, IdleTimeout: time.Minute},
+	})
+	if err != nil {
+		t.Fatalf("InstallPlan failed: %v", err)
+	}
+	if len(plan.Files) != 0 {
+		t.Errorf("Windows services are registered without files, got %d", len(plan.Files))
+	}
+	create := plan.Commands[0]
+	if create[0] != "sc.exe" || create[1] != "create" || create[3] != "binPath=" {
+		t.Fatalf("first command should create the service, got %v", create)
+	}
+	if !strings.HasPrefix(create[4], `"C:\Program Files\todoat\todoat.exe" --daemon-mode`) {
+		t.Errorf("binPath should quote the executable, got %s", create[4])
+	}
+	if strings.Contains(create[4], "--daemon-idle-timeout") {
+		t.Error("a Windows service should not exit when idle")
+	}
+	if last := plan.Commands[len(plan.Commands)-1]; last[1] != "start" {
+		t.Errorf("last command should start the service, got %v", last)
+	}
+}
+
+func TestWindowsCommandLine(t *testing.T) {
+	got := windowsCommandLine([]string{`C:\a b\x.exe`, "--flag", "", `say "hi"`, `dir\`, `tail\ x\`})
+	want := `"C:\a b\x.exe" --flag "" "say \"hi\"" dir\ "tail\ x\\"`
+	if got != want {
+		t.Errorf("windowsCommandLine = %s, want %s", got, want)
+	}
+}
+
+func TestListenDialRoundTrip(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "ipc", "daemon.sock")
+	listener, err := Listen(socketPath)
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		var msg Message
+		if err := json.NewDecoder(conn).Decode(&msg); err == nil {
+			_ = json.NewEncoder(conn).Encode(Response{Status: "ok", SyncCount: 7})
+		}
+	}()
+
+	resp, err := NewClient(socketPath).Status()
+	if err != nil {
+		t.Fatalf("Status over IPC failed: %v", err)
+	}
+	if resp.SyncCount != 7 {
+		t.Errorf("expected response from listener, got %+v", resp)
+	}
+	_ = listener.Close()
+	if _, err := listener.Accept(); err == nil {
+		t.Error("Accept should fail after Close")
+	}
+}
+
+func TestProcessAliveAndStopTwice(t *testing.T) {
+	if !ProcessAlive(os.Getpid()) {
+		t.Error("current process should be reported alive")
+	}
+	d := New(&Config{})
+	d.Stop()
+	d.Stop() // Must not panic when stop is requested twice
+}
+
+func TestReloadConfigAppliesChangedSettings(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	logPath := filepath.Join(tmpDir, "daemon.log")
+	if err := os.WriteFile(configPath, []byte("v1\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	settings := Settings{Interval: 5 * time.Minute, Backends: []string{"todoist"}, OfflineMode: "auto"}
+	d := New(&Config{
+		LogPath:    logPath,
+		ConfigPath: configPath,
+		Interval:   time.Minute, // Given at start, kept until the config changes it
+		LoadSettings: func() (Settings, error) {
+			return settings, nil
+		},
+	})
+	d.loadInitialSettings()
+	if d.ReloadConfig() {
+		t.Fatal("ReloadConfig reported a change for an unchanged config file")
+	}
+	if d.cfg.Interval != time.Minute || d.isOffline() || d.notificationsEnabled() {
+		t.Fatalf("initial settings should not be applied: interval=%v offline=%v notify=%v", d.cfg.Interval, d.isOffline(), d.notificationsEnabled())
+	}
+
+	settings = Settings{Interval: 2 * time.Minute, Backends: []string{"todoist", "work"}, OfflineMode: OfflineModeOffline, Notifications: true}
+	if err := os.WriteFile(configPath, []byte("v2 with more bytes\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if !d.ReloadConfig() {
+		t.Fatal("ReloadConfig did not apply the changed config")
+	}
+	if d.cfg.Interval != 2*time.Minute || !d.isOffline() || !d.notificationsEnabled() {
+		t.Errorf("changed settings not applied: interval=%v offline=%v notify=%v", d.cfg.Interval, d.isOffline(), d.notificationsEnabled())
+	}
+	if result, stop := d.runSyncCycle(context.Background()); result != syncNoOp || stop {
+		t.Errorf("offline daemon ran a sync: result=%v stop=%v", result, stop)
+	}
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Config reloaded: interval 5m0s -> 2m0s, backends [todoist] -> [todoist, work], offline_mode auto -> offline, notifications false -> true"
+	if !strings.Contains(string(data), want) {
+		t.Errorf("log does not contain %q:\n%s", want, data)
+	}
+}
+
+func TestJSONRPCProtocol(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	var syncs atomic.Int32
+	cfg := &Config{
+		PIDPath:     filepath.Join(tmpDir, "daemon.pid"),
+		SocketPath:  filepath.Join(tmpDir, "daemon.sock"),
+		LogPath:     filepath.Join(tmpDir, "daemon.log"),
+		Interval:    time.Hour,
+		IdleTimeout: 0,
+		RPCHandler: func(ctx context.Context, method string, params json.RawMessage) (any, error) {
+			if method == "tasks.delete" {
+				return nil, fmt.Errorf("task not found")
+			}
+			if method == "tasks.create" && len(params) == 0 {
+				return nil, &RPCError{Code: RPCInvalidParams, Message: "summary is required"}
+			}
+			return map[string]string{"method": method, "params": string(params)}, nil
+		},
+	}
+	d := New(cfg)
+	d.SetSyncFunc(func() error {
+		syncs.Add(1)
+		return nil
+	})
+
+	go func() {
+		_ = d.Start()
+	}()
+	defer func() {
+		d.Stop()
+		time.Sleep(100 * time.Millisecond) // Wait for cleanup
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	conn, err := dial(cfg.SocketPath, time.Second)
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+	decoder := json.NewDecoder(conn)
+
+	// Several requests share one connection
+	call := func(request string) map[string]any {
+		t.Helper()
+		if _, err := conn.Write([]byte(request + "\n")); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+		var resp map[string]any
+		if err := decoder.Decode(&resp); err != nil {
+			t.Fatalf("decode failed: %v", err)
+		}
+		return resp
+	}
+	errorCode := func(resp map[string]any) int {
+		e, ok := resp["error"].(map[string]any)
+		if !ok {
+			return 0
+		}
+		return int(e["code"].(float64))
+	}
+
+	resp := call(`{"jsonrpc":"2.0","id":1,"method":"daemon.version"}`)
+	result, _ := resp["result"].(map[string]any)
+	if resp["id"] != float64(1) || result["protocol"] != float64(ProtocolVersion) {
+		t.Errorf("daemon.version: unexpected response %v", resp)
+	}
+	if !strings.Contains(fmt.Sprint(result["methods"]), "tasks.create") {
+		t.Errorf("daemon.version: methods should include handler methods, got %v", result["methods"])
+	}
+
+	resp = call(`{"jsonrpc":"2.0","id":"s","method":"daemon.status"}`)
+	result, _ = resp["result"].(map[string]any)
+	if resp["id"] != "s" || result["running"] != true {
+		t.Errorf("daemon.status: unexpected response %v", resp)
+	}
+
+	before := syncs.Load()
+	resp = call(`{"jsonrpc":"2.0","id":2,"method":"sync.trigger"}`)
+	if errorCode(resp) != 0 {
+		t.Errorf("sync.trigger: unexpected error %v", resp)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for syncs.Load() == before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if syncs.Load() == before {
+		t.Error("sync.trigger did not run a sync")
+	}
+
+	resp = call(`{"jsonrpc":"2.0","id":3,"method":"tasks.list","params":{"list":"Work"}}`)
+	result, _ = resp["result"].(map[string]any)
+	if result["method"] != "tasks.list" || result["params"] != `{"list":"Work"}` {
+		t.Errorf("tasks.list: unexpected response %v", resp)
+	}
+
+	if code := errorCode(call(`{"jsonrpc":"2.0","id":4,"method":"tasks.delete","params":{}}`)); code != RPCCommandFailed {
+		t.Errorf("handler error: expected code %d, got %d", RPCCommandFailed, code)
+	}
+	if code := errorCode(call(`{"jsonrpc":"2.0","id":5,"method":"tasks.create"}`)); code != RPCInvalidParams {
+		t.Errorf("handler RPCError: expected code %d, got %d", RPCInvalidParams, code)
+	}
+	if code := errorCode(call(`{"jsonrpc":"2.0","id":6,"method":"tasks.frobnicate"}`)); code != RPCMethodNotFound {
+		t.Errorf("unknown method: expected code %d, got %d", RPCMethodNotFound, code)
+	}
+	if code := errorCode(call(`{"jsonrpc":"1.0","id":7,"method":"daemon.status"}`)); code != RPCInvalidRequest {
+		t.Errorf("wrong version: expected code %d, got %d", RPCInvalidRequest, code)
+	}
+
+	// A notification gets no response: the next response answers id 8
+	resp = call(`{"jsonrpc":"2.0","method":"daemon.status"}` + "\n" + `{"jsonrpc":"2.0","id":8,"method":"daemon.version"}`)
+	if resp["id"] != float64(8) {
+		t.Errorf("notification should not be answered, got %v", resp)
+	}
+
+	if code := errorCode(call(`{"jsonrpc":"2.0","id":9,]`)); code != RPCParseError {
+		t.Errorf("malformed JSON: expected code %d, got %d", RPCParseError, code)
+	}
+
+	// The internal protocol still works next to JSON-RPC
+	status, err := NewClient(cfg.SocketPath).Status()
+	if err != nil || !status.Running {
+		t.Errorf("internal status failed: %v %+v", err, status)
+	}
+}
+
+// TestDaemonRunsScheduledJobs verifies scheduled jobs run at start and then every schedule interval
+func TestDaemonRunsScheduledJobs(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	var calls int32
+	cfg := &Config{
+		PIDPath:          filepath.Join(tmpDir, "daemon.pid"),
+		SocketPath:       filepath.Join(tmpDir, "daemon.sock"),
+		LogPath:          filepath.Join(tmpDir, "daemon.log"),
+		Interval:         time.Hour,
+		ScheduleInterval: 50 * time.Millisecond,
+		Scheduled: func(now time.Time) error {
+			atomic.AddInt32(&calls, 1)
+			return nil
+		},
+	}
+
+	d := New(cfg)
+	d.SetSyncFunc(func() error { return nil })
+	go func() { _ = d.Start() }()
+
+	time.Sleep(180 * time.Millisecond)
+	d.Stop()
+	time.Sleep(50 * time.Millisecond)
+
+	if got := atomic.LoadInt32(&calls); got < 2 {
+		t.Errorf("expected scheduled jobs to run at start and on the schedule ticker, got %d calls", got)
+	}
+}
@@ -0,0 +1,72 @@
+//go:build !windows
+
+package daemon
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+// listen creates a Unix socket at socketPath, replacing a stale socket file
+// left by a crashed daemon. extraSID only applies to Windows pipes.
+func listen(socketPath, extraSID string) (net.Listener, error) {
+	if err := os.MkdirAll(filepath.Dir(socketPath), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create socket directory: %w", err)
+	}
+	// Remove existing socket file if present
+	_ = os.Remove(socketPath)
+
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Unix socket: %w", err)
+	}
+	return listener, nil
+}
+
+// dial connects to the daemon's IPC socket.
+func dial(socketPath string, timeout time.Duration) (net.Conn, error) {
+	return net.DialTimeout("unix", socketPath, timeout)
+}
+
+// detachedProcAttr starts the forked daemon in a new session so it outlives the terminal.
+func detachedProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{
+		Setsid: true, // Create new session
+	}
+}
+
+// ProcessAlive reports whether a process with the given PID exists.
+func ProcessAlive(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	// On Unix, FindProcess always succeeds, so we need to send signal 0
+	// to check if process exists
+	return process.Signal(syscall.Signal(0)) == nil
+}
+
+// Terminate asks a daemon process to shut down gracefully (SIGTERM).
+func Terminate(process *os.Process) error {
+	return process.Signal(syscall.SIGTERM)
+}
+
+// currentUserSID is only used for Windows pipe permissions.
+func currentUserSID() string {
+	return ""
+}
+
+// runAsService runs the daemon under a platform service manager protocol if
+// the process was started by one. Unix service managers need no handshake.
+func runAsService(d *Daemon) (handled bool, err error) {
+	return false, nil
+}
+
+// fallbackRuntimePath returns /tmp/todoat-daemon-<uid>.<ext>.
+func fallbackRuntimePath(ext string) string {
+	return fmt.Sprintf("/tmp/todoat-daemon-%d.%s", os.Getuid(), ext)
+}
@@ -0,0 +1,360 @@
+//go:build windows
+
+package daemon
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// Windows has no Unix socket files the daemon can rely on, so IPC uses a
+// named pipe whose name is derived from the configured socket path. Each
+// workspace keeps its own pipe, and the socket path setting stays meaningful.
+
+const pipeBufferSize = 4096
+
+// stillActive is the exit code GetExitCodeProcess reports for a running process.
+const stillActive = 259
+
+// PipeName returns the named pipe used for the given socket path.
+func PipeName(socketPath string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(socketPath)))
+	return `\\.\pipe\todoat-sync-` + hex.EncodeToString(sum[:8])
+}
+
+// listen creates a named pipe derived from socketPath. Only the current user,
+// SYSTEM and extraSID (the installing user when running as a service) may connect.
+func listen(socketPath, extraSID string) (net.Listener, error) {
+	sddl := "D:P(A;;GA;;;SY)"
+	for _, sid := range []string{currentUserSID(), extraSID} {
+		if sid != "" {
+			sddl += "(A;;GA;;;" + sid + ")"
+		}
+	}
+	sd, err := windows.SecurityDescriptorFromString(sddl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pipe security descriptor: %w", err)
+	}
+	l := &pipeListener{
+		name: PipeName(socketPath),
+		sa: &windows.SecurityAttributes{
+			Length:             uint32(unsafe.Sizeof(windows.SecurityAttributes{})),
+			SecurityDescriptor: sd,
+		},
+	}
+	// Create the first instance now so a second daemon fails immediately
+	h, err := l.createInstance(true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create named pipe %s: %w", l.name, err)
+	}
+	l.next = h
+	return l, nil
+}
+
+type pipeListener struct {
+	name   string
+	sa     *windows.SecurityAttributes
+	mu     sync.Mutex
+	next   windows.Handle // Pipe instance waiting for the next client
+	closed bool
+}
+
+func (l *pipeListener) createInstance(first bool) (windows.Handle, error) {
+	name, err := windows.UTF16PtrFromString(l.name)
+	if err != nil {
+		return windows.InvalidHandle, err
+	}
+	flags := uint32(windows.PIPE_ACCESS_DUPLEX)
+	if first {
+		flags |= windows.FILE_FLAG_FIRST_PIPE_INSTANCE
+	}
+	return windows.CreateNamedPipe(name, flags,
+		windows.PIPE_TYPE_BYTE|windows.PIPE_READMODE_BYTE|windows.PIPE_WAIT|windows.PIPE_REJECT_REMOTE_CLIENTS,
+		windows.PIPE_UNLIMITED_INSTANCES, pipeBufferSize, pipeBufferSize, 0, l.sa)
+}
+
+// Accept waits for a client to connect to the pipe.
+func (l *pipeListener) Accept() (net.Conn, error) {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return nil, net.ErrClosed
+	}
+	h := l.next
+	l.next = windows.InvalidHandle
+	l.mu.Unlock()
+
+	if h == windows.InvalidHandle {
+		var err error
+		if h, err = l.createInstance(false); err != nil {
+			return nil, err
+		}
+	}
+
+	err := windows.ConnectNamedPipe(h, nil)
+	if err != nil && !errors.Is(err, windows.ERROR_PIPE_CONNECTED) {
+		_ = windows.CloseHandle(h)
+		return nil, err
+	}
+
+	l.mu.Lock()
+	closed := l.closed
+	l.mu.Unlock()
+	if closed {
+		// Woken up by Close
+		_ = windows.DisconnectNamedPipe(h)
+		_ = windows.CloseHandle(h)
+		return nil, net.ErrClosed
+	}
+	return newPipeConn(h, l.name, true), nil
+}
+
+// Close stops accepting connections. A blocked Accept is woken by connecting to the pipe.
+func (l *pipeListener) Close() error {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return nil
+	}
+	l.closed = true
+	l.mu.Unlock()
+
+	if conn, err := dial(l.name, 100*time.Millisecond); err == nil {
+		_ = conn.Close()
+	}
+	return nil
+}
+
+func (l *pipeListener) Addr() net.Addr {
+	return pipeAddr(l.name)
+}
+
+// dial connects to the daemon's named pipe, waiting while all instances are busy.
+func dial(socketPath string, timeout time.Duration) (net.Conn, error) {
+	name := socketPath
+	if !strings.HasPrefix(name, `\\.\pipe\`) {
+		name = PipeName(socketPath)
+	}
+	path, err := windows.UTF16PtrFromString(name)
+	if err != nil {
+		return nil, err
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		h, err := windows.CreateFile(path, windows.GENERIC_READ|windows.GENERIC_WRITE, 0, nil, windows.OPEN_EXISTING, 0, 0)
+		if err == nil {
+			return newPipeConn(h, name, false), nil
+		}
+		if !errors.Is(err, windows.ERROR_PIPE_BUSY) || time.Now().After(deadline) {
+			return nil, &net.OpError{Op: "dial", Net: "pipe", Addr: pipeAddr(name), Err: err}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// pipeConn adapts a synchronous pipe handle to net.Conn. A deadline is a timer
+// that cancels the pending I/O on the handle with CancelIoEx when it expires;
+// the cancelled Read or Write then fails with os.ErrDeadlineExceeded.
+type pipeConn struct {
+	f      *os.File
+	h      windows.Handle
+	addr   pipeAddr
+	server bool
+	read   pipeDeadline
+	write  pipeDeadline
+}
+
+func newPipeConn(h windows.Handle, name string, server bool) *pipeConn {
+	return &pipeConn{f: os.NewFile(uintptr(h), name), h: h, addr: pipeAddr(name), server: server}
+}
+
+func (c *pipeConn) Read(b []byte) (int, error) {
+	n, err := c.do(&c.read, func() (int, error) { return c.f.Read(b) })
+	if errors.Is(err, windows.ERROR_BROKEN_PIPE) || errors.Is(err, windows.ERROR_PIPE_NOT_CONNECTED) {
+		return n, io.EOF
+	}
+	return n, err
+}
+
+func (c *pipeConn) Write(b []byte) (int, error) {
+	written := 0
+	for written < len(b) {
+		n, err := c.do(&c.write, func() (int, error) { return c.f.Write(b[written:]) })
+		written += n
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// do runs one read or write under deadline d. CancelIoEx cancels all I/O on
+// the handle, so an operation cancelled for the other direction's deadline
+// (or for a deadline moved since) is retried.
+func (c *pipeConn) do(d *pipeDeadline, op func() (int, error)) (int, error) {
+	d.active.Add(1)
+	defer d.active.Add(-1)
+	for {
+		if d.expired.Load() {
+			return 0, os.ErrDeadlineExceeded
+		}
+		n, err := op()
+		if !errors.Is(err, windows.ERROR_OPERATION_ABORTED) {
+			return n, err
+		}
+		if d.expired.Load() {
+			return n, os.ErrDeadlineExceeded
+		}
+		if n > 0 {
+			return n, nil
+		}
+	}
+}
+
+func (c *pipeConn) Close() error {
+	c.read.set(c.h, time.Time{})
+	c.write.set(c.h, time.Time{})
+	if c.server {
+		_ = windows.FlushFileBuffers(c.h)
+		_ = windows.DisconnectNamedPipe(c.h)
+	}
+	return c.f.Close()
+}
+
+func (c *pipeConn) LocalAddr() net.Addr  { return c.addr }
+func (c *pipeConn) RemoteAddr() net.Addr { return c.addr }
+
+func (c *pipeConn) SetDeadline(t time.Time) error {
+	c.read.set(c.h, t)
+	c.write.set(c.h, t)
+	return nil
+}
+
+func (c *pipeConn) SetReadDeadline(t time.Time) error {
+	c.read.set(c.h, t)
+	return nil
+}
+
+func (c *pipeConn) SetWriteDeadline(t time.Time) error {
+	c.write.set(c.h, t)
+	return nil
+}
+
+// pipeDeadline is the read or write deadline of a pipeConn
+type pipeDeadline struct {
+	mu      sync.Mutex
+	timer   *time.Timer
+	gen     uint64 // Incremented by set, so a timer of an earlier deadline does nothing
+	expired atomic.Bool
+	active  atomic.Int32 // Reads or writes in progress under this deadline
+}
+
+// set replaces the deadline with t; the zero time clears it
+func (d *pipeDeadline) set(h windows.Handle, t time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.gen++
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+	d.expired.Store(false)
+	if t.IsZero() {
+		return
+	}
+	wait := time.Until(t)
+	if wait <= 0 {
+		d.expired.Store(true)
+		go d.cancelPending(h)
+		return
+	}
+	gen := d.gen
+	d.timer = time.AfterFunc(wait, func() {
+		d.mu.Lock()
+		current := d.gen == gen
+		if current {
+			d.expired.Store(true)
+		}
+		d.mu.Unlock()
+		if current {
+			d.cancelPending(h)
+		}
+	})
+}
+
+// cancelPending cancels the I/O on h until the operations under the expired
+// deadline have returned. An operation that had not reached ReadFile or
+// WriteFile when the deadline expired is cancelled once it is pending.
+func (d *pipeDeadline) cancelPending(h windows.Handle) {
+	for d.expired.Load() && d.active.Load() > 0 {
+		_ = windows.CancelIoEx(h, nil)
+		time.Sleep(time.Millisecond)
+	}
+}
+
+type pipeAddr string
+
+func (a pipeAddr) Network() string { return "pipe" }
+func (a pipeAddr) String() string  { return string(a) }
+
+// detachedProcAttr starts the forked daemon without a console so it outlives the terminal.
+func detachedProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{
+		CreationFlags: windows.CREATE_NEW_PROCESS_GROUP | windows.DETACHED_PROCESS,
+		HideWindow:    true,
+	}
+}
+
+// ProcessAlive reports whether a process with the given PID is still running.
+func ProcessAlive(pid int) bool {
+	h, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return false
+	}
+	defer func() { _ = windows.CloseHandle(h) }()
+	var code uint32
+	if err := windows.GetExitCodeProcess(h, &code); err != nil {
+		return false
+	}
+	return code == stillActive
+}
+
+// Terminate stops a daemon process. Detached Windows processes cannot receive
+// a console interrupt, so the process is terminated directly; use
+// "sync daemon stop" for a graceful shutdown over IPC.
+func Terminate(process *os.Process) error {
+	return process.Kill()
+}
+
+// currentUserSID returns the SID of the user running this process.
+func currentUserSID() string {
+	user, err := windows.GetCurrentProcessToken().GetTokenUser()
+	if err != nil {
+		return ""
+	}
+	return user.User.Sid.String()
+}
+
+// fallbackRuntimePath returns %LOCALAPPDATA%\todoat\daemon.<ext>; Windows has
+// no shared /tmp and no numeric user IDs.
+func fallbackRuntimePath(ext string) string {
+	dir := os.Getenv("LOCALAPPDATA")
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "todoat", "daemon."+ext)
+}
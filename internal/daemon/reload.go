@@ -0,0 +1,156 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// DefaultConfigPollInterval is how often the daemon checks the config file for changes.
+const DefaultConfigPollInterval = 2 * time.Second
+
+// OfflineModeOffline is the sync.offline_mode value that pauses the daemon's syncs.
+const OfflineModeOffline = "offline"
+
+// Settings are the app config settings the daemon applies while running, so
+// editing config.yaml takes effect without restarting the daemon.
+type Settings struct {
+	Interval      time.Duration // Global sync interval (sync.daemon.interval)
+	Backends      []string      // Remote backends each sync uses
+	OfflineMode   string        // sync.offline_mode; "offline" skips syncs
+	Notifications bool          // Send sync complete/error notifications (sync.daemon.notify)
+}
+
+// Changes describes the settings that differ from old, e.g. "interval 5m0s -> 1m0s".
+func (s Settings) Changes(old Settings) []string {
+	var changes []string
+	if s.Interval != old.Interval {
+		changes = append(changes, fmt.Sprintf("interval %v -> %v", old.Interval, s.Interval))
+	}
+	if strings.Join(s.Backends, ",") != strings.Join(old.Backends, ",") {
+		changes = append(changes, fmt.Sprintf("backends [%s] -> [%s]", strings.Join(old.Backends, ", "), strings.Join(s.Backends, ", ")))
+	}
+	if s.OfflineMode != old.OfflineMode {
+		changes = append(changes, fmt.Sprintf("offline_mode %s -> %s", old.OfflineMode, s.OfflineMode))
+	}
+	if s.Notifications != old.Notifications {
+		changes = append(changes, fmt.Sprintf("notifications %v -> %v", old.Notifications, s.Notifications))
+	}
+	return changes
+}
+
+// configStamp identifies a version of the config file by modification time and size.
+type configStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// statConfig returns the stamp of the config file, or the zero stamp if it is missing.
+func (d *Daemon) statConfig() configStamp {
+	info, err := os.Stat(d.cfg.ConfigPath)
+	if err != nil {
+		return configStamp{}
+	}
+	return configStamp{modTime: info.ModTime(), size: info.Size()}
+}
+
+// hotReloadEnabled reports whether the daemon watches its config file.
+func (d *Daemon) hotReloadEnabled() bool {
+	return d.cfg.LoadSettings != nil && d.cfg.ConfigPath != ""
+}
+
+// configPollInterval returns the configured config poll interval or the default.
+func (d *Daemon) configPollInterval() time.Duration {
+	if d.cfg.ConfigPollInterval > 0 {
+		return d.cfg.ConfigPollInterval
+	}
+	return DefaultConfigPollInterval
+}
+
+// loadInitialSettings records the settings the daemon starts with. They are not
+// applied: the interval given at start (e.g. by --interval) stays in effect
+// until the config file changes it.
+func (d *Daemon) loadInitialSettings() {
+	if !d.hotReloadEnabled() {
+		return
+	}
+	stamp := d.statConfig()
+	settings, err := d.cfg.LoadSettings()
+	if err != nil {
+		d.logError("Failed to read config %s: %v", d.cfg.ConfigPath, err)
+		return
+	}
+	d.mu.Lock()
+	d.settings = &settings
+	d.configStamp = stamp
+	d.mu.Unlock()
+}
+
+// ReloadConfig re-reads the settings if the config file changed since the last
+// check, applies the ones that differ and logs them. It reports whether any
+// setting changed. A config that cannot be read keeps the current settings.
+func (d *Daemon) ReloadConfig() bool {
+	if !d.hotReloadEnabled() {
+		return false
+	}
+	stamp := d.statConfig()
+	d.mu.Lock()
+	unchanged := stamp == d.configStamp
+	d.configStamp = stamp
+	d.mu.Unlock()
+	if unchanged {
+		return false
+	}
+
+	settings, err := d.cfg.LoadSettings()
+	if err != nil {
+		d.logError("Config reload failed, keeping current settings: %v", err)
+		return false
+	}
+
+	d.mu.RLock()
+	old := Settings{Interval: d.cfg.Interval}
+	if d.settings != nil {
+		old = *d.settings
+	}
+	d.mu.RUnlock()
+
+	changes := settings.Changes(old)
+	if len(changes) == 0 {
+		return false
+	}
+
+	d.backendsMu.Lock()
+	d.mu.Lock()
+	if settings.Interval > 0 {
+		d.cfg.Interval = settings.Interval
+	}
+	d.settings = &settings
+	d.mu.Unlock()
+	d.backendsMu.Unlock()
+
+	d.log("Config reloaded: %s", strings.Join(changes, ", "))
+	return true
+}
+
+// currentSettings returns the settings read from the config file, or nil when
+// hot reload is off.
+func (d *Daemon) currentSettings() *Settings {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.settings
+}
+
+// isOffline reports whether sync.offline_mode pauses syncs.
+func (d *Daemon) isOffline() bool {
+	s := d.currentSettings()
+	return s != nil && s.OfflineMode == OfflineModeOffline
+}
+
+// notificationsEnabled reports whether sync notifications are sent: always when
+// hot reload is off, otherwise as sync.daemon.notify says.
+func (d *Daemon) notificationsEnabled() bool {
+	s := d.currentSettings()
+	return s == nil || s.Notifications
+}
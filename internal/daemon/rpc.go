@@ -0,0 +1,184 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"time"
+)
+
+// ProtocolVersion is the version of the JSON-RPC methods served on the daemon
+// socket. It only changes when a method is removed or changes incompatibly.
+const ProtocolVersion = 1
+
+// RPCIdleTimeout closes JSON-RPC connections that send no request for this long.
+const RPCIdleTimeout = 10 * time.Minute
+
+// JSON-RPC 2.0 error codes
+const (
+	RPCParseError     = -32700
+	RPCInvalidRequest = -32600
+	RPCMethodNotFound = -32601
+	RPCInvalidParams  = -32602
+	RPCInternalError  = -32603
+	// RPCCommandFailed is returned when a list or task method fails, e.g.
+	// because the list does not exist
+	RPCCommandFailed = -32000
+)
+
+// RPCRequest is a JSON-RPC 2.0 request. Requests without an id are
+// notifications and get no response.
+type RPCRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// RPCResponse is a JSON-RPC 2.0 response
+type RPCResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+// RPCError is the error of a JSON-RPC response
+type RPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"`
+}
+
+// Error returns the error message
+func (e *RPCError) Error() string {
+	return e.Message
+}
+
+// RPCHandler runs the JSON-RPC methods the daemon does not implement itself
+// (list and task methods). It returns an *RPCError to choose the error code;
+// other errors are reported as RPCCommandFailed.
+type RPCHandler func(ctx context.Context, method string, params json.RawMessage) (any, error)
+
+// VersionResult is the result of daemon.version
+type VersionResult struct {
+	Protocol int      `json:"protocol"`
+	Methods  []string `json:"methods"`
+}
+
+// daemonMethods are the methods implemented by the daemon itself
+var daemonMethods = []string{"daemon.version", "daemon.status", "sync.trigger"}
+
+// RPCMethods are the list and task methods passed to the RPCHandler
+var RPCMethods = []string{
+	"lists.list", "lists.create",
+	"tasks.list", "tasks.create", "tasks.update", "tasks.complete", "tasks.delete",
+	"cli.run",
+}
+
+// isRPCMessage reports whether the first message on a connection is a
+// JSON-RPC request rather than an internal Message
+func isRPCMessage(raw json.RawMessage) bool {
+	var probe struct {
+		JSONRPC string `json:"jsonrpc"`
+	}
+	return json.Unmarshal(raw, &probe) == nil && probe.JSONRPC != ""
+}
+
+// serveRPC answers JSON-RPC requests on conn, starting with first, until the
+// client closes the connection or stays idle for RPCIdleTimeout
+func (d *Daemon) serveRPC(conn net.Conn, decoder *json.Decoder, encoder *json.Encoder, first json.RawMessage) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-d.stopChan:
+			_ = conn.Close()
+		case <-ctx.Done():
+		}
+	}()
+
+	raw := first
+	for {
+		if resp := d.handleRPC(ctx, raw); resp != nil {
+			if err := encoder.Encode(resp); err != nil {
+				return
+			}
+		}
+		_ = conn.SetReadDeadline(time.Now().Add(RPCIdleTimeout))
+		raw = nil
+		if err := decoder.Decode(&raw); err != nil {
+			var syntaxErr *json.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				_ = encoder.Encode(rpcErrorResponse(nil, RPCParseError, "parse error: "+err.Error()))
+			}
+			if !errors.Is(err, io.EOF) {
+				d.logAt(slog.LevelDebug, "JSON-RPC connection closed: %v", err)
+			}
+			return
+		}
+	}
+}
+
+// handleRPC answers one JSON-RPC request; it returns nil for notifications
+func (d *Daemon) handleRPC(ctx context.Context, raw json.RawMessage) *RPCResponse {
+	var req RPCRequest
+	if err := json.Unmarshal(raw, &req); err != nil {
+		return rpcErrorResponse(nil, RPCInvalidRequest, "invalid request: "+err.Error())
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return rpcErrorResponse(req.ID, RPCInvalidRequest, `invalid request: jsonrpc must be "2.0" and method is required`)
+	}
+
+	result, err := d.callRPC(ctx, req.Method, req.Params)
+	if len(req.ID) == 0 || bytes.Equal(req.ID, []byte("null")) {
+		return nil
+	}
+	if err != nil {
+		var rpcErr *RPCError
+		if !errors.As(err, &rpcErr) {
+			rpcErr = &RPCError{Code: RPCCommandFailed, Message: err.Error()}
+		}
+		return &RPCResponse{JSONRPC: "2.0", ID: req.ID, Error: rpcErr}
+	}
+	return &RPCResponse{JSONRPC: "2.0", ID: req.ID, Result: result}
+}
+
+// callRPC runs a method
+func (d *Daemon) callRPC(ctx context.Context, method string, params json.RawMessage) (any, error) {
+	switch method {
+	case "daemon.version":
+		methods := append([]string(nil), daemonMethods...)
+		if d.cfg.RPCHandler != nil {
+			methods = append(methods, RPCMethods...)
+		}
+		return VersionResult{Protocol: ProtocolVersion, Methods: methods}, nil
+	case "daemon.status":
+		return d.statusResponse(), nil
+	case "sync.trigger":
+		if d.isOffline() {
+			return nil, &RPCError{Code: RPCCommandFailed, Message: "sync is paused (sync.offline_mode: offline)"}
+		}
+		go d.performSync()
+		return map[string]bool{"triggered": true}, nil
+	}
+
+	for _, m := range RPCMethods {
+		if m == method && d.cfg.RPCHandler != nil {
+			d.log("JSON-RPC %s", method)
+			return d.cfg.RPCHandler(ctx, method, params)
+		}
+	}
+	return nil, &RPCError{Code: RPCMethodNotFound, Message: "method not found: " + method}
+}
+
+func rpcErrorResponse(id json.RawMessage, code int, message string) *RPCResponse {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	return &RPCResponse{JSONRPC: "2.0", ID: id, Error: &RPCError{Code: code, Message: message}}
+}
@@ -0,0 +1,38 @@
+package daemon
+
+import (
+	"time"
+)
+
+// DefaultScheduleInterval is how often the daemon runs its scheduled jobs.
+const DefaultScheduleInterval = time.Minute
+
+// ScheduledFunc runs time-based jobs such as the daily digest. It is called
+// with the current time at start and then every ScheduleInterval, and decides
+// itself which jobs are due, so a job missed while the daemon was not running
+// runs on the first call. A returned error is written to the daemon log.
+type ScheduledFunc func(now time.Time) error
+
+// scheduleInterval returns the configured schedule interval or the default.
+func (d *Daemon) scheduleInterval() time.Duration {
+	if d.cfg.ScheduleInterval > 0 {
+		return d.cfg.ScheduleInterval
+	}
+	return DefaultScheduleInterval
+}
+
+// runScheduled runs the scheduled jobs in the background so a slow delivery
+// does not hold up syncs. A call is skipped while the previous one is running.
+func (d *Daemon) runScheduled(now time.Time) {
+	if d.cfg.Scheduled == nil || !d.scheduledRunning.CompareAndSwap(false, true) {
+		return
+	}
+	d.scheduledWG.Add(1)
+	go func() {
+		defer d.scheduledWG.Done()
+		defer d.scheduledRunning.Store(false)
+		if err := d.cfg.Scheduled(now); err != nil {
+			d.logError("Scheduled job failed: %v", err)
+		}
+	}()
+}
@@ -0,0 +1,445 @@
+package daemon
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Service identifiers used for the installed user service.
+const (
+	// ServiceName is the systemd unit name (without suffix).
+	ServiceName = "todoat-sync"
+	// LaunchdLabel is the launchd job label.
+	LaunchdLabel = "com.todoat.sync"
+)
+
+// Service manager kinds supported by InstallService.
+const (
+	ServiceManagerSystemd = "systemd"
+	ServiceManagerLaunchd = "launchd"
+	ServiceManagerWindows = "windows" // Windows service registered with sc.exe
+)
+
+// listenFDsStart is the first file descriptor passed by systemd socket activation.
+const listenFDsStart = 3
+
+// CommandRunner runs an external service manager command such as systemctl or launchctl.
+type CommandRunner func(name string, args ...string) error
+
+// ExecCommand runs a command and includes its combined output in the error on failure.
+func ExecCommand(name string, args ...string) error {
+	out, err := exec.Command(name, args...).CombinedOutput()
+	if err != nil {
+		msg := strings.TrimSpace(string(out))
+		if msg != "" {
+			return fmt.Errorf("%s %s: %w: %s", name, strings.Join(args, " "), err, msg)
+		}
+		return fmt.Errorf("%s %s: %w", name, strings.Join(args, " "), err)
+	}
+	return nil
+}
+
+// ServiceOptions describes how the daemon should be installed as a user service.
+type ServiceOptions struct {
+	Manager          string  // ServiceManagerSystemd or ServiceManagerLaunchd; empty selects by OS
+	Dir              string  // Directory for the unit/plist files; empty uses the manager default
+	Executable       string  // Binary to run; empty uses the current executable
+	Daemon           *Config // Daemon settings passed as --daemon-* flags
+	SocketActivation bool    // systemd only: also install a .socket unit that starts the daemon on demand
+}
+
+// ServiceFile is a generated service definition.
+type ServiceFile struct {
+	Path    string
+	Content string
+}
+
+// ServicePlan lists the files and manager commands for an install or uninstall.
+type ServicePlan struct {
+	Manager  string
+	Files    []ServiceFile
+	Commands [][]string
+}
+
+// DefaultServiceManager returns the service manager for the current OS, or an
+// error if the platform has no supported user service manager.
+func DefaultServiceManager() (string, error) {
+	switch runtime.GOOS {
+	case "linux":
+		return ServiceManagerSystemd, nil
+	case "darwin":
+		return ServiceManagerLaunchd, nil
+	case "windows":
+		return ServiceManagerWindows, nil
+	default:
+		return "", fmt.Errorf("service installation is not supported on %s", runtime.GOOS)
+	}
+}
+
+// DefaultServiceDir returns the per-user directory where the manager looks for
+// service files. Windows services are registered without files.
+func DefaultServiceDir(manager string) (string, error) {
+	if manager == ServiceManagerWindows {
+		return "", nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	switch manager {
+	case ServiceManagerSystemd:
+		configHome := os.Getenv("XDG_CONFIG_HOME")
+		if configHome == "" {
+			configHome = filepath.Join(home, ".config")
+		}
+		return filepath.Join(configHome, "systemd", "user"), nil
+	case ServiceManagerLaunchd:
+		return filepath.Join(home, "Library", "LaunchAgents"), nil
+	default:
+		return "", fmt.Errorf("unknown service manager: %s", manager)
+	}
+}
+
+// resolve fills in defaults for the manager and directory.
+func (o ServiceOptions) resolve() (ServiceOptions, error) {
+	if o.Manager == "" {
+		manager, err := DefaultServiceManager()
+		if err != nil {
+			return o, err
+		}
+		o.Manager = manager
+	}
+	switch o.Manager {
+	case ServiceManagerSystemd, ServiceManagerLaunchd, ServiceManagerWindows:
+	default:
+		return o, fmt.Errorf("unknown service manager: %s", o.Manager)
+	}
+	if o.SocketActivation && o.Manager != ServiceManagerSystemd {
+		return o, fmt.Errorf("socket activation is only supported with systemd")
+	}
+	if o.Dir == "" {
+		dir, err := DefaultServiceDir(o.Manager)
+		if err != nil {
+			return o, err
+		}
+		o.Dir = dir
+	}
+	return o, nil
+}
+
+// currentExecutable returns the path of the running binary with symlinks resolved,
+// so the service keeps working when invoked through a symlink.
+func currentExecutable() (string, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("failed to get executable path: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(executable); err == nil {
+		executable = resolved
+	}
+	return executable, nil
+}
+
+// serviceArgs returns the daemon-mode arguments for a managed service.
+// Without socket activation the idle timeout is dropped: the service manager
+// would not restart a daemon that exited cleanly, so it must keep running.
+func serviceArgs(o ServiceOptions) []string {
+	cfg := Config{}
+	if o.Daemon != nil {
+		cfg = *o.Daemon
+	}
+	if !o.SocketActivation {
+		cfg.IdleTimeout = 0
+	}
+	return buildForkArgs(&cfg)
+}
+
+// InstallPlan returns the files to write and the commands that enable the service.
+func InstallPlan(opts ServiceOptions) (*ServicePlan, error) {
+	o, err := opts.resolve()
+	if err != nil {
+		return nil, err
+	}
+	if o.Daemon == nil || o.Daemon.SocketPath == "" {
+		return nil, fmt.Errorf("daemon socket path is required")
+	}
+	if o.Executable == "" {
+		if o.Executable, err = currentExecutable(); err != nil {
+			return nil, err
+		}
+	}
+	plan := &ServicePlan{Manager: o.Manager}
+	switch o.Manager {
+	case ServiceManagerSystemd:
+		plan.Files = append(plan.Files, ServiceFile{
+			Path:    filepath.Join(o.Dir, ServiceName+".service"),
+			Content: SystemdUnit(o.Executable, serviceArgs(o), o.SocketActivation),
+		})
+		unit := ServiceName + ".service"
+		if o.SocketActivation {
+			plan.Files = append(plan.Files, ServiceFile{
+				Path:    filepath.Join(o.Dir, ServiceName+".socket"),
+				Content: SystemdSocketUnit(o.Daemon.SocketPath),
+			})
+			unit = ServiceName + ".socket"
+		}
+		plan.Commands = [][]string{
+			{"systemctl", "--user", "daemon-reload"},
+			{"systemctl", "--user", "enable", "--now", unit},
+		}
+	case ServiceManagerLaunchd:
+		path := filepath.Join(o.Dir, LaunchdLabel+".plist")
+		plan.Files = append(plan.Files, ServiceFile{
+			Path:    path,
+			Content: LaunchdPlist(o.Executable, serviceArgs(o), o.Daemon.LogPath),
+		})
+		plan.Commands = [][]string{{"launchctl", "load", "-w", path}}
+	case ServiceManagerWindows:
+		// The service runs as LocalSystem; let the installing user reach its pipe
+		daemonCfg := *o.Daemon
+		daemonCfg.IPCUserSID = currentUserSID()
+		o.Daemon = &daemonCfg
+		binPath := windowsCommandLine(append([]string{o.Executable}, serviceArgs(o)...))
+		plan.Commands = [][]string{
+			{"sc.exe", "create", ServiceName, "binPath=", binPath, "start=", "auto", "DisplayName=", "todoat sync daemon"},
+			{"sc.exe", "failure", ServiceName, "reset=", "86400", "actions=", "restart/10000"},
+			{"sc.exe", "start", ServiceName},
+		}
+	}
+	return plan, nil
+}
+
+// UninstallPlan returns the files to remove and the commands that disable the service.
+func UninstallPlan(opts ServiceOptions) (*ServicePlan, error) {
+	o, err := opts.resolve()
+	if err != nil {
+		return nil, err
+	}
+	plan := &ServicePlan{Manager: o.Manager}
+	switch o.Manager {
+	case ServiceManagerSystemd:
+		// Only disable units that are installed; systemctl fails on unknown units
+		disable := []string{"systemctl", "--user", "disable", "--now"}
+		for _, unit := range []string{ServiceName + ".socket", ServiceName + ".service"} {
+			path := filepath.Join(o.Dir, unit)
+			plan.Files = append(plan.Files, ServiceFile{Path: path})
+			if _, err := os.Stat(path); err == nil {
+				disable = append(disable, unit)
+			}
+		}
+		if len(disable) > 4 {
+			plan.Commands = [][]string{disable}
+		}
+	case ServiceManagerLaunchd:
+		path := filepath.Join(o.Dir, LaunchdLabel+".plist")
+		plan.Files = []ServiceFile{{Path: path}}
+		plan.Commands = [][]string{{"launchctl", "unload", "-w", path}}
+	case ServiceManagerWindows:
+		// Deleting a running service only marks it; UninstallService stops it first
+		plan.Commands = [][]string{{"sc.exe", "delete", ServiceName}}
+	}
+	return plan, nil
+}
+
+// InstallService writes the service files and, when run is non-nil, enables the service.
+func InstallService(opts ServiceOptions, run CommandRunner) (*ServicePlan, error) {
+	plan, err := InstallPlan(opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range plan.Files {
+		if err := os.MkdirAll(filepath.Dir(f.Path), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create service directory: %w", err)
+		}
+		if err := os.WriteFile(f.Path, []byte(f.Content), 0644); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", f.Path, err)
+		}
+	}
+	if run == nil {
+		return plan, nil
+	}
+	for _, c := range plan.Commands {
+		if err := run(c[0], c[1:]...); err != nil {
+			return plan, fmt.Errorf("failed to enable service: %w", err)
+		}
+	}
+	return plan, nil
+}
+
+// UninstallService disables the service when run is non-nil and removes its files.
+// Files are removed even if disabling fails; the disable error is returned afterwards.
+func UninstallService(opts ServiceOptions, run CommandRunner) (*ServicePlan, []string, error) {
+	plan, err := UninstallPlan(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	var disableErr error
+	if run != nil {
+		if plan.Manager == ServiceManagerWindows {
+			_ = run("sc.exe", "stop", ServiceName) // Fails if already stopped
+		}
+		for _, c := range plan.Commands {
+			if err := run(c[0], c[1:]...); err != nil && disableErr == nil {
+				disableErr = fmt.Errorf("failed to disable service: %w", err)
+			}
+		}
+	}
+	var removed []string
+	for _, f := range plan.Files {
+		err := os.Remove(f.Path)
+		if err == nil {
+			removed = append(removed, f.Path)
+		} else if !os.IsNotExist(err) {
+			return plan, removed, fmt.Errorf("failed to remove %s: %w", f.Path, err)
+		}
+	}
+	if disableErr == nil && run != nil && plan.Manager == ServiceManagerSystemd && len(removed) > 0 {
+		disableErr = run("systemctl", "--user", "daemon-reload")
+	}
+	return plan, removed, disableErr
+}
+
+// SystemdUnit renders a systemd user service running the daemon in the foreground.
+func SystemdUnit(executable string, args []string, socketActivated bool) string {
+	var b strings.Builder
+	b.WriteString("[Unit]\n")
+	b.WriteString("Description=todoat sync daemon\n")
+	if socketActivated {
+		b.WriteString("Requires=" + ServiceName + ".socket\n")
+		b.WriteString("After=" + ServiceName + ".socket\n")
+	}
+	b.WriteString("\n[Service]\n")
+	b.WriteString("Type=simple\n")
+	b.WriteString("ExecStart=" + systemdQuote(append([]string{executable}, args...)) + "\n")
+	b.WriteString("Restart=on-failure\n")
+	b.WriteString("RestartSec=10\n")
+	if !socketActivated {
+		b.WriteString("\n[Install]\n")
+		b.WriteString("WantedBy=default.target\n")
+	}
+	return b.String()
+}
+
+// SystemdSocketUnit renders a systemd socket unit listening on the daemon socket,
+// so the daemon is started on the first CLI connection.
+func SystemdSocketUnit(socketPath string) string {
+	var b strings.Builder
+	b.WriteString("[Unit]\n")
+	b.WriteString("Description=todoat sync daemon socket\n")
+	b.WriteString("\n[Socket]\n")
+	b.WriteString("ListenStream=" + socketPath + "\n")
+	b.WriteString("SocketMode=0600\n")
+	b.WriteString("RemoveOnStop=true\n")
+	b.WriteString("\n[Install]\n")
+	b.WriteString("WantedBy=sockets.target\n")
+	return b.String()
+}
+
+// systemdQuote joins a command line, quoting words that contain spaces or quotes.
+func systemdQuote(words []string) string {
+	quoted := make([]string, len(words))
+	for i, w := range words {
+		if w == "" || strings.ContainsAny(w, " \t\"'\\") {
+			w = strings.ReplaceAll(w, `\`, `\\`)
+			w = strings.ReplaceAll(w, `"`, `\"`)
+			w = `"` + w + `"`
+		}
+		quoted[i] = strings.ReplaceAll(w, "%", "%%")
+	}
+	return strings.Join(quoted, " ")
+}
+
+// windowsCommandLine joins a command line using the quoting rules of
+// CommandLineToArgvW, as used for a Windows service binary path.
+func windowsCommandLine(words []string) string {
+	quoted := make([]string, len(words))
+	for i, w := range words {
+		if w != "" && !strings.ContainsAny(w, " \t\"") {
+			quoted[i] = w
+			continue
+		}
+		var b strings.Builder
+		b.WriteByte('"')
+		slashes := 0
+		for _, r := range w {
+			switch r {
+			case '\\':
+				slashes++
+			case '"':
+				// Backslashes before a quote are escaped, then the quote itself
+				b.WriteString(strings.Repeat(`\`, slashes+1))
+				slashes = 0
+			default:
+				slashes = 0
+			}
+			b.WriteRune(r)
+		}
+		// Backslashes before the closing quote are escaped
+		b.WriteString(strings.Repeat(`\`, slashes))
+		b.WriteByte('"')
+		quoted[i] = b.String()
+	}
+	return strings.Join(quoted, " ")
+}
+
+// LaunchdPlist renders a launchd agent that keeps the daemon running.
+func LaunchdPlist(executable string, args []string, logPath string) string {
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	b.WriteString(`<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">` + "\n")
+	b.WriteString(`<plist version="1.0">` + "\n<dict>\n")
+	writePlistKey(&b, "Label", LaunchdLabel)
+	b.WriteString("\t<key>ProgramArguments</key>\n\t<array>\n")
+	for _, arg := range append([]string{executable}, args...) {
+		b.WriteString("\t\t<string>")
+		_ = xml.EscapeText(&b, []byte(arg))
+		b.WriteString("</string>\n")
+	}
+	b.WriteString("\t</array>\n")
+	b.WriteString("\t<key>RunAtLoad</key>\n\t<true/>\n")
+	b.WriteString("\t<key>KeepAlive</key>\n\t<dict>\n\t\t<key>SuccessfulExit</key>\n\t\t<false/>\n\t</dict>\n")
+	if logPath != "" {
+		writePlistKey(&b, "StandardErrorPath", logPath+".stderr")
+	}
+	b.WriteString("</dict>\n</plist>\n")
+	return b.String()
+}
+
+func writePlistKey(b *bytes.Buffer, key, value string) {
+	b.WriteString("\t<key>" + key + "</key>\n\t<string>")
+	_ = xml.EscapeText(b, []byte(value))
+	b.WriteString("</string>\n")
+}
+
+// activatedListener returns the listening socket passed by systemd socket
+// activation, or nil if the process was not socket-activated.
+func activatedListener() (net.Listener, error) {
+	pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || n < 1 {
+		return nil, nil
+	}
+	// Don't pass the activation environment on to child processes
+	_ = os.Unsetenv("LISTEN_PID")
+	_ = os.Unsetenv("LISTEN_FDS")
+	_ = os.Unsetenv("LISTEN_FDNAMES")
+
+	f := os.NewFile(uintptr(listenFDsStart), "todoat-sync.socket")
+	defer func() { _ = f.Close() }()
+	listener, err := net.FileListener(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to use activated socket: %w", err)
+	}
+	return listener, nil
+}
@@ -0,0 +1,58 @@
+//go:build windows
+
+package daemon
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/windows/svc"
+)
+
+// runAsService runs the daemon under the Windows service control manager when
+// the process was started as a service, reporting start/stop state to it.
+func runAsService(d *Daemon) (handled bool, err error) {
+	isService, err := svc.IsWindowsService()
+	if err != nil || !isService {
+		return false, nil
+	}
+	h := &serviceHandler{d: d}
+	if err := svc.Run(ServiceName, h); err != nil {
+		return true, fmt.Errorf("failed to run as Windows service: %w", err)
+	}
+	return true, h.err
+}
+
+type serviceHandler struct {
+	d   *Daemon
+	err error
+}
+
+// Execute implements svc.Handler.
+func (h *serviceHandler) Execute(args []string, r <-chan svc.ChangeRequest, changes chan<- svc.Status) (bool, uint32) {
+	changes <- svc.Status{State: svc.StartPending}
+	done := make(chan error, 1)
+	go func() { done <- h.d.Start() }()
+	changes <- svc.Status{State: svc.Running, Accepts: svc.AcceptStop | svc.AcceptShutdown}
+
+	for {
+		select {
+		case err := <-done:
+			// Daemon exited on its own (idle timeout, repeated errors or IPC stop)
+			h.err = err
+			if err != nil {
+				return true, 1
+			}
+			return false, 0
+		case c := <-r:
+			switch c.Cmd {
+			case svc.Interrogate:
+				changes <- c.CurrentStatus
+			case svc.Stop, svc.Shutdown:
+				changes <- svc.Status{State: svc.StopPending}
+				h.d.Stop()
+				h.err = <-done
+				return false, 0
+			}
+		}
+	}
+}
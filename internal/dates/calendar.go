@@ -0,0 +1,220 @@
+// Package dates moves dates to working days: it skips weekends and the
+// holidays of a country or of an iCalendar file, and counts business days.
+package dates
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"todoat/internal/ical"
+	"todoat/internal/recurrence"
+	"todoat/internal/tz"
+)
+
+// maxHolidayOccurrences bounds the days a recurring event of a holiday
+// calendar is expanded to
+const maxHolidayOccurrences = 500
+
+// day is a calendar day, independent of time zone and time of day
+type day struct {
+	year  int
+	month time.Month
+	day   int
+}
+
+// dayOf returns the calendar day of t in the local time zone
+func dayOf(t time.Time) day {
+	t = t.In(tz.Location())
+	return day{t.Year(), t.Month(), t.Day()}
+}
+
+// Calendar tells working days from weekends and holidays. The zero Calendar
+// has no holidays and does not skip weekends when adjusting dates.
+type Calendar struct {
+	// SkipWeekends makes Adjust move dates on Saturday or Sunday to Monday
+	SkipWeekends bool
+
+	mu           sync.Mutex
+	holidays     map[day]string
+	country      string
+	countryYears map[int]bool // Years whose country holidays are in holidays
+}
+
+// NewCalendar returns a calendar without holidays
+func NewCalendar(skipWeekends bool) *Calendar {
+	return &Calendar{SkipWeekends: skipWeekends}
+}
+
+// Load returns a calendar with the holidays of source: a country code such as
+// "US" (see Countries), or the path of an iCalendar (.ics) file. An empty
+// source adds no holidays.
+func Load(skipWeekends bool, source string) (*Calendar, error) {
+	c := NewCalendar(skipWeekends)
+	switch {
+	case source == "":
+	case IsICSPath(source):
+		if err := c.LoadICS(source); err != nil {
+			return nil, err
+		}
+	default:
+		if err := c.SetCountry(source); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// IsICSPath reports whether a holiday source names an iCalendar file rather
+// than a country
+func IsICSPath(source string) bool {
+	return strings.HasSuffix(strings.ToLower(source), ".ics") || strings.ContainsRune(source, filepath.Separator)
+}
+
+// AddHoliday marks the calendar day of t as a holiday
+func (c *Calendar) AddHoliday(t time.Time, name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.addHoliday(t, name)
+}
+
+func (c *Calendar) addHoliday(t time.Time, name string) {
+	if c.holidays == nil {
+		c.holidays = make(map[day]string)
+	}
+	c.holidays[dayOf(t)] = name
+}
+
+// SetCountry adds the public holidays of a country, given by its ISO 3166
+// code, for every year
+func (c *Calendar) SetCountry(code string) error {
+	code = strings.ToUpper(code)
+	if !IsCountry(code) {
+		return fmt.Errorf("unknown holiday country %q (supported: %s, or the path of an .ics file)", code, strings.Join(Countries(), ", "))
+	}
+	c.country = code
+	c.countryYears = make(map[int]bool)
+	return nil
+}
+
+// LoadICS adds the all-day events of an iCalendar file as holidays. Events
+// spanning several days mark each of them; yearly and other recurring events
+// are expanded with their RRULE.
+func (c *Calendar) LoadICS(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read holiday calendar: %w", err)
+	}
+	components, err := ical.Parse(string(data))
+	if err != nil {
+		return fmt.Errorf("invalid holiday calendar %s: %w", path, err)
+	}
+	for _, cal := range components {
+		for _, event := range cal.Find("VEVENT") {
+			if err := c.addEvent(event); err != nil {
+				return fmt.Errorf("invalid holiday calendar %s: %w", path, err)
+			}
+		}
+	}
+	return nil
+}
+
+// addEvent marks the days of an event as holidays
+func (c *Calendar) addEvent(event *ical.Component) error {
+	startProp := event.Get("DTSTART")
+	if startProp == nil {
+		return nil
+	}
+	start, _, err := ical.ParseTime(*startProp)
+	if err != nil {
+		return err
+	}
+	start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, tz.Location())
+	days := 1
+	if endProp := event.Get("DTEND"); endProp != nil {
+		if end, _, err := ical.ParseTime(*endProp); err == nil {
+			end = time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, tz.Location())
+			// DTEND is exclusive
+			days = max(1, int(end.Sub(start).Hours()/24+0.5))
+		}
+	}
+	name := ""
+	if summary := event.Get("SUMMARY"); summary != nil {
+		name = summary.Text()
+	}
+
+	starts := []time.Time{start}
+	if rruleProp := event.Get("RRULE"); rruleProp != nil {
+		rule, err := recurrence.Parse(rruleProp.Value)
+		if err != nil {
+			return err
+		}
+		starts = append(starts, rule.Occurrences(start, maxHolidayOccurrences)...)
+	}
+	for _, s := range starts {
+		for i := 0; i < days; i++ {
+			c.AddHoliday(s.AddDate(0, 0, i), name)
+		}
+	}
+	return nil
+}
+
+// Holiday returns the name of the holiday on the calendar day of t, if any
+func (c *Calendar) Holiday(t time.Time) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// Country holidays are computed for the years asked about
+	year := t.In(tz.Location()).Year()
+	if c.country != "" && !c.countryYears[year] {
+		c.countryYears[year] = true
+		for _, h := range countryHolidays[c.country](year) {
+			c.addHoliday(h.date, h.name)
+		}
+	}
+	name, ok := c.holidays[dayOf(t)]
+	return name, ok
+}
+
+// IsWeekend reports whether t falls on a Saturday or Sunday
+func IsWeekend(t time.Time) bool {
+	return t.Weekday() == time.Saturday || t.Weekday() == time.Sunday
+}
+
+// IsBusinessDay reports whether t is neither on a weekend nor a holiday
+func (c *Calendar) IsBusinessDay(t time.Time) bool {
+	_, holiday := c.Holiday(t)
+	return !IsWeekend(t) && !holiday
+}
+
+// Adjust moves t forward, keeping its time of day, past holidays and, with
+// SkipWeekends, past weekends
+func (c *Calendar) Adjust(t time.Time) time.Time {
+	for i := 0; i < 366; i++ {
+		_, holiday := c.Holiday(t)
+		if !holiday && (!c.SkipWeekends || !IsWeekend(t)) {
+			break
+		}
+		t = t.AddDate(0, 0, 1)
+	}
+	return t
+}
+
+// AddBusinessDays moves t by n business days, skipping weekends and holidays;
+// a negative n moves backwards. One business day after a Friday or a Saturday
+// is the next Monday.
+func (c *Calendar) AddBusinessDays(t time.Time, n int) time.Time {
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+	for n > 0 {
+		t = t.AddDate(0, 0, step)
+		if c.IsBusinessDay(t) {
+			n--
+		}
+	}
+	return t
+}
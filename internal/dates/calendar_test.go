@@ -0,0 +1,83 @@
+package dates
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCountryHolidays(t *testing.T) {
+	tests := []struct {
+		country string
+		day     time.Time
+		want    string
+	}{
+		{"US", date(2026, time.November, 26), "Thanksgiving Day"},
+		{"US", date(2026, time.May, 25), "Memorial Day"},
+		{"GB", date(2026, time.April, 6), "Easter Monday"},
+		{"DE", date(2027, time.May, 6), "Christi Himmelfahrt"},
+		{"CA", date(2026, time.May, 18), "Victoria Day"},
+	}
+	for _, tt := range tests {
+		c, err := Load(false, tt.country)
+		if err != nil {
+			t.Fatalf("Load(%s): %v", tt.country, err)
+		}
+		if name, ok := c.Holiday(tt.day); !ok || name != tt.want {
+			t.Errorf("%s holiday on %s = %q, %v; want %q", tt.country, tt.day.Format("2006-01-02"), name, ok, tt.want)
+		}
+	}
+	if _, err := Load(false, "XX"); err == nil {
+		t.Error("Load(XX) succeeded, want an unknown country error")
+	}
+}
+
+func TestAdjustAndAddBusinessDays(t *testing.T) {
+	c, err := Load(true, "US")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Saturday 2026-07-04 is Independence Day; the weekend moves to Monday
+	saturday := time.Date(2026, time.July, 4, 9, 30, 0, 0, time.Local)
+	if got := c.Adjust(saturday); !got.Equal(time.Date(2026, time.July, 6, 9, 30, 0, 0, time.Local)) {
+		t.Errorf("Adjust(%v) = %v, want Monday 09:30", saturday, got)
+	}
+	// Thanksgiving Thursday is skipped, as is the weekend
+	wednesday := date(2026, time.November, 25)
+	if got := c.AddBusinessDays(wednesday, 3); !got.Equal(date(2026, time.December, 1)) {
+		t.Errorf("AddBusinessDays(+3) = %v, want 2026-12-01", got)
+	}
+	if got := c.AddBusinessDays(date(2026, time.November, 30), -2); !got.Equal(date(2026, time.November, 25)) {
+		t.Errorf("AddBusinessDays(-2) = %v, want 2026-11-25", got)
+	}
+
+	// Without skip_weekends only holidays move
+	holidaysOnly, _ := Load(false, "US")
+	if got := holidaysOnly.Adjust(date(2026, time.October, 17)); !got.Equal(date(2026, time.October, 17)) {
+		t.Errorf("Adjust moved a Saturday without SkipWeekends: %v", got)
+	}
+}
+
+func TestLoadICS(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "company.ics")
+	ics := "BEGIN:VCALENDAR\r\n" +
+		"BEGIN:VEVENT\r\nDTSTART;VALUE=DATE:20261224\r\nDTEND;VALUE=DATE:20261227\r\nSUMMARY:Winter break\r\nEND:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\nDTSTART;VALUE=DATE:20200501\r\nRRULE:FREQ=YEARLY\r\nSUMMARY:Company day\r\nEND:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+	if err := os.WriteFile(path, []byte(ics), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c, err := Load(false, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range []time.Time{date(2026, 12, 24), date(2026, 12, 26), date(2031, 5, 1)} {
+		if _, ok := c.Holiday(d); !ok {
+			t.Errorf("%s is not a holiday", d.Format("2006-01-02"))
+		}
+	}
+	if _, ok := c.Holiday(date(2026, 12, 27)); ok {
+		t.Error("DTEND day is a holiday, want it excluded")
+	}
+}
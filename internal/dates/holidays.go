@@ -0,0 +1,144 @@
+package dates
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"todoat/internal/tz"
+)
+
+// holiday is a public holiday of a year
+type holiday struct {
+	date time.Time
+	name string
+}
+
+// countryHolidays returns the national public holidays of a year, by country
+// code. Regional holidays and days observed in lieu of holidays falling on a
+// weekend are not included.
+var countryHolidays = map[string]func(year int) []holiday{
+	"US": func(y int) []holiday {
+		return []holiday{
+			{date(y, time.January, 1), "New Year's Day"},
+			{nthWeekday(y, time.January, time.Monday, 3), "Martin Luther King Jr. Day"},
+			{nthWeekday(y, time.February, time.Monday, 3), "Presidents' Day"},
+			{nthWeekday(y, time.May, time.Monday, -1), "Memorial Day"},
+			{date(y, time.June, 19), "Juneteenth"},
+			{date(y, time.July, 4), "Independence Day"},
+			{nthWeekday(y, time.September, time.Monday, 1), "Labor Day"},
+			{nthWeekday(y, time.October, time.Monday, 2), "Columbus Day"},
+			{date(y, time.November, 11), "Veterans Day"},
+			{nthWeekday(y, time.November, time.Thursday, 4), "Thanksgiving Day"},
+			{date(y, time.December, 25), "Christmas Day"},
+		}
+	},
+	"CA": func(y int) []holiday {
+		easter := easterSunday(y)
+		// Victoria Day is the last Monday before May 25
+		victoria := date(y, time.May, 24)
+		for victoria.Weekday() != time.Monday {
+			victoria = victoria.AddDate(0, 0, -1)
+		}
+		return []holiday{
+			{date(y, time.January, 1), "New Year's Day"},
+			{easter.AddDate(0, 0, -2), "Good Friday"},
+			{victoria, "Victoria Day"},
+			{date(y, time.July, 1), "Canada Day"},
+			{nthWeekday(y, time.September, time.Monday, 1), "Labour Day"},
+			{nthWeekday(y, time.October, time.Monday, 2), "Thanksgiving"},
+			{date(y, time.December, 25), "Christmas Day"},
+			{date(y, time.December, 26), "Boxing Day"},
+		}
+	},
+	"GB": func(y int) []holiday {
+		easter := easterSunday(y)
+		return []holiday{
+			{date(y, time.January, 1), "New Year's Day"},
+			{easter.AddDate(0, 0, -2), "Good Friday"},
+			{easter.AddDate(0, 0, 1), "Easter Monday"},
+			{nthWeekday(y, time.May, time.Monday, 1), "Early May Bank Holiday"},
+			{nthWeekday(y, time.May, time.Monday, -1), "Spring Bank Holiday"},
+			{nthWeekday(y, time.August, time.Monday, -1), "Summer Bank Holiday"},
+			{date(y, time.December, 25), "Christmas Day"},
+			{date(y, time.December, 26), "Boxing Day"},
+		}
+	},
+	"DE": func(y int) []holiday {
+		easter := easterSunday(y)
+		return []holiday{
+			{date(y, time.January, 1), "Neujahr"},
+			{easter.AddDate(0, 0, -2), "Karfreitag"},
+			{easter.AddDate(0, 0, 1), "Ostermontag"},
+			{date(y, time.May, 1), "Tag der Arbeit"},
+			{easter.AddDate(0, 0, 39), "Christi Himmelfahrt"},
+			{easter.AddDate(0, 0, 50), "Pfingstmontag"},
+			{date(y, time.October, 3), "Tag der Deutschen Einheit"},
+			{date(y, time.December, 25), "1. Weihnachtstag"},
+			{date(y, time.December, 26), "2. Weihnachtstag"},
+		}
+	},
+	"FR": func(y int) []holiday {
+		easter := easterSunday(y)
+		return []holiday{
+			{date(y, time.January, 1), "Jour de l'an"},
+			{easter.AddDate(0, 0, 1), "Lundi de Pâques"},
+			{date(y, time.May, 1), "Fête du Travail"},
+			{date(y, time.May, 8), "Victoire 1945"},
+			{easter.AddDate(0, 0, 39), "Ascension"},
+			{easter.AddDate(0, 0, 50), "Lundi de Pentecôte"},
+			{date(y, time.July, 14), "Fête nationale"},
+			{date(y, time.August, 15), "Assomption"},
+			{date(y, time.November, 1), "Toussaint"},
+			{date(y, time.November, 11), "Armistice 1918"},
+			{date(y, time.December, 25), "Noël"},
+		}
+	},
+}
+
+// Countries returns the codes of the countries with built-in holidays
+func Countries() []string {
+	codes := make([]string, 0, len(countryHolidays))
+	for code := range countryHolidays {
+		codes = append(codes, code)
+	}
+	slices.Sort(codes)
+	return codes
+}
+
+// IsCountry reports whether code is a country with built-in holidays
+func IsCountry(code string) bool {
+	_, ok := countryHolidays[strings.ToUpper(code)]
+	return ok
+}
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, tz.Location())
+}
+
+// nthWeekday returns the n-th weekday of a month; n = -1 is the last one
+func nthWeekday(y int, m time.Month, wd time.Weekday, n int) time.Time {
+	if n < 0 {
+		last := date(y, m+1, 0)
+		return last.AddDate(0, 0, -((int(last.Weekday()) - int(wd) + 7) % 7))
+	}
+	first := date(y, m, 1)
+	return first.AddDate(0, 0, (int(wd)-int(first.Weekday())+7)%7+7*(n-1))
+}
+
+// easterSunday returns the date of Easter Sunday in the Gregorian calendar
+// (anonymous Gregorian algorithm)
+func easterSunday(y int) time.Time {
+	a := y % 19
+	b, c := y/100, y%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	dom := (h+l-7*m+114)%31 + 1
+	return date(y, time.Month(month), dom)
+}
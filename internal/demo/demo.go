@@ -0,0 +1,173 @@
+// Package demo holds the sample lists and tasks of the demo backend, a
+// throwaway database for trying todoat without touching real data.
+package demo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"todoat/backend"
+)
+
+// BackendName is the name that selects the demo backend (todoat -b demo)
+const BackendName = "demo"
+
+// Task is a sample task. Due is the number of days from today the task is due
+// (nil for no due date).
+type Task struct {
+	Summary     string
+	Description string
+	Status      backend.TaskStatus
+	Priority    int
+	Due         *int
+	Tags        string
+	Recurrence  string
+	Subtasks    []Task
+}
+
+// List is a sample list and its top-level tasks
+type List struct {
+	Name  string
+	Color string
+	Tasks []Task
+}
+
+// days returns a due date offset for a sample task
+func days(n int) *int {
+	return &n
+}
+
+// Lists is the sample data written to a new demo database
+var Lists = []List{
+	{
+		Name:  "MyProjects",
+		Color: "#3B82F6",
+		Tasks: []Task{
+			{
+				Summary:  "Launch personal website",
+				Priority: 1,
+				Due:      days(5),
+				Tags:     "web",
+				Subtasks: []Task{
+					{Summary: "Pick a static site generator", Status: backend.StatusCompleted},
+					{Summary: "Write the About page", Due: days(2)},
+					{Summary: "Set up DNS and HTTPS", Due: days(4), Tags: "web,ops"},
+				},
+			},
+			{
+				Summary:     "Prepare conference talk",
+				Description: "20 minute talk on terminal productivity",
+				Status:      backend.StatusInProgress,
+				Priority:    3,
+				Due:         days(14),
+				Tags:        "speaking",
+				Subtasks: []Task{
+					{Summary: "Draft outline", Status: backend.StatusCompleted},
+					{Summary: "Make slides", Due: days(10)},
+					{Summary: "Rehearse with a friend", Due: days(12)},
+				},
+			},
+			{Summary: "Renew domain name", Priority: 2, Due: days(-2), Tags: "web"},
+			{Summary: "Archive old GitHub repositories", Priority: 7},
+		},
+	},
+	{
+		Name:  "Home",
+		Color: "#22C55E",
+		Tasks: []Task{
+			{Summary: "Buy groceries", Description: "Milk, eggs, bread, coffee", Due: days(0), Tags: "errands"},
+			{Summary: "Pay electricity bill", Priority: 2, Due: days(3), Recurrence: "FREQ=MONTHLY;INTERVAL=1"},
+			{Summary: "Water the plants", Due: days(1), Recurrence: "FREQ=WEEKLY;INTERVAL=1"},
+			{Summary: "Fix leaking kitchen tap", Priority: 4},
+			{Summary: "Book dentist appointment", Status: backend.StatusCompleted, Tags: "health"},
+		},
+	},
+	{
+		Name:  "Reading",
+		Color: "#A855F7",
+		Tasks: []Task{
+			{Summary: "The Pragmatic Programmer", Status: backend.StatusInProgress, Tags: "books"},
+			{Summary: "Designing Data-Intensive Applications", Tags: "books"},
+			{Summary: "Thinking, Fast and Slow", Status: backend.StatusCompleted, Tags: "books"},
+		},
+	},
+}
+
+// Seed creates the sample lists and tasks of Lists in be, with due dates
+// relative to now
+func Seed(ctx context.Context, be backend.TaskManager, now time.Time) error {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	for _, l := range Lists {
+		list, err := be.CreateList(ctx, l.Name)
+		if err != nil {
+			return fmt.Errorf("failed to create list '%s': %w", l.Name, err)
+		}
+		if l.Color != "" {
+			list.Color = l.Color
+			if _, err := be.UpdateList(ctx, list); err != nil {
+				return fmt.Errorf("failed to update list '%s': %w", l.Name, err)
+			}
+		}
+		for _, t := range l.Tasks {
+			if err := createTask(ctx, be, list.ID, "", t, today, now); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// createTask creates a sample task and its subtasks under parentID
+func createTask(ctx context.Context, be backend.TaskManager, listID, parentID string, t Task, today, now time.Time) error {
+	task := &backend.Task{
+		Summary:     t.Summary,
+		Description: t.Description,
+		Status:      t.Status,
+		Priority:    t.Priority,
+		ParentID:    parentID,
+		Categories:  t.Tags,
+		Recurrence:  t.Recurrence,
+	}
+	if task.Status == "" {
+		task.Status = backend.StatusNeedsAction
+	}
+	if task.Recurrence != "" {
+		task.RecurFromDue = true
+	}
+	if t.Due != nil {
+		due := today.AddDate(0, 0, *t.Due)
+		task.DueDate = &due
+	}
+	if task.Status == backend.StatusCompleted {
+		completed := now
+		task.Completed = &completed
+	}
+	created, err := be.CreateTask(ctx, listID, task)
+	if err != nil {
+		return fmt.Errorf("failed to create task '%s': %w", t.Summary, err)
+	}
+	for _, sub := range t.Subtasks {
+		if err := createTask(ctx, be, listID, created.ID, sub, today, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TaskCount returns the number of tasks, subtasks included, that Seed creates
+func TaskCount() int {
+	var count func(tasks []Task) int
+	count = func(tasks []Task) int {
+		n := len(tasks)
+		for _, t := range tasks {
+			n += count(t.Subtasks)
+		}
+		return n
+	}
+	total := 0
+	for _, l := range Lists {
+		total += count(l.Tasks)
+	}
+	return total
+}
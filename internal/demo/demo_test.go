@@ -0,0 +1,59 @@
+package demo
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"todoat/backend"
+	"todoat/backend/sqlite"
+)
+
+func TestSeedCreatesSampleData(t *testing.T) {
+	be, err := sqlite.New(filepath.Join(t.TempDir(), "demo.db"))
+	if err != nil {
+		t.Fatalf("sqlite.New: %v", err)
+	}
+	defer func() { _ = be.Close() }()
+
+	ctx := context.Background()
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	if err := Seed(ctx, be, now); err != nil {
+		t.Fatalf("Seed: %v", err)
+	}
+
+	lists, err := be.GetLists(ctx)
+	if err != nil {
+		t.Fatalf("GetLists: %v", err)
+	}
+	if len(lists) != len(Lists) {
+		t.Fatalf("got %d lists, want %d", len(lists), len(Lists))
+	}
+
+	total := 0
+	for _, l := range lists {
+		tasks, err := be.GetTasks(ctx, l.ID)
+		if err != nil {
+			t.Fatalf("GetTasks(%s): %v", l.Name, err)
+		}
+		total += len(tasks)
+		for _, task := range tasks {
+			if task.Summary == "Renew domain name" {
+				want := time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)
+				if task.DueDate == nil || !task.DueDate.Equal(want) {
+					t.Errorf("due date of %q = %v, want %v", task.Summary, task.DueDate, want)
+				}
+			}
+			if task.Summary == "Write the About page" && task.ParentID == "" {
+				t.Errorf("%q has no parent", task.Summary)
+			}
+			if task.Summary == "Book dentist appointment" && task.Status != backend.StatusCompleted {
+				t.Errorf("status of %q = %s, want %s", task.Summary, task.Status, backend.StatusCompleted)
+			}
+		}
+	}
+	if total != TaskCount() {
+		t.Errorf("got %d tasks, want %d", total, TaskCount())
+	}
+}
@@ -0,0 +1,258 @@
+// Package duplicate finds tasks whose summaries are near-duplicates, such as
+// the same task quick-added on two devices before sync converged.
+package duplicate
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"todoat/backend"
+	"todoat/internal/tz"
+)
+
+// DefaultThreshold is the similarity from which two summaries count as duplicates
+const DefaultThreshold = 0.85
+
+// Normalize lowercases a summary, drops punctuation and collapses whitespace
+func Normalize(summary string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range strings.ToLower(summary) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			space = false
+			b.WriteRune(r)
+		case unicode.IsSpace(r):
+			space = true
+		}
+	}
+	return b.String()
+}
+
+// Similarity returns how alike two summaries are after normalization, from 0
+// (nothing in common) to 1 (equal), based on their Levenshtein distance
+func Similarity(a, b string) float64 {
+	ra, rb := []rune(Normalize(a)), []rune(Normalize(b))
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+// Find returns the open tasks whose summary is at least threshold similar to summary
+func Find(summary string, tasks []backend.Task, threshold float64) []backend.Task {
+	var matches []backend.Task
+	for _, t := range tasks {
+		base := t.Status.Base()
+		if base == backend.StatusCompleted || base == backend.StatusCancelled {
+			continue
+		}
+		if Similarity(summary, t.Summary) >= threshold {
+			matches = append(matches, t)
+		}
+	}
+	return matches
+}
+
+// Strategies for picking the task a group of duplicates is merged into
+const (
+	StrategyNewest = "newest" // The most recently modified task
+	StrategyOldest = "oldest" // The first created task
+)
+
+// Strategies lists the accepted merge strategies
+var Strategies = []string{StrategyNewest, StrategyOldest}
+
+// Groups returns the likely duplicates among tasks, as groups of indexes into
+// tasks in their original order. Two open tasks are duplicates when their
+// summaries are at least threshold similar and their due and start dates fall
+// on the same day or are missing on either side; groups are the tasks linked by
+// such pairs.
+func Groups(tasks []backend.Task, threshold float64) [][]int {
+	parent := make([]int, len(tasks))
+	for i := range parent {
+		parent[i] = i
+	}
+	var root func(i int) int
+	root = func(i int) int {
+		if parent[i] != i {
+			parent[i] = root(parent[i])
+		}
+		return parent[i]
+	}
+
+	normalized := make([][]rune, len(tasks))
+	for i, t := range tasks {
+		base := t.Status.Base()
+		if base == backend.StatusCompleted || base == backend.StatusCancelled {
+			continue
+		}
+		normalized[i] = []rune(Normalize(t.Summary))
+	}
+	for i := range tasks {
+		if len(normalized[i]) == 0 {
+			continue
+		}
+		for j := i + 1; j < len(tasks); j++ {
+			a, b := normalized[i], normalized[j]
+			if len(b) == 0 || !datesOverlap(tasks[i], tasks[j]) {
+				continue
+			}
+			// The length difference alone is a lower bound of the distance
+			longest := max(len(a), len(b))
+			if 1-float64(abs(len(a)-len(b)))/float64(longest) < threshold {
+				continue
+			}
+			if 1-float64(levenshtein(a, b))/float64(longest) >= threshold {
+				parent[root(j)] = root(i)
+			}
+		}
+	}
+
+	members := make(map[int][]int)
+	var roots []int
+	for i := range tasks {
+		if len(normalized[i]) == 0 {
+			continue
+		}
+		r := root(i)
+		if _, ok := members[r]; !ok {
+			roots = append(roots, r)
+		}
+		members[r] = append(members[r], i)
+	}
+	var groups [][]int
+	for _, r := range roots {
+		if len(members[r]) > 1 {
+			groups = append(groups, members[r])
+		}
+	}
+	return groups
+}
+
+// Keeper returns the index in tasks of the task to merge the others into
+func Keeper(tasks []backend.Task, strategy string) int {
+	keep := 0
+	for i, t := range tasks[1:] {
+		switch strategy {
+		case StrategyOldest:
+			if t.Created.Before(tasks[keep].Created) {
+				keep = i + 1
+			}
+		default:
+			if t.Modified.After(tasks[keep].Modified) {
+				keep = i + 1
+			}
+		}
+	}
+	return keep
+}
+
+// Merge returns keeper with what its duplicates add to it: their descriptions
+// when different, their tags, and their priority, dates and metadata where
+// keeper has none
+func Merge(keeper backend.Task, duplicates []backend.Task) backend.Task {
+	merged := keeper
+	var descriptions []string
+	if strings.TrimSpace(keeper.Description) != "" {
+		descriptions = append(descriptions, keeper.Description)
+	}
+	tags := splitTags(keeper.Categories)
+	if keeper.Metadata != nil {
+		merged.Metadata = maps.Clone(keeper.Metadata)
+	}
+	for _, d := range duplicates {
+		if desc := strings.TrimSpace(d.Description); desc != "" && !slices.ContainsFunc(descriptions, func(existing string) bool {
+			return strings.Contains(existing, desc)
+		}) {
+			descriptions = append(descriptions, desc)
+		}
+		for _, tag := range splitTags(d.Categories) {
+			if !slices.ContainsFunc(tags, func(existing string) bool { return strings.EqualFold(existing, tag) }) {
+				tags = append(tags, tag)
+			}
+		}
+		if merged.Priority == 0 {
+			merged.Priority = d.Priority
+		}
+		if merged.DueDate == nil {
+			merged.DueDate = d.DueDate
+		}
+		if merged.StartDate == nil {
+			merged.StartDate = d.StartDate
+		}
+		for key, value := range d.Metadata {
+			if _, ok := merged.Metadata[key]; !ok {
+				if merged.Metadata == nil {
+					merged.Metadata = make(map[string]string)
+				}
+				merged.Metadata[key] = value
+			}
+		}
+	}
+	merged.Description = strings.Join(descriptions, "\n\n")
+	merged.Categories = strings.Join(tags, ",")
+	return merged
+}
+
+// splitTags splits comma-separated categories, dropping empty ones
+func splitTags(categories string) []string {
+	var tags []string
+	for _, tag := range strings.Split(categories, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// datesOverlap reports whether the due and start dates of two tasks agree: on
+// the same local day, or missing on either side
+func datesOverlap(a, b backend.Task) bool {
+	return sameDay(a.DueDate, b.DueDate) && sameDay(a.StartDate, b.StartDate)
+}
+
+// sameDay reports whether two optional times are on the same local day; a
+// missing time matches any day
+func sameDay(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return true
+	}
+	return a.In(tz.Location()).Format("2006-01-02") == b.In(tz.Location()).Format("2006-01-02")
+}
+
+// abs returns the absolute value of n
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// levenshtein returns the number of single-rune edits that turn a into b
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,110 @@
+package duplicate
+
+import (
+	"testing"
+	"time"
+
+	"todoat/backend"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"  Buy   Milk! ":      "buy milk",
+		"Call mom, re: trip?": "call mom re trip",
+		"Ünïcode\tsummary":    "ünïcode summary",
+		"...":                 "",
+	}
+	for in, want := range tests {
+		if got := Normalize(in); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	if got := Similarity("Buy milk", "buy milk."); got != 1 {
+		t.Errorf("Similarity of equal normalized summaries = %v, want 1", got)
+	}
+	if got := Similarity("Buy milk", "Buy milkk"); got < DefaultThreshold {
+		t.Errorf("Similarity with one typo = %v, want at least %v", got, DefaultThreshold)
+	}
+	if got := Similarity("Buy milk", "Walk dog"); got >= DefaultThreshold {
+		t.Errorf("Similarity of different summaries = %v, want below %v", got, DefaultThreshold)
+	}
+}
+
+func TestFindSkipsClosedTasks(t *testing.T) {
+	tasks := []backend.Task{
+		{ID: "open", Summary: "Renew passport"},
+		{ID: "typo", Summary: "Renew pasport", Status: backend.StatusInProgress},
+		{ID: "done", Summary: "Renew passport", Status: backend.StatusCompleted},
+		{ID: "other", Summary: "Renew car insurance"},
+	}
+	matches := Find("renew passport", tasks, DefaultThreshold)
+	if len(matches) != 2 || matches[0].ID != "open" || matches[1].ID != "typo" {
+		t.Errorf("Find = %+v, want open and typo", matches)
+	}
+}
+
+func TestGroupsRequireOverlappingDates(t *testing.T) {
+	monday := time.Date(2026, 3, 9, 9, 0, 0, 0, time.Local)
+	mondayEvening := monday.Add(8 * time.Hour)
+	tuesday := monday.AddDate(0, 0, 1)
+	tasks := []backend.Task{
+		{ID: "a", Summary: "Pay rent", DueDate: &monday},
+		{ID: "b", Summary: "Walk dog"},
+		{ID: "c", Summary: "pay rent!", DueDate: &mondayEvening},
+		{ID: "d", Summary: "Pay rent", DueDate: &tuesday},
+		{ID: "e", Summary: "Pay rent"},
+		{ID: "f", Summary: "Pay rent", Status: backend.StatusCompleted},
+		{ID: "g", Summary: "Walk the dog tomorrow"},
+	}
+	groups := Groups(tasks, DefaultThreshold)
+	// e has no date, so it links the Monday and Tuesday tasks into one group
+	if len(groups) != 1 || len(groups[0]) != 4 {
+		t.Fatalf("Groups = %v, want one group of a, c, d and e", groups)
+	}
+
+	tasks[4].Summary = "Call the bank"
+	groups = Groups(tasks, DefaultThreshold)
+	if len(groups) != 1 || len(groups[0]) != 2 || groups[0][0] != 0 || groups[0][1] != 2 {
+		t.Errorf("Groups without the undated task = %v, want [[0 2]]", groups)
+	}
+}
+
+func TestKeeperAndMerge(t *testing.T) {
+	now := time.Date(2026, 3, 9, 9, 0, 0, 0, time.UTC)
+	due := now.AddDate(0, 0, 3)
+	tasks := []backend.Task{
+		{ID: "old", Summary: "Pay rent", Created: now.Add(-time.Hour), Modified: now, Description: "Landlord IBAN in notes", Categories: "home", Metadata: map[string]string{"owner": "sam"}},
+		{ID: "new", Summary: "Pay rent", Created: now, Modified: now.Add(time.Hour), Priority: 2, DueDate: &due, Categories: "Home,money", Metadata: map[string]string{"owner": "alex", "cost": "900"}},
+	}
+	if got := Keeper(tasks, StrategyNewest); got != 1 {
+		t.Errorf("Keeper(newest) = %d, want 1", got)
+	}
+	if got := Keeper(tasks, StrategyOldest); got != 0 {
+		t.Errorf("Keeper(oldest) = %d, want 0", got)
+	}
+
+	merged := Merge(tasks[0], tasks[1:])
+	if merged.ID != "old" || merged.Priority != 2 || merged.DueDate == nil || !merged.DueDate.Equal(due) {
+		t.Errorf("Merge kept %+v", merged)
+	}
+	if merged.Categories != "home,money" {
+		t.Errorf("merged categories = %q, want home,money", merged.Categories)
+	}
+	if merged.Description != "Landlord IBAN in notes" {
+		t.Errorf("merged description = %q", merged.Description)
+	}
+	if merged.Metadata["owner"] != "sam" || merged.Metadata["cost"] != "900" {
+		t.Errorf("merged metadata = %v", merged.Metadata)
+	}
+	if tasks[0].Metadata["cost"] != "" {
+		t.Error("Merge must not change the keeper's metadata map")
+	}
+
+	tasks[1].Description = "Transfer before the 5th"
+	if merged := Merge(tasks[0], tasks[1:]); merged.Description != "Landlord IBAN in notes\n\nTransfer before the 5th" {
+		t.Errorf("merged descriptions = %q", merged.Description)
+	}
+}
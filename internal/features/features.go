@@ -0,0 +1,137 @@
+// Package features provides the experimental feature flags that gate large,
+// incrementally shipped subsystems, and local (never transmitted) usage counters for them.
+package features
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Feature describes an experimental feature that must be enabled explicitly
+type Feature struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// Experimental lists the features that can be enabled with features.experimental
+// in config or --enable-feature on the command line
+var Experimental = []Feature{
+	{Name: "crdt_sync", Description: "Conflict-free replicated sync that merges concurrent edits field by field"},
+	{Name: "rest_server", Description: "Local REST API server for tasks and lists"},
+	{Name: "board_view", Description: "Kanban board layout in the TUI, one column per status"},
+}
+
+// Lookup returns the experimental feature with the given name
+func Lookup(name string) (Feature, bool) {
+	for _, f := range Experimental {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return Feature{}, false
+}
+
+// Validate returns an error naming the first unknown feature in names
+func Validate(names []string) error {
+	for _, name := range names {
+		if _, ok := Lookup(name); !ok {
+			return fmt.Errorf("unknown experimental feature %q (available: %s)", name, strings.Join(Names(), ", "))
+		}
+	}
+	return nil
+}
+
+// Names returns the names of all experimental features
+func Names() []string {
+	names := make([]string, len(Experimental))
+	for i, f := range Experimental {
+		names[i] = f.Name
+	}
+	return names
+}
+
+// Set is the set of experimental features enabled for a run
+type Set struct {
+	enabled map[string]bool
+}
+
+// NewSet combines the feature names from config and command-line flags,
+// rejecting unknown names
+func NewSet(names ...[]string) (*Set, error) {
+	s := &Set{enabled: map[string]bool{}}
+	for _, group := range names {
+		if err := Validate(group); err != nil {
+			return nil, err
+		}
+		for _, name := range group {
+			s.enabled[name] = true
+		}
+	}
+	return s, nil
+}
+
+// Enabled reports whether the named feature is enabled. A nil Set has no features enabled.
+func (s *Set) Enabled(name string) bool {
+	return s != nil && s.enabled[name]
+}
+
+// Names returns the enabled feature names in sorted order
+func (s *Set) Names() []string {
+	if s == nil {
+		return nil
+	}
+	names := make([]string, 0, len(s.enabled))
+	for name := range s.enabled {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Usage counts how often an experimental feature has been used on this machine
+type Usage struct {
+	Count    int       `json:"count"`
+	LastUsed time.Time `json:"last_used"`
+}
+
+// LoadUsage reads the usage counters stored at path. A missing file yields no counters.
+func LoadUsage(path string) (map[string]Usage, error) {
+	usage := map[string]Usage{}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return usage, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read feature usage: %w", err)
+	}
+	if err := json.Unmarshal(data, &usage); err != nil {
+		return nil, fmt.Errorf("failed to parse feature usage: %w", err)
+	}
+	return usage, nil
+}
+
+// RecordUse increments the usage counter of the named feature stored at path
+func RecordUse(path, name string) error {
+	usage, err := LoadUsage(path)
+	if err != nil {
+		return err
+	}
+	u := usage[name]
+	u.Count++
+	u.LastUsed = time.Now().UTC()
+	usage[name] = u
+
+	data, err := json.MarshalIndent(usage, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create feature usage directory: %w", err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
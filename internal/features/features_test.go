@@ -0,0 +1,55 @@
+package features
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewSetCombinesSources(t *testing.T) {
+	s, err := NewSet([]string{"crdt_sync"}, []string{"board_view", "crdt_sync"})
+	if err != nil {
+		t.Fatalf("NewSet: %v", err)
+	}
+	if !s.Enabled("crdt_sync") || !s.Enabled("board_view") {
+		t.Errorf("expected crdt_sync and board_view enabled, got %v", s.Names())
+	}
+	if s.Enabled("rest_server") {
+		t.Error("rest_server should not be enabled")
+	}
+	if got := strings.Join(s.Names(), ","); got != "board_view,crdt_sync" {
+		t.Errorf("Names() = %q", got)
+	}
+
+	var none *Set
+	if none.Enabled("crdt_sync") {
+		t.Error("nil Set should have no features enabled")
+	}
+}
+
+func TestNewSetRejectsUnknownFeature(t *testing.T) {
+	_, err := NewSet([]string{"time_travel"})
+	if err == nil || !strings.Contains(err.Error(), `unknown experimental feature "time_travel"`) {
+		t.Fatalf("expected unknown feature error, got %v", err)
+	}
+}
+
+func TestRecordUse(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", "features.json")
+
+	for i := 0; i < 2; i++ {
+		if err := RecordUse(path, "crdt_sync"); err != nil {
+			t.Fatalf("RecordUse: %v", err)
+		}
+	}
+	usage, err := LoadUsage(path)
+	if err != nil {
+		t.Fatalf("LoadUsage: %v", err)
+	}
+	if usage["crdt_sync"].Count != 2 || usage["crdt_sync"].LastUsed.IsZero() {
+		t.Errorf("unexpected usage: %+v", usage["crdt_sync"])
+	}
+	if _, ok := usage["board_view"]; ok {
+		t.Error("board_view should have no usage")
+	}
+}
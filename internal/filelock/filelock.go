@@ -0,0 +1,68 @@
+// Package filelock provides advisory file locks that serialize writers across todoat processes.
+package filelock
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"todoat/internal/utils"
+)
+
+// DefaultTimeout is how long Acquire waits for another process to release the lock.
+const DefaultTimeout = 10 * time.Second
+
+// retryInterval is the delay between lock attempts while another process holds the lock.
+const retryInterval = 50 * time.Millisecond
+
+// errLocked is returned by tryLock when another process holds the lock.
+var errLocked = errors.New("lock held by another process")
+
+// Lock is an exclusive advisory lock on a file.
+type Lock struct {
+	path string
+	file *os.File
+}
+
+// Acquire takes an exclusive lock on path, creating the file if needed.
+// While another process holds the lock it retries every 50ms, logging each
+// wait at debug level, and gives up with an error after timeout.
+func Acquire(path string, timeout time.Duration) (*Lock, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for attempt := 1; ; attempt++ {
+		err := tryLock(f)
+		if err == nil {
+			return &Lock{path: path, file: f}, nil
+		}
+		if !errors.Is(err, errLocked) {
+			_ = f.Close()
+			return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+		}
+		if time.Now().After(deadline) {
+			_ = f.Close()
+			return nil, fmt.Errorf("timed out after %s waiting for another todoat process to release %s", timeout, path)
+		}
+		utils.Debugf("Waiting for write lock %s (attempt %d)", path, attempt)
+		time.Sleep(retryInterval)
+	}
+}
+
+// Release unlocks and closes the lock file. The file itself is left in place
+// so concurrent processes keep locking the same inode.
+func (l *Lock) Release() error {
+	if l == nil || l.file == nil {
+		return nil
+	}
+	err := unlock(l.file)
+	if cerr := l.file.Close(); err == nil {
+		err = cerr
+	}
+	l.file = nil
+	return err
+}
@@ -0,0 +1,32 @@
+//go:build !unix && !windows
+
+package filelock
+
+import (
+	"os"
+	"sync"
+)
+
+// Without flock, only writers within this process are serialized; other
+// processes rely on SQLite's busy timeout.
+var (
+	mu     sync.Mutex
+	locked = map[string]bool{}
+)
+
+func tryLock(f *os.File) error {
+	mu.Lock()
+	defer mu.Unlock()
+	if locked[f.Name()] {
+		return errLocked
+	}
+	locked[f.Name()] = true
+	return nil
+}
+
+func unlock(f *os.File) error {
+	mu.Lock()
+	defer mu.Unlock()
+	delete(locked, f.Name())
+	return nil
+}
@@ -0,0 +1,60 @@
+package filelock
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestAcquireRelease verifies a released lock can be taken again
+func TestAcquireRelease(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db.lock")
+
+	lock, err := Acquire(path, time.Second)
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	if err := lock.Release(); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+
+	lock, err = Acquire(path, time.Second)
+	if err != nil {
+		t.Fatalf("re-Acquire: %v", err)
+	}
+	if err := lock.Release(); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+}
+
+// TestAcquireWaitsForHolder verifies a second writer waits for the first and times out if it never releases
+func TestAcquireWaitsForHolder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db.lock")
+
+	held, err := Acquire(path, time.Second)
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+
+	if _, err := Acquire(path, 100*time.Millisecond); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("expected timeout while lock is held, got %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		lock, err := Acquire(path, 5*time.Second)
+		if err == nil {
+			err = lock.Release()
+		}
+		done <- err
+	}()
+
+	time.Sleep(150 * time.Millisecond)
+	if err := held.Release(); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("waiting writer failed after release: %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+// Package focus runs focus (pomodoro) sessions on a task and keeps the running
+// session in a small state file so other views, such as the TUI status bar,
+// can show its countdown.
+package focus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultDuration is the length of a session when none is given
+const DefaultDuration = 25 * time.Minute
+
+// Clock is the time source of a session. Tests replace SystemClock with a
+// clock that advances on After.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// SystemClock is the wall clock
+var SystemClock Clock = systemClock{}
+
+// Session is a focus session on one task
+type Session struct {
+	TaskID  string    `json:"task_id"`
+	Summary string    `json:"summary"`
+	List    string    `json:"list"`
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+}
+
+// Remaining returns the time left at now, never less than zero
+func (s *Session) Remaining(now time.Time) time.Duration {
+	return max(s.End.Sub(now), 0)
+}
+
+// Run waits until the session ends or ctx is cancelled, calling tick about
+// once a second with the remaining time. It returns how long the session ran
+// and whether it reached its end.
+func Run(ctx context.Context, clock Clock, s *Session, tick func(remaining time.Duration)) (time.Duration, bool) {
+	for {
+		now := clock.Now()
+		remaining := s.Remaining(now)
+		if tick != nil {
+			tick(remaining)
+		}
+		if remaining == 0 {
+			return s.End.Sub(s.Start), true
+		}
+		if ctx.Err() != nil {
+			return now.Sub(s.Start), false
+		}
+		select {
+		case <-ctx.Done():
+			return clock.Now().Sub(s.Start), false
+		case <-clock.After(min(remaining, time.Second)):
+		}
+	}
+}
+
+// FormatRemaining formats a countdown as mm:ss, or h:mm:ss from one hour
+func FormatRemaining(d time.Duration) string {
+	total := int(d.Round(time.Second) / time.Second)
+	if total >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", total/3600, total%3600/60, total%60)
+	}
+	return fmt.Sprintf("%02d:%02d", total/60, total%60)
+}
+
+// Save writes the running session to path
+func Save(path string, s *Session) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// Load reads the session at path. It returns nil without an error when no
+// session is running.
+func Load(path string) (*Session, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var s Session
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("invalid focus session file %s: %w", path, err)
+	}
+	return &s, nil
+}
+
+// Clear removes the session file at path
+func Clear(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
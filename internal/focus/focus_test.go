@@ -0,0 +1,95 @@
+package focus
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// fakeClock advances by the requested duration on every After call
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.now = c.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+func TestRunTicksUntilEnd(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)}
+	s := &Session{Start: clock.now, End: clock.now.Add(3*time.Second + 500*time.Millisecond)}
+
+	var ticks []string
+	elapsed, completed := Run(context.Background(), clock, s, func(remaining time.Duration) {
+		ticks = append(ticks, FormatRemaining(remaining))
+	})
+	if !completed || elapsed != 3500*time.Millisecond {
+		t.Errorf("Run = %v, %v; want 3.5s, true", elapsed, completed)
+	}
+	want := []string{"00:04", "00:03", "00:02", "00:01", "00:00"}
+	if len(ticks) != len(want) {
+		t.Fatalf("ticks = %v, want %v", ticks, want)
+	}
+	for i := range want {
+		if ticks[i] != want[i] {
+			t.Errorf("ticks = %v, want %v", ticks, want)
+			break
+		}
+	}
+}
+
+func TestRunStopsOnCancel(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)}
+	s := &Session{Start: clock.now, End: clock.now.Add(DefaultDuration)}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ticks := 0
+	elapsed, completed := Run(ctx, clock, s, func(time.Duration) {
+		if ticks++; ticks == 3 {
+			cancel()
+		}
+	})
+	if completed || elapsed > 3*time.Second {
+		t.Errorf("Run = %v, %v; want at most 3s, false", elapsed, completed)
+	}
+}
+
+func TestFormatRemaining(t *testing.T) {
+	tests := map[time.Duration]string{
+		25 * time.Minute:                      "25:00",
+		59*time.Second + 600*time.Millisecond: "01:00",
+		90 * time.Minute:                      "1:30:00",
+	}
+	for d, want := range tests {
+		if got := FormatRemaining(d); got != want {
+			t.Errorf("FormatRemaining(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+func TestSessionFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "focus.json")
+	if s, err := Load(path); s != nil || err != nil {
+		t.Fatalf("Load(missing) = %v, %v; want nil, nil", s, err)
+	}
+	start := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	if err := Save(path, &Session{TaskID: "t1", Summary: "Write report", List: "Work", Start: start, End: start.Add(DefaultDuration)}); err != nil {
+		t.Fatalf("Save error: %v", err)
+	}
+	s, err := Load(path)
+	if err != nil || s == nil || s.Summary != "Write report" || !s.End.Equal(start.Add(DefaultDuration)) {
+		t.Fatalf("Load = %+v, %v", s, err)
+	}
+	if err := Clear(path); err != nil {
+		t.Fatalf("Clear error: %v", err)
+	}
+	if s, _ := Load(path); s != nil {
+		t.Errorf("Load after Clear = %+v, want nil", s)
+	}
+}
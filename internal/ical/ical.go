@@ -0,0 +1,422 @@
+// Package ical reads and writes iCalendar (RFC 5545) content: folded content
+// lines, property parameters, text escaping, date-time values and durations.
+package ical
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"todoat/internal/tz"
+)
+
+// Date-time layouts used by iCalendar values
+const (
+	DateTimeUTCFormat = "20060102T150405Z"
+	DateTimeFormat    = "20060102T150405"
+	DateFormat        = "20060102"
+)
+
+// maxLineOctets is the longest content line allowed before folding
+const maxLineOctets = 75
+
+// Property is a content line: NAME;PARAM=value:VALUE. Parameter names are
+// upper-cased; the value is kept as written (still escaped for TEXT values).
+type Property struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// Param returns the value of a parameter, or "" if it is not set
+func (p Property) Param(name string) string {
+	return p.Params[strings.ToUpper(name)]
+}
+
+// Text returns the value unescaped as a TEXT value
+func (p Property) Text() string {
+	return Unescape(p.Value)
+}
+
+// Component is a BEGIN/END block with its properties and nested components
+type Component struct {
+	Name       string
+	Properties []Property
+	Components []*Component
+}
+
+// Get returns the first property with the given name, or nil
+func (c *Component) Get(name string) *Property {
+	for i := range c.Properties {
+		if c.Properties[i].Name == name {
+			return &c.Properties[i]
+		}
+	}
+	return nil
+}
+
+// GetAll returns every property with the given name
+func (c *Component) GetAll(name string) []Property {
+	var props []Property
+	for _, p := range c.Properties {
+		if p.Name == name {
+			props = append(props, p)
+		}
+	}
+	return props
+}
+
+// Find returns the nested components with the given name at any depth, in document order
+func (c *Component) Find(name string) []*Component {
+	var found []*Component
+	for _, child := range c.Components {
+		if child.Name == name {
+			found = append(found, child)
+		}
+		found = append(found, child.Find(name)...)
+	}
+	return found
+}
+
+// Parse reads iCalendar content and returns its top-level components
+// (normally a single VCALENDAR). Folded lines are joined first; both CRLF and
+// bare LF line endings are accepted.
+func Parse(data string) ([]*Component, error) {
+	root := &Component{}
+	stack := []*Component{root}
+
+	for i, line := range unfold(data) {
+		if line == "" {
+			continue
+		}
+		prop, err := ParseProperty(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		current := stack[len(stack)-1]
+		switch prop.Name {
+		case "BEGIN":
+			comp := &Component{Name: strings.ToUpper(prop.Value)}
+			current.Components = append(current.Components, comp)
+			stack = append(stack, comp)
+		case "END":
+			if len(stack) == 1 || current.Name != strings.ToUpper(prop.Value) {
+				return nil, fmt.Errorf("line %d: unexpected END:%s", i+1, prop.Value)
+			}
+			stack = stack[:len(stack)-1]
+		default:
+			current.Properties = append(current.Properties, prop)
+		}
+	}
+	if len(stack) > 1 {
+		return nil, fmt.Errorf("missing END:%s", stack[len(stack)-1].Name)
+	}
+	return root.Components, nil
+}
+
+// unfold splits content into logical lines, joining continuation lines that
+// start with a space or tab onto the previous line
+func unfold(data string) []string {
+	data = strings.ReplaceAll(data, "\r\n", "\n")
+	var lines []string
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// ParseProperty splits an unfolded content line into name, parameters and value.
+// Colons and semicolons inside quoted parameter values do not end the parameter.
+func ParseProperty(line string) (Property, error) {
+	prop := Property{Params: map[string]string{}}
+
+	end := strings.IndexAny(line, ";:")
+	if end <= 0 {
+		return prop, fmt.Errorf("malformed content line %q", line)
+	}
+	prop.Name = strings.ToUpper(line[:end])
+
+	rest := line[end:]
+	for strings.HasPrefix(rest, ";") {
+		rest = rest[1:]
+		eq := strings.IndexByte(rest, '=')
+		if eq <= 0 {
+			return prop, fmt.Errorf("malformed parameter in %q", line)
+		}
+		name := strings.ToUpper(rest[:eq])
+		rest = rest[eq+1:]
+
+		var value strings.Builder
+		quoted := false
+		i := 0
+		for ; i < len(rest); i++ {
+			c := rest[i]
+			if c == '"' {
+				quoted = !quoted
+				continue
+			}
+			if !quoted && (c == ';' || c == ':') {
+				break
+			}
+			value.WriteByte(c)
+		}
+		prop.Params[name] = value.String()
+		rest = rest[i:]
+	}
+
+	if !strings.HasPrefix(rest, ":") {
+		return prop, fmt.Errorf("missing value in %q", line)
+	}
+	prop.Value = rest[1:]
+	return prop, nil
+}
+
+// Unescape decodes a TEXT value: \n or \N is a newline, and \\, \; and \,
+// are the literal characters
+func Unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+			switch s[i] {
+			case 'n', 'N':
+				b.WriteByte('\n')
+			default:
+				b.WriteByte(s[i])
+			}
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// Escape encodes a TEXT value so that it round-trips through Unescape
+func Escape(s string) string {
+	return textEscaper.Replace(s)
+}
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// SplitList splits a multi-valued TEXT value (such as CATEGORIES) on its
+// unescaped commas and unescapes each item
+func SplitList(value string) []string {
+	var items []string
+	start := 0
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++
+		case ',':
+			items = append(items, Unescape(value[start:i]))
+			start = i + 1
+		}
+	}
+	return append(items, Unescape(value[start:]))
+}
+
+// JoinList escapes each item and joins them into a multi-valued TEXT value
+func JoinList(items []string) string {
+	escaped := make([]string, len(items))
+	for i, item := range items {
+		escaped[i] = Escape(item)
+	}
+	return strings.Join(escaped, ",")
+}
+
+// ParseTime parses a DATE or DATE-TIME property. UTC values end in "Z",
+// values with a TZID parameter are read in that zone, and floating values
+// and dates are read in local time. An unknown TZID is treated as floating.
+// dateOnly reports whether the value was a DATE with no time of day.
+func ParseTime(p Property) (t time.Time, dateOnly bool, err error) {
+	value := strings.TrimSpace(p.Value)
+
+	loc := tz.Location()
+	if tzid := p.Param("TZID"); tzid != "" {
+		if l, err := time.LoadLocation(strings.TrimPrefix(tzid, "/")); err == nil {
+			loc = l
+		}
+	}
+
+	switch {
+	case strings.EqualFold(p.Param("VALUE"), "DATE") || len(value) == len(DateFormat):
+		t, err = time.ParseInLocation(DateFormat, value, loc)
+		return t, true, err
+	case strings.HasSuffix(value, "Z"):
+		t, err = time.Parse(DateTimeUTCFormat, value)
+		return t, false, err
+	default:
+		t, err = time.ParseInLocation(DateTimeFormat, value, loc)
+		return t, false, err
+	}
+}
+
+// FormatTime formats a DATE or DATE-TIME value with the parameters to write
+// alongside it: a time at midnight is a date without time of day and is written
+// as a DATE (VALUE=DATE) so that it stays on the same calendar day in every
+// timezone; other times are written in UTC.
+func FormatTime(t time.Time) (value string, params []string) {
+	if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 {
+		return t.Format(DateFormat), []string{"VALUE=DATE"}
+	}
+	return t.UTC().Format(DateTimeUTCFormat), nil
+}
+
+// ParseDuration parses a DURATION value such as "-PT15M", "P1D" or "-P1W"
+func ParseDuration(s string) (time.Duration, error) {
+	value := strings.ToUpper(strings.TrimSpace(s))
+	sign := time.Duration(1)
+	switch {
+	case strings.HasPrefix(value, "-"):
+		sign = -1
+		value = value[1:]
+	case strings.HasPrefix(value, "+"):
+		value = value[1:]
+	}
+	if !strings.HasPrefix(value, "P") || len(value) < 3 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	value = value[1:]
+
+	units := map[byte]time.Duration{'W': 7 * 24 * time.Hour, 'D': 24 * time.Hour}
+	timeUnits := map[byte]time.Duration{'H': time.Hour, 'M': time.Minute, 'S': time.Second}
+
+	var total time.Duration
+	num := ""
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c >= '0' && c <= '9':
+			num += string(c)
+		case c == 'T':
+			if num != "" {
+				return 0, fmt.Errorf("invalid duration %q", s)
+			}
+			units = timeUnits
+		default:
+			unit, ok := units[c]
+			if !ok || num == "" {
+				return 0, fmt.Errorf("invalid duration %q", s)
+			}
+			n, err := strconv.Atoi(num)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q", s)
+			}
+			total += time.Duration(n) * unit
+			num = ""
+		}
+	}
+	if num != "" {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return sign * total, nil
+}
+
+// FormatDuration formats a duration as a DURATION value using whole weeks,
+// days, hours, minutes and seconds
+func FormatDuration(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign = "-"
+		d = -d
+	}
+	week := 7 * 24 * time.Hour
+	if d > 0 && d%week == 0 {
+		return fmt.Sprintf("%sP%dW", sign, d/week)
+	}
+
+	var b strings.Builder
+	b.WriteString(sign + "P")
+	if days := d / (24 * time.Hour); days > 0 {
+		_, _ = fmt.Fprintf(&b, "%dD", days)
+		d -= days * 24 * time.Hour
+	}
+	if d > 0 || b.Len() == len(sign)+1 {
+		b.WriteString("T")
+		h, m, s := d/time.Hour, (d%time.Hour)/time.Minute, (d%time.Minute)/time.Second
+		if h > 0 {
+			_, _ = fmt.Fprintf(&b, "%dH", h)
+		}
+		if m > 0 {
+			_, _ = fmt.Fprintf(&b, "%dM", m)
+		}
+		if s > 0 || (h == 0 && m == 0) {
+			_, _ = fmt.Fprintf(&b, "%dS", s)
+		}
+	}
+	return b.String()
+}
+
+// Writer builds iCalendar content with CRLF line endings, folding lines
+// longer than 75 octets
+type Writer struct {
+	b strings.Builder
+}
+
+// Begin opens a component
+func (w *Writer) Begin(name string) {
+	w.Line("BEGIN", name)
+}
+
+// End closes a component
+func (w *Writer) End(name string) {
+	w.Line("END", name)
+}
+
+// Line writes a property. Params are written as given ("RELTYPE=PARENT");
+// the value must already be escaped if it is TEXT.
+func (w *Writer) Line(name, value string, params ...string) {
+	line := name
+	for _, param := range params {
+		line += ";" + param
+	}
+	w.b.WriteString(fold(line + ":" + value))
+	w.b.WriteString("\r\n")
+}
+
+// String returns the content written so far
+func (w *Writer) String() string {
+	return w.b.String()
+}
+
+// Flush writes the content written so far to out and clears it, so large
+// calendars can be streamed component by component
+func (w *Writer) Flush(out io.Writer) error {
+	_, err := io.WriteString(out, w.b.String())
+	w.b.Reset()
+	return err
+}
+
+// fold splits a line into 75-octet chunks joined by CRLF and a space,
+// never splitting a UTF-8 sequence
+func fold(line string) string {
+	if len(line) <= maxLineOctets {
+		return line
+	}
+	var b strings.Builder
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines lose one octet to the leading space
+		limit = maxLineOctets - 1
+	}
+	b.WriteString(line)
+	return b.String()
+}
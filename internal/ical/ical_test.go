@@ -0,0 +1,143 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseFoldedAndEscapedLines(t *testing.T) {
+	data := "BEGIN:VCALENDAR\r\n" +
+		"BEGIN:VTODO\r\n" +
+		"UID:abc-123\r\n" +
+		"SUMMARY:Buy milk\\, eggs\\; and bre\r\n" +
+		" ad\r\n" +
+		"DESCRIPTION:Line one\\nLine two\\\\\r\n" +
+		"CATEGORIES:home,errands\\,misc\r\n" +
+		"X-NOTE;ALTREP=\"cid:a;b:c\":value\r\n" +
+		"BEGIN:VALARM\r\n" +
+		"TRIGGER;RELATED=END:-PT15M\r\n" +
+		"END:VALARM\r\n" +
+		"END:VTODO\r\n" +
+		"END:VCALENDAR\r\n"
+
+	comps, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(comps) != 1 || comps[0].Name != "VCALENDAR" {
+		t.Fatalf("expected one VCALENDAR, got %+v", comps)
+	}
+	todos := comps[0].Find("VTODO")
+	if len(todos) != 1 {
+		t.Fatalf("expected one VTODO, got %d", len(todos))
+	}
+	todo := todos[0]
+
+	if got := todo.Get("SUMMARY").Text(); got != "Buy milk, eggs; and bread" {
+		t.Errorf("SUMMARY = %q", got)
+	}
+	if got := todo.Get("DESCRIPTION").Text(); got != "Line one\nLine two\\" {
+		t.Errorf("DESCRIPTION = %q", got)
+	}
+	if got := SplitList(todo.Get("CATEGORIES").Value); strings.Join(got, "|") != "home|errands,misc" {
+		t.Errorf("CATEGORIES = %q", got)
+	}
+	note := todo.Get("X-NOTE")
+	if note.Param("altrep") != "cid:a;b:c" || note.Value != "value" {
+		t.Errorf("quoted parameter parsed as %+v", note)
+	}
+	alarms := todo.Find("VALARM")
+	if len(alarms) != 1 || alarms[0].Get("TRIGGER").Param("RELATED") != "END" {
+		t.Errorf("VALARM not parsed: %+v", alarms)
+	}
+}
+
+func TestParseUnbalancedComponents(t *testing.T) {
+	if _, err := Parse("BEGIN:VCALENDAR\nBEGIN:VTODO\nEND:VCALENDAR\n"); err == nil {
+		t.Error("expected error for mismatched END")
+	}
+	if _, err := Parse("BEGIN:VCALENDAR\nBEGIN:VTODO\n"); err == nil {
+		t.Error("expected error for missing END")
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	tm, dateOnly, err := ParseTime(Property{Name: "DUE", Params: map[string]string{"TZID": "America/New_York"}, Value: "20260301T090000"})
+	if err != nil || dateOnly {
+		t.Fatalf("TZID value: %v, dateOnly=%v", err, dateOnly)
+	}
+	if want := time.Date(2026, 3, 1, 14, 0, 0, 0, time.UTC); !tm.Equal(want) {
+		t.Errorf("TZID value = %v, want %v", tm.UTC(), want)
+	}
+
+	tm, _, err = ParseTime(Property{Name: "DUE", Value: "20260301T090000Z"})
+	if err != nil || !tm.Equal(time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("UTC value = %v, %v", tm, err)
+	}
+
+	tm, dateOnly, err = ParseTime(Property{Name: "DUE", Params: map[string]string{"VALUE": "DATE"}, Value: "20260301"})
+	if err != nil || !dateOnly || tm.Day() != 1 || tm.Location() != time.Local {
+		t.Errorf("DATE value = %v, dateOnly=%v, %v", tm, dateOnly, err)
+	}
+}
+
+func TestFormatTime(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skip("timezone data not available")
+	}
+	value, params := FormatTime(time.Date(2026, 6, 1, 0, 0, 0, 0, paris))
+	if value != "20260601" || len(params) != 1 || params[0] != "VALUE=DATE" {
+		t.Errorf("date-only value = %q %v, want 20260601 VALUE=DATE", value, params)
+	}
+	value, params = FormatTime(time.Date(2026, 6, 1, 17, 0, 0, 0, paris))
+	if value != "20260601T150000Z" || len(params) != 0 {
+		t.Errorf("timed value = %q %v, want 20260601T150000Z", value, params)
+	}
+}
+
+func TestDurationRoundTrip(t *testing.T) {
+	tests := []struct {
+		text string
+		d    time.Duration
+	}{
+		{"-PT15M", -15 * time.Minute},
+		{"-P1D", -24 * time.Hour},
+		{"-P2W", -14 * 24 * time.Hour},
+		{"P1DT2H", 26 * time.Hour},
+		{"PT0S", 0},
+	}
+	for _, tt := range tests {
+		d, err := ParseDuration(tt.text)
+		if err != nil || d != tt.d {
+			t.Errorf("ParseDuration(%q) = %v, %v; want %v", tt.text, d, err, tt.d)
+		}
+		if got := FormatDuration(tt.d); got != tt.text {
+			t.Errorf("FormatDuration(%v) = %q, want %q", tt.d, got, tt.text)
+		}
+	}
+	if _, err := ParseDuration("15M"); err == nil {
+		t.Error("expected error for duration without P")
+	}
+}
+
+func TestWriterFoldsLongLines(t *testing.T) {
+	var w Writer
+	summary := strings.Repeat("é", 60)
+	w.Line("SUMMARY", Escape(summary+", done"))
+
+	out := w.String()
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		if len(line) > maxLineOctets {
+			t.Errorf("line longer than %d octets: %q", maxLineOctets, line)
+		}
+	}
+	comps, err := Parse("BEGIN:VTODO\r\n" + out + "END:VTODO\r\n")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if got := comps[0].Get("SUMMARY").Text(); got != summary+", done" {
+		t.Errorf("round trip = %q", got)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"todoat/backend"
+	"todoat/internal/tz"
 )
 
 // OrganizeTasksHierarchically separates root tasks from children.
@@ -65,9 +66,9 @@ func ParseStatusChar(char string) backend.TaskStatus {
 	}
 }
 
-// FormatStatusChar converts TaskStatus to markdown checkbox character.
+// FormatStatusChar converts TaskStatus to markdown checkbox character. Custom statuses use their base.
 func FormatStatusChar(status backend.TaskStatus) string {
-	switch status {
+	switch status.Base() {
 	case backend.StatusCompleted:
 		return "x"
 	case backend.StatusInProgress:
@@ -94,7 +95,7 @@ func ParseTaskText(text string) (summary string, priority int, dueDate *time.Tim
 	// Extract due date: @2024-01-15
 	dueDatePattern := regexp.MustCompile(`@(\d{4}-\d{2}-\d{2})`)
 	if matches := dueDatePattern.FindStringSubmatch(text); len(matches) == 2 {
-		if t, err := time.Parse("2006-01-02", matches[1]); err == nil {
+		if t, err := time.ParseInLocation("2006-01-02", matches[1], tz.Location()); err == nil {
 			dueDate = &t
 		}
 		summary = strings.TrimSpace(dueDatePattern.ReplaceAllString(summary, ""))
@@ -116,6 +117,25 @@ func ParseTaskText(text string) (summary string, priority int, dueDate *time.Tim
 	return summary, priority, dueDate, categories
 }
 
+// CheckTaskText reports priority and due date annotations of task text that
+// ParseTaskText would misread or leave in the summary, such as "!10" or
+// "@03/04/2026". It is used in strict mode instead of guessing.
+func CheckTaskText(text string) error {
+	for _, field := range strings.Fields(text) {
+		if value, ok := strings.CutPrefix(field, "!"); ok && value != "" && value[0] >= '0' && value[0] <= '9' {
+			if len(value) != 1 {
+				return fmt.Errorf("invalid priority %s (use !0 to !9)", field)
+			}
+		}
+		if value, ok := strings.CutPrefix(field, "@"); ok && value != "" && value[0] >= '0' && value[0] <= '9' {
+			if _, err := time.ParseInLocation("2006-01-02", value, tz.Location()); err != nil {
+				return fmt.Errorf("invalid due date %s (use @YYYY-MM-DD)", field)
+			}
+		}
+	}
+	return nil
+}
+
 // FormatTaskText formats a task back to markdown text.
 func FormatTaskText(task *backend.Task) string {
 	parts := []string{task.Summary}
@@ -135,6 +135,19 @@ func TestParseTaskText(t *testing.T) {
 	}
 }
 
+func TestCheckTaskText(t *testing.T) {
+	for _, text := range []string{"Buy milk !2 @2026-03-04 #shop", "Say hi! to @bob", "Plain task"} {
+		if err := CheckTaskText(text); err != nil {
+			t.Errorf("CheckTaskText(%q) = %v, want nil", text, err)
+		}
+	}
+	for _, text := range []string{"Buy milk !10", "Buy milk @03/04/2026", "Buy milk @2026-13-01"} {
+		if err := CheckTaskText(text); err == nil {
+			t.Errorf("CheckTaskText(%q) = nil, want error", text)
+		}
+	}
+}
+
 func TestFormatTaskText(t *testing.T) {
 	dueDate := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
 
@@ -0,0 +1,46 @@
+package notification
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultDigestTime is the time of day the daily digest is sent when none is configured
+const DefaultDigestTime = "08:00"
+
+// DigestConfig configures the daily digest of tasks due today and overdue
+type DigestConfig struct {
+	Enabled  bool     `yaml:"enabled" json:"enabled"`
+	Time     string   `yaml:"time" json:"time"`         // Local time of day to send the digest, "HH:MM" (default: "08:00")
+	Channels []string `yaml:"channels" json:"channels"` // Channels to send the digest to (default: all enabled channels)
+}
+
+// ParseDigestTime parses a digest time of day in 24-hour "HH:MM" format
+func ParseDigestTime(s string) (hour, minute int, err error) {
+	h, m, ok := strings.Cut(strings.TrimSpace(s), ":")
+	if ok {
+		hour, err = strconv.Atoi(h)
+		if err == nil {
+			minute, err = strconv.Atoi(m)
+		}
+	}
+	if !ok || err != nil || len(m) != 2 || hour < 0 || hour > 23 || minute < 0 || minute > 59 {
+		return 0, 0, fmt.Errorf("invalid digest time %q (use HH:MM, e.g. 08:00)", s)
+	}
+	return hour, minute, nil
+}
+
+// DueAt returns when the digest of the day of now is due
+func (c DigestConfig) DueAt(now time.Time) (time.Time, error) {
+	at := c.Time
+	if at == "" {
+		at = DefaultDigestTime
+	}
+	hour, minute, err := ParseDigestTime(at)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, now.Location()), nil
+}
@@ -0,0 +1,111 @@
+package notification
+
+import (
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultSMTPPort = 587
+
+// SMTPSender sends a fully formatted message; it matches the signature of smtp.SendMail
+type SMTPSender func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+
+// emailNotificationChannel sends notifications by SMTP email
+type emailNotificationChannel struct {
+	config *EmailNotificationConfig
+	sender SMTPSender
+}
+
+// NewEmailNotificationChannel creates a new email notification channel
+func NewEmailNotificationChannel(cfg *EmailNotificationConfig, opts ...Option) NotificationChannel {
+	ch := &emailNotificationChannel{
+		config: cfg,
+	}
+
+	for _, opt := range opts {
+		opt(ch)
+	}
+
+	if ch.sender == nil {
+		ch.sender = smtp.SendMail
+	}
+
+	return ch
+}
+
+// Send emails the notification to all configured recipients
+func (c *emailNotificationChannel) Send(n Notification) error {
+	return sendWithRetry(c.config.RetryConfig, func() error { return c.deliver(n) })
+}
+
+// deliver makes a single attempt to email the notification
+func (c *emailNotificationChannel) deliver(n Notification) error {
+	if c.config.Host == "" {
+		return fmt.Errorf("email notification: host is not configured")
+	}
+	if len(c.config.To) == 0 {
+		return fmt.Errorf("email notification: no recipients configured")
+	}
+
+	port := c.config.Port
+	if port == 0 {
+		port = defaultSMTPPort
+	}
+	addr := net.JoinHostPort(c.config.Host, strconv.Itoa(port))
+
+	from := c.config.From
+	if from == "" {
+		from = c.config.Username
+	}
+
+	var auth smtp.Auth
+	if c.config.Username != "" {
+		auth = smtp.PlainAuth("", c.config.Username, c.config.Password, c.config.Host)
+	}
+
+	msg := formatEmail(from, c.config.To, n)
+	if err := c.sender(addr, auth, from, c.config.To, msg); err != nil {
+		return fmt.Errorf("email notification failed: %w", err)
+	}
+	return nil
+}
+
+// retryConfig returns the channel's retry settings
+func (c *emailNotificationChannel) retryConfig() RetryConfig {
+	return c.config.RetryConfig
+}
+
+// formatEmail builds a plain-text RFC 5322 message for a notification; the subject is
+// RFC 2047 encoded so that non-ASCII task summaries survive transport
+func formatEmail(from string, to []string, n Notification) []byte {
+	subject := n.Title
+	if subject == "" {
+		subject = "todoat notification"
+	}
+	timestamp := n.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", timestamp.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(n.Message)
+	b.WriteString("\r\n")
+	return []byte(b.String())
+}
+
+// Close is a no-op for email notifications
+func (c *emailNotificationChannel) Close() error {
+	return nil
+}
@@ -33,8 +33,10 @@ func (c *logNotificationChannel) Send(n Notification) error {
 	}
 
 	// Format: 2026-01-16T10:30:00Z [SYNC_COMPLETE] Message
+	// Multi-line messages such as digests are joined so each entry stays on one line
 	typeStr := strings.ToUpper(string(n.Type))
-	line := fmt.Sprintf("%s [%s] %s\n", n.Timestamp.UTC().Format("2006-01-02T15:04:05Z"), typeStr, n.Message)
+	message := strings.ReplaceAll(n.Message, "\n", " ")
+	line := fmt.Sprintf("%s [%s] %s\n", n.Timestamp.UTC().Format("2006-01-02T15:04:05Z"), typeStr, message)
 
 	_, err := c.file.WriteString(line)
 	if err != nil {
@@ -1,12 +1,20 @@
 package notification
 
-import "sync"
+import (
+	"fmt"
+	"sync"
+
+	"todoat/internal/utils"
+)
 
 // manager implements NotificationManager
 type manager struct {
 	channels        []NotificationChannel
+	channelNames    []string
+	logChannel      NotificationChannel
 	enabled         bool
 	commandExecutor CommandExecutor
+	smtpSender      SMTPSender
 	sendCallback    func(Notification)
 	wg              sync.WaitGroup
 }
@@ -36,32 +44,121 @@ func NewManager(cfg *Config, opts ...Option) (NotificationManager, error) {
 			osOpts = append(osOpts, WithSendCallback(m.sendCallback))
 		}
 		osChannel := NewOSNotificationChannel(&cfg.OSNotification, osOpts...)
-		m.channels = append(m.channels, osChannel)
+		m.addChannel(ChannelOS, osChannel)
 	}
 
 	if cfg.LogNotification.Enabled {
-		logChannel := NewLogNotificationChannel(&cfg.LogNotification)
-		m.channels = append(m.channels, logChannel)
+		m.logChannel = NewLogNotificationChannel(&cfg.LogNotification)
+		m.addChannel(ChannelLog, m.logChannel)
+	}
+
+	if cfg.Email.Enabled {
+		var emailOpts []Option
+		if m.smtpSender != nil {
+			emailOpts = append(emailOpts, WithSMTPSender(m.smtpSender))
+		}
+		m.addChannel(ChannelEmail, NewEmailNotificationChannel(&cfg.Email, emailOpts...))
+	}
+
+	if cfg.Webhook.Enabled {
+		m.addChannel(ChannelWebhook, NewWebhookNotificationChannel(&cfg.Webhook))
+	}
+
+	if cfg.Push.Enabled {
+		m.addChannel(ChannelPush, NewPushNotificationChannel(&cfg.Push))
 	}
 
 	return m, nil
 }
 
-// Send dispatches notification to all enabled channels
+// addChannel registers a channel under its selection name
+func (m *manager) addChannel(name string, ch NotificationChannel) {
+	m.channels = append(m.channels, ch)
+	m.channelNames = append(m.channelNames, name)
+}
+
+// selected reports whether a notification should be delivered to the named channel
+func selected(n Notification, name string) bool {
+	if len(n.Channels) == 0 {
+		return true
+	}
+	for _, ch := range n.Channels {
+		if ch == name {
+			return true
+		}
+	}
+	return false
+}
+
+// retryingChannel is a channel that retries failed deliveries
+type retryingChannel interface {
+	deliver(n Notification) error
+	retryConfig() RetryConfig
+}
+
+// Send dispatches notification to all enabled channels.
+// Each channel gets one attempt here; when a channel with retries configured fails, the
+// retries run in the background so that callers such as the daemon tick are not held up
+// by retry delays. Their outcome is recorded in the notification log, and Close waits for them.
 func (m *manager) Send(n Notification) error {
 	if !m.enabled {
 		return nil
 	}
 
 	var lastErr error
-	for _, ch := range m.channels {
-		if err := ch.Send(n); err != nil {
-			lastErr = err
+	for i, ch := range m.channels {
+		name := m.channelNames[i]
+		if !selected(n, name) {
+			continue
+		}
+		rc, ok := ch.(retryingChannel)
+		if !ok {
+			if err := ch.Send(n); err != nil {
+				lastErr = err
+				m.logDeliveryFailure(name, n, err)
+			}
+			continue
 		}
+		err := rc.deliver(n)
+		if err == nil {
+			continue
+		}
+		if rc.retryConfig().Retries > 0 {
+			m.retryAsync(name, rc, n, err)
+			continue
+		}
+		lastErr = err
+		m.logDeliveryFailure(name, n, err)
 	}
 	return lastErr
 }
 
+// retryAsync retries a failed delivery in the background
+func (m *manager) retryAsync(name string, ch retryingChannel, n Notification, err error) {
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		if err := retryFailed(ch.retryConfig(), err, func() error { return ch.deliver(n) }); err != nil {
+			m.logDeliveryFailure(name, n, err)
+		}
+	}()
+}
+
+// logDeliveryFailure records a failed delivery in the notification log, if enabled
+func (m *manager) logDeliveryFailure(name string, n Notification, err error) {
+	utils.Component("reminder").Debug("Notification delivery failed", "channel", name, "type", string(n.Type), "error", err)
+	if m.logChannel == nil || name == ChannelLog {
+		return
+	}
+	_ = m.logChannel.Send(Notification{
+		Type:      NotifyDeliveryFailed,
+		Title:     "Notification Delivery Failed",
+		Message:   fmt.Sprintf("%s channel: %v (%s)", name, err, n.Message),
+		Timestamp: n.Timestamp,
+		Metadata:  n.Metadata,
+	})
+}
+
 // SendAsync dispatches notification without blocking
 func (m *manager) SendAsync(n Notification) {
 	m.wg.Add(1)
@@ -13,9 +13,36 @@ const (
 	NotifySyncError    NotificationType = "sync_error"
 	NotifyConflict     NotificationType = "conflict"
 	NotifyReminder     NotificationType = "reminder"
+	NotifyFocusEnd     NotificationType = "focus_end"
+	NotifyDigest       NotificationType = "digest"
 	NotifyTest         NotificationType = "test"
+
+	// NotifyDeliveryFailed is written to the log channel when another channel gives up
+	NotifyDeliveryFailed NotificationType = "delivery_failed"
+)
+
+// Channel names used to select delivery channels for a notification
+const (
+	ChannelOS      = "os"
+	ChannelLog     = "log"
+	ChannelEmail   = "email"
+	ChannelWebhook = "webhook"
+	ChannelPush    = "push"
 )
 
+// ChannelNames lists all valid channel names
+var ChannelNames = []string{ChannelOS, ChannelLog, ChannelEmail, ChannelWebhook, ChannelPush}
+
+// IsValidChannel reports whether name is a known channel name
+func IsValidChannel(name string) bool {
+	for _, valid := range ChannelNames {
+		if name == valid {
+			return true
+		}
+	}
+	return false
+}
+
 // Notification represents a notification to be sent
 type Notification struct {
 	Type      NotificationType
@@ -23,6 +50,7 @@ type Notification struct {
 	Message   string
 	Timestamp time.Time
 	Metadata  map[string]string
+	Channels  []string // Restrict delivery to these channels (empty = all enabled channels)
 }
 
 // NotificationManager is the interface for managing notifications
@@ -44,6 +72,9 @@ type Config struct {
 	Enabled         bool
 	OSNotification  OSNotificationConfig
 	LogNotification LogNotificationConfig
+	Email           EmailNotificationConfig
+	Webhook         WebhookNotificationConfig
+	Push            PushNotificationConfig
 }
 
 // OSNotificationConfig holds OS notification configuration
@@ -62,6 +93,43 @@ type LogNotificationConfig struct {
 	RetentionDays int
 }
 
+// RetryConfig controls how often a remote channel retries a failed delivery
+type RetryConfig struct {
+	Retries    int    `yaml:"retries" json:"retries"`         // Extra attempts after the first failure (default: 0)
+	RetryDelay string `yaml:"retry_delay" json:"retry_delay"` // Delay between attempts (default: "2s")
+}
+
+// EmailNotificationConfig holds SMTP email notification configuration
+type EmailNotificationConfig struct {
+	Enabled     bool     `yaml:"enabled" json:"enabled"`
+	Host        string   `yaml:"host" json:"host"`
+	Port        int      `yaml:"port" json:"port"` // Default: 587
+	Username    string   `yaml:"username" json:"username"`
+	Password    string   `yaml:"password" json:"password"`
+	From        string   `yaml:"from" json:"from"`
+	To          []string `yaml:"to" json:"to"`
+	RetryConfig `yaml:",inline"`
+}
+
+// WebhookNotificationConfig holds generic webhook notification configuration
+type WebhookNotificationConfig struct {
+	Enabled     bool              `yaml:"enabled" json:"enabled"`
+	URL         string            `yaml:"url" json:"url"`
+	Headers     map[string]string `yaml:"headers" json:"headers"`
+	RetryConfig `yaml:",inline"`
+}
+
+// PushNotificationConfig holds ntfy.sh or Gotify push notification configuration
+type PushNotificationConfig struct {
+	Enabled     bool   `yaml:"enabled" json:"enabled"`
+	Provider    string `yaml:"provider" json:"provider"` // "ntfy" (default) or "gotify"
+	URL         string `yaml:"url" json:"url"`           // Server URL (default for ntfy: https://ntfy.sh)
+	Topic       string `yaml:"topic" json:"topic"`       // ntfy topic
+	Token       string `yaml:"token" json:"token"`       // ntfy access token or Gotify application token
+	Priority    int    `yaml:"priority" json:"priority"`
+	RetryConfig `yaml:",inline"`
+}
+
 // CommandExecutor is the interface for executing system commands
 type CommandExecutor interface {
 	Execute(cmd string, args ...string) error
@@ -104,6 +172,18 @@ func WithPlatform(platform string) Option {
 	}
 }
 
+// WithSMTPSender sets a custom SMTP send function for email notifications
+func WithSMTPSender(sender SMTPSender) Option {
+	return func(c interface{}) {
+		if ch, ok := c.(*emailNotificationChannel); ok {
+			ch.sender = sender
+		}
+		if mgr, ok := c.(*manager); ok {
+			mgr.smtpSender = sender
+		}
+	}
+}
+
 // WithSendCallback sets a callback to be called when a notification is sent
 func WithSendCallback(callback func(Notification)) Option {
 	return func(c interface{}) {
@@ -1,10 +1,17 @@
 package notification_test
 
 import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -741,3 +748,344 @@ func TestNotificationTypeFiltering(t *testing.T) {
 		t.Errorf("expected sync_error to be sent, got %s", sentNotifications[0].Type)
 	}
 }
+
+// =============================================================================
+// Unit Tests - Remote Channels (email, webhook, push)
+// =============================================================================
+
+// TestWebhookNotificationRetries tests that the webhook channel retries failed deliveries
+func TestWebhookNotificationRetries(t *testing.T) {
+	var attempts int32
+	var payload map[string]interface{}
+	var authHeader string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		authHeader = r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+	}))
+	defer server.Close()
+
+	channel := notification.NewWebhookNotificationChannel(&notification.WebhookNotificationConfig{
+		Enabled:     true,
+		URL:         server.URL,
+		Headers:     map[string]string{"Authorization": "Bearer secret"},
+		RetryConfig: notification.RetryConfig{Retries: 2, RetryDelay: "1ms"},
+	})
+
+	err := channel.Send(notification.Notification{
+		Type:      notification.NotifyReminder,
+		Title:     "Task Reminder",
+		Message:   "Pay rent - Due: 2026-02-01",
+		Timestamp: time.Now(),
+		Metadata:  map[string]string{"task_id": "abc"},
+	})
+	if err != nil {
+		t.Fatalf("expected delivery after retries, got %v", err)
+	}
+	if atomic.LoadInt32(&attempts) != 3 {
+		t.Errorf("expected 3 attempts, got %d", attempts)
+	}
+	if payload["type"] != "reminder" || payload["message"] != "Pay rent - Due: 2026-02-01" {
+		t.Errorf("unexpected payload: %v", payload)
+	}
+	if authHeader != "Bearer secret" {
+		t.Errorf("expected custom header to be sent, got %q", authHeader)
+	}
+}
+
+// TestWebhookNotificationGivesUp tests that the webhook channel returns an error once retries are exhausted
+func TestWebhookNotificationGivesUp(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	channel := notification.NewWebhookNotificationChannel(&notification.WebhookNotificationConfig{
+		Enabled:     true,
+		URL:         server.URL,
+		RetryConfig: notification.RetryConfig{Retries: 1, RetryDelay: "1ms"},
+	})
+
+	err := channel.Send(notification.Notification{Type: notification.NotifyReminder, Message: "Test", Timestamp: time.Now()})
+	if err == nil {
+		t.Fatal("expected error when webhook keeps failing")
+	}
+	if !strings.Contains(err.Error(), "after 2 attempts") {
+		t.Errorf("expected attempt count in error, got %v", err)
+	}
+	if atomic.LoadInt32(&attempts) != 2 {
+		t.Errorf("expected 2 attempts, got %d", attempts)
+	}
+}
+
+// TestPushNotificationNtfy tests that ntfy notifications are published to the topic with headers
+func TestPushNotificationNtfy(t *testing.T) {
+	var path, title, priority, auth, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		title = r.Header.Get("Title")
+		priority = r.Header.Get("Priority")
+		auth = r.Header.Get("Authorization")
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+	}))
+	defer server.Close()
+
+	channel := notification.NewPushNotificationChannel(&notification.PushNotificationConfig{
+		Enabled:  true,
+		Provider: "ntfy",
+		URL:      server.URL,
+		Topic:    "my-tasks",
+		Token:    "tk_123",
+		Priority: 4,
+	})
+
+	err := channel.Send(notification.Notification{
+		Type:      notification.NotifyReminder,
+		Title:     "Task Reminder",
+		Message:   "Call dentist",
+		Timestamp: time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if path != "/my-tasks" {
+		t.Errorf("expected topic path /my-tasks, got %q", path)
+	}
+	if title != "Task Reminder" || priority != "4" || auth != "Bearer tk_123" {
+		t.Errorf("unexpected headers: title=%q priority=%q auth=%q", title, priority, auth)
+	}
+	if body != "Call dentist" {
+		t.Errorf("expected message body, got %q", body)
+	}
+}
+
+// TestPushNotificationGotify tests that Gotify notifications are posted to /message with the app token
+func TestPushNotificationGotify(t *testing.T) {
+	var path, key string
+	var payload map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		key = r.Header.Get("X-Gotify-Key")
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+	}))
+	defer server.Close()
+
+	channel := notification.NewPushNotificationChannel(&notification.PushNotificationConfig{
+		Enabled:  true,
+		Provider: "gotify",
+		URL:      server.URL,
+		Token:    "app-token",
+		Priority: 5,
+	})
+
+	err := channel.Send(notification.Notification{
+		Type:      notification.NotifyReminder,
+		Title:     "Task Reminder",
+		Message:   "Call dentist",
+		Timestamp: time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if path != "/message" || key != "app-token" {
+		t.Errorf("unexpected request: path=%q key=%q", path, key)
+	}
+	if payload["title"] != "Task Reminder" || payload["message"] != "Call dentist" || payload["priority"] != float64(5) {
+		t.Errorf("unexpected payload: %v", payload)
+	}
+}
+
+// TestEmailNotification tests that email notifications are sent via SMTP with retry
+func TestEmailNotification(t *testing.T) {
+	var attempts int
+	var sentAddr, sentFrom, sentMsg string
+	var sentTo []string
+	sender := func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		attempts++
+		if attempts == 1 {
+			return errors.New("connection refused")
+		}
+		sentAddr, sentFrom, sentTo, sentMsg = addr, from, to, string(msg)
+		return nil
+	}
+
+	channel := notification.NewEmailNotificationChannel(&notification.EmailNotificationConfig{
+		Enabled:     true,
+		Host:        "smtp.example.com",
+		Username:    "me@example.com",
+		Password:    "secret",
+		To:          []string{"me@example.com"},
+		RetryConfig: notification.RetryConfig{Retries: 1, RetryDelay: "1ms"},
+	}, notification.WithSMTPSender(sender))
+
+	err := channel.Send(notification.Notification{
+		Type:      notification.NotifyReminder,
+		Title:     "Task Reminder",
+		Message:   "Renew passport - Due: 2026-03-01",
+		Timestamp: time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("expected 2 attempts, got %d", attempts)
+	}
+	if sentAddr != "smtp.example.com:587" {
+		t.Errorf("expected default port 587, got %q", sentAddr)
+	}
+	if sentFrom != "me@example.com" || len(sentTo) != 1 {
+		t.Errorf("unexpected envelope: from=%q to=%v", sentFrom, sentTo)
+	}
+	if !strings.Contains(sentMsg, "Subject: Task Reminder") || !strings.Contains(sentMsg, "Renew passport") {
+		t.Errorf("unexpected message: %q", sentMsg)
+	}
+}
+
+// TestManagerChannelSelection tests that a notification with Channels is only delivered to those channels
+func TestManagerChannelSelection(t *testing.T) {
+	var webhookHits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&webhookHits, 1)
+	}))
+	defer server.Close()
+
+	var emails int
+	sender := func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		emails++
+		return nil
+	}
+
+	mgr, err := notification.NewManager(&notification.Config{
+		Enabled: true,
+		Email:   notification.EmailNotificationConfig{Enabled: true, Host: "smtp.example.com", From: "todoat@example.com", To: []string{"me@example.com"}},
+		Webhook: notification.WebhookNotificationConfig{Enabled: true, URL: server.URL},
+	}, notification.WithSMTPSender(sender))
+	if err != nil {
+		t.Fatalf("failed to create manager: %v", err)
+	}
+	defer func() { _ = mgr.Close() }()
+
+	if mgr.ChannelCount() != 2 {
+		t.Fatalf("expected 2 channels, got %d", mgr.ChannelCount())
+	}
+
+	// Restricted to webhook
+	if err := mgr.Send(notification.Notification{Type: notification.NotifyReminder, Message: "one", Timestamp: time.Now(), Channels: []string{notification.ChannelWebhook}}); err != nil {
+		t.Fatalf("send failed: %v", err)
+	}
+	// No restriction: all channels
+	if err := mgr.Send(notification.Notification{Type: notification.NotifyReminder, Message: "two", Timestamp: time.Now()}); err != nil {
+		t.Fatalf("send failed: %v", err)
+	}
+
+	if atomic.LoadInt32(&webhookHits) != 2 {
+		t.Errorf("expected 2 webhook deliveries, got %d", webhookHits)
+	}
+	if emails != 1 {
+		t.Errorf("expected 1 email, got %d", emails)
+	}
+}
+
+// TestManagerLogsDeliveryFailure tests that a failing remote channel is recorded in the notification log
+func TestManagerLogsDeliveryFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	logPath := filepath.Join(t.TempDir(), "notifications.log")
+	mgr, err := notification.NewManager(&notification.Config{
+		Enabled:         true,
+		LogNotification: notification.LogNotificationConfig{Enabled: true, Path: logPath, MaxSizeMB: 10},
+		Webhook:         notification.WebhookNotificationConfig{Enabled: true, URL: server.URL},
+	})
+	if err != nil {
+		t.Fatalf("failed to create manager: %v", err)
+	}
+
+	err = mgr.Send(notification.Notification{Type: notification.NotifyReminder, Message: "Water plants", Timestamp: time.Now()})
+	if err == nil {
+		t.Error("expected error from failing webhook")
+	}
+	_ = mgr.Close()
+
+	entries, err := notification.ReadLog(logPath)
+	if err != nil {
+		t.Fatalf("failed to read log: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected reminder and failure entries, got %v", entries)
+	}
+	if !strings.Contains(entries[1], "[DELIVERY_FAILED] webhook channel") || !strings.Contains(entries[1], "Water plants") {
+		t.Errorf("unexpected failure entry: %q", entries[1])
+	}
+}
+
+// TestEmailSubjectEncoding tests that non-ASCII subjects are RFC 2047 encoded
+func TestEmailSubjectEncoding(t *testing.T) {
+	var sentMsg string
+	sender := func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		sentMsg = string(msg)
+		return nil
+	}
+
+	channel := notification.NewEmailNotificationChannel(&notification.EmailNotificationConfig{
+		Enabled: true,
+		Host:    "smtp.example.com",
+		From:    "todoat@example.com",
+		To:      []string{"me@example.com"},
+	}, notification.WithSMTPSender(sender))
+
+	if err := channel.Send(notification.Notification{Type: notification.NotifyReminder, Title: "Réunion café", Message: "Réunion café", Timestamp: time.Now()}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !strings.Contains(sentMsg, "Subject: =?utf-8?q?R=C3=A9union_caf=C3=A9?=\r\n") {
+		t.Errorf("expected encoded subject, got %q", sentMsg)
+	}
+}
+
+// TestManagerRetriesInBackground tests that Send returns after the first failed attempt
+// and that the retries run in the background until Close
+func TestManagerRetriesInBackground(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	mgr, err := notification.NewManager(&notification.Config{
+		Enabled: true,
+		Webhook: notification.WebhookNotificationConfig{
+			Enabled:     true,
+			URL:         server.URL,
+			RetryConfig: notification.RetryConfig{Retries: 2, RetryDelay: "200ms"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create manager: %v", err)
+	}
+
+	start := time.Now()
+	if err := mgr.Send(notification.Notification{Type: notification.NotifyReminder, Message: "Water plants", Timestamp: time.Now()}); err != nil {
+		t.Fatalf("expected retry to be scheduled, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed >= 200*time.Millisecond {
+		t.Errorf("Send waited for retries (%v)", elapsed)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 1 {
+		t.Errorf("expected 1 attempt before Send returned, got %d", got)
+	}
+
+	_ = mgr.Close()
+	if got := atomic.LoadInt32(&attempts); got != 3 {
+		t.Errorf("expected 3 attempts after Close, got %d", got)
+	}
+}
@@ -0,0 +1,233 @@
+package notification
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultRetryDelay  = 2 * time.Second
+	defaultHTTPTimeout = 10 * time.Second
+	defaultNtfyURL     = "https://ntfy.sh"
+)
+
+// sendWithRetry calls send until it succeeds or the configured retries are exhausted
+func sendWithRetry(cfg RetryConfig, send func() error) error {
+	err := send()
+	if err == nil || cfg.Retries == 0 {
+		return err
+	}
+	return retryFailed(cfg, err, send)
+}
+
+// retryFailed makes the configured retries of a delivery whose first attempt failed with err
+func retryFailed(cfg RetryConfig, err error, send func() error) error {
+	delay := defaultRetryDelay
+	if cfg.RetryDelay != "" {
+		if d, parseErr := time.ParseDuration(cfg.RetryDelay); parseErr == nil {
+			delay = d
+		}
+	}
+
+	for attempt := 0; attempt < cfg.Retries; attempt++ {
+		time.Sleep(delay)
+		if err = send(); err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w (after %d attempts)", err, cfg.Retries+1)
+}
+
+// doHTTPRequest sends a request and treats any non-2xx status as an error
+func doHTTPRequest(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d from %s", resp.StatusCode, req.URL.Host)
+	}
+	return nil
+}
+
+// webhookPayload is the JSON body posted to generic webhooks
+type webhookPayload struct {
+	Type      string            `json:"type"`
+	Title     string            `json:"title"`
+	Message   string            `json:"message"`
+	Timestamp string            `json:"timestamp"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+}
+
+// webhookNotificationChannel posts notifications as JSON to a URL
+type webhookNotificationChannel struct {
+	config *WebhookNotificationConfig
+	client *http.Client
+}
+
+// NewWebhookNotificationChannel creates a new webhook notification channel
+func NewWebhookNotificationChannel(cfg *WebhookNotificationConfig) NotificationChannel {
+	return &webhookNotificationChannel{
+		config: cfg,
+		client: &http.Client{Timeout: defaultHTTPTimeout},
+	}
+}
+
+// Send posts the notification to the webhook URL
+func (c *webhookNotificationChannel) Send(n Notification) error {
+	return sendWithRetry(c.config.RetryConfig, func() error { return c.deliver(n) })
+}
+
+// deliver makes a single attempt to post the notification
+func (c *webhookNotificationChannel) deliver(n Notification) error {
+	if c.config.URL == "" {
+		return fmt.Errorf("webhook notification: url is not configured")
+	}
+
+	body, err := json.Marshal(webhookPayload{
+		Type:      string(n.Type),
+		Title:     n.Title,
+		Message:   n.Message,
+		Timestamp: n.Timestamp.UTC().Format(time.RFC3339),
+		Metadata:  n.Metadata,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range c.config.Headers {
+		req.Header.Set(key, value)
+	}
+	if err := doHTTPRequest(c.client, req); err != nil {
+		return fmt.Errorf("webhook notification failed: %w", err)
+	}
+	return nil
+}
+
+// retryConfig returns the channel's retry settings
+func (c *webhookNotificationChannel) retryConfig() RetryConfig {
+	return c.config.RetryConfig
+}
+
+// Close is a no-op for webhook notifications
+func (c *webhookNotificationChannel) Close() error {
+	return nil
+}
+
+// pushNotificationChannel sends notifications to an ntfy or Gotify server
+type pushNotificationChannel struct {
+	config *PushNotificationConfig
+	client *http.Client
+}
+
+// NewPushNotificationChannel creates a new ntfy/Gotify push notification channel
+func NewPushNotificationChannel(cfg *PushNotificationConfig) NotificationChannel {
+	return &pushNotificationChannel{
+		config: cfg,
+		client: &http.Client{Timeout: defaultHTTPTimeout},
+	}
+}
+
+// Send pushes the notification to the configured provider
+func (c *pushNotificationChannel) Send(n Notification) error {
+	return sendWithRetry(c.config.RetryConfig, func() error { return c.deliver(n) })
+}
+
+// deliver makes a single attempt to push the notification
+func (c *pushNotificationChannel) deliver(n Notification) error {
+	var req *http.Request
+	var err error
+	switch c.config.Provider {
+	case "", "ntfy":
+		req, err = c.ntfyRequest(n)
+	case "gotify":
+		req, err = c.gotifyRequest(n)
+	default:
+		return fmt.Errorf("unsupported push provider: %s", c.config.Provider)
+	}
+	if err == nil {
+		err = doHTTPRequest(c.client, req)
+	}
+	if err != nil {
+		return fmt.Errorf("push notification failed: %w", err)
+	}
+	return nil
+}
+
+// retryConfig returns the channel's retry settings
+func (c *pushNotificationChannel) retryConfig() RetryConfig {
+	return c.config.RetryConfig
+}
+
+// ntfyRequest builds an ntfy publish request (POST <url>/<topic> with the message as body)
+func (c *pushNotificationChannel) ntfyRequest(n Notification) (*http.Request, error) {
+	if c.config.Topic == "" {
+		return nil, fmt.Errorf("ntfy topic is not configured")
+	}
+	baseURL := c.config.URL
+	if baseURL == "" {
+		baseURL = defaultNtfyURL
+	}
+
+	req, err := http.NewRequest(http.MethodPost, strings.TrimRight(baseURL, "/")+"/"+c.config.Topic, strings.NewReader(n.Message))
+	if err != nil {
+		return nil, err
+	}
+	if n.Title != "" {
+		req.Header.Set("Title", n.Title)
+	}
+	req.Header.Set("Tags", string(n.Type))
+	if c.config.Priority > 0 {
+		req.Header.Set("Priority", strconv.Itoa(c.config.Priority))
+	}
+	if c.config.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.config.Token)
+	}
+	return req, nil
+}
+
+// gotifyRequest builds a Gotify message request (POST <url>/message with a JSON body)
+func (c *pushNotificationChannel) gotifyRequest(n Notification) (*http.Request, error) {
+	if c.config.URL == "" {
+		return nil, fmt.Errorf("gotify url is not configured")
+	}
+	if c.config.Token == "" {
+		return nil, fmt.Errorf("gotify token is not configured")
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"title":    n.Title,
+		"message":  n.Message,
+		"priority": c.config.Priority,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, strings.TrimRight(c.config.URL, "/")+"/message", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Gotify-Key", c.config.Token)
+	return req, nil
+}
+
+// Close is a no-op for push notifications
+func (c *pushNotificationChannel) Close() error {
+	return nil
+}
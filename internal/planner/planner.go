@@ -0,0 +1,137 @@
+// Package planner proposes a set of tasks for the day that fits a time budget,
+// based on the effort estimates stored in task metadata.
+package planner
+
+import (
+	"sort"
+	"time"
+
+	"todoat/backend"
+	"todoat/internal/utils"
+)
+
+// EstimateKey is the metadata key that holds a task's effort estimate
+const EstimateKey = "estimate"
+
+// Estimate returns the effort estimate of a task and whether it has a valid one
+func Estimate(t *backend.Task) (time.Duration, bool) {
+	value, ok := t.Metadata[EstimateKey]
+	if !ok {
+		return 0, false
+	}
+	d, err := utils.ParseDuration(value)
+	if err != nil {
+		return 0, false
+	}
+	return d, true
+}
+
+// SpentKey is the metadata key that holds the time logged on a task, e.g. by
+// focus sessions
+const SpentKey = "spent"
+
+// Spent returns the time logged on a task, zero when none is logged
+func Spent(t *backend.Task) time.Duration {
+	d, err := utils.ParseDuration(t.Metadata[SpentKey])
+	if err != nil {
+		return 0
+	}
+	return d
+}
+
+// Item is a task considered by the planner together with its estimate
+type Item struct {
+	Task     backend.Task
+	Estimate time.Duration
+}
+
+// Plan is the outcome of Build
+type Plan struct {
+	Capacity    time.Duration
+	Planned     time.Duration  // sum of the selected estimates
+	Selected    []Item         // tasks proposed for today, in working order
+	Deferred    []Item         // estimated tasks that did not fit the budget
+	Unestimated []backend.Task // open tasks without an estimate, in ranking order
+}
+
+// Build ranks the open tasks that can be worked on today and selects them in order
+// while they fit the capacity. A task that does not fit is deferred and smaller
+// tasks after it may still be selected.
+//
+// Tasks are ranked overdue or due today first, then by priority (1 highest,
+// undefined last), then by due date and creation time. Completed and cancelled
+// tasks, tasks that start after today and parents with open subtasks (whose work
+// is tracked on the subtasks) are left out.
+func Build(tasks []backend.Task, capacity time.Duration, now time.Time) *Plan {
+	endOfToday := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, 1)
+
+	openChildren := make(map[string]bool)
+	for _, t := range tasks {
+		if t.ParentID != "" && isOpen(t.Status) {
+			openChildren[t.ParentID] = true
+		}
+	}
+
+	var candidates []backend.Task
+	for _, t := range tasks {
+		if !isOpen(t.Status) || openChildren[t.ID] {
+			continue
+		}
+		if t.StartDate != nil && !t.StartDate.Before(endOfToday) {
+			continue
+		}
+		candidates = append(candidates, t)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return ranksBefore(&candidates[i], &candidates[j], endOfToday)
+	})
+
+	plan := &Plan{Capacity: capacity}
+	for _, t := range candidates {
+		estimate, ok := Estimate(&t)
+		if !ok {
+			plan.Unestimated = append(plan.Unestimated, t)
+			continue
+		}
+		item := Item{Task: t, Estimate: estimate}
+		if plan.Planned+estimate <= capacity {
+			plan.Selected = append(plan.Selected, item)
+			plan.Planned += estimate
+		} else {
+			plan.Deferred = append(plan.Deferred, item)
+		}
+	}
+	return plan
+}
+
+// ranksBefore reports whether a should be worked on before b
+func ranksBefore(a, b *backend.Task, endOfToday time.Time) bool {
+	aUrgent := a.DueDate != nil && a.DueDate.Before(endOfToday)
+	bUrgent := b.DueDate != nil && b.DueDate.Before(endOfToday)
+	if aUrgent != bUrgent {
+		return aUrgent
+	}
+	if pa, pb := priorityRank(a.Priority), priorityRank(b.Priority); pa != pb {
+		return pa < pb
+	}
+	if (a.DueDate == nil) != (b.DueDate == nil) {
+		return a.DueDate != nil
+	}
+	if a.DueDate != nil && !a.DueDate.Equal(*b.DueDate) {
+		return a.DueDate.Before(*b.DueDate)
+	}
+	return a.Created.Before(b.Created)
+}
+
+// priorityRank orders priorities 1 (highest) to 9, with 0 (undefined) last
+func priorityRank(priority int) int {
+	if priority <= 0 {
+		return 10
+	}
+	return priority
+}
+
+// isOpen reports whether a task still needs work
+func isOpen(status backend.TaskStatus) bool {
+	return status != backend.StatusCompleted && status != backend.StatusCancelled
+}
@@ -0,0 +1,80 @@
+package planner
+
+import (
+	"testing"
+	"time"
+
+	"todoat/backend"
+)
+
+func estimated(id, estimate string, priority int) backend.Task {
+	return backend.Task{ID: id, Summary: id, Priority: priority, Status: backend.StatusNeedsAction,
+		Metadata: map[string]string{EstimateKey: estimate}}
+}
+
+func TestBuildFillsCapacityByRank(t *testing.T) {
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	yesterday := now.AddDate(0, 0, -1)
+	tomorrow := now.AddDate(0, 0, 1)
+
+	overdue := estimated("overdue", "1h", 5)
+	overdue.DueDate = &yesterday
+	big := estimated("big", "4h", 1)
+	urgent := estimated("urgent", "2h", 1)
+	urgent.DueDate = &tomorrow
+	small := estimated("small", "30m", 7)
+	later := estimated("later", "1h", 1)
+	later.StartDate = &tomorrow
+	done := estimated("done", "1h", 1)
+	done.Status = backend.StatusCompleted
+	parent := estimated("parent", "3h", 1)
+	child := estimated("child", "1h", 9)
+	child.ParentID = "parent"
+	unestimated := backend.Task{ID: "none", Summary: "none", Status: backend.StatusNeedsAction}
+
+	plan := Build([]backend.Task{small, big, unestimated, urgent, overdue, later, done, parent, child}, 4*time.Hour, now)
+
+	var selected []string
+	for _, item := range plan.Selected {
+		selected = append(selected, item.Task.ID)
+	}
+	want := []string{"overdue", "urgent", "small"}
+	if len(selected) != len(want) {
+		t.Fatalf("selected %v, want %v", selected, want)
+	}
+	for i := range want {
+		if selected[i] != want[i] {
+			t.Fatalf("selected %v, want %v", selected, want)
+		}
+	}
+	if plan.Planned != 3*time.Hour+30*time.Minute {
+		t.Errorf("planned %v, want 3h30m", plan.Planned)
+	}
+	if len(plan.Deferred) != 2 || plan.Deferred[0].Task.ID != "big" || plan.Deferred[1].Task.ID != "child" {
+		t.Errorf("expected big and child to be deferred, got %+v", plan.Deferred)
+	}
+	if len(plan.Unestimated) != 1 || plan.Unestimated[0].ID != "none" {
+		t.Errorf("expected one unestimated task, got %+v", plan.Unestimated)
+	}
+}
+
+func TestEstimate(t *testing.T) {
+	task := estimated("a", "1h30m", 0)
+	if d, ok := Estimate(&task); !ok || d != 90*time.Minute {
+		t.Errorf("Estimate = %v, %v; want 1h30m", d, ok)
+	}
+	task.Metadata[EstimateKey] = "soon"
+	if _, ok := Estimate(&task); ok {
+		t.Error("expected an invalid estimate to be ignored")
+	}
+}
+
+func TestSpent(t *testing.T) {
+	task := backend.Task{Metadata: map[string]string{SpentKey: "50m"}}
+	if d := Spent(&task); d != 50*time.Minute {
+		t.Errorf("Spent = %v, want 50m", d)
+	}
+	if d := Spent(&backend.Task{}); d != 0 {
+		t.Errorf("Spent without metadata = %v, want 0", d)
+	}
+}
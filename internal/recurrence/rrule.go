@@ -0,0 +1,389 @@
+// Package recurrence evaluates the RFC 5545 recurrence rules (RRULE) of
+// recurring tasks and iterates over their occurrences.
+package recurrence
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"todoat/internal/tz"
+)
+
+// Frequencies of a rule
+const (
+	Daily   = "DAILY"
+	Weekly  = "WEEKLY"
+	Monthly = "MONTHLY"
+	Yearly  = "YEARLY"
+)
+
+// maxEmptyPeriods bounds the periods searched for an occurrence, so that a rule
+// matching no date (BYMONTH=2;BYMONTHDAY=30) ends instead of looping forever
+const maxEmptyPeriods = 1000
+
+// WeekdayNum is a BYDAY entry: a weekday, with for monthly and yearly rules the
+// optional position of the weekday in the month (1 = first, -1 = last)
+type WeekdayNum struct {
+	N   int
+	Day time.Weekday
+}
+
+// Rule is a parsed recurrence rule. Supported parts are FREQ, INTERVAL, COUNT,
+// UNTIL, BYDAY, BYMONTHDAY, BYMONTH and WKST; other parts are ignored.
+type Rule struct {
+	Freq       string
+	Interval   int
+	Count      int        // Occurrences in the series, counting its start; 0 = unlimited
+	Until      *time.Time // Last possible occurrence
+	ByDay      []WeekdayNum
+	ByMonthDay []int // Days of the month; negative days count from the end of the month
+	ByMonth    []time.Month
+	WeekStart  time.Weekday
+}
+
+var weekdayCodes = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// Parse parses an RRULE such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR". A leading
+// "RRULE:" is accepted.
+func Parse(rrule string) (*Rule, error) {
+	rrule = strings.TrimPrefix(strings.TrimSpace(rrule), "RRULE:")
+	r := &Rule{Interval: 1, WeekStart: time.Monday}
+	for _, part := range strings.Split(rrule, ";") {
+		if part == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid RRULE part %q", part)
+		}
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			r.Freq = strings.ToUpper(value)
+			if r.Freq != Daily && r.Freq != Weekly && r.Freq != Monthly && r.Freq != Yearly {
+				return nil, fmt.Errorf("unsupported RRULE frequency %q (use DAILY, WEEKLY, MONTHLY or YEARLY)", value)
+			}
+		case "INTERVAL":
+			if r.Interval, err = strconv.Atoi(value); err != nil || r.Interval < 1 {
+				return nil, fmt.Errorf("invalid RRULE INTERVAL %q", value)
+			}
+		case "COUNT":
+			if r.Count, err = strconv.Atoi(value); err != nil || r.Count < 1 {
+				return nil, fmt.Errorf("invalid RRULE COUNT %q", value)
+			}
+		case "UNTIL":
+			until, err := parseUntil(value)
+			if err != nil {
+				return nil, err
+			}
+			r.Until = &until
+		case "BYDAY":
+			for _, code := range strings.Split(value, ",") {
+				day, err := parseWeekdayNum(code)
+				if err != nil {
+					return nil, err
+				}
+				r.ByDay = append(r.ByDay, day)
+			}
+		case "BYMONTHDAY":
+			for _, s := range strings.Split(value, ",") {
+				n, err := strconv.Atoi(s)
+				if err != nil || n == 0 || n < -31 || n > 31 {
+					return nil, fmt.Errorf("invalid RRULE BYMONTHDAY %q", s)
+				}
+				r.ByMonthDay = append(r.ByMonthDay, n)
+			}
+		case "BYMONTH":
+			for _, s := range strings.Split(value, ",") {
+				n, err := strconv.Atoi(s)
+				if err != nil || n < 1 || n > 12 {
+					return nil, fmt.Errorf("invalid RRULE BYMONTH %q", s)
+				}
+				r.ByMonth = append(r.ByMonth, time.Month(n))
+			}
+		case "WKST":
+			day, ok := weekdayCodes[strings.ToUpper(value)]
+			if !ok {
+				return nil, fmt.Errorf("invalid RRULE WKST %q", value)
+			}
+			r.WeekStart = day
+		}
+	}
+	if r.Freq == "" {
+		return nil, fmt.Errorf("RRULE has no FREQ: %q", rrule)
+	}
+	if r.Count > 0 && r.Until != nil {
+		return nil, fmt.Errorf("RRULE cannot have both COUNT and UNTIL")
+	}
+	return r, nil
+}
+
+// Anchor pins a rule whose occurrences follow the day of its series start to
+// the day of start, by adding BYDAY to weekly rules, BYMONTHDAY to monthly
+// rules and BYMONTH and BYMONTHDAY to yearly rules that have none. A series
+// whose start is later moved, such as off a weekend, then keeps its original
+// days instead of following the moved date. Other rules are returned as is.
+func Anchor(rrule string, start time.Time) string {
+	r, err := Parse(rrule)
+	if err != nil || len(r.ByDay) > 0 || len(r.ByMonthDay) > 0 {
+		return rrule
+	}
+	start = start.In(tz.Location())
+	var parts []string
+	switch r.Freq {
+	case Weekly:
+		for code, day := range weekdayCodes {
+			if day == start.Weekday() {
+				parts = append(parts, "BYDAY="+code)
+			}
+		}
+	case Monthly:
+		parts = append(parts, fmt.Sprintf("BYMONTHDAY=%d", start.Day()))
+	case Yearly:
+		if len(r.ByMonth) == 0 {
+			parts = append(parts, fmt.Sprintf("BYMONTH=%d", int(start.Month())))
+		}
+		parts = append(parts, fmt.Sprintf("BYMONTHDAY=%d", start.Day()))
+	default:
+		return rrule
+	}
+	return strings.TrimSuffix(rrule, ";") + ";" + strings.Join(parts, ";")
+}
+
+// parseWeekdayNum parses a BYDAY entry such as MO, 1MO or -1FR
+func parseWeekdayNum(s string) (WeekdayNum, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if len(s) < 2 {
+		return WeekdayNum{}, fmt.Errorf("invalid RRULE BYDAY %q", s)
+	}
+	day, ok := weekdayCodes[s[len(s)-2:]]
+	if !ok {
+		return WeekdayNum{}, fmt.Errorf("invalid RRULE BYDAY %q", s)
+	}
+	var n int
+	if prefix := s[:len(s)-2]; prefix != "" {
+		var err error
+		if n, err = strconv.Atoi(prefix); err != nil || n == 0 || n < -5 || n > 5 {
+			return WeekdayNum{}, fmt.Errorf("invalid RRULE BYDAY %q", s)
+		}
+	}
+	return WeekdayNum{N: n, Day: day}, nil
+}
+
+// parseUntil parses an UNTIL date (20261231) or time (20261231T235959Z).
+// Dates and floating times are in the local time zone.
+func parseUntil(s string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102T150405", "20060102"} {
+		loc := tz.Location()
+		if strings.HasSuffix(layout, "Z") {
+			loc = time.UTC
+		}
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			if layout == "20060102" {
+				// A date includes its whole day
+				t = t.AddDate(0, 0, 1).Add(-time.Second)
+			}
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid RRULE UNTIL %q (use YYYYMMDD or YYYYMMDDTHHMMSSZ)", s)
+}
+
+// Iterator yields the occurrences of a rule after the start of its series, in
+// order. The start itself is the first occurrence of the series and is not
+// yielded, but counts towards COUNT.
+type Iterator struct {
+	rule    *Rule
+	start   time.Time
+	period  int         // Periods of Interval units stepped from the period of start
+	pending []time.Time // Remaining occurrences of the current period
+	yielded int
+	done    bool
+}
+
+// Iter returns an iterator over the occurrences of the series starting at
+// start. Occurrences are computed in the local time zone, so they keep the
+// wall-clock time of start across DST changes.
+func (r *Rule) Iter(start time.Time) *Iterator {
+	start = start.In(tz.Location())
+	it := &Iterator{rule: r, start: start}
+	it.pending = it.expand(0)
+	return it
+}
+
+// Next returns the next occurrence, or false once the series has ended
+func (it *Iterator) Next() (time.Time, bool) {
+	if it.done {
+		return time.Time{}, false
+	}
+	if it.rule.Count > 0 && it.yielded+1 >= it.rule.Count {
+		it.done = true
+		return time.Time{}, false
+	}
+	for empty := 0; empty < maxEmptyPeriods; {
+		for len(it.pending) > 0 {
+			next := it.pending[0]
+			it.pending = it.pending[1:]
+			if !next.After(it.start) {
+				continue
+			}
+			if it.rule.Until != nil && next.After(*it.rule.Until) {
+				it.done = true
+				return time.Time{}, false
+			}
+			it.yielded++
+			return next, true
+		}
+		it.period++
+		it.pending = it.expand(it.period)
+		if len(it.pending) == 0 {
+			empty++
+		}
+	}
+	it.done = true
+	return time.Time{}, false
+}
+
+// Next returns the first occurrence after start of the series starting at start
+func (r *Rule) Next(start time.Time) (time.Time, bool) {
+	return r.Iter(start).Next()
+}
+
+// Occurrences returns up to n occurrences after start of the series starting at start
+func (r *Rule) Occurrences(start time.Time, n int) []time.Time {
+	it := r.Iter(start)
+	var out []time.Time
+	for len(out) < n {
+		next, ok := it.Next()
+		if !ok {
+			break
+		}
+		out = append(out, next)
+	}
+	return out
+}
+
+// expand returns the candidate dates of the period-th period of the series,
+// sorted, with the time of day of start
+func (it *Iterator) expand(period int) []time.Time {
+	r, s := it.rule, it.start
+	step := period * r.Interval
+	var days []time.Time
+	switch r.Freq {
+	case Daily:
+		day := s.AddDate(0, 0, step)
+		if r.matchDay(day) && r.matchMonthDay(day) && r.matchMonth(day) {
+			days = append(days, day)
+		}
+	case Weekly:
+		offset := (int(s.Weekday()) - int(r.WeekStart) + 7) % 7
+		weekStart := s.AddDate(0, 0, 7*step-offset)
+		for i := 0; i < 7; i++ {
+			day := weekStart.AddDate(0, 0, i)
+			if len(r.ByDay) == 0 && day.Weekday() != s.Weekday() {
+				continue
+			}
+			if r.matchDay(day) && r.matchMonth(day) {
+				days = append(days, day)
+			}
+		}
+	case Monthly:
+		first := time.Date(s.Year(), s.Month()+time.Month(step), 1, s.Hour(), s.Minute(), s.Second(), 0, tz.Location())
+		if r.matchMonth(first) {
+			days = it.expandMonth(first)
+		}
+	case Yearly:
+		months := r.ByMonth
+		if len(months) == 0 {
+			months = []time.Month{s.Month()}
+		}
+		for _, month := range months {
+			first := time.Date(s.Year()+step, month, 1, s.Hour(), s.Minute(), s.Second(), 0, tz.Location())
+			days = append(days, it.expandMonth(first)...)
+		}
+	}
+	slices.SortFunc(days, func(a, b time.Time) int { return a.Compare(b) })
+	return days
+}
+
+// expandMonth returns the days of the month starting at first that match
+// BYMONTHDAY and BYDAY, or the day of the month of the series start when the
+// rule has neither. Months without that day (the 31st) are skipped.
+func (it *Iterator) expandMonth(first time.Time) []time.Time {
+	r := it.rule
+	last := first.AddDate(0, 1, -1).Day()
+	var days []time.Time
+	for d := 1; d <= last; d++ {
+		day := first.AddDate(0, 0, d-1)
+		switch {
+		case len(r.ByMonthDay) == 0 && len(r.ByDay) == 0:
+			if d != it.start.Day() {
+				continue
+			}
+		case !r.matchMonthDay(day) || !r.matchDayInMonth(day, last):
+			continue
+		}
+		days = append(days, day)
+	}
+	return days
+}
+
+// matchDay reports whether the weekday of day is in BYDAY, ignoring positions
+func (r *Rule) matchDay(day time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(r.ByDay, func(w WeekdayNum) bool { return w.Day == day.Weekday() })
+}
+
+// matchDayInMonth reports whether day matches BYDAY, with positions counted
+// within its month of last days
+func (r *Rule) matchDayInMonth(day time.Time, last int) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, w := range r.ByDay {
+		if w.Day != day.Weekday() {
+			continue
+		}
+		switch {
+		case w.N == 0:
+			return true
+		case w.N > 0 && (day.Day()-1)/7+1 == w.N:
+			return true
+		case w.N < 0 && (last-day.Day())/7+1 == -w.N:
+			return true
+		}
+	}
+	return false
+}
+
+// matchMonthDay reports whether day is in BYMONTHDAY
+func (r *Rule) matchMonthDay(day time.Time) bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+	last := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, tz.Location()).Day()
+	for _, n := range r.ByMonthDay {
+		if n == day.Day() || (n < 0 && last+n+1 == day.Day()) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchMonth reports whether the month of day is in BYMONTH
+func (r *Rule) matchMonth(day time.Time) bool {
+	return len(r.ByMonth) == 0 || slices.Contains(r.ByMonth, day.Month())
+}
@@ -0,0 +1,91 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+)
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 9, 0, 0, 0, time.Local)
+}
+
+func TestOccurrences(t *testing.T) {
+	tests := []struct {
+		rrule string
+		start time.Time
+		want  []time.Time
+	}{
+		{"FREQ=DAILY;INTERVAL=2", date(2026, 3, 1), []time.Time{date(2026, 3, 3), date(2026, 3, 5), date(2026, 3, 7)}},
+		// Friday to the next weekdays
+		{"FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR", date(2026, 10, 16), []time.Time{date(2026, 10, 19), date(2026, 10, 20), date(2026, 10, 21)}},
+		// Every other week on Monday and Friday, from a Wednesday
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR", date(2026, 10, 14), []time.Time{date(2026, 10, 16), date(2026, 10, 26), date(2026, 10, 30)}},
+		// Months without a 31st are skipped
+		{"FREQ=MONTHLY", date(2026, 1, 31), []time.Time{date(2026, 3, 31), date(2026, 5, 31), date(2026, 7, 31)}},
+		{"FREQ=MONTHLY;BYDAY=-1FR", date(2026, 1, 30), []time.Time{date(2026, 2, 27), date(2026, 3, 27), date(2026, 4, 24)}},
+		{"FREQ=MONTHLY;BYMONTHDAY=1,-1", date(2026, 2, 1), []time.Time{date(2026, 2, 28), date(2026, 3, 1), date(2026, 3, 31)}},
+		{"FREQ=YEARLY;BYMONTH=1,7;BYMONTHDAY=15", date(2026, 1, 15), []time.Time{date(2026, 7, 15), date(2027, 1, 15), date(2027, 7, 15)}},
+		// The start counts as the first of COUNT occurrences
+		{"FREQ=WEEKLY;COUNT=3", date(2026, 10, 16), []time.Time{date(2026, 10, 23), date(2026, 10, 30)}},
+		{"FREQ=DAILY;UNTIL=20261018", date(2026, 10, 16), []time.Time{date(2026, 10, 17), date(2026, 10, 18)}},
+		{"FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30", date(2026, 1, 1), nil},
+	}
+	for _, tt := range tests {
+		rule, err := Parse(tt.rrule)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.rrule, err)
+		}
+		got := rule.Occurrences(tt.start, 3)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.rrule, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if !got[i].Equal(tt.want[i]) {
+				t.Errorf("%s: occurrence %d = %v, want %v", tt.rrule, i, got[i], tt.want[i])
+			}
+		}
+	}
+}
+
+func TestParseRejectsInvalidRules(t *testing.T) {
+	for _, rrule := range []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=DAILY;COUNT=2;UNTIL=20261231",
+	} {
+		if _, err := Parse(rrule); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", rrule)
+		}
+	}
+}
+
+func TestAnchor(t *testing.T) {
+	saturday := date(2026, 10, 17)
+	tests := map[string]string{
+		"FREQ=WEEKLY;INTERVAL=1":  "FREQ=WEEKLY;INTERVAL=1;BYDAY=SA",
+		"FREQ=MONTHLY":            "FREQ=MONTHLY;BYMONTHDAY=17",
+		"FREQ=YEARLY":             "FREQ=YEARLY;BYMONTH=10;BYMONTHDAY=17",
+		"FREQ=WEEKLY;BYDAY=MO,FR": "FREQ=WEEKLY;BYDAY=MO,FR",
+		"FREQ=DAILY;INTERVAL=2":   "FREQ=DAILY;INTERVAL=2",
+		"FREQ=MONTHLY;BYDAY=-1FR": "FREQ=MONTHLY;BYDAY=-1FR",
+	}
+	for rrule, want := range tests {
+		if got := Anchor(rrule, saturday); got != want {
+			t.Errorf("Anchor(%q) = %q, want %q", rrule, got, want)
+		}
+	}
+
+	// The anchored rule keeps Saturdays from a due date moved to Monday
+	rule, err := Parse(Anchor("FREQ=WEEKLY", saturday))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next, _ := rule.Next(date(2026, 10, 19)); !next.Equal(date(2026, 10, 24)) {
+		t.Errorf("next after the moved date = %v, want Saturday 2026-10-24", next)
+	}
+}
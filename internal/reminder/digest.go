@@ -0,0 +1,128 @@
+package reminder
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"todoat/backend"
+	"todoat/internal/notification"
+	"todoat/internal/tz"
+)
+
+// digestListLimit caps the tasks due today listed in a digest notification
+const digestListLimit = 10
+
+// Digest summarizes the open tasks due on a day and those overdue
+type Digest struct {
+	Day      time.Time       // Start of the day the digest is for
+	DueToday []*backend.Task // Open tasks due on Day, in the order given
+	Overdue  []*backend.Task // Open tasks due before Day
+}
+
+// BuildDigest collects the open tasks due today and overdue. Due dates are
+// compared by calendar day in the location of now, like the @today and
+// @overdue virtual lists.
+func BuildDigest(tasks []*backend.Task, now time.Time) *Digest {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	d := &Digest{Day: today}
+	for _, task := range tasks {
+		if task.DueDate == nil || task.Status == backend.StatusCompleted || task.Status == backend.StatusCancelled {
+			continue
+		}
+		due := task.DueDate.In(now.Location())
+		dueDay := time.Date(due.Year(), due.Month(), due.Day(), 0, 0, 0, 0, now.Location())
+		switch {
+		case dueDay.Equal(today):
+			d.DueToday = append(d.DueToday, task)
+		case dueDay.Before(today):
+			d.Overdue = append(d.Overdue, task)
+		}
+	}
+	return d
+}
+
+// Empty reports whether no task is due today or overdue
+func (d *Digest) Empty() bool {
+	return len(d.DueToday) == 0 && len(d.Overdue) == 0
+}
+
+// Title returns the one-line summary of the digest
+func (d *Digest) Title() string {
+	if d.Empty() {
+		return "Daily digest: nothing due today"
+	}
+	return fmt.Sprintf("Daily digest: %d due today, %d overdue", len(d.DueToday), len(d.Overdue))
+}
+
+// Message lists the tasks due today, with their time of day if they have one,
+// followed by the number of overdue tasks
+func (d *Digest) Message() string {
+	var b strings.Builder
+	if len(d.DueToday) > 0 {
+		b.WriteString("Due today:\n")
+		for i, task := range d.DueToday {
+			if i == digestListLimit {
+				fmt.Fprintf(&b, "- ... and %d more\n", len(d.DueToday)-i)
+				break
+			}
+			if hasTimeOfDay(*task.DueDate) {
+				fmt.Fprintf(&b, "- %s (%s)\n", task.Summary, task.DueDate.In(tz.Location()).Format("15:04"))
+			} else {
+				fmt.Fprintf(&b, "- %s\n", task.Summary)
+			}
+		}
+	} else {
+		b.WriteString("Nothing due today.\n")
+	}
+	switch len(d.Overdue) {
+	case 0:
+	case 1:
+		b.WriteString("1 task is overdue.")
+	default:
+		fmt.Fprintf(&b, "%d tasks are overdue.", len(d.Overdue))
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// Notification returns the digest as a notification for the given channels
+// (empty = all enabled channels)
+func (d *Digest) Notification(now time.Time, channels []string) notification.Notification {
+	return notification.Notification{
+		Type:      notification.NotifyDigest,
+		Title:     d.Title(),
+		Message:   d.Message(),
+		Timestamp: now,
+		Metadata: map[string]string{
+			"day":       d.Day.Format("2006-01-02"),
+			"due_today": strconv.Itoa(len(d.DueToday)),
+			"overdue":   strconv.Itoa(len(d.Overdue)),
+		},
+		Channels: channels,
+	}
+}
+
+// SendDigest sends the digest through the notifier to the configured digest channels
+func (s *Service) SendDigest(d *Digest, now time.Time) error {
+	if s.notifier == nil {
+		return fmt.Errorf("no notification channel is enabled")
+	}
+	return s.notifier.Send(d.Notification(now, s.config.Digest.Channels))
+}
+
+// DigestSent reports whether the digest for day has been sent
+func (s *Service) DigestSent(day time.Time) (bool, error) {
+	var count int
+	err := s.db.QueryRow(`SELECT COUNT(*) FROM digests WHERE day = ?`, day.Format("2006-01-02")).Scan(&count)
+	return count > 0, err
+}
+
+// MarkDigestSent records that the digest for day has been sent
+func (s *Service) MarkDigestSent(day time.Time, sentAt time.Time) error {
+	_, err := s.db.Exec(`
+		INSERT OR REPLACE INTO digests (day, sent_at)
+		VALUES (?, ?)
+	`, day.Format("2006-01-02"), sentAt)
+	return err
+}
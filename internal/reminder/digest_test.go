@@ -0,0 +1,149 @@
+package reminder_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"todoat/backend"
+	"todoat/internal/notification"
+	"todoat/internal/reminder"
+	"todoat/internal/testutil"
+)
+
+// TestBuildDigest verifies open tasks are split into due today and overdue by calendar day
+func TestBuildDigest(t *testing.T) {
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local)
+	at := func(day, hour int) *time.Time {
+		d := time.Date(2026, 3, day, hour, 0, 0, 0, time.Local)
+		return &d
+	}
+	tasks := []*backend.Task{
+		{ID: "today", Summary: "Pay rent", DueDate: at(10, 0), Status: backend.StatusNeedsAction},
+		{ID: "tonight", Summary: "Call Bob", DueDate: at(10, 18), Status: backend.StatusInProgress},
+		{ID: "late", Summary: "File taxes", DueDate: at(8, 0), Status: backend.StatusNeedsAction},
+		{ID: "done", Summary: "Old report", DueDate: at(9, 0), Status: backend.StatusCompleted},
+		{ID: "tomorrow", Summary: "Dentist", DueDate: at(11, 0), Status: backend.StatusNeedsAction},
+		{ID: "undated", Summary: "Someday", Status: backend.StatusNeedsAction},
+	}
+
+	digest := reminder.BuildDigest(tasks, now)
+	if len(digest.DueToday) != 2 || len(digest.Overdue) != 1 || digest.Overdue[0].ID != "late" {
+		t.Fatalf("expected 2 due today and 'late' overdue, got %d due today and %d overdue", len(digest.DueToday), len(digest.Overdue))
+	}
+	if got := digest.Title(); got != "Daily digest: 2 due today, 1 overdue" {
+		t.Errorf("Title = %q", got)
+	}
+	message := digest.Message()
+	for _, want := range []string{"- Pay rent\n", "- Call Bob (18:00)", "1 task is overdue."} {
+		if !strings.Contains(message, want) {
+			t.Errorf("Message should contain %q, got:\n%s", want, message)
+		}
+	}
+
+	if empty := reminder.BuildDigest(tasks[4:], now); !empty.Empty() || empty.Title() != "Daily digest: nothing due today" {
+		t.Errorf("expected an empty digest, got %q", empty.Title())
+	}
+}
+
+// TestDigestTime verifies the digest time format and default
+func TestDigestTime(t *testing.T) {
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local)
+	dueAt, err := notification.DigestConfig{}.DueAt(now)
+	if err != nil || dueAt.Hour() != 8 || dueAt.Minute() != 0 || dueAt.Day() != 10 {
+		t.Errorf("default DueAt = %v, %v; want 08:00 on the same day", dueAt, err)
+	}
+	for _, invalid := range []string{"8", "24:00", "7:5", "noon"} {
+		if _, _, err := notification.ParseDigestTime(invalid); err == nil {
+			t.Errorf("ParseDigestTime(%q) should fail", invalid)
+		}
+	}
+}
+
+// TestSendDigestChannels verifies the digest goes to the digest channels and the sent day is recorded
+func TestSendDigestChannels(t *testing.T) {
+	service, err := reminder.NewService(&reminder.Config{
+		Digest: notification.DigestConfig{Enabled: true, Channels: []string{"push"}},
+	}, filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create service: %v", err)
+	}
+	defer func() { _ = service.Close() }()
+
+	var sent []notification.Notification
+	service.SetNotifier(&mockNotificationManager{
+		sendFunc: func(n notification.Notification) error {
+			sent = append(sent, n)
+			return nil
+		},
+	})
+
+	now := time.Now()
+	due := now
+	digest := reminder.BuildDigest([]*backend.Task{{ID: "a", Summary: "Pay rent", DueDate: &due}}, now)
+	if err := service.SendDigest(digest, now); err != nil {
+		t.Fatalf("SendDigest failed: %v", err)
+	}
+	if len(sent) != 1 || sent[0].Type != notification.NotifyDigest || strings.Join(sent[0].Channels, ",") != "push" {
+		t.Fatalf("expected one digest notification for push, got %+v", sent)
+	}
+	if sent[0].Metadata["due_today"] != "1" {
+		t.Errorf("expected due_today metadata 1, got %q", sent[0].Metadata["due_today"])
+	}
+
+	if done, _ := service.DigestSent(digest.Day); done {
+		t.Error("digest should not be recorded as sent before MarkDigestSent")
+	}
+	if err := service.MarkDigestSent(digest.Day, now); err != nil {
+		t.Fatalf("MarkDigestSent failed: %v", err)
+	}
+	if done, _ := service.DigestSent(digest.Day); !done {
+		t.Error("digest should be recorded as sent")
+	}
+}
+
+// TestNotificationDigestCLI tests 'todoat notification digest' with and without --now
+func TestNotificationDigestCLI(t *testing.T) {
+	cli := testutil.NewCLITestWithReminder(t)
+	cli.SetReminderConfig(&reminder.Config{
+		LogNotification: true,
+		Digest:          notification.DigestConfig{Enabled: true, Time: "07:30"},
+	})
+
+	today := time.Now().Format("2006-01-02")
+	lastWeek := time.Now().AddDate(0, 0, -7).Format("2006-01-02")
+	cli.MustExecute("-y", "Work", "add", "Pay rent", "--due-date", today)
+	cli.MustExecute("-y", "Work", "add", "File taxes", "--due-date", lastWeek)
+
+	stdout := cli.MustExecute("-y", "notification", "digest")
+	testutil.AssertContains(t, stdout, "Daily digest: 1 due today, 1 overdue")
+	testutil.AssertContains(t, stdout, "- Pay rent")
+	testutil.AssertContains(t, stdout, "Schedule: daily at 07:30")
+	testutil.AssertContains(t, stdout, "Channels: log")
+	testutil.AssertResultCode(t, stdout, testutil.ResultInfoOnly)
+	if log := cli.GetNotificationLog(); strings.Contains(log, "DIGEST") {
+		t.Errorf("showing the digest should not send it, log:\n%s", log)
+	}
+
+	stdout = cli.MustExecute("-y", "notification", "digest", "--now")
+	testutil.AssertContains(t, stdout, "Digest sent to log: Daily digest: 1 due today, 1 overdue")
+	testutil.AssertResultCode(t, stdout, testutil.ResultActionCompleted)
+	log := cli.GetNotificationLog()
+	if !strings.Contains(log, "[DIGEST]") || !strings.Contains(log, "Pay rent") {
+		t.Errorf("expected the digest in the notification log, got:\n%s", log)
+	}
+
+	stdout = cli.MustExecute("-y", "--json", "notification", "digest")
+	testutil.AssertContains(t, stdout, `"sent":true`)
+	testutil.AssertContains(t, stdout, `"summary":"File taxes"`)
+}
+
+// TestNotificationDigestNoChannelCLI tests that sending a digest without an enabled channel fails
+func TestNotificationDigestNoChannelCLI(t *testing.T) {
+	cli := testutil.NewCLITestWithReminder(t)
+	cli.SetReminderConfig(&reminder.Config{Digest: notification.DigestConfig{Enabled: true}})
+
+	_, stderr := cli.ExecuteAndFail("-y", "notification", "digest", "--now")
+	testutil.AssertContains(t, stderr, "no notification channel is enabled")
+}
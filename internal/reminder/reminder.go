@@ -14,6 +14,7 @@ import (
 
 	"todoat/backend"
 	"todoat/internal/notification"
+	"todoat/internal/tz"
 
 	_ "modernc.org/sqlite"
 )
@@ -24,6 +25,19 @@ type Config struct {
 	Intervals       []string `yaml:"intervals" json:"intervals"`
 	OSNotification  bool     `yaml:"os_notification" json:"os_notification"`
 	LogNotification bool     `yaml:"log_notification" json:"log_notification"`
+
+	// Remote delivery channels
+	Email   notification.EmailNotificationConfig   `yaml:"email" json:"email"`
+	Webhook notification.WebhookNotificationConfig `yaml:"webhook" json:"webhook"`
+	Push    notification.PushNotificationConfig    `yaml:"push" json:"push"`
+
+	// IntervalChannels restricts the reminder for an interval to the named channels
+	// (e.g., "1h": ["push"]). Intervals not listed use every enabled channel.
+	// Channels set on a task with SetReminderChannels take precedence.
+	IntervalChannels map[string][]string `yaml:"interval_channels" json:"interval_channels"`
+
+	// Digest sends a daily summary of the tasks due today and overdue
+	Digest notification.DigestConfig `yaml:"digest" json:"digest"`
 }
 
 // Service manages task reminders
@@ -95,6 +109,42 @@ func NewService(cfg *Config, dbPath string) (*Service, error) {
 		return nil, fmt.Errorf("failed to create task_reminder_settings table: %w", err)
 	}
 
+	// Create task_reminder_channels table for per-task channel selection
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS task_reminder_channels (
+			task_id TEXT PRIMARY KEY,
+			channels TEXT NOT NULL
+		)
+	`)
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to create task_reminder_channels table: %w", err)
+	}
+
+	// Create task_reminder_intervals table for per-task intervals (e.g., imported alarms)
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS task_reminder_intervals (
+			task_id TEXT PRIMARY KEY,
+			intervals TEXT NOT NULL
+		)
+	`)
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to create task_reminder_intervals table: %w", err)
+	}
+
+	// Create digests table recording the days a digest was sent
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS digests (
+			day TEXT PRIMARY KEY,
+			sent_at DATETIME
+		)
+	`)
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to create digests table: %w", err)
+	}
+
 	return &Service{
 		config: cfg,
 		db:     db,
@@ -122,7 +172,7 @@ func (s *Service) CheckReminders(tasks []*backend.Task) ([]*backend.Task, error)
 
 	var triggered []*backend.Task
 	seen := make(map[string]bool)
-	now := time.Now()
+	now := tz.Now()
 
 	for _, task := range tasks {
 		if task.DueDate == nil || task.Status == backend.StatusCompleted {
@@ -143,8 +193,18 @@ func (s *Service) CheckReminders(tasks []*backend.Task) ([]*backend.Task, error)
 			continue
 		}
 
+		taskChannels, err := s.ReminderChannels(task.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		intervals, err := s.taskIntervals(task.ID)
+		if err != nil {
+			return nil, err
+		}
+
 		// Check each interval
-		for _, intervalStr := range s.config.Intervals {
+		for _, intervalStr := range intervals {
 			duration, isAtDue, err := ParseInterval(intervalStr)
 			if err != nil {
 				continue
@@ -153,11 +213,16 @@ func (s *Service) CheckReminders(tasks []*backend.Task) ([]*backend.Task, error)
 			// Check if reminder should trigger
 			shouldTrigger := false
 			if isAtDue {
-				// "at due time" - trigger if due date is today
+				// "at due time" - trigger on the due date, and for tasks due at a
+				// time of day only once that time has been reached
 				// Compare year, month, day in local time (not UTC truncation)
-				dueY, dueM, dueD := task.DueDate.Date()
+				due := task.DueDate.In(now.Location())
+				dueY, dueM, dueD := due.Date()
 				nowY, nowM, nowD := now.Date()
 				shouldTrigger = dueY == nowY && dueM == nowM && dueD == nowD
+				if shouldTrigger && hasTimeOfDay(due) {
+					shouldTrigger = !now.Before(due)
+				}
 			} else {
 				// Duration-based - trigger if within window
 				timeUntilDue := task.DueDate.Sub(now)
@@ -183,15 +248,20 @@ func (s *Service) CheckReminders(tasks []*backend.Task) ([]*backend.Task, error)
 
 			// Send notification
 			if s.notifier != nil {
+				channels := taskChannels
+				if len(channels) == 0 {
+					channels = s.config.IntervalChannels[intervalStr]
+				}
 				notif := notification.Notification{
 					Type:      notification.NotifyReminder,
 					Title:     "Task Reminder",
-					Message:   fmt.Sprintf("%s - Due: %s", task.Summary, task.DueDate.Format("2006-01-02")),
+					Message:   fmt.Sprintf("%s - Due: %s", task.Summary, formatDue(*task.DueDate)),
 					Timestamp: now,
 					Metadata: map[string]string{
 						"task_id":  task.ID,
 						"interval": intervalStr,
 					},
+					Channels: channels,
 				}
 				_ = s.notifier.Send(notif)
 			}
@@ -235,31 +305,93 @@ func (s *Service) EnableReminder(taskID string) error {
 	return err
 }
 
-// GetUpcomingReminders returns tasks with upcoming reminders
-func (s *Service) GetUpcomingReminders(tasks []*backend.Task) ([]*backend.Task, error) {
-	if !s.config.Enabled || len(s.config.Intervals) == 0 {
+// SetReminderChannels restricts a task's reminders to the named channels.
+// An empty list clears the selection so the configured channels are used again.
+func (s *Service) SetReminderChannels(taskID string, channels []string) error {
+	if len(channels) == 0 {
+		_, err := s.db.Exec(`DELETE FROM task_reminder_channels WHERE task_id = ?`, taskID)
+		return err
+	}
+	_, err := s.db.Exec(`
+		INSERT OR REPLACE INTO task_reminder_channels (task_id, channels)
+		VALUES (?, ?)
+	`, taskID, strings.Join(channels, ","))
+	return err
+}
+
+// ReminderChannels returns the channels selected for a task's reminders, or nil if none are set
+func (s *Service) ReminderChannels(taskID string) ([]string, error) {
+	var channels string
+	err := s.db.QueryRow(`
+		SELECT channels FROM task_reminder_channels
+		WHERE task_id = ?
+	`, taskID).Scan(&channels)
+
+	if err == sql.ErrNoRows {
 		return nil, nil
 	}
+	if err != nil {
+		return nil, err
+	}
 
-	// Find the maximum interval to use as the window
-	var maxDuration time.Duration
-	for _, intervalStr := range s.config.Intervals {
-		duration, isAtDue, err := ParseInterval(intervalStr)
-		if err != nil {
-			continue
-		}
-		if isAtDue {
-			// "at due time" effectively has 0 advance warning
-			continue
-		}
-		if duration > maxDuration {
-			maxDuration = duration
+	return strings.Split(channels, ","), nil
+}
+
+// SetReminderIntervals sets the intervals used for a task's reminders in place of
+// the configured ones. An empty list clears them so the configured intervals apply again.
+func (s *Service) SetReminderIntervals(taskID string, intervals []string) error {
+	if len(intervals) == 0 {
+		_, err := s.db.Exec(`DELETE FROM task_reminder_intervals WHERE task_id = ?`, taskID)
+		return err
+	}
+	for _, interval := range intervals {
+		if _, _, err := ParseInterval(interval); err != nil {
+			return err
 		}
 	}
+	_, err := s.db.Exec(`
+		INSERT OR REPLACE INTO task_reminder_intervals (task_id, intervals)
+		VALUES (?, ?)
+	`, taskID, strings.Join(intervals, ","))
+	return err
+}
+
+// ReminderIntervals returns the intervals set on a task, or nil if it uses the configured ones
+func (s *Service) ReminderIntervals(taskID string) ([]string, error) {
+	var intervals string
+	err := s.db.QueryRow(`
+		SELECT intervals FROM task_reminder_intervals
+		WHERE task_id = ?
+	`, taskID).Scan(&intervals)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.Split(intervals, ","), nil
+}
+
+// taskIntervals returns the intervals that apply to a task
+func (s *Service) taskIntervals(taskID string) ([]string, error) {
+	intervals, err := s.ReminderIntervals(taskID)
+	if err != nil || len(intervals) > 0 {
+		return intervals, err
+	}
+	return s.config.Intervals, nil
+}
+
+// GetUpcomingReminders returns tasks with upcoming reminders
+func (s *Service) GetUpcomingReminders(tasks []*backend.Task) ([]*backend.Task, error) {
+	if !s.config.Enabled {
+		return nil, nil
+	}
 
 	var upcoming []*backend.Task
 	seen := make(map[string]bool)
-	now := time.Now()
+	now := tz.Now()
 
 	for _, task := range tasks {
 		if task.DueDate == nil || task.Status == backend.StatusCompleted {
@@ -280,9 +412,17 @@ func (s *Service) GetUpcomingReminders(tasks []*backend.Task) ([]*backend.Task,
 			continue
 		}
 
-		// Check if due date is within the max window
+		intervals, err := s.taskIntervals(task.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(intervals) == 0 {
+			continue
+		}
+
+		// Check if due date is within the task's largest interval
 		timeUntilDue := task.DueDate.Sub(now)
-		if timeUntilDue >= 0 && timeUntilDue <= maxDuration {
+		if timeUntilDue >= 0 && timeUntilDue <= maxInterval(intervals) {
 			upcoming = append(upcoming, task)
 			seen[task.ID] = true
 		}
@@ -291,6 +431,39 @@ func (s *Service) GetUpcomingReminders(tasks []*backend.Task) ([]*backend.Task,
 	return upcoming, nil
 }
 
+// hasTimeOfDay reports whether a due date has a time of day rather than being a date only
+func hasTimeOfDay(t time.Time) bool {
+	return t.Hour() != 0 || t.Minute() != 0 || t.Second() != 0
+}
+
+// formatDue formats a due date for notifications, with the local time for tasks
+// due at a time of day
+func formatDue(t time.Time) string {
+	if hasTimeOfDay(t) {
+		return t.In(tz.Location()).Format("2006-01-02 15:04")
+	}
+	return t.Format("2006-01-02")
+}
+
+// maxInterval returns the longest advance warning among intervals
+func maxInterval(intervals []string) time.Duration {
+	var maxDuration time.Duration
+	for _, intervalStr := range intervals {
+		duration, isAtDue, err := ParseInterval(intervalStr)
+		if err != nil {
+			continue
+		}
+		if isAtDue {
+			// "at due time" effectively has 0 advance warning
+			continue
+		}
+		if duration > maxDuration {
+			maxDuration = duration
+		}
+	}
+	return maxDuration
+}
+
 // isDismissed checks if a reminder has been dismissed for a specific interval
 func (s *Service) isDismissed(taskID, interval string) (bool, error) {
 	var dismissedAt sql.NullTime
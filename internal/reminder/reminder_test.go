@@ -592,6 +592,50 @@ func TestReminderServiceCheckReminders(t *testing.T) {
 	}
 }
 
+// TestReminderAtDueTimeWithTimeOfDay tests that "at due time" waits for the due
+// time of tasks due at a time of day, and that the notification shows the time
+func TestReminderAtDueTimeWithTimeOfDay(t *testing.T) {
+	now := time.Now()
+	if now.Hour() == 23 || now.Hour() == 0 {
+		t.Skip("needs room for a due time later today and earlier today")
+	}
+
+	service, err := reminder.NewService(&reminder.Config{
+		Enabled:   true,
+		Intervals: []string{"at due time"},
+	}, filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create service: %v", err)
+	}
+	defer func() { _ = service.Close() }()
+
+	var sent []notification.Notification
+	service.SetNotifier(&mockNotificationManager{
+		sendFunc: func(n notification.Notification) error {
+			sent = append(sent, n)
+			return nil
+		},
+	})
+
+	later := time.Date(now.Year(), now.Month(), now.Day(), now.Hour()+1, 0, 0, 0, time.Local)
+	earlier := time.Date(now.Year(), now.Month(), now.Day(), now.Hour()-1, 30, 0, 0, time.Local)
+	tasks := []*backend.Task{
+		{ID: "later", Summary: "Call later", DueDate: &later, Status: backend.StatusNeedsAction},
+		{ID: "earlier", Summary: "Call earlier", DueDate: &earlier, Status: backend.StatusNeedsAction},
+	}
+
+	triggered, err := service.CheckReminders(tasks)
+	if err != nil {
+		t.Fatalf("CheckReminders failed: %v", err)
+	}
+	if len(triggered) != 1 || triggered[0].ID != "earlier" {
+		t.Fatalf("expected only the task whose due time has passed to trigger, got %v", triggered)
+	}
+	if len(sent) != 1 || !strings.Contains(sent[0].Message, earlier.Format("2006-01-02 15:04")) {
+		t.Errorf("expected the notification to include the due time, got %+v", sent)
+	}
+}
+
 // TestReminderServiceDismissedReminders tests that dismissed reminders are not triggered
 func TestReminderServiceDismissedReminders(t *testing.T) {
 	tmpDir := t.TempDir()
@@ -1261,3 +1305,198 @@ func TestReminderServiceCreatesParentDirectory(t *testing.T) {
 		t.Errorf("expected 0 upcoming reminders, got %d", len(upcoming))
 	}
 }
+
+// TestReminderIntervalChannels tests that per-interval channel selection is passed to the notifier
+func TestReminderIntervalChannels(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "test.db")
+
+	service, err := reminder.NewService(&reminder.Config{
+		Enabled:          true,
+		Intervals:        []string{"1h", "1d"},
+		IntervalChannels: map[string][]string{"1h": {"push", "email"}},
+	}, dbPath)
+	if err != nil {
+		t.Fatalf("failed to create service: %v", err)
+	}
+	defer func() { _ = service.Close() }()
+
+	var sent []notification.Notification
+	service.SetNotifier(&mockNotificationManager{
+		sendFunc: func(n notification.Notification) error {
+			sent = append(sent, n)
+			return nil
+		},
+	})
+
+	soon := time.Now().Add(30 * time.Minute)
+	tomorrow := time.Now().Add(20 * time.Hour)
+	tasks := []*backend.Task{
+		{ID: "soon", Summary: "Soon task", DueDate: &soon, Status: backend.StatusNeedsAction},
+		{ID: "later", Summary: "Later task", DueDate: &tomorrow, Status: backend.StatusNeedsAction},
+	}
+
+	if _, err := service.CheckReminders(tasks); err != nil {
+		t.Fatalf("CheckReminders failed: %v", err)
+	}
+	if len(sent) != 2 {
+		t.Fatalf("expected 2 notifications, got %d", len(sent))
+	}
+	for _, n := range sent {
+		switch n.Metadata["task_id"] {
+		case "soon":
+			if strings.Join(n.Channels, ",") != "push,email" {
+				t.Errorf("expected 1h reminder restricted to push,email, got %v", n.Channels)
+			}
+		case "later":
+			if len(n.Channels) != 0 {
+				t.Errorf("expected 1d reminder to use all channels, got %v", n.Channels)
+			}
+		}
+	}
+}
+
+// TestReminderStatusShowsChannelsCLI tests that 'reminder status' lists the enabled delivery channels
+func TestReminderStatusShowsChannelsCLI(t *testing.T) {
+	cli := testutil.NewCLITestWithConfig(t)
+
+	cli.SetFullConfig(`backends:
+  sqlite:
+    type: sqlite
+    enabled: true
+
+default_backend: sqlite
+
+reminder:
+  enabled: true
+  intervals:
+    - 1h
+  log_notification: true
+  webhook:
+    enabled: true
+    url: https://example.com/hook
+  push:
+    enabled: true
+    provider: ntfy
+    topic: my-tasks
+    retries: 3
+    retry_delay: 5s
+`)
+
+	stdout := cli.MustExecute("-y", "reminder", "status")
+	testutil.AssertContains(t, stdout, "Channels: log, webhook, push")
+
+	stdout = cli.MustExecute("-y", "--json", "reminder", "status")
+	testutil.AssertContains(t, stdout, `"channels":["log","webhook","push"]`)
+}
+
+// TestReminderTaskChannels tests that channels chosen for a task override the interval channels
+func TestReminderTaskChannels(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	service, err := reminder.NewService(&reminder.Config{
+		Enabled:          true,
+		Intervals:        []string{"1h"},
+		IntervalChannels: map[string][]string{"1h": {"push"}},
+	}, dbPath)
+	if err != nil {
+		t.Fatalf("failed to create service: %v", err)
+	}
+	defer func() { _ = service.Close() }()
+
+	sent := make(map[string][]string)
+	service.SetNotifier(&mockNotificationManager{
+		sendFunc: func(n notification.Notification) error {
+			sent[n.Metadata["task_id"]] = n.Channels
+			return nil
+		},
+	})
+
+	if err := service.SetReminderChannels("email-only", []string{"email"}); err != nil {
+		t.Fatalf("SetReminderChannels failed: %v", err)
+	}
+	if err := service.SetReminderChannels("cleared", []string{"webhook"}); err != nil {
+		t.Fatalf("SetReminderChannels failed: %v", err)
+	}
+	if err := service.SetReminderChannels("cleared", nil); err != nil {
+		t.Fatalf("clearing channels failed: %v", err)
+	}
+
+	soon := time.Now().Add(30 * time.Minute)
+	tasks := []*backend.Task{
+		{ID: "email-only", Summary: "Email task", DueDate: &soon, Status: backend.StatusNeedsAction},
+		{ID: "cleared", Summary: "Default task", DueDate: &soon, Status: backend.StatusNeedsAction},
+	}
+	if _, err := service.CheckReminders(tasks); err != nil {
+		t.Fatalf("CheckReminders failed: %v", err)
+	}
+
+	if strings.Join(sent["email-only"], ",") != "email" {
+		t.Errorf("expected task channels to override interval channels, got %v", sent["email-only"])
+	}
+	if strings.Join(sent["cleared"], ",") != "push" {
+		t.Errorf("expected cleared task to use interval channels, got %v", sent["cleared"])
+	}
+}
+
+func TestReminderTaskIntervals(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	service, err := reminder.NewService(&reminder.Config{
+		Enabled:   true,
+		Intervals: []string{"15m"},
+	}, dbPath)
+	if err != nil {
+		t.Fatalf("failed to create service: %v", err)
+	}
+	defer func() { _ = service.Close() }()
+
+	if err := service.SetReminderIntervals("early", []string{"2h", "1d"}); err != nil {
+		t.Fatalf("SetReminderIntervals failed: %v", err)
+	}
+	if err := service.SetReminderIntervals("bad", []string{"soon"}); err == nil {
+		t.Error("expected an invalid interval to be rejected")
+	}
+	got, err := service.ReminderIntervals("early")
+	if err != nil || strings.Join(got, ",") != "2h,1d" {
+		t.Errorf("ReminderIntervals = %v, %v", got, err)
+	}
+
+	inOneHour := time.Now().Add(time.Hour)
+	tasks := []*backend.Task{
+		{ID: "early", Summary: "Own intervals", DueDate: &inOneHour, Status: backend.StatusNeedsAction},
+		{ID: "default", Summary: "Configured intervals", DueDate: &inOneHour, Status: backend.StatusNeedsAction},
+	}
+	triggered, err := service.CheckReminders(tasks)
+	if err != nil {
+		t.Fatalf("CheckReminders failed: %v", err)
+	}
+	if len(triggered) != 1 || triggered[0].ID != "early" {
+		t.Errorf("expected only the task with its own 2h interval to trigger, got %v", triggered)
+	}
+
+	if err := service.SetReminderIntervals("early", nil); err != nil {
+		t.Fatalf("clearing intervals failed: %v", err)
+	}
+	if got, _ := service.ReminderIntervals("early"); got != nil {
+		t.Errorf("expected cleared intervals, got %v", got)
+	}
+}
+
+// TestReminderChannelsCLI tests the 'todoat reminder channels' command
+func TestReminderChannelsCLI(t *testing.T) {
+	cli := testutil.NewCLITestWithReminder(t)
+	cli.SetReminderConfig(&reminder.Config{Enabled: true, Intervals: []string{"1 day"}, LogNotification: true})
+
+	cli.MustExecute("-y", "Work", "add", "Pay rent")
+
+	stdout := cli.MustExecute("-y", "reminder", "channels", "Pay rent", "email", "push")
+	testutil.AssertContains(t, stdout, "Reminders for task Pay rent go to: email, push")
+	testutil.AssertResultCode(t, stdout, testutil.ResultActionCompleted)
+
+	stdout = cli.MustExecute("-y", "reminder", "channels", "Pay rent")
+	testutil.AssertContains(t, stdout, "use the configured channels")
+
+	_, stderr := cli.ExecuteAndFail("-y", "reminder", "channels", "Pay rent", "sms")
+	testutil.AssertContains(t, stderr, `unknown reminder channel "sms"`)
+}
@@ -0,0 +1,88 @@
+// Package review collects the tasks a weekly review walks through: overdue
+// tasks, tasks that have not changed for a long time and tasks without a due
+// date.
+package review
+
+import (
+	"sort"
+	"time"
+
+	"todoat/backend"
+)
+
+// DefaultStaleAfter is how long an open task can go unchanged before it is stale
+const DefaultStaleAfter = 30 * 24 * time.Hour
+
+// Category is the reason a task needs review
+type Category string
+
+const (
+	Overdue   Category = "overdue"     // Due before today
+	Stale     Category = "stale"       // Not modified within the stale period
+	NoDueDate Category = "no_due_date" // No due date
+)
+
+// Categories lists the categories in review order
+var Categories = []Category{Overdue, Stale, NoDueDate}
+
+// Label returns the heading of a category in text output
+func (c Category) Label() string {
+	switch c {
+	case Overdue:
+		return "Overdue"
+	case Stale:
+		return "Stale"
+	default:
+		return "No due date"
+	}
+}
+
+// Item is a task to review and the reason it needs review
+type Item struct {
+	Task     backend.Task
+	Category Category
+}
+
+// Collect returns the open tasks that need review, each under the first
+// matching category of Categories, ordered by category and then by due date
+// (overdue), last change (stale) or creation (no due date), oldest first.
+func Collect(tasks []backend.Task, now time.Time, staleAfter time.Duration) []Item {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	staleBefore := now.Add(-staleAfter)
+
+	var items []Item
+	for _, t := range tasks {
+		base := t.Status.Base()
+		if base == backend.StatusCompleted || base == backend.StatusCancelled {
+			continue
+		}
+		switch {
+		case t.DueDate != nil && t.DueDate.Before(today):
+			items = append(items, Item{Task: t, Category: Overdue})
+		case t.Modified.Before(staleBefore):
+			items = append(items, Item{Task: t, Category: Stale})
+		case t.DueDate == nil:
+			items = append(items, Item{Task: t, Category: NoDueDate})
+		}
+	}
+
+	rank := make(map[Category]int, len(Categories))
+	for i, c := range Categories {
+		rank[c] = i
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if a.Category != b.Category {
+			return rank[a.Category] < rank[b.Category]
+		}
+		switch a.Category {
+		case Overdue:
+			return a.Task.DueDate.Before(*b.Task.DueDate)
+		case Stale:
+			return a.Task.Modified.Before(b.Task.Modified)
+		default:
+			return a.Task.Created.Before(b.Task.Created)
+		}
+	})
+	return items
+}
@@ -0,0 +1,52 @@
+package review
+
+import (
+	"testing"
+	"time"
+
+	"todoat/backend"
+)
+
+func TestCollectCategorizesOpenTasks(t *testing.T) {
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	day := func(n int) *time.Time {
+		d := time.Date(2026, 3, 10+n, 0, 0, 0, 0, time.UTC)
+		return &d
+	}
+	recent := now.Add(-time.Hour)
+	old := now.AddDate(0, 0, -45)
+	older := now.AddDate(0, 0, -60)
+
+	tasks := []backend.Task{
+		{ID: "due-today", DueDate: day(0), Modified: recent},
+		{ID: "overdue-late", DueDate: day(-1), Modified: recent},
+		{ID: "overdue-early", DueDate: day(-5), Modified: old},
+		{ID: "stale", DueDate: day(3), Modified: old},
+		{ID: "stale-older", Modified: older},
+		{ID: "undated-new", Modified: recent, Created: recent},
+		{ID: "undated-old", Modified: recent, Created: old},
+		{ID: "done", Status: backend.StatusCompleted, DueDate: day(-3), Modified: old},
+		{ID: "cancelled", Status: backend.StatusCancelled},
+	}
+
+	items := Collect(tasks, now, DefaultStaleAfter)
+	want := []struct {
+		id       string
+		category Category
+	}{
+		{"overdue-early", Overdue},
+		{"overdue-late", Overdue},
+		{"stale-older", Stale},
+		{"stale", Stale},
+		{"undated-old", NoDueDate},
+		{"undated-new", NoDueDate},
+	}
+	if len(items) != len(want) {
+		t.Fatalf("Collect returned %d items, want %d: %+v", len(items), len(want), items)
+	}
+	for i, w := range want {
+		if items[i].Task.ID != w.id || items[i].Category != w.category {
+			t.Errorf("items[%d] = %s/%s, want %s/%s", i, items[i].Task.ID, items[i].Category, w.id, w.category)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+// Package rowindex remembers the task rows of the last listing of a list so
+// the next command can refer to a task by its row number instead of its
+// summary or UID.
+package rowindex
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// DefaultTTL is how long row numbers stay valid after a listing
+const DefaultTTL = 15 * time.Minute
+
+// Index is the row order of one listing
+type Index struct {
+	Backend string    `json:"backend"`
+	ListID  string    `json:"list_id"`
+	List    string    `json:"list"`
+	Created time.Time `json:"created"`
+	UIDs    []string  `json:"uids"` // Task UIDs by row, starting at row 1
+}
+
+// Row parses a task reference as a row number. It returns false for anything
+// but a positive integer.
+func Row(ref string) (int, bool) {
+	n, err := strconv.Atoi(ref)
+	if err != nil || n < 1 {
+		return 0, false
+	}
+	return n, true
+}
+
+// Matches reports whether the index is a listing of the list on the backend
+// made within ttl of now
+func (idx *Index) Matches(backend, listID string, now time.Time, ttl time.Duration) bool {
+	return idx != nil && idx.Backend == backend && idx.ListID == listID && now.Sub(idx.Created) <= ttl
+}
+
+// UID returns the UID of the task on row n
+func (idx *Index) UID(n int) (string, error) {
+	if n < 1 || n > len(idx.UIDs) {
+		return "", fmt.Errorf("row %d is not in the last listing of '%s' (rows 1-%d)", n, idx.List, len(idx.UIDs))
+	}
+	return idx.UIDs[n-1], nil
+}
+
+// Save writes the index to path
+func Save(path string, idx *Index) error {
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// Load reads the index at path. It returns nil without an error when there is none.
+func Load(path string) (*Index, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var idx Index
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return nil, fmt.Errorf("invalid row index file %s: %w", path, err)
+	}
+	return &idx, nil
+}
@@ -0,0 +1,48 @@
+package rowindex
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRow(t *testing.T) {
+	for ref, want := range map[string]int{"3": 3, "12": 12, "0": 0, "-1": 0, "3a": 0, "Buy milk": 0} {
+		n, ok := Row(ref)
+		if n != want || ok != (want > 0) {
+			t.Errorf("Row(%q) = %d, %v; want %d", ref, n, ok, want)
+		}
+	}
+}
+
+func TestIndexFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "rows.json")
+	if idx, err := Load(path); idx != nil || err != nil {
+		t.Fatalf("Load(missing) = %v, %v; want nil, nil", idx, err)
+	}
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	if err := Save(path, &Index{Backend: "sqlite", ListID: "l1", List: "Work", Created: now, UIDs: []string{"a", "b"}}); err != nil {
+		t.Fatalf("Save error: %v", err)
+	}
+	idx, err := Load(path)
+	if err != nil || idx == nil {
+		t.Fatalf("Load = %v, %v", idx, err)
+	}
+
+	if !idx.Matches("sqlite", "l1", now.Add(time.Minute), DefaultTTL) {
+		t.Error("expected a fresh index of the same list to match")
+	}
+	if idx.Matches("sqlite", "l2", now, DefaultTTL) || idx.Matches("todoist", "l1", now, DefaultTTL) {
+		t.Error("expected an index of another list or backend not to match")
+	}
+	if idx.Matches("sqlite", "l1", now.Add(DefaultTTL+time.Second), DefaultTTL) {
+		t.Error("expected an expired index not to match")
+	}
+
+	if uid, err := idx.UID(2); uid != "b" || err != nil {
+		t.Errorf("UID(2) = %q, %v", uid, err)
+	}
+	if _, err := idx.UID(3); err == nil || err.Error() != "row 3 is not in the last listing of 'Work' (rows 1-2)" {
+		t.Errorf("UID(3) error = %v", err)
+	}
+}
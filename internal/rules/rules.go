@@ -0,0 +1,137 @@
+// Package rules evaluates automation rules that change open tasks matching a
+// set of view filters, e.g. raising the priority of tasks that are due soon or
+// tagging tasks that have been open for a long time.
+package rules
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"todoat/backend"
+	"todoat/internal/views"
+)
+
+// Rule changes the open tasks matching all of its conditions
+type Rule struct {
+	Name  string
+	Lists []string       // List names the rule applies to; empty applies to every list
+	When  []views.Filter // Conditions, combined with AND like view filters
+	Then  Actions
+}
+
+// Actions are the changes a rule makes to a matching task. Zero values leave
+// the task unchanged.
+type Actions struct {
+	SetPriority *int               // New priority; 0 clears it
+	SetStatus   backend.TaskStatus // New open status
+	AddTags     []string
+	RemoveTags  []string
+}
+
+// Action is one change a rule made to a task
+type Action struct {
+	Rule        string
+	Description string // e.g. "priority 5 -> 2" or "add tag stale"
+}
+
+// Change is a task changed by one or more rules
+type Change struct {
+	Task    backend.Task // The task with every matching rule applied
+	Actions []Action
+}
+
+// AppliesTo reports whether the rule applies to tasks of the named list
+func (r *Rule) AppliesTo(listName string) bool {
+	if len(r.Lists) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(r.Lists, func(name string) bool {
+		return strings.EqualFold(name, listName)
+	})
+}
+
+// Evaluate applies the rules in order to the open tasks of a list and returns
+// the tasks that changed. Each rule sees the changes of the rules before it.
+// Actions that would not change anything are left out, so evaluating the same
+// rules again on the result returns no changes. Status changes the workflow
+// does not allow are skipped.
+func Evaluate(rules []Rule, listName string, tasks []backend.Task) []Change {
+	var changes []Change
+	for _, t := range tasks {
+		if !isOpen(t.Status) {
+			continue
+		}
+		change := Change{Task: t}
+		for i := range rules {
+			r := &rules[i]
+			if !r.AppliesTo(listName) || !views.MatchesFilters(&change.Task, r.When) {
+				continue
+			}
+			for _, desc := range apply(&change.Task, r.Then) {
+				change.Actions = append(change.Actions, Action{Rule: r.Name, Description: desc})
+			}
+		}
+		if len(change.Actions) > 0 {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// apply makes the actions' changes to t and describes the ones that changed it
+func apply(t *backend.Task, a Actions) []string {
+	var done []string
+	if a.SetPriority != nil && *a.SetPriority != t.Priority {
+		done = append(done, fmt.Sprintf("priority %d -> %d", t.Priority, *a.SetPriority))
+		t.Priority = *a.SetPriority
+	}
+	if a.SetStatus != "" && a.SetStatus != t.Status && backend.CheckStatusTransition(t.Status, a.SetStatus) == nil {
+		done = append(done, fmt.Sprintf("status %s -> %s", t.Status.Name(), a.SetStatus.Name()))
+		t.Status = a.SetStatus
+	}
+	tags := splitTags(t.Categories)
+	changed := false
+	for _, tag := range a.RemoveTags {
+		if i := indexTag(tags, tag); i >= 0 {
+			tags = slices.Delete(tags, i, i+1)
+			done = append(done, "remove tag "+tag)
+			changed = true
+		}
+	}
+	for _, tag := range a.AddTags {
+		if indexTag(tags, tag) < 0 {
+			tags = append(tags, tag)
+			done = append(done, "add tag "+tag)
+			changed = true
+		}
+	}
+	if changed {
+		t.Categories = strings.Join(tags, ",")
+	}
+	return done
+}
+
+// isOpen reports whether rules apply to a task with status s
+func isOpen(s backend.TaskStatus) bool {
+	base := s.Base()
+	return base != backend.StatusCompleted && base != backend.StatusCancelled
+}
+
+// splitTags splits comma-separated categories into trimmed, non-empty tags
+func splitTags(categories string) []string {
+	var tags []string
+	for _, tag := range strings.Split(categories, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// indexTag returns the index of tag in tags, ignoring case, or -1
+func indexTag(tags []string, tag string) int {
+	return slices.IndexFunc(tags, func(t string) bool {
+		return strings.EqualFold(t, tag)
+	})
+}
@@ -0,0 +1,98 @@
+package rules
+
+import (
+	"testing"
+	"time"
+
+	"todoat/backend"
+	"todoat/internal/views"
+)
+
+func intPtr(n int) *int { return &n }
+
+func TestEvaluateAppliesMatchingRules(t *testing.T) {
+	now := time.Now()
+	soon := now.AddDate(0, 0, 1)
+	later := now.AddDate(0, 0, 10)
+	old := now.AddDate(0, 0, -45)
+
+	rules := []Rule{
+		{
+			Name: "escalate",
+			When: []views.Filter{
+				{Field: "due_date", Operator: "lte", Value: "+2d"},
+				{Field: "priority", Operator: "gt", Value: 3},
+			},
+			Then: Actions{SetPriority: intPtr(2)},
+		},
+		{
+			Name: "stale",
+			When: []views.Filter{
+				{Field: "status", Operator: "eq", Value: "TODO"},
+				{Field: "created", Operator: "lt", Value: "-30d"},
+			},
+			Then: Actions{AddTags: []string{"stale"}, RemoveTags: []string{"fresh"}},
+		},
+	}
+	tasks := []backend.Task{
+		{ID: "due-soon", Summary: "Due soon", Status: backend.StatusNeedsAction, Priority: 5, DueDate: &soon, Created: now},
+		{ID: "due-later", Summary: "Due later", Status: backend.StatusNeedsAction, Priority: 5, DueDate: &later, Created: now},
+		{ID: "old", Summary: "Old", Status: backend.StatusNeedsAction, Categories: "fresh,home", Created: old},
+		{ID: "old-done", Summary: "Old done", Status: backend.StatusCompleted, Created: old},
+	}
+
+	changes := Evaluate(rules, "Work", tasks)
+	if len(changes) != 2 {
+		t.Fatalf("changes = %+v, want 2", changes)
+	}
+	if got := changes[0]; got.Task.ID != "due-soon" || got.Task.Priority != 2 || len(got.Actions) != 1 ||
+		got.Actions[0] != (Action{Rule: "escalate", Description: "priority 5 -> 2"}) {
+		t.Errorf("changes[0] = %+v", got)
+	}
+	if got := changes[1]; got.Task.ID != "old" || got.Task.Categories != "home,stale" || len(got.Actions) != 2 {
+		t.Errorf("changes[1] = %+v", got)
+	}
+
+	// Applying the changes and evaluating again changes nothing
+	for _, c := range changes {
+		for i := range tasks {
+			if tasks[i].ID == c.Task.ID {
+				tasks[i] = c.Task
+			}
+		}
+	}
+	if again := Evaluate(rules, "Work", tasks); len(again) != 0 {
+		t.Errorf("second Evaluate = %+v, want no changes", again)
+	}
+}
+
+func TestEvaluateListsAndStatus(t *testing.T) {
+	defer backend.SetStatusWorkflow(backend.StatusWorkflow{})
+	backend.SetStatusWorkflow(backend.StatusWorkflow{
+		Transitions: map[backend.TaskStatus][]backend.TaskStatus{
+			backend.StatusInProgress: {backend.StatusCompleted},
+		},
+	})
+
+	rules := []Rule{{
+		Name:  "start",
+		Lists: []string{"work"},
+		Then:  Actions{SetStatus: backend.StatusInProgress},
+	}, {
+		Name:  "back",
+		Lists: []string{"work"},
+		Then:  Actions{SetStatus: backend.StatusNeedsAction},
+	}}
+	tasks := []backend.Task{{ID: "a", Status: backend.StatusNeedsAction}}
+
+	if changes := Evaluate(rules, "Home", tasks); len(changes) != 0 {
+		t.Errorf("rules for Work changed a Home task: %+v", changes)
+	}
+	changes := Evaluate(rules, "Work", tasks)
+	if len(changes) != 1 || changes[0].Task.Status != backend.StatusInProgress || len(changes[0].Actions) != 1 {
+		t.Fatalf("changes = %+v, want only the allowed TODO -> IN-PROGRESS", changes)
+	}
+	if got := changes[0].Actions[0].Description; got != "status TODO -> IN-PROGRESS" {
+		t.Errorf("description = %q", got)
+	}
+}
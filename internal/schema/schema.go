@@ -0,0 +1,337 @@
+// Package schema applies numbered schema migrations to SQLite databases and
+// records them, with the time they were applied, in a version table.
+package schema
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Migration is one numbered change to a database schema
+type Migration struct {
+	Version int
+	Name    string
+	// Up applies the migration. It must be idempotent: databases created
+	// before the version table existed may already have the change.
+	Up func(db *sql.DB) error
+	// Down reverts the migration; nil when it cannot be reverted
+	Down func(db *sql.DB) error
+}
+
+// State is the state of a migration in a database
+type State struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+	// Unknown is set for applied versions this build has no migration for,
+	// e.g. when the database was migrated by a newer todoat
+	Unknown bool `json:"unknown,omitempty"`
+}
+
+// Migrator applies an ordered list of migrations and records them in Table
+type Migrator struct {
+	Table      string
+	Migrations []Migration
+}
+
+// tableNameRe matches the version table names a Migrator accepts. Table names
+// cannot be query parameters, so they are checked before use.
+var tableNameRe = regexp.MustCompile(`^[a-z_]+$`)
+
+// appliedAtLayout is the format of SQLite's CURRENT_TIMESTAMP (UTC)
+const appliedAtLayout = "2006-01-02 15:04:05"
+
+// Init creates the version table, or adds the name column to a version table
+// created by an older todoat
+func (m *Migrator) Init(db *sql.DB) error {
+	if !tableNameRe.MatchString(m.Table) {
+		return fmt.Errorf("invalid schema version table name: %q", m.Table)
+	}
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS ` + m.Table + ` (
+		version INTEGER PRIMARY KEY,
+		name TEXT NOT NULL DEFAULT '',
+		applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create %s table: %w", m.Table, err)
+	}
+	exists, err := ColumnExists(db, m.Table, "name")
+	if err != nil {
+		return err
+	}
+	if !exists {
+		_, err := db.Exec("ALTER TABLE " + m.Table + " ADD COLUMN name TEXT NOT NULL DEFAULT ''")
+		// Another process may have added it since the check
+		if err != nil && !strings.Contains(err.Error(), "duplicate column name") {
+			return fmt.Errorf("failed to upgrade %s table: %w", m.Table, err)
+		}
+	}
+	return nil
+}
+
+// Version returns the highest applied version, 0 if none
+func (m *Migrator) Version(db *sql.DB) (int, error) {
+	var version int
+	err := db.QueryRow("SELECT COALESCE(MAX(version), 0) FROM " + m.Table).Scan(&version)
+	return version, err
+}
+
+// Latest returns the version of the last migration
+func (m *Migrator) Latest() int {
+	latest := 0
+	for _, mig := range m.Migrations {
+		latest = max(latest, mig.Version)
+	}
+	return latest
+}
+
+// Up applies the pending migrations in version order and returns them. A
+// database migrated by a newer todoat is left as it is.
+func (m *Migrator) Up(db *sql.DB) ([]Migration, error) {
+	current, err := m.start(db)
+	if err != nil {
+		return nil, err
+	}
+	return m.up(db, current, m.Latest())
+}
+
+// To migrates the database up or down to target and returns the migrations
+// applied or reverted, in the order they ran. Reverting stops at the first
+// migration without a Down function.
+func (m *Migrator) To(db *sql.DB, target int) ([]Migration, error) {
+	if target < 0 || target > m.Latest() {
+		return nil, fmt.Errorf("unknown schema version %d (latest is %d)", target, m.Latest())
+	}
+	current, err := m.start(db)
+	if err != nil {
+		return nil, err
+	}
+	if target >= current {
+		return m.up(db, current, target)
+	}
+	if current > m.Latest() {
+		return nil, fmt.Errorf("schema version %d is newer than this todoat (latest is %d)", current, m.Latest())
+	}
+	return m.down(db, current, target)
+}
+
+// Plan returns the migrations To would run for target, or Up for a negative
+// target, in the order they would run, without changing the database. reverted
+// reports whether they would be reverted. A nil db has no migration applied.
+func (m *Migrator) Plan(db *sql.DB, target int) (plan []Migration, reverted bool, err error) {
+	states, err := m.Status(db)
+	if err != nil {
+		return nil, false, err
+	}
+	current := 0
+	for _, state := range states {
+		if state.Applied {
+			current = max(current, state.Version)
+		}
+	}
+	if target < 0 {
+		target = max(current, m.Latest())
+	} else if target > m.Latest() {
+		return nil, false, fmt.Errorf("unknown schema version %d (latest is %d)", target, m.Latest())
+	}
+
+	migrations := m.sorted()
+	if target >= current {
+		for _, mig := range migrations {
+			if mig.Version > current && mig.Version <= target {
+				plan = append(plan, mig)
+			}
+		}
+		return plan, false, nil
+	}
+	if current > m.Latest() {
+		return nil, false, fmt.Errorf("schema version %d is newer than this todoat (latest is %d)", current, m.Latest())
+	}
+	for i := len(migrations) - 1; i >= 0; i-- {
+		mig := migrations[i]
+		if mig.Version > current || mig.Version <= target {
+			continue
+		}
+		if mig.Down == nil {
+			return nil, false, fmt.Errorf("migration %d (%s) cannot be reverted", mig.Version, mig.Name)
+		}
+		plan = append(plan, mig)
+	}
+	return plan, true, nil
+}
+
+// start initializes the version table and returns the current version
+func (m *Migrator) start(db *sql.DB) (int, error) {
+	if err := m.Init(db); err != nil {
+		return 0, err
+	}
+	current, err := m.Version(db)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get schema version: %w", err)
+	}
+	return current, nil
+}
+
+// up applies the migrations after current up to target
+func (m *Migrator) up(db *sql.DB, current, target int) ([]Migration, error) {
+	var ran []Migration
+	for _, mig := range m.sorted() {
+		if mig.Version <= current || mig.Version > target {
+			continue
+		}
+		if err := mig.Up(db); err != nil {
+			return ran, fmt.Errorf("migration %d (%s) failed: %w", mig.Version, mig.Name, err)
+		}
+		_, err := db.Exec("INSERT OR REPLACE INTO "+m.Table+" (version, name, applied_at) VALUES (?, ?, CURRENT_TIMESTAMP)", mig.Version, mig.Name)
+		if err != nil {
+			return ran, fmt.Errorf("failed to record migration %d: %w", mig.Version, err)
+		}
+		ran = append(ran, mig)
+	}
+	return ran, nil
+}
+
+// down reverts the migrations after target up to current, newest first
+func (m *Migrator) down(db *sql.DB, current, target int) ([]Migration, error) {
+	migrations := m.sorted()
+	var ran []Migration
+	for i := len(migrations) - 1; i >= 0; i-- {
+		mig := migrations[i]
+		if mig.Version > current || mig.Version <= target {
+			continue
+		}
+		if mig.Down == nil {
+			return ran, fmt.Errorf("migration %d (%s) cannot be reverted", mig.Version, mig.Name)
+		}
+		if err := mig.Down(db); err != nil {
+			return ran, fmt.Errorf("reverting migration %d (%s) failed: %w", mig.Version, mig.Name, err)
+		}
+		if _, err := db.Exec("DELETE FROM "+m.Table+" WHERE version = ?", mig.Version); err != nil {
+			return ran, fmt.Errorf("failed to record revert of migration %d: %w", mig.Version, err)
+		}
+		ran = append(ran, mig)
+	}
+	return ran, nil
+}
+
+// Status returns the state of every migration, in version order, followed by
+// applied versions unknown to this build. A nil db or one without a version
+// table has no migration applied.
+func (m *Migrator) Status(db *sql.DB) ([]State, error) {
+	applied := make(map[int]State)
+	if db != nil {
+		var count int
+		err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", m.Table).Scan(&count)
+		if err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			if err := m.Init(db); err != nil {
+				return nil, err
+			}
+			if applied, err = m.applied(db); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var states []State
+	for _, mig := range m.sorted() {
+		state := State{Version: mig.Version, Name: mig.Name}
+		if a, ok := applied[mig.Version]; ok {
+			state.Applied = true
+			state.AppliedAt = a.AppliedAt
+			delete(applied, mig.Version)
+		}
+		states = append(states, state)
+	}
+	var unknown []State
+	for _, a := range applied {
+		a.Unknown = true
+		unknown = append(unknown, a)
+	}
+	sort.Slice(unknown, func(i, j int) bool { return unknown[i].Version < unknown[j].Version })
+	return append(states, unknown...), nil
+}
+
+// applied reads the version table
+func (m *Migrator) applied(db *sql.DB) (map[int]State, error) {
+	rows, err := db.Query("SELECT version, name, CAST(applied_at AS TEXT) FROM " + m.Table)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	applied := make(map[int]State)
+	for rows.Next() {
+		var state State
+		var appliedAt sql.NullString
+		if err := rows.Scan(&state.Version, &state.Name, &appliedAt); err != nil {
+			return nil, err
+		}
+		state.Applied = true
+		if t, err := time.Parse(appliedAtLayout, appliedAt.String); err == nil {
+			state.AppliedAt = &t
+		}
+		applied[state.Version] = state
+	}
+	return applied, rows.Err()
+}
+
+// sorted returns the migrations in version order
+func (m *Migrator) sorted() []Migration {
+	migrations := append([]Migration(nil), m.Migrations...)
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations
+}
+
+// ColumnExists reports whether table has column. Callers must pass a trusted
+// table name: PRAGMA statements cannot use query parameters.
+func ColumnExists(db *sql.DB, table, column string) (bool, error) {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var cid int
+		var name, colType string
+		var notNull, pk int
+		var dfltValue interface{}
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dfltValue, &pk); err != nil {
+			return false, err
+		}
+		if name == column {
+			return true, nil
+		}
+	}
+	return false, rows.Err()
+}
+
+// AddColumn adds a column unless the table already has it. definition is the
+// column definition, starting with the column name.
+func AddColumn(db *sql.DB, table, definition string) error {
+	name := strings.Fields(definition)[0]
+	exists, err := ColumnExists(db, table, name)
+	if err != nil || exists {
+		return err
+	}
+	_, err = db.Exec("ALTER TABLE " + table + " ADD COLUMN " + definition)
+	return err
+}
+
+// DropColumn removes a column if the table has it
+func DropColumn(db *sql.DB, table, column string) error {
+	exists, err := ColumnExists(db, table, column)
+	if err != nil || !exists {
+		return err
+	}
+	_, err = db.Exec("ALTER TABLE " + table + " DROP COLUMN " + column)
+	return err
+}
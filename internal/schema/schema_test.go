@@ -0,0 +1,167 @@
+package schema
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func testMigrator() *Migrator {
+	return &Migrator{Table: "test_schema_version", Migrations: []Migration{
+		{
+			Version: 1,
+			Name:    "create_notes",
+			Up: func(db *sql.DB) error {
+				_, err := db.Exec("CREATE TABLE IF NOT EXISTS notes (id INTEGER PRIMARY KEY)")
+				return err
+			},
+			Down: func(db *sql.DB) error {
+				_, err := db.Exec("DROP TABLE notes")
+				return err
+			},
+		},
+		{
+			Version: 2,
+			Name:    "add_note_body",
+			Up: func(db *sql.DB) error {
+				return AddColumn(db, "notes", "body TEXT DEFAULT ''")
+			},
+			Down: func(db *sql.DB) error {
+				return DropColumn(db, "notes", "body")
+			},
+		},
+	}}
+}
+
+func TestMigratorUpAndDown(t *testing.T) {
+	db := openTestDB(t)
+	m := testMigrator()
+
+	ran, err := m.Up(db)
+	if err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	if len(ran) != 2 {
+		t.Fatalf("Up ran %d migrations, want 2", len(ran))
+	}
+	if ran, err := m.Up(db); err != nil || len(ran) != 0 {
+		t.Fatalf("second Up = %d migrations, %v; want none", len(ran), err)
+	}
+	if exists, _ := ColumnExists(db, "notes", "body"); !exists {
+		t.Error("notes.body missing after Up")
+	}
+
+	states, err := m.Status(db)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	for _, state := range states {
+		if !state.Applied || state.AppliedAt == nil {
+			t.Errorf("migration %d (%s) should be applied with a timestamp: %+v", state.Version, state.Name, state)
+		}
+	}
+
+	ran, err = m.To(db, 1)
+	if err != nil || len(ran) != 1 || ran[0].Version != 2 {
+		t.Fatalf("To(1) = %+v, %v; want migration 2 reverted", ran, err)
+	}
+	if exists, _ := ColumnExists(db, "notes", "body"); exists {
+		t.Error("notes.body still there after reverting migration 2")
+	}
+	if version, _ := m.Version(db); version != 1 {
+		t.Errorf("version after To(1) = %d, want 1", version)
+	}
+
+	if _, err := m.To(db, 3); err == nil || !strings.Contains(err.Error(), "unknown schema version 3") {
+		t.Errorf("To(3) error = %v, want unknown schema version", err)
+	}
+}
+
+func TestMigratorPlan(t *testing.T) {
+	db := openTestDB(t)
+	m := testMigrator()
+
+	plan, reverted, err := m.Plan(nil, -1)
+	if err != nil || reverted || len(plan) != 2 {
+		t.Fatalf("Plan(nil) = %+v, %v, %v; want both migrations applied", plan, reverted, err)
+	}
+	if _, err := m.To(db, 1); err != nil {
+		t.Fatalf("To(1): %v", err)
+	}
+	plan, reverted, err = m.Plan(db, -1)
+	if err != nil || reverted || len(plan) != 1 || plan[0].Version != 2 {
+		t.Errorf("Plan(-1) = %+v, %v, %v; want migration 2 applied", plan, reverted, err)
+	}
+	plan, reverted, err = m.Plan(db, 0)
+	if err != nil || !reverted || len(plan) != 1 || plan[0].Version != 1 {
+		t.Errorf("Plan(0) = %+v, %v, %v; want migration 1 reverted", plan, reverted, err)
+	}
+	if version, _ := m.Version(db); version != 1 {
+		t.Errorf("version after planning = %d, want 1 unchanged", version)
+	}
+	if _, _, err := m.Plan(db, 3); err == nil {
+		t.Error("Plan(3) succeeded, want unknown schema version")
+	}
+}
+
+func TestMigratorIrreversibleMigration(t *testing.T) {
+	db := openTestDB(t)
+	m := testMigrator()
+	m.Migrations[0].Down = nil
+	if _, err := m.Up(db); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	ran, err := m.To(db, 0)
+	if err == nil || !strings.Contains(err.Error(), "migration 1 (create_notes) cannot be reverted") {
+		t.Fatalf("To(0) error = %v, want cannot be reverted", err)
+	}
+	if len(ran) != 1 {
+		t.Errorf("migration 2 should have been reverted before stopping, ran %d", len(ran))
+	}
+}
+
+func TestMigratorStatusOfLegacyAndNewerDatabases(t *testing.T) {
+	db := openTestDB(t)
+	m := testMigrator()
+
+	states, err := m.Status(db)
+	if err != nil {
+		t.Fatalf("Status of empty database: %v", err)
+	}
+	if len(states) != 2 || states[0].Applied || states[1].Applied {
+		t.Fatalf("empty database should have both migrations pending: %+v", states)
+	}
+
+	// A version table written before names were recorded, by a newer build
+	_, err = db.Exec(`CREATE TABLE test_schema_version (version INTEGER PRIMARY KEY, applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP);
+		INSERT INTO test_schema_version (version) VALUES (1), (2), (3)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	states, err = m.Status(db)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if len(states) != 3 || states[1].Name != "add_note_body" || !states[2].Unknown {
+		t.Errorf("unexpected states: %+v", states)
+	}
+	if ran, err := m.Up(db); err != nil || len(ran) != 0 {
+		t.Errorf("Up on a newer database = %d migrations, %v; want none", len(ran), err)
+	}
+	if _, err := m.To(db, 1); err == nil || !strings.Contains(err.Error(), "newer than this todoat") {
+		t.Errorf("To(1) on a newer database error = %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package shell
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// fileHistory is a bounded term.History that appends each entry to a file,
+// so history survives between shell sessions
+type fileHistory struct {
+	path    string
+	size    int
+	entries []string // oldest first
+}
+
+// newFileHistory loads the most recent size entries from path (if any)
+func newFileHistory(path string, size int) *fileHistory {
+	h := &fileHistory{path: path, size: size}
+	if path == "" {
+		return h
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return h
+	}
+	defer func() { _ = f.Close() }()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			h.entries = append(h.entries, line)
+		}
+	}
+	if len(h.entries) > size {
+		h.entries = h.entries[len(h.entries)-size:]
+	}
+	return h
+}
+
+// Add records an entry, skipping blanks and immediate repeats
+func (h *fileHistory) Add(entry string) {
+	entry = strings.TrimSpace(entry)
+	if entry == "" || (len(h.entries) > 0 && h.entries[len(h.entries)-1] == entry) {
+		return
+	}
+	h.entries = append(h.entries, entry)
+	if len(h.entries) > h.size {
+		h.entries = h.entries[len(h.entries)-h.size:]
+	}
+	h.save(entry)
+}
+
+// Len returns the number of entries
+func (h *fileHistory) Len() int {
+	return len(h.entries)
+}
+
+// At returns an entry; index 0 is the most recent
+func (h *fileHistory) At(idx int) string {
+	return h.entries[len(h.entries)-1-idx]
+}
+
+// save appends an entry to the history file; history is best effort, so errors are ignored.
+// The file is rewritten with only the kept entries once it grows to twice the limit.
+func (h *fileHistory) save(entry string) {
+	if h.path == "" {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(h.path), 0700); err != nil {
+		return
+	}
+	if info, err := os.Stat(h.path); err == nil && info.Size() > int64(h.size)*2*80 {
+		_ = os.WriteFile(h.path, []byte(strings.Join(h.entries, "\n")+"\n"), 0600)
+		return
+	}
+	f, err := os.OpenFile(h.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return
+	}
+	defer func() { _ = f.Close() }()
+	_, _ = f.WriteString(entry + "\n")
+}
@@ -0,0 +1,277 @@
+// Package shell provides an interactive command loop (REPL) with line editing,
+// persistent history and tab completion.
+package shell
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+// DefaultHistorySize is the number of history entries kept when HistorySize is not set
+const DefaultHistorySize = 1000
+
+// Executor runs one command line, already split into arguments
+type Executor func(args []string) error
+
+// Completer returns completion candidates for word, the argument being typed,
+// given the complete arguments before it. Candidates not starting with word are ignored.
+type Completer func(args []string, word string) []string
+
+// Shell is a read-eval loop over command lines
+type Shell struct {
+	Prompt      string
+	Banner      string    // printed once when running on a terminal
+	Exec        Executor  // runs each command line
+	Complete    Completer // tab completion (optional)
+	HistoryPath string    // file used to keep history across sessions ("" keeps it in memory)
+	HistorySize int       // maximum number of history entries (default: DefaultHistorySize)
+}
+
+// Run reads and executes command lines from in until EOF, "exit" or "quit".
+// When in is a terminal, lines are edited with history and tab completion;
+// otherwise (a pipe or script) lines are read as-is without a prompt.
+func (s *Shell) Run(in io.Reader, out io.Writer) error {
+	if f, ok := in.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		return s.runTerminal(f, out)
+	}
+
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		if done := s.runLine(scanner.Text(), out); done {
+			return nil
+		}
+	}
+	return scanner.Err()
+}
+
+// runTerminal runs the loop with line editing. The terminal is in raw mode only
+// while a line is read, so commands print and prompt as they do outside the shell.
+func (s *Shell) runTerminal(f *os.File, out io.Writer) error {
+	history := newFileHistory(s.HistoryPath, s.historySize())
+	t := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{f, out}, s.Prompt)
+	t.History = history
+	if s.Complete != nil {
+		t.AutoCompleteCallback = func(line string, pos int, key rune) (string, int, bool) {
+			if key != '\t' {
+				return "", 0, false
+			}
+			newLine, newPos, candidates := CompleteLine(line, pos, s.Complete)
+			// Several candidates and nothing to add: list them and redraw the prompt
+			if len(candidates) > 1 && newLine == line && pos == len(line) {
+				_, _ = fmt.Fprintf(out, "\r\n%s\r\n%s%s", strings.Join(candidates, "  "), s.Prompt, line)
+			}
+			return newLine, newPos, true
+		}
+	}
+
+	if s.Banner != "" {
+		_, _ = fmt.Fprintln(out, s.Banner)
+	}
+	for {
+		state, err := term.MakeRaw(int(f.Fd()))
+		if err != nil {
+			return err
+		}
+		line, err := t.ReadLine()
+		_ = term.Restore(int(f.Fd()), state)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				_, _ = fmt.Fprintln(out)
+				return nil
+			}
+			return err
+		}
+		if done := s.runLine(line, out); done {
+			return nil
+		}
+	}
+}
+
+// runLine executes one input line and reports whether the loop should stop
+func (s *Shell) runLine(line string, out io.Writer) bool {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return false
+	}
+	if line == "exit" || line == "quit" {
+		return true
+	}
+	args, err := Split(line)
+	if err != nil {
+		_, _ = fmt.Fprintf(out, "Error: %v\n", err)
+		return false
+	}
+	if err := s.Exec(args); err != nil {
+		_, _ = fmt.Fprintf(out, "Error: %v\n", err)
+	}
+	return false
+}
+
+func (s *Shell) historySize() int {
+	if s.HistorySize > 0 {
+		return s.HistorySize
+	}
+	return DefaultHistorySize
+}
+
+// Split splits a command line into arguments like a POSIX shell does for words:
+// whitespace separates arguments, single quotes keep text literally, and double
+// quotes keep whitespace while allowing backslash escapes.
+func Split(line string) ([]string, error) {
+	args, _, quote := splitWords(line)
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	return args, nil
+}
+
+// splitWords splits line into words, reporting whether the last word was still
+// open (not followed by whitespace) and the quote left unterminated, if any
+func splitWords(line string) (words []string, open bool, quote rune) {
+	var word strings.Builder
+	inWord := false
+	escaped := false
+	for _, r := range line {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inWord = true
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, inWord, quote
+}
+
+// CompleteLine completes the word before pos. A single candidate replaces the word
+// (followed by a space); several candidates extend it to their longest common prefix.
+// It returns the new line and cursor position, and the matching candidates.
+func CompleteLine(line string, pos int, complete Completer) (string, int, []string) {
+	prefix, suffix := line[:pos], line[pos:]
+	words, open, _ := splitWords(prefix)
+	word := ""
+	if open {
+		word = words[len(words)-1]
+		words = words[:len(words)-1]
+	}
+
+	var matches []string
+	seen := make(map[string]bool)
+	for _, c := range complete(words, word) {
+		if !seen[c] && strings.HasPrefix(strings.ToLower(c), strings.ToLower(word)) {
+			seen[c] = true
+			matches = append(matches, c)
+		}
+	}
+	sort.Strings(matches)
+	if len(matches) == 0 {
+		return line, pos, nil
+	}
+
+	// Replace the typed word (including any opening quote) with the completion
+	start := wordStart(prefix, open)
+	var completed string
+	if len(matches) == 1 {
+		completed = quoteWord(matches[0]) + " "
+	} else {
+		common := commonPrefix(matches)
+		if len(common) <= len(word) {
+			return line, pos, matches
+		}
+		completed = quoteWord(common)
+		if strings.ContainsAny(common, " \t") {
+			// Leave the quote open so the rest of the candidate can still be typed
+			completed = strings.TrimSuffix(completed, "'")
+		}
+	}
+	newPrefix := prefix[:start] + completed
+	return newPrefix + suffix, len(newPrefix), matches
+}
+
+// wordStart returns the byte offset where the last word of prefix starts
+func wordStart(prefix string, open bool) int {
+	if !open {
+		return len(prefix)
+	}
+	start := 0
+	var quote rune
+	escaped := false
+	for i, r := range prefix {
+		switch {
+		case escaped:
+			escaped = false
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else if r == '\\' && quote == '"' {
+				escaped = true
+			}
+		case r == '\\':
+			escaped = true
+		case r == '\'' || r == '"':
+			quote = r
+		case r == ' ' || r == '\t':
+			start = i + 1
+		}
+	}
+	return start
+}
+
+// quoteWord single-quotes s when it contains characters the shell would split on
+func quoteWord(s string) string {
+	if s == "" || !strings.ContainsAny(s, " \t'\"\\#") {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// commonPrefix returns the longest prefix shared by all strings
+func commonPrefix(strs []string) string {
+	prefix := strs[0]
+	for _, s := range strs[1:] {
+		for !strings.HasPrefix(s, prefix) {
+			_, size := utf8.DecodeLastRuneInString(prefix)
+			prefix = prefix[:len(prefix)-size]
+		}
+	}
+	return prefix
+}
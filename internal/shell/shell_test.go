@@ -0,0 +1,122 @@
+package shell
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{`Work add "Buy milk"`, []string{"Work", "add", "Buy milk"}},
+		{`Work add 'It''s'`, []string{"Work", "add", "Its"}},
+		{`Work add 'say "hi"'`, []string{"Work", "add", `say "hi"`}},
+		{`Work add Buy\ milk`, []string{"Work", "add", "Buy milk"}},
+		{`Work add "a \"quoted\" word"`, []string{"Work", "add", `a "quoted" word`}},
+		{`  list   create  X  `, []string{"list", "create", "X"}},
+		{`Work add ""`, []string{"Work", "add", ""}},
+	}
+	for _, tt := range tests {
+		got, err := Split(tt.line)
+		if err != nil {
+			t.Errorf("Split(%q) error: %v", tt.line, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Split(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+
+	if _, err := Split(`Work add "Buy milk`); err == nil {
+		t.Error("expected error for unterminated quote")
+	}
+}
+
+func TestCompleteLine(t *testing.T) {
+	complete := func(args []string, word string) []string {
+		if len(args) == 0 {
+			return []string{"Work", "Weekend", "list"}
+		}
+		return []string{"Buy milk", "Buy bread"}
+	}
+	tests := []struct {
+		line     string
+		wantLine string
+	}{
+		{"wo", "Work "},                           // single match, case-insensitive
+		{"We", "Weekend "},                        // single match
+		{"W", "W"},                                // ambiguous, no common extension
+		{"Work c ", "Work c 'Buy "},               // common prefix with a space stays quoted
+		{"Work c 'Buy m", "Work c 'Buy milk' "},   // completes inside an open quote
+		{"Work c Buy\\ b", "Work c 'Buy bread' "}, // escaped space
+		{"x", "x"},                                // no match
+	}
+	for _, tt := range tests {
+		got, pos, _ := CompleteLine(tt.line, len(tt.line), complete)
+		if got != tt.wantLine || pos != len(got) {
+			t.Errorf("CompleteLine(%q) = %q (pos %d), want %q", tt.line, got, pos, tt.wantLine)
+		}
+	}
+
+	_, _, candidates := CompleteLine("W", 1, complete)
+	if !reflect.DeepEqual(candidates, []string{"Weekend", "Work"}) {
+		t.Errorf("expected both W candidates, got %q", candidates)
+	}
+}
+
+func TestRunNonTerminal(t *testing.T) {
+	var ran [][]string
+	sh := &Shell{
+		Prompt: "> ",
+		Exec: func(args []string) error {
+			ran = append(ran, args)
+			if args[0] == "fail" {
+				return errors.New("boom")
+			}
+			return nil
+		},
+	}
+	input := "Work add \"Buy milk\"\n\n# comment\nfail\nWork add 'unterminated\nexit\nnever run\n"
+	var out bytes.Buffer
+	if err := sh.Run(strings.NewReader(input), &out); err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+
+	want := [][]string{{"Work", "add", "Buy milk"}, {"fail"}}
+	if !reflect.DeepEqual(ran, want) {
+		t.Errorf("executed %q, want %q", ran, want)
+	}
+	if !strings.Contains(out.String(), "Error: boom") {
+		t.Errorf("expected command error in output, got %q", out.String())
+	}
+	if !strings.Contains(out.String(), "unterminated ' quote") {
+		t.Errorf("expected quote error in output, got %q", out.String())
+	}
+	if strings.Contains(out.String(), "> ") {
+		t.Errorf("expected no prompt when input is not a terminal, got %q", out.String())
+	}
+}
+
+func TestFileHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+
+	h := newFileHistory(path, 3)
+	for _, entry := range []string{"one", "two", "two", "three", "four"} {
+		h.Add(entry)
+	}
+	if h.Len() != 3 || h.At(0) != "four" || h.At(2) != "two" {
+		t.Errorf("unexpected history: len=%d entries=%q", h.Len(), h.entries)
+	}
+
+	// A new session sees the most recent entries
+	reloaded := newFileHistory(path, 3)
+	if !reflect.DeepEqual(reloaded.entries, []string{"two", "three", "four"}) {
+		t.Errorf("reloaded history = %q, want [two three four]", reloaded.entries)
+	}
+}
@@ -0,0 +1,172 @@
+// Package trello reads Trello boards, either from the JSON file produced by
+// "Menu > Print, export and share > Export as JSON" or from the Trello REST API.
+package trello
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the Trello REST API base URL
+const DefaultBaseURL = "https://api.trello.com/1"
+
+// Board is a Trello board with its lists, cards and checklists
+type Board struct {
+	ID         string      `json:"id"`
+	Name       string      `json:"name"`
+	Lists      []List      `json:"lists"`
+	Cards      []Card      `json:"cards"`
+	Checklists []Checklist `json:"checklists"`
+}
+
+// List is a column of a board
+type List struct {
+	ID     string  `json:"id"`
+	Name   string  `json:"name"`
+	Closed bool    `json:"closed"`
+	Pos    float64 `json:"pos"`
+}
+
+// Card is a card in a list
+type Card struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Desc        string     `json:"desc"`
+	IDList      string     `json:"idList"`
+	Closed      bool       `json:"closed"`
+	Due         *time.Time `json:"due"`
+	DueComplete bool       `json:"dueComplete"`
+	Start       *time.Time `json:"start"`
+	Labels      []Label    `json:"labels"`
+	Pos         float64    `json:"pos"`
+}
+
+// Label is a colored label attached to a card
+type Label struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// Title returns the label name, or its color for unnamed labels
+func (l Label) Title() string {
+	if l.Name != "" {
+		return l.Name
+	}
+	return l.Color
+}
+
+// Checklist is a named checklist on a card
+type Checklist struct {
+	ID         string      `json:"id"`
+	Name       string      `json:"name"`
+	IDCard     string      `json:"idCard"`
+	Pos        float64     `json:"pos"`
+	CheckItems []CheckItem `json:"checkItems"`
+}
+
+// CheckItem is one item of a checklist
+type CheckItem struct {
+	ID    string     `json:"id"`
+	Name  string     `json:"name"`
+	State string     `json:"state"` // "complete" or "incomplete"
+	Due   *time.Time `json:"due"`
+	Pos   float64    `json:"pos"`
+}
+
+// Complete reports whether the item is checked
+func (i CheckItem) Complete() bool {
+	return i.State == "complete"
+}
+
+// ParseBoard decodes a board JSON export and sorts its lists, cards,
+// checklists and items in board order
+func ParseBoard(data []byte) (*Board, error) {
+	var board Board
+	if err := json.Unmarshal(data, &board); err != nil {
+		return nil, fmt.Errorf("invalid Trello board JSON: %w", err)
+	}
+	if board.ID == "" || board.Name == "" {
+		return nil, fmt.Errorf("invalid Trello board JSON: missing board id or name")
+	}
+
+	sort.SliceStable(board.Lists, func(i, j int) bool { return board.Lists[i].Pos < board.Lists[j].Pos })
+	sort.SliceStable(board.Cards, func(i, j int) bool { return board.Cards[i].Pos < board.Cards[j].Pos })
+	sort.SliceStable(board.Checklists, func(i, j int) bool { return board.Checklists[i].Pos < board.Checklists[j].Pos })
+	for _, checklist := range board.Checklists {
+		items := checklist.CheckItems
+		sort.SliceStable(items, func(i, j int) bool { return items[i].Pos < items[j].Pos })
+	}
+	return &board, nil
+}
+
+// LoadBoard reads a board JSON export from a file
+func LoadBoard(path string) (*Board, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseBoard(data)
+}
+
+// Client fetches boards from the Trello REST API
+type Client struct {
+	Key     string
+	Token   string
+	BaseURL string // Override for testing
+	HTTP    *http.Client
+}
+
+// FetchBoard downloads a board with all of its lists, cards and checklists.
+// boardID may be the board ID or the short ID from its URL.
+func (c *Client) FetchBoard(ctx context.Context, boardID string) (*Board, error) {
+	if c.Key == "" || c.Token == "" {
+		return nil, fmt.Errorf("trello API key and token are required")
+	}
+	baseURL := c.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	client := c.HTTP
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	query := url.Values{}
+	query.Set("key", c.Key)
+	query.Set("token", c.Token)
+	query.Set("fields", "id,name")
+	query.Set("lists", "all")
+	query.Set("cards", "all")
+	query.Set("checklists", "all")
+	endpoint := fmt.Sprintf("%s/boards/%s?%s", strings.TrimSuffix(baseURL, "/"), url.PathEscape(boardID), query.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch Trello board: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch Trello board: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return ParseBoard(body)
+}
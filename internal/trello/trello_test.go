@@ -0,0 +1,77 @@
+package trello
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const boardJSON = `{
+  "id": "b1",
+  "name": "Launch",
+  "lists": [
+    {"id": "l2", "name": "Doing", "pos": 200},
+    {"id": "l1", "name": "To Do", "pos": 100}
+  ],
+  "cards": [
+    {"id": "c2", "name": "Second", "idList": "l1", "pos": 2, "due": null},
+    {"id": "c1", "name": "First", "idList": "l1", "pos": 1, "due": "2026-05-01T12:00:00.000Z", "dueComplete": true,
+     "labels": [{"id": "x", "name": "", "color": "red"}, {"id": "y", "name": "Urgent", "color": "orange"}]}
+  ],
+  "checklists": [
+    {"id": "k1", "name": "Steps", "idCard": "c1", "pos": 1, "checkItems": [
+      {"id": "i2", "name": "Ship", "state": "incomplete", "pos": 2},
+      {"id": "i1", "name": "Build", "state": "complete", "pos": 1}
+    ]}
+  ]
+}`
+
+func TestParseBoardSortsByPosition(t *testing.T) {
+	board, err := ParseBoard([]byte(boardJSON))
+	if err != nil {
+		t.Fatalf("ParseBoard: %v", err)
+	}
+	if board.Lists[0].Name != "To Do" || board.Cards[0].Name != "First" {
+		t.Errorf("expected lists and cards in board order, got %s, %s", board.Lists[0].Name, board.Cards[0].Name)
+	}
+	items := board.Checklists[0].CheckItems
+	if items[0].Name != "Build" || !items[0].Complete() || items[1].Complete() {
+		t.Errorf("expected checklist items in order with state, got %+v", items)
+	}
+	card := board.Cards[0]
+	if card.Due == nil || card.Due.Day() != 1 || !card.DueComplete {
+		t.Errorf("expected due date and completion, got %+v", card)
+	}
+	if card.Labels[0].Title() != "red" || card.Labels[1].Title() != "Urgent" {
+		t.Errorf("expected label titles, got %q %q", card.Labels[0].Title(), card.Labels[1].Title())
+	}
+
+	if _, err := ParseBoard([]byte(`{"lists": []}`)); err == nil {
+		t.Error("expected error for JSON that is not a board")
+	}
+}
+
+func TestFetchBoard(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/boards/abc" || r.URL.Query().Get("key") != "k" || r.URL.Query().Get("checklists") != "all" {
+			http.Error(w, "unexpected request "+r.URL.String(), http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(boardJSON))
+	}))
+	defer server.Close()
+
+	client := &Client{Key: "k", Token: "t", BaseURL: server.URL}
+	board, err := client.FetchBoard(context.Background(), "abc")
+	if err != nil {
+		t.Fatalf("FetchBoard: %v", err)
+	}
+	if board.Name != "Launch" || len(board.Cards) != 2 {
+		t.Errorf("unexpected board %+v", board)
+	}
+
+	if _, err := (&Client{Key: "k", Token: "t", BaseURL: server.URL}).FetchBoard(context.Background(), "missing"); err == nil {
+		t.Error("expected error for a failed request")
+	}
+}
@@ -0,0 +1,375 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"todoat/backend"
+)
+
+// agendaDays is the number of days shown in the agenda
+const agendaDays = 7
+
+// agendaOverdue is the day index of tasks due before the agenda window
+const agendaOverdue = -1
+
+// agendaRow is a task placed in the agenda: day is its index in the window
+// (0 = first day) or agendaOverdue
+type agendaRow struct {
+	taskIdx int
+	day     int
+}
+
+// startOfDay returns midnight of t's calendar day in t's location
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// openAgenda switches to the agenda of the selected list, starting today.
+// The agenda needs every task of the list, so pages not loaded yet are fetched.
+func (m *Model) openAgenda() tea.Cmd {
+	m.agenda = true
+	m.agendaStart = startOfDay(m.now())
+	m.agendaCursor = 0
+	m.backlogCursor = 0
+	m.focus = FocusAgenda
+	if m.hasMore && len(m.lists) > 0 && m.listCursor < len(m.lists) {
+		m.loadingMore = true
+		return m.fetchTasks(m.lists[m.listCursor].ID, 0, 0)
+	}
+	return nil
+}
+
+// closeAgenda returns to the list and task panes
+func (m *Model) closeAgenda() {
+	m.agenda = false
+	m.agendaFollow = ""
+	m.focus = FocusTasks
+}
+
+// isOpenTask reports whether a task still needs scheduling (not completed or cancelled)
+func isOpenTask(t backend.Task) bool {
+	return t.Status != backend.StatusCompleted && t.Status != backend.StatusCancelled
+}
+
+// backlogRows returns the indices of open tasks without a due date
+func (m *Model) backlogRows() []int {
+	var rows []int
+	for i, t := range m.tasks {
+		if isOpenTask(t) && t.DueDate == nil {
+			rows = append(rows, i)
+		}
+	}
+	return rows
+}
+
+// agendaDay returns the day index of a due date in the agenda window,
+// agendaOverdue before it, and ok=false after it
+func (m *Model) agendaDay(due time.Time) (int, bool) {
+	day := startOfDay(due.In(m.agendaStart.Location()))
+	if day.Before(m.agendaStart) {
+		return agendaOverdue, true
+	}
+	for i := 0; i < agendaDays; i++ {
+		if day.Equal(m.agendaStart.AddDate(0, 0, i)) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// agendaRows returns the open tasks due up to the end of the window: overdue tasks
+// first, then each day's tasks by due time and priority
+func (m *Model) agendaRows() []agendaRow {
+	var rows []agendaRow
+	for i, t := range m.tasks {
+		if !isOpenTask(t) || t.DueDate == nil {
+			continue
+		}
+		if day, ok := m.agendaDay(*t.DueDate); ok {
+			rows = append(rows, agendaRow{taskIdx: i, day: day})
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := m.tasks[rows[i].taskIdx], m.tasks[rows[j].taskIdx]
+		if rows[i].day != rows[j].day {
+			return rows[i].day < rows[j].day
+		}
+		if !a.DueDate.Equal(*b.DueDate) {
+			return a.DueDate.Before(*b.DueDate)
+		}
+		// Priority 1 is highest; 0 (none) sorts last
+		pa, pb := a.Priority, b.Priority
+		if pa == 0 {
+			pa = 10
+		}
+		if pb == 0 {
+			pb = 10
+		}
+		return pa < pb
+	})
+	return rows
+}
+
+// selectedAgendaTask returns the task under the cursor of the focused agenda pane
+func (m *Model) selectedAgendaTask() *backend.Task {
+	if m.focus == FocusBacklog {
+		rows := m.backlogRows()
+		if m.backlogCursor < len(rows) {
+			task := m.tasks[rows[m.backlogCursor]]
+			return &task
+		}
+		return nil
+	}
+	rows := m.agendaRows()
+	if m.agendaCursor < len(rows) {
+		task := m.tasks[rows[m.agendaCursor].taskIdx]
+		return &task
+	}
+	return nil
+}
+
+// reschedule moves a task's due date to day, keeping its time of day.
+// A nil day removes the due date, returning the task to the backlog.
+func (m *Model) reschedule(task *backend.Task, day *time.Time) tea.Cmd {
+	if task == nil {
+		return nil
+	}
+	updated := *task
+	if day == nil {
+		updated.DueDate = nil
+	} else {
+		due := *day
+		if task.DueDate != nil {
+			old := task.DueDate.In(day.Location())
+			due = time.Date(day.Year(), day.Month(), day.Day(), old.Hour(), old.Minute(), old.Second(), 0, day.Location())
+		}
+		updated.DueDate = &due
+	}
+	m.agendaFollow = updated.ID
+	return m.updateTask(&updated)
+}
+
+// followAgendaTask moves the agenda cursor to a task that was just rescheduled
+func (m *Model) followAgendaTask(taskID string) {
+	if !m.agenda || m.agendaFollow != taskID {
+		return
+	}
+	m.agendaFollow = ""
+	defer m.clampAgendaCursors()
+	for i, idx := range m.backlogRows() {
+		if m.tasks[idx].ID == taskID {
+			m.focus = FocusBacklog
+			m.backlogCursor = i
+			return
+		}
+	}
+	for i, row := range m.agendaRows() {
+		if m.tasks[row.taskIdx].ID == taskID {
+			m.focus = FocusAgenda
+			m.agendaCursor = i
+			return
+		}
+	}
+}
+
+// clampAgendaCursors keeps both agenda cursors on existing rows
+func (m *Model) clampAgendaCursors() {
+	if n := len(m.agendaRows()); m.agendaCursor >= n {
+		m.agendaCursor = max(n-1, 0)
+	}
+	if n := len(m.backlogRows()); m.backlogCursor >= n {
+		m.backlogCursor = max(n-1, 0)
+	}
+}
+
+// handleAgendaKey handles keys while the agenda is shown
+func (m *Model) handleAgendaKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	switch key {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+
+	case "?":
+		m.mode = ModeHelp
+		return m, nil
+
+	case "w", "esc":
+		m.closeAgenda()
+		return m, nil
+
+	case "tab":
+		if m.focus == FocusAgenda {
+			m.focus = FocusBacklog
+		} else {
+			m.focus = FocusAgenda
+		}
+		return m, nil
+
+	case "up", "k":
+		if m.focus == FocusBacklog {
+			if m.backlogCursor > 0 {
+				m.backlogCursor--
+			}
+		} else if m.agendaCursor > 0 {
+			m.agendaCursor--
+		}
+		return m, nil
+
+	case "down", "j":
+		if m.focus == FocusBacklog {
+			if m.backlogCursor < len(m.backlogRows())-1 {
+				m.backlogCursor++
+			}
+		} else if m.agendaCursor < len(m.agendaRows())-1 {
+			m.agendaCursor++
+		}
+		return m, nil
+
+	case "[", "]":
+		// Show the previous or next week
+		weeks := 1
+		if key == "[" {
+			weeks = -1
+		}
+		m.agendaStart = m.agendaStart.AddDate(0, 0, weeks*agendaDays)
+		m.clampAgendaCursors()
+		return m, nil
+
+	case "1", "2", "3", "4", "5", "6", "7":
+		// Place the task on a day of the shown week
+		day := m.agendaStart.AddDate(0, 0, int(key[0]-'1'))
+		return m, m.reschedule(m.selectedAgendaTask(), &day)
+
+	case "t":
+		today := startOfDay(m.now())
+		return m, m.reschedule(m.selectedAgendaTask(), &today)
+
+	case ">", "<":
+		// Move the task one day later or earlier; backlog tasks start from today
+		task := m.selectedAgendaTask()
+		if task == nil {
+			return m, nil
+		}
+		day := startOfDay(m.now())
+		if task.DueDate != nil {
+			day = startOfDay(task.DueDate.In(m.agendaStart.Location()))
+			if key == ">" {
+				day = day.AddDate(0, 0, 1)
+			} else {
+				day = day.AddDate(0, 0, -1)
+			}
+		} else if key == "<" {
+			return m, nil
+		}
+		return m, m.reschedule(task, &day)
+
+	case "u":
+		task := m.selectedAgendaTask()
+		if task == nil || task.DueDate == nil {
+			return m, nil
+		}
+		return m, m.reschedule(task, nil)
+	}
+
+	return m, nil
+}
+
+// renderAgenda renders the backlog and agenda panes side by side
+func (m *Model) renderAgenda() string {
+	backlogWidth := m.width / 3
+	agendaWidth := m.width - backlogWidth - 4
+
+	backlogPane := m.paneStyle(FocusBacklog).Width(backlogWidth).Height(m.height - 4).Render(m.renderBacklogPane(backlogWidth - 4))
+	agendaPane := m.paneStyle(FocusAgenda).Width(agendaWidth).Height(m.height - 4).Render(m.renderAgendaPane(agendaWidth - 4))
+	return lipgloss.JoinHorizontal(lipgloss.Top, backlogPane, agendaPane)
+}
+
+// paneStyle highlights the border of the focused agenda pane
+func (m *Model) paneStyle(focus Focus) lipgloss.Style {
+	if m.focus == focus {
+		return m.taskPaneStyle.BorderForeground(lipgloss.Color("62"))
+	}
+	return m.taskPaneStyle
+}
+
+// renderBacklogPane lists the undated tasks
+func (m *Model) renderBacklogPane(width int) string {
+	var b strings.Builder
+	b.WriteString("Backlog\n")
+	b.WriteString(strings.Repeat("─", width))
+	b.WriteString("\n")
+
+	rows := m.backlogRows()
+	if len(rows) == 0 {
+		b.WriteString("No undated tasks\n")
+		return b.String()
+	}
+	for i, idx := range rows {
+		b.WriteString(m.renderAgendaTask(m.tasks[idx], m.focus == FocusBacklog && i == m.backlogCursor) + "\n")
+	}
+	return b.String()
+}
+
+// renderAgendaPane lists the days of the shown week with their tasks
+func (m *Model) renderAgendaPane(width int) string {
+	var b strings.Builder
+	end := m.agendaStart.AddDate(0, 0, agendaDays-1)
+	_, _ = fmt.Fprintf(&b, "Agenda %s - %s\n", m.agendaStart.Format("Jan 2"), end.Format("Jan 2"))
+	b.WriteString(strings.Repeat("─", width))
+	b.WriteString("\n")
+
+	rows := m.agendaRows()
+	today := startOfDay(m.now())
+	next := 0
+	for day := agendaOverdue; day < agendaDays; day++ {
+		if day == agendaOverdue {
+			if next >= len(rows) || rows[next].day != agendaOverdue {
+				continue
+			}
+			b.WriteString(m.helpStyle.Render("Overdue") + "\n")
+		} else {
+			date := m.agendaStart.AddDate(0, 0, day)
+			header := fmt.Sprintf("%d %s", day+1, date.Format("Mon Jan 2"))
+			if date.Equal(today) {
+				header += " (today)"
+			}
+			b.WriteString(m.selectedStyle.Render(header) + "\n")
+		}
+		empty := true
+		for ; next < len(rows) && rows[next].day == day; next++ {
+			selected := m.focus == FocusAgenda && next == m.agendaCursor
+			b.WriteString(m.renderAgendaTask(m.tasks[rows[next].taskIdx], selected) + "\n")
+			empty = false
+		}
+		if empty {
+			b.WriteString(m.helpStyle.Render("    -") + "\n")
+		}
+	}
+	return b.String()
+}
+
+// renderAgendaTask renders one task line of the agenda or backlog
+func (m *Model) renderAgendaTask(task backend.Task, selected bool) string {
+	cursor := " "
+	summary := task.Summary
+	if task.DueDate != nil {
+		if due := task.DueDate.In(m.agendaStart.Location()); due.Hour() != 0 || due.Minute() != 0 {
+			summary = due.Format("15:04") + " " + summary
+		}
+	}
+	if selected {
+		cursor = ">"
+		summary = m.selectedStyle.Render(summary)
+	}
+	status := "[ ]"
+	if task.Status == backend.StatusInProgress {
+		status = "[~]"
+	}
+	return cursor + " " + status + " " + summary
+}
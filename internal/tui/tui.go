@@ -3,13 +3,17 @@ package tui
 
 import (
 	"context"
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
 	"todoat/backend"
+	"todoat/internal/focus"
+	"todoat/internal/tz"
 )
 
 // Backend interface for task operations (subset of backend.TaskManager)
@@ -22,12 +26,22 @@ type Backend interface {
 	DeleteTask(ctx context.Context, listID, taskID string) error
 }
 
+// DefaultPageSize is the number of tasks loaded at a time from backends that
+// support paging (backend.TaskPager)
+const DefaultPageSize = 100
+
+// loadAheadRows is how close the cursor gets to the last loaded task before the
+// next page is requested
+const loadAheadRows = 5
+
 // Focus indicates which pane has focus
 type Focus int
 
 const (
 	FocusLists Focus = iota
 	FocusTasks
+	FocusBacklog // agenda: undated tasks
+	FocusAgenda  // agenda: tasks by day
 )
 
 // Mode indicates the current input mode
@@ -62,6 +76,32 @@ type Model struct {
 	textInput textinput.Model
 	filter    string
 
+	// addParent is the task new tasks are created under while in add mode
+	// (set by the "add child" keybinding, nil for root-level tasks)
+	addParent *backend.Task
+
+	// Paging: tasks of the selected list are loaded a page at a time when the
+	// backend supports it, and the next page is requested as the cursor nears the end
+	pageSize    int
+	loaded      int  // tasks fetched from the backend so far (the next page offset)
+	hasMore     bool // the last page was full, so more tasks may follow
+	loadingMore bool
+	taskScroll  int // first visible row of the task pane
+
+	// Agenda: the selected list's open tasks by due date over a week, next to
+	// a backlog of undated tasks that can be scheduled onto days
+	agenda        bool
+	agendaStart   time.Time // first day shown
+	agendaCursor  int
+	backlogCursor int
+	agendaFollow  string // ID of a task being rescheduled, followed by the cursor
+	now           func() time.Time
+
+	// Focus session started with 'todoat focus', read from focusPath every
+	// second and shown as a countdown in the status bar
+	focusPath    string
+	focusSession *focus.Session
+
 	// UI dimensions
 	width  int
 	height int
@@ -83,7 +123,10 @@ type listsLoadedMsg struct {
 }
 
 type tasksLoadedMsg struct {
-	tasks []backend.Task
+	listID string
+	offset int
+	tasks  []backend.Task
+	more   bool
 }
 
 type taskCreatedMsg struct {
@@ -98,10 +141,20 @@ type taskDeletedMsg struct {
 	taskID string
 }
 
+type tasksReorderedMsg struct {
+	listID string
+	taskID string // the task that was moved or pinned
+	tasks  []backend.Task
+}
+
 type errMsg struct {
 	err error
 }
 
+type focusTickMsg struct {
+	session *focus.Session
+}
+
 // New creates a new TUI model
 func New(b Backend) *Model {
 	ti := textinput.New()
@@ -112,6 +165,8 @@ func New(b Backend) *Model {
 		backend:   b,
 		ctx:       context.Background(),
 		textInput: ti,
+		pageSize:  DefaultPageSize,
+		now:       tz.Now,
 		focus:     FocusLists,
 		mode:      ModeNormal,
 		listPaneStyle: lipgloss.NewStyle().
@@ -143,11 +198,34 @@ func New(b Backend) *Model {
 	}
 }
 
+// SetPageSize sets how many tasks are loaded per page; 0 loads whole lists at once
+func (m *Model) SetPageSize(n int) {
+	if n < 0 {
+		n = 0
+	}
+	m.pageSize = n
+}
+
+// SetFocusSessionPath sets the file of the running focus session to show in
+// the status bar; empty disables it
+func (m *Model) SetFocusSessionPath(path string) {
+	m.focusPath = path
+}
+
 // Init initializes the TUI
 func (m *Model) Init() tea.Cmd {
+	if m.focusPath != "" {
+		return tea.Batch(m.loadLists(), m.readFocusSession)
+	}
 	return m.loadLists()
 }
 
+// readFocusSession reloads the focus session file
+func (m *Model) readFocusSession() tea.Msg {
+	session, _ := focus.Load(m.focusPath)
+	return focusTickMsg{session: session}
+}
+
 func (m *Model) loadLists() tea.Cmd {
 	return func() tea.Msg {
 		lists, err := m.backend.GetLists(m.ctx)
@@ -162,26 +240,56 @@ func (m *Model) loadTasks() tea.Cmd {
 	if len(m.lists) == 0 || m.listCursor >= len(m.lists) {
 		return nil
 	}
-	listID := m.lists[m.listCursor].ID
+	m.loaded = 0
+	m.hasMore = false
+	m.loadingMore = false
+	m.taskScroll = 0
+	return m.fetchTasks(m.lists[m.listCursor].ID, 0, m.pageSize)
+}
+
+// loadMoreTasks requests the next page once the cursor is close to the last loaded task
+func (m *Model) loadMoreTasks() tea.Cmd {
+	if !m.hasMore || m.loadingMore || m.taskCursor < len(m.filteredIdx)-1-loadAheadRows {
+		return nil
+	}
+	if len(m.lists) == 0 || m.listCursor >= len(m.lists) {
+		return nil
+	}
+	m.loadingMore = true
+	return m.fetchTasks(m.lists[m.listCursor].ID, m.loaded, m.pageSize)
+}
+
+// fetchTasks loads one page of tasks, or the whole list when pageSize is 0 or
+// paging is unavailable
+func (m *Model) fetchTasks(listID string, offset, pageSize int) tea.Cmd {
+	pager, paged := m.backend.(backend.TaskPager)
 	return func() tea.Msg {
-		tasks, err := m.backend.GetTasks(m.ctx, listID)
+		if !paged || pageSize == 0 {
+			tasks, err := m.backend.GetTasks(m.ctx, listID)
+			if err != nil {
+				return errMsg{err}
+			}
+			return tasksLoadedMsg{listID: listID, tasks: tasks}
+		}
+		tasks, err := pager.GetTasksPage(m.ctx, listID, offset, pageSize)
 		if err != nil {
 			return errMsg{err}
 		}
-		return tasksLoadedMsg{tasks}
+		return tasksLoadedMsg{listID: listID, offset: offset, tasks: tasks, more: len(tasks) == pageSize}
 	}
 }
 
-func (m *Model) createTask(summary string) tea.Cmd {
+func (m *Model) createTask(summary, parentID string) tea.Cmd {
 	if len(m.lists) == 0 || m.listCursor >= len(m.lists) {
 		return nil
 	}
 	listID := m.lists[m.listCursor].ID
 	return func() tea.Msg {
 		task := &backend.Task{
-			Summary: summary,
-			Status:  backend.StatusNeedsAction,
-			ListID:  listID,
+			Summary:  summary,
+			Status:   backend.StatusNeedsAction,
+			ListID:   listID,
+			ParentID: parentID,
 		}
 		created, err := m.backend.CreateTask(m.ctx, listID, task)
 		if err != nil {
@@ -191,6 +299,37 @@ func (m *Model) createTask(summary string) tea.Cmd {
 	}
 }
 
+// canReorder reports whether task can be moved or pinned: the backend must
+// store the manual order of tasks, and the list shown must be the task's own
+// list rather than a virtual list gathering tasks from several lists
+func (m *Model) canReorder(task backend.Task) bool {
+	storer, ok := m.backend.(backend.TaskOrderStorer)
+	if !ok || !storer.StoresTaskOrder() || m.listCursor >= len(m.lists) {
+		return false
+	}
+	return task.ListID == "" || task.ListID == m.lists[m.listCursor].ID
+}
+
+// reorderTask stores the siblings whose position or pin changed when taskID was
+// moved or pinned
+func (m *Model) reorderTask(taskID string, changed []backend.Task, err error) tea.Cmd {
+	if err != nil || len(changed) == 0 || len(m.lists) == 0 || m.listCursor >= len(m.lists) {
+		return nil
+	}
+	listID := m.lists[m.listCursor].ID
+	return func() tea.Msg {
+		updated := make([]backend.Task, 0, len(changed))
+		for i := range changed {
+			task, err := m.backend.UpdateTask(m.ctx, listID, &changed[i])
+			if err != nil {
+				return errMsg{err}
+			}
+			updated = append(updated, *task)
+		}
+		return tasksReorderedMsg{listID: listID, taskID: taskID, tasks: updated}
+	}
+}
+
 func (m *Model) updateTask(task *backend.Task) tea.Cmd {
 	if len(m.lists) == 0 || m.listCursor >= len(m.lists) {
 		return nil
@@ -224,9 +363,31 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tasksLoadedMsg:
-		m.tasks = msg.tasks
+		// Drop pages of a list that is no longer selected
+		if m.listCursor >= len(m.lists) || m.lists[m.listCursor].ID != msg.listID {
+			return m, nil
+		}
+		if msg.offset == 0 {
+			m.tasks = msg.tasks
+		} else {
+			// Tasks added locally since the last page may come back again
+			known := make(map[string]bool, len(m.tasks))
+			for _, t := range m.tasks {
+				known[t.ID] = true
+			}
+			for _, t := range msg.tasks {
+				if !known[t.ID] {
+					m.tasks = append(m.tasks, t)
+				}
+			}
+		}
+		m.loaded = msg.offset + len(msg.tasks)
+		m.hasMore = msg.more
+		m.loadingMore = false
+		backend.SortByPosition(m.tasks)
 		m.applyFilter()
-		return m, nil
+		// Keep loading while the loaded tasks do not reach past the cursor (e.g. with a filter)
+		return m, m.loadMoreTasks()
 
 	case taskCreatedMsg:
 		m.tasks = append(m.tasks, *msg.task)
@@ -242,12 +403,39 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 		m.applyFilter()
+		m.followAgendaTask(msg.task.ID)
+		return m, nil
+
+	case tasksReorderedMsg:
+		if m.listCursor >= len(m.lists) || m.lists[m.listCursor].ID != msg.listID {
+			return m, nil
+		}
+		byID := make(map[string]backend.Task, len(msg.tasks))
+		for _, t := range msg.tasks {
+			byID[t.ID] = t
+		}
+		for i, t := range m.tasks {
+			if updated, ok := byID[t.ID]; ok {
+				m.tasks[i] = updated
+			}
+		}
+		backend.SortByPosition(m.tasks)
+		m.applyFilter()
+		// Keep the cursor on the moved task
+		for fi, idx := range m.filteredIdx {
+			if m.tasks[idx].ID == msg.taskID {
+				m.taskCursor = fi
+			}
+		}
 		return m, nil
 
 	case taskDeletedMsg:
 		for i, t := range m.tasks {
 			if t.ID == msg.taskID {
 				m.tasks = append(m.tasks[:i], m.tasks[i+1:]...)
+				if m.loaded > 0 {
+					m.loaded--
+				}
 				break
 			}
 		}
@@ -255,12 +443,17 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.taskCursor >= len(m.filteredIdx) && m.taskCursor > 0 {
 			m.taskCursor--
 		}
+		m.clampAgendaCursors()
 		return m, nil
 
 	case errMsg:
 		// For now just ignore errors
 		return m, nil
 
+	case focusTickMsg:
+		m.focusSession = msg.session
+		return m, tea.Tick(time.Second, func(time.Time) tea.Msg { return m.readFocusSession() })
+
 	case tea.KeyMsg:
 		// Handle mode-specific input
 		switch m.mode {
@@ -276,6 +469,10 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.handleConfirmDeleteMode(msg)
 		}
 
+		if m.agenda {
+			return m.handleAgendaKey(msg)
+		}
+
 		// Normal mode key handling
 		switch msg.String() {
 		case "q", "ctrl+c":
@@ -312,16 +509,32 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if m.taskCursor < len(m.filteredIdx)-1 {
 					m.taskCursor++
 				}
+				return m, m.loadMoreTasks()
 			}
 			return m, nil
 
 		case "a":
 			m.mode = ModeAdd
+			m.addParent = nil
 			m.textInput.Reset()
 			m.textInput.Placeholder = "New task name..."
 			m.textInput.Focus()
 			return m, textinput.Blink
 
+		case "A":
+			// Add a child of the selected task, referencing the parent by ID
+			// so duplicated parent names are never ambiguous
+			if len(m.filteredIdx) > 0 && m.taskCursor < len(m.filteredIdx) {
+				parent := m.tasks[m.filteredIdx[m.taskCursor]]
+				m.mode = ModeAdd
+				m.addParent = &parent
+				m.textInput.Reset()
+				m.textInput.Placeholder = "New subtask name..."
+				m.textInput.Focus()
+				return m, textinput.Blink
+			}
+			return m, nil
+
 		case "e":
 			if len(m.filteredIdx) > 0 && m.taskCursor < len(m.filteredIdx) {
 				taskIdx := m.filteredIdx[m.taskCursor]
@@ -337,11 +550,27 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if len(m.filteredIdx) > 0 && m.taskCursor < len(m.filteredIdx) {
 				taskIdx := m.filteredIdx[m.taskCursor]
 				task := m.tasks[taskIdx]
+				next := backend.StatusCompleted
 				if task.Status == backend.StatusCompleted {
-					task.Status = backend.StatusNeedsAction
-				} else {
-					task.Status = backend.StatusCompleted
+					next = backend.StatusNeedsAction
+				}
+				if backend.CheckStatusTransition(task.Status, next) != nil {
+					return m, nil
 				}
+				task.Status = next
+				return m, m.updateTask(&task)
+			}
+			return m, nil
+
+		case "s":
+			if len(m.filteredIdx) > 0 && m.taskCursor < len(m.filteredIdx) {
+				taskIdx := m.filteredIdx[m.taskCursor]
+				task := m.tasks[taskIdx]
+				next := backend.NextOpenStatus(task.Status)
+				if next == task.Status {
+					return m, nil
+				}
+				task.Status = next
 				return m, m.updateTask(&task)
 			}
 			return m, nil
@@ -353,6 +582,34 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
+		case "K", "J", "shift+up", "shift+down":
+			// Move the selected task up or down among its siblings
+			if m.focus == FocusTasks && len(m.filteredIdx) > 0 && m.taskCursor < len(m.filteredIdx) {
+				task := m.tasks[m.filteredIdx[m.taskCursor]]
+				if !m.canReorder(task) {
+					return m, nil
+				}
+				delta := 1
+				if msg.String() == "K" || msg.String() == "shift+up" {
+					delta = -1
+				}
+				changed, err := backend.ShiftTask(m.tasks, task.ID, delta)
+				return m, m.reorderTask(task.ID, changed, err)
+			}
+			return m, nil
+
+		case "p":
+			// Pin or unpin the selected task
+			if len(m.filteredIdx) > 0 && m.taskCursor < len(m.filteredIdx) {
+				task := m.tasks[m.filteredIdx[m.taskCursor]]
+				if !m.canReorder(task) {
+					return m, nil
+				}
+				changed, err := backend.PinTask(m.tasks, task.ID, !task.Pinned)
+				return m, m.reorderTask(task.ID, changed, err)
+			}
+			return m, nil
+
 		case "/":
 			m.mode = ModeFilter
 			m.textInput.Reset()
@@ -363,6 +620,9 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "?":
 			m.mode = ModeHelp
 			return m, nil
+
+		case "w":
+			return m, m.openAgenda()
 		}
 	}
 
@@ -381,14 +641,19 @@ func (m *Model) handleAddMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.Type {
 	case tea.KeyEnter:
 		value := m.textInput.Value()
-		if value != "" {
-			m.mode = ModeNormal
-			return m, m.createTask(value)
+		parentID := ""
+		if m.addParent != nil {
+			parentID = m.addParent.ID
 		}
+		m.addParent = nil
 		m.mode = ModeNormal
+		if value != "" {
+			return m, m.createTask(value, parentID)
+		}
 		return m, nil
 
 	case tea.KeyEsc:
+		m.addParent = nil
 		m.mode = ModeNormal
 		return m, nil
 	}
@@ -430,7 +695,7 @@ func (m *Model) handleFilterMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.filter = m.textInput.Value()
 		m.applyFilter()
 		m.mode = ModeNormal
-		return m, nil
+		return m, m.loadMoreTasks()
 
 	case tea.KeyEsc:
 		m.filter = ""
@@ -534,11 +799,14 @@ func (m *Model) View() string {
 	listPane := m.listPaneStyle.Width(listWidth).Height(m.height - 4).Render(listContent)
 
 	// Render task pane
-	taskContent := m.renderTaskPane(taskWidth - 4)
+	taskContent := m.renderTaskPane(taskWidth-4, m.height-4)
 	taskPane := m.taskPaneStyle.Width(taskWidth).Height(m.height - 4).Render(taskContent)
 
-	// Join panes horizontally
+	// Join panes horizontally (the agenda replaces both panes)
 	mainView := lipgloss.JoinHorizontal(lipgloss.Top, listPane, taskPane)
+	if m.agenda {
+		mainView = m.renderAgenda()
+	}
 
 	// Status bar
 	statusBar := m.renderStatusBar()
@@ -585,14 +853,18 @@ func (m *Model) renderListPane(width int) string {
 	return b.String()
 }
 
-func (m *Model) renderTaskPane(width int) string {
+func (m *Model) renderTaskPane(width, height int) string {
 	var b strings.Builder
 	b.WriteString("Tasks\n")
 	b.WriteString(strings.Repeat("─", width))
 	b.WriteString("\n")
 
 	if len(m.filteredIdx) == 0 {
-		b.WriteString("No tasks\n")
+		if m.loadingMore {
+			b.WriteString("Loading more tasks...\n")
+		} else {
+			b.WriteString("No tasks\n")
+		}
 		return b.String()
 	}
 
@@ -605,6 +877,7 @@ func (m *Model) renderTaskPane(width int) string {
 	// Track which tasks have been rendered (for tree view)
 	rendered := make(map[string]bool)
 
+	var rows taskRows
 	for fi, taskIdx := range m.filteredIdx {
 		task := m.tasks[taskIdx]
 
@@ -614,14 +887,51 @@ func (m *Model) renderTaskPane(width int) string {
 		}
 
 		// Render task with proper indentation
-		m.renderTask(&b, task, fi, 0, taskByID, rendered)
+		m.renderTask(&rows, task, fi, 0, taskByID, rendered)
+	}
+
+	// Only the rows around the cursor fit in the pane (header and footer take 3 lines)
+	visible := height - 3
+	if visible < 1 {
+		visible = 1
+	}
+	if rows.cursor < m.taskScroll {
+		m.taskScroll = rows.cursor
+	}
+	if rows.cursor >= m.taskScroll+visible {
+		m.taskScroll = rows.cursor - visible + 1
+	}
+	if maxScroll := len(rows.lines) - visible; m.taskScroll > maxScroll {
+		m.taskScroll = max(maxScroll, 0)
+	}
+	end := min(m.taskScroll+visible, len(rows.lines))
+	for _, line := range rows.lines[m.taskScroll:end] {
+		b.WriteString(line + "\n")
+	}
+
+	switch {
+	case m.loadingMore:
+		b.WriteString(m.helpStyle.Render("Loading more tasks...") + "\n")
+	case m.hasMore:
+		b.WriteString(m.helpStyle.Render("↓ more tasks") + "\n")
+	case end < len(rows.lines):
+		b.WriteString(m.helpStyle.Render(fmt.Sprintf("↓ %d more", len(rows.lines)-end)) + "\n")
 	}
 
 	return b.String()
 }
 
-func (m *Model) renderTask(b *strings.Builder, task backend.Task, filterIdx, indent int, taskByID map[string]int, rendered map[string]bool) {
+// taskRows collects rendered task lines and the line the cursor is on
+type taskRows struct {
+	lines  []string
+	cursor int
+}
+
+func (m *Model) renderTask(rows *taskRows, task backend.Task, filterIdx, indent int, taskByID map[string]int, rendered map[string]bool) {
 	rendered[task.ID] = true
+	if filterIdx == m.taskCursor {
+		rows.cursor = len(rows.lines)
+	}
 
 	cursor := " "
 	if filterIdx == m.taskCursor && m.focus == FocusTasks {
@@ -634,19 +944,24 @@ func (m *Model) renderTask(b *strings.Builder, task backend.Task, filterIdx, ind
 		indentStr = strings.Repeat("  ", indent-1) + "└─"
 	}
 
-	// Status indicator
+	// Status indicator; custom statuses show their name
 	var status string
-	switch task.Status {
-	case backend.StatusCompleted:
+	switch {
+	case task.Status == backend.StatusCompleted:
 		status = "[✓]"
-	case backend.StatusInProgress:
+	case task.Status == backend.StatusInProgress:
 		status = "[~]"
+	case task.Status.IsCustom():
+		status = "[" + string(task.Status) + "]"
 	default:
 		status = "[ ]"
 	}
 
-	// Summary
+	// Summary, with a marker for pinned tasks
 	summary := task.Summary
+	if task.Pinned {
+		summary = "📌 " + summary
+	}
 	if task.Status == backend.StatusCompleted {
 		summary = m.completedStyle.Render(summary)
 	} else if filterIdx == m.taskCursor && m.focus == FocusTasks {
@@ -656,7 +971,7 @@ func (m *Model) renderTask(b *strings.Builder, task backend.Task, filterIdx, ind
 		summary = m.subtaskStyle.Render(summary)
 	}
 
-	b.WriteString(cursor + " " + indentStr + status + " " + summary + "\n")
+	rows.lines = append(rows.lines, cursor+" "+indentStr+status+" "+summary)
 
 	// Render children (subtasks)
 	for i, t := range m.tasks {
@@ -670,7 +985,7 @@ func (m *Model) renderTask(b *strings.Builder, task backend.Task, filterIdx, ind
 				}
 			}
 			if childFilterIdx >= 0 {
-				m.renderTask(b, t, childFilterIdx, indent+1, taskByID, rendered)
+				m.renderTask(rows, t, childFilterIdx, indent+1, taskByID, rendered)
 			}
 		}
 	}
@@ -683,9 +998,18 @@ func (m *Model) renderStatusBar() string {
 	}
 
 	right := "q:quit  ?:help"
+	if m.agenda {
+		left += " - agenda"
+		right = "1-7:schedule  </>:move  u:unschedule  w:back  " + right
+	}
 	if m.filter != "" {
 		right = "Filter: " + m.filter + "  " + right
 	}
+	if s := m.focusSession; s != nil {
+		if remaining := s.Remaining(m.now()); remaining > 0 {
+			right = "Focus: " + s.Summary + " " + focus.FormatRemaining(remaining) + "  " + right
+		}
+	}
 
 	padding := m.width - len(left) - len(right) - 2
 	if padding < 1 {
@@ -696,8 +1020,12 @@ func (m *Model) renderStatusBar() string {
 }
 
 func (m *Model) renderAddDialog() string {
+	title := "Add New Task"
+	if m.addParent != nil {
+		title = "Add Subtask to: " + m.addParent.Summary
+	}
 	dialog := m.dialogStyle.Render(
-		"Add New Task\n\n" +
+		title + "\n\n" +
 			m.textInput.View() + "\n\n" +
 			m.helpStyle.Render("Enter: confirm  Esc: cancel"),
 	)
@@ -732,22 +1060,39 @@ func (m *Model) renderHelpDialog() string {
 	help := `Help - Key Bindings
 
 Navigation:
-  j/↓    Move down
-  k/↑    Move up
+  j/k    Move down/up (also ↓/↑)
   Tab    Switch focus between lists/tasks
+  ?      Show this help
+  q      Quit
 
 Actions:
   a      Add new task
+  A      Add subtask under selected task
   e      Edit selected task
   c      Toggle task completion
+  s      Cycle status (TODO, IN-PROGRESS, custom statuses)
   d      Delete task (with confirm)
+  J/K    Move task down/up among its siblings
+  p      Pin/unpin task (pinned tasks stay on top)
   /      Search/filter tasks
+  w      Week agenda (? there for its keys)
 
-General:
-  ?      Show this help
+Press any key to close`
+	if m.agenda {
+		help = `Help - Agenda Keys
+
+  j/k    Move down/up
+  Tab    Switch between backlog and days
+  1-7    Schedule task on that day
+  t      Schedule task for today
+  >/<    Move task one day later/earlier
+  u      Unschedule (back to backlog)
+  ]/[    Show next/previous week
+  w/Esc  Close the agenda
   q      Quit
 
 Press any key to close`
+	}
 
 	dialog := m.dialogStyle.Render(help)
 	return m.centerDialog(dialog)
@@ -3,6 +3,7 @@ package tui_test
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"io"
 	"strings"
 	"sync"
@@ -13,6 +14,7 @@ import (
 	"github.com/charmbracelet/x/exp/teatest"
 
 	"todoat/backend"
+	"todoat/internal/focus"
 	"todoat/internal/tui"
 )
 
@@ -151,6 +153,27 @@ func TestTUILaunch(t *testing.T) {
 	}
 }
 
+// TestTUIFocusSessionInStatusBar - a running focus session shows its countdown in the status bar
+func TestTUIFocusSessionInStatusBar(t *testing.T) {
+	path := t.TempDir() + "/test.db.focus"
+	now := time.Now()
+	if err := focus.Save(path, &focus.Session{Summary: "Write report", Start: now, End: now.Add(20 * time.Minute)}); err != nil {
+		t.Fatalf("focus.Save error: %v", err)
+	}
+	model := tui.New(newMockBackend())
+	model.SetFocusSessionPath(path)
+
+	tm := teatest.NewTestModel(t, model, teatest.WithInitialTermSize(120, 24))
+	time.Sleep(100 * time.Millisecond)
+	sendRunesAndWait(tm, []rune{'j'})
+	sendRunesAndWait(tm, []rune{'q'})
+
+	out := readAll(t, tm.FinalOutput(t, teatest.WithFinalTimeout(time.Second)))
+	if !strings.Contains(string(out), "Focus: Write report") {
+		t.Errorf("expected the focus session in the status bar, got:\n%s", out)
+	}
+}
+
 // --- List Navigation Tests ---
 
 // TestTUIListNavigation - Arrow keys navigate between task lists
@@ -378,6 +401,44 @@ func TestTUITreeView(t *testing.T) {
 	}
 }
 
+// TestTUIAddChildTask - Press 'A' to add a subtask under the selected task
+func TestTUIAddChildTask(t *testing.T) {
+	mb := newMockBackend()
+	model := tui.New(mb)
+
+	tm := teatest.NewTestModel(t, model, teatest.WithInitialTermSize(80, 24))
+
+	// Wait for initial render
+	time.Sleep(100 * time.Millisecond)
+
+	// Switch to task pane and press 'A' on the first task
+	sendKeyAndWait(tm, tea.KeyMsg{Type: tea.KeyTab})
+	sendRunesAndWait(tm, []rune{'A'})
+
+	for _, r := range "Child of review" {
+		tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	sendKeyAndWait(tm, tea.KeyMsg{Type: tea.KeyEnter})
+
+	// Quit
+	sendRunesAndWait(tm, []rune{'q'})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(time.Second))
+
+	tasks, _ := mb.GetTasks(context.Background(), "1")
+	var found bool
+	for _, task := range tasks {
+		if task.Summary == "Child of review" {
+			found = true
+			if task.ParentID != "t1" {
+				t.Errorf("expected subtask parent to be t1, got %q", task.ParentID)
+			}
+		}
+	}
+	if !found {
+		t.Error("expected subtask to be created")
+	}
+}
+
 // --- Filter Tests ---
 
 // TestTUIFilterTasks - '/' opens filter/search dialog
@@ -409,6 +470,131 @@ func TestTUIFilterTasks(t *testing.T) {
 	}
 }
 
+// --- Infinite Scroll Tests ---
+
+// pagedMockBackend adds backend.TaskPager to mockBackend and records requested offsets
+type pagedMockBackend struct {
+	*mockBackend
+	offsets []int
+}
+
+func (m *pagedMockBackend) GetTasksPage(ctx context.Context, listID string, offset, limit int) ([]backend.Task, error) {
+	tasks, _ := m.GetTasks(ctx, listID)
+	m.mu.Lock()
+	m.offsets = append(m.offsets, offset)
+	m.mu.Unlock()
+	return backend.PageTasks(tasks, offset, limit), nil
+}
+
+func (m *pagedMockBackend) requestedOffsets() []int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]int(nil), m.offsets...)
+}
+
+// TestTUIInfiniteScroll - Pages of a large list are loaded as the cursor nears the end
+func TestTUIInfiniteScroll(t *testing.T) {
+	mb := &pagedMockBackend{mockBackend: newMockBackend()}
+	var tasks []backend.Task
+	for i := 0; i < 25; i++ {
+		tasks = append(tasks, backend.Task{ID: fmt.Sprintf("p%02d", i), Summary: fmt.Sprintf("Paged task %02d", i), ListID: "1"})
+	}
+	mb.tasks["1"] = tasks
+
+	model := tui.New(mb)
+	model.SetPageSize(10)
+
+	tm := teatest.NewTestModel(t, model, teatest.WithInitialTermSize(80, 24))
+
+	// Wait for initial render
+	time.Sleep(100 * time.Millisecond)
+	if got := fmt.Sprint(mb.requestedOffsets()); got != "[0]" {
+		t.Fatalf("expected only the first page to be loaded, got offsets %s", got)
+	}
+
+	// Moving towards the end of the loaded tasks loads the following pages
+	sendKeyAndWait(tm, tea.KeyMsg{Type: tea.KeyTab})
+	for i := 0; i < 20; i++ {
+		sendRunesAndWait(tm, []rune{'j'})
+	}
+
+	sendRunesAndWait(tm, []rune{'q'})
+
+	out := readAll(t, tm.FinalOutput(t, teatest.WithFinalTimeout(time.Second)))
+	if got := fmt.Sprint(mb.requestedOffsets()); got != "[0 10 20]" {
+		t.Errorf("expected pages at offsets [0 10 20], got %s", got)
+	}
+	if !bytes.Contains(out, []byte("Paged task 20")) {
+		t.Error("expected tasks from later pages to be shown")
+	}
+}
+
+// --- Agenda Tests ---
+
+// dueDateOf returns the due date of a task in the mock backend
+func (m *mockBackend) dueDateOf(listID, taskID string) *time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, t := range m.tasks[listID] {
+		if t.ID == taskID {
+			return t.DueDate
+		}
+	}
+	return nil
+}
+
+// TestTUIAgendaReschedule - 'w' opens the agenda, where backlog tasks are placed on days and moved between them
+func TestTUIAgendaReschedule(t *testing.T) {
+	mb := newMockBackend()
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	due := today.Add(9 * time.Hour)
+	mb.tasks["1"][1].DueDate = &due // "Write tests" is due today at 09:00
+
+	model := tui.New(mb)
+	tm := teatest.NewTestModel(t, model, teatest.WithInitialTermSize(100, 30))
+
+	// Wait for initial render
+	time.Sleep(100 * time.Millisecond)
+
+	// Open the agenda and schedule the undated "Review PR" from the backlog on day 2 (tomorrow)
+	sendRunesAndWait(tm, []rune{'w'})
+	sendKeyAndWait(tm, tea.KeyMsg{Type: tea.KeyTab})
+	sendRunesAndWait(tm, []rune{'2'})
+	if got := mb.dueDateOf("1", "t1"); got == nil || !got.Equal(today.AddDate(0, 0, 1)) {
+		t.Fatalf("expected backlog task scheduled for tomorrow, got %v", got)
+	}
+
+	// The cursor follows the task into the agenda; move it one more day later
+	sendRunesAndWait(tm, []rune{'>'})
+	if got := mb.dueDateOf("1", "t1"); got == nil || !got.Equal(today.AddDate(0, 0, 2)) {
+		t.Fatalf("expected task moved to the day after tomorrow, got %v", got)
+	}
+
+	// Unscheduling returns it to the backlog
+	sendRunesAndWait(tm, []rune{'u'})
+	if got := mb.dueDateOf("1", "t1"); got != nil {
+		t.Fatalf("expected task unscheduled, got %v", got)
+	}
+
+	// Moving a timed task keeps its time of day
+	sendKeyAndWait(tm, tea.KeyMsg{Type: tea.KeyTab})
+	sendRunesAndWait(tm, []rune{'7'})
+	if got := mb.dueDateOf("1", "t2"); got == nil || !got.Equal(due.AddDate(0, 0, 6)) {
+		t.Fatalf("expected timed task moved to day 7 at 09:00, got %v", got)
+	}
+
+	sendRunesAndWait(tm, []rune{'q'})
+
+	out := readAll(t, tm.FinalOutput(t, teatest.WithFinalTimeout(time.Second)))
+	if !bytes.Contains(out, []byte("Backlog")) {
+		t.Error("expected backlog pane to be shown")
+	}
+	if !bytes.Contains(out, []byte("(today)")) {
+		t.Error("expected today to be marked in the agenda")
+	}
+}
+
 // --- Help Tests ---
 
 // TestTUIKeyBindings - Help panel shows all available key bindings ('?')
@@ -0,0 +1,34 @@
+// Package tz holds the timezone dates are entered, shown and stepped in. It
+// defaults to the system zone; the timezone setting replaces it without
+// changing time.Local for the rest of the process.
+package tz
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+var location atomic.Pointer[time.Location]
+
+// Location returns the configured timezone, or the system zone when none is set
+func Location() *time.Location {
+	if loc := location.Load(); loc != nil {
+		return loc
+	}
+	return time.Local
+}
+
+// Set sets the timezone; nil restores the system zone
+func Set(loc *time.Location) {
+	location.Store(loc)
+}
+
+// Now returns the current time in the configured timezone
+func Now() time.Time {
+	return time.Now().In(Location())
+}
+
+// Date returns midnight in the configured timezone on the given calendar date
+func Date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, Location())
+}
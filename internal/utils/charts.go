@@ -0,0 +1,32 @@
+package utils
+
+import "strings"
+
+// sparkLevels are the block characters of a sparkline, from lowest to highest
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders values as a row of block characters scaled to the largest
+// value. Zeros use the lowest block so every value keeps its position.
+func Sparkline(values []int) string {
+	maxValue := 0
+	for _, v := range values {
+		maxValue = max(maxValue, v)
+	}
+	var b strings.Builder
+	for _, v := range values {
+		level := 0
+		if maxValue > 0 && v > 0 {
+			level = (v*(len(sparkLevels)-1) + maxValue - 1) / maxValue
+		}
+		b.WriteRune(sparkLevels[level])
+	}
+	return b.String()
+}
+
+// ProgressBar renders fraction (0 to 1) as a bar of width cells, filled with
+// '█' and padded with '░'
+func ProgressBar(fraction float64, width int) string {
+	fraction = min(max(fraction, 0), 1)
+	filled := int(fraction*float64(width) + 0.5)
+	return strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
+}
@@ -0,0 +1,31 @@
+package utils
+
+import "testing"
+
+// TestSparkline verifies values are scaled to the largest one
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		values []int
+		want   string
+	}{
+		{[]int{0, 1, 2, 4, 8}, "▁▂▃▅█"},
+		{[]int{0, 0, 0}, "▁▁▁"},
+		{[]int{3, 3}, "██"},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		if got := Sparkline(tt.values); got != tt.want {
+			t.Errorf("Sparkline(%v) = %q, want %q", tt.values, got, tt.want)
+		}
+	}
+}
+
+// TestProgressBar verifies the filled share of the bar and clamping
+func TestProgressBar(t *testing.T) {
+	tests := map[float64]string{0: "░░░░", 0.5: "██░░", 1: "████", 1.5: "████", -1: "░░░░"}
+	for fraction, want := range tests {
+		if got := ProgressBar(fraction, 4); got != want {
+			t.Errorf("ProgressBar(%v, 4) = %q, want %q", fraction, got, want)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// colorCodes maps color names to their ANSI foreground codes
+var colorCodes = map[string]int{
+	"black":          30,
+	"red":            31,
+	"green":          32,
+	"yellow":         33,
+	"blue":           34,
+	"magenta":        35,
+	"cyan":           36,
+	"white":          37,
+	"gray":           90,
+	"grey":           90,
+	"bright-red":     91,
+	"bright-green":   92,
+	"bright-yellow":  93,
+	"bright-blue":    94,
+	"bright-magenta": 95,
+	"bright-cyan":    96,
+	"bright-white":   97,
+}
+
+// attributeCodes maps text attributes to their ANSI codes
+var attributeCodes = map[string]int{
+	"bold":      1,
+	"dim":       2,
+	"italic":    3,
+	"underline": 4,
+	"strike":    9,
+}
+
+// ParseStyle converts a style such as "bold red", "dim", "208" or "#ff8800" into
+// ANSI SGR parameters (e.g. "1;31"). Styles are space-separated words: a color
+// name, a 256-color index or a hex color for the foreground, and any number of
+// attributes (bold, dim, italic, underline, strike). An empty style or "none"
+// returns an empty string, meaning no styling.
+func ParseStyle(style string) (string, error) {
+	var params []string
+	for _, word := range strings.Fields(strings.ToLower(style)) {
+		if word == "none" {
+			continue
+		}
+		if code, ok := attributeCodes[word]; ok {
+			params = append(params, strconv.Itoa(code))
+			continue
+		}
+		if code, ok := colorCodes[word]; ok {
+			params = append(params, strconv.Itoa(code))
+			continue
+		}
+		if n, err := strconv.Atoi(word); err == nil && n >= 0 && n <= 255 {
+			params = append(params, "38;5;"+word)
+			continue
+		}
+		if rgb, ok := parseHexColor(word); ok {
+			params = append(params, fmt.Sprintf("38;2;%d;%d;%d", rgb[0], rgb[1], rgb[2]))
+			continue
+		}
+		return "", fmt.Errorf("invalid style %q: unknown color or attribute %q", style, word)
+	}
+	return strings.Join(params, ";"), nil
+}
+
+// parseHexColor parses a #rrggbb color
+func parseHexColor(s string) ([3]uint8, bool) {
+	var rgb [3]uint8
+	if len(s) != 7 || s[0] != '#' {
+		return rgb, false
+	}
+	for i := range rgb {
+		v, err := strconv.ParseUint(s[1+2*i:3+2*i], 16, 8)
+		if err != nil {
+			return rgb, false
+		}
+		rgb[i] = uint8(v)
+	}
+	return rgb, true
+}
+
+// Colorize wraps the text of s in the ANSI sequence for the given SGR parameters.
+// Leading and trailing spaces are left outside the sequence so that padded
+// columns keep their width and no styling bleeds into the gaps.
+func Colorize(s, params string) string {
+	if params == "" {
+		return s
+	}
+	trimmed := strings.TrimRight(s, " ")
+	text := strings.TrimLeft(trimmed, " ")
+	if text == "" {
+		return s
+	}
+	lead := s[:len(trimmed)-len(text)]
+	return lead + "\x1b[" + params + "m" + text + "\x1b[0m" + s[len(trimmed):]
+}
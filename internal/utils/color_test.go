@@ -0,0 +1,42 @@
+package utils
+
+import "testing"
+
+// TestParseStyle verifies style words convert to ANSI SGR parameters
+func TestParseStyle(t *testing.T) {
+	tests := map[string]string{
+		"":                  "",
+		"none":              "",
+		"red":               "31",
+		"Bold Red":          "1;31",
+		"dim":               "2",
+		"208":               "38;5;208",
+		"#ff8800 underline": "38;2;255;136;0;4",
+		"bright-yellow":     "93",
+	}
+	for input, want := range tests {
+		got, err := ParseStyle(input)
+		if err != nil || got != want {
+			t.Errorf("ParseStyle(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+
+	for _, input := range []string{"purple", "256", "#ff88"} {
+		if _, err := ParseStyle(input); err == nil {
+			t.Errorf("ParseStyle(%q) should fail", input)
+		}
+	}
+}
+
+// TestColorize verifies padding stays outside the color sequence
+func TestColorize(t *testing.T) {
+	if got := Colorize("  [P1]  ", "31"); got != "  \x1b[31m[P1]\x1b[0m  " {
+		t.Errorf("Colorize kept padding inside the sequence: %q", got)
+	}
+	if got := Colorize("   ", "31"); got != "   " {
+		t.Errorf("Colorize should leave blank values alone, got %q", got)
+	}
+	if got := Colorize("text", ""); got != "text" {
+		t.Errorf("Colorize without a style should not change the text, got %q", got)
+	}
+}
@@ -0,0 +1,474 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"todoat/internal/dates"
+	"todoat/internal/tz"
+)
+
+// DateLayouts are the named date display formats accepted by dates.format
+var DateLayouts = map[string]string{
+	"short": "Jan 02",
+	"iso":   "2006-01-02",
+	"us":    "01/02/2006",
+	"eu":    "02/01/2006",
+	"long":  "Mon Jan 2, 2006",
+}
+
+// ResolveDateLayout returns the Go time layout for a dates.format value: one of
+// the DateLayouts names or a Go layout such as "02.01.2006". Empty means "short".
+func ResolveDateLayout(format string) (string, error) {
+	if format == "" {
+		return DateLayouts["short"], nil
+	}
+	if layout, ok := DateLayouts[strings.ToLower(format)]; ok {
+		return layout, nil
+	}
+	reference := time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)
+	if reference.Format(format) == format {
+		return "", fmt.Errorf("invalid date format %q (use short, iso, us, eu, long or a Go layout like 02.01.2006)", format)
+	}
+	return format, nil
+}
+
+// Orders of day and month in numeric dates, set with dates.date_order
+const (
+	DateOrderDMY = "DMY"
+	DateOrderMDY = "MDY"
+)
+
+// numericDateLayout is the layout used to parse numeric dates like 03/04/2026.
+// It is empty unless a us or eu date format or a date order is configured,
+// because the order of day and month is ambiguous otherwise.
+var numericDateLayout string
+
+// dateOrderSet reports whether the order of numeric dates was configured with
+// dates.date_order rather than derived from dates.format
+var dateOrderSet bool
+
+// strictDates makes date parsing reject ambiguous dates instead of guessing
+var strictDates bool
+
+// weekStart is the first day of the week, Monday unless set with SetWeekStart
+var weekStart = time.Monday
+
+// businessCalendar holds the holidays skipped by business day offsets (+3bd)
+// and by recurring due dates, set with SetBusinessCalendar
+var businessCalendar = dates.NewCalendar(false)
+
+// SetDateInputFormat makes ParseDateFlag accept numeric dates in the order of the
+// configured dates.format: MM/DD/YYYY for "us" and DD/MM/YYYY for "eu". Any other
+// format only accepts ISO dates.
+func SetDateInputFormat(format string) {
+	dateOrderSet = false
+	switch strings.ToLower(format) {
+	case "us", "eu":
+		numericDateLayout = DateLayouts[strings.ToLower(format)]
+	default:
+		numericDateLayout = ""
+	}
+}
+
+// SetDateOrder makes ParseDateFlag accept numeric dates in the given order of day
+// and month (DMY or MDY), whatever the display format. Empty keeps the order of
+// SetDateInputFormat.
+func SetDateOrder(order string) error {
+	switch strings.ToUpper(order) {
+	case "":
+		return nil
+	case DateOrderDMY:
+		numericDateLayout = DateLayouts["eu"]
+	case DateOrderMDY:
+		numericDateLayout = DateLayouts["us"]
+	default:
+		return fmt.Errorf("invalid date order %q (use DMY or MDY)", order)
+	}
+	dateOrderSet = true
+	return nil
+}
+
+// SetStrictDates turns strict date parsing on or off. In strict mode a numeric
+// date like 03/04/2026, whose day and month could be swapped, is rejected unless
+// the order was set with SetDateOrder.
+func SetStrictDates(strict bool) {
+	strictDates = strict
+}
+
+// StrictDates reports whether strict date parsing is on
+func StrictDates() bool {
+	return strictDates
+}
+
+// ParseWeekStart parses a dates.week_start value: monday (default, also for
+// empty) or sunday
+func ParseWeekStart(name string) (time.Weekday, error) {
+	switch strings.ToLower(name) {
+	case "", "monday", "mon":
+		return time.Monday, nil
+	case "sunday", "sun":
+		return time.Sunday, nil
+	}
+	return 0, fmt.Errorf("invalid week start %q (use monday or sunday)", name)
+}
+
+// SetWeekStart sets the first day of the week, used by eow and weekly grouping
+func SetWeekStart(day time.Weekday) {
+	weekStart = day
+}
+
+// SetBusinessCalendar sets the weekends and holidays used by business day
+// offsets and by the due dates of recurring tasks
+func SetBusinessCalendar(c *dates.Calendar) {
+	businessCalendar = c
+}
+
+// BusinessCalendar returns the calendar set with SetBusinessCalendar
+func BusinessCalendar() *dates.Calendar {
+	return businessCalendar
+}
+
+// StartOfWeek returns midnight of the first day of the week containing t, in
+// the location of t
+func StartOfWeek(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return day.AddDate(0, 0, -((int(day.Weekday()) - int(weekStart) + 7) % 7))
+}
+
+// relativePattern matches relative date formats like +7d, -3d, +2w, +1m, +1y
+var relativePattern = regexp.MustCompile(`^([+-])(\d+)([dwmy])$`)
+
+// businessDayPattern matches business day offsets like +3bd and -2bd
+var businessDayPattern = regexp.MustCompile(`^([+-])(\d+)bd$`)
+
+// timePattern matches time components like 14:30, 14:30:00, 9am or 9:30pm
+var timePattern = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(?::(\d{2}))?(am|pm)?$`)
+
+// inPattern matches "in 3 days", "in a week" and "2 weeks ago"
+var inPattern = regexp.MustCompile(`^(?:in (\d+|an?) (day|week|month|year)s?|(\d+|an?) (day|week|month|year)s? ago)$`)
+
+// monthDayPattern matches "jan 15", "15 jan", "january 15 2027" and "15 january 2027"
+var monthDayPattern = regexp.MustCompile(`^(?:([a-z]+) (\d{1,2})|(\d{1,2}) ([a-z]+))(?: (\d{4}))?$`)
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thurs": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+// parseTimeComponent parses a time string like "14:30", "14:30:00", "9am" or "9:30pm"
+// and returns hour, minute, second. Returns -1, -1, -1 if the string is not a valid time.
+func parseTimeComponent(timeStr string) (hour, minute, second int) {
+	matches := timePattern.FindStringSubmatch(timeStr)
+	// A bare number is a day or count, not a time
+	if matches == nil || (matches[2] == "" && matches[4] == "") {
+		return -1, -1, -1
+	}
+
+	hour, _ = strconv.Atoi(matches[1])
+	if matches[2] != "" {
+		minute, _ = strconv.Atoi(matches[2])
+	}
+	if matches[3] != "" {
+		second, _ = strconv.Atoi(matches[3])
+	}
+	if matches[4] != "" {
+		if hour < 1 || hour > 12 {
+			return -1, -1, -1
+		}
+		hour %= 12
+		if matches[4] == "pm" {
+			hour += 12
+		}
+	}
+
+	if hour > 23 || minute > 59 || second > 59 {
+		return -1, -1, -1
+	}
+	return hour, minute, second
+}
+
+// ParseTimeOfDay parses a time of day such as "17:00", "17:00:30", "5pm" or "5:30pm"
+func ParseTimeOfDay(s string) (hour, minute, second int, err error) {
+	hour, minute, second = parseTimeComponent(strings.ToLower(strings.TrimSpace(s)))
+	if hour < 0 {
+		return 0, 0, 0, fmt.Errorf("invalid time %q (use e.g. 17:00 or 5pm)", s)
+	}
+	return hour, minute, second, nil
+}
+
+// ParseNaturalDate parses a relative or natural-language date against now:
+//   - today, tomorrow, yesterday
+//   - monday ... sunday (or mon ... sun): the next such day, today included
+//   - next friday, last friday: the next or previous such day, today excluded
+//   - next week, next month, next year (and last ...): one unit from today
+//   - in 3 days, in 2 weeks, in a month, 3 days ago
+//   - +7d, -3d, +2w, +1m, +1y, and +3bd, -2bd in business days (see SetBusinessCalendar)
+//   - eod (today), eow (last day of this week), eom (end of month), eoy (end of year)
+//   - jan 15, 15 january, jan 15 2027: without a year, the next such date
+//   - 2026-06-01, and 06/01/2026 or 01/06/2026 with a us or eu date format or a date order
+//
+// A time may follow, optionally after "at": "friday 14:30", "tomorrow at 9am",
+// "2026-06-01 17:00".
+// Dates without a time are at midnight in the local timezone.
+// Returns nil, nil if the string is not a natural date, and an error if it is
+// one with invalid values.
+func ParseNaturalDate(dateStr string, now time.Time) (*time.Time, error) {
+	words := strings.Fields(strings.ToLower(strings.ReplaceAll(dateStr, ",", " ")))
+	if len(words) == 0 {
+		return nil, nil
+	}
+
+	hour, minute, second := -1, -1, -1
+	if len(words) > 1 {
+		if h, m, s := parseTimeComponent(words[len(words)-1]); h >= 0 {
+			hour, minute, second = h, m, s
+			words = words[:len(words)-1]
+			if len(words) > 1 && words[len(words)-1] == "at" {
+				words = words[:len(words)-1]
+			}
+		} else if strings.Contains(words[len(words)-1], ":") || words[len(words)-2] == "at" {
+			// A time was given but is not a valid one
+			return nil, ErrInvalidDate(dateStr)
+		}
+	}
+
+	now = now.In(tz.Location())
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, tz.Location())
+	date, ok, err := parseNaturalDay(strings.Join(words, " "), today)
+	if err != nil || !ok {
+		if _, ambiguous := err.(*ErrorWithSuggestion); ambiguous {
+			return nil, err
+		}
+		if err != nil {
+			return nil, ErrInvalidDate(dateStr)
+		}
+		return nil, nil
+	}
+
+	if hour >= 0 {
+		date = time.Date(date.Year(), date.Month(), date.Day(), hour, minute, second, 0, tz.Location())
+	}
+	return &date, nil
+}
+
+// parseNaturalDay resolves the date words of ParseNaturalDate to a day
+func parseNaturalDay(s string, today time.Time) (time.Time, bool, error) {
+	switch s {
+	case "today", "eod":
+		return today, true, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), true, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), true, nil
+	case "eow":
+		return StartOfWeek(today).AddDate(0, 0, 6), true, nil
+	case "eom":
+		return time.Date(today.Year(), today.Month()+1, 0, 0, 0, 0, 0, tz.Location()), true, nil
+	case "eoy":
+		return time.Date(today.Year(), 12, 31, 0, 0, 0, 0, tz.Location()), true, nil
+	}
+
+	if day, ok := weekdays[s]; ok {
+		return today.AddDate(0, 0, (int(day)-int(today.Weekday())+7)%7), true, nil
+	}
+
+	if matches := relativePattern.FindStringSubmatch(s); matches != nil {
+		num, err := strconv.Atoi(matches[2])
+		if err != nil {
+			return time.Time{}, false, err
+		}
+		if matches[1] == "-" {
+			num = -num
+		}
+		return addUnits(today, num, matches[3]), true, nil
+	}
+	if matches := businessDayPattern.FindStringSubmatch(s); matches != nil {
+		num, err := strconv.Atoi(matches[2])
+		if err != nil {
+			return time.Time{}, false, err
+		}
+		if matches[1] == "-" {
+			num = -num
+		}
+		return businessCalendar.AddBusinessDays(today, num), true, nil
+	}
+
+	if rest, ok := strings.CutPrefix(s, "next "); ok {
+		return shiftDay(today, rest, 1)
+	}
+	if rest, ok := strings.CutPrefix(s, "last "); ok {
+		return shiftDay(today, rest, -1)
+	}
+	if rest, ok := strings.CutPrefix(s, "this "); ok {
+		if day, ok := weekdays[rest]; ok {
+			return today.AddDate(0, 0, (int(day)-int(today.Weekday())+7)%7), true, nil
+		}
+	}
+
+	if matches := inPattern.FindStringSubmatch(s); matches != nil {
+		count, unit, sign := matches[1], matches[2], 1
+		if count == "" {
+			count, unit, sign = matches[3], matches[4], -1
+		}
+		num := 1
+		if count != "a" && count != "an" {
+			num, _ = strconv.Atoi(count)
+		}
+		return addUnits(today, sign*num, unit[:1]), true, nil
+	}
+
+	if matches := monthDayPattern.FindStringSubmatch(s); matches != nil {
+		monthName, dayStr := matches[1], matches[2]
+		if monthName == "" {
+			monthName, dayStr = matches[4], matches[3]
+		}
+		month, ok := parseMonthName(monthName)
+		if !ok {
+			return time.Time{}, false, nil
+		}
+		day, _ := strconv.Atoi(dayStr)
+		year := today.Year()
+		if matches[5] != "" {
+			year, _ = strconv.Atoi(matches[5])
+		}
+		date := time.Date(year, month, day, 0, 0, 0, 0, tz.Location())
+		if date.Day() != day {
+			return time.Time{}, false, fmt.Errorf("invalid day %d for %s", day, month)
+		}
+		if matches[5] == "" && date.Before(today) {
+			date = date.AddDate(1, 0, 0)
+		}
+		return date, true, nil
+	}
+
+	// Absolute dates, so that a time can follow them: "2026-06-01 17:00"
+	if date, err := time.ParseInLocation("2006-01-02", s, tz.Location()); err == nil {
+		return date, true, nil
+	}
+	if numericDateLayout != "" {
+		if date, err := time.ParseInLocation(numericDateLayout, s, tz.Location()); err == nil {
+			if strictDates && !dateOrderSet && date.Day() <= 12 && date.Day() != int(date.Month()) {
+				return time.Time{}, false, ErrAmbiguousDate(s)
+			}
+			return date, true, nil
+		}
+	}
+
+	return time.Time{}, false, nil
+}
+
+// shiftDay resolves "next X" (direction 1) and "last X" (direction -1) for a
+// weekday or a week, month or year
+func shiftDay(today time.Time, unit string, direction int) (time.Time, bool, error) {
+	if day, ok := weekdays[unit]; ok {
+		diff := (int(day) - int(today.Weekday()) + 7) % 7
+		if direction > 0 {
+			if diff == 0 {
+				diff = 7
+			}
+			return today.AddDate(0, 0, diff), true, nil
+		}
+		back := (7 - diff) % 7
+		if back == 0 {
+			back = 7
+		}
+		return today.AddDate(0, 0, -back), true, nil
+	}
+	switch unit {
+	case "week", "month", "year":
+		return addUnits(today, direction, unit[:1]), true, nil
+	}
+	return time.Time{}, false, nil
+}
+
+// addUnits adds n days (d), weeks (w), months (m) or years (y) to a date
+func addUnits(date time.Time, n int, unit string) time.Time {
+	switch unit {
+	case "w":
+		return date.AddDate(0, 0, 7*n)
+	case "m":
+		return date.AddDate(0, n, 0)
+	case "y":
+		return date.AddDate(n, 0, 0)
+	default:
+		return date.AddDate(0, 0, n)
+	}
+}
+
+// parseMonthName parses a full or abbreviated English month name
+func parseMonthName(name string) (time.Month, bool) {
+	if len(name) < 3 {
+		return 0, false
+	}
+	if name == "sept" {
+		return time.September, true
+	}
+	for m := time.January; m <= time.December; m++ {
+		full := strings.ToLower(m.String())
+		if name == full || name == full[:3] {
+			return m, true
+		}
+	}
+	return 0, false
+}
+
+// FormatRelativeDate describes a date relative to now by calendar day:
+// "today", "tomorrow", "yesterday", "in 3 days", "2 weeks ago", "in 4 months"
+func FormatRelativeDate(t, now time.Time) string {
+	days := CalendarDaysBetween(now, t)
+	switch {
+	case days == 0:
+		return "today"
+	case days == 1:
+		return "tomorrow"
+	case days == -1:
+		return "yesterday"
+	}
+
+	n := days
+	if n < 0 {
+		n = -n
+	}
+	var amount string
+	switch {
+	case n < 14:
+		amount = plural(n, "day")
+	case n < 60:
+		amount = plural(n/7, "week")
+	case n < 730:
+		amount = plural(n/30, "month")
+	default:
+		amount = plural(n/365, "year")
+	}
+	if days > 0 {
+		return "in " + amount
+	}
+	return amount + " ago"
+}
+
+// CalendarDaysBetween returns the number of calendar days from one date to another
+// in the local timezone, ignoring the time of day
+func CalendarDaysBetween(from, to time.Time) int {
+	from = from.In(tz.Location())
+	to = to.In(tz.Location())
+	a := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	b := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(b.Sub(a).Hours() / 24)
+}
+
+// plural formats a count with a unit, adding "s" unless the count is 1
+func plural(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit
+	}
+	return strconv.Itoa(n) + " " + unit + "s"
+}
@@ -0,0 +1,194 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"todoat/internal/dates"
+)
+
+// TestParseNaturalDate verifies natural-language dates resolve against a fixed day
+func TestParseNaturalDate(t *testing.T) {
+	// Wednesday
+	now := time.Date(2026, 3, 11, 10, 0, 0, 0, time.Local)
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.Local) }
+
+	tests := map[string]time.Time{
+		"today":            day(2026, 3, 11),
+		"Tomorrow":         day(2026, 3, 12),
+		"friday":           day(2026, 3, 13),
+		"wed":              day(2026, 3, 11),
+		"this friday":      day(2026, 3, 13),
+		"next wednesday":   day(2026, 3, 18),
+		"next friday":      day(2026, 3, 13),
+		"last wednesday":   day(2026, 3, 4),
+		"last friday":      day(2026, 3, 6),
+		"next week":        day(2026, 3, 18),
+		"next month":       day(2026, 4, 11),
+		"in 2 weeks":       day(2026, 3, 25),
+		"in a month":       day(2026, 4, 11),
+		"in 3 days":        day(2026, 3, 14),
+		"3 days ago":       day(2026, 3, 8),
+		"+1y":              day(2027, 3, 11),
+		"-2w":              day(2026, 2, 25),
+		"eod":              day(2026, 3, 11),
+		"eow":              day(2026, 3, 15),
+		"eom":              day(2026, 3, 31),
+		"eoy":              day(2026, 12, 31),
+		"jan 15":           day(2027, 1, 15),
+		"15 April":         day(2026, 4, 15),
+		"march 11":         day(2026, 3, 11),
+		"Jan 15, 2028":     day(2028, 1, 15),
+		"tomorrow at 9am":  time.Date(2026, 3, 12, 9, 0, 0, 0, time.Local),
+		"friday 14:30":     time.Date(2026, 3, 13, 14, 30, 0, 0, time.Local),
+		"eod 5:30pm":       time.Date(2026, 3, 11, 17, 30, 0, 0, time.Local),
+		"2026-06-01 17:00": time.Date(2026, 6, 1, 17, 0, 0, 0, time.Local),
+	}
+	for input, want := range tests {
+		got, err := ParseNaturalDate(input, now)
+		if err != nil || got == nil || !got.Equal(want) {
+			t.Errorf("ParseNaturalDate(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+
+	for _, input := range []string{"someday", "2026-13-01", "in two weeks"} {
+		if got, err := ParseNaturalDate(input, now); got != nil || err != nil {
+			t.Errorf("ParseNaturalDate(%q) = %v, %v; want not a natural date", input, got, err)
+		}
+	}
+	for _, input := range []string{"tomorrow 25:00", "feb 30", "friday at noonish"} {
+		if _, err := ParseNaturalDate(input, now); err == nil {
+			t.Errorf("ParseNaturalDate(%q) should fail", input)
+		}
+	}
+}
+
+// TestParseTimeOfDay verifies 24-hour and 12-hour times
+func TestParseTimeOfDay(t *testing.T) {
+	tests := map[string][3]int{"17:00": {17, 0, 0}, "5pm": {17, 0, 0}, "12am": {0, 0, 0}, "9:30AM": {9, 30, 0}, "08:15:30": {8, 15, 30}}
+	for input, want := range tests {
+		h, m, s, err := ParseTimeOfDay(input)
+		if err != nil || [3]int{h, m, s} != want {
+			t.Errorf("ParseTimeOfDay(%q) = %d:%d:%d, %v; want %v", input, h, m, s, err, want)
+		}
+	}
+	for _, input := range []string{"", "17", "25:00", "13pm", "noon"} {
+		if _, _, _, err := ParseTimeOfDay(input); err == nil {
+			t.Errorf("ParseTimeOfDay(%q) should fail", input)
+		}
+	}
+}
+
+// TestFormatRelativeDate verifies relative descriptions by calendar day
+func TestFormatRelativeDate(t *testing.T) {
+	now := time.Date(2026, 3, 11, 23, 0, 0, 0, time.Local)
+	tests := map[time.Time]string{
+		time.Date(2026, 3, 11, 1, 0, 0, 0, time.Local): "today",
+		time.Date(2026, 3, 12, 0, 0, 0, 0, time.Local): "tomorrow",
+		time.Date(2026, 3, 10, 0, 0, 0, 0, time.Local): "yesterday",
+		time.Date(2026, 3, 14, 0, 0, 0, 0, time.Local): "in 3 days",
+		time.Date(2026, 2, 25, 0, 0, 0, 0, time.Local): "2 weeks ago",
+		time.Date(2026, 7, 11, 0, 0, 0, 0, time.Local): "in 4 months",
+		time.Date(2029, 3, 11, 0, 0, 0, 0, time.Local): "in 3 years",
+	}
+	for input, want := range tests {
+		if got := FormatRelativeDate(input, now); got != want {
+			t.Errorf("FormatRelativeDate(%v) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+// TestResolveDateLayoutAndNumericInput verifies date format names, custom layouts
+// and the configured order of numeric dates
+func TestResolveDateLayoutAndNumericInput(t *testing.T) {
+	if layout, err := ResolveDateLayout("EU"); err != nil || layout != "02/01/2006" {
+		t.Errorf("ResolveDateLayout(EU) = %q, %v", layout, err)
+	}
+	if layout, err := ResolveDateLayout("02.01.2006"); err != nil || layout != "02.01.2006" {
+		t.Errorf("ResolveDateLayout(custom) = %q, %v", layout, err)
+	}
+	if _, err := ResolveDateLayout("dd/mm/yyyy"); err == nil {
+		t.Error("ResolveDateLayout should reject a string without layout elements")
+	}
+
+	defer SetDateInputFormat("")
+	if _, err := ParseDateFlag("03/04/2026"); err == nil {
+		t.Error("numeric dates should be rejected without a us or eu format")
+	}
+	SetDateInputFormat("eu")
+	if got, err := ParseDateFlag("03/04/2026"); err != nil || got.Month() != time.April || got.Day() != 3 {
+		t.Errorf("eu ParseDateFlag(03/04/2026) = %v, %v", got, err)
+	}
+	SetDateInputFormat("us")
+	if got, err := ParseDateFlag("03/04/2026"); err != nil || got.Month() != time.March || got.Day() != 4 {
+		t.Errorf("us ParseDateFlag(03/04/2026) = %v, %v", got, err)
+	}
+}
+
+// TestStrictDatesAndWeekStart verifies that strict mode rejects numeric dates
+// whose day and month could be swapped unless a date order is set, and that eow
+// follows the week start
+func TestStrictDatesAndWeekStart(t *testing.T) {
+	defer SetDateInputFormat("")
+	defer SetStrictDates(false)
+	defer SetWeekStart(time.Monday)
+
+	SetDateInputFormat("us")
+	SetStrictDates(true)
+	if _, err := ParseDateFlag("03/04/2026"); err == nil || !strings.Contains(err.Error(), "ambiguous date") {
+		t.Errorf("strict ParseDateFlag(03/04/2026) error = %v, want ambiguous date", err)
+	}
+	for _, unambiguous := range []string{"03/13/2026", "03/03/2026", "2026-03-04"} {
+		if _, err := ParseDateFlag(unambiguous); err != nil {
+			t.Errorf("strict ParseDateFlag(%s) = %v", unambiguous, err)
+		}
+	}
+	if err := SetDateOrder("DMY"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := ParseDateFlag("03/04/2026"); err != nil || got.Month() != time.April || got.Day() != 3 {
+		t.Errorf("DMY strict ParseDateFlag(03/04/2026) = %v, %v", got, err)
+	}
+	if err := SetDateOrder("YMD"); err == nil {
+		t.Error("SetDateOrder should reject YMD")
+	}
+
+	// Wednesday March 11, 2026
+	now := time.Date(2026, 3, 11, 10, 0, 0, 0, time.Local)
+	if got, _ := ParseNaturalDate("eow", now); got.Weekday() != time.Sunday || got.Day() != 15 {
+		t.Errorf("eow with Monday week start = %v", got)
+	}
+	day, err := ParseWeekStart("Sunday")
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetWeekStart(day)
+	if got, _ := ParseNaturalDate("eow", now); got.Weekday() != time.Saturday || got.Day() != 14 {
+		t.Errorf("eow with Sunday week start = %v", got)
+	}
+	if got := StartOfWeek(now); got.Day() != 8 {
+		t.Errorf("StartOfWeek = %v, want Sunday March 8", got)
+	}
+}
+
+func TestBusinessDayOffsets(t *testing.T) {
+	defer SetBusinessCalendar(dates.NewCalendar(false))
+
+	// Thursday April 2, 2026: Good Friday and Easter Monday are GB holidays
+	now := time.Date(2026, 4, 2, 10, 0, 0, 0, time.Local)
+	if got, _ := ParseNaturalDate("+2bd", now); got.Weekday() != time.Monday || got.Day() != 6 {
+		t.Errorf("+2bd without holidays = %v, want Monday April 6", got)
+	}
+	calendar, err := dates.Load(false, "GB")
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetBusinessCalendar(calendar)
+	if got, _ := ParseNaturalDate("+2bd 14:00", now); got.Day() != 8 || got.Hour() != 14 {
+		t.Errorf("+2bd with GB holidays = %v, want Wednesday April 8 14:00", got)
+	}
+	if got, _ := ParseNaturalDate("-1bd", now); got.Day() != 1 {
+		t.Errorf("-1bd = %v, want April 1", got)
+	}
+}
@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// durationPattern matches effort durations like 2h, 30m, 1h30m or 1.5h
+var durationPattern = regexp.MustCompile(`^(?:(\d+(?:\.\d+)?)h)?(?:(\d+)m)?$`)
+
+// ParseDuration parses an effort duration such as "2h", "45m", "1h30m" or "1.5h".
+// Unlike time.ParseDuration it rejects seconds and negative values, and rounds to
+// whole minutes.
+func ParseDuration(s string) (time.Duration, error) {
+	value := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(s), " ", ""))
+	matches := durationPattern.FindStringSubmatch(value)
+	if value == "" || matches == nil {
+		return 0, fmt.Errorf("invalid duration %q (use e.g. 2h, 45m or 1h30m)", s)
+	}
+
+	var d time.Duration
+	if matches[1] != "" {
+		hours, err := strconv.ParseFloat(matches[1], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q: %w", s, err)
+		}
+		d += time.Duration(hours * float64(time.Hour))
+	}
+	if matches[2] != "" {
+		minutes, err := strconv.Atoi(matches[2])
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q: %w", s, err)
+		}
+		d += time.Duration(minutes) * time.Minute
+	}
+	return d.Round(time.Minute), nil
+}
+
+// FormatDuration formats an effort duration in the form ParseDuration reads,
+// e.g. "2h", "45m" or "1h30m"
+func FormatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	hours := int(d / time.Hour)
+	minutes := int((d % time.Hour) / time.Minute)
+	switch {
+	case hours > 0 && minutes > 0:
+		return fmt.Sprintf("%dh%dm", hours, minutes)
+	case hours > 0:
+		return fmt.Sprintf("%dh", hours)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+// TestParseDuration verifies effort durations parse to whole minutes
+func TestParseDuration(t *testing.T) {
+	tests := map[string]time.Duration{
+		"2h":    2 * time.Hour,
+		"45m":   45 * time.Minute,
+		"1h30m": 90 * time.Minute,
+		"1.5h":  90 * time.Minute,
+		"1H 5M": 65 * time.Minute,
+	}
+	for input, want := range tests {
+		got, err := ParseDuration(input)
+		if err != nil || got != want {
+			t.Errorf("ParseDuration(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+
+	for _, input := range []string{"", "2", "-1h", "30s", "h", "2x"} {
+		if _, err := ParseDuration(input); err == nil {
+			t.Errorf("ParseDuration(%q) should fail", input)
+		}
+	}
+}
+
+// TestFormatDuration verifies durations format back into ParseDuration syntax
+func TestFormatDuration(t *testing.T) {
+	tests := map[time.Duration]string{
+		0:                "0m",
+		45 * time.Minute: "45m",
+		2 * time.Hour:    "2h",
+		90 * time.Minute: "1h30m",
+	}
+	for input, want := range tests {
+		if got := FormatDuration(input); got != want {
+			t.Errorf("FormatDuration(%v) = %q, want %q", input, got, want)
+		}
+	}
+}
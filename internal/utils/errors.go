@@ -115,7 +115,16 @@ func ErrInvalidPriority(priority int) error {
 func ErrInvalidDate(dateStr string) error {
 	return &ErrorWithSuggestion{
 		Err:        fmt.Errorf("invalid date: %s", dateStr),
-		Suggestion: "Use date format YYYY-MM-DD (e.g., 2026-01-15)",
+		Suggestion: "Use date format YYYY-MM-DD (e.g., 2026-01-15) or a natural date (e.g., tomorrow, next friday, in 2 weeks, eom)",
+	}
+}
+
+// ErrAmbiguousDate returns an error for a numeric date whose day and month
+// could be swapped, rejected in strict mode.
+func ErrAmbiguousDate(dateStr string) error {
+	return &ErrorWithSuggestion{
+		Err:        fmt.Errorf("ambiguous date: %s (day and month could be swapped)", dateStr),
+		Suggestion: "Use date format YYYY-MM-DD (e.g., 2026-01-15), or set dates.date_order to DMY or MDY",
 	}
 }
 
@@ -1,10 +1,13 @@
 package utils
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"log"
+	"log/slog"
 	"os"
+	"strings"
 	"sync"
 	"time"
 )
@@ -14,9 +17,21 @@ import (
 // The runtime config option logging.background_enabled overrides this default.
 const defaultBackgroundLoggingEnabled = true
 
-// Logger provides leveled logging with verbose mode support.
+// Log output formats
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
+// Logger provides leveled, structured logging built on log/slog. Records go to
+// stderr ("15:04:05 [DEBUG] message key=value" in text format) and, when a log
+// file is configured, to that file as well. Debug records are only written in
+// verbose mode, which is the same as the debug level.
 type Logger struct {
 	verbose bool
+	level   slog.Level
+	format  string
+	file    io.WriteCloser
 	mu      sync.RWMutex
 }
 
@@ -30,6 +45,8 @@ func GetLogger() *Logger {
 	once.Do(func() {
 		loggerInstance = &Logger{
 			verbose: false,
+			level:   slog.LevelInfo,
+			format:  LogFormatText,
 		}
 	})
 	return loggerInstance
@@ -42,10 +59,16 @@ func SetVerboseMode(verbose bool) {
 }
 
 // SetVerbose sets the verbose mode for this logger instance.
+// Verbose mode lowers the level to debug; turning it off restores info.
 func (l *Logger) SetVerbose(verbose bool) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	l.verbose = verbose
+	if verbose {
+		l.level = slog.LevelDebug
+	} else if l.level < slog.LevelInfo {
+		l.level = slog.LevelInfo
+	}
 }
 
 // IsVerbose returns whether verbose mode is enabled.
@@ -55,6 +78,70 @@ func (l *Logger) IsVerbose() bool {
 	return l.verbose
 }
 
+// SetLevel sets the minimum level written to stderr and the log file.
+func (l *Logger) SetLevel(level slog.Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.level = level
+	l.verbose = level <= slog.LevelDebug
+}
+
+// Level returns the minimum level that is logged.
+func (l *Logger) Level() slog.Level {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.level
+}
+
+// SetFormat sets the output format, LogFormatText or LogFormatJSON.
+func (l *Logger) SetFormat(format string) error {
+	if format != LogFormatText && format != LogFormatJSON {
+		return fmt.Errorf("invalid log format %q (must be text or json)", format)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.format = format
+	return nil
+}
+
+// SetOutputFile also writes log records to w, closing any previously set file.
+// Pass nil to stop writing to a file.
+func (l *Logger) SetOutputFile(w io.WriteCloser) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file != nil {
+		_ = l.file.Close()
+	}
+	l.file = w
+}
+
+// ParseLogLevel parses a level name: debug, info, warn (or warning) or error.
+func ParseLogLevel(name string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return slog.LevelInfo, fmt.Errorf("invalid log level %q (must be debug, info, warn or error)", name)
+	}
+}
+
+// Slog returns a structured logger that writes through this logger.
+func (l *Logger) Slog() *slog.Logger {
+	return slog.New(&logHandler{logger: l})
+}
+
+// Component returns a structured logger for a subsystem (e.g. "sync", "daemon"),
+// adding a component attribute to every record.
+func Component(name string) *slog.Logger {
+	return GetLogger().Slog().With("component", name)
+}
+
 // formatMessage formats a message with optional printf-style arguments.
 func formatMessage(msgOrFormat string, args ...interface{}) string {
 	if len(args) > 0 {
@@ -63,31 +150,37 @@ func formatMessage(msgOrFormat string, args ...interface{}) string {
 	return msgOrFormat
 }
 
+// log writes a printf-style message at level
+func (l *Logger) log(level slog.Level, msgOrFormat string, args ...interface{}) {
+	if level < l.Level() {
+		return
+	}
+	r := slog.NewRecord(time.Now(), level, formatMessage(msgOrFormat, args...), 0)
+	_ = (&logHandler{logger: l}).Handle(context.Background(), r)
+}
+
 // Debug logs a debug message (only shown when verbose=true).
 // Can be used with a simple message or printf-style format string with args.
 func (l *Logger) Debug(msgOrFormat string, args ...interface{}) {
-	if !l.IsVerbose() {
-		return
-	}
-	fmt.Fprintf(os.Stderr, "%s [DEBUG] %s\n", time.Now().Format("15:04:05"), formatMessage(msgOrFormat, args...))
+	l.log(slog.LevelDebug, msgOrFormat, args...)
 }
 
-// Info logs an info message (always shown).
+// Info logs an info message (shown unless the level is warn or error).
 // Can be used with a simple message or printf-style format string with args.
 func (l *Logger) Info(msgOrFormat string, args ...interface{}) {
-	fmt.Fprintf(os.Stderr, "[INFO] %s\n", formatMessage(msgOrFormat, args...))
+	l.log(slog.LevelInfo, msgOrFormat, args...)
 }
 
-// Warn logs a warning message (always shown).
+// Warn logs a warning message (shown unless the level is error).
 // Can be used with a simple message or printf-style format string with args.
 func (l *Logger) Warn(msgOrFormat string, args ...interface{}) {
-	fmt.Fprintf(os.Stderr, "[WARN] %s\n", formatMessage(msgOrFormat, args...))
+	l.log(slog.LevelWarn, msgOrFormat, args...)
 }
 
 // Error logs an error message (always shown).
 // Can be used with a simple message or printf-style format string with args.
 func (l *Logger) Error(msgOrFormat string, args ...interface{}) {
-	fmt.Fprintf(os.Stderr, "[ERROR] %s\n", formatMessage(msgOrFormat, args...))
+	l.log(slog.LevelError, msgOrFormat, args...)
 }
 
 // Debugf is a convenience function that logs a debug message using the global logger.
@@ -110,6 +203,110 @@ func Errorf(format string, args ...interface{}) {
 	GetLogger().Error(format, args...)
 }
 
+// stderrWriter writes to the current os.Stderr, so redirecting it takes effect immediately
+type stderrWriter struct{}
+
+func (stderrWriter) Write(p []byte) (int, error) {
+	return os.Stderr.Write(p)
+}
+
+// logHandler is the slog.Handler behind Logger. It writes each record to stderr
+// and, when configured, to the log file.
+type logHandler struct {
+	logger *Logger
+	attrs  []slog.Attr
+	groups []string
+}
+
+func (h *logHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.logger.Level()
+}
+
+func (h *logHandler) Handle(ctx context.Context, r slog.Record) error {
+	h.logger.mu.RLock()
+	format, file := h.logger.format, h.logger.file
+	h.logger.mu.RUnlock()
+
+	var err error
+	if format == LogFormatJSON {
+		err = h.wrap(slog.NewJSONHandler(stderrWriter{}, &slog.HandlerOptions{Level: slog.LevelDebug})).Handle(ctx, r)
+	} else {
+		_, err = io.WriteString(os.Stderr, h.consoleLine(r))
+	}
+
+	if file != nil {
+		var fh slog.Handler
+		if format == LogFormatJSON {
+			fh = slog.NewJSONHandler(file, &slog.HandlerOptions{Level: slog.LevelDebug})
+		} else {
+			fh = slog.NewTextHandler(file, &slog.HandlerOptions{Level: slog.LevelDebug})
+		}
+		if ferr := h.wrap(fh).Handle(ctx, r); err == nil {
+			err = ferr
+		}
+	}
+	return err
+}
+
+// wrap applies this handler's attributes and groups to a standard slog handler
+func (h *logHandler) wrap(handler slog.Handler) slog.Handler {
+	for _, g := range h.groups {
+		handler = handler.WithGroup(g)
+	}
+	if len(h.attrs) > 0 {
+		handler = handler.WithAttrs(h.attrs)
+	}
+	return handler
+}
+
+// consoleLine renders a record in the human-readable stderr format
+func (h *logHandler) consoleLine(r slog.Record) string {
+	var b strings.Builder
+	if r.Level < slog.LevelInfo {
+		b.WriteString(r.Time.Format("15:04:05") + " ")
+	}
+	fmt.Fprintf(&b, "[%s] %s", levelName(r.Level), r.Message)
+	prefix := strings.Join(h.groups, ".")
+	if prefix != "" {
+		prefix += "."
+	}
+	writeAttr := func(a slog.Attr) bool {
+		fmt.Fprintf(&b, " %s%s=%v", prefix, a.Key, a.Value)
+		return true
+	}
+	for _, a := range h.attrs {
+		writeAttr(a)
+	}
+	r.Attrs(writeAttr)
+	b.WriteString("\n")
+	return b.String()
+}
+
+func (h *logHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &logHandler{logger: h.logger, attrs: append(append([]slog.Attr{}, h.attrs...), attrs...), groups: h.groups}
+}
+
+func (h *logHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &logHandler{logger: h.logger, attrs: h.attrs, groups: append(append([]string{}, h.groups...), name)}
+}
+
+// levelName returns the console label of a level
+func levelName(level slog.Level) string {
+	switch {
+	case level < slog.LevelInfo:
+		return "DEBUG"
+	case level < slog.LevelWarn:
+		return "INFO"
+	case level < slog.LevelError:
+		return "WARN"
+	default:
+		return "ERROR"
+	}
+}
+
 // BackgroundLogger provides logging for background processes to a PID-specific file.
 type BackgroundLogger struct {
 	logger   *log.Logger
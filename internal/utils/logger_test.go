@@ -2,7 +2,9 @@ package utils
 
 import (
 	"bytes"
+	"encoding/json"
 	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -727,3 +729,111 @@ func TestNewBackgroundLoggerWithEnabledFalseNoFileCreated(t *testing.T) {
 		t.Errorf("No files should be created when disabled, before: %d, after: %d", countBefore, countAfter)
 	}
 }
+
+// =============================================================================
+// Structured Logging Tests
+// =============================================================================
+
+// nopCloser adapts a buffer to io.WriteCloser for log file tests
+type nopCloser struct{ *bytes.Buffer }
+
+func (nopCloser) Close() error { return nil }
+
+// TestParseLogLevel verifies level names are parsed case-insensitively
+func TestParseLogLevel(t *testing.T) {
+	for name, want := range map[string]slog.Level{
+		"debug":   slog.LevelDebug,
+		"INFO":    slog.LevelInfo,
+		"warn":    slog.LevelWarn,
+		"warning": slog.LevelWarn,
+		"error":   slog.LevelError,
+	} {
+		got, err := ParseLogLevel(name)
+		if err != nil || got != want {
+			t.Errorf("ParseLogLevel(%q) = %v, %v; want %v", name, got, err, want)
+		}
+	}
+	if _, err := ParseLogLevel("trace"); err == nil {
+		t.Error("ParseLogLevel(\"trace\") should fail")
+	}
+}
+
+// TestSetLevelFiltersRecords verifies records below the level are dropped
+func TestSetLevelFiltersRecords(t *testing.T) {
+	once = sync.Once{}
+	loggerInstance = nil
+
+	var buf bytes.Buffer
+	logger := GetLogger()
+	logger.SetOutputFile(nopCloser{&buf})
+	defer logger.SetOutputFile(nil)
+	logger.SetLevel(slog.LevelWarn)
+
+	oldStderr := os.Stderr
+	_, w, _ := os.Pipe()
+	os.Stderr = w
+	logger.Info("dropped info")
+	logger.Warn("kept warning")
+	_ = w.Close()
+	os.Stderr = oldStderr
+
+	if strings.Contains(buf.String(), "dropped info") {
+		t.Errorf("info should be filtered at warn level, got: %s", buf.String())
+	}
+	if !strings.Contains(buf.String(), "kept warning") {
+		t.Errorf("warning should be logged at warn level, got: %s", buf.String())
+	}
+}
+
+// TestComponentLoggerJSONFile verifies structured records with attributes reach the log file as JSON
+func TestComponentLoggerJSONFile(t *testing.T) {
+	once = sync.Once{}
+	loggerInstance = nil
+
+	var buf bytes.Buffer
+	logger := GetLogger()
+	logger.SetOutputFile(nopCloser{&buf})
+	defer logger.SetOutputFile(nil)
+	if err := logger.SetFormat(LogFormatJSON); err != nil {
+		t.Fatal(err)
+	}
+
+	oldStderr := os.Stderr
+	_, w, _ := os.Pipe()
+	os.Stderr = w
+	Component("sync").Info("pushed tasks", "count", 3)
+	_ = w.Close()
+	os.Stderr = oldStderr
+
+	var record map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("log file should contain a JSON record, got %q: %v", buf.String(), err)
+	}
+	if record["msg"] != "pushed tasks" || record["component"] != "sync" || record["count"] != float64(3) || record["level"] != "INFO" {
+		t.Errorf("unexpected record: %v", record)
+	}
+
+	if err := logger.SetFormat("xml"); err == nil {
+		t.Error("SetFormat(\"xml\") should fail")
+	}
+}
+
+// TestComponentLoggerTextConsole verifies attributes are appended to the console line
+func TestComponentLoggerTextConsole(t *testing.T) {
+	once = sync.Once{}
+	loggerInstance = nil
+
+	oldStderr := os.Stderr
+	r, w, _ := os.Pipe()
+	os.Stderr = w
+	Component("daemon").Warn("backend slow", "backend", "nextcloud")
+	_ = w.Close()
+	var buf bytes.Buffer
+	_, _ = io.Copy(&buf, r)
+	os.Stderr = oldStderr
+
+	want := "[WARN] backend slow component=daemon backend=nextcloud\n"
+	if buf.String() != want {
+		t.Errorf("console output = %q, want %q", buf.String(), want)
+	}
+}
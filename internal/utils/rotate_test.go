@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestRotatingFileRotatesAtMaxSize verifies the file is rotated into numbered backups
+func TestRotatingFileRotatesAtMaxSize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "daemon.log")
+	f, err := OpenRotatingFile(path, 10, 2)
+	if err != nil {
+		t.Fatalf("OpenRotatingFile failed: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if _, err := f.Write([]byte(line)); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+
+	expect := map[string]string{
+		path:        "fourth\n",
+		path + ".1": "third\n",
+		path + ".2": "second\n",
+	}
+	for p, want := range expect {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", p, err)
+		}
+		if string(data) != want {
+			t.Errorf("%s = %q, want %q", filepath.Base(p), data, want)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Error("only 2 backups should be kept")
+	}
+}
+
+// TestRotatingFileAppendsToExisting verifies an existing file's size counts toward rotation
+func TestRotatingFileAppendsToExisting(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "daemon.log")
+	if err := os.WriteFile(path, []byte("existing\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := OpenRotatingFile(path, 12, 1)
+	if err != nil {
+		t.Fatalf("OpenRotatingFile failed: %v", err)
+	}
+	if _, err := f.Write([]byte("new entry\n")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	_ = f.Close()
+
+	backup, _ := os.ReadFile(path + ".1")
+	if !strings.Contains(string(backup), "existing") {
+		t.Errorf("existing content should be rotated to backup, got %q", backup)
+	}
+	current, _ := os.ReadFile(path)
+	if string(current) != "new entry\n" {
+		t.Errorf("current file = %q, want new entry only", current)
+	}
+	if _, err := f.Write([]byte("x")); err == nil {
+		t.Error("Write after Close should fail")
+	}
+}
@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"todoat/internal/tz"
+
+	// Embedded zone database so timezone works on systems without one
+	_ "time/tzdata"
+)
+
+// SetTimezone sets the zone dates are parsed and displayed in. name is an IANA
+// zone such as "Europe/Berlin", "UTC", or empty or "local" for the system zone.
+// Timestamps are stored in UTC; date-only values are midnight in this zone.
+// time.Local is left unchanged.
+func SetTimezone(name string) error {
+	loc, err := LoadTimezone(name)
+	if err != nil {
+		return err
+	}
+	tz.Set(loc)
+	return nil
+}
+
+// LoadTimezone resolves a timezone setting to a location; empty or "local"
+// returns the system zone
+func LoadTimezone(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || strings.EqualFold(name, "local") {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q (use an IANA name like Europe/Berlin, UTC or local)", name)
+	}
+	return loc, nil
+}
+
+// DateAsLocal returns midnight in the display zone on the calendar date t has in
+// its own location. Use it for date-only values from services that send them
+// as UTC midnight.
+func DateAsLocal(t time.Time) time.Time {
+	return tz.Date(t.Year(), t.Month(), t.Day())
+}
+
+// DateAsUTC returns UTC midnight on the calendar date t has in the display zone.
+// Use it to send date-only values to services that expect UTC midnight.
+func DateAsUTC(t time.Time) time.Time {
+	local := t.In(tz.Location())
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+}
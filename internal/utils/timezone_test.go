@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"todoat/internal/tz"
+)
+
+// TestSetTimezone verifies named zones become the display zone without changing
+// time.Local, and "local" restores the system zone
+func TestSetTimezone(t *testing.T) {
+	defer func() { _ = SetTimezone("") }()
+
+	if err := SetTimezone("America/New_York"); err != nil {
+		t.Fatalf("SetTimezone error: %v", err)
+	}
+	if tz.Location().String() != "America/New_York" {
+		t.Errorf("tz.Location() = %s, want America/New_York", tz.Location())
+	}
+	if time.Local == tz.Location() {
+		t.Error("SetTimezone should not change time.Local")
+	}
+	if err := SetTimezone("Mars/Olympus"); err == nil {
+		t.Error("SetTimezone should reject unknown zones")
+	}
+	if err := SetTimezone("local"); err != nil || tz.Location() != time.Local {
+		t.Errorf("SetTimezone(local) = %v, tz.Location() = %s", err, tz.Location())
+	}
+}
+
+// TestDateAsLocalAndUTC verifies date-only values keep their calendar date across zones
+func TestDateAsLocalAndUTC(t *testing.T) {
+	defer func() { _ = SetTimezone("") }()
+	if err := SetTimezone("Europe/Berlin"); err != nil {
+		t.Fatalf("SetTimezone error: %v", err)
+	}
+
+	local := DateAsLocal(time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC))
+	if local.Format("2006-01-02 15:04 MST") != "2026-06-01 00:00 CEST" {
+		t.Errorf("DateAsLocal = %s, want 2026-06-01 00:00 CEST", local)
+	}
+	if got := DateAsUTC(local); !got.Equal(time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("DateAsUTC = %s, want 2026-06-01 00:00 UTC", got)
+	}
+}
@@ -2,10 +2,9 @@ package utils
 
 import (
 	"errors"
-	"regexp"
-	"strconv"
-	"strings"
 	"time"
+
+	"todoat/internal/tz"
 )
 
 // ValidatePriority validates that priority is within valid range (0-9).
@@ -16,119 +15,15 @@ func ValidatePriority(priority int) error {
 	return nil
 }
 
-// relativePattern matches relative date formats like +7d, -3d, +2w, +1m
-var relativePattern = regexp.MustCompile(`^([+-])(\d+)([dwm])$`)
-
-// timePattern matches time components like 14:30 or 14:30:00
-var timePattern = regexp.MustCompile(`^(\d{1,2}):(\d{2})(?::(\d{2}))?$`)
-
-// parseTimeComponent parses a time string like "14:30" or "14:30:00" and returns hour, minute, second.
-// Returns -1, -1, -1 if the string is not a valid time format.
-func parseTimeComponent(timeStr string) (hour, minute, second int) {
-	matches := timePattern.FindStringSubmatch(timeStr)
-	if matches == nil {
-		return -1, -1, -1
-	}
-
-	hour, _ = strconv.Atoi(matches[1])
-	minute, _ = strconv.Atoi(matches[2])
-	second = 0
-	if matches[3] != "" {
-		second, _ = strconv.Atoi(matches[3])
-	}
-
-	// Validate ranges
-	if hour < 0 || hour > 23 || minute < 0 || minute > 59 || second < 0 || second > 59 {
-		return -1, -1, -1
-	}
-
-	return hour, minute, second
-}
-
-// parseRelativeDate parses relative date strings like "today", "tomorrow", "yesterday", "+7d", "-3d", "+2w", "+1m".
-// Also supports optional time component: "tomorrow 14:30", "today 09:00", "+7d 10:00".
-// Returns nil if the string is not a relative date format.
-// Returns the calculated time and nil for valid relative dates.
-// Returns nil and error for invalid relative date values.
-func parseRelativeDate(dateStr string) (*time.Time, error) {
-	now := time.Now()
-	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
-
-	// Check for time component (space-separated)
-	parts := strings.SplitN(dateStr, " ", 2)
-	datePart := parts[0]
-	var timePart string
-	if len(parts) == 2 {
-		timePart = parts[1]
-	}
-
-	lower := strings.ToLower(datePart)
-
-	var baseDate time.Time
-	matched := false
-
-	switch lower {
-	case "today":
-		baseDate = today
-		matched = true
-	case "tomorrow":
-		baseDate = today.AddDate(0, 0, 1)
-		matched = true
-	case "yesterday":
-		baseDate = today.AddDate(0, 0, -1)
-		matched = true
-	default:
-		// Check for relative format (+/-Nd, +/-Nw, +/-Nm)
-		matches := relativePattern.FindStringSubmatch(lower)
-		if matches != nil {
-			sign := matches[1]
-			numStr := matches[2]
-			unit := matches[3]
-
-			num, err := strconv.Atoi(numStr)
-			if err != nil {
-				return nil, ErrInvalidDate(dateStr)
-			}
-
-			if sign == "-" {
-				num = -num
-			}
-
-			switch unit {
-			case "d":
-				baseDate = today.AddDate(0, 0, num)
-			case "w":
-				baseDate = today.AddDate(0, 0, num*7)
-			case "m":
-				baseDate = today.AddDate(0, num, 0)
-			}
-			matched = true
-		}
-	}
-
-	if !matched {
-		return nil, nil // Not a relative format
-	}
-
-	// Apply time component if present
-	if timePart != "" {
-		hour, minute, second := parseTimeComponent(timePart)
-		if hour < 0 {
-			return nil, ErrInvalidDate(dateStr)
-		}
-		baseDate = time.Date(baseDate.Year(), baseDate.Month(), baseDate.Day(), hour, minute, second, 0, time.Local)
-	}
-
-	return &baseDate, nil
-}
-
-// ParseDateFlag parses a date string supporting both relative and absolute formats.
-// Supported relative formats: today, tomorrow, yesterday, +Nd, -Nd, +Nw, +Nm
-// Relative formats also support time: tomorrow 14:30, +7d 09:00
+// ParseDateFlag parses a date string supporting natural, relative and absolute formats.
+// Natural and relative formats are those of ParseNaturalDate, e.g. today, friday,
+// next week, in 2 weeks, eom, jan 15, +7d; they also support a time: tomorrow 14:30.
 // Supported absolute formats:
 //   - YYYY-MM-DD (date only, midnight assumed)
+//   - YYYY-MM-DD HH:MM, YYYY-MM-DD 5pm (date and local time)
 //   - YYYY-MM-DDTHH:MM or YYYY-MM-DDTHH:MM:SS (datetime)
 //   - YYYY-MM-DDTHH:MM±HH:MM or YYYY-MM-DDTHH:MMZ (datetime with timezone)
+//   - MM/DD/YYYY or DD/MM/YYYY when the us or eu date format is configured (see SetDateInputFormat)
 //
 // Returns nil, nil for empty string (clear date).
 // Returns parsed time and nil for valid date.
@@ -138,8 +33,8 @@ func ParseDateFlag(dateStr string) (*time.Time, error) {
 		return nil, nil
 	}
 
-	// Try relative date first (handles time component via space separator)
-	t, err := parseRelativeDate(dateStr)
+	// Try natural and relative dates first (handles time component via space separator)
+	t, err := ParseNaturalDate(dateStr, tz.Now())
 	if err != nil {
 		return nil, err
 	}
@@ -166,17 +61,17 @@ func ParseDateFlag(dateStr string) (*time.Time, error) {
 	}
 
 	// ISO8601 datetime with seconds, local timezone (2026-01-20T14:30:00)
-	if parsed, err := time.ParseInLocation("2006-01-02T15:04:05", dateStr, time.Local); err == nil {
+	if parsed, err := time.ParseInLocation("2006-01-02T15:04:05", dateStr, tz.Location()); err == nil {
 		return &parsed, nil
 	}
 
 	// ISO8601 datetime without seconds, local timezone (2026-01-20T14:30)
-	if parsed, err := time.ParseInLocation("2006-01-02T15:04", dateStr, time.Local); err == nil {
+	if parsed, err := time.ParseInLocation("2006-01-02T15:04", dateStr, tz.Location()); err == nil {
 		return &parsed, nil
 	}
 
 	// Date only (2026-01-20)
-	if parsed, err := time.ParseInLocation("2006-01-02", dateStr, time.Local); err == nil {
+	if parsed, err := time.ParseInLocation("2006-01-02", dateStr, tz.Location()); err == nil {
 		return &parsed, nil
 	}
 
@@ -0,0 +1,100 @@
+package views
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// BundleKind identifies a view bundle file
+const BundleKind = "todoat-view"
+
+// BundleVersion is the version of the bundle format written by NewBundle
+const BundleVersion = 1
+
+// Bundle is a view packaged for sharing between machines or people, as
+// written by 'todoat view export'
+type Bundle struct {
+	Kind     string    `yaml:"kind"`
+	Version  int       `yaml:"version"`
+	Exported time.Time `yaml:"exported,omitempty"`
+	View     View      `yaml:"view"`
+}
+
+// NewBundle packages a view named name
+func NewBundle(name string, v *View, now time.Time) *Bundle {
+	view := *v
+	view.Name = name
+	return &Bundle{Kind: BundleKind, Version: BundleVersion, Exported: now.UTC(), View: view}
+}
+
+// ParseView parses and validates the YAML of a view. Unlike views loaded from
+// the views directory, unknown keys are rejected, so typos are caught when a
+// view is edited or imported.
+func ParseView(data []byte) (*View, error) {
+	var view View
+	if err := decodeStrict(data, &view); err != nil {
+		return nil, err
+	}
+	if err := (&Loader{}).validateView(&view); err != nil {
+		return nil, err
+	}
+	return &view, nil
+}
+
+// ParseBundle parses and validates a view bundle. A plain view YAML file is
+// accepted as well, so views copied out of a views directory can be imported.
+func ParseBundle(data []byte) (*View, error) {
+	var header struct {
+		Kind    string `yaml:"kind"`
+		Version int    `yaml:"version"`
+	}
+	if err := yaml.Unmarshal(data, &header); err != nil {
+		return nil, fmt.Errorf("invalid view bundle: %w", err)
+	}
+	if header.Kind == "" {
+		return ParseView(data)
+	}
+	if header.Kind != BundleKind {
+		return nil, fmt.Errorf("not a view bundle (kind %q)", header.Kind)
+	}
+	if header.Version > BundleVersion {
+		return nil, fmt.Errorf("view bundle version %d is newer than supported (%d); upgrade todoat", header.Version, BundleVersion)
+	}
+	var bundle Bundle
+	if err := decodeStrict(data, &bundle); err != nil {
+		return nil, err
+	}
+	if err := (&Loader{}).validateView(&bundle.View); err != nil {
+		return nil, err
+	}
+	return &bundle.View, nil
+}
+
+// PluginCommands returns the commands run by the plugin fields of a view
+func PluginCommands(v *View) []string {
+	var commands []string
+	for _, f := range v.Fields {
+		if f.Plugin != nil && f.Plugin.Command != "" {
+			commands = append(commands, f.Plugin.Command)
+		}
+	}
+	return commands
+}
+
+// decodeStrict decodes YAML into v, failing on keys v has no field for
+func decodeStrict(data []byte, v any) error {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(v); err != nil {
+		if errors.Is(err, io.EOF) {
+			return fmt.Errorf("view is empty")
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package views
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestBundleRoundTrip(t *testing.T) {
+	view := &View{
+		Name:    "old-name",
+		Fields:  []Field{{Name: "status"}, {Name: "summary", Width: 40}},
+		Filters: []Filter{{Field: "priority", Operator: "lte", Value: 3}},
+		Sort:    []SortRule{{Field: "due_date", Direction: "asc"}},
+	}
+	data, err := yaml.Marshal(NewBundle("urgent", view, time.Now()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := ParseBundle(data)
+	if err != nil {
+		t.Fatalf("ParseBundle: %v\n%s", err, data)
+	}
+	if parsed.Name != "urgent" || len(parsed.Fields) != 2 || len(parsed.Filters) != 1 || parsed.Sort[0].Field != "due_date" {
+		t.Errorf("ParseBundle = %+v", parsed)
+	}
+
+	// Plain view files are accepted too
+	plain, err := ParseBundle([]byte("name: plain\nfields:\n  - name: summary\n"))
+	if err != nil || plain.Name != "plain" {
+		t.Errorf("ParseBundle(plain view) = %+v, %v", plain, err)
+	}
+}
+
+func TestParseBundleRejectsInvalidViews(t *testing.T) {
+	tests := map[string]string{
+		"unknown key":   "name: x\nfields:\n  - name: summary\nfilter:\n  - field: status\n",
+		"unknown field": "name: x\nfields:\n  - name: nope\n",
+		"other kind":    "kind: todoat-config\nversion: 1\n",
+		"newer version": "kind: todoat-view\nversion: 99\nview:\n  fields:\n    - name: summary\n",
+		"empty":         "",
+	}
+	for name, data := range tests {
+		if _, err := ParseBundle([]byte(data)); err == nil {
+			t.Errorf("%s: ParseBundle succeeded, want error", name)
+		}
+	}
+	if _, err := ParseBundle([]byte("name: x\nfields:\n  - name: summary\nfilters:\n  - field: due_date\n    operator: lt\n    value: feb 30\n")); err == nil || !strings.Contains(err.Error(), "due_date") {
+		t.Errorf("ParseBundle with an invalid filter date = %v", err)
+	}
+}
+
+func TestPluginCommands(t *testing.T) {
+	view := &View{Fields: []Field{{Name: "summary"}, {Name: "status", Plugin: &PluginConfig{Command: "./fmt.sh"}}}}
+	if got := PluginCommands(view); len(got) != 1 || got[0] != "./fmt.sh" {
+		t.Errorf("PluginCommands = %v", got)
+	}
+}
@@ -2,6 +2,7 @@ package views_test
 
 import (
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -551,3 +552,114 @@ func TestViewWithCLIPriorityFilter(t *testing.T) {
 	testutil.AssertContains(t, stdout, "Urgent task")
 	testutil.AssertNotContains(t, stdout, "Low priority task") // This was failing before fix
 }
+
+// TestViewExportImportEditViewsCLI verifies that views round-trip through bundles,
+// that name collisions and plugin views need explicit flags in no-prompt mode, and
+// that 'view edit' only saves valid views
+func TestViewExportImportEditViewsCLI(t *testing.T) {
+	cli, viewsDir := testutil.NewCLITestWithViews(t)
+	cli.MustExecute("-y", "view", "create", "urgent", "--fields", "status,summary", "--sort", "priority:asc")
+
+	bundle := filepath.Join(t.TempDir(), "urgent.yaml")
+	cli.MustExecute("-y", "view", "export", "urgent", "-o", bundle)
+	stdout := cli.MustExecute("-y", "view", "export", "urgent")
+	testutil.AssertContains(t, stdout, "kind: todoat-view")
+
+	// -y alone turns off the collision prompt
+	cli.Config().NoPrompt = false
+	_, stderr := cli.ExecuteAndFail("-y", "view", "import", bundle)
+	testutil.AssertContains(t, stderr, "already exists")
+	testutil.AssertContains(t, cli.MustExecute("-y", "view", "import", bundle, "--name", "urgent2"), "Imported view 'urgent2'")
+	cli.MustExecute("-y", "view", "import", bundle, "--force")
+	if _, err := os.Stat(filepath.Join(viewsDir, "urgent2.yaml")); err != nil {
+		t.Errorf("imported view file missing: %v", err)
+	}
+
+	plugin := filepath.Join(t.TempDir(), "fancy.yaml")
+	if err := os.WriteFile(plugin, []byte("name: fancy\nfields:\n  - name: summary\n    plugin:\n      command: ./fmt.sh\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, stderr = cli.ExecuteAndFail("-y", "view", "import", plugin)
+	testutil.AssertContains(t, stderr, "--allow-plugins")
+	cli.MustExecute("-y", "view", "import", plugin, "--allow-plugins")
+
+	invalid := filepath.Join(t.TempDir(), "bad.yaml")
+	if err := os.WriteFile(invalid, []byte("name: bad\nfields:\n  - name: nope\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, stderr = cli.ExecuteAndFail("-y", "view", "import", invalid)
+	testutil.AssertContains(t, stderr, "unknown field: nope")
+
+	// The editor renames a field: an unknown field is rejected, a known one saved
+	editor := filepath.Join(t.TempDir(), "editor.sh")
+	writeEditor := func(from, to string) {
+		script := "#!/bin/sh\nsed -e 's/name: " + from + "/name: " + to + "/' \"$1\" > \"$1.tmp\" && mv \"$1.tmp\" \"$1\"\n"
+		if err := os.WriteFile(editor, []byte(script), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("EDITOR", editor)
+	writeEditor("status", "bogus")
+	_, stderr = cli.ExecuteAndFail("-y", "view", "edit", "urgent")
+	testutil.AssertContains(t, stderr, "unknown field: bogus")
+	writeEditor("status", "due_date")
+	testutil.AssertContains(t, cli.MustExecute("-y", "view", "edit", "urgent"), "Saved view 'urgent'")
+	data, err := os.ReadFile(filepath.Join(viewsDir, "urgent.yaml"))
+	if err != nil || !strings.Contains(string(data), "due_date") {
+		t.Errorf("edited view = %s, %v", data, err)
+	}
+}
+
+// TestViewShowCopyRenameDeleteViewsCLI verifies view management, and that built-in
+// views are copied instead of renamed and only their overrides can be deleted
+func TestViewShowCopyRenameDeleteViewsCLI(t *testing.T) {
+	cli, viewsDir := testutil.NewCLITestWithViews(t)
+
+	stdout := cli.MustExecute("-y", "view", "show", "default")
+	testutil.AssertContains(t, stdout, "# View 'default' (built-in)")
+	testutil.AssertContains(t, stdout, "name: summary")
+	testutil.AssertContains(t, cli.MustExecute("-y", "--json", "view", "show", "all"), `"source":"built-in"`)
+
+	cli.MustExecute("-y", "view", "copy", "default", "mine")
+	_, stderr := cli.ExecuteAndFail("-y", "view", "copy", "all", "mine")
+	testutil.AssertContains(t, stderr, "already exists")
+	cli.MustExecute("-y", "view", "copy", "all", "mine", "--force")
+	testutil.AssertContains(t, cli.MustExecute("-y", "view", "show", "mine"), "name: mine")
+
+	cli.MustExecute("-y", "view", "rename", "mine", "ours")
+	if _, err := os.Stat(filepath.Join(viewsDir, "mine.yaml")); !os.IsNotExist(err) {
+		t.Errorf("renamed view file still exists: %v", err)
+	}
+	testutil.AssertContains(t, cli.MustExecute("-y", "view", "rename", "default", "everyday"), "copied it to 'everyday'")
+	testutil.AssertContains(t, cli.MustExecute("-y", "view", "show", "default"), "(built-in)")
+
+	_, stderr = cli.ExecuteAndFail("-y", "view", "delete", "default")
+	testutil.AssertContains(t, stderr, "built-in and cannot be deleted")
+	cli.MustExecute("-y", "view", "copy", "all", "default", "--force")
+	testutil.AssertContains(t, cli.MustExecute("-y", "view", "delete", "default"), "Restored built-in view 'default'")
+	testutil.AssertContains(t, cli.MustExecute("-y", "view", "delete", "ours"), "Deleted view 'ours'")
+	_, stderr = cli.ExecuteAndFail("-y", "view", "delete", "ours")
+	testutil.AssertContains(t, stderr, "not found")
+}
+
+// TestViewDeletePromptCLI verifies 'view delete' asks before deleting unless -y
+// is given, and fails when the answer is no
+func TestViewDeletePromptCLI(t *testing.T) {
+	cli, viewsDir := testutil.NewCLITestWithViews(t)
+	cli.MustExecute("-y", "view", "copy", "default", "mine")
+	cli.Config().NoPrompt = false
+
+	stdout, stderr, exitCode := cli.ExecuteWithStdin("n\n", "view", "delete", "mine")
+	if exitCode == 0 {
+		t.Fatalf("declined delete should fail, got exit code 0:\n%s", stdout)
+	}
+	testutil.AssertContains(t, stdout, "Continue? [y/N]")
+	testutil.AssertContains(t, stderr, "cancelled")
+	if _, err := os.Stat(filepath.Join(viewsDir, "mine.yaml")); err != nil {
+		t.Fatalf("declined delete removed the view: %v", err)
+	}
+
+	stdout = cli.MustExecute("-y", "view", "delete", "mine")
+	testutil.AssertNotContains(t, stdout, "Continue?")
+	testutil.AssertContains(t, stdout, "Deleted view 'mine'")
+}
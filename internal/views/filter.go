@@ -3,11 +3,14 @@ package views
 import (
 	"fmt"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"todoat/backend"
+	"todoat/internal/tz"
+	"todoat/internal/utils"
 )
 
 // FilterTasks applies all filters to a list of tasks
@@ -25,6 +28,11 @@ func FilterTasks(tasks []backend.Task, filters []Filter) []backend.Task {
 	return result
 }
 
+// MatchesFilters reports whether a task matches all filters
+func MatchesFilters(t *backend.Task, filters []Filter) bool {
+	return matchesAllFilters(t, filters)
+}
+
 // matchesAllFilters checks if a task matches all filters (AND logic)
 func matchesAllFilters(t *backend.Task, filters []Filter) bool {
 	for _, f := range filters {
@@ -68,7 +76,20 @@ func getFieldValue(t *backend.Task, field string) any {
 		return t.ID
 	case "parent":
 		return t.ParentID
+	case "position":
+		// Tasks never reordered sort after the others
+		if t.Position == 0 {
+			return nil
+		}
+		return t.Position
+	case "pinned":
+		return t.Pinned
 	default:
+		if key, ok := strings.CutPrefix(field, MetaFieldPrefix); ok {
+			if v, ok := t.Metadata[key]; ok {
+				return v
+			}
+		}
 		return nil
 	}
 }
@@ -298,20 +319,15 @@ func SortTasks(tasks []backend.Task, rules []SortRule) []backend.Task {
 	return result
 }
 
-// sortTaskSlice sorts a slice of task pointers by sort rules
+// sortTaskSlice sorts a slice of task pointers by sort rules. The sort is
+// stable, so tasks the rules consider equal keep their manual order.
 func sortTaskSlice(tasks []*backend.Task, rules []SortRule) {
 	if len(tasks) <= 1 || len(rules) == 0 {
 		return
 	}
-
-	// Simple bubble sort for now (can optimize later)
-	for i := 0; i < len(tasks); i++ {
-		for j := i + 1; j < len(tasks); j++ {
-			if compareTasksForSort(tasks[i], tasks[j], rules) > 0 {
-				tasks[i], tasks[j] = tasks[j], tasks[i]
-			}
-		}
-	}
+	slices.SortStableFunc(tasks, func(a, b *backend.Task) int {
+		return compareTasksForSort(a, b, rules)
+	})
 }
 
 // compareTasksForSort compares two tasks according to sort rules
@@ -440,59 +456,28 @@ func toTime(v any) *time.Time {
 	case time.Time:
 		return &val
 	case string:
-		if t, err := time.Parse(DefaultDateFormat, val); err == nil {
+		if t, err := time.ParseInLocation(DefaultDateFormat, val, tz.Location()); err == nil {
 			return &t
 		}
 	}
 	return nil
 }
 
-// parseFilterDate parses a filter date value which can be a date string or relative date
+// parseFilterDate parses a filter date value which can be a date string or a
+// natural or relative date (see utils.ParseNaturalDate)
 func parseFilterDate(v any) *time.Time {
 	str := toString(v)
 	if str == "" {
 		return nil
 	}
 
-	// Handle relative dates
-	now := time.Now().Truncate(24 * time.Hour)
-
-	switch strings.ToLower(str) {
-	case "today":
-		return &now
-	case "tomorrow":
-		t := now.AddDate(0, 0, 1)
-		return &t
-	case "yesterday":
-		t := now.AddDate(0, 0, -1)
-		return &t
-	}
-
-	// Handle +Nd, -Nd, +Nw, +Nm formats
-	if len(str) > 1 && (str[0] == '+' || str[0] == '-') {
-		sign := 1
-		if str[0] == '-' {
-			sign = -1
-		}
-		numStr := str[1 : len(str)-1]
-		unit := str[len(str)-1]
-		if n, err := strconv.Atoi(numStr); err == nil {
-			switch unit {
-			case 'd', 'D':
-				t := now.AddDate(0, 0, sign*n)
-				return &t
-			case 'w', 'W':
-				t := now.AddDate(0, 0, sign*n*7)
-				return &t
-			case 'm', 'M':
-				t := now.AddDate(0, sign*n, 0)
-				return &t
-			}
-		}
+	// Handle natural and relative dates (today, friday, in 2 weeks, +7d, eom, ...)
+	if t, err := utils.ParseNaturalDate(str, tz.Now()); err == nil && t != nil {
+		return t
 	}
 
 	// Try to parse as absolute date
-	if t, err := time.Parse(DefaultDateFormat, str); err == nil {
+	if t, err := time.ParseInLocation(DefaultDateFormat, str, tz.Location()); err == nil {
 		return &t
 	}
 
@@ -5,6 +5,9 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
+
+	"todoat/internal/utils"
 
 	"gopkg.in/yaml.v3"
 )
@@ -36,6 +39,7 @@ fields:
     width: 20
   - name: recurrence
     width: 5
+  - name: progress
 filters:
   - field: status
     operator: ne
@@ -61,6 +65,7 @@ fields:
   - name: tags
   - name: uid
   - name: parent
+  - name: progress
 `
 	if err := os.WriteFile(filepath.Join(viewsDir, "all.yaml"), []byte(allYAML), 0644); err != nil {
 		return false, err
@@ -337,30 +342,22 @@ func (l *Loader) validateView(v *View) error {
 		return fmt.Errorf("view must have at least one field")
 	}
 
-	validFields := make(map[string]bool)
-	for _, f := range AvailableFields {
-		validFields[f] = true
-	}
-
 	for _, f := range v.Fields {
-		if !validFields[f.Name] {
+		if !IsDisplayField(f.Name) {
 			return fmt.Errorf("unknown field: %s", f.Name)
 		}
 	}
 
 	// Validate filters
 	for _, filter := range v.Filters {
-		if !validFields[filter.Field] {
-			return fmt.Errorf("unknown filter field: %s", filter.Field)
-		}
-		if !isValidOperator(filter.Operator) {
-			return fmt.Errorf("invalid operator: %s", filter.Operator)
+		if err := ValidateFilter(filter); err != nil {
+			return err
 		}
 	}
 
 	// Validate sort rules
 	for _, sort := range v.Sort {
-		if !validFields[sort.Field] {
+		if !IsValidField(sort.Field) {
 			return fmt.Errorf("unknown sort field: %s", sort.Field)
 		}
 		dir := strings.ToLower(sort.Direction)
@@ -369,6 +366,44 @@ func (l *Loader) validateView(v *View) error {
 		}
 	}
 
+	if v.PageSize < 0 {
+		return fmt.Errorf("invalid page_size: %d (must be 0 or greater)", v.PageSize)
+	}
+
+	return nil
+}
+
+// ParseSortRule parses a sort rule in "field:direction" form; the direction defaults to asc
+func ParseSortRule(s string) (SortRule, error) {
+	field, direction, _ := strings.Cut(strings.TrimSpace(s), ":")
+	field = strings.TrimSpace(field)
+	direction = strings.ToLower(strings.TrimSpace(direction))
+	if direction == "" {
+		direction = "asc"
+	}
+	if !IsValidField(field) {
+		return SortRule{}, fmt.Errorf("unknown sort field: %s", field)
+	}
+	if direction != "asc" && direction != "desc" {
+		return SortRule{}, fmt.Errorf("invalid sort direction: %s (must be 'asc' or 'desc')", direction)
+	}
+	return SortRule{Field: field, Direction: direction}, nil
+}
+
+// ValidateFilter checks that a filter uses a known field and operator
+func ValidateFilter(f Filter) error {
+	if !IsValidField(f.Field) {
+		return fmt.Errorf("unknown filter field: %s", f.Field)
+	}
+	if !isValidOperator(f.Operator) {
+		return fmt.Errorf("invalid operator: %s", f.Operator)
+	}
+	// Invalid and, in strict mode, ambiguous dates fail here rather than match nothing
+	if value, ok := f.Value.(string); ok && isDateField(f.Field) {
+		if _, err := utils.ParseNaturalDate(value, time.Now()); err != nil {
+			return fmt.Errorf("filter %s: %w", f.Field, err)
+		}
+	}
 	return nil
 }
 
@@ -0,0 +1,416 @@
+package views
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+
+	"todoat/backend"
+	"todoat/internal/tz"
+)
+
+// OutputOptions configures an output renderer
+type OutputOptions struct {
+	View     *View
+	Progress map[string]Progress // Subtask progress by task ID, for the "progress" field
+	Template string              // Go text/template executed per task by the template format
+	Theme    *Theme              // Colors for the text and table formats; nil renders plain text
+	Dates    DateDisplay         // Date display of the text format
+	Hidden   map[string]int      // Collapsed subtasks by task ID, as returned by LimitDepth
+}
+
+// OutputRenderer writes a list of tasks in one output format. Tasks are expected
+// to be filtered and sorted already, with subtasks following their parents.
+type OutputRenderer interface {
+	Render(w io.Writer, tasks []backend.Task) error
+}
+
+// OutputRendererFactory creates an output renderer for the given options
+type OutputRendererFactory func(opts OutputOptions) (OutputRenderer, error)
+
+// outputRenderers holds the registered output formats by name
+var outputRenderers = map[string]OutputRendererFactory{
+	"text":     newTextOutput,
+	"table":    newTableOutput,
+	"tsv":      newTSVOutput,
+	"markdown": newMarkdownOutput,
+	"template": newTemplateOutput,
+}
+
+// RegisterOutputRenderer adds or replaces an output format
+func RegisterOutputRenderer(name string, factory OutputRendererFactory) {
+	outputRenderers[name] = factory
+}
+
+// OutputFormats returns the names of the registered output formats, sorted
+func OutputFormats() []string {
+	names := make([]string, 0, len(outputRenderers))
+	for name := range outputRenderers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewOutputRenderer creates the renderer for a registered output format
+func NewOutputRenderer(format string, opts OutputOptions) (OutputRenderer, error) {
+	factory, ok := outputRenderers[format]
+	if !ok {
+		return nil, fmt.Errorf("unknown output format: %s (valid: %s)", format, strings.Join(OutputFormats(), ", "))
+	}
+	if opts.View == nil {
+		opts.View = DefaultView()
+	}
+	return factory(opts)
+}
+
+// textOutput is the human-readable tree output of Renderer
+type textOutput struct{ opts OutputOptions }
+
+func newTextOutput(opts OutputOptions) (OutputRenderer, error) {
+	return &textOutput{opts: opts}, nil
+}
+
+func (o *textOutput) Render(w io.Writer, tasks []backend.Task) error {
+	NewRenderer(o.opts.View, w).WithProgress(o.opts.Progress).WithTheme(o.opts.Theme).WithDates(o.opts.Dates).WithHidden(o.opts.Hidden).Render(tasks)
+	return nil
+}
+
+// tableOutput writes the view's fields as aligned columns under a header row
+type tableOutput struct{ opts OutputOptions }
+
+func newTableOutput(opts OutputOptions) (OutputRenderer, error) {
+	return &tableOutput{opts: opts}, nil
+}
+
+// Render aligns the columns itself rather than with text/tabwriter so that
+// theme colors can be applied after padding without skewing the widths.
+func (o *tableOutput) Render(w io.Writer, tasks []backend.Task) error {
+	fields := o.opts.View.Fields
+	rows := make([][]string, 0, len(tasks)+1)
+	header := make([]string, len(fields))
+	for i, f := range fields {
+		header[i] = strings.ToUpper(f.Name)
+	}
+	rows = append(rows, header)
+	depths := taskDepths(tasks)
+	prefixes := TreePrefixes(tasks)
+	for i := range tasks {
+		t := &tasks[i]
+		row := make([]string, len(fields))
+		for j, f := range fields {
+			value := singleLine(plainFieldValue(t, f, o.opts.Progress))
+			switch f.Name {
+			case "summary":
+				value = strings.Repeat("  ", depths[t.ID]) + value + HiddenMarker(o.opts.Hidden[t.ID])
+			case TreeField:
+				value = prefixes[t.ID] + value + HiddenMarker(o.opts.Hidden[t.ID])
+			}
+			row[j] = value
+		}
+		rows = append(rows, row)
+	}
+
+	widths := make([]int, len(fields))
+	for _, row := range rows {
+		for j, cell := range row {
+			widths[j] = max(widths[j], utf8.RuneCountInString(cell))
+		}
+	}
+
+	now := tz.Now()
+	theme := o.opts.Theme
+	for i, row := range rows {
+		var sb strings.Builder
+		for j, cell := range row {
+			if j < len(row)-1 {
+				cell += strings.Repeat(" ", widths[j]-utf8.RuneCountInString(cell)+2)
+			}
+			if i == 0 {
+				cell = theme.Paint(cell, theme.HeaderStyle())
+			} else {
+				cell = theme.Paint(cell, theme.fieldStyle(&tasks[i-1], fields[j].Name, now))
+			}
+			sb.WriteString(cell)
+		}
+		if _, err := fmt.Fprintln(w, sb.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tsvOutput writes the view's fields as tab-separated values for awk and cut
+type tsvOutput struct{ opts OutputOptions }
+
+func newTSVOutput(opts OutputOptions) (OutputRenderer, error) {
+	return &tsvOutput{opts: opts}, nil
+}
+
+func (o *tsvOutput) Render(w io.Writer, tasks []backend.Task) error {
+	fields := o.opts.View.Fields
+	header := make([]string, len(fields))
+	for i, f := range fields {
+		header[i] = f.Name
+	}
+	if _, err := fmt.Fprintln(w, strings.Join(header, "\t")); err != nil {
+		return err
+	}
+	prefixes := TreePrefixes(tasks)
+	for i := range tasks {
+		row := make([]string, len(fields))
+		for j, f := range fields {
+			value := plainFieldValue(&tasks[i], f, o.opts.Progress)
+			if f.Name == TreeField {
+				value = prefixes[tasks[i].ID] + value
+			}
+			row[j] = strings.ReplaceAll(singleLine(value), "\t", " ")
+		}
+		if _, err := fmt.Fprintln(w, strings.Join(row, "\t")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// markdownOutput writes a Markdown task list, with subtasks nested under their parents
+type markdownOutput struct{ opts OutputOptions }
+
+func newMarkdownOutput(opts OutputOptions) (OutputRenderer, error) {
+	return &markdownOutput{opts: opts}, nil
+}
+
+func (o *markdownOutput) Render(w io.Writer, tasks []backend.Task) error {
+	depths := taskDepths(tasks)
+	for i := range tasks {
+		t := &tasks[i]
+		box := "[ ]"
+		summary := t.Summary
+		switch t.Status {
+		case backend.StatusCompleted:
+			box = "[x]"
+		case backend.StatusCancelled:
+			box = "[x]"
+			summary = "~~" + summary + "~~"
+		}
+
+		var details []string
+		for _, f := range o.opts.View.Fields {
+			if f.Name == "status" || f.Name == "summary" || f.Name == TreeField || f.Name == "description" {
+				continue
+			}
+			value := singleLine(plainFieldValue(t, f, o.opts.Progress))
+			if value == "" {
+				continue
+			}
+			switch f.Name {
+			case "priority":
+				details = append(details, "P"+value)
+			case "due_date":
+				details = append(details, "due "+value)
+			case "start_date":
+				details = append(details, "starts "+value)
+			case "tags":
+				for _, tag := range strings.Split(value, ",") {
+					details = append(details, "#"+strings.TrimSpace(tag))
+				}
+			case "recurrence":
+				details = append(details, "repeats")
+			case "progress":
+				details = append(details, value+" done")
+			case "pinned":
+				details = append(details, "pinned")
+			default:
+				details = append(details, strings.TrimPrefix(f.Name, MetaFieldPrefix)+": "+value)
+			}
+		}
+
+		line := fmt.Sprintf("%s- %s %s%s", strings.Repeat("  ", depths[t.ID]), box, summary, HiddenMarker(o.opts.Hidden[t.ID]))
+		if len(details) > 0 {
+			line += " (" + strings.Join(details, ", ") + ")"
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TemplateTask is the data a template output receives for each task
+type TemplateTask struct {
+	UID         string
+	Summary     string
+	Description string
+	Status      string
+	Priority    int
+	DueDate     string
+	StartDate   string
+	Completed   string
+	Created     string
+	Modified    string
+	Tags        []string
+	Parent      string
+	Recurrence  string
+	Progress    string
+	Depth       int
+	Tree        string // Connectors drawn before the summary in tree output
+	Hidden      int    // Subtasks collapsed under the task by --depth
+	Metadata    map[string]string
+}
+
+// templateOutput executes a Go text/template once per task
+type templateOutput struct {
+	opts OutputOptions
+	tmpl *template.Template
+}
+
+func newTemplateOutput(opts OutputOptions) (OutputRenderer, error) {
+	if opts.Template == "" {
+		return nil, fmt.Errorf("template output requires a template")
+	}
+	tmpl, err := template.New("task").Option("missingkey=zero").Parse(opts.Template)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	return &templateOutput{opts: opts, tmpl: tmpl}, nil
+}
+
+func (o *templateOutput) Render(w io.Writer, tasks []backend.Task) error {
+	depths := taskDepths(tasks)
+	prefixes := TreePrefixes(tasks)
+	for i := range tasks {
+		t := &tasks[i]
+		data := TemplateTask{
+			UID:         t.ID,
+			Summary:     t.Summary,
+			Description: t.Description,
+			Status:      StatusToString(t.Status),
+			Priority:    t.Priority,
+			DueDate:     formatDateForJSON(t.DueDate),
+			StartDate:   formatDateForJSON(t.StartDate),
+			Completed:   formatDateForJSON(t.Completed),
+			Created:     formatDateTime(t.Created, ""),
+			Modified:    formatDateTime(t.Modified, ""),
+			Parent:      t.ParentID,
+			Recurrence:  t.Recurrence,
+			Depth:       depths[t.ID],
+			Tree:        prefixes[t.ID],
+			Hidden:      o.opts.Hidden[t.ID],
+			Metadata:    t.Metadata,
+		}
+		for _, tag := range strings.Split(t.Categories, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				data.Tags = append(data.Tags, tag)
+			}
+		}
+		if p, ok := o.opts.Progress[t.ID]; ok {
+			data.Progress = p.String()
+		}
+
+		var sb strings.Builder
+		if err := o.tmpl.Execute(&sb, data); err != nil {
+			return fmt.Errorf("template failed for task %q: %w", t.Summary, err)
+		}
+		out := sb.String()
+		if !strings.HasSuffix(out, "\n") {
+			out += "\n"
+		}
+		if _, err := io.WriteString(w, out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// plainFieldValue returns a field's value without display decorations, for
+// machine-readable and document output. Dates use ISO format unless the field
+// sets its own.
+func plainFieldValue(t *backend.Task, field Field, progress map[string]Progress) string {
+	switch field.Name {
+	case "status":
+		return StatusToString(t.Status)
+	case "summary", TreeField:
+		return t.Summary
+	case "description":
+		return t.Description
+	case "priority":
+		if t.Priority > 0 {
+			return strconv.Itoa(t.Priority)
+		}
+		return ""
+	case "due_date", "start_date", "completed":
+		value := t.DueDate
+		if field.Name == "start_date" {
+			value = t.StartDate
+		} else if field.Name == "completed" {
+			value = t.Completed
+		}
+		if field.Format != "" {
+			return formatDate(value, field.Format)
+		}
+		return formatDateForJSON(value)
+	case "created":
+		return formatDateTime(t.Created, field.Format)
+	case "modified":
+		return formatDateTime(t.Modified, field.Format)
+	case "tags":
+		return t.Categories
+	case "uid":
+		return t.ID
+	case "parent":
+		return t.ParentID
+	case "recurrence":
+		return t.Recurrence
+	case "position":
+		if t.Position > 0 {
+			return strconv.Itoa(t.Position)
+		}
+		return ""
+	case "pinned":
+		if t.Pinned {
+			return "pinned"
+		}
+		return ""
+	case "progress":
+		if p, ok := progress[t.ID]; ok {
+			return p.String()
+		}
+		return ""
+	default:
+		if key, ok := strings.CutPrefix(field.Name, MetaFieldPrefix); ok {
+			return t.Metadata[key]
+		}
+		return ""
+	}
+}
+
+// taskDepths returns the nesting level of each task among the given tasks;
+// tasks whose parent is not in the list are at level 0
+func taskDepths(tasks []backend.Task) map[string]int {
+	parents := make(map[string]string, len(tasks))
+	for _, t := range tasks {
+		parents[t.ID] = t.ParentID
+	}
+	depths := make(map[string]int, len(tasks))
+	for _, t := range tasks {
+		depth := 0
+		for id, seen := t.ParentID, map[string]bool{t.ID: true}; id != "" && !seen[id]; id = parents[id] {
+			if _, ok := parents[id]; !ok {
+				break
+			}
+			seen[id] = true
+			depth++
+		}
+		depths[t.ID] = depth
+	}
+	return depths
+}
+
+// singleLine replaces line breaks with spaces so a value fits in one row
+func singleLine(s string) string {
+	return strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(s)
+}
@@ -236,6 +236,88 @@ func TestPaginationPageSize(t *testing.T) {
 	testutil.AssertContains(t, stdout, "Showing 16-30 of 50 tasks")
 }
 
+// TestPaginationViewPageSize verifies that a view's page_size applies when no pagination flags are passed
+func TestPaginationViewPageSize(t *testing.T) {
+	cli, viewsDir := testutil.NewCLITestWithViews(t)
+
+	viewYAML := `name: paged
+fields:
+  - name: status
+  - name: summary
+page_size: 5
+stable_sort: true
+`
+	if err := os.WriteFile(viewsDir+"/paged.yaml", []byte(viewYAML), 0644); err != nil {
+		t.Fatalf("failed to write view file: %v", err)
+	}
+
+	cli.MustExecute("-y", "list", "create", "PaginationViewTest")
+	for i := 1; i <= 12; i++ {
+		cli.MustExecute("-y", "PaginationViewTest", "add", "Task "+padNumber(i))
+	}
+
+	// No pagination flags: first page of the view's size
+	stdout := cli.MustExecute("-y", "PaginationViewTest", "-v", "paged")
+	testutil.AssertContains(t, stdout, "Showing 1-5 of 12 tasks")
+
+	// Explicit flags take precedence over the view's page size
+	stdout = cli.MustExecute("-y", "PaginationViewTest", "-v", "paged", "--page", "3")
+	testutil.AssertContains(t, stdout, "Showing 11-12 of 12 tasks")
+	stdout = cli.MustExecute("-y", "PaginationViewTest", "-v", "paged", "--page-size", "10")
+	testutil.AssertContains(t, stdout, "Showing 1-10 of 12 tasks")
+	stdout = cli.MustExecute("-y", "PaginationViewTest", "-v", "paged", "--limit", "20")
+	testutil.AssertContains(t, stdout, "Showing 1-12 of 12 tasks")
+
+	// Views without page_size are not paginated
+	stdout = cli.MustExecute("-y", "PaginationViewTest")
+	testutil.AssertNotContains(t, stdout, "Showing")
+}
+
+// TestPaginationJSONAggregates verifies that --aggregates counts every matching
+// task, not only those of the page
+func TestPaginationJSONAggregates(t *testing.T) {
+	cli := testutil.NewCLITest(t)
+
+	cli.MustExecute("-y", "list", "create", "AggregatesTest")
+	for i := 1; i <= 8; i++ {
+		cli.MustExecute("-y", "AggregatesTest", "add", "Task "+padNumber(i))
+	}
+	cli.MustExecute("-y", "AggregatesTest", "update", "Task 001", "--due-date", "2020-01-15")
+	cli.MustExecute("-y", "AggregatesTest", "update", "Task 002", "--due-date", "2020-01-10", "-s", "DONE")
+	cli.MustExecute("-y", "AggregatesTest", "update", "Task 003", "--due-date", "2999-06-01", "-s", "IN-PROGRESS")
+
+	stdout := cli.MustExecute("-y", "AggregatesTest", "-v", "all", "--limit", "3", "--json", "--aggregates")
+	var response struct {
+		Count      int `json:"count"`
+		Aggregates struct {
+			Total       int            `json:"total"`
+			ByStatus    map[string]int `json:"by_status"`
+			Overdue     int            `json:"overdue"`
+			EarliestDue string         `json:"earliest_due"`
+		} `json:"aggregates"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(stdout)), &response); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nOutput: %s", err, stdout)
+	}
+	agg := response.Aggregates
+	if response.Count != 3 || agg.Total != 8 {
+		t.Errorf("count=%d total=%d, want 3 and 8", response.Count, agg.Total)
+	}
+	if agg.ByStatus["TODO"] != 6 || agg.ByStatus["DONE"] != 1 || agg.ByStatus["IN-PROGRESS"] != 1 {
+		t.Errorf("by_status = %v", agg.ByStatus)
+	}
+	// The completed task due earlier is neither overdue nor the earliest due date
+	if agg.Overdue != 1 || agg.EarliestDue != "2020-01-15" {
+		t.Errorf("overdue=%d earliest_due=%q, want 1 and 2020-01-15", agg.Overdue, agg.EarliestDue)
+	}
+
+	// Without --aggregates the block is left out
+	stdout = cli.MustExecute("-y", "AggregatesTest", "--json")
+	testutil.AssertNotContains(t, stdout, "aggregates")
+	_, stderr := cli.ExecuteAndFail("-y", "AggregatesTest", "--aggregates")
+	testutil.AssertContains(t, stderr, "--aggregates requires")
+}
+
 // padNumber pads a number to 3 digits for consistent sorting (e.g., 001, 002, ...)
 func padNumber(n int) string {
 	return string([]byte{
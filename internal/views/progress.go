@@ -0,0 +1,60 @@
+package views
+
+import (
+	"fmt"
+
+	"todoat/backend"
+)
+
+// Progress is the completion of a parent task's subtasks
+type Progress struct {
+	Done  int `json:"done"`
+	Total int `json:"total"`
+}
+
+// String formats progress as "done/total"
+func (p Progress) String() string {
+	return fmt.Sprintf("%d/%d", p.Done, p.Total)
+}
+
+// SubtaskProgress computes the progress of every task that has subtasks, keyed by task ID.
+// All descendants are counted, not only direct children; cancelled subtasks are left out.
+// Pass the complete task list so subtasks hidden by filters are still counted.
+func SubtaskProgress(tasks []backend.Task) map[string]Progress {
+	childMap := make(map[string][]*backend.Task)
+	for i := range tasks {
+		if tasks[i].ParentID != "" {
+			childMap[tasks[i].ParentID] = append(childMap[tasks[i].ParentID], &tasks[i])
+		}
+	}
+
+	progress := make(map[string]Progress)
+	for parentID := range childMap {
+		var p Progress
+		visited := map[string]bool{parentID: true}
+		queue := []string{parentID}
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			for _, child := range childMap[current] {
+				if visited[child.ID] {
+					continue // Guard against parent cycles
+				}
+				visited[child.ID] = true
+				queue = append(queue, child.ID)
+				switch child.Status {
+				case backend.StatusCancelled:
+				case backend.StatusCompleted:
+					p.Done++
+					p.Total++
+				default:
+					p.Total++
+				}
+			}
+		}
+		if p.Total > 0 {
+			progress[parentID] = p
+		}
+	}
+	return progress
+}
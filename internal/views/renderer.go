@@ -9,17 +9,36 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"todoat/backend"
 	"todoat/internal/config"
+	"todoat/internal/tz"
+	"todoat/internal/utils"
 )
 
 // Renderer handles rendering tasks using a view configuration
 type Renderer struct {
-	view   *View
-	writer io.Writer
+	view        *View
+	writer      io.Writer
+	progress    map[string]Progress // Subtask progress by task ID, for the "progress" field
+	theme       *Theme              // Colors for terminal output; nil renders plain text
+	dates       DateDisplay         // Display of dates for fields without their own format
+	hidden      map[string]int      // Collapsed subtasks by task ID, noted after the summary
+	syncErrors  map[string]bool     // Task IDs whose push failed, marked after the summary
+	tree        map[string]string   // Connectors of the "tree" field by task ID
+	numbered    bool                // Start each line with its row number
+	numberWidth int                 // Digits of the highest row number
+	rows        []string            // Task IDs in the order they were rendered
+}
+
+// DateDisplay controls how the text renderer shows dates of fields that have no
+// format of their own
+type DateDisplay struct {
+	Layout   string // Go layout for the date part; empty keeps "Jan 02"
+	Relative bool   // Show dates relative to today ("in 3 days", "2d overdue")
 }
 
 // NewRenderer creates a new view renderer
@@ -27,6 +46,52 @@ func NewRenderer(view *View, writer io.Writer) *Renderer {
 	return &Renderer{view: view, writer: writer}
 }
 
+// WithProgress sets the subtask progress shown by the "progress" field.
+// Compute it with SubtaskProgress over all tasks, not only the rendered ones.
+func (r *Renderer) WithProgress(progress map[string]Progress) *Renderer {
+	r.progress = progress
+	return r
+}
+
+// WithTheme colors priorities, due dates and statuses with the given theme.
+// A nil theme renders plain text.
+func (r *Renderer) WithTheme(theme *Theme) *Renderer {
+	r.theme = theme
+	return r
+}
+
+// WithDates sets the display of dates for fields without their own format
+func (r *Renderer) WithDates(dates DateDisplay) *Renderer {
+	r.dates = dates
+	return r
+}
+
+// WithHidden notes after each summary how many subtasks are collapsed under
+// the task, as returned by LimitDepth
+func (r *Renderer) WithHidden(hidden map[string]int) *Renderer {
+	r.hidden = hidden
+	return r
+}
+
+// WithSyncErrors marks the summary of the tasks whose last push to the remote
+// backend failed for good
+func (r *Renderer) WithSyncErrors(ids map[string]bool) *Renderer {
+	r.syncErrors = ids
+	return r
+}
+
+// WithRowNumbers starts each line with its row number, counted from 1 in the
+// order lines are rendered. Rows returns the task of each row.
+func (r *Renderer) WithRowNumbers(numbered bool) *Renderer {
+	r.numbered = numbered
+	return r
+}
+
+// Rows returns the IDs of the rendered tasks in display order
+func (r *Renderer) Rows() []string {
+	return r.rows
+}
+
 // Render renders tasks according to the view configuration
 // NOTE: Filtering and sorting are expected to be done BEFORE calling Render().
 // The renderer only handles visual formatting and hierarchy display.
@@ -37,6 +102,10 @@ func (r *Renderer) Render(tasks []backend.Task) {
 	}
 
 	// Render with hierarchy - filtering and sorting is done by the caller
+	if r.view.hasField(TreeField) {
+		r.tree = TreePrefixes(tasks)
+	}
+	r.numberWidth = len(fmt.Sprint(len(tasks)))
 	r.renderWithHierarchy(tasks)
 }
 
@@ -91,9 +160,11 @@ func (r *Renderer) renderNode(node *taskNode, prefix string, isLast bool) {
 		parts = append(parts, val)
 	}
 
-	// Tree character
+	// Tree character; a "tree" field draws the connectors itself
 	var treeChar string
-	if prefix == "" {
+	if r.tree != nil {
+		prefix, treeChar = "", "  "
+	} else if prefix == "" {
 		treeChar = "  "
 	} else if isLast {
 		treeChar = "└─ "
@@ -102,7 +173,12 @@ func (r *Renderer) renderNode(node *taskNode, prefix string, isLast bool) {
 	}
 
 	line := strings.Join(parts, " ")
-	_, _ = fmt.Fprintf(r.writer, "%s%s%s\n", prefix, treeChar, line)
+	r.rows = append(r.rows, node.task.ID)
+	lead := prefix + treeChar
+	if r.numbered {
+		lead = fmt.Sprintf("%*d %s", r.numberWidth, len(r.rows), lead)
+	}
+	_, _ = fmt.Fprintf(r.writer, "%s%s\n", lead, line)
 
 	// Children prefix
 	var childPrefix string
@@ -137,7 +213,9 @@ func (r *Renderer) formatField(t *backend.Task, field Field) string {
 		case "status":
 			value = formatStatus(t.Status)
 		case "summary":
-			value = t.Summary
+			value = t.Summary + HiddenMarker(r.hidden[t.ID]) + r.syncErrorMarker(t.ID)
+		case TreeField:
+			value = r.tree[t.ID] + t.Summary + HiddenMarker(r.hidden[t.ID]) + r.syncErrorMarker(t.ID)
 		case "description":
 			value = t.Description
 		case "priority":
@@ -145,15 +223,16 @@ func (r *Renderer) formatField(t *backend.Task, field Field) string {
 				value = fmt.Sprintf("[P%d]", t.Priority)
 			}
 		case "due_date":
-			value = formatDate(t.DueDate, field.Format)
+			open := t.Status != backend.StatusCompleted && t.Status != backend.StatusCancelled
+			value = r.displayDate(t.DueDate, field.Format, open)
 		case "start_date":
-			value = formatDate(t.StartDate, field.Format)
+			value = r.displayDate(t.StartDate, field.Format, false)
 		case "created":
 			value = formatDateTime(t.Created, field.Format)
 		case "modified":
 			value = formatDateTime(t.Modified, field.Format)
 		case "completed":
-			value = formatDate(t.Completed, field.Format)
+			value = r.displayDate(t.Completed, field.Format, false)
 		case "tags":
 			if t.Categories != "" {
 				value = fmt.Sprintf("{%s}", t.Categories)
@@ -166,6 +245,22 @@ func (r *Renderer) formatField(t *backend.Task, field Field) string {
 			if t.Recurrence != "" {
 				value = "[R]"
 			}
+		case "position":
+			if t.Position > 0 {
+				value = strconv.Itoa(t.Position)
+			}
+		case "pinned":
+			if t.Pinned {
+				value = "[pin]"
+			}
+		case "progress":
+			if p, ok := r.progress[t.ID]; ok {
+				value = "[" + p.String() + "]"
+			}
+		default:
+			if key, ok := strings.CutPrefix(field.Name, MetaFieldPrefix); ok {
+				value = t.Metadata[key]
+			}
 		}
 	}
 
@@ -188,6 +283,44 @@ func (r *Renderer) formatField(t *backend.Task, field Field) string {
 		}
 	}
 
+	// Color after padding so escape sequences don't count toward the width
+	return r.theme.Paint(value, r.theme.fieldStyle(t, field.Name, tz.Now()))
+}
+
+// SyncErrorMarker follows the summary of tasks whose push failed
+const SyncErrorMarker = " [sync error]"
+
+func (r *Renderer) syncErrorMarker(id string) string {
+	if r.syncErrors[id] {
+		return SyncErrorMarker
+	}
+	return ""
+}
+
+// displayDate formats a date for the text renderer. A field's own format wins;
+// otherwise dates are shown relative to today or with the configured layout.
+// Past due dates of open tasks show as overdue in relative mode.
+func (r *Renderer) displayDate(d *time.Time, format string, due bool) string {
+	if d == nil || format != "" || (!r.dates.Relative && r.dates.Layout == "") {
+		return formatDate(d, format)
+	}
+
+	local := d.In(tz.Location())
+	d = &local
+	var value string
+	if r.dates.Relative {
+		now := tz.Now()
+		if days := utils.CalendarDaysBetween(now, *d); due && days < 0 {
+			value = fmt.Sprintf("%dd overdue", -days)
+		} else {
+			value = utils.FormatRelativeDate(*d, now)
+		}
+	} else {
+		value = d.Format(r.dates.Layout)
+	}
+	if hasTimeComponent(*d) {
+		value += " " + d.Format("15:04")
+	}
 	return value
 }
 
@@ -201,7 +334,7 @@ func formatStatus(status backend.TaskStatus) string {
 	case backend.StatusCancelled:
 		return "[CANCELLED]"
 	default:
-		return "[TODO]"
+		return "[" + status.Name() + "]"
 	}
 }
 
@@ -216,10 +349,11 @@ func formatDateForJSON(t *time.Time) string {
 	if t == nil {
 		return ""
 	}
-	if hasTimeComponent(*t) {
-		return t.Format(time.RFC3339)
+	local := t.In(tz.Location())
+	if hasTimeComponent(local) {
+		return local.Format(time.RFC3339)
 	}
-	return t.Format(DefaultDateFormat)
+	return local.Format(DefaultDateFormat)
 }
 
 // formatDate formats a date pointer for display
@@ -228,16 +362,17 @@ func formatDate(t *time.Time, format string) string {
 	if t == nil {
 		return ""
 	}
+	local := t.In(tz.Location())
 	if format == "" {
 		// Use user-friendly format (matches old default behavior)
 		// Shows time if present, otherwise date only
-		if hasTimeComponent(*t) {
+		if hasTimeComponent(local) {
 			format = "Jan 02 15:04"
 		} else {
 			format = "Jan 02"
 		}
 	}
-	return t.Format(format)
+	return local.Format(format)
 }
 
 // formatDateTime formats a time.Time value for display
@@ -248,7 +383,7 @@ func formatDateTime(t time.Time, format string) string {
 	if format == "" {
 		format = DefaultDateFormat
 	}
-	return t.Format(format)
+	return t.In(tz.Location()).Format(format)
 }
 
 // RenderTasksWithView is a convenience function for rendering tasks with a view
@@ -259,18 +394,19 @@ func RenderTasksWithView(tasks []backend.Task, view *View, writer io.Writer) {
 
 // pluginTaskData represents the JSON data sent to plugin stdin
 type pluginTaskData struct {
-	UID         string  `json:"uid"`
-	Summary     string  `json:"summary"`
-	Description string  `json:"description,omitempty"`
-	Status      string  `json:"status"`
-	Priority    int     `json:"priority"`
-	DueDate     *string `json:"due_date,omitempty"`
-	StartDate   *string `json:"start_date,omitempty"`
-	Created     string  `json:"created"`
-	Modified    string  `json:"modified"`
-	Completed   *string `json:"completed,omitempty"`
-	Tags        string  `json:"tags,omitempty"`
-	ParentID    string  `json:"parent,omitempty"`
+	UID         string            `json:"uid"`
+	Summary     string            `json:"summary"`
+	Description string            `json:"description,omitempty"`
+	Status      string            `json:"status"`
+	Priority    int               `json:"priority"`
+	DueDate     *string           `json:"due_date,omitempty"`
+	StartDate   *string           `json:"start_date,omitempty"`
+	Created     string            `json:"created"`
+	Modified    string            `json:"modified"`
+	Completed   *string           `json:"completed,omitempty"`
+	Tags        string            `json:"tags,omitempty"`
+	ParentID    string            `json:"parent,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
 }
 
 // taskToPluginData converts a backend.Task to pluginTaskData
@@ -285,6 +421,7 @@ func taskToPluginData(t *backend.Task) pluginTaskData {
 		Modified:    t.Modified.Format(time.RFC3339),
 		Tags:        t.Categories,
 		ParentID:    t.ParentID,
+		Metadata:    t.Metadata,
 	}
 
 	if t.DueDate != nil {
@@ -309,17 +446,9 @@ func taskToPluginData(t *backend.Task) pluginTaskData {
 // StatusInProgress (IN-PROCESS) → "IN-PROGRESS"
 // StatusCancelled (CANCELLED) → "CANCELLED"
 // StatusNeedsAction (NEEDS-ACTION) or default → "TODO"
+// Custom statuses keep their name.
 func StatusToString(status backend.TaskStatus) string {
-	switch status {
-	case backend.StatusCompleted:
-		return "DONE"
-	case backend.StatusInProgress:
-		return "IN-PROGRESS"
-	case backend.StatusCancelled:
-		return "CANCELLED"
-	default:
-		return "TODO"
-	}
+	return status.Name()
 }
 
 // getPluginDir returns the plugin directory path.
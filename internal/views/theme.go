@@ -0,0 +1,135 @@
+package views
+
+import (
+	"fmt"
+	"time"
+
+	"todoat/backend"
+	"todoat/internal/config"
+	"todoat/internal/utils"
+)
+
+// Theme holds the ANSI styles used to color terminal output, as SGR parameters.
+// A nil *Theme renders plain text.
+type Theme struct {
+	PriorityHigh   string
+	PriorityMedium string
+	PriorityLow    string
+	Overdue        string
+	DueToday       string
+	Todo           string
+	InProgress     string
+	Completed      string
+	Cancelled      string
+	Header         string
+}
+
+// defaultThemeStyles are the styles used for theme keys the config leaves empty
+var defaultThemeStyles = config.ThemeConfig{
+	PriorityHigh:   "bold red",
+	PriorityMedium: "yellow",
+	PriorityLow:    "blue",
+	Overdue:        "bold red",
+	DueToday:       "yellow",
+	InProgress:     "cyan",
+	Completed:      "green",
+	Cancelled:      "dim",
+	Header:         "bold",
+}
+
+// DefaultTheme returns the built-in theme
+func DefaultTheme() *Theme {
+	theme, _ := NewTheme(config.ThemeConfig{})
+	return theme
+}
+
+// NewTheme builds a theme from the theme config section, using the built-in
+// style for every key left empty
+func NewTheme(cfg config.ThemeConfig) (*Theme, error) {
+	styles := cfg.Styles()
+	defaults := defaultThemeStyles.Styles()
+	params := make(map[string]string, len(styles))
+	for key, style := range styles {
+		if style == "" {
+			style = defaults[key]
+		}
+		p, err := utils.ParseStyle(style)
+		if err != nil {
+			return nil, fmt.Errorf("invalid theme.%s: %w", key, err)
+		}
+		params[key] = p
+	}
+	return &Theme{
+		PriorityHigh:   params["priority_high"],
+		PriorityMedium: params["priority_medium"],
+		PriorityLow:    params["priority_low"],
+		Overdue:        params["overdue"],
+		DueToday:       params["due_today"],
+		Todo:           params["todo"],
+		InProgress:     params["in_progress"],
+		Completed:      params["completed"],
+		Cancelled:      params["cancelled"],
+		Header:         params["header"],
+	}, nil
+}
+
+// Paint applies a style to s; it returns s unchanged on a nil theme
+func (th *Theme) Paint(s, style string) string {
+	if th == nil {
+		return s
+	}
+	return utils.Colorize(s, style)
+}
+
+// HeaderStyle returns the style for table and list headers
+func (th *Theme) HeaderStyle() string {
+	if th == nil {
+		return ""
+	}
+	return th.Header
+}
+
+// fieldStyle returns the style for a field of a task: status by status, priority
+// by its high (1-3), medium (4-6) or low (7-9) band, and the due date when an open
+// task is overdue or due today. Other fields are not colored.
+func (th *Theme) fieldStyle(t *backend.Task, field string, now time.Time) string {
+	if th == nil {
+		return ""
+	}
+	switch field {
+	case "status":
+		switch t.Status.Base() {
+		case backend.StatusCompleted:
+			return th.Completed
+		case backend.StatusInProgress:
+			return th.InProgress
+		case backend.StatusCancelled:
+			return th.Cancelled
+		default:
+			return th.Todo
+		}
+	case "priority":
+		switch {
+		case t.Priority >= 1 && t.Priority <= 3:
+			return th.PriorityHigh
+		case t.Priority >= 4 && t.Priority <= 6:
+			return th.PriorityMedium
+		case t.Priority >= 7:
+			return th.PriorityLow
+		}
+	case "due_date":
+		if t.DueDate == nil || t.Status == backend.StatusCompleted || t.Status == backend.StatusCancelled {
+			return ""
+		}
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		due := t.DueDate.In(now.Location())
+		dueDay := time.Date(due.Year(), due.Month(), due.Day(), 0, 0, 0, 0, now.Location())
+		switch {
+		case dueDay.Before(today):
+			return th.Overdue
+		case dueDay.Equal(today):
+			return th.DueToday
+		}
+	}
+	return ""
+}
@@ -0,0 +1,183 @@
+package views
+
+import (
+	"fmt"
+
+	"todoat/backend"
+)
+
+// TreeField is a display-only field showing the summary behind box-drawing
+// connectors that outline the parent/child structure
+const TreeField = "tree"
+
+// IsDisplayField reports whether name can be shown by a view: a field of
+// AvailableFields, a meta.<key> field or TreeField
+func IsDisplayField(name string) bool {
+	return name == TreeField || IsValidField(name)
+}
+
+// WithTreeField returns a copy of the view that shows TreeField in place of
+// summary, or before all fields when the view has no summary
+func WithTreeField(v *View) *View {
+	tree := *v
+	tree.Fields = make([]Field, 0, len(v.Fields)+1)
+	replaced := false
+	for _, f := range v.Fields {
+		switch f.Name {
+		case "summary":
+			f.Name = TreeField
+			replaced = true
+		case TreeField:
+			replaced = true
+		}
+		tree.Fields = append(tree.Fields, f)
+	}
+	if !replaced {
+		tree.Fields = append([]Field{{Name: TreeField}}, tree.Fields...)
+	}
+	return &tree
+}
+
+// hasField reports whether the view shows the named field
+func (v *View) hasField(name string) bool {
+	for _, f := range v.Fields {
+		if f.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// TreeOrder returns the tasks with each parent followed by its subtasks,
+// keeping the relative order of siblings. Tasks whose parent is not among
+// tasks are roots.
+func TreeOrder(tasks []backend.Task) []backend.Task {
+	roots, children := treeChildren(tasks)
+	ordered := make([]backend.Task, 0, len(tasks))
+	var walk func(i int)
+	walk = func(i int) {
+		ordered = append(ordered, tasks[i])
+		for _, c := range children[tasks[i].ID] {
+			walk(c)
+		}
+	}
+	for _, i := range roots {
+		walk(i)
+	}
+	return ordered
+}
+
+// LimitDepth drops the tasks nested deeper than depth levels (1 keeps only
+// roots) and returns how many descendants were hidden under each remaining
+// task. A depth of 0 or less keeps every task.
+func LimitDepth(tasks []backend.Task, depth int) ([]backend.Task, map[string]int) {
+	if depth <= 0 {
+		return tasks, nil
+	}
+	roots, children := treeChildren(tasks)
+	kept := make([]backend.Task, 0, len(tasks))
+	hidden := make(map[string]int)
+	var count func(i int) int
+	count = func(i int) int {
+		n := 0
+		for _, c := range children[tasks[i].ID] {
+			n += 1 + count(c)
+		}
+		return n
+	}
+	var walk func(i, level int)
+	walk = func(i, level int) {
+		kept = append(kept, tasks[i])
+		if level == depth {
+			if n := count(i); n > 0 {
+				hidden[tasks[i].ID] = n
+			}
+			return
+		}
+		for _, c := range children[tasks[i].ID] {
+			walk(c, level+1)
+		}
+	}
+	for _, i := range roots {
+		walk(i, 1)
+	}
+	return kept, hidden
+}
+
+// TreePrefixes returns the connectors drawn before each task's summary: none
+// for roots, "├─ " or "└─ " for subtasks, behind "│  " or "   " for each
+// ancestor below the root that has (or has no) later siblings
+func TreePrefixes(tasks []backend.Task) map[string]string {
+	roots, children := treeChildren(tasks)
+	prefixes := make(map[string]string, len(tasks))
+	var walk func(i int, indent string)
+	walk = func(i int, indent string) {
+		kids := children[tasks[i].ID]
+		for n, c := range kids {
+			connector, next := "├─ ", "│  "
+			if n == len(kids)-1 {
+				connector, next = "└─ ", "   "
+			}
+			prefixes[tasks[c].ID] = indent + connector
+			walk(c, indent+next)
+		}
+	}
+	for _, i := range roots {
+		prefixes[tasks[i].ID] = ""
+		walk(i, "")
+	}
+	return prefixes
+}
+
+// HiddenMarker returns the note shown after the summary of a task whose n
+// subtasks are collapsed, or "" when none are
+func HiddenMarker(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (+%d hidden)", n)
+}
+
+// treeChildren returns the indexes of the root tasks and of each task's
+// subtasks, in the order of tasks. Parent cycles are broken at the task that
+// closes them, which becomes a root.
+func treeChildren(tasks []backend.Task) ([]int, map[string][]int) {
+	index := make(map[string]int, len(tasks))
+	for i, t := range tasks {
+		index[t.ID] = i
+	}
+	var roots []int
+	children := make(map[string][]int)
+	for i, t := range tasks {
+		if t.ParentID == "" || inCycle(tasks, index, i) {
+			roots = append(roots, i)
+			continue
+		}
+		if _, ok := index[t.ParentID]; !ok {
+			roots = append(roots, i)
+			continue
+		}
+		children[t.ParentID] = append(children[t.ParentID], i)
+	}
+	return roots, children
+}
+
+// inCycle reports whether following parents from task i leads back to it
+func inCycle(tasks []backend.Task, index map[string]int, i int) bool {
+	seen := map[string]bool{}
+	for id := tasks[i].ParentID; id != ""; {
+		if id == tasks[i].ID {
+			return true
+		}
+		if seen[id] {
+			return false
+		}
+		seen[id] = true
+		j, ok := index[id]
+		if !ok {
+			return false
+		}
+		id = tasks[j].ParentID
+	}
+	return false
+}
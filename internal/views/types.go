@@ -1,5 +1,7 @@
 package views
 
+import "strings"
+
 // DefaultDateFormat is the standard date format used throughout the views package
 const DefaultDateFormat = "2006-01-02"
 
@@ -11,6 +13,23 @@ type View struct {
 	Filters     []Filter   `yaml:"filters,omitempty"`
 	Sort        []SortRule `yaml:"sort,omitempty"`
 	Hierarchy   *Hierarchy `yaml:"hierarchy,omitempty"`
+	PageSize    int        `yaml:"page_size,omitempty"`   // default page size for get when no pagination flags are passed
+	StableSort  bool       `yaml:"stable_sort,omitempty"` // break sort ties by creation time and UID
+}
+
+// SortRules returns the rules used to order the view's tasks.
+// With StableSort, ties are broken by creation time and then UID, so the order
+// (and therefore every page) is the same on each run regardless of backend order.
+func (v *View) SortRules() []SortRule {
+	if !v.StableSort {
+		return v.Sort
+	}
+	rules := make([]SortRule, 0, len(v.Sort)+2)
+	rules = append(rules, v.Sort...)
+	return append(rules,
+		SortRule{Field: "created", Direction: "asc"},
+		SortRule{Field: "uid", Direction: "asc"},
+	)
 }
 
 // Field represents a field configuration in a view
@@ -65,6 +84,25 @@ var AvailableFields = []string{
 	"uid",
 	"parent",
 	"recurrence",
+	"progress",
+	"position",
+	"pinned",
+}
+
+// MetaFieldPrefix prefixes view fields that read a task's custom metadata, e.g. meta.estimate
+const MetaFieldPrefix = "meta."
+
+// IsValidField reports whether name is one of AvailableFields or a meta.<key> field
+func IsValidField(name string) bool {
+	if key, ok := strings.CutPrefix(name, MetaFieldPrefix); ok {
+		return key != ""
+	}
+	for _, f := range AvailableFields {
+		if f == name {
+			return true
+		}
+	}
+	return false
 }
 
 // DefaultView returns the built-in default view
@@ -79,6 +117,7 @@ func DefaultView() *View {
 			{Name: "due_date", Width: 12},
 			{Name: "tags", Width: 20},
 			{Name: "recurrence", Width: 5},
+			{Name: "progress"},
 		},
 		Filters: []Filter{
 			{Field: "status", Operator: "ne", Value: "DONE"},
@@ -105,6 +144,9 @@ func AllView() *View {
 			{Name: "uid"},
 			{Name: "parent"},
 			{Name: "recurrence"},
+			{Name: "progress"},
+			{Name: "position"},
+			{Name: "pinned"},
 		},
 	}
 }
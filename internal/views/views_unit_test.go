@@ -2,10 +2,12 @@ package views
 
 import (
 	"bytes"
+	"strings"
 	"testing"
 	"time"
 
 	"todoat/backend"
+	"todoat/internal/config"
 )
 
 // Tests for filter.go helper functions
@@ -746,3 +748,206 @@ func TestRunPluginNilConfig(t *testing.T) {
 		t.Errorf("runPlugin with nonexistent command = (%q, %v), want (\"\", false)", result, ok)
 	}
 }
+
+func TestViewSortRulesStable(t *testing.T) {
+	v := &View{Sort: []SortRule{{Field: "priority", Direction: "asc"}}}
+	if got := v.SortRules(); len(got) != 1 {
+		t.Errorf("SortRules() without stable_sort = %v, want the view's rules", got)
+	}
+
+	v.StableSort = true
+	got := v.SortRules()
+	if len(got) != 3 || got[1].Field != "created" || got[2].Field != "uid" {
+		t.Fatalf("SortRules() with stable_sort = %v, want priority, created, uid", got)
+	}
+
+	// Equal priorities keep a fixed order regardless of input order
+	now := time.Now()
+	a := backend.Task{ID: "a", Summary: "A", Priority: 1, Created: now}
+	b := backend.Task{ID: "b", Summary: "B", Priority: 1, Created: now}
+	c := backend.Task{ID: "c", Summary: "C", Priority: 1, Created: now.Add(-time.Hour)}
+	for _, in := range [][]backend.Task{{a, b, c}, {b, c, a}, {c, a, b}} {
+		sorted := SortTasks(in, got)
+		if sorted[0].ID != "c" || sorted[1].ID != "a" || sorted[2].ID != "b" {
+			t.Errorf("SortTasks(%s%s%s) = %s%s%s, want cab", in[0].ID, in[1].ID, in[2].ID, sorted[0].ID, sorted[1].ID, sorted[2].ID)
+		}
+	}
+}
+
+func TestSubtaskProgress(t *testing.T) {
+	tasks := []backend.Task{
+		{ID: "p", Summary: "Release"},
+		{ID: "a", Summary: "Docs", ParentID: "p", Status: backend.StatusCompleted},
+		{ID: "b", Summary: "Tests", ParentID: "p"},
+		{ID: "c", Summary: "Unit", ParentID: "b", Status: backend.StatusCompleted},
+		{ID: "d", Summary: "Dropped", ParentID: "p", Status: backend.StatusCancelled},
+		{ID: "e", Summary: "Leaf"},
+	}
+
+	progress := SubtaskProgress(tasks)
+	if got := progress["p"]; got != (Progress{Done: 2, Total: 3}) {
+		t.Errorf("expected Release progress 2/3 (descendants, cancelled excluded), got %s", got)
+	}
+	if got := progress["b"]; got != (Progress{Done: 1, Total: 1}) {
+		t.Errorf("expected Tests progress 1/1, got %s", got)
+	}
+	if _, ok := progress["e"]; ok {
+		t.Error("expected no progress for a task without subtasks")
+	}
+
+	var buf bytes.Buffer
+	view := &View{Fields: []Field{{Name: "summary"}, {Name: "progress"}}}
+	NewRenderer(view, &buf).WithProgress(progress).Render(tasks[:1])
+	if !bytes.Contains(buf.Bytes(), []byte("Release [2/3]")) {
+		t.Errorf("expected progress field in output, got %q", buf.String())
+	}
+}
+
+func TestMetaFields(t *testing.T) {
+	if !IsValidField("meta.estimate") || IsValidField("meta.") || IsValidField("bogus") {
+		t.Error("expected meta.<key> to be the only extra valid field form")
+	}
+	task := backend.Task{Summary: "A", Metadata: map[string]string{"client": "acme"}}
+	filters := []Filter{{Field: "meta.client", Operator: "eq", Value: "acme"}}
+	if len(FilterTasks([]backend.Task{task, {Summary: "B"}}, filters)) != 1 {
+		t.Error("expected meta filter to match only the task with the field")
+	}
+}
+
+func TestOutputRenderers(t *testing.T) {
+	due := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	tasks := []backend.Task{
+		{ID: "p", Summary: "Parent", Priority: 2, DueDate: &due, Categories: "ops"},
+		{ID: "c", Summary: "Child\tline", ParentID: "p", Status: backend.StatusCompleted},
+	}
+	view := &View{Fields: []Field{{Name: "status"}, {Name: "summary"}, {Name: "priority"}, {Name: "due_date"}, {Name: "tags"}}}
+
+	render := func(format, tmpl string) string {
+		r, err := NewOutputRenderer(format, OutputOptions{View: view, Template: tmpl})
+		if err != nil {
+			t.Fatalf("NewOutputRenderer(%s): %v", format, err)
+		}
+		var buf bytes.Buffer
+		if err := r.Render(&buf, tasks); err != nil {
+			t.Fatalf("Render(%s): %v", format, err)
+		}
+		return buf.String()
+	}
+
+	if got, want := render("tsv", ""), "status\tsummary\tpriority\tdue_date\ttags\nTODO\tParent\t2\t2026-05-01\tops\nDONE\tChild line\t\t\t\n"; got != want {
+		t.Errorf("tsv = %q, want %q", got, want)
+	}
+	if got, want := render("markdown", ""), "- [ ] Parent (P2, due 2026-05-01, #ops)\n  - [x] Child\tline\n"; got != want {
+		t.Errorf("markdown = %q, want %q", got, want)
+	}
+	if got, want := render("template", "{{.Status}} {{.Summary}}"), "TODO Parent\nDONE Child\tline\n"; got != want {
+		t.Errorf("template = %q, want %q", got, want)
+	}
+
+	if _, err := NewOutputRenderer("yaml", OutputOptions{}); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+	RegisterOutputRenderer("count", func(OutputOptions) (OutputRenderer, error) { return nil, nil })
+	defer delete(outputRenderers, "count")
+	if formats := OutputFormats(); len(formats) != 6 || formats[0] != "count" {
+		t.Errorf("expected registered format to be listed, got %v", formats)
+	}
+}
+
+// TestTreeHelpers verifies tree ordering, depth limits and the connectors of the tree field
+func TestTreeHelpers(t *testing.T) {
+	// Sorted flat, subtasks before their parents
+	tasks := []backend.Task{
+		{ID: "b1", Summary: "B1", ParentID: "b"},
+		{ID: "a", Summary: "A"},
+		{ID: "a2", Summary: "A2", ParentID: "a"},
+		{ID: "a1x", Summary: "A1x", ParentID: "a1"},
+		{ID: "a1", Summary: "A1", ParentID: "a"},
+		{ID: "b", Summary: "B"},
+		{ID: "orphan", Summary: "Orphan", ParentID: "missing"},
+	}
+
+	ordered := TreeOrder(tasks)
+	var ids []string
+	for _, task := range ordered {
+		ids = append(ids, task.ID)
+	}
+	if got, want := strings.Join(ids, ","), "a,a2,a1,a1x,b,b1,orphan"; got != want {
+		t.Errorf("TreeOrder = %s, want %s", got, want)
+	}
+
+	prefixes := TreePrefixes(ordered)
+	want := map[string]string{"a": "", "a2": "├─ ", "a1": "└─ ", "a1x": "   └─ ", "b": "", "b1": "└─ ", "orphan": ""}
+	for id, p := range want {
+		if prefixes[id] != p {
+			t.Errorf("prefix of %s = %q, want %q", id, prefixes[id], p)
+		}
+	}
+
+	kept, hidden := LimitDepth(ordered, 1)
+	if len(kept) != 3 || hidden["a"] != 3 || hidden["b"] != 1 || hidden["orphan"] != 0 {
+		t.Errorf("LimitDepth(1) = %d tasks, hidden %v", len(kept), hidden)
+	}
+	kept, hidden = LimitDepth(ordered, 2)
+	if len(kept) != 6 || hidden["a1"] != 1 || len(hidden) != 1 {
+		t.Errorf("LimitDepth(2) = %d tasks, hidden %v", len(kept), hidden)
+	}
+	if kept, hidden = LimitDepth(ordered, 0); len(kept) != len(ordered) || hidden != nil {
+		t.Error("LimitDepth(0) should keep every task")
+	}
+
+	view := WithTreeField(&View{Fields: []Field{{Name: "status"}, {Name: "summary"}}})
+	if view.Fields[1].Name != TreeField || !IsDisplayField(TreeField) || IsValidField(TreeField) {
+		t.Errorf("WithTreeField fields = %+v", view.Fields)
+	}
+	r, err := NewOutputRenderer("table", OutputOptions{View: view, Hidden: map[string]int{"b": 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := r.Render(&buf, []backend.Task{ordered[0], ordered[1], ordered[2], ordered[3], ordered[4]}); err != nil {
+		t.Fatal(err)
+	}
+	wantTable := "STATUS  TREE\nTODO    A\nTODO    ├─ A2\nTODO    └─ A1\nTODO       └─ A1x\nTODO    B (+1 hidden)\n"
+	if got := buf.String(); got != wantTable {
+		t.Errorf("table = %q, want %q", got, wantTable)
+	}
+}
+
+// TestThemeFieldStyles verifies which theme style each field of a task gets
+func TestThemeFieldStyles(t *testing.T) {
+	theme := DefaultTheme()
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local)
+	yesterday := now.AddDate(0, 0, -1)
+	today := time.Date(2026, 3, 10, 0, 0, 0, 0, time.Local)
+
+	task := backend.Task{Priority: 2, Status: backend.StatusInProgress, DueDate: &yesterday}
+	if got := theme.fieldStyle(&task, "priority", now); got != theme.PriorityHigh {
+		t.Errorf("priority 2 style = %q, want high", got)
+	}
+	if got := theme.fieldStyle(&task, "status", now); got != theme.InProgress {
+		t.Errorf("status style = %q, want in progress", got)
+	}
+	if got := theme.fieldStyle(&task, "due_date", now); got != theme.Overdue {
+		t.Errorf("due date style = %q, want overdue", got)
+	}
+	task.DueDate = &today
+	if got := theme.fieldStyle(&task, "due_date", now); got != theme.DueToday {
+		t.Errorf("due date style = %q, want due today", got)
+	}
+	task.Status = backend.StatusCompleted
+	if got := theme.fieldStyle(&task, "due_date", now); got != "" {
+		t.Errorf("completed task due date should not be colored, got %q", got)
+	}
+	if got := theme.fieldStyle(&task, "summary", now); got != "" {
+		t.Errorf("summary should not be colored, got %q", got)
+	}
+
+	var nilTheme *Theme
+	if got := nilTheme.Paint("[P1]", "31"); got != "[P1]" {
+		t.Errorf("nil theme should render plain text, got %q", got)
+	}
+	if _, err := NewTheme(config.ThemeConfig{Overdue: "sparkly"}); err == nil {
+		t.Error("expected an invalid theme style to fail")
+	}
+}